@@ -652,7 +652,7 @@ func (b *SimulatedBackend) EstimateGas(ctx context.Context, call ethereum.CallMs
 		b.pendingState.RevertToSnapshot(snapshot)
 
 		if err != nil {
-			if errors.Is(err, core.ErrIntrinsicGas) {
+			if errors.Is(err, core.ErrIntrinsicGas) || errors.Is(err, core.ErrFloorDataGas) {
 				return true, nil, nil // Special case, raise gas limit
 			}
 			return true, nil, err // Bail out
@@ -846,6 +846,7 @@ func (m callMsg) RollupCostData() types2.RollupCostData { return types2.RollupCo
 func (m callMsg) IsDepositTx() bool                     { return false }
 func (m callMsg) IsSystemTx() bool                      { return false }
 
-func (m callMsg) BlobGas() uint64                { return misc.GetBlobGasUsed(len(m.CallMsg.BlobHashes)) }
-func (m callMsg) MaxFeePerBlobGas() *uint256.Int { return m.CallMsg.MaxFeePerBlobGas }
-func (m callMsg) BlobHashes() []libcommon.Hash   { return m.CallMsg.BlobHashes }
+func (m callMsg) BlobGas() uint64                       { return misc.GetBlobGasUsed(len(m.CallMsg.BlobHashes)) }
+func (m callMsg) MaxFeePerBlobGas() *uint256.Int        { return m.CallMsg.MaxFeePerBlobGas }
+func (m callMsg) BlobHashes() []libcommon.Hash          { return m.CallMsg.BlobHashes }
+func (m callMsg) Authorizations() []types.Authorization { return nil }
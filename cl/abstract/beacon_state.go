@@ -19,6 +19,7 @@ type BeaconStateUpgradable interface {
 	UpgradeToBellatrix() error
 	UpgradeToCapella() error
 	UpgradeToDeneb() error
+	UpgradeToElectra() error
 }
 
 type BeaconStateExtension interface {
@@ -36,11 +37,17 @@ type BeaconStateExtension interface {
 	GetBeaconCommitee(slot, committeeIndex uint64) ([]uint64, error)
 	ComputeNextSyncCommittee() (*solid.SyncCommittee, error)
 	GetAttestingIndicies(attestation solid.AttestationData, aggregationBits []byte, checkBitsLength bool) ([]uint64, error)
+	GetAttestingIndiciesForAttestation(attestation *solid.Attestation, checkBitsLength bool) ([]uint64, error)
 	GetValidatorChurnLimit() uint64
 	ValidatorIndexByPubkey(key [48]byte) (uint64, bool)
 	PreviousStateRoot() common.Hash
 	SetPreviousStateRoot(root common.Hash)
 	GetValidatorActivationChurnLimit() uint64
+	GetBalanceChurnLimit() uint64
+	GetActivationExitChurnLimit() uint64
+	GetConsolidationChurnLimit() uint64
+	ComputeExitEpochAndUpdateChurn(exitBalance uint64) uint64
+	ComputeConsolidationEpochAndUpdateChurn(consolidationBalance uint64) uint64
 }
 
 type BeaconStateBasic interface {
@@ -105,6 +112,15 @@ type BeaconStateMutator interface {
 	SetCurrentEpochParticipationFlags(flags []cltypes.ParticipationFlags)
 	SetPreviousEpochParticipationFlags(flags []cltypes.ParticipationFlags)
 	SetPreviousEpochAttestations(attestations *solid.ListSSZ[*solid.PendingAttestation])
+	SetDepositRequestsStartIndex(index uint64)
+	SetDepositBalanceToConsume(balance uint64)
+	SetExitBalanceToConsume(balance uint64)
+	SetEarliestExitEpoch(epoch uint64)
+	SetConsolidationBalanceToConsume(balance uint64)
+	SetEarliestConsolidationEpoch(epoch uint64)
+	SetPendingDeposits(deposits *solid.ListSSZ[*cltypes.PendingDeposit])
+	SetPendingPartialWithdrawals(withdrawals *solid.ListSSZ[*cltypes.PendingPartialWithdrawal])
+	SetPendingConsolidations(consolidations *solid.ListSSZ[*cltypes.PendingConsolidation])
 
 	AddEth1DataVote(vote *cltypes.Eth1Data)
 	AddValidator(validator solid.Validator, balance uint64)
@@ -118,6 +134,9 @@ type BeaconStateMutator interface {
 	AddPreviousEpochAttestation(attestation *solid.PendingAttestation)
 
 	AppendValidator(in solid.Validator)
+	AppendPendingDeposit(deposit *cltypes.PendingDeposit)
+	AppendPendingPartialWithdrawal(withdrawal *cltypes.PendingPartialWithdrawal)
+	AppendPendingConsolidation(consolidation *cltypes.PendingConsolidation)
 
 	ResetEth1DataVotes()
 	ResetEpochParticipation()
@@ -190,6 +209,16 @@ type BeaconStateMinimal interface {
 	CurrentEpochAttestationsLength() int
 	PreviousEpochAttestations() *solid.ListSSZ[*solid.PendingAttestation]
 	PreviousEpochAttestationsLength() int
+
+	DepositRequestsStartIndex() uint64
+	DepositBalanceToConsume() uint64
+	ExitBalanceToConsume() uint64
+	EarliestExitEpoch() uint64
+	ConsolidationBalanceToConsume() uint64
+	EarliestConsolidationEpoch() uint64
+	PendingDeposits() *solid.ListSSZ[*cltypes.PendingDeposit]
+	PendingPartialWithdrawals() *solid.ListSSZ[*cltypes.PendingPartialWithdrawal]
+	PendingConsolidations() *solid.ListSSZ[*cltypes.PendingConsolidation]
 }
 
 // TODO figure this out
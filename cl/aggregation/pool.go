@@ -3,6 +3,7 @@ package aggregation
 import (
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/utils"
 )
 
 //go:generate mockgen -typed=true -destination=./mock_services/aggregation_pool_mock.go -package=mock_services . AggregationPool
@@ -11,3 +12,10 @@ type AggregationPool interface {
 	AddAttestation(att *solid.Attestation) error
 	GetAggregatationByRoot(root common.Hash) *solid.Attestation
 }
+
+// ElectraAggregationRoot returns the pool key of the Electra aggregate for the given attestation data root and committee.
+func ElectraAggregationRoot(attDataRoot common.Hash, committeeIndex uint64) common.Hash {
+	committeeBits := make([]byte, solid.CommitteeBitsSize)
+	committeeBits[committeeIndex/8] |= 1 << (committeeIndex % 8)
+	return utils.Sha256(attDataRoot[:], committeeBits)
+}
@@ -52,6 +52,10 @@ func (p *aggregationPoolImpl) AddAttestation(inAtt *solid.Attestation) error {
 	if err != nil {
 		return err
 	}
+	if inAtt.IsElectra() {
+		// Electra attestations of different committees share their data, so the committee bits are part of the key.
+		hashRoot = utils.Sha256(hashRoot[:], inAtt.CommitteeBits())
+	}
 
 	p.aggregatesLock.Lock()
 	defer p.aggregatesLock.Unlock()
@@ -86,11 +90,10 @@ func (p *aggregationPoolImpl) AddAttestation(inAtt *solid.Attestation) error {
 	}
 
 	// update attestation
-	p.aggregates[hashRoot] = solid.NewAttestionFromParameters(
-		mergedBits,
-		inAtt.AttestantionData(),
-		mergedSig,
-	)
+	mergedAtt := inAtt.Copy()
+	mergedAtt.SetAggregationBits(mergedBits)
+	mergedAtt.SetSignature(mergedSig)
+	p.aggregates[hashRoot] = mergedAtt
 	return nil
 }
 
@@ -286,6 +286,10 @@ func (a *Antiquary) loopBlobs(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-blobAntiquationTicker.C:
+			// Archives index the versioned hashes of frozen sidecars they did not store themselves.
+			if err := a.blobStorage.IndexFrozenBlobSidecars(ctx, a.sn.FrozenBlobs(), a.sn.ReadBlobSidecars); err != nil {
+				log.Error("[Antiquary]: Failed to index frozen blobs", "err", err)
+			}
 			if !a.blobBackfilled.Load() {
 				continue
 			}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
@@ -13,6 +16,8 @@ import (
 
 var blobSidecarSSZLenght = (*cltypes.BlobSidecar)(nil).EncodingSizeSSZ()
 
+var versionedHashRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
 func (a *ApiHandler) GetEthV1BeaconBlobSidecars(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
 	ctx := r.Context()
 	tx, err := a.indiciesDB.BeginRo(ctx)
@@ -36,49 +41,67 @@ func (a *ApiHandler) GetEthV1BeaconBlobSidecars(w http.ResponseWriter, r *http.R
 	if slot == nil {
 		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("block not found"))
 	}
-	if a.caplinSnapshots != nil && *slot <= a.caplinSnapshots.FrozenBlobs() {
-		out, err := a.caplinSnapshots.ReadBlobSidecars(*slot)
+	strIdxs, err := beaconhttp.StringListFromQueryParams(r, "indices")
+	if err != nil {
+		return nil, err
+	}
+	included := make(map[uint64]struct{})
+	for _, idx := range strIdxs {
+		i, err := strconv.ParseUint(idx, 10, 64)
 		if err != nil {
-			return nil, err
+			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("invalid blob index %s", idx))
 		}
-		resp := solid.NewStaticListSSZ[*cltypes.BlobSidecar](696969, blobSidecarSSZLenght)
-		for _, v := range out {
+		included[i] = struct{}{}
+	}
+
+	out, err := a.readBlobSidecars(ctx, *slot, blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	resp := solid.NewStaticListSSZ[*cltypes.BlobSidecar](696969, blobSidecarSSZLenght)
+	for _, v := range out {
+		if _, ok := included[v.Index]; len(included) == 0 || ok {
 			resp.Append(v)
 		}
-		return beaconhttp.NewBeaconResponse(resp), nil
-
 	}
-	out, found, err := a.blobStoage.ReadBlobSidecars(ctx, *slot, blockRoot)
+	return beaconhttp.NewBeaconResponse(resp), nil
+}
+
+// GetEthV1BeaconBlobSidecarByVersionedHash is a non-standard endpoint serving the sidecar which carries the blob
+// with the given versioned hash. It is only available with the blob archive, which maintains the index.
+func (a *ApiHandler) GetEthV1BeaconBlobSidecarByVersionedHash(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	ctx := r.Context()
+	versionedHashStr, err := beaconhttp.StringFromRequest(r, "versioned_hash")
 	if err != nil {
 		return nil, err
 	}
-	strIdxs, err := beaconhttp.StringListFromQueryParams(r, "indices")
+	if !versionedHashRegex.MatchString(versionedHashStr) {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("invalid path variable: {versioned_hash}"))
+	}
+	location, found, err := a.blobStoage.ReadBlobSidecarLocation(ctx, libcommon.HexToHash(versionedHashStr))
 	if err != nil {
 		return nil, err
 	}
-	resp := solid.NewStaticListSSZ[*cltypes.BlobSidecar](696969, blobSidecarSSZLenght)
 	if !found {
-		return beaconhttp.NewBeaconResponse(resp), nil
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("blob sidecar not found"))
 	}
-	if len(strIdxs) == 0 {
-		for _, v := range out {
-			resp.Append(v)
-		}
-	} else {
-		included := make(map[uint64]struct{})
-		for _, idx := range strIdxs {
-			i, err := strconv.ParseUint(idx, 10, 64)
-			if err != nil {
-				return nil, err
-			}
-			included[i] = struct{}{}
-		}
-		for _, v := range out {
-			if _, ok := included[v.Index]; ok {
-				resp.Append(v)
-			}
+	out, err := a.readBlobSidecars(ctx, location.Slot, location.BlockRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range out {
+		if v.Index == location.Index {
+			return beaconhttp.NewBeaconResponse(v), nil
 		}
 	}
+	return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("blob sidecar not found"))
+}
 
-	return beaconhttp.NewBeaconResponse(resp), nil
+// readBlobSidecars reads the sidecars of a block, from the snapshots if they were already frozen.
+func (a *ApiHandler) readBlobSidecars(ctx context.Context, slot uint64, blockRoot libcommon.Hash) ([]*cltypes.BlobSidecar, error) {
+	if a.caplinSnapshots != nil && slot <= a.caplinSnapshots.FrozenBlobs() {
+		return a.caplinSnapshots.ReadBlobSidecars(slot)
+	}
+	out, _, err := a.blobStoage.ReadBlobSidecars(ctx, slot, blockRoot)
+	return out, err
 }
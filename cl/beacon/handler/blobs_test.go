@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/crypto/kzg"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBlobSidecarsIndices(t *testing.T) {
+	_, blocks, _, _, _, handler, _, _, _, _ := setupTestingHandler(t, clparams.BellatrixVersion, log.Root())
+
+	blockRoot, err := blocks[0].Block.HashSSZ()
+	require.NoError(t, err)
+
+	cases := []struct {
+		query   string
+		code    int
+		indices []string
+	}{
+		{
+			query:   "",
+			code:    http.StatusOK,
+			indices: []string{"0", "1"},
+		},
+		{
+			query:   "?indices=1",
+			code:    http.StatusOK,
+			indices: []string{"1"},
+		},
+		{
+			query:   "?indices=0,1",
+			code:    http.StatusOK,
+			indices: []string{"0", "1"},
+		},
+		{
+			query:   "?indices=0&indices=1",
+			code:    http.StatusOK,
+			indices: []string{"0", "1"},
+		},
+		{
+			query:   "?indices=5",
+			code:    http.StatusOK,
+			indices: []string{},
+		},
+		{
+			query: "?indices=x",
+			code:  http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			server := httptest.NewServer(handler.mux)
+			defer server.Close()
+			resp, err := http.Get(server.URL + "/eth/v1/beacon/blob_sidecars/" + libcommon.Hash(blockRoot).Hex() + c.query)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, c.code, resp.StatusCode)
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+			out := struct {
+				Data []struct {
+					Index string `json:"index"`
+				} `json:"data"`
+			}{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+			indices := []string{}
+			for _, sidecar := range out.Data {
+				indices = append(indices, sidecar.Index)
+			}
+			require.Equal(t, c.indices, indices)
+		})
+	}
+}
+
+func TestGetBlobSidecarByVersionedHash(t *testing.T) {
+	_, blocks, _, _, _, handler, _, _, _, _ := setupTestingHandler(t, clparams.BellatrixVersion, log.Root())
+
+	blockRoot, err := blocks[0].Block.HashSSZ()
+	require.NoError(t, err)
+	// the versioned hash index is only kept by archives
+	handler.blobStoage = blob_storage.NewArchiveBlobStore(memdb.NewTestDB(t), afero.NewMemMapFs(), handler.beaconChainCfg, handler.ethClock)
+	sidecars := []*cltypes.BlobSidecar{
+		{
+			Index:                    0,
+			Blob:                     cltypes.Blob{byte(1)},
+			SignedBlockHeader:        blocks[0].SignedBeaconBlockHeader(),
+			KzgCommitment:            [48]byte{69},
+			CommitmentInclusionProof: solid.NewHashVector(17),
+		},
+		{
+			Index:                    1,
+			Blob:                     cltypes.Blob{byte(2)},
+			SignedBlockHeader:        blocks[0].SignedBeaconBlockHeader(),
+			KzgCommitment:            [48]byte{1},
+			CommitmentInclusionProof: solid.NewHashVector(17),
+		},
+	}
+	require.NoError(t, handler.blobStoage.WriteBlobSidecars(context.Background(), blockRoot, sidecars))
+	versionedHash := libcommon.Hash(kzg.KZGToVersionedHash(gokzg4844.KZGCommitment(sidecars[1].KzgCommitment)))
+
+	cases := []struct {
+		name          string
+		versionedHash string
+		code          int
+	}{
+		{
+			name:          "found",
+			versionedHash: versionedHash.Hex(),
+			code:          http.StatusOK,
+		},
+		{
+			name:          "unknown",
+			versionedHash: libcommon.Hash{1}.Hex(),
+			code:          http.StatusNotFound,
+		},
+		{
+			name:          "invalid",
+			versionedHash: "0x1234",
+			code:          http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := httptest.NewServer(handler.mux)
+			defer server.Close()
+			resp, err := http.Get(server.URL + "/eth/v1/beacon/blob_sidecars/versioned_hash/" + c.versionedHash)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, c.code, resp.StatusCode)
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+			out := struct {
+				Data struct {
+					Index         string `json:"index"`
+					KzgCommitment string `json:"kzg_commitment"`
+				} `json:"data"`
+			}{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+			require.Equal(t, "1", out.Data.Index)
+			require.Equal(t, libcommon.Bytes48(sidecars[1].KzgCommitment).String(), out.Data.KzgCommitment)
+		})
+	}
+}
@@ -246,7 +246,7 @@ func (a *ApiHandler) produceBeaconBody(
 	// Setup body.
 	beaconBody.RandaoReveal = randaoReveal
 	beaconBody.Graffiti = graffiti
-	beaconBody.Version = stateVersion
+	beaconBody.SetVersion(stateVersion)
 
 	// Build execution payload
 	latestExecutionPayload := baseState.LatestExecutionPayloadHeader()
@@ -430,9 +430,8 @@ func (a *ApiHandler) getBlockOperations(s *state.CachingBeaconState, targetSlot
 	*solid.ListSSZ[*cltypes.SignedVoluntaryExit],
 	*solid.ListSSZ[*cltypes.SignedBLSToExecutionChange]) {
 
-	attesterSlashings := solid.NewDynamicListSSZ[*cltypes.AttesterSlashing](
-		int(a.beaconChainCfg.MaxAttesterSlashings),
-	)
+	maxAttesterSlashings := a.beaconChainCfg.MaxAttesterSlashingsByVersion(s.Version())
+	attesterSlashings := solid.NewDynamicListSSZ[*cltypes.AttesterSlashing](int(maxAttesterSlashings))
 	slashedIndicies := []uint64{}
 	// AttesterSlashings
 AttLoop:
@@ -452,7 +451,7 @@ AttLoop:
 		}
 		slashedIndicies = append(slashedIndicies, rawIdxs...)
 		attesterSlashings.Append(slashing)
-		if attesterSlashings.Len() >= int(a.beaconChainCfg.MaxAttesterSlashings) {
+		if attesterSlashings.Len() >= int(maxAttesterSlashings) {
 			break
 		}
 	}
@@ -727,7 +726,8 @@ func (a *ApiHandler) findBestAttestationsForBlockProduction(
 	s abstract.BeaconState,
 ) *solid.ListSSZ[*solid.Attestation] {
 
-	ret := solid.NewDynamicListSSZ[*solid.Attestation](int(a.beaconChainCfg.MaxAttestations))
+	maxAttestations := a.beaconChainCfg.MaxAttestationsByVersion(s.Version())
+	ret := solid.NewDynamicListSSZ[*solid.Attestation](int(maxAttestations))
 	attestationCandidates := []attestationCandidate{}
 
 	for _, attestation := range a.operationsPool.AttestationsPool.Raw() {
@@ -765,6 +765,10 @@ func (a *ApiHandler) findBestAttestationsForBlockProduction(
 			log.Warn("[Block Production] Cannot compute attestation data root", "err", err)
 			continue
 		}
+		if candidate.attestation.IsElectra() {
+			// Aggregation bits are only comparable within the same set of committees.
+			attestationDataRoot = utils.Sha256(attestationDataRoot[:], candidate.attestation.CommitteeBits())
+		}
 		currAggregationBits, exists := aggregationBitsByAttestationData[attestationDataRoot]
 		if exists {
 			if utils.IsNonStrictSupersetBitlist(
@@ -781,7 +785,7 @@ func (a *ApiHandler) findBestAttestationsForBlockProduction(
 		aggregationBitsByAttestationData[attestationDataRoot] = currAggregationBits
 
 		ret.Append(candidate.attestation)
-		if ret.Len() >= int(a.beaconChainCfg.MaxAttestations) {
+		if ret.Len() >= int(maxAttestations) {
 			break
 		}
 	}
@@ -807,7 +811,7 @@ func computeAttestationReward(
 	if err != nil {
 		return 0, err
 	}
-	attestingIndicies, err := s.GetAttestingIndicies(data, attestation.AggregationBits(), true)
+	attestingIndicies, err := s.GetAttestingIndiciesForAttestation(attestation, true)
 	if err != nil {
 		return 0, err
 	}
@@ -247,6 +247,7 @@ func (a *ApiHandler) init() {
 						r.Get("/updates", a.GetEthV1BeaconLightClientUpdates)
 					})
 					r.Get("/blob_sidecars/{block_id}", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconBlobSidecars))
+					r.Get("/blob_sidecars/versioned_hash/{versioned_hash}", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconBlobSidecarByVersionedHash))
 					r.Route("/states", func(r chi.Router) {
 						r.Route("/{state_id}", func(r chi.Router) {
 							r.Get("/randao", beaconhttp.HandleEndpointFunc(a.getRandao))
@@ -301,6 +302,7 @@ func (a *ApiHandler) init() {
 			if a.routerCfg.Validator {
 				r.Route("/validator", func(r chi.Router) {
 					r.Post("/blocks/{slot}", http.NotFound)
+					r.Get("/aggregate_attestation", beaconhttp.HandleEndpointFunc(a.GetEthV2ValidatorAggregateAttestation))
 				})
 			}
 		})
@@ -12,7 +12,9 @@ import (
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cl/aggregation"
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
 	state_accessors "github.com/ledgerwatch/erigon/cl/persistence/state"
@@ -688,3 +690,47 @@ func (a *ApiHandler) GetEthV1ValidatorAggregateAttestation(w http.ResponseWriter
 
 	return newBeaconResponse(att), nil
 }
+
+// GetEthV2ValidatorAggregateAttestation serves the Electra aggregate of a given committee, which is keyed by
+// both the attestation data root and the committee index.
+func (a *ApiHandler) GetEthV2ValidatorAggregateAttestation(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	attDataRoot := r.URL.Query().Get("attestation_data_root")
+	if attDataRoot == "" {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("attestation_data_root is required"))
+	}
+	slot := r.URL.Query().Get("slot")
+	if slot == "" {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("slot is required"))
+	}
+	slotNum, err := strconv.ParseUint(slot, 10, 64)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, errors.WithMessage(err, "invalid slot"))
+	}
+	committeeIndex := r.URL.Query().Get("committee_index")
+	if committeeIndex == "" {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("committee_index is required"))
+	}
+	committeeIndexNum, err := strconv.ParseUint(committeeIndex, 10, 64)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, errors.WithMessage(err, "invalid committee_index"))
+	}
+	if committeeIndexNum >= a.beaconChainCfg.MaxCommitteesPerSlot {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("committee_index out of range"))
+	}
+
+	version := a.beaconChainCfg.GetCurrentStateVersion(slotNum / a.beaconChainCfg.SlotsPerEpoch)
+	attDataRootHash := libcommon.HexToHash(attDataRoot)
+	if version >= clparams.ElectraVersion {
+		attDataRootHash = aggregation.ElectraAggregationRoot(attDataRootHash, committeeIndexNum)
+	}
+	att := a.aggregatePool.GetAggregatationByRoot(attDataRootHash)
+	if att == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("attestation not found. attestation_data_root"))
+	}
+	if slotNum != att.AttestantionData().Slot() {
+		log.Debug("attestation slot does not match", "attestation_data_root", attDataRoot, "slot_inquire", slot)
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("attestation slot mismatch"))
+	}
+
+	return newBeaconResponse(att).WithVersion(version), nil
+}
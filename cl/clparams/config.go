@@ -34,7 +34,9 @@ type CaplinConfig struct {
 	Backfilling         bool
 	BlobBackfilling     bool
 	BlobPruningDisabled bool
-	Archive             bool
+	// BlobArchive keeps every blob sidecar and indexes them by versioned hash.
+	BlobArchive bool
+	Archive     bool
 }
 
 type NetworkType int
@@ -446,6 +448,8 @@ type BeaconChainConfig struct {
 	CapellaForkEpoch     uint64            `yaml:"CAPELLA_FORK_EPOCH" spec:"true" json:"CAPELLA_FORK_EPOCH,string"`     // CapellaForkEpoch is used to represent the assigned fork epoch for Capella.
 	DenebForkVersion     ConfigForkVersion `yaml:"DENEB_FORK_VERSION" spec:"true" json:"DENEB_FORK_VERSION"`            // DenebForkVersion is used to represent the fork version for Deneb.
 	DenebForkEpoch       uint64            `yaml:"DENEB_FORK_EPOCH" spec:"true" json:"DENEB_FORK_EPOCH,string"`         // DenebForkEpoch is used to represent the assigned fork epoch for Deneb.
+	ElectraForkVersion   ConfigForkVersion `yaml:"ELECTRA_FORK_VERSION" spec:"true" json:"ELECTRA_FORK_VERSION"`        // ElectraForkVersion is used to represent the fork version for Electra.
+	ElectraForkEpoch     uint64            `yaml:"ELECTRA_FORK_EPOCH" spec:"true" json:"ELECTRA_FORK_EPOCH,string"`     // ElectraForkEpoch is used to represent the assigned fork epoch for Electra.
 
 	ForkVersionSchedule map[libcommon.Bytes4]uint64 `json:"-"` // Schedule of fork epochs by version.
 	ForkVersionNames    map[libcommon.Bytes4]string `json:"-"` // Human-readable names of fork versions.
@@ -499,6 +503,28 @@ type BeaconChainConfig struct {
 
 	MaxBlobGasPerBlock uint64 `yaml:"MAX_BLOB_GAS_PER_BLOCK" json:"MAX_BLOB_GAS_PER_BLOCK,string"` // MaxBlobGasPerBlock defines the maximum gas limit for blob sidecar per block.
 	MaxBlobsPerBlock   uint64 `yaml:"MAX_BLOBS_PER_BLOCK" json:"MAX_BLOBS_PER_BLOCK,string"`       // MaxBlobsPerBlock defines the maximum number of blobs per block.
+
+	// Electra
+	MinActivationBalance                  uint64     `yaml:"MIN_ACTIVATION_BALANCE" spec:"true" json:"MIN_ACTIVATION_BALANCE,string"`                                         // MinActivationBalance is the minimum balance needed to activate a validator.
+	MaxEffectiveBalanceElectra            uint64     `yaml:"MAX_EFFECTIVE_BALANCE_ELECTRA" spec:"true" json:"MAX_EFFECTIVE_BALANCE_ELECTRA,string"`                           // MaxEffectiveBalanceElectra is the maximal effective balance of a compounding validator.
+	CompoundingWithdrawalPrefix           ConfigByte `yaml:"COMPOUNDING_WITHDRAWAL_PREFIX" spec:"true" json:"COMPOUNDING_WITHDRAWAL_PREFIX"`                                  // CompoundingWithdrawalPrefix is the withdrawal credentials prefix of compounding validators.
+	MinSlashingPenaltyQuotientElectra     uint64     `yaml:"MIN_SLASHING_PENALTY_QUOTIENT_ELECTRA" spec:"true" json:"MIN_SLASHING_PENALTY_QUOTIENT_ELECTRA,string"`           // MinSlashingPenaltyQuotientElectra for slashing penalties post Electra hard fork.
+	WhistleBlowerRewardQuotientElectra    uint64     `yaml:"WHISTLEBLOWER_REWARD_QUOTIENT_ELECTRA" spec:"true" json:"WHISTLEBLOWER_REWARD_QUOTIENT_ELECTRA,string"`           // WhistleBlowerRewardQuotientElectra is used to calculate whistle blower reward post Electra.
+	PendingDepositsLimit                  uint64     `yaml:"PENDING_DEPOSITS_LIMIT" spec:"true" json:"PENDING_DEPOSITS_LIMIT,string"`                                         // PendingDepositsLimit is the maximum length of the pending deposits queue.
+	PendingPartialWithdrawalsLimit        uint64     `yaml:"PENDING_PARTIAL_WITHDRAWALS_LIMIT" spec:"true" json:"PENDING_PARTIAL_WITHDRAWALS_LIMIT,string"`                   // PendingPartialWithdrawalsLimit is the maximum length of the pending partial withdrawals queue.
+	PendingConsolidationsLimit            uint64     `yaml:"PENDING_CONSOLIDATIONS_LIMIT" spec:"true" json:"PENDING_CONSOLIDATIONS_LIMIT,string"`                             // PendingConsolidationsLimit is the maximum length of the pending consolidations queue.
+	MaxAttesterSlashingsElectra           uint64     `yaml:"MAX_ATTESTER_SLASHINGS_ELECTRA" spec:"true" json:"MAX_ATTESTER_SLASHINGS_ELECTRA,string"`                         // MaxAttesterSlashingsElectra defines the maximum number of attester slashings in a block post Electra.
+	MaxAttestationsElectra                uint64     `yaml:"MAX_ATTESTATIONS_ELECTRA" spec:"true" json:"MAX_ATTESTATIONS_ELECTRA,string"`                                     // MaxAttestationsElectra defines the maximum number of attestations in a block post Electra.
+	MaxDepositRequestsPerPayload          uint64     `yaml:"MAX_DEPOSIT_REQUESTS_PER_PAYLOAD" spec:"true" json:"MAX_DEPOSIT_REQUESTS_PER_PAYLOAD,string"`                     // MaxDepositRequestsPerPayload defines the maximum number of deposit requests in a block.
+	MaxWithdrawalRequestsPerPayload       uint64     `yaml:"MAX_WITHDRAWAL_REQUESTS_PER_PAYLOAD" spec:"true" json:"MAX_WITHDRAWAL_REQUESTS_PER_PAYLOAD,string"`               // MaxWithdrawalRequestsPerPayload defines the maximum number of withdrawal requests in a block.
+	MaxConsolidationRequestsPerPayload    uint64     `yaml:"MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD" spec:"true" json:"MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD,string"`         // MaxConsolidationRequestsPerPayload defines the maximum number of consolidation requests in a block.
+	MaxPendingPartialsPerWithdrawalsSweep uint64     `yaml:"MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP" spec:"true" json:"MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP,string"` // MaxPendingPartialsPerWithdrawalsSweep bounds the pending partial withdrawals processed per payload.
+	MaxPendingDepositsPerEpoch            uint64     `yaml:"MAX_PENDING_DEPOSITS_PER_EPOCH" spec:"true" json:"MAX_PENDING_DEPOSITS_PER_EPOCH,string"`                         // MaxPendingDepositsPerEpoch bounds the pending deposits processed per epoch.
+	MinPerEpochChurnLimitElectra          uint64     `yaml:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA" spec:"true" json:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA,string"`                   // MinPerEpochChurnLimitElectra is the minimum balance churn allowed per epoch.
+	MaxPerEpochActivationExitChurnLimit   uint64     `yaml:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT" spec:"true" json:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT,string"`   // MaxPerEpochActivationExitChurnLimit is the maximum balance churn allowed per epoch for activations and exits.
+	MaxBlobsPerBlockElectra               uint64     `yaml:"MAX_BLOBS_PER_BLOCK_ELECTRA" json:"MAX_BLOBS_PER_BLOCK_ELECTRA,string"`                                           // MaxBlobsPerBlockElectra defines the maximum number of blobs per block post Electra.
+	BlobSidecarSubnetCountElectra         uint64     `yaml:"BLOB_SIDECAR_SUBNET_COUNT_ELECTRA" json:"BLOB_SIDECAR_SUBNET_COUNT_ELECTRA,string"`                               // BlobSidecarSubnetCountElectra defines the number of blob sidecar subnets post Electra.
+	MaxRequestBlobSidecarsElectra         uint64     `yaml:"MAX_REQUEST_BLOB_SIDECARS_ELECTRA" json:"MAX_REQUEST_BLOB_SIDECARS_ELECTRA,string"`                               // MaxRequestBlobSidecarsElectra defines the maximum number of blob sidecars in a single request post Electra.
 }
 
 func (b *BeaconChainConfig) RoundSlotToEpoch(slot uint64) uint64 {
@@ -520,7 +546,7 @@ func (b *BeaconChainConfig) RoundSlotToVotePeriod(slot uint64) uint64 {
 }
 
 func (b *BeaconChainConfig) GetCurrentStateVersion(epoch uint64) StateVersion {
-	forkEpochList := []uint64{b.AltairForkEpoch, b.BellatrixForkEpoch, b.CapellaForkEpoch, b.DenebForkEpoch}
+	forkEpochList := []uint64{b.AltairForkEpoch, b.BellatrixForkEpoch, b.CapellaForkEpoch, b.DenebForkEpoch, b.ElectraForkEpoch}
 	stateVersion := Phase0Version
 	for _, forkEpoch := range forkEpochList {
 		if forkEpoch > epoch {
@@ -544,6 +570,7 @@ func configForkSchedule(b *BeaconChainConfig) map[libcommon.Bytes4]uint64 {
 	fvs[utils.Uint32ToBytes4(uint32(b.BellatrixForkVersion))] = b.BellatrixForkEpoch
 	fvs[utils.Uint32ToBytes4(uint32(b.CapellaForkVersion))] = b.CapellaForkEpoch
 	fvs[utils.Uint32ToBytes4(uint32(b.DenebForkVersion))] = b.DenebForkEpoch
+	fvs[utils.Uint32ToBytes4(uint32(b.ElectraForkVersion))] = b.ElectraForkEpoch
 	return fvs
 }
 
@@ -554,6 +581,7 @@ func configForkNames(b *BeaconChainConfig) map[libcommon.Bytes4]string {
 	fvn[utils.Uint32ToBytes4(uint32(b.BellatrixForkVersion))] = "bellatrix"
 	fvn[utils.Uint32ToBytes4(uint32(b.CapellaForkVersion))] = "capella"
 	fvn[utils.Uint32ToBytes4(uint32(b.DenebForkVersion))] = "deneb"
+	fvn[utils.Uint32ToBytes4(uint32(b.ElectraForkVersion))] = "electra"
 	return fvn
 }
 
@@ -691,6 +719,8 @@ var MainnetBeaconConfig BeaconChainConfig = BeaconChainConfig{
 	CapellaForkEpoch:     194048,
 	DenebForkVersion:     0x04000000,
 	DenebForkEpoch:       269568,
+	ElectraForkVersion:   0x05000000,
+	ElectraForkEpoch:     364032,
 
 	// New values introduced in Altair hard fork 1.
 	// Participation flag indices.
@@ -739,6 +769,28 @@ var MainnetBeaconConfig BeaconChainConfig = BeaconChainConfig{
 
 	MaxBlobGasPerBlock: 786432,
 	MaxBlobsPerBlock:   6,
+
+	// Electra
+	MinActivationBalance:                  32 * 1e9,
+	MaxEffectiveBalanceElectra:            2048 * 1e9,
+	CompoundingWithdrawalPrefix:           ConfigByte(2),
+	MinSlashingPenaltyQuotientElectra:     4096,
+	WhistleBlowerRewardQuotientElectra:    4096,
+	PendingDepositsLimit:                  1 << 27,
+	PendingPartialWithdrawalsLimit:        1 << 27,
+	PendingConsolidationsLimit:            1 << 18,
+	MaxAttesterSlashingsElectra:           1,
+	MaxAttestationsElectra:                8,
+	MaxDepositRequestsPerPayload:          8192,
+	MaxWithdrawalRequestsPerPayload:       16,
+	MaxConsolidationRequestsPerPayload:    2,
+	MaxPendingPartialsPerWithdrawalsSweep: 8,
+	MaxPendingDepositsPerEpoch:            16,
+	MinPerEpochChurnLimitElectra:          128 * 1e9,
+	MaxPerEpochActivationExitChurnLimit:   256 * 1e9,
+	MaxBlobsPerBlockElectra:               9,
+	BlobSidecarSubnetCountElectra:         9,
+	MaxRequestBlobSidecarsElectra:         1152,
 }
 
 func mainnetConfig() BeaconChainConfig {
@@ -777,6 +829,8 @@ func sepoliaConfig() BeaconChainConfig {
 	cfg.CapellaForkVersion = 0x90000072
 	cfg.DenebForkEpoch = 132608
 	cfg.DenebForkVersion = 0x90000073
+	cfg.ElectraForkEpoch = 222464
+	cfg.ElectraForkVersion = 0x90000074
 	cfg.TerminalTotalDifficulty = "17000000000000000"
 	cfg.DepositContractAddress = "0x7f02C3E3c98b133055B8B348B2Ac625669Ed295D"
 	cfg.InitializeForkSchedule()
@@ -800,6 +854,8 @@ func goerliConfig() BeaconChainConfig {
 	cfg.CapellaForkVersion = 0x03001020
 	cfg.DenebForkEpoch = 231680
 	cfg.DenebForkVersion = 0x04001020
+	cfg.ElectraForkEpoch = math.MaxUint64
+	cfg.ElectraForkVersion = 0x05001020
 	cfg.TerminalTotalDifficulty = "10790000"
 	cfg.DepositContractAddress = "0xff50ed3d0ec03aC01D4C79aAd74928BFF48a7b2b"
 	cfg.InitializeForkSchedule()
@@ -826,6 +882,8 @@ func holeskyConfig() BeaconChainConfig {
 	cfg.CapellaForkVersion = 0x04017000
 	cfg.DenebForkEpoch = 29696
 	cfg.DenebForkVersion = 0x05017000
+	cfg.ElectraForkEpoch = 115968
+	cfg.ElectraForkVersion = 0x06017000
 	cfg.TerminalTotalDifficulty = "0"
 	cfg.TerminalBlockHash = [32]byte{}
 	cfg.TerminalBlockHashActivationEpoch = math.MaxUint64
@@ -871,6 +929,9 @@ func gnosisConfig() BeaconChainConfig {
 	cfg.EpochsPerSyncCommitteePeriod = 512
 	cfg.DenebForkEpoch = 889856
 	cfg.DenebForkVersion = 0x04000064
+	// Gnosis runs Electra with its own preset values, which are not wired in yet.
+	cfg.ElectraForkEpoch = math.MaxUint64
+	cfg.ElectraForkVersion = 0x05000064
 	cfg.InactivityScoreRecoveryRate = 16
 	cfg.InactivityScoreBias = 4
 	cfg.MaxWithdrawalsPerPayload = 8
@@ -901,6 +962,8 @@ func chiadoConfig() BeaconChainConfig {
 	cfg.CapellaForkVersion = 0x0300006f
 	cfg.DenebForkEpoch = 516608
 	cfg.DenebForkVersion = 0x0400006f
+	cfg.ElectraForkEpoch = math.MaxUint64
+	cfg.ElectraForkVersion = 0x0500006f
 	cfg.TerminalTotalDifficulty = "231707791542740786049188744689299064356246512"
 	cfg.DepositContractAddress = "0xb97036A26259B7147018913bD58a774cf91acf25"
 	cfg.BaseRewardFactor = 25
@@ -923,6 +986,8 @@ func (b *BeaconChainConfig) GetMinSlashingPenaltyQuotient(version StateVersion)
 		return b.MinSlashingPenaltyQuotientBellatrix
 	case DenebVersion:
 		return b.MinSlashingPenaltyQuotientBellatrix
+	case ElectraVersion:
+		return b.MinSlashingPenaltyQuotientElectra
 	default:
 		panic("not implemented")
 	}
@@ -938,13 +1003,53 @@ func (b *BeaconChainConfig) GetPenaltyQuotient(version StateVersion) uint64 {
 		return b.InactivityPenaltyQuotientBellatrix
 	case CapellaVersion:
 		return b.InactivityPenaltyQuotientBellatrix
-	case DenebVersion:
+	case DenebVersion, ElectraVersion:
 		return b.InactivityPenaltyQuotientBellatrix
 	default:
 		panic("not implemented")
 	}
 }
 
+// GetWhistleBlowerRewardQuotient returns the whistleblower reward quotient for the given version.
+func (b *BeaconChainConfig) GetWhistleBlowerRewardQuotient(version StateVersion) uint64 {
+	if version >= ElectraVersion {
+		return b.WhistleBlowerRewardQuotientElectra
+	}
+	return b.WhistleBlowerRewardQuotient
+}
+
+// MaxBlobsPerBlockByVersion returns the maximum number of blobs per block for the given version.
+func (b *BeaconChainConfig) MaxBlobsPerBlockByVersion(version StateVersion) uint64 {
+	if version >= ElectraVersion {
+		return b.MaxBlobsPerBlockElectra
+	}
+	return b.MaxBlobsPerBlock
+}
+
+// BlobSidecarSubnetCountByVersion returns the number of blob sidecar subnets for the given version.
+func (b *BeaconChainConfig) BlobSidecarSubnetCountByVersion(version StateVersion) uint64 {
+	if version >= ElectraVersion {
+		return b.BlobSidecarSubnetCountElectra
+	}
+	return b.MaxBlobsPerBlock
+}
+
+// MaxAttestationsByVersion returns the maximum number of attestations per block for the given version.
+func (b *BeaconChainConfig) MaxAttestationsByVersion(version StateVersion) uint64 {
+	if version >= ElectraVersion {
+		return b.MaxAttestationsElectra
+	}
+	return b.MaxAttestations
+}
+
+// MaxAttesterSlashingsByVersion returns the maximum number of attester slashings per block for the given version.
+func (b *BeaconChainConfig) MaxAttesterSlashingsByVersion(version StateVersion) uint64 {
+	if version >= ElectraVersion {
+		return b.MaxAttesterSlashingsElectra
+	}
+	return b.MaxAttesterSlashings
+}
+
 // Beacon configs
 var BeaconConfigs map[NetworkType]BeaconChainConfig = map[NetworkType]BeaconChainConfig{
 	MainnetNetwork: mainnetConfig(),
@@ -987,6 +1092,8 @@ func (b *BeaconChainConfig) GetForkVersionByVersion(v StateVersion) uint32 {
 		return uint32(b.CapellaForkVersion)
 	case DenebVersion:
 		return uint32(b.DenebForkVersion)
+	case ElectraVersion:
+		return uint32(b.ElectraForkVersion)
 	}
 	panic("invalid version")
 }
@@ -1003,6 +1110,8 @@ func (b *BeaconChainConfig) GetForkEpochByVersion(v StateVersion) uint64 {
 		return b.CapellaForkEpoch
 	case DenebVersion:
 		return b.DenebForkEpoch
+	case ElectraVersion:
+		return b.ElectraForkEpoch
 	}
 	panic("invalid version")
 }
@@ -21,6 +21,8 @@ const (
 	MaxVoluntaryExits            = 16
 	MaxExecutionChanges          = 16
 	MaxBlobsCommittmentsPerBlock = 4096
+	MaxAttesterSlashingsElectra  = 1
+	MaxAttestationsElectra       = 8
 )
 
 type SignedBeaconBlock struct {
@@ -62,6 +64,8 @@ type BeaconBody struct {
 	// The commitments for beacon chain blobs
 	// With a max of 4 per block
 	BlobKzgCommitments *solid.ListSSZ[*KZGCommitment] `json:"blob_kzg_commitments,omitempty"`
+	// The requests made by the execution layer (deposits, withdrawals and consolidations)
+	ExecutionRequests *ExecutionRequests `json:"execution_requests,omitempty"`
 	// The version of the beacon chain
 	Version   clparams.StateVersion `json:"-"`
 	beaconCfg *clparams.BeaconChainConfig
@@ -131,9 +135,49 @@ func NewBeaconBody(beaconCfg *clparams.BeaconChainConfig) *BeaconBody {
 		ExecutionPayload:   NewEth1Block(clparams.Phase0Version, beaconCfg),
 		ExecutionChanges:   solid.NewStaticListSSZ[*SignedBLSToExecutionChange](MaxExecutionChanges, 172),
 		BlobKzgCommitments: solid.NewStaticListSSZ[*KZGCommitment](MaxBlobsCommittmentsPerBlock, 48),
+		ExecutionRequests:  NewExecutionRequests(),
 	}
 }
 
+// newAttesterSlashingsList returns an empty attester slashings list with the limit of the given version.
+func newAttesterSlashingsList(version clparams.StateVersion) *solid.ListSSZ[*AttesterSlashing] {
+	if version >= clparams.ElectraVersion {
+		return solid.NewDynamicListSSZ[*AttesterSlashing](MaxAttesterSlashingsElectra)
+	}
+	return solid.NewDynamicListSSZ[*AttesterSlashing](MaxAttesterSlashings)
+}
+
+// newAttestationsList returns an empty attestations list with the limit of the given version.
+func newAttestationsList(version clparams.StateVersion) *solid.ListSSZ[*solid.Attestation] {
+	if version >= clparams.ElectraVersion {
+		return solid.NewDynamicListSSZ[*solid.Attestation](MaxAttestationsElectra)
+	}
+	return solid.NewDynamicListSSZ[*solid.Attestation](MaxAttestations)
+}
+
+// SetVersion sets the version of the body, resizing the operation lists whose limits changed in Electra.
+func (b *BeaconBody) SetVersion(version clparams.StateVersion) {
+	if (b.Version >= clparams.ElectraVersion) != (version >= clparams.ElectraVersion) {
+		attesterSlashings := newAttesterSlashingsList(version)
+		b.AttesterSlashings.Range(func(_ int, a *AttesterSlashing, _ int) bool {
+			attesterSlashings.Append(a)
+			return true
+		})
+		attestations := newAttestationsList(version)
+		b.Attestations.Range(func(_ int, a *solid.Attestation, _ int) bool {
+			attestations.Append(a)
+			return true
+		})
+		b.AttesterSlashings, b.Attestations = attesterSlashings, attestations
+	}
+	if version < clparams.ElectraVersion {
+		b.ExecutionRequests = nil
+	} else if b.ExecutionRequests == nil {
+		b.ExecutionRequests = NewExecutionRequests()
+	}
+	b.Version = version
+}
+
 // Version returns beacon block version.
 func (b *SignedBeaconBlock) Version() clparams.StateVersion {
 	return b.Block.Body.Version
@@ -161,10 +205,10 @@ func (b *BeaconBody) EncodingSizeSSZ() (size int) {
 		b.ProposerSlashings = solid.NewStaticListSSZ[*ProposerSlashing](MaxProposerSlashings, 416)
 	}
 	if b.AttesterSlashings == nil {
-		b.AttesterSlashings = solid.NewDynamicListSSZ[*AttesterSlashing](MaxAttesterSlashings)
+		b.AttesterSlashings = newAttesterSlashingsList(b.Version)
 	}
 	if b.Attestations == nil {
-		b.Attestations = solid.NewDynamicListSSZ[*solid.Attestation](MaxAttestations)
+		b.Attestations = newAttestationsList(b.Version)
 	}
 	if b.Deposits == nil {
 		b.Deposits = solid.NewStaticListSSZ[*Deposit](MaxDeposits, 1240)
@@ -181,6 +225,9 @@ func (b *BeaconBody) EncodingSizeSSZ() (size int) {
 	if b.BlobKzgCommitments == nil {
 		b.BlobKzgCommitments = solid.NewStaticListSSZ[*KZGCommitment](MaxBlobsCommittmentsPerBlock, 48)
 	}
+	if b.ExecutionRequests == nil {
+		b.ExecutionRequests = NewExecutionRequests()
+	}
 
 	size += b.ProposerSlashings.EncodingSizeSSZ()
 	size += b.AttesterSlashings.EncodingSizeSSZ()
@@ -196,6 +243,9 @@ func (b *BeaconBody) EncodingSizeSSZ() (size int) {
 	if b.Version >= clparams.DenebVersion {
 		size += b.ExecutionChanges.EncodingSizeSSZ()
 	}
+	if b.Version >= clparams.ElectraVersion {
+		size += b.ExecutionRequests.EncodingSizeSSZ()
+	}
 
 	return
 }
@@ -208,6 +258,12 @@ func (b *BeaconBody) DecodeSSZ(buf []byte, version int) error {
 	}
 
 	b.ExecutionPayload = NewEth1Block(b.Version, b.beaconCfg)
+	b.AttesterSlashings = newAttesterSlashingsList(b.Version)
+	b.Attestations = newAttestationsList(b.Version)
+	b.ExecutionRequests = nil
+	if b.Version >= clparams.ElectraVersion {
+		b.ExecutionRequests = NewExecutionRequests()
+	}
 
 	err := ssz2.UnmarshalSSZ(buf, version, b.getSchema(false)...)
 	return err
@@ -231,6 +287,7 @@ func (b *BeaconBody) Blinded() (*BlindedBeaconBody, error) {
 		ExecutionPayload:   header,
 		ExecutionChanges:   b.ExecutionChanges,
 		BlobKzgCommitments: b.BlobKzgCommitments,
+		ExecutionRequests:  b.ExecutionRequests,
 		Version:            b.Version,
 	}, nil
 }
@@ -253,6 +310,9 @@ func (b *BeaconBody) getSchema(storage bool) []interface{} {
 	if b.Version >= clparams.DenebVersion {
 		s = append(s, b.BlobKzgCommitments)
 	}
+	if b.Version >= clparams.ElectraVersion {
+		s = append(s, b.ExecutionRequests)
+	}
 	return s
 }
 
@@ -332,15 +392,17 @@ func (b *BeaconBody) UnmarshalJSON(buf []byte) error {
 		ExecutionPayload   *Eth1Block                                  `json:"execution_payload,omitempty"`
 		ExecutionChanges   *solid.ListSSZ[*SignedBLSToExecutionChange] `json:"bls_to_execution_changes,omitempty"`
 		BlobKzgCommitments *solid.ListSSZ[*KZGCommitment]              `json:"blob_kzg_commitments,omitempty"`
+		ExecutionRequests  *ExecutionRequests                          `json:"execution_requests,omitempty"`
 	}
 	tmp.ProposerSlashings = solid.NewStaticListSSZ[*ProposerSlashing](MaxProposerSlashings, 416)
-	tmp.AttesterSlashings = solid.NewDynamicListSSZ[*AttesterSlashing](MaxAttesterSlashings)
-	tmp.Attestations = solid.NewDynamicListSSZ[*solid.Attestation](MaxAttestations)
+	tmp.AttesterSlashings = newAttesterSlashingsList(b.Version)
+	tmp.Attestations = newAttestationsList(b.Version)
 	tmp.Deposits = solid.NewStaticListSSZ[*Deposit](MaxDeposits, 1240)
 	tmp.VoluntaryExits = solid.NewStaticListSSZ[*SignedVoluntaryExit](MaxVoluntaryExits, 112)
 	tmp.ExecutionChanges = solid.NewStaticListSSZ[*SignedBLSToExecutionChange](MaxExecutionChanges, 172)
 	tmp.BlobKzgCommitments = solid.NewStaticListSSZ[*KZGCommitment](MaxBlobsCommittmentsPerBlock, 48)
 	tmp.ExecutionPayload = NewEth1Block(b.Version, b.beaconCfg)
+	tmp.ExecutionRequests = NewExecutionRequests()
 
 	if err := json.Unmarshal(buf, &tmp); err != nil {
 		return err
@@ -357,5 +419,9 @@ func (b *BeaconBody) UnmarshalJSON(buf []byte) error {
 	b.ExecutionPayload = tmp.ExecutionPayload
 	b.ExecutionChanges = tmp.ExecutionChanges
 	b.BlobKzgCommitments = tmp.BlobKzgCommitments
+	b.ExecutionRequests = nil
+	if b.Version >= clparams.ElectraVersion {
+		b.ExecutionRequests = tmp.ExecutionRequests
+	}
 	return nil
 }
@@ -2,6 +2,8 @@ package cltypes
 
 import (
 	"github.com/ledgerwatch/erigon-lib/types/clonable"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 )
 
 func (s *SignedBeaconBlock) Clone() clonable.Clonable {
@@ -133,3 +135,35 @@ func (*Root) Clone() clonable.Clonable {
 func (*LightClientUpdatesByRangeRequest) Clone() clonable.Clonable {
 	return &LightClientUpdatesByRangeRequest{}
 }
+
+func (*DepositRequest) Clone() clonable.Clonable {
+	return &DepositRequest{}
+}
+
+func (*WithdrawalRequest) Clone() clonable.Clonable {
+	return &WithdrawalRequest{}
+}
+
+func (*ConsolidationRequest) Clone() clonable.Clonable {
+	return &ConsolidationRequest{}
+}
+
+func (e *ExecutionRequests) Clone() clonable.Clonable {
+	return &ExecutionRequests{
+		Deposits:       e.Deposits.Clone().(*solid.ListSSZ[*DepositRequest]),
+		Withdrawals:    e.Withdrawals.Clone().(*solid.ListSSZ[*WithdrawalRequest]),
+		Consolidations: e.Consolidations.Clone().(*solid.ListSSZ[*ConsolidationRequest]),
+	}
+}
+
+func (*PendingDeposit) Clone() clonable.Clonable {
+	return &PendingDeposit{}
+}
+
+func (*PendingPartialWithdrawal) Clone() clonable.Clonable {
+	return &PendingPartialWithdrawal{}
+}
+
+func (*PendingConsolidation) Clone() clonable.Clonable {
+	return &PendingConsolidation{}
+}
@@ -284,8 +284,9 @@ func (b *Eth1Block) getSchema() []interface{} {
 	return s
 }
 
-// RlpHeader returns the equivalent types.Header struct with RLP-based fields.
-func (b *Eth1Block) RlpHeader(parentRoot *libcommon.Hash) (*types.Header, error) {
+// RlpHeader returns the equivalent types.Header struct with RLP-based fields. From Electra the header commits to the
+// EIP-7685 hash of the execution requests of the beacon block, checked along with the block hash.
+func (b *Eth1Block) RlpHeader(parentRoot *libcommon.Hash, requestsHash libcommon.Hash) (*types.Header, error) {
 	// Reverse the order of the bytes in the BaseFeePerGas array and convert it to a big integer.
 	reversedBaseFeePerGas := libcommon.Copy(b.BaseFeePerGas[:])
 	for i, j := 0, len(reversedBaseFeePerGas)-1; i < j; i, j = i+1, j-1 {
@@ -335,6 +336,9 @@ func (b *Eth1Block) RlpHeader(parentRoot *libcommon.Hash) (*types.Header, error)
 		excessBlobGas := b.ExcessBlobGas
 		header.ExcessBlobGas = &excessBlobGas
 	}
+	if b.version >= clparams.ElectraVersion {
+		header.RequestsHash = &requestsHash
+	}
 
 	// If the header hash does not match the block hash, return an error.
 	if header.Hash() != b.BlockHash {
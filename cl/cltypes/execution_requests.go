@@ -0,0 +1,190 @@
+package cltypes
+
+import (
+	"encoding/json"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+)
+
+// EIP-7685 request types, used to prefix each request list sent to the execution layer.
+const (
+	DepositRequestType       byte = 0x00
+	WithdrawalRequestType    byte = 0x01
+	ConsolidationRequestType byte = 0x02
+)
+
+const (
+	MaxDepositRequestsPerPayload       = 8192
+	MaxWithdrawalRequestsPerPayload    = 16
+	MaxConsolidationRequestsPerPayload = 2
+)
+
+const (
+	depositRequestSize       = length.Bytes48 + length.Hash + 8 + length.Bytes96 + 8
+	withdrawalRequestSize    = length.Addr + length.Bytes48 + 8
+	consolidationRequestSize = length.Addr + length.Bytes48*2
+)
+
+// DepositRequest is a deposit made to the deposit contract, surfaced by the execution layer (EIP-6110).
+type DepositRequest struct {
+	PubKey                libcommon.Bytes48 `json:"pubkey"`
+	WithdrawalCredentials libcommon.Hash    `json:"withdrawal_credentials"`
+	Amount                uint64            `json:"amount,string"`
+	Signature             libcommon.Bytes96 `json:"signature"`
+	Index                 uint64            `json:"index,string"`
+}
+
+func (d *DepositRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.PubKey[:], d.WithdrawalCredentials[:], d.Amount, d.Signature[:], d.Index)
+}
+
+func (d *DepositRequest) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, d.PubKey[:], d.WithdrawalCredentials[:], &d.Amount, d.Signature[:], &d.Index)
+}
+
+func (*DepositRequest) EncodingSizeSSZ() int {
+	return depositRequestSize
+}
+
+func (d *DepositRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.PubKey[:], d.WithdrawalCredentials[:], d.Amount, d.Signature[:], d.Index)
+}
+
+func (*DepositRequest) Static() bool {
+	return true
+}
+
+// WithdrawalRequest is an execution layer triggered exit or partial withdrawal (EIP-7002).
+type WithdrawalRequest struct {
+	SourceAddress   libcommon.Address `json:"source_address"`
+	ValidatorPubKey libcommon.Bytes48 `json:"validator_pubkey"`
+	Amount          uint64            `json:"amount,string"`
+}
+
+func (w *WithdrawalRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, w.SourceAddress[:], w.ValidatorPubKey[:], w.Amount)
+}
+
+func (w *WithdrawalRequest) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, w.SourceAddress[:], w.ValidatorPubKey[:], &w.Amount)
+}
+
+func (*WithdrawalRequest) EncodingSizeSSZ() int {
+	return withdrawalRequestSize
+}
+
+func (w *WithdrawalRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(w.SourceAddress[:], w.ValidatorPubKey[:], w.Amount)
+}
+
+func (*WithdrawalRequest) Static() bool {
+	return true
+}
+
+// ConsolidationRequest is an execution layer triggered consolidation of two validators (EIP-7251).
+type ConsolidationRequest struct {
+	SourceAddress libcommon.Address `json:"source_address"`
+	SourcePubKey  libcommon.Bytes48 `json:"source_pubkey"`
+	TargetPubKey  libcommon.Bytes48 `json:"target_pubkey"`
+}
+
+func (c *ConsolidationRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, c.SourceAddress[:], c.SourcePubKey[:], c.TargetPubKey[:])
+}
+
+func (c *ConsolidationRequest) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, c.SourceAddress[:], c.SourcePubKey[:], c.TargetPubKey[:])
+}
+
+func (*ConsolidationRequest) EncodingSizeSSZ() int {
+	return consolidationRequestSize
+}
+
+func (c *ConsolidationRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(c.SourceAddress[:], c.SourcePubKey[:], c.TargetPubKey[:])
+}
+
+func (*ConsolidationRequest) Static() bool {
+	return true
+}
+
+// ExecutionRequests groups the execution layer requests included in an Electra beacon block body.
+type ExecutionRequests struct {
+	Deposits       *solid.ListSSZ[*DepositRequest]       `json:"deposits"`
+	Withdrawals    *solid.ListSSZ[*WithdrawalRequest]    `json:"withdrawals"`
+	Consolidations *solid.ListSSZ[*ConsolidationRequest] `json:"consolidations"`
+}
+
+func NewExecutionRequests() *ExecutionRequests {
+	return &ExecutionRequests{
+		Deposits:       solid.NewStaticListSSZ[*DepositRequest](MaxDepositRequestsPerPayload, depositRequestSize),
+		Withdrawals:    solid.NewStaticListSSZ[*WithdrawalRequest](MaxWithdrawalRequestsPerPayload, withdrawalRequestSize),
+		Consolidations: solid.NewStaticListSSZ[*ConsolidationRequest](MaxConsolidationRequestsPerPayload, consolidationRequestSize),
+	}
+}
+
+func (e *ExecutionRequests) UnmarshalJSON(buf []byte) error {
+	var tmp struct {
+		Deposits       *solid.ListSSZ[*DepositRequest]       `json:"deposits"`
+		Withdrawals    *solid.ListSSZ[*WithdrawalRequest]    `json:"withdrawals"`
+		Consolidations *solid.ListSSZ[*ConsolidationRequest] `json:"consolidations"`
+	}
+	empty := NewExecutionRequests()
+	tmp.Deposits, tmp.Withdrawals, tmp.Consolidations = empty.Deposits, empty.Withdrawals, empty.Consolidations
+	if err := json.Unmarshal(buf, &tmp); err != nil {
+		return err
+	}
+	e.Deposits, e.Withdrawals, e.Consolidations = tmp.Deposits, tmp.Withdrawals, tmp.Consolidations
+	return nil
+}
+
+func (e *ExecutionRequests) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, e.Deposits, e.Withdrawals, e.Consolidations)
+}
+
+func (e *ExecutionRequests) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, e.Deposits, e.Withdrawals, e.Consolidations)
+}
+
+func (e *ExecutionRequests) EncodingSizeSSZ() int {
+	return 12 + e.Deposits.EncodingSizeSSZ() + e.Withdrawals.EncodingSizeSSZ() + e.Consolidations.EncodingSizeSSZ()
+}
+
+func (e *ExecutionRequests) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(e.Deposits, e.Withdrawals, e.Consolidations)
+}
+
+func (*ExecutionRequests) Static() bool {
+	return false
+}
+
+// EngineRequests returns the requests in the EIP-7685 form expected by engine_newPayloadV4:
+// each non-empty list is encoded as its type byte followed by the SSZ encoding of its elements.
+func (e *ExecutionRequests) EngineRequests() ([][]byte, error) {
+	out := [][]byte{}
+	for _, req := range []struct {
+		typ  byte
+		list ssz.Marshaler
+		len  int
+	}{
+		{DepositRequestType, e.Deposits, e.Deposits.Len()},
+		{WithdrawalRequestType, e.Withdrawals, e.Withdrawals.Len()},
+		{ConsolidationRequestType, e.Consolidations, e.Consolidations.Len()},
+	} {
+		if req.len == 0 {
+			continue
+		}
+		encoded, err := req.list.EncodeSSZ([]byte{req.typ})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, encoded)
+	}
+	return out, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/merkle_tree"
 	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
@@ -18,6 +19,15 @@ type IndexedAttestation struct {
 	Signature        libcommon.Bytes96     `json:"signature"`
 }
 
+// indexedAttestationLimit returns the maximum number of attesting indices, which grows
+// to MAX_VALIDATORS_PER_COMMITTEE * MAX_COMMITTEES_PER_SLOT in Electra (EIP-7549).
+func indexedAttestationLimit(version clparams.StateVersion) int {
+	if version >= clparams.ElectraVersion {
+		return 2048 * 64
+	}
+	return 2048
+}
+
 func NewIndexedAttestation() *IndexedAttestation {
 	return &IndexedAttestation{
 		AttestingIndices: solid.NewRawUint64List(2048, nil),
@@ -53,7 +63,7 @@ func (i *IndexedAttestation) EncodeSSZ(buf []byte) (dst []byte, err error) {
 // DecodeSSZ ssz unmarshals the IndexedAttestation object
 func (i *IndexedAttestation) DecodeSSZ(buf []byte, version int) error {
 	i.Data = solid.NewAttestationData()
-	i.AttestingIndices = solid.NewRawUint64List(2048, nil)
+	i.AttestingIndices = solid.NewRawUint64List(indexedAttestationLimit(clparams.StateVersion(version)), nil)
 
 	return ssz2.UnmarshalSSZ(buf, version, i.AttestingIndices, i.Data, i.Signature[:])
 }
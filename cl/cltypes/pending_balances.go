@@ -0,0 +1,99 @@
+package cltypes
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+)
+
+const (
+	PendingDepositSize           = length.Bytes48 + length.Hash + 8 + length.Bytes96 + 8
+	PendingPartialWithdrawalSize = 24
+	PendingConsolidationSize     = 16
+)
+
+// PendingDeposit is a deposit waiting in the beacon state queue to be applied to a validator balance.
+type PendingDeposit struct {
+	PubKey                libcommon.Bytes48 `json:"pubkey"`
+	WithdrawalCredentials libcommon.Hash    `json:"withdrawal_credentials"`
+	Amount                uint64            `json:"amount,string"`
+	Signature             libcommon.Bytes96 `json:"signature"`
+	Slot                  uint64            `json:"slot,string"`
+}
+
+func (p *PendingDeposit) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, p.PubKey[:], p.WithdrawalCredentials[:], p.Amount, p.Signature[:], p.Slot)
+}
+
+func (p *PendingDeposit) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, p.PubKey[:], p.WithdrawalCredentials[:], &p.Amount, p.Signature[:], &p.Slot)
+}
+
+func (*PendingDeposit) EncodingSizeSSZ() int {
+	return PendingDepositSize
+}
+
+func (p *PendingDeposit) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(p.PubKey[:], p.WithdrawalCredentials[:], p.Amount, p.Signature[:], p.Slot)
+}
+
+func (*PendingDeposit) Static() bool {
+	return true
+}
+
+// PendingPartialWithdrawal is a partial withdrawal requested from the execution layer,
+// waiting for its withdrawable epoch.
+type PendingPartialWithdrawal struct {
+	Index             uint64 `json:"validator_index,string"`
+	Amount            uint64 `json:"amount,string"`
+	WithdrawableEpoch uint64 `json:"withdrawable_epoch,string"`
+}
+
+func (p *PendingPartialWithdrawal) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, p.Index, p.Amount, p.WithdrawableEpoch)
+}
+
+func (p *PendingPartialWithdrawal) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, &p.Index, &p.Amount, &p.WithdrawableEpoch)
+}
+
+func (*PendingPartialWithdrawal) EncodingSizeSSZ() int {
+	return PendingPartialWithdrawalSize
+}
+
+func (p *PendingPartialWithdrawal) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(p.Index, p.Amount, p.WithdrawableEpoch)
+}
+
+func (*PendingPartialWithdrawal) Static() bool {
+	return true
+}
+
+// PendingConsolidation moves the balance of the source validator into the target validator
+// once the source has become withdrawable.
+type PendingConsolidation struct {
+	SourceIndex uint64 `json:"source_index,string"`
+	TargetIndex uint64 `json:"target_index,string"`
+}
+
+func (p *PendingConsolidation) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, p.SourceIndex, p.TargetIndex)
+}
+
+func (p *PendingConsolidation) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, &p.SourceIndex, &p.TargetIndex)
+}
+
+func (*PendingConsolidation) EncodingSizeSSZ() int {
+	return PendingConsolidationSize
+}
+
+func (p *PendingConsolidation) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(p.SourceIndex, p.TargetIndex)
+}
+
+func (*PendingConsolidation) Static() bool {
+	return true
+}
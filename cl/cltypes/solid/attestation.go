@@ -3,6 +3,7 @@ package solid
 import (
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
@@ -20,6 +21,13 @@ const (
 
 	// offset is usually always the same
 	aggregationBitsOffset = 228
+
+	// Electra appends the committee bits (Bitvector[MAX_COMMITTEES_PER_SLOT]) to the static part.
+	CommitteeBitsSize            = 8
+	electraAggregationBitsOffset = aggregationBitsOffset + CommitteeBitsSize
+
+	maxValidatorsPerCommittee = 2048
+	maxCommitteesPerSlot      = 64
 )
 
 // Attestation type represents a statement or confirmation of some occurrence or phenomenon.
@@ -28,6 +36,8 @@ type Attestation struct {
 	staticBuffer [attestationStaticBufferSize]byte
 	// Dynamic field to store aggregation bits
 	aggregationBitsBuffer []byte
+	// Committee bits, only set for Electra attestations (EIP-7549)
+	committeeBits []byte
 }
 
 // Static returns whether the attestation is static or not. For Attestation, it's always false.
@@ -40,6 +50,7 @@ func (a *Attestation) Copy() *Attestation {
 	copy(new.staticBuffer[:], a.staticBuffer[:])
 	new.aggregationBitsBuffer = make([]byte, len(a.aggregationBitsBuffer))
 	copy(new.aggregationBitsBuffer, a.aggregationBitsBuffer)
+	new.committeeBits = libcommon.CopyBytes(a.committeeBits)
 	return new
 }
 
@@ -57,15 +68,29 @@ func NewAttestionFromParameters(
 	return a
 }
 
+// NewElectraAttestionFromParameters creates a new Electra Attestation instance, which carries the committee bits.
+func NewElectraAttestionFromParameters(
+	aggregationBits []byte,
+	attestationData AttestationData,
+	signature [96]byte,
+	committeeBits [CommitteeBitsSize]byte,
+) *Attestation {
+	a := NewAttestionFromParameters(aggregationBits, attestationData, signature)
+	a.SetCommitteeBits(committeeBits[:])
+	return a
+}
+
 func (a Attestation) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		AggregationBits hexutility.Bytes  `json:"aggregation_bits"`
 		Signature       libcommon.Bytes96 `json:"signature"`
 		Data            AttestationData   `json:"data"`
+		CommitteeBits   hexutility.Bytes  `json:"committee_bits,omitempty"`
 	}{
 		AggregationBits: a.aggregationBitsBuffer,
 		Signature:       a.Signature(),
 		Data:            a.AttestantionData(),
+		CommitteeBits:   a.committeeBits,
 	})
 }
 
@@ -74,6 +99,7 @@ func (a *Attestation) UnmarshalJSON(buf []byte) error {
 		AggregationBits hexutility.Bytes  `json:"aggregation_bits"`
 		Signature       libcommon.Bytes96 `json:"signature"`
 		Data            AttestationData   `json:"data"`
+		CommitteeBits   hexutility.Bytes  `json:"committee_bits"`
 	}
 	tmp.Data = NewAttestationData()
 	if err := json.Unmarshal(buf, &tmp); err != nil {
@@ -83,6 +109,13 @@ func (a *Attestation) UnmarshalJSON(buf []byte) error {
 	a.SetAggregationBits(tmp.AggregationBits)
 	a.SetSignature(tmp.Signature)
 	a.SetAttestationData(tmp.Data)
+	a.committeeBits = nil
+	if tmp.CommitteeBits != nil {
+		if len(tmp.CommitteeBits) != CommitteeBitsSize {
+			return fmt.Errorf("invalid committee bits length %d", len(tmp.CommitteeBits))
+		}
+		a.SetCommitteeBits(tmp.CommitteeBits)
+	}
 	return nil
 }
 
@@ -96,6 +129,32 @@ func (a *Attestation) SetAggregationBits(bits []byte) {
 	a.aggregationBitsBuffer = bits
 }
 
+// CommitteeBits returns the committee bits of an Electra attestation, or nil for earlier forks.
+func (a *Attestation) CommitteeBits() []byte {
+	return a.committeeBits
+}
+
+// SetCommitteeBits sets the committee bits, turning the attestation into the Electra format.
+func (a *Attestation) SetCommitteeBits(bits []byte) {
+	a.committeeBits = libcommon.CopyBytes(bits)
+}
+
+// IsElectra reports whether the attestation uses the Electra (EIP-7549) format.
+func (a *Attestation) IsElectra() bool {
+	return a.committeeBits != nil
+}
+
+// CommitteeIndices returns the committee indices set in the committee bits of an Electra attestation.
+func (a *Attestation) CommitteeIndices() []uint64 {
+	indices := []uint64{}
+	for i := 0; i < len(a.committeeBits)*8; i++ {
+		if a.committeeBits[i/8]&(1<<(i%8)) != 0 {
+			indices = append(indices, uint64(i))
+		}
+	}
+	return indices
+}
+
 // AttestantionData returns the attestation data of the Attestation instance.
 func (a *Attestation) AttestantionData() AttestationData {
 	return (AttestationData)(a.staticBuffer[4:132])
@@ -123,23 +182,44 @@ func (a *Attestation) EncodingSizeSSZ() (size int) {
 	if a == nil {
 		return
 	}
-	return size + len(a.aggregationBitsBuffer)
+	return size + len(a.committeeBits) + len(a.aggregationBitsBuffer)
 }
 
 // DecodeSSZ decodes the provided buffer into the Attestation instance.
+// The format is recognised from the aggregation bits offset, which also covers the committee bits in Electra.
 func (a *Attestation) DecodeSSZ(buf []byte, _ int) error {
 	if len(buf) < attestationStaticBufferSize {
 		return ssz.ErrLowBufferSize
 	}
 	copy(a.staticBuffer[:], buf)
-	a.aggregationBitsBuffer = libcommon.CopyBytes(buf[aggregationBitsOffset:])
+	offset := binary.LittleEndian.Uint32(buf)
+	switch offset {
+	case aggregationBitsOffset:
+		a.committeeBits = nil
+	case electraAggregationBitsOffset:
+		if len(buf) < electraAggregationBitsOffset {
+			return ssz.ErrLowBufferSize
+		}
+		a.committeeBits = libcommon.CopyBytes(buf[aggregationBitsOffset:electraAggregationBitsOffset])
+		binary.LittleEndian.PutUint32(a.staticBuffer[:4], aggregationBitsOffset)
+	default:
+		return ssz.ErrBadOffset
+	}
+	a.aggregationBitsBuffer = libcommon.CopyBytes(buf[offset:])
 	return nil
 }
 
 // EncodeSSZ encodes the Attestation instance into the provided buffer.
 func (a *Attestation) EncodeSSZ(dst []byte) ([]byte, error) {
 	buf := dst
-	buf = append(buf, a.staticBuffer[:]...)
+	if a.committeeBits == nil {
+		buf = append(buf, a.staticBuffer[:]...)
+		buf = append(buf, a.aggregationBitsBuffer...)
+		return buf, nil
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, electraAggregationBitsOffset)
+	buf = append(buf, a.staticBuffer[4:]...)
+	buf = append(buf, a.committeeBits...)
 	buf = append(buf, a.aggregationBitsBuffer...)
 	return buf, nil
 }
@@ -149,7 +229,11 @@ func (a *Attestation) CopyHashBufferTo(o []byte) error {
 	for i := 0; i < 128; i++ {
 		o[i] = 0
 	}
-	aggBytesRoot, err := merkle_tree.BitlistRootWithLimit(a.AggregationBits(), 2048)
+	aggregationBitsLimit := uint64(maxValidatorsPerCommittee)
+	if a.committeeBits != nil {
+		aggregationBitsLimit *= maxCommitteesPerSlot
+	}
+	aggBytesRoot, err := merkle_tree.BitlistRootWithLimit(a.AggregationBits(), aggregationBitsLimit)
 	if err != nil {
 		return err
 	}
@@ -164,6 +248,11 @@ func (a *Attestation) CopyHashBufferTo(o []byte) error {
 	copy(o[64:], o[:32])
 	copy(o[:32], aggBytesRoot[:])
 	copy(o[32:64], dataRoot[:])
+	for i := 96; i < 128; i++ {
+		o[i] = 0
+	}
+	// The committee bits fit in a single chunk, hence their root is the right-padded bitvector.
+	copy(o[96:], a.committeeBits)
 	return nil
 }
 
@@ -195,5 +284,6 @@ func (a *Attestation) Clone() clonable.Clonable {
 	return &Attestation{
 		aggregationBitsBuffer: bitsBuffer,
 		staticBuffer:          staticBuffer,
+		committeeBits:         libcommon.CopyBytes(a.committeeBits),
 	}
 }
@@ -13,12 +13,14 @@ import (
 
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
 	"github.com/ledgerwatch/erigon-lib/crypto/kzg"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/sentinel/communication/ssz_snappy"
+	"github.com/ledgerwatch/erigon/cl/utils"
 	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
 	"github.com/spf13/afero"
 )
@@ -32,6 +34,17 @@ type BlobStorage interface {
 	WriteStream(w io.Writer, slot uint64, blockRoot libcommon.Hash, idx uint64) error // Used for P2P networking
 	KzgCommitmentsCount(ctx context.Context, blockRoot libcommon.Hash) (uint32, error)
 	Prune() error
+	// ReadBlobSidecarLocation resolves a versioned hash to the sidecar carrying its blob. Only archives keep this index.
+	ReadBlobSidecarLocation(ctx context.Context, versionedHash libcommon.Hash) (location BlobSidecarLocation, found bool, err error)
+	// IndexFrozenBlobSidecars indexes the versioned hashes of sidecars frozen into snapshots up to frozenSlot (excluded).
+	IndexFrozenBlobSidecars(ctx context.Context, frozenSlot uint64, readFrozen func(slot uint64) ([]*cltypes.BlobSidecar, error)) error
+}
+
+// BlobSidecarLocation identifies a single sidecar, as stored in the versioned hash index.
+type BlobSidecarLocation struct {
+	Slot      uint64
+	BlockRoot libcommon.Hash
+	Index     uint64
 }
 
 type BlobStore struct {
@@ -40,12 +53,22 @@ type BlobStore struct {
 	beaconChainConfig *clparams.BeaconChainConfig
 	ethClock          eth_clock.EthereumClock
 	slotsKept         uint64
+	// archive stores keep a versioned hash => sidecar index and only prune sidecars once they are frozen.
+	archive bool
+	// archivePrunedTo - subdivision up to which (excluded) the frozen sidecars of an archive are removed
+	archivePrunedTo atomic.Uint64
 }
 
 func NewBlobStore(db kv.RwDB, fs afero.Fs, slotsKept uint64, beaconChainConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock) BlobStorage {
 	return &BlobStore{fs: fs, db: db, slotsKept: slotsKept, beaconChainConfig: beaconChainConfig, ethClock: ethClock}
 }
 
+// NewArchiveBlobStore creates a blob store which keeps all sidecars and indexes them by versioned hash.
+// Old sidecars are moved into snapshots by the antiquary: they are removed from the store once frozen and indexed.
+func NewArchiveBlobStore(db kv.RwDB, fs afero.Fs, beaconChainConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock) BlobStorage {
+	return &BlobStore{fs: fs, db: db, slotsKept: math.MaxUint64, beaconChainConfig: beaconChainConfig, ethClock: ethClock, archive: true}
+}
+
 func blobSidecarFilePath(slot, index uint64, blockRoot libcommon.Hash) (folderpath, filepath string) {
 	subdir := slot / subdivisionSlot
 	folderpath = strconv.FormatUint(subdir, 10)
@@ -57,6 +80,7 @@ func blobSidecarFilePath(slot, index uint64, blockRoot libcommon.Hash) (folderpa
 file system layout: <slot/subdivisionSlot>/<blockRoot>_<index>
 indicies:
 - <blockRoot> -> kzg_commitments_length // block
+- <versionedHash> -> <slot><blockRoot><index> // archive only
 */
 
 // WriteBlobSidecars writes the sidecars on the database. it assumes that all blobSidecars are for the same blockRoot and we have all of them.
@@ -93,9 +117,101 @@ func (bs *BlobStore) WriteBlobSidecars(ctx context.Context, blockRoot libcommon.
 	if err := tx.Put(kv.BlockRootToKzgCommitments, blockRoot[:], val); err != nil {
 		return err
 	}
+	if bs.archive {
+		if err := writeVersionedHashes(tx, blockRoot, blobSidecars); err != nil {
+			return err
+		}
+	}
 	return tx.Commit()
 }
 
+func writeVersionedHashes(tx kv.RwTx, blockRoot libcommon.Hash, blobSidecars []*cltypes.BlobSidecar) error {
+	val := make([]byte, 8+length.Hash+8)
+	for _, blobSidecar := range blobSidecars {
+		versionedHash := kzg.KZGToVersionedHash(gokzg4844.KZGCommitment(blobSidecar.KzgCommitment))
+		binary.BigEndian.PutUint64(val, blobSidecar.SignedBlockHeader.Header.Slot)
+		copy(val[8:], blockRoot[:])
+		binary.BigEndian.PutUint64(val[8+length.Hash:], blobSidecar.Index)
+		if err := tx.Put(kv.VersionedHashToBlobSidecar, versionedHash[:], val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bs *BlobStore) ReadBlobSidecarLocation(ctx context.Context, versionedHash libcommon.Hash) (BlobSidecarLocation, bool, error) {
+	tx, err := bs.db.BeginRo(ctx)
+	if err != nil {
+		return BlobSidecarLocation{}, false, err
+	}
+	defer tx.Rollback()
+	val, err := tx.GetOne(kv.VersionedHashToBlobSidecar, versionedHash[:])
+	if err != nil {
+		return BlobSidecarLocation{}, false, err
+	}
+	if len(val) != 8+length.Hash+8 {
+		return BlobSidecarLocation{}, false, nil
+	}
+	return BlobSidecarLocation{
+		Slot:      binary.BigEndian.Uint64(val),
+		BlockRoot: libcommon.BytesToHash(val[8 : 8+length.Hash]),
+		Index:     binary.BigEndian.Uint64(val[8+length.Hash:]),
+	}, true, nil
+}
+
+// IndexFrozenBlobSidecars indexes sidecars which reached the snapshots without going through this store, e.g. downloaded ones.
+// Progress is kept in the database, so each frozen slot is only read once.
+func (bs *BlobStore) IndexFrozenBlobSidecars(ctx context.Context, frozenSlot uint64, readFrozen func(slot uint64) ([]*cltypes.BlobSidecar, error)) error {
+	if !bs.archive || bs.beaconChainConfig.DenebForkEpoch == math.MaxUint64 {
+		return nil
+	}
+	from := bs.beaconChainConfig.DenebForkEpoch * bs.beaconChainConfig.SlotsPerEpoch
+	if err := bs.db.View(ctx, func(tx kv.Tx) error {
+		progress, err := tx.GetOne(kv.DatabaseInfo, kv.FrozenBlobsIndexedKey)
+		if err != nil {
+			return err
+		}
+		if len(progress) == 8 {
+			from = utils.Max64(from, binary.BigEndian.Uint64(progress))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	// Commit every subdivision so that progress survives restarts.
+	for from < frozenSlot {
+		to := utils.Min64(frozenSlot, (from/subdivisionSlot+1)*subdivisionSlot)
+		if err := bs.db.Update(ctx, func(tx kv.RwTx) error {
+			for slot := from; slot < to; slot++ {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				blobSidecars, err := readFrozen(slot)
+				if err != nil {
+					return err
+				}
+				if len(blobSidecars) == 0 {
+					continue
+				}
+				blockRoot, err := blobSidecars[0].SignedBlockHeader.Header.HashSSZ()
+				if err != nil {
+					return err
+				}
+				if err := writeVersionedHashes(tx, blockRoot, blobSidecars); err != nil {
+					return err
+				}
+			}
+			progress := make([]byte, 8)
+			binary.BigEndian.PutUint64(progress, to)
+			return tx.Put(kv.DatabaseInfo, kv.FrozenBlobsIndexedKey, progress)
+		}); err != nil {
+			return err
+		}
+		from = to
+	}
+	return nil
+}
+
 // ReadBlobSidecars reads the sidecars from the database. it assumes that all blobSidecars are for the same blockRoot and we have all of them.
 func (bs *BlobStore) ReadBlobSidecars(ctx context.Context, slot uint64, blockRoot libcommon.Hash) ([]*cltypes.BlobSidecar, bool, error) {
 	tx, err := bs.db.BeginRo(ctx)
@@ -136,6 +252,9 @@ func (bs *BlobStore) ReadBlobSidecars(ctx context.Context, slot uint64, blockRoo
 
 // Do a bit of pruning
 func (bs *BlobStore) Prune() error {
+	if bs.archive {
+		return bs.pruneFrozen()
+	}
 	if bs.slotsKept == math.MaxUint64 {
 		return nil
 	}
@@ -154,6 +273,33 @@ func (bs *BlobStore) Prune() error {
 	return nil
 }
 
+// pruneFrozen removes the sidecars of an archive which are in the snapshots, i.e. the subdivisions below the
+// frozen slots indexed by IndexFrozenBlobSidecars. The snapshots serve them from then on.
+func (bs *BlobStore) pruneFrozen() error {
+	var frozenSlot uint64
+	if err := bs.db.View(context.Background(), func(tx kv.Tx) error {
+		progress, err := tx.GetOne(kv.DatabaseInfo, kv.FrozenBlobsIndexedKey)
+		if err != nil {
+			return err
+		}
+		if len(progress) == 8 {
+			frozenSlot = binary.BigEndian.Uint64(progress)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	// only whole subdivisions are removed
+	to := frozenSlot / subdivisionSlot
+	for i := bs.archivePrunedTo.Load(); i < to; i++ {
+		if err := bs.fs.RemoveAll(strconv.FormatUint(i, 10)); err != nil {
+			return err
+		}
+	}
+	bs.archivePrunedTo.Store(max(bs.archivePrunedTo.Load(), to))
+	return nil
+}
+
 func (bs *BlobStore) WriteStream(w io.Writer, slot uint64, blockRoot libcommon.Hash, idx uint64) error {
 	_, filePath := blobSidecarFilePath(slot, idx, blockRoot)
 	file, err := bs.fs.Open(filePath)
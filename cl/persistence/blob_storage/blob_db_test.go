@@ -4,7 +4,9 @@ import (
 	"context"
 	"testing"
 
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/crypto/kzg"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
 	"github.com/ledgerwatch/erigon/cl/clparams"
@@ -50,3 +52,62 @@ func TestBlobDB(t *testing.T) {
 	require.Equal(t, s1.SignedBlockHeader, sidecars[0].SignedBlockHeader)
 	require.Equal(t, s2.SignedBlockHeader, sidecars[1].SignedBlockHeader)
 }
+
+func TestBlobDBArchiveVersionedHash(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	s1 := cltypes.NewBlobSidecar(0, &cltypes.Blob{1}, libcommon.Bytes48{2}, libcommon.Bytes48{3}, &cltypes.SignedBeaconBlockHeader{Header: &cltypes.BeaconBlockHeader{Slot: 1}}, solid.NewHashVector(cltypes.CommitmentBranchSize))
+	s2 := cltypes.NewBlobSidecar(1, &cltypes.Blob{3}, libcommon.Bytes48{5}, libcommon.Bytes48{9}, &cltypes.SignedBeaconBlockHeader{Header: &cltypes.BeaconBlockHeader{Slot: 1}}, solid.NewHashVector(cltypes.CommitmentBranchSize))
+
+	bs := NewArchiveBlobStore(db, afero.NewMemMapFs(), &clparams.MainnetBeaconConfig, nil)
+	blockRoot := libcommon.Hash{1}
+	require.NoError(t, bs.WriteBlobSidecars(context.Background(), blockRoot, []*cltypes.BlobSidecar{s1, s2}))
+
+	location, found, err := bs.ReadBlobSidecarLocation(context.Background(), libcommon.Hash(kzg.KZGToVersionedHash(gokzg4844.KZGCommitment(s2.KzgCommitment))))
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, BlobSidecarLocation{Slot: 1, BlockRoot: blockRoot, Index: 1}, location)
+
+	// sidecars which aren't frozen yet are kept
+	require.NoError(t, bs.Prune())
+	_, found, err = bs.ReadBlobSidecars(context.Background(), 1, blockRoot)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	_, found, err = bs.ReadBlobSidecarLocation(context.Background(), libcommon.Hash{0xff})
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestBlobDBArchivePruneFrozen(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	cfg := clparams.MainnetBeaconConfig
+	cfg.DenebForkEpoch = 0
+	bs := NewArchiveBlobStore(db, afero.NewMemMapFs(), &cfg, nil)
+	var blockRoots []libcommon.Hash
+	for i, slot := range []uint64{1, subdivisionSlot + 1, 2*subdivisionSlot + 1} {
+		s := cltypes.NewBlobSidecar(0, &cltypes.Blob{byte(i)}, libcommon.Bytes48{byte(i)}, libcommon.Bytes48{byte(i)}, &cltypes.SignedBeaconBlockHeader{Header: &cltypes.BeaconBlockHeader{Slot: slot}}, solid.NewHashVector(cltypes.CommitmentBranchSize))
+		blockRoot := libcommon.Hash{byte(i + 1)}
+		require.NoError(t, bs.WriteBlobSidecars(context.Background(), blockRoot, []*cltypes.BlobSidecar{s}))
+		blockRoots = append(blockRoots, blockRoot)
+	}
+
+	// the sidecars of the first subdivision and part of the second are frozen
+	require.NoError(t, bs.IndexFrozenBlobSidecars(context.Background(), subdivisionSlot+10, func(slot uint64) ([]*cltypes.BlobSidecar, error) {
+		return nil, nil
+	}))
+	require.NoError(t, bs.Prune())
+	for i, slot := range []uint64{1, subdivisionSlot + 1, 2*subdivisionSlot + 1} {
+		_, found, err := bs.ReadBlobSidecars(context.Background(), slot, blockRoots[i])
+		require.NoError(t, err)
+		require.Equal(t, i > 0, found)
+	}
+	// the versioned hash index still points to the frozen sidecar
+	location, found, err := bs.ReadBlobSidecarLocation(context.Background(), libcommon.Hash(kzg.KZGToVersionedHash(gokzg4844.KZGCommitment(libcommon.Bytes48{0}))))
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint64(1), location.Slot)
+}
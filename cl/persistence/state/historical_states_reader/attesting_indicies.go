@@ -13,32 +13,51 @@ import (
 	"github.com/ledgerwatch/erigon/cl/utils"
 )
 
-func (r *HistoricalStatesReader) attestingIndicies(attestation solid.AttestationData, aggregationBits []byte, checkBitsLength bool, mix libcommon.Hash, idxs []uint64) ([]uint64, error) {
+func (r *HistoricalStatesReader) attestingIndicies(att *solid.Attestation, checkBitsLength bool, mix libcommon.Hash, idxs []uint64) ([]uint64, error) {
+	attestation := att.AttestantionData()
+	aggregationBits := att.AggregationBits()
 	slot := attestation.Slot()
 	committeesPerSlot := committeeCount(r.cfg, slot/r.cfg.SlotsPerEpoch, idxs)
-	committeeIndex := attestation.CommitteeIndex()
-	index := (slot%r.cfg.SlotsPerEpoch)*committeesPerSlot + committeeIndex
 	count := committeesPerSlot * r.cfg.SlotsPerEpoch
 
-	committee, err := r.ComputeCommittee(mix, idxs, attestation.Slot(), count, index)
-	if err != nil {
-		return nil, err
-	}
-	aggregationBitsLen := utils.GetBitlistLength(aggregationBits)
-	if checkBitsLength && utils.GetBitlistLength(aggregationBits) != len(committee) {
-		return nil, fmt.Errorf("GetAttestingIndicies: invalid aggregation bits. agg bits size: %d, expect: %d", aggregationBitsLen, len(committee))
+	committeeIndicies := []uint64{attestation.CommitteeIndex()}
+	if att.IsElectra() {
+		// Electra attestations aggregate several committees, named by the committee bits.
+		committeeIndicies = committeeIndicies[:0]
+		committeeBits := att.CommitteeBits()
+		for i := uint64(0); i < uint64(len(committeeBits))*8; i++ {
+			if committeeBits[i/8]&(1<<(i%8)) != 0 {
+				committeeIndicies = append(committeeIndicies, i)
+			}
+		}
 	}
 
 	attestingIndices := []uint64{}
-	for i, member := range committee {
-		bitIndex := i % 8
-		sliceIndex := i / 8
-		if sliceIndex >= len(aggregationBits) {
-			return nil, fmt.Errorf("GetAttestingIndicies: committee is too big")
+	committeeOffset := 0
+	for _, committeeIndex := range committeeIndicies {
+		if committeeIndex >= committeesPerSlot {
+			return nil, fmt.Errorf("GetAttestingIndicies: committee index %d out of range", committeeIndex)
 		}
-		if (aggregationBits[sliceIndex] & (1 << bitIndex)) > 0 {
-			attestingIndices = append(attestingIndices, member)
+		index := (slot%r.cfg.SlotsPerEpoch)*committeesPerSlot + committeeIndex
+		committee, err := r.ComputeCommittee(mix, idxs, attestation.Slot(), count, index)
+		if err != nil {
+			return nil, err
+		}
+		for i, member := range committee {
+			bit := committeeOffset + i
+			sliceIndex := bit / 8
+			if sliceIndex >= len(aggregationBits) {
+				return nil, fmt.Errorf("GetAttestingIndicies: committee is too big")
+			}
+			if (aggregationBits[sliceIndex] & (1 << (bit % 8))) > 0 {
+				attestingIndices = append(attestingIndices, member)
+			}
 		}
+		committeeOffset += len(committee)
+	}
+	aggregationBitsLen := utils.GetBitlistLength(aggregationBits)
+	if checkBitsLength && aggregationBitsLen != committeeOffset {
+		return nil, fmt.Errorf("GetAttestingIndicies: invalid aggregation bits. agg bits size: %d, expect: %d", aggregationBitsLen, committeeOffset)
 	}
 	return attestingIndices, nil
 }
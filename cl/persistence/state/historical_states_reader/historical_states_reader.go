@@ -774,7 +774,7 @@ func (r *HistoricalStatesReader) ReadPartecipations(tx kv.Tx, slot uint64) (*sol
 			}
 
 			var attestingIndicies []uint64
-			attestingIndicies, err = r.attestingIndicies(data, attestation.AggregationBits(), true, mix, activeIndicies)
+			attestingIndicies, err = r.attestingIndicies(attestation, true, mix, activeIndicies)
 			if err != nil {
 				return false
 			}
@@ -177,6 +177,10 @@ func GetUnslashedParticipatingIndices(b abstract.BeaconState, flagIndex int, epo
 // Implementation of is_eligible_for_activation_queue.
 // Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#is_eligible_for_activation_queue
 func IsValidatorEligibleForActivationQueue(b abstract.BeaconState, validator solid.Validator) bool {
+	if b.Version() >= clparams.ElectraVersion {
+		return validator.ActivationEligibilityEpoch() == b.BeaconConfig().FarFutureEpoch &&
+			validator.EffectiveBalance() >= b.BeaconConfig().MinActivationBalance
+	}
 	return validator.ActivationEligibilityEpoch() == b.BeaconConfig().FarFutureEpoch &&
 		validator.EffectiveBalance() == b.BeaconConfig().MaxEffectiveBalance
 }
@@ -201,25 +205,66 @@ func ComputeTimestampAtSlot(b abstract.BeaconState, slot uint64) uint64 {
 
 // ExpectedWithdrawals calculates the expected withdrawals that can be made by validators in the current epoch
 func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes.Withdrawal {
+	withdrawals, _ := ExpectedWithdrawalsAndPartials(b, currentEpoch)
+	return withdrawals
+}
+
+// ExpectedWithdrawalsAndPartials calculates the expected withdrawals and, from Electra onwards, the number of
+// pending partial withdrawals consumed by them.
+func ExpectedWithdrawalsAndPartials(b abstract.BeaconState, currentEpoch uint64) ([]*cltypes.Withdrawal, uint64) {
+	cfg := b.BeaconConfig()
 	// Get the current epoch, the next withdrawal index, and the next withdrawal validator index
 	nextWithdrawalIndex := b.NextWithdrawalIndex()
 	nextWithdrawalValidatorIndex := b.NextWithdrawalValidatorIndex()
 
 	// Determine the upper bound for the loop and initialize the withdrawals slice with a capacity of bound
 	maxValidators := uint64(b.ValidatorLength())
-	maxValidatorsPerWithdrawalsSweep := b.BeaconConfig().MaxValidatorsPerWithdrawalsSweep
+	maxValidatorsPerWithdrawalsSweep := cfg.MaxValidatorsPerWithdrawalsSweep
 	bound := utils.Min64(maxValidators, maxValidatorsPerWithdrawalsSweep)
 	withdrawals := make([]*cltypes.Withdrawal, 0, bound)
+	// withdrawn tracks the amount already withdrawn per validator in this payload.
+	withdrawn := map[uint64]uint64{}
+
+	// Consume the pending partial withdrawals first.
+	var processedPartialWithdrawalsCount uint64
+	if b.Version() >= clparams.ElectraVersion {
+		b.PendingPartialWithdrawals().Range(func(_ int, withdrawal *cltypes.PendingPartialWithdrawal, _ int) bool {
+			if withdrawal.WithdrawableEpoch > currentEpoch || uint64(len(withdrawals)) == cfg.MaxPendingPartialsPerWithdrawalsSweep {
+				return false
+			}
+			validator, err := b.ValidatorForValidatorIndex(int(withdrawal.Index))
+			if err != nil {
+				return false
+			}
+			balance, _ := b.ValidatorBalance(int(withdrawal.Index))
+			balance -= withdrawn[withdrawal.Index]
+			if validator.ExitEpoch() == cfg.FarFutureEpoch && validator.EffectiveBalance() >= cfg.MinActivationBalance && balance > cfg.MinActivationBalance {
+				wd := validator.WithdrawalCredentials()
+				amount := utils.Min64(balance-cfg.MinActivationBalance, withdrawal.Amount)
+				withdrawals = append(withdrawals, &cltypes.Withdrawal{
+					Index:     nextWithdrawalIndex,
+					Validator: withdrawal.Index,
+					Address:   libcommon.BytesToAddress(wd[12:]),
+					Amount:    amount,
+				})
+				withdrawn[withdrawal.Index] += amount
+				nextWithdrawalIndex++
+			}
+			processedPartialWithdrawalsCount++
+			return true
+		})
+	}
 
 	// Loop through the validators to calculate expected withdrawals
-	for validatorCount := uint64(0); validatorCount < bound && len(withdrawals) != int(b.BeaconConfig().MaxWithdrawalsPerPayload); validatorCount++ {
+	for validatorCount := uint64(0); validatorCount < bound && len(withdrawals) != int(cfg.MaxWithdrawalsPerPayload); validatorCount++ {
 		// Get the validator and balance for the current validator index
 		// supposedly this operation is safe because we checked the validator length about
 		currentValidator, _ := b.ValidatorForValidatorIndex(int(nextWithdrawalValidatorIndex))
 		currentBalance, _ := b.ValidatorBalance(int(nextWithdrawalValidatorIndex))
+		currentBalance -= withdrawn[nextWithdrawalValidatorIndex]
 		wd := currentValidator.WithdrawalCredentials()
 		// Check if the validator is fully withdrawable
-		if isFullyWithdrawableValidator(b.BeaconConfig(), currentValidator, currentBalance, currentEpoch) {
+		if isFullyWithdrawableValidator(cfg, b.Version(), currentValidator, currentBalance, currentEpoch) {
 			// Add a new withdrawal with the validator's withdrawal credentials and balance
 			newWithdrawal := &cltypes.Withdrawal{
 				Index:     nextWithdrawalIndex,
@@ -229,13 +274,13 @@ func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes
 			}
 			withdrawals = append(withdrawals, newWithdrawal)
 			nextWithdrawalIndex++
-		} else if isPartiallyWithdrawableValidator(b.BeaconConfig(), currentValidator, currentBalance) { // Check if the validator is partially withdrawable
+		} else if isPartiallyWithdrawableValidator(cfg, b.Version(), currentValidator, currentBalance) { // Check if the validator is partially withdrawable
 			// Add a new withdrawal with the validator's withdrawal credentials and balance minus the maximum effective balance
 			newWithdrawal := &cltypes.Withdrawal{
 				Index:     nextWithdrawalIndex,
 				Validator: nextWithdrawalValidatorIndex,
 				Address:   libcommon.BytesToAddress(wd[12:]),
-				Amount:    currentBalance - b.BeaconConfig().MaxEffectiveBalance,
+				Amount:    currentBalance - GetMaxEffectiveBalance(cfg, b.Version(), currentValidator),
 			}
 			withdrawals = append(withdrawals, newWithdrawal)
 			nextWithdrawalIndex++
@@ -246,5 +291,17 @@ func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes
 	}
 
 	// Return the withdrawals slice
-	return withdrawals
+	return withdrawals, processedPartialWithdrawalsCount
+}
+
+// GetPendingBalanceToWithdraw returns the sum of the pending partial withdrawals of a validator.
+func GetPendingBalanceToWithdraw(b abstract.BeaconState, validatorIndex uint64) uint64 {
+	var total uint64
+	b.PendingPartialWithdrawals().Range(func(_ int, withdrawal *cltypes.PendingPartialWithdrawal, _ int) bool {
+		if withdrawal.Index == validatorIndex {
+			total += withdrawal.Amount
+		}
+		return true
+	})
+	return total
 }
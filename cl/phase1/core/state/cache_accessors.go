@@ -272,16 +272,29 @@ func (b *CachingBeaconState) ComputeNextSyncCommittee() (*solid.SyncCommittee, e
 			return nil, err
 		}
 		candidateIndex := activeValidatorIndicies[shuffledIndex]
-		// Compute random byte.
-		buf := make([]byte, 8)
-		binary.LittleEndian.PutUint64(buf, i/32)
-		input := append(seed[:], buf...)
-		randomByte := uint64(utils.Sha256(input)[i%32])
 		// retrieve validator.
 		validator, err := b.ValidatorForValidatorIndex(int(candidateIndex))
 		if err != nil {
 			return nil, err
 		}
+		buf := make([]byte, 8)
+		if b.Version() >= clparams.ElectraVersion {
+			// Compute a 16-bit random value against the Electra maximum effective balance.
+			binary.LittleEndian.PutUint64(buf, i/16)
+			input := append(seed[:], buf...)
+			randomBytes := utils.Sha256(input)
+			offset := (i % 16) * 2
+			randomValue := uint64(binary.LittleEndian.Uint16(randomBytes[offset : offset+2]))
+			if validator.EffectiveBalance()*math.MaxUint16 >= beaconConfig.MaxEffectiveBalanceElectra*randomValue {
+				syncCommitteePubKeys = append(syncCommitteePubKeys, validator.PublicKey())
+			}
+			i++
+			continue
+		}
+		// Compute random byte.
+		binary.LittleEndian.PutUint64(buf, i/32)
+		input := append(seed[:], buf...)
+		randomByte := uint64(utils.Sha256(input)[i%32])
 		if validator.EffectiveBalance()*math.MaxUint8 >= beaconConfig.MaxEffectiveBalance*randomByte {
 			syncCommitteePubKeys = append(syncCommitteePubKeys, validator.PublicKey())
 		}
@@ -337,6 +350,60 @@ func (b *CachingBeaconState) GetAttestingIndicies(
 	return attestingIndices, nil
 }
 
+// GetAttestingIndiciesForAttestation retrieves attesting indicies for a full attestation, handling the Electra
+// format where a single attestation aggregates over the committees selected by its committee bits.
+func (b *CachingBeaconState) GetAttestingIndiciesForAttestation(
+	attestation *solid.Attestation,
+	checkBitsLength bool,
+) ([]uint64, error) {
+	if !attestation.IsElectra() {
+		return b.GetAttestingIndicies(attestation.AttestantionData(), attestation.AggregationBits(), checkBitsLength)
+	}
+	data := attestation.AttestantionData()
+	aggregationBits := attestation.AggregationBits()
+	committeeIndices := attestation.CommitteeIndices()
+	if len(committeeIndices) == 0 {
+		return nil, fmt.Errorf("GetAttestingIndiciesForAttestation: no committee bits set")
+	}
+	committeeCount := b.CommitteeCount(GetEpochAtSlot(b.BeaconConfig(), data.Slot()))
+
+	attestingIndices := []uint64{}
+	committeeOffset := 0
+	for _, committeeIndex := range committeeIndices {
+		if committeeIndex >= committeeCount {
+			return nil, fmt.Errorf("GetAttestingIndiciesForAttestation: committee index %d out of range", committeeIndex)
+		}
+		committee, err := b.GetBeaconCommitee(data.Slot(), committeeIndex)
+		if err != nil {
+			return nil, err
+		}
+		committeeAttesters := 0
+		for i, member := range committee {
+			bitIndex := committeeOffset + i
+			if bitIndex/8 >= len(aggregationBits) {
+				return nil, fmt.Errorf("GetAttestingIndiciesForAttestation: committee is too big")
+			}
+			if (aggregationBits[bitIndex/8] & (1 << (bitIndex % 8))) > 0 {
+				attestingIndices = append(attestingIndices, member)
+				committeeAttesters++
+			}
+		}
+		if checkBitsLength && committeeAttesters == 0 {
+			return nil, fmt.Errorf("GetAttestingIndiciesForAttestation: committee %d has no attesters", committeeIndex)
+		}
+		committeeOffset += len(committee)
+	}
+	aggregationBitsLen := utils.GetBitlistLength(aggregationBits)
+	if checkBitsLength && aggregationBitsLen != committeeOffset {
+		return nil, fmt.Errorf(
+			"GetAttestingIndiciesForAttestation: invalid aggregation bits. agg bits size: %d, expect: %d",
+			aggregationBitsLen,
+			committeeOffset,
+		)
+	}
+	return attestingIndices, nil
+}
+
 // See: https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#get_validator_churn_limit
 func (b *CachingBeaconState) GetValidatorChurnLimit() uint64 {
 	activeIndsCount := uint64(len(b.GetActiveValidatorsIndices(Epoch(b))))
@@ -356,3 +423,23 @@ func (b *CachingBeaconState) GetValidatorActivationChurnLimit() uint64 {
 	}
 	return b.GetValidatorChurnLimit()
 }
+
+// GetBalanceChurnLimit returns the amount of stake that may enter or leave the validator set per epoch.
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-get_balance_churn_limit
+func (b *CachingBeaconState) GetBalanceChurnLimit() uint64 {
+	cfg := b.BeaconConfig()
+	churn := utils.Max64(cfg.MinPerEpochChurnLimitElectra, b.GetTotalActiveBalance()/cfg.ChurnLimitQuotient)
+	return churn - churn%cfg.EffectiveBalanceIncrement
+}
+
+// GetActivationExitChurnLimit returns the churn available for activations and exits.
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-get_activation_exit_churn_limit
+func (b *CachingBeaconState) GetActivationExitChurnLimit() uint64 {
+	return utils.Min64(b.BeaconConfig().MaxPerEpochActivationExitChurnLimit, b.GetBalanceChurnLimit())
+}
+
+// GetConsolidationChurnLimit returns the churn available for consolidations.
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-get_consolidation_churn_limit
+func (b *CachingBeaconState) GetConsolidationChurnLimit() uint64 {
+	return b.GetBalanceChurnLimit() - b.GetActivationExitChurnLimit()
+}
@@ -61,7 +61,7 @@ func (b *CachingBeaconState) SlashValidator(slashedInd uint64, whistleblowerInd
 		whistleblowerInd = new(uint64)
 		*whistleblowerInd = proposerInd
 	}
-	whistleBlowerReward := newEffectiveBalance / b.BeaconConfig().WhistleBlowerRewardQuotient
+	whistleBlowerReward := newEffectiveBalance / b.BeaconConfig().GetWhistleBlowerRewardQuotient(b.Version())
 	proposerReward := b.getSlashingProposerReward(whistleBlowerReward)
 	if err := IncreaseBalance(b, proposerInd, proposerReward); err != nil {
 		return 0, err
@@ -83,6 +83,15 @@ func (b *CachingBeaconState) InitiateValidatorExit(index uint64) error {
 	}
 
 	currentEpoch := Epoch(b)
+	if b.Version() >= clparams.ElectraVersion {
+		effectiveBalance, err := b.ValidatorEffectiveBalance(int(index))
+		if err != nil {
+			return err
+		}
+		exitQueueEpoch := b.ComputeExitEpochAndUpdateChurn(effectiveBalance)
+		b.SetExitEpochForValidatorAtIndex(int(index), exitQueueEpoch)
+		return b.SetWithdrawableEpochForValidatorAtIndex(int(index), exitQueueEpoch+b.BeaconConfig().MinValidatorWithdrawabilityDelay)
+	}
 	exitQueueEpoch := ComputeActivationExitEpoch(b.BeaconConfig(), currentEpoch)
 	b.ForEachValidator(func(v solid.Validator, idx, total int) bool {
 		if v.ExitEpoch() != b.BeaconConfig().FarFutureEpoch && v.ExitEpoch() > exitQueueEpoch {
@@ -111,3 +120,48 @@ func (b *CachingBeaconState) InitiateValidatorExit(index uint64) error {
 	b.SetWithdrawableEpochForValidatorAtIndex(int(index), newWithdrawableEpoch)
 	return nil
 }
+
+// ComputeExitEpochAndUpdateChurn consumes exit churn for the given balance and returns the epoch at which it may exit.
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-compute_exit_epoch_and_update_churn
+func (b *CachingBeaconState) ComputeExitEpochAndUpdateChurn(exitBalance uint64) uint64 {
+	earliestExitEpoch := utils.Max64(b.EarliestExitEpoch(), ComputeActivationExitEpoch(b.BeaconConfig(), Epoch(b)))
+	perEpochChurn := b.GetActivationExitChurnLimit()
+	// New epoch for exits.
+	exitBalanceToConsume := b.ExitBalanceToConsume()
+	if b.EarliestExitEpoch() < earliestExitEpoch {
+		exitBalanceToConsume = perEpochChurn
+	}
+	// Exit doesn't fit in the current earliest epoch.
+	if exitBalance > exitBalanceToConsume {
+		balanceToProcess := exitBalance - exitBalanceToConsume
+		additionalEpochs := (balanceToProcess-1)/perEpochChurn + 1
+		earliestExitEpoch += additionalEpochs
+		exitBalanceToConsume += additionalEpochs * perEpochChurn
+	}
+	b.SetExitBalanceToConsume(exitBalanceToConsume - exitBalance)
+	b.SetEarliestExitEpoch(earliestExitEpoch)
+	return earliestExitEpoch
+}
+
+// ComputeConsolidationEpochAndUpdateChurn consumes consolidation churn for the given balance and returns the
+// epoch at which the consolidation may happen.
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-compute_consolidation_epoch_and_update_churn
+func (b *CachingBeaconState) ComputeConsolidationEpochAndUpdateChurn(consolidationBalance uint64) uint64 {
+	earliestConsolidationEpoch := utils.Max64(b.EarliestConsolidationEpoch(), ComputeActivationExitEpoch(b.BeaconConfig(), Epoch(b)))
+	perEpochConsolidationChurn := b.GetConsolidationChurnLimit()
+	// New epoch for consolidations.
+	consolidationBalanceToConsume := b.ConsolidationBalanceToConsume()
+	if b.EarliestConsolidationEpoch() < earliestConsolidationEpoch {
+		consolidationBalanceToConsume = perEpochConsolidationChurn
+	}
+	// Consolidation doesn't fit in the current earliest epoch.
+	if consolidationBalance > consolidationBalanceToConsume {
+		balanceToProcess := consolidationBalance - consolidationBalanceToConsume
+		additionalEpochs := (balanceToProcess-1)/perEpochConsolidationChurn + 1
+		earliestConsolidationEpoch += additionalEpochs
+		consolidationBalanceToConsume += additionalEpochs * perEpochConsolidationChurn
+	}
+	b.SetConsolidationBalanceToConsume(consolidationBalanceToConsume - consolidationBalance)
+	b.SetEarliestConsolidationEpoch(earliestConsolidationEpoch)
+	return earliestConsolidationEpoch
+}
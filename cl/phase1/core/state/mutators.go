@@ -1,6 +1,12 @@
 package state
 
-import "github.com/ledgerwatch/erigon/cl/abstract"
+import (
+	"github.com/ledgerwatch/erigon/cl/abstract"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+)
+
+// G2PointAtInfinity is the compressed encoding of the BLS signature point at infinity.
+var G2PointAtInfinity = [96]byte{0xc0}
 
 func IncreaseBalance(b abstract.BeaconState, index, delta uint64) error {
 	currentBalance, err := b.ValidatorBalance(int(index))
@@ -21,3 +27,45 @@ func DecreaseBalance(b abstract.BeaconState, index, delta uint64) error {
 	}
 	return b.SetValidatorBalance(int(index), newBalance)
 }
+
+// QueueExcessActiveBalance moves the balance above the minimum activation balance into the pending deposits queue.
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-queue_excess_active_balance
+func QueueExcessActiveBalance(b abstract.BeaconState, index uint64) error {
+	balance, err := b.ValidatorBalance(int(index))
+	if err != nil {
+		return err
+	}
+	if balance <= b.BeaconConfig().MinActivationBalance {
+		return nil
+	}
+	if err := b.SetValidatorBalance(int(index), b.BeaconConfig().MinActivationBalance); err != nil {
+		return err
+	}
+	validator, err := b.ValidatorForValidatorIndex(int(index))
+	if err != nil {
+		return err
+	}
+	// G2 point at infinity is used as a placeholder signature, and the genesis slot marks it as
+	// not coming from a deposit request.
+	b.AppendPendingDeposit(&cltypes.PendingDeposit{
+		PubKey:                validator.PublicKey(),
+		WithdrawalCredentials: validator.WithdrawalCredentials(),
+		Amount:                balance - b.BeaconConfig().MinActivationBalance,
+		Signature:             G2PointAtInfinity,
+		Slot:                  b.BeaconConfig().GenesisSlot,
+	})
+	return nil
+}
+
+// SwitchToCompoundingValidator updates the validator credentials to the compounding prefix and queues its excess balance.
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-switch_to_compounding_validator
+func SwitchToCompoundingValidator(b abstract.BeaconState, index uint64) error {
+	validator, err := b.ValidatorForValidatorIndex(int(index))
+	if err != nil {
+		return err
+	}
+	credentials := validator.WithdrawalCredentials()
+	credentials[0] = byte(b.BeaconConfig().CompoundingWithdrawalPrefix)
+	b.SetWithdrawalCredentialForValidatorAtIndex(int(index), credentials)
+	return QueueExcessActiveBalance(b, index)
+}
@@ -60,6 +60,27 @@ func (b *BeaconState) CopyInto(dst *BeaconState) error {
 		dst.historicalSummaries.Append(value)
 		return true
 	})
+	dst.depositRequestsStartIndex = b.depositRequestsStartIndex
+	dst.depositBalanceToConsume = b.depositBalanceToConsume
+	dst.exitBalanceToConsume = b.exitBalanceToConsume
+	dst.earliestExitEpoch = b.earliestExitEpoch
+	dst.consolidationBalanceToConsume = b.consolidationBalanceToConsume
+	dst.earliestConsolidationEpoch = b.earliestConsolidationEpoch
+	dst.pendingDeposits = solid.NewStaticListSSZ[*cltypes.PendingDeposit](int(b.beaconConfig.PendingDepositsLimit), cltypes.PendingDepositSize)
+	b.pendingDeposits.Range(func(_ int, value *cltypes.PendingDeposit, _ int) bool {
+		dst.pendingDeposits.Append(value)
+		return true
+	})
+	dst.pendingPartialWithdrawals = solid.NewStaticListSSZ[*cltypes.PendingPartialWithdrawal](int(b.beaconConfig.PendingPartialWithdrawalsLimit), cltypes.PendingPartialWithdrawalSize)
+	b.pendingPartialWithdrawals.Range(func(_ int, value *cltypes.PendingPartialWithdrawal, _ int) bool {
+		dst.pendingPartialWithdrawals.Append(value)
+		return true
+	})
+	dst.pendingConsolidations = solid.NewStaticListSSZ[*cltypes.PendingConsolidation](int(b.beaconConfig.PendingConsolidationsLimit), cltypes.PendingConsolidationSize)
+	b.pendingConsolidations.Range(func(_ int, value *cltypes.PendingConsolidation, _ int) bool {
+		dst.pendingConsolidations.Append(value)
+		return true
+	})
 	dst.version = b.version
 	// Now sync internals
 	copy(dst.leaves, b.leaves)
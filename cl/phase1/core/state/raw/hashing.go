@@ -21,41 +21,55 @@ func (b *BeaconState) HashSSZ() (out [32]byte, err error) {
 	// 	fmt.Println(i/32, libcommon.BytesToHash(b.leaves[i:i+32]))
 	// }
 	// Pad to 32 of length
-	err = merkle_tree.MerkleRootFromFlatLeaves(b.leaves, out[:])
+	err = merkle_tree.MerkleRootFromFlatLeaves(b.activeLeaves(), out[:])
 	return
 }
 
+// activeLeaves returns the leaves of the state tree for the current version.
+// Electra grows the state past 32 fields, which adds a level to the tree.
+func (b *BeaconState) activeLeaves() []byte {
+	if b.version >= clparams.ElectraVersion {
+		return b.leaves
+	}
+	return b.leaves[:32*32]
+}
+
+// treeDepth returns the depth of the state tree for the current version.
+func (b *BeaconState) treeDepth() int {
+	if b.version >= clparams.ElectraVersion {
+		return 6
+	}
+	return 5
+}
+
+func (b *BeaconState) leavesSchema() []interface{} {
+	leaves := b.activeLeaves()
+	schema := []interface{}{}
+	for i := 0; i < len(leaves); i += 32 {
+		schema = append(schema, leaves[i:i+32])
+	}
+	return schema
+}
+
 func (b *BeaconState) CurrentSyncCommitteeBranch() ([][32]byte, error) {
 	if err := b.computeDirtyLeaves(); err != nil {
 		return nil, err
 	}
-	schema := []interface{}{}
-	for i := 0; i < len(b.leaves); i += 32 {
-		schema = append(schema, b.leaves[i:i+32])
-	}
-	return merkle_tree.MerkleProof(5, 22, schema...)
+	return merkle_tree.MerkleProof(b.treeDepth(), int(CurrentSyncCommitteeLeafIndex), b.leavesSchema()...)
 }
 
 func (b *BeaconState) NextSyncCommitteeBranch() ([][32]byte, error) {
 	if err := b.computeDirtyLeaves(); err != nil {
 		return nil, err
 	}
-	schema := []interface{}{}
-	for i := 0; i < len(b.leaves); i += 32 {
-		schema = append(schema, b.leaves[i:i+32])
-	}
-	return merkle_tree.MerkleProof(5, 23, schema...)
+	return merkle_tree.MerkleProof(b.treeDepth(), int(NextSyncCommitteeLeafIndex), b.leavesSchema()...)
 }
 
 func (b *BeaconState) FinalityRootBranch() ([][32]byte, error) {
 	if err := b.computeDirtyLeaves(); err != nil {
 		return nil, err
 	}
-	schema := []interface{}{}
-	for i := 0; i < len(b.leaves); i += 32 {
-		schema = append(schema, b.leaves[i:i+32])
-	}
-	proof, err := merkle_tree.MerkleProof(5, 20, schema...)
+	proof, err := merkle_tree.MerkleProof(b.treeDepth(), int(FinalizedCheckpointLeafIndex), b.leavesSchema()...)
 	if err != nil {
 		return nil, err
 	}
@@ -345,6 +359,67 @@ func (b *BeaconState) computeDirtyLeaves() error {
 	}
 	log.Trace("HistoricalSummaries hashing", "elapsed", time.Since(begin))
 
+	if b.version < clparams.ElectraVersion {
+		return nil
+	}
+
+	// Field(28): DepositRequestsStartIndex
+	if b.isLeafDirty(DepositRequestsStartIndexLeafIndex) {
+		b.updateLeaf(DepositRequestsStartIndexLeafIndex, merkle_tree.Uint64Root(b.depositRequestsStartIndex))
+	}
+
+	// Field(29): DepositBalanceToConsume
+	if b.isLeafDirty(DepositBalanceToConsumeLeafIndex) {
+		b.updateLeaf(DepositBalanceToConsumeLeafIndex, merkle_tree.Uint64Root(b.depositBalanceToConsume))
+	}
+
+	// Field(30): ExitBalanceToConsume
+	if b.isLeafDirty(ExitBalanceToConsumeLeafIndex) {
+		b.updateLeaf(ExitBalanceToConsumeLeafIndex, merkle_tree.Uint64Root(b.exitBalanceToConsume))
+	}
+
+	// Field(31): EarliestExitEpoch
+	if b.isLeafDirty(EarliestExitEpochLeafIndex) {
+		b.updateLeaf(EarliestExitEpochLeafIndex, merkle_tree.Uint64Root(b.earliestExitEpoch))
+	}
+
+	// Field(32): ConsolidationBalanceToConsume
+	if b.isLeafDirty(ConsolidationBalanceToConsumeLeafIndex) {
+		b.updateLeaf(ConsolidationBalanceToConsumeLeafIndex, merkle_tree.Uint64Root(b.consolidationBalanceToConsume))
+	}
+
+	// Field(33): EarliestConsolidationEpoch
+	if b.isLeafDirty(EarliestConsolidationEpochLeafIndex) {
+		b.updateLeaf(EarliestConsolidationEpochLeafIndex, merkle_tree.Uint64Root(b.earliestConsolidationEpoch))
+	}
+
+	// Field(34): PendingDeposits
+	if b.isLeafDirty(PendingDepositsLeafIndex) {
+		root, err := b.pendingDeposits.HashSSZ()
+		if err != nil {
+			return err
+		}
+		b.updateLeaf(PendingDepositsLeafIndex, root)
+	}
+
+	// Field(35): PendingPartialWithdrawals
+	if b.isLeafDirty(PendingPartialWithdrawalsLeafIndex) {
+		root, err := b.pendingPartialWithdrawals.HashSSZ()
+		if err != nil {
+			return err
+		}
+		b.updateLeaf(PendingPartialWithdrawalsLeafIndex, root)
+	}
+
+	// Field(36): PendingConsolidations
+	if b.isLeafDirty(PendingConsolidationsLeafIndex) {
+		root, err := b.pendingConsolidations.HashSSZ()
+		if err != nil {
+			return err
+		}
+		b.updateLeaf(PendingConsolidationsLeafIndex, root)
+	}
+
 	return nil
 }
 
@@ -497,3 +497,65 @@ func (b *BeaconState) SetSlashings(slashings solid.Uint64VectorSSZ) {
 	b.markLeaf(SlashingsLeafIndex)
 	b.slashings = slashings
 }
+
+// Electra
+
+func (b *BeaconState) SetDepositRequestsStartIndex(index uint64) {
+	b.markLeaf(DepositRequestsStartIndexLeafIndex)
+	b.depositRequestsStartIndex = index
+}
+
+func (b *BeaconState) SetDepositBalanceToConsume(balance uint64) {
+	b.markLeaf(DepositBalanceToConsumeLeafIndex)
+	b.depositBalanceToConsume = balance
+}
+
+func (b *BeaconState) SetExitBalanceToConsume(balance uint64) {
+	b.markLeaf(ExitBalanceToConsumeLeafIndex)
+	b.exitBalanceToConsume = balance
+}
+
+func (b *BeaconState) SetEarliestExitEpoch(epoch uint64) {
+	b.markLeaf(EarliestExitEpochLeafIndex)
+	b.earliestExitEpoch = epoch
+}
+
+func (b *BeaconState) SetConsolidationBalanceToConsume(balance uint64) {
+	b.markLeaf(ConsolidationBalanceToConsumeLeafIndex)
+	b.consolidationBalanceToConsume = balance
+}
+
+func (b *BeaconState) SetEarliestConsolidationEpoch(epoch uint64) {
+	b.markLeaf(EarliestConsolidationEpochLeafIndex)
+	b.earliestConsolidationEpoch = epoch
+}
+
+func (b *BeaconState) AppendPendingDeposit(deposit *cltypes.PendingDeposit) {
+	b.markLeaf(PendingDepositsLeafIndex)
+	b.pendingDeposits.Append(deposit)
+}
+
+func (b *BeaconState) SetPendingDeposits(deposits *solid.ListSSZ[*cltypes.PendingDeposit]) {
+	b.markLeaf(PendingDepositsLeafIndex)
+	b.pendingDeposits = deposits
+}
+
+func (b *BeaconState) AppendPendingPartialWithdrawal(withdrawal *cltypes.PendingPartialWithdrawal) {
+	b.markLeaf(PendingPartialWithdrawalsLeafIndex)
+	b.pendingPartialWithdrawals.Append(withdrawal)
+}
+
+func (b *BeaconState) SetPendingPartialWithdrawals(withdrawals *solid.ListSSZ[*cltypes.PendingPartialWithdrawal]) {
+	b.markLeaf(PendingPartialWithdrawalsLeafIndex)
+	b.pendingPartialWithdrawals = withdrawals
+}
+
+func (b *BeaconState) AppendPendingConsolidation(consolidation *cltypes.PendingConsolidation) {
+	b.markLeaf(PendingConsolidationsLeafIndex)
+	b.pendingConsolidations.Append(consolidation)
+}
+
+func (b *BeaconState) SetPendingConsolidations(consolidations *solid.ListSSZ[*cltypes.PendingConsolidation]) {
+	b.markLeaf(PendingConsolidationsLeafIndex)
+	b.pendingConsolidations = consolidations
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state/raw"
 
 	"github.com/ledgerwatch/erigon/cl/utils"
@@ -14,6 +15,7 @@ func ComputeProposerIndex(b *raw.BeaconState, indices []uint64, seed [32]byte) (
 		return 0, nil
 	}
 	maxRandomByte := uint64(1<<8 - 1)
+	maxRandomValue := uint64(1<<16 - 1)
 	i := uint64(0)
 	total := uint64(len(indices))
 	input := make([]byte, 40)
@@ -27,13 +29,25 @@ func ComputeProposerIndex(b *raw.BeaconState, indices []uint64, seed [32]byte) (
 		if candidateIndex >= uint64(b.ValidatorLength()) {
 			return 0, fmt.Errorf("candidate index out of range: %d for validator set of length: %d", candidateIndex, b.ValidatorLength())
 		}
-		copy(input, seed[:])
-		binary.LittleEndian.PutUint64(input[32:], i/32)
-		randomByte := uint64(utils.Sha256(input)[i%32])
 		validator, err := b.ValidatorForValidatorIndex(int(candidateIndex))
 		if err != nil {
 			return 0, err
 		}
+		copy(input, seed[:])
+		if b.Version() >= clparams.ElectraVersion {
+			// Electra samples a 16-bit random value against the larger maximum effective balance.
+			binary.LittleEndian.PutUint64(input[32:], i/16)
+			randomBytes := utils.Sha256(input)
+			offset := (i % 16) * 2
+			randomValue := uint64(binary.LittleEndian.Uint16(randomBytes[offset : offset+2]))
+			if validator.EffectiveBalance()*maxRandomValue >= b.BeaconConfig().MaxEffectiveBalanceElectra*randomValue {
+				return candidateIndex, nil
+			}
+			i += 1
+			continue
+		}
+		binary.LittleEndian.PutUint64(input[32:], i/32)
+		randomByte := uint64(utils.Sha256(input)[i%32])
 		if validator.EffectiveBalance()*maxRandomByte >= b.BeaconConfig().MaxEffectiveBalance*randomByte {
 			return candidateIndex, nil
 		}
@@ -1,6 +1,9 @@
 package state
 
 import (
+	"math"
+	"sort"
+
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
@@ -114,3 +117,80 @@ func (b *CachingBeaconState) UpgradeToDeneb() error {
 	b.SetVersion(clparams.DenebVersion)
 	return nil
 }
+
+func (b *CachingBeaconState) UpgradeToElectra() error {
+	b.previousStateRoot = libcommon.Hash{}
+	epoch := Epoch(b.BeaconState)
+	// update version
+	fork := b.Fork()
+	fork.Epoch = epoch
+	fork.PreviousVersion = fork.CurrentVersion
+	fork.CurrentVersion = utils.Uint32ToBytes4(uint32(b.BeaconConfig().ElectraForkVersion))
+	b.SetFork(fork)
+	// Update the state root cache
+	b.SetVersion(clparams.ElectraVersion)
+
+	// Set new fields
+	earliestExitEpoch := ComputeActivationExitEpoch(b.BeaconConfig(), epoch)
+	b.ForEachValidator(func(v solid.Validator, idx, total int) bool {
+		if v.ExitEpoch() != b.BeaconConfig().FarFutureEpoch && v.ExitEpoch() > earliestExitEpoch {
+			earliestExitEpoch = v.ExitEpoch()
+		}
+		return true
+	})
+	b.SetDepositRequestsStartIndex(math.MaxUint64)
+	b.SetDepositBalanceToConsume(0)
+	b.SetEarliestExitEpoch(earliestExitEpoch + 1)
+	b.SetEarliestConsolidationEpoch(ComputeActivationExitEpoch(b.BeaconConfig(), epoch))
+	b.SetExitBalanceToConsume(b.GetActivationExitChurnLimit())
+	b.SetConsolidationBalanceToConsume(b.GetConsolidationChurnLimit())
+
+	// Add validators that are not yet active to the pending deposits queue.
+	preActivation := []uint64{}
+	b.ForEachValidator(func(v solid.Validator, idx, total int) bool {
+		if v.ActivationEpoch() == b.BeaconConfig().FarFutureEpoch {
+			preActivation = append(preActivation, uint64(idx))
+		}
+		return true
+	})
+	sort.SliceStable(preActivation, func(i, j int) bool {
+		vi, _ := b.ValidatorForValidatorIndex(int(preActivation[i]))
+		vj, _ := b.ValidatorForValidatorIndex(int(preActivation[j]))
+		return vi.ActivationEligibilityEpoch() < vj.ActivationEligibilityEpoch()
+	})
+	for _, index := range preActivation {
+		balance, err := b.ValidatorBalance(int(index))
+		if err != nil {
+			return err
+		}
+		if err := b.SetValidatorBalance(int(index), 0); err != nil {
+			return err
+		}
+		validator, err := b.ValidatorForValidatorIndex(int(index))
+		if err != nil {
+			return err
+		}
+		b.SetEffectiveBalanceForValidatorAtIndex(int(index), 0)
+		b.SetActivationEligibilityEpochForValidatorAtIndex(int(index), b.BeaconConfig().FarFutureEpoch)
+		b.AppendPendingDeposit(&cltypes.PendingDeposit{
+			PubKey:                validator.PublicKey(),
+			WithdrawalCredentials: validator.WithdrawalCredentials(),
+			Amount:                balance,
+			Signature:             G2PointAtInfinity,
+			Slot:                  b.BeaconConfig().GenesisSlot,
+		})
+	}
+	// Ensure early adopters of compounding credentials go through the activation churn.
+	for index := 0; index < b.ValidatorLength(); index++ {
+		validator, err := b.ValidatorForValidatorIndex(index)
+		if err != nil {
+			return err
+		}
+		if HasCompoundingWithdrawalCredential(b.BeaconConfig(), validator) {
+			if err := QueueExcessActiveBalance(b, uint64(index)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -3,11 +3,15 @@ package state
 import (
 	"sort"
 
+	"github.com/Giulio2002/bls"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state/lru"
 
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/fork"
 	"github.com/ledgerwatch/erigon/cl/utils"
 )
 
@@ -28,8 +32,12 @@ func GetIndexedAttestation(attestation *solid.Attestation, attestingIndicies []u
 	sort.Slice(attestingIndicies, func(i, j int) bool {
 		return attestingIndicies[i] < attestingIndicies[j]
 	})
+	limit := 2048
+	if attestation.IsElectra() {
+		limit *= 64
+	}
 	return &cltypes.IndexedAttestation{
-		AttestingIndices: solid.NewRawUint64List(2048, attestingIndicies),
+		AttestingIndices: solid.NewRawUint64List(limit, attestingIndicies),
 		Data:             attestation.AttestantionData(),
 		Signature:        attestation.Signature(),
 	}
@@ -50,20 +58,84 @@ func ValidatorFromDeposit(conf *clparams.BeaconChainConfig, deposit *cltypes.Dep
 	return validator
 }
 
+// ElectraValidatorFromDeposit builds a new validator for an Electra deposit, capping the effective balance
+// by the maximum allowed for its withdrawal credentials.
+func ElectraValidatorFromDeposit(conf *clparams.BeaconChainConfig, pubKey libcommon.Bytes48, withdrawalCredentials libcommon.Hash, amount uint64) solid.Validator {
+	validator := solid.NewValidator()
+	validator.SetPublicKey(pubKey)
+	validator.SetWithdrawalCredentials(withdrawalCredentials)
+	validator.SetActivationEligibilityEpoch(conf.FarFutureEpoch)
+	validator.SetActivationEpoch(conf.FarFutureEpoch)
+	validator.SetExitEpoch(conf.FarFutureEpoch)
+	validator.SetWithdrawableEpoch(conf.FarFutureEpoch)
+	maxEffectiveBalance := GetMaxEffectiveBalance(conf, clparams.ElectraVersion, validator)
+	validator.SetEffectiveBalance(utils.Min64(amount-amount%conf.EffectiveBalanceIncrement, maxEffectiveBalance))
+	return validator
+}
+
 // Check whether a validator is fully withdrawable at the given epoch.
-func isFullyWithdrawableValidator(conf *clparams.BeaconChainConfig, validator solid.Validator, balance uint64, epoch uint64) bool {
-	withdrawalCredentials := validator.WithdrawalCredentials()
-	return withdrawalCredentials[0] == byte(conf.ETH1AddressWithdrawalPrefixByte) &&
+func isFullyWithdrawableValidator(conf *clparams.BeaconChainConfig, version clparams.StateVersion, validator solid.Validator, balance uint64, epoch uint64) bool {
+	return hasWithdrawableCredential(conf, version, validator) &&
 		validator.WithdrawableEpoch() <= epoch && balance > 0
 }
 
 // Check whether a validator is partially withdrawable.
-func isPartiallyWithdrawableValidator(conf *clparams.BeaconChainConfig, validator solid.Validator, balance uint64) bool {
-	withdrawalCredentials := validator.WithdrawalCredentials()
-	return withdrawalCredentials[0] == byte(conf.ETH1AddressWithdrawalPrefixByte) &&
-		validator.EffectiveBalance() == conf.MaxEffectiveBalance && balance > conf.MaxEffectiveBalance
+func isPartiallyWithdrawableValidator(conf *clparams.BeaconChainConfig, version clparams.StateVersion, validator solid.Validator, balance uint64) bool {
+	maxEffectiveBalance := GetMaxEffectiveBalance(conf, version, validator)
+	return hasWithdrawableCredential(conf, version, validator) &&
+		validator.EffectiveBalance() == maxEffectiveBalance && balance > maxEffectiveBalance
+}
+
+// hasWithdrawableCredential checks whether withdrawals can be sent to the validator's credentials:
+// execution address credentials, plus compounding credentials from Electra onwards.
+func hasWithdrawableCredential(conf *clparams.BeaconChainConfig, version clparams.StateVersion, validator solid.Validator) bool {
+	if version >= clparams.ElectraVersion {
+		return HasExecutionWithdrawalCredential(conf, validator)
+	}
+	return HasEth1WithdrawalCredential(conf, validator)
+}
+
+// HasEth1WithdrawalCredential checks whether the validator withdraws to an execution address.
+func HasEth1WithdrawalCredential(conf *clparams.BeaconChainConfig, validator solid.Validator) bool {
+	return validator.WithdrawalCredentials()[0] == byte(conf.ETH1AddressWithdrawalPrefixByte)
+}
+
+// HasCompoundingWithdrawalCredential checks whether the validator has compounding (0x02) credentials.
+func HasCompoundingWithdrawalCredential(conf *clparams.BeaconChainConfig, validator solid.Validator) bool {
+	return validator.WithdrawalCredentials()[0] == byte(conf.CompoundingWithdrawalPrefix)
+}
+
+// HasExecutionWithdrawalCredential checks whether the validator has either eth1 or compounding credentials.
+func HasExecutionWithdrawalCredential(conf *clparams.BeaconChainConfig, validator solid.Validator) bool {
+	return HasEth1WithdrawalCredential(conf, validator) || HasCompoundingWithdrawalCredential(conf, validator)
+}
+
+// GetMaxEffectiveBalance returns the maximal effective balance the validator may reach.
+func GetMaxEffectiveBalance(conf *clparams.BeaconChainConfig, version clparams.StateVersion, validator solid.Validator) uint64 {
+	if version < clparams.ElectraVersion {
+		return conf.MaxEffectiveBalance
+	}
+	if HasCompoundingWithdrawalCredential(conf, validator) {
+		return conf.MaxEffectiveBalanceElectra
+	}
+	return conf.MinActivationBalance
 }
 
 func ComputeActivationExitEpoch(config *clparams.BeaconChainConfig, epoch uint64) uint64 {
 	return epoch + 1 + config.MaxSeedLookahead
 }
+
+// IsValidDepositSignature verifies the proof of possession of a deposit, which the deposit contract does not check.
+func IsValidDepositSignature(conf *clparams.BeaconChainConfig, data *cltypes.DepositData) (bool, error) {
+	// Agnostic domain.
+	domain, err := fork.ComputeDomain(conf.DomainDeposit[:], utils.Uint32ToBytes4(uint32(conf.GenesisForkVersion)), [32]byte{})
+	if err != nil {
+		return false, err
+	}
+	depositMessageRoot, err := data.MessageHash()
+	if err != nil {
+		return false, err
+	}
+	signedRoot := utils.Sha256(depositMessageRoot[:], domain)
+	return bls.Verify(data.Signature[:], signedRoot[:], data.PubKey[:])
+}
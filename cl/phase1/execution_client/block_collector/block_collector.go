@@ -55,7 +55,15 @@ func (b *blockCollector) AddBlock(block *cltypes.BeaconBlock) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	payload := block.Body.ExecutionPayload
-	encodedBlock, err := encodeBlock(payload, block.ParentRoot)
+	var requestsHash common.Hash
+	if block.Version() >= clparams.ElectraVersion {
+		requests, err := block.Body.ExecutionRequests.EngineRequests()
+		if err != nil {
+			return err
+		}
+		requestsHash = types.CalcRequestsHash(requests)
+	}
+	encodedBlock, err := encodeBlock(payload, block.ParentRoot, requestsHash)
 	if err != nil {
 		return err
 	}
@@ -93,7 +101,8 @@ func (b *blockCollector) Flush(ctx context.Context) error {
 		}
 		version := clparams.StateVersion(v[0])
 		parentRoot := common.BytesToHash(v[1:33])
-		v = v[33:]
+		requestsHash := common.BytesToHash(v[33:65])
+		v = v[65:]
 		executionPayload := cltypes.NewEth1Block(version, b.beaconChainCfg)
 		if err := executionPayload.DecodeSSZ(v, int(version)); err != nil {
 			return err
@@ -104,7 +113,7 @@ func (b *blockCollector) Flush(ctx context.Context) error {
 			b.logger.Warn("bad blocks segment received", "err", err)
 			return err
 		}
-		header, err := executionPayload.RlpHeader(&parentRoot)
+		header, err := executionPayload.RlpHeader(&parentRoot, requestsHash)
 		if err != nil {
 			b.logger.Warn("bad blocks segment received", "err", err)
 			return err
@@ -134,13 +143,15 @@ func (b *blockCollector) Flush(ctx context.Context) error {
 }
 
 // serializes block value
-func encodeBlock(payload *cltypes.Eth1Block, parentRoot common.Hash) ([]byte, error) {
+func encodeBlock(payload *cltypes.Eth1Block, parentRoot, requestsHash common.Hash) ([]byte, error) {
 	encodedPayload, err := payload.EncodeSSZ(nil)
 	if err != nil {
 		return nil, fmt.Errorf("error encoding execution payload during download: %s", err)
 	}
 	// Use snappy compression that the temporary files do not take too much disk.
-	return utils.CompressSnappy(append([]byte{byte(payload.Version())}, append(parentRoot[:], encodedPayload...)...)), nil
+	encoded := append([]byte{byte(payload.Version())}, parentRoot[:]...)
+	encoded = append(encoded, requestsHash[:]...)
+	return utils.CompressSnappy(append(encoded, encodedPayload...)), nil
 }
 
 // payloadKey returns the key for the payload: number + payload.HashTreeRoot()
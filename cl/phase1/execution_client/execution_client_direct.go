@@ -24,12 +24,13 @@ func NewExecutionClientDirect(chainRW eth1_chain_reader.ChainReaderWriterEth1) (
 	}, nil
 }
 
-func (cc *ExecutionClientDirect) NewPayload(ctx context.Context, payload *cltypes.Eth1Block, beaconParentRoot *libcommon.Hash, versionedHashes []libcommon.Hash) (invalid bool, err error) {
+func (cc *ExecutionClientDirect) NewPayload(ctx context.Context, payload *cltypes.Eth1Block, beaconParentRoot *libcommon.Hash, versionedHashes []libcommon.Hash, executionRequests [][]byte) (invalid bool, err error) {
 	if payload == nil {
 		return
 	}
 
-	header, err := payload.RlpHeader(beaconParentRoot)
+	// the requests hash is part of the block hash checked by RlpHeader
+	header, err := payload.RlpHeader(beaconParentRoot, types.CalcRequestsHash(executionRequests))
 	if err != nil {
 		return true, err
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 
 	"github.com/ledgerwatch/log/v3"
 
@@ -53,7 +54,7 @@ func NewExecutionClientRPC(jwtSecret []byte, addr string, port int) (*ExecutionC
 	}, nil
 }
 
-func (cc *ExecutionClientRpc) NewPayload(ctx context.Context, payload *cltypes.Eth1Block, beaconParentRoot *libcommon.Hash, versionedHashes []libcommon.Hash) (invalid bool, err error) {
+func (cc *ExecutionClientRpc) NewPayload(ctx context.Context, payload *cltypes.Eth1Block, beaconParentRoot *libcommon.Hash, versionedHashes []libcommon.Hash, executionRequests [][]byte) (invalid bool, err error) {
 	if payload == nil {
 		return
 	}
@@ -72,6 +73,8 @@ func (cc *ExecutionClientRpc) NewPayload(ctx context.Context, payload *cltypes.E
 		engineMethod = rpc_helper.EngineNewPayloadV2
 	case clparams.DenebVersion:
 		engineMethod = rpc_helper.EngineNewPayloadV3
+	case clparams.ElectraVersion:
+		engineMethod = rpc_helper.EngineNewPayloadV4
 	default:
 		err = fmt.Errorf("invalid payload version")
 		return
@@ -115,6 +118,13 @@ func (cc *ExecutionClientRpc) NewPayload(ctx context.Context, payload *cltypes.E
 	if versionedHashes != nil {
 		args = append(args, versionedHashes, *beaconParentRoot)
 	}
+	if payload.Version() >= clparams.ElectraVersion {
+		requests := make([]hexutility.Bytes, len(executionRequests))
+		for i, request := range executionRequests {
+			requests[i] = request
+		}
+		args = append(args, requests)
+	}
 	err = cc.client.CallContext(ctx, &payloadStatus, engineMethod, args...)
 	if err != nil {
 		err = fmt.Errorf("execution Client RPC failed to retrieve the NewPayload status response, err: %w", err)
@@ -3,6 +3,7 @@ package rpc_helper
 const EngineNewPayloadV1 = "engine_newPayloadV1"
 const EngineNewPayloadV2 = "engine_newPayloadV2"
 const EngineNewPayloadV3 = "engine_newPayloadV3"
+const EngineNewPayloadV4 = "engine_newPayloadV4"
 
 const ForkChoiceUpdatedV1 = "engine_forkchoiceUpdatedV1"
 const ForkChoiceUpdatedV2 = "engine_forkchoiceUpdatedV2"
@@ -99,31 +99,44 @@ func newCheckpointState(beaconConfig *clparams.BeaconChainConfig, anchorPublicKe
 	return c
 }
 
-// getAttestingIndicies retrieves the beacon committee.
-func (c *checkpointState) getAttestingIndicies(attestation *solid.AttestationData, aggregationBits []byte) ([]uint64, error) {
+// getAttestingIndicies retrieves the attesting indicies from the beacon committees of the attestation.
+func (c *checkpointState) getAttestingIndicies(attestation *solid.Attestation) ([]uint64, error) {
+	data := attestation.AttestantionData()
+	aggregationBits := attestation.AggregationBits()
 	// First get beacon committee
-	slot := attestation.Slot()
+	slot := data.Slot()
 	epoch := c.epochAtSlot(slot)
 	// Compute shuffled indicies
 
 	lenIndicies := uint64(len(c.shuffledSet))
 	committeesPerSlot := c.committeeCount(epoch, lenIndicies)
 	count := committeesPerSlot * c.beaconConfig.SlotsPerEpoch
-	index := (slot%c.beaconConfig.SlotsPerEpoch)*committeesPerSlot + attestation.CommitteeIndex()
-	start := (lenIndicies * index) / count
-	end := (lenIndicies * (index + 1)) / count
-	committee := c.shuffledSet[start:end]
+	// Electra attestations span the committees selected by their committee bits.
+	committeeIndices := []uint64{data.CommitteeIndex()}
+	if attestation.IsElectra() {
+		committeeIndices = attestation.CommitteeIndices()
+	}
 
 	attestingIndices := []uint64{}
-	for i, member := range committee {
-		bitIndex := i % 8
-		sliceIndex := i / 8
-		if sliceIndex >= len(aggregationBits) {
-			return nil, fmt.Errorf("GetAttestingIndicies: committee is too big")
+	committeeOffset := 0
+	for _, committeeIndex := range committeeIndices {
+		if committeeIndex >= committeesPerSlot {
+			return nil, fmt.Errorf("GetAttestingIndicies: committee index out of range")
 		}
-		if (aggregationBits[sliceIndex] & (1 << bitIndex)) > 0 {
-			attestingIndices = append(attestingIndices, member)
+		index := (slot%c.beaconConfig.SlotsPerEpoch)*committeesPerSlot + committeeIndex
+		start := (lenIndicies * index) / count
+		end := (lenIndicies * (index + 1)) / count
+		committee := c.shuffledSet[start:end]
+		for i, member := range committee {
+			bitIndex := committeeOffset + i
+			if bitIndex/8 >= len(aggregationBits) {
+				return nil, fmt.Errorf("GetAttestingIndicies: committee is too big")
+			}
+			if (aggregationBits[bitIndex/8] & (1 << (bitIndex % 8))) > 0 {
+				attestingIndices = append(attestingIndices, member)
+			}
 		}
+		committeeOffset += len(committee)
 	}
 	return attestingIndices, nil
 }
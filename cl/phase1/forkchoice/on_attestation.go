@@ -78,7 +78,6 @@ func (f *ForkChoiceStore) verifyAttestationWithCheckpointState(
 	attestation *solid.Attestation,
 	fromBlock bool,
 ) (attestationIndicies []uint64, err error) {
-	data := attestation.AttestantionData()
 	targetState, err := f.getCheckpointState(target)
 	if err != nil {
 		return nil, err
@@ -88,10 +87,7 @@ func (f *ForkChoiceStore) verifyAttestationWithCheckpointState(
 		return nil, fmt.Errorf("target state does not exist")
 	}
 	// Now we need to find the attesting indicies.
-	attestationIndicies, err = targetState.getAttestingIndicies(
-		&data,
-		attestation.AggregationBits(),
-	)
+	attestationIndicies, err = targetState.getAttestingIndicies(attestation)
 	if err != nil {
 		return nil, err
 	}
@@ -117,12 +113,7 @@ func (f *ForkChoiceStore) verifyAttestationWithState(
 	attestation *solid.Attestation,
 	fromBlock bool,
 ) (attestationIndicies []uint64, err error) {
-	data := attestation.AttestantionData()
-	if err != nil {
-		return nil, err
-	}
-
-	attestationIndicies, err = s.GetAttestingIndicies(data, attestation.AggregationBits(), true)
+	attestationIndicies, err = s.GetAttestingIndiciesForAttestation(attestation, true)
 	if err != nil {
 		return nil, err
 	}
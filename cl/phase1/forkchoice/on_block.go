@@ -26,7 +26,7 @@ const foreseenProposers = 16
 
 var ErrEIP4844DataNotAvailable = fmt.Errorf("EIP-4844 blob data is not available")
 
-func verifyKzgCommitmentsAgainstTransactions(cfg *clparams.BeaconChainConfig, block *cltypes.Eth1Block, kzgCommitments *solid.ListSSZ[*cltypes.KZGCommitment]) error {
+func verifyKzgCommitmentsAgainstTransactions(cfg *clparams.BeaconChainConfig, version clparams.StateVersion, block *cltypes.Eth1Block, kzgCommitments *solid.ListSSZ[*cltypes.KZGCommitment]) error {
 	expectedBlobHashes := []common.Hash{}
 	transactions, err := types.DecodeTransactions(block.Transactions.UnderlyngReference())
 	if err != nil {
@@ -45,7 +45,9 @@ func verifyKzgCommitmentsAgainstTransactions(cfg *clparams.BeaconChainConfig, bl
 		return err
 	}
 
-	return ethutils.ValidateBlobs(block.BlobGasUsed, cfg.MaxBlobGasPerBlock, cfg.MaxBlobsPerBlock, expectedBlobHashes, &transactions)
+	maxBlobs := cfg.MaxBlobsPerBlockByVersion(version)
+	maxBlobGas := maxBlobs * (cfg.MaxBlobGasPerBlock / cfg.MaxBlobsPerBlock)
+	return ethutils.ValidateBlobs(block.BlobGasUsed, maxBlobGas, maxBlobs, expectedBlobHashes, &transactions)
 }
 
 func (f *ForkChoiceStore) OnBlock(ctx context.Context, block *cltypes.SignedBeaconBlock, newPayload, fullValidation, checkDataAvaiability bool) error {
@@ -93,12 +95,18 @@ func (f *ForkChoiceStore) OnBlock(ctx context.Context, block *cltypes.SignedBeac
 	startEngine := time.Now()
 	if newPayload && f.engine != nil {
 		if block.Version() >= clparams.DenebVersion {
-			if err := verifyKzgCommitmentsAgainstTransactions(f.beaconCfg, block.Block.Body.ExecutionPayload, block.Block.Body.BlobKzgCommitments); err != nil {
+			if err := verifyKzgCommitmentsAgainstTransactions(f.beaconCfg, block.Version(), block.Block.Body.ExecutionPayload, block.Block.Body.BlobKzgCommitments); err != nil {
 				return fmt.Errorf("OnBlock: failed to process kzg commitments: %v", err)
 			}
 		}
 
-		if invalidBlock, err = f.engine.NewPayload(ctx, block.Block.Body.ExecutionPayload, &block.Block.ParentRoot, versionedHashes); err != nil {
+		var executionRequests [][]byte
+		if block.Version() >= clparams.ElectraVersion {
+			if executionRequests, err = block.Block.Body.ExecutionRequests.EngineRequests(); err != nil {
+				return fmt.Errorf("OnBlock: failed to encode execution requests: %v", err)
+			}
+		}
+		if invalidBlock, err = f.engine.NewPayload(ctx, block.Block.Body.ExecutionPayload, &block.Block.ParentRoot, versionedHashes, executionRequests); err != nil {
 			if invalidBlock {
 				f.forkGraph.MarkHeaderAsInvalid(blockRoot)
 			}
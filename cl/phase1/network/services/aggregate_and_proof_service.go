@@ -70,6 +70,14 @@ func (a *aggregateAndProofServiceImpl) ProcessMessage(
 	target := aggregateAndProof.Message.Aggregate.AttestantionData().Target()
 	slot := aggregateAndProof.Message.Aggregate.AttestantionData().Slot()
 	committeeIndex := aggregateAndProof.Message.Aggregate.AttestantionData().CommitteeIndex()
+	if aggregateAndProof.Message.Aggregate.IsElectra() {
+		// [REJECT] aggregate.data.index == 0 and exactly one committee bit is set, which selects the committee.
+		committeeIndices := aggregateAndProof.Message.Aggregate.CommitteeIndices()
+		if committeeIndex != 0 || len(committeeIndices) != 1 {
+			return fmt.Errorf("invalid committee bits in aggregate and proof")
+		}
+		committeeIndex = committeeIndices[0]
+	}
 
 	if aggregateData.Slot() > headState.Slot() {
 		a.scheduleAggregateForLaterProcessing(aggregateAndProof)
@@ -97,7 +105,7 @@ func (a *aggregateAndProofServiceImpl) ProcessMessage(
 
 	// [REJECT] The committee index is within the expected range -- i.e. index < get_committee_count_per_slot(state, aggregate.data.target.epoch).
 	committeeCountPerSlot := headState.CommitteeCount(target.Epoch())
-	if aggregateData.CommitteeIndex() >= committeeCountPerSlot {
+	if committeeIndex >= committeeCountPerSlot {
 		return fmt.Errorf("invalid committee index in aggregate and proof")
 	}
 	// [REJECT] The aggregate attestation's epoch matches its target -- i.e. aggregate.data.target.epoch == compute_epoch_at_slot(aggregate.data.slot)
@@ -129,9 +137,8 @@ func (a *aggregateAndProofServiceImpl) ProcessMessage(
 		log.Warn("receveived aggregate and proof from invalid aggregator")
 		return fmt.Errorf("invalid aggregate and proof")
 	}
-	attestingIndicies, err := headState.GetAttestingIndicies(
-		aggregateAndProof.Message.Aggregate.AttestantionData(),
-		aggregateAndProof.Message.Aggregate.AggregationBits(),
+	attestingIndicies, err := headState.GetAttestingIndiciesForAttestation(
+		aggregateAndProof.Message.Aggregate,
 		true,
 	)
 	if err != nil {
@@ -155,11 +162,9 @@ func verifySignaturesOnAggregate(
 	s *state.CachingBeaconState,
 	aggregateAndProof *cltypes.SignedAggregateAndProof,
 ) error {
-	aggregationBits := aggregateAndProof.Message.Aggregate.AggregationBits()
 	// [REJECT] The aggregate attestation has participants -- that is, len(get_attesting_indices(state, aggregate)) >= 1.
-	attestingIndicies, err := s.GetAttestingIndicies(
-		aggregateAndProof.Message.Aggregate.AttestantionData(),
-		aggregationBits,
+	attestingIndicies, err := s.GetAttestingIndiciesForAttestation(
+		aggregateAndProof.Message.Aggregate,
 		true,
 	)
 	if err != nil {
@@ -74,6 +74,14 @@ func (s *attestationService) ProcessMessage(ctx context.Context, subnet *uint64,
 	if headState == nil {
 		return ErrIgnore
 	}
+	if att.IsElectra() {
+		// [REJECT] attestation.data.index == 0 and exactly one committee bit is set, which selects the committee.
+		committeeIndices := att.CommitteeIndices()
+		if committeeIndex != 0 || len(committeeIndices) != 1 {
+			return fmt.Errorf("invalid committee bits")
+		}
+		committeeIndex = committeeIndices[0]
+	}
 
 	// [REJECT] The committee index is within the expected range
 	committeeCount := computeCommitteeCountPerSlot(headState, slot, s.beaconCfg.SlotsPerEpoch)
@@ -67,11 +67,15 @@ func (b *blobSidecarService) ProcessMessage(ctx context.Context, subnetId *uint6
 		return ErrIgnore
 	}
 
+	if msg.SignedBlockHeader == nil || msg.SignedBlockHeader.Header == nil {
+		return fmt.Errorf("blob sidecar without block header")
+	}
 	// [REJECT] The sidecar's index is consistent with MAX_BLOBS_PER_BLOCK -- i.e. blob_sidecar.index < MAX_BLOBS_PER_BLOCK.
-	if msg.Index >= b.beaconCfg.MaxBlobsPerBlock {
+	version := b.beaconCfg.GetCurrentStateVersion(msg.SignedBlockHeader.Header.Slot / b.beaconCfg.SlotsPerEpoch)
+	if msg.Index >= b.beaconCfg.MaxBlobsPerBlockByVersion(version) {
 		return fmt.Errorf("blob index out of range")
 	}
-	sidecarSubnetIndex := msg.Index % b.beaconCfg.MaxBlobsPerBlock
+	sidecarSubnetIndex := msg.Index % b.beaconCfg.BlobSidecarSubnetCountByVersion(version)
 	if sidecarSubnetIndex != *subnetId {
 		return ErrBlobIndexOutOfRange
 	}
@@ -221,7 +221,8 @@ func (b *BeaconRpcP2P) SendBlobsSidecarByRangerReq(ctx context.Context, start, c
 	}
 
 	data := libcommon.CopyBytes(buffer.Bytes())
-	return b.sendBlobsSidecar(ctx, communication.BlobSidecarByRangeProtocolV1, data, count*b.beaconConfig.MaxBlobsPerBlock)
+	version := b.beaconConfig.GetCurrentStateVersion((start + count) / b.beaconConfig.SlotsPerEpoch)
+	return b.sendBlobsSidecar(ctx, communication.BlobSidecarByRangeProtocolV1, data, count*b.beaconConfig.MaxBlobsPerBlockByVersion(version))
 }
 
 // SendBeaconBlocksByRangeReq retrieves blocks range from beacon chain.
@@ -7,10 +7,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/gossip"
 	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
 	"github.com/ledgerwatch/erigon/cl/sentinel"
+	"github.com/ledgerwatch/erigon/cl/utils"
 	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
 	"github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
@@ -92,7 +94,11 @@ func createSentinel(
 		gossipTopics,
 		generateSubnetsTopics(
 			gossip.TopicNamePrefixBlobSidecar,
-			int(cfg.BeaconConfig.MaxBlobsPerBlock),
+			// Subscribe to the largest subnet count so that the electra subnets are ready at the fork.
+			int(utils.Max64(
+				cfg.BeaconConfig.BlobSidecarSubnetCountByVersion(clparams.DenebVersion),
+				cfg.BeaconConfig.BlobSidecarSubnetCountByVersion(clparams.ElectraVersion),
+			)),
 		)...)
 	gossipTopics = append(
 		gossipTopics,
@@ -32,7 +32,9 @@ func init() {
 		With("rewards_and_penalties", rewardsAndPenaltiesTest).
 		With("slashings", slashingsTest).
 		With("slashings_reset", slashingsResetTest).
-		With("participation_record_updates", participationRecordUpdatesTest)
+		With("participation_record_updates", participationRecordUpdatesTest).
+		With("pending_deposits", pendingDepositsTest).
+		With("pending_consolidations", pendingConsolidationsTest)
 	TestFormats.Add("finality").
 		With("finality", FinalityFinality)
 	TestFormats.Add("fork_choice").
@@ -60,7 +62,10 @@ func init() {
 		WithFn("voluntary_exit", operationVoluntaryExitHandler).
 		WithFn("sync_aggregate", operationSyncAggregateHandler).
 		WithFn("withdrawals", operationWithdrawalHandler).
-		WithFn("bls_to_execution-change", operationSignedBlsChangeHandler)
+		WithFn("bls_to_execution-change", operationSignedBlsChangeHandler).
+		WithFn("deposit_request", operationDepositRequestHandler).
+		WithFn("withdrawal_request", operationWithdrawalRequestHandler).
+		WithFn("consolidation_request", operationConsolidationRequestHandler)
 	TestFormats.Add("random").
 		With("random", SanityBlocks)
 	TestFormats.Add("rewards").
@@ -113,6 +113,14 @@ var slashingsResetTest = NewEpochProcessing(func(s abstract.BeaconState) error {
 	return nil
 })
 
+var pendingDepositsTest = NewEpochProcessing(func(s abstract.BeaconState) error {
+	return statechange.ProcessPendingDeposits(s)
+})
+
+var pendingConsolidationsTest = NewEpochProcessing(func(s abstract.BeaconState) error {
+	return statechange.ProcessPendingConsolidations(s)
+})
+
 var recordsResetTest = NewEpochProcessing(func(s abstract.BeaconState) error {
 	statechange.ProcessParticipationRecordUpdates(s)
 	return nil
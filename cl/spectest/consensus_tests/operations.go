@@ -25,6 +25,10 @@ const (
 	voluntaryExitFileName    = "voluntary_exit.ssz_snappy"
 	executionPayloadFileName = "execution_payload.ssz_snappy"
 	addressChangeFileName    = "address_change.ssz_snappy"
+
+	depositRequestFileName       = "deposit_request.ssz_snappy"
+	withdrawalRequestFileName    = "withdrawal_request.ssz_snappy"
+	consolidationRequestFileName = "consolidation_request.ssz_snappy"
 )
 
 func operationAttestationHandler(t *testing.T, root fs.FS, c spectest.TestCase) error {
@@ -297,3 +301,93 @@ func operationSignedBlsChangeHandler(t *testing.T, root fs.FS, c spectest.TestCa
 	assert.EqualValues(t, haveRoot, expectedRoot)
 	return nil
 }
+
+func operationDepositRequestHandler(t *testing.T, root fs.FS, c spectest.TestCase) error {
+	preState, err := spectest.ReadBeaconState(root, c.Version(), "pre.ssz_snappy")
+	require.NoError(t, err)
+	postState, err := spectest.ReadBeaconState(root, c.Version(), "post.ssz_snappy")
+	expectedError := os.IsNotExist(err)
+	if err != nil && !expectedError {
+		return err
+	}
+	depositRequest := &cltypes.DepositRequest{}
+	if err := spectest.ReadSszOld(root, depositRequest, c.Version(), depositRequestFileName); err != nil {
+		return err
+	}
+	if err := c.Machine.ProcessDepositRequest(preState, depositRequest); err != nil {
+		if expectedError {
+			return nil
+		}
+		return err
+	}
+	if expectedError {
+		return fmt.Errorf("expected error")
+	}
+	haveRoot, err := preState.HashSSZ()
+	require.NoError(t, err)
+	expectedRoot, err := postState.HashSSZ()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, haveRoot, expectedRoot)
+	return nil
+}
+
+func operationWithdrawalRequestHandler(t *testing.T, root fs.FS, c spectest.TestCase) error {
+	preState, err := spectest.ReadBeaconState(root, c.Version(), "pre.ssz_snappy")
+	require.NoError(t, err)
+	postState, err := spectest.ReadBeaconState(root, c.Version(), "post.ssz_snappy")
+	expectedError := os.IsNotExist(err)
+	if err != nil && !expectedError {
+		return err
+	}
+	withdrawalRequest := &cltypes.WithdrawalRequest{}
+	if err := spectest.ReadSszOld(root, withdrawalRequest, c.Version(), withdrawalRequestFileName); err != nil {
+		return err
+	}
+	if err := c.Machine.ProcessWithdrawalRequest(preState, withdrawalRequest); err != nil {
+		if expectedError {
+			return nil
+		}
+		return err
+	}
+	if expectedError {
+		return fmt.Errorf("expected error")
+	}
+	haveRoot, err := preState.HashSSZ()
+	require.NoError(t, err)
+	expectedRoot, err := postState.HashSSZ()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, haveRoot, expectedRoot)
+	return nil
+}
+
+func operationConsolidationRequestHandler(t *testing.T, root fs.FS, c spectest.TestCase) error {
+	preState, err := spectest.ReadBeaconState(root, c.Version(), "pre.ssz_snappy")
+	require.NoError(t, err)
+	postState, err := spectest.ReadBeaconState(root, c.Version(), "post.ssz_snappy")
+	expectedError := os.IsNotExist(err)
+	if err != nil && !expectedError {
+		return err
+	}
+	consolidationRequest := &cltypes.ConsolidationRequest{}
+	if err := spectest.ReadSszOld(root, consolidationRequest, c.Version(), consolidationRequestFileName); err != nil {
+		return err
+	}
+	if err := c.Machine.ProcessConsolidationRequest(preState, consolidationRequest); err != nil {
+		if expectedError {
+			return nil
+		}
+		return err
+	}
+	if expectedError {
+		return fmt.Errorf("expected error")
+	}
+	haveRoot, err := preState.HashSSZ()
+	require.NoError(t, err)
+	expectedRoot, err := postState.HashSSZ()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, haveRoot, expectedRoot)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 	"slices"
 	"time"
 
@@ -200,6 +201,15 @@ func (I *impl) ProcessDeposit(s abstract.BeaconState, deposit *cltypes.Deposit)
 			log.Debug("Validator BLS verification failed", "valid", valid, "err", err)
 			return nil
 		}
+		if s.Version() >= clparams.ElectraVersion {
+			// Electra registers the validator with no balance and queues the deposit.
+			s.AddValidator(state.ElectraValidatorFromDeposit(s.BeaconConfig(), publicKey, deposit.Data.WithdrawalCredentials, 0), 0)
+			s.AddCurrentEpochParticipationFlags(cltypes.ParticipationFlags(0))
+			s.AddPreviousEpochParticipationFlags(cltypes.ParticipationFlags(0))
+			s.AddInactivityScore(0)
+			appendPendingDeposit(s, deposit.Data, s.BeaconConfig().GenesisSlot)
+			return nil
+		}
 		// Append validator
 		s.AddValidator(state.ValidatorFromDeposit(s.BeaconConfig(), deposit), amount)
 		// Altair forward
@@ -210,10 +220,24 @@ func (I *impl) ProcessDeposit(s abstract.BeaconState, deposit *cltypes.Deposit)
 		}
 		return nil
 	}
+	if s.Version() >= clparams.ElectraVersion {
+		appendPendingDeposit(s, deposit.Data, s.BeaconConfig().GenesisSlot)
+		return nil
+	}
 	// Increase the balance if exists already
 	return state.IncreaseBalance(s, validatorIndex, amount)
 }
 
+func appendPendingDeposit(s abstract.BeaconState, data *cltypes.DepositData, slot uint64) {
+	s.AppendPendingDeposit(&cltypes.PendingDeposit{
+		PubKey:                data.PubKey,
+		WithdrawalCredentials: data.WithdrawalCredentials,
+		Amount:                data.Amount,
+		Signature:             data.Signature,
+		Slot:                  slot,
+	})
+}
+
 func IsVoluntaryExitApplicable(s abstract.BeaconState, voluntaryExit *cltypes.VoluntaryExit) error {
 	currentEpoch := state.Epoch(s)
 	validator, err := s.ValidatorForValidatorIndex(int(voluntaryExit.ValidatorIndex))
@@ -234,6 +258,9 @@ func IsVoluntaryExitApplicable(s abstract.BeaconState, voluntaryExit *cltypes.Vo
 	if currentEpoch < validator.ActivationEpoch()+s.BeaconConfig().ShardCommitteePeriod {
 		return errors.New("ProcessVoluntaryExit: exit is happening too fast")
 	}
+	if s.Version() >= clparams.ElectraVersion && state.GetPendingBalanceToWithdraw(s, voluntaryExit.ValidatorIndex) != 0 {
+		return errors.New("ProcessVoluntaryExit: validator has pending partial withdrawals")
+	}
 	return nil
 }
 
@@ -293,8 +320,8 @@ func (I *impl) ProcessWithdrawals(
 	numValidators := uint64(s.ValidatorLength())
 
 	// Check if full validation is required and verify expected withdrawals.
+	expectedWithdrawals, processedPartialWithdrawals := state.ExpectedWithdrawalsAndPartials(s, state.Epoch(s))
 	if I.FullValidation {
-		expectedWithdrawals := state.ExpectedWithdrawals(s, state.Epoch(s))
 		if len(expectedWithdrawals) != withdrawals.Len() {
 			return fmt.Errorf(
 				"ProcessWithdrawals: expected %d withdrawals, but got %d",
@@ -321,6 +348,13 @@ func (I *impl) ProcessWithdrawals(
 		return err
 	}
 
+	// Drop the pending partial withdrawals consumed by this payload.
+	if processedPartialWithdrawals > 0 {
+		pendingPartialWithdrawals := s.PendingPartialWithdrawals()
+		pendingPartialWithdrawals.Cut(int(processedPartialWithdrawals))
+		s.SetPendingPartialWithdrawals(pendingPartialWithdrawals)
+	}
+
 	// Update next withdrawal index based on number of withdrawals.
 	if withdrawals.Len() > 0 {
 		lastWithdrawalIndex := withdrawals.Get(withdrawals.Len() - 1).Index
@@ -518,6 +552,180 @@ func (I *impl) ProcessBlsToExecutionChange(
 	return nil
 }
 
+// ProcessDepositRequest queues a deposit surfaced by the execution layer (EIP-6110).
+func (I *impl) ProcessDepositRequest(s abstract.BeaconState, depositRequest *cltypes.DepositRequest) error {
+	// Set the deposit requests start index if not set yet.
+	if s.DepositRequestsStartIndex() == math.MaxUint64 {
+		s.SetDepositRequestsStartIndex(depositRequest.Index)
+	}
+	s.AppendPendingDeposit(&cltypes.PendingDeposit{
+		PubKey:                depositRequest.PubKey,
+		WithdrawalCredentials: depositRequest.WithdrawalCredentials,
+		Amount:                depositRequest.Amount,
+		Signature:             depositRequest.Signature,
+		Slot:                  s.Slot(),
+	})
+	return nil
+}
+
+// ProcessWithdrawalRequest processes an execution layer triggered exit or partial withdrawal (EIP-7002).
+// Invalid requests are ignored rather than failing the block.
+func (I *impl) ProcessWithdrawalRequest(s abstract.BeaconState, withdrawalRequest *cltypes.WithdrawalRequest) error {
+	beaconConfig := s.BeaconConfig()
+	amount := withdrawalRequest.Amount
+	isFullExitRequest := amount == 0
+	// If the partial withdrawal queue is full, only full exits are processed.
+	if uint64(s.PendingPartialWithdrawals().Len()) == beaconConfig.PendingPartialWithdrawalsLimit && !isFullExitRequest {
+		return nil
+	}
+	validatorIndex, has := s.ValidatorIndexByPubkey(withdrawalRequest.ValidatorPubKey)
+	if !has {
+		return nil
+	}
+	validator, err := s.ValidatorForValidatorIndex(int(validatorIndex))
+	if err != nil {
+		return err
+	}
+	// Verify withdrawal credentials.
+	withdrawalCredentials := validator.WithdrawalCredentials()
+	if !state.HasExecutionWithdrawalCredential(beaconConfig, validator) ||
+		!bytes.Equal(withdrawalCredentials[12:], withdrawalRequest.SourceAddress[:]) {
+		return nil
+	}
+	currentEpoch := state.Epoch(s)
+	if !validator.Active(currentEpoch) || validator.ExitEpoch() != beaconConfig.FarFutureEpoch {
+		return nil
+	}
+	// Verify the validator has been active long enough.
+	if currentEpoch < validator.ActivationEpoch()+beaconConfig.ShardCommitteePeriod {
+		return nil
+	}
+
+	pendingBalanceToWithdraw := state.GetPendingBalanceToWithdraw(s, validatorIndex)
+	if isFullExitRequest {
+		// Only exit the validator if it has no pending withdrawals in the queue.
+		if pendingBalanceToWithdraw == 0 {
+			return s.InitiateValidatorExit(validatorIndex)
+		}
+		return nil
+	}
+	balance, err := s.ValidatorBalance(int(validatorIndex))
+	if err != nil {
+		return err
+	}
+	hasSufficientEffectiveBalance := validator.EffectiveBalance() >= beaconConfig.MinActivationBalance
+	hasExcessBalance := balance > beaconConfig.MinActivationBalance+pendingBalanceToWithdraw
+	// Only allow partial withdrawals with compounding withdrawal credentials.
+	if state.HasCompoundingWithdrawalCredential(beaconConfig, validator) && hasSufficientEffectiveBalance && hasExcessBalance {
+		toWithdraw := utils.Min64(balance-beaconConfig.MinActivationBalance-pendingBalanceToWithdraw, amount)
+		exitQueueEpoch := s.ComputeExitEpochAndUpdateChurn(toWithdraw)
+		s.AppendPendingPartialWithdrawal(&cltypes.PendingPartialWithdrawal{
+			Index:             validatorIndex,
+			Amount:            toWithdraw,
+			WithdrawableEpoch: exitQueueEpoch + beaconConfig.MinValidatorWithdrawabilityDelay,
+		})
+	}
+	return nil
+}
+
+// isValidSwitchToCompoundingRequest checks whether a consolidation request asks to switch its source to compounding credentials.
+func isValidSwitchToCompoundingRequest(s abstract.BeaconState, consolidationRequest *cltypes.ConsolidationRequest) bool {
+	// Switching to compounding requires the source and the target to be equal.
+	if consolidationRequest.SourcePubKey != consolidationRequest.TargetPubKey {
+		return false
+	}
+	sourceIndex, has := s.ValidatorIndexByPubkey(consolidationRequest.SourcePubKey)
+	if !has {
+		return false
+	}
+	sourceValidator, err := s.ValidatorForValidatorIndex(int(sourceIndex))
+	if err != nil {
+		return false
+	}
+	// Verify the request has been authorized.
+	withdrawalCredentials := sourceValidator.WithdrawalCredentials()
+	if !bytes.Equal(withdrawalCredentials[12:], consolidationRequest.SourceAddress[:]) {
+		return false
+	}
+	return state.HasEth1WithdrawalCredential(s.BeaconConfig(), sourceValidator) &&
+		sourceValidator.Active(state.Epoch(s)) &&
+		sourceValidator.ExitEpoch() == s.BeaconConfig().FarFutureEpoch
+}
+
+// ProcessConsolidationRequest processes an execution layer triggered consolidation (EIP-7251).
+// Invalid requests are ignored rather than failing the block.
+func (I *impl) ProcessConsolidationRequest(s abstract.BeaconState, consolidationRequest *cltypes.ConsolidationRequest) error {
+	beaconConfig := s.BeaconConfig()
+	if isValidSwitchToCompoundingRequest(s, consolidationRequest) {
+		sourceIndex, _ := s.ValidatorIndexByPubkey(consolidationRequest.SourcePubKey)
+		return state.SwitchToCompoundingValidator(s, sourceIndex)
+	}
+	// Verify that source != target, so a consolidation cannot be used as an exit.
+	if consolidationRequest.SourcePubKey == consolidationRequest.TargetPubKey {
+		return nil
+	}
+	// If the pending consolidations queue is full, consolidation requests are ignored.
+	if uint64(s.PendingConsolidations().Len()) == beaconConfig.PendingConsolidationsLimit {
+		return nil
+	}
+	// If there is too little available consolidation churn limit, consolidation requests are ignored.
+	if s.GetConsolidationChurnLimit() <= beaconConfig.MinActivationBalance {
+		return nil
+	}
+	sourceIndex, has := s.ValidatorIndexByPubkey(consolidationRequest.SourcePubKey)
+	if !has {
+		return nil
+	}
+	targetIndex, has := s.ValidatorIndexByPubkey(consolidationRequest.TargetPubKey)
+	if !has {
+		return nil
+	}
+	sourceValidator, err := s.ValidatorForValidatorIndex(int(sourceIndex))
+	if err != nil {
+		return err
+	}
+	targetValidator, err := s.ValidatorForValidatorIndex(int(targetIndex))
+	if err != nil {
+		return err
+	}
+	// Verify source withdrawal credentials.
+	withdrawalCredentials := sourceValidator.WithdrawalCredentials()
+	if !state.HasExecutionWithdrawalCredential(beaconConfig, sourceValidator) ||
+		!bytes.Equal(withdrawalCredentials[12:], consolidationRequest.SourceAddress[:]) {
+		return nil
+	}
+	// Verify that the target has compounding withdrawal credentials.
+	if !state.HasCompoundingWithdrawalCredential(beaconConfig, targetValidator) {
+		return nil
+	}
+	// Verify the source and the target are active and not exiting.
+	currentEpoch := state.Epoch(s)
+	if !sourceValidator.Active(currentEpoch) || !targetValidator.Active(currentEpoch) {
+		return nil
+	}
+	if sourceValidator.ExitEpoch() != beaconConfig.FarFutureEpoch || targetValidator.ExitEpoch() != beaconConfig.FarFutureEpoch {
+		return nil
+	}
+	// Verify the source has been active long enough and has no pending withdrawals in the queue.
+	if currentEpoch < sourceValidator.ActivationEpoch()+beaconConfig.ShardCommitteePeriod {
+		return nil
+	}
+	if state.GetPendingBalanceToWithdraw(s, sourceIndex) > 0 {
+		return nil
+	}
+	// Initiate the source validator exit and append the pending consolidation.
+	exitEpoch := s.ComputeConsolidationEpochAndUpdateChurn(sourceValidator.EffectiveBalance())
+	s.SetExitEpochForValidatorAtIndex(int(sourceIndex), exitEpoch)
+	if err := s.SetWithdrawableEpochForValidatorAtIndex(int(sourceIndex), exitEpoch+beaconConfig.MinValidatorWithdrawabilityDelay); err != nil {
+		return err
+	}
+	s.AppendPendingConsolidation(&cltypes.PendingConsolidation{
+		SourceIndex: sourceIndex,
+		TargetIndex: targetIndex,
+	})
+	return nil
+}
+
 func (I *impl) ProcessAttestations(
 	s abstract.BeaconState,
 	attestations *solid.ListSSZ[*solid.Attestation],
@@ -581,7 +789,7 @@ func (I *impl) processAttestationPostAltair(
 
 	c = h.Tag("step", "get_attesting_indices")
 
-	attestingIndicies, err := s.GetAttestingIndicies(data, attestation.AggregationBits(), true)
+	attestingIndicies, err := s.GetAttestingIndiciesForAttestation(attestation, true)
 	if err != nil {
 		return nil, err
 	}
@@ -774,6 +982,19 @@ func IsAttestationApplicable(s abstract.BeaconState, attestation *solid.Attestat
 		data.Slot()+beaconConfig.MinAttestationInclusionDelay > stateSlot {
 		return errors.New("ProcessAttestation: attestation slot not in range")
 	}
+	if s.Version() >= clparams.ElectraVersion {
+		if !attestation.IsElectra() {
+			return errors.New("ProcessAttestation: attestation is missing committee bits")
+		}
+		// Committees are selected through the committee bits, checked when computing the attesting indicies.
+		if data.CommitteeIndex() != 0 {
+			return errors.New("ProcessAttestation: attestation committee index must be zero")
+		}
+		return nil
+	}
+	if attestation.IsElectra() {
+		return errors.New("ProcessAttestation: unexpected committee bits before electra")
+	}
 	if data.CommitteeIndex() >= s.CommitteeCount(data.Target().Epoch()) {
 		return errors.New("ProcessAttestation: attester index out of range")
 	}
@@ -1021,6 +1242,11 @@ func (I *impl) ProcessSlots(s abstract.BeaconState, slot uint64) error {
 				return err
 			}
 		}
+		if state.Epoch(s) == beaconConfig.ElectraForkEpoch {
+			if err := s.UpgradeToElectra(); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
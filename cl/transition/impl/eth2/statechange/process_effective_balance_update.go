@@ -3,6 +3,7 @@ package statechange
 import (
 	"github.com/ledgerwatch/erigon/cl/abstract"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	state2 "github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/utils"
 )
 
@@ -24,7 +25,8 @@ func ProcessEffectiveBalanceUpdates(state abstract.BeaconState) error {
 		eb := validator.EffectiveBalance()
 		if balance+downwardThreshold < eb || eb+upwardThreshold < balance {
 			// Set new effective balance
-			effectiveBalance := utils.Min64(balance-(balance%beaconConfig.EffectiveBalanceIncrement), beaconConfig.MaxEffectiveBalance)
+			maxEffectiveBalance := state2.GetMaxEffectiveBalance(beaconConfig, state.Version(), validator)
+			effectiveBalance := utils.Min64(balance-(balance%beaconConfig.EffectiveBalanceIncrement), maxEffectiveBalance)
 			state.SetEffectiveBalanceForValidatorAtIndex(index, effectiveBalance)
 		}
 		return true
@@ -59,6 +59,14 @@ func ProcessEpoch(s abstract.BeaconState) error {
 	}
 	// fmt.Println("ProcessSlashings", time.Since(start))
 	ProcessEth1DataReset(s)
+	if s.Version() >= clparams.ElectraVersion {
+		if err := ProcessPendingDeposits(s); err != nil {
+			return err
+		}
+		if err := ProcessPendingConsolidations(s); err != nil {
+			return err
+		}
+	}
 	// start = time.Now()
 	if err := ProcessEffectiveBalanceUpdates(s); err != nil {
 		return err
@@ -0,0 +1,152 @@
+package statechange
+
+import (
+	"github.com/ledgerwatch/erigon/cl/abstract"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+// ProcessPendingDeposits applies the queued deposits within the activation churn. Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-process_pending_deposits
+func ProcessPendingDeposits(s abstract.BeaconState) error {
+	beaconConfig := s.BeaconConfig()
+	nextEpoch := state.Epoch(s) + 1
+	availableForProcessing := s.DepositBalanceToConsume() + s.GetActivationExitChurnLimit()
+	finalizedSlot := s.FinalizedCheckpoint().Epoch() * beaconConfig.SlotsPerEpoch
+
+	var (
+		processedAmount     uint64
+		nextDepositIndex    int
+		isChurnLimitReached bool
+		depositsToPostpone  []*cltypes.PendingDeposit
+		err                 error
+	)
+	pendingDeposits := s.PendingDeposits()
+	pendingDeposits.Range(func(_ int, deposit *cltypes.PendingDeposit, _ int) bool {
+		// Do not process deposit requests if the eth1 bridge deposits are not yet applied.
+		if deposit.Slot > beaconConfig.GenesisSlot && s.Eth1DepositIndex() < s.DepositRequestsStartIndex() {
+			return false
+		}
+		// Check if the deposit has been finalized, otherwise stop processing.
+		if deposit.Slot > finalizedSlot {
+			return false
+		}
+		if uint64(nextDepositIndex) >= beaconConfig.MaxPendingDepositsPerEpoch {
+			return false
+		}
+		var isValidatorExited, isValidatorWithdrawn bool
+		if validatorIndex, has := s.ValidatorIndexByPubkey(deposit.PubKey); has {
+			validator, err2 := s.ValidatorForValidatorIndex(int(validatorIndex))
+			if err2 != nil {
+				err = err2
+				return false
+			}
+			isValidatorExited = validator.ExitEpoch() < beaconConfig.FarFutureEpoch
+			isValidatorWithdrawn = validator.WithdrawableEpoch() < nextEpoch
+		}
+		switch {
+		case isValidatorWithdrawn:
+			// The deposited balance will never become active, so it does not consume churn.
+			if err = applyPendingDeposit(s, deposit); err != nil {
+				return false
+			}
+		case isValidatorExited:
+			// The validator is exiting, postpone the deposit until after its withdrawable epoch.
+			depositsToPostpone = append(depositsToPostpone, deposit)
+		default:
+			// Check if the deposit fits in the churn, otherwise stop processing for this epoch.
+			isChurnLimitReached = processedAmount+deposit.Amount > availableForProcessing
+			if isChurnLimitReached {
+				return false
+			}
+			processedAmount += deposit.Amount
+			if err = applyPendingDeposit(s, deposit); err != nil {
+				return false
+			}
+		}
+		nextDepositIndex++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	pendingDeposits.Cut(nextDepositIndex)
+	for _, deposit := range depositsToPostpone {
+		pendingDeposits.Append(deposit)
+	}
+	s.SetPendingDeposits(pendingDeposits)
+	// Accumulate churn only if the churn limit has been hit.
+	if isChurnLimitReached {
+		s.SetDepositBalanceToConsume(availableForProcessing - processedAmount)
+	} else {
+		s.SetDepositBalanceToConsume(0)
+	}
+	return nil
+}
+
+// applyPendingDeposit credits a queued deposit, registering the validator if it is not known yet.
+func applyPendingDeposit(s abstract.BeaconState, deposit *cltypes.PendingDeposit) error {
+	if validatorIndex, has := s.ValidatorIndexByPubkey(deposit.PubKey); has {
+		return state.IncreaseBalance(s, validatorIndex, deposit.Amount)
+	}
+	// Verify the deposit signature (proof of possession), which is not checked by the deposit contract.
+	valid, err := state.IsValidDepositSignature(s.BeaconConfig(), &cltypes.DepositData{
+		PubKey:                deposit.PubKey,
+		WithdrawalCredentials: deposit.WithdrawalCredentials,
+		Amount:                deposit.Amount,
+		Signature:             deposit.Signature,
+	})
+	if err != nil || !valid {
+		return nil
+	}
+	s.AddValidator(state.ElectraValidatorFromDeposit(s.BeaconConfig(), deposit.PubKey, deposit.WithdrawalCredentials, deposit.Amount), deposit.Amount)
+	s.AddCurrentEpochParticipationFlags(cltypes.ParticipationFlags(0))
+	s.AddPreviousEpochParticipationFlags(cltypes.ParticipationFlags(0))
+	s.AddInactivityScore(0)
+	return nil
+}
+
+// ProcessPendingConsolidations moves the balance of withdrawable consolidation sources into their targets. Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-process_pending_consolidations
+func ProcessPendingConsolidations(s abstract.BeaconState) error {
+	nextEpoch := state.Epoch(s) + 1
+	var (
+		nextPendingConsolidation int
+		err                      error
+	)
+	pendingConsolidations := s.PendingConsolidations()
+	pendingConsolidations.Range(func(_ int, consolidation *cltypes.PendingConsolidation, _ int) bool {
+		var sourceValidator solid.Validator
+		if sourceValidator, err = s.ValidatorForValidatorIndex(int(consolidation.SourceIndex)); err != nil {
+			return false
+		}
+		if sourceValidator.Slashed() {
+			nextPendingConsolidation++
+			return true
+		}
+		if sourceValidator.WithdrawableEpoch() > nextEpoch {
+			return false
+		}
+		var sourceBalance uint64
+		if sourceBalance, err = s.ValidatorBalance(int(consolidation.SourceIndex)); err != nil {
+			return false
+		}
+		// Move the active balance to the target, the excess balance stays withdrawable.
+		sourceEffectiveBalance := utils.Min64(sourceBalance, sourceValidator.EffectiveBalance())
+		if err = state.DecreaseBalance(s, consolidation.SourceIndex, sourceEffectiveBalance); err != nil {
+			return false
+		}
+		if err = state.IncreaseBalance(s, consolidation.TargetIndex, sourceEffectiveBalance); err != nil {
+			return false
+		}
+		nextPendingConsolidation++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	pendingConsolidations.Cut(nextPendingConsolidation)
+	s.SetPendingConsolidations(pendingConsolidations)
+	return nil
+}
@@ -20,6 +20,8 @@ func processSlashings(s abstract.BeaconState, slashingMultiplier uint64) error {
 		slashing = totalBalance
 	}
 	beaconConfig := s.BeaconConfig()
+	// Electra computes the penalty per effective balance increment up front to avoid precision loss.
+	penaltyPerEffectiveBalanceIncrement := slashing / (totalBalance / beaconConfig.EffectiveBalanceIncrement)
 	// Apply penalties to validators who have been slashed and reached the withdrawable epoch
 	var err error
 	s.ForEachValidator(func(validator solid.Validator, i, total int) bool {
@@ -28,10 +30,15 @@ func processSlashings(s abstract.BeaconState, slashingMultiplier uint64) error {
 		}
 		// Get the effective balance increment
 		increment := beaconConfig.EffectiveBalanceIncrement
-		// Calculate the penalty numerator by multiplying the validator's effective balance by the total slashing amount
-		penaltyNumerator := validator.EffectiveBalance() / increment * slashing
-		// Calculate the penalty by dividing the penalty numerator by the total balance and multiplying by the increment
-		penalty := penaltyNumerator / totalBalance * increment
+		var penalty uint64
+		if s.Version() >= clparams.ElectraVersion {
+			penalty = penaltyPerEffectiveBalanceIncrement * (validator.EffectiveBalance() / increment)
+		} else {
+			// Calculate the penalty numerator by multiplying the validator's effective balance by the total slashing amount
+			penaltyNumerator := validator.EffectiveBalance() / increment * slashing
+			// Calculate the penalty by dividing the penalty numerator by the total balance and multiplying by the increment
+			penalty = penaltyNumerator / totalBalance * increment
+		}
 		// Decrease the validator's balance by the calculated penalty
 		if err = state.DecreaseBalance(s, uint64(i), penalty); err != nil {
 			return false
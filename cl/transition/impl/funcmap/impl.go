@@ -25,6 +25,9 @@ type Impl struct {
 	FnProcessDeposit              func(s abstract.BeaconState, deposit *cltypes.Deposit) error
 	FnProcessVoluntaryExit        func(s abstract.BeaconState, signedVoluntaryExit *cltypes.SignedVoluntaryExit) error
 	FnProcessBlsToExecutionChange func(state abstract.BeaconState, signedChange *cltypes.SignedBLSToExecutionChange) error
+	FnProcessDepositRequest       func(s abstract.BeaconState, depositRequest *cltypes.DepositRequest) error
+	FnProcessWithdrawalRequest    func(s abstract.BeaconState, withdrawalRequest *cltypes.WithdrawalRequest) error
+	FnProcessConsolidationRequest func(s abstract.BeaconState, consolidationRequest *cltypes.ConsolidationRequest) error
 }
 
 func (i Impl) VerifyBlockSignature(s abstract.BeaconState, block *cltypes.SignedBeaconBlock) error {
@@ -83,6 +86,18 @@ func (i Impl) ProcessBlsToExecutionChange(state abstract.BeaconState, signedChan
 	return i.FnProcessBlsToExecutionChange(state, signedChange)
 }
 
+func (i Impl) ProcessDepositRequest(s abstract.BeaconState, depositRequest *cltypes.DepositRequest) error {
+	return i.FnProcessDepositRequest(s, depositRequest)
+}
+
+func (i Impl) ProcessWithdrawalRequest(s abstract.BeaconState, withdrawalRequest *cltypes.WithdrawalRequest) error {
+	return i.FnProcessWithdrawalRequest(s, withdrawalRequest)
+}
+
+func (i Impl) ProcessConsolidationRequest(s abstract.BeaconState, consolidationRequest *cltypes.ConsolidationRequest) error {
+	return i.FnProcessConsolidationRequest(s, consolidationRequest)
+}
+
 func (i Impl) ProcessSlots(s abstract.BeaconState, slot uint64) error {
 	return i.FnProcessSlots(s, slot)
 }
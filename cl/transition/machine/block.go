@@ -122,11 +122,51 @@ func ProcessOperations(impl BlockOperationProcessor, s abstract.BeaconState, blo
 	}); err != nil {
 		return err
 	}
-	return nil
+	if s.Version() < clparams.ElectraVersion {
+		return nil
+	}
+	return ProcessExecutionRequests(impl, s, blockBody.ExecutionRequests)
+}
+
+// ProcessExecutionRequests processes the execution layer requests of an electra block body.
+func ProcessExecutionRequests(impl BlockOperationProcessor, s abstract.BeaconState, requests *cltypes.ExecutionRequests) error {
+	if requests == nil {
+		return nil
+	}
+	if err := solid.RangeErr[*cltypes.DepositRequest](requests.Deposits, func(index int, request *cltypes.DepositRequest, length int) error {
+		if err := impl.ProcessDepositRequest(s, request); err != nil {
+			return fmt.Errorf("ProcessDepositRequest: %s", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := solid.RangeErr[*cltypes.WithdrawalRequest](requests.Withdrawals, func(index int, request *cltypes.WithdrawalRequest, length int) error {
+		if err := impl.ProcessWithdrawalRequest(s, request); err != nil {
+			return fmt.Errorf("ProcessWithdrawalRequest: %s", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return solid.RangeErr[*cltypes.ConsolidationRequest](requests.Consolidations, func(index int, request *cltypes.ConsolidationRequest, length int) error {
+		if err := impl.ProcessConsolidationRequest(s, request); err != nil {
+			return fmt.Errorf("ProcessConsolidationRequest: %s", err)
+		}
+		return nil
+	})
 }
 
 func maximumDeposits(s abstract.BeaconState) (maxDeposits uint64) {
-	maxDeposits = s.Eth1Data().DepositCount - s.Eth1DepositIndex()
+	depositIndexLimit := s.Eth1Data().DepositCount
+	// Electra stops processing eth1 bridge deposits once deposit requests take over.
+	if s.Version() >= clparams.ElectraVersion && s.DepositRequestsStartIndex() < depositIndexLimit {
+		depositIndexLimit = s.DepositRequestsStartIndex()
+	}
+	if s.Eth1DepositIndex() >= depositIndexLimit {
+		return 0
+	}
+	maxDeposits = depositIndexLimit - s.Eth1DepositIndex()
 	if maxDeposits > s.BeaconConfig().MaxDeposits {
 		maxDeposits = s.BeaconConfig().MaxDeposits
 	}
@@ -66,6 +66,7 @@ func OpenCaplinDatabase(ctx context.Context,
 	engine execution_client.ExecutionEngine,
 	wipeout bool,
 	blobPruneDistance uint64,
+	blobArchive bool,
 ) (kv.RwDB, blob_storage.BlobStorage, error) {
 	dataDirIndexer := path.Join(dbPath, "beacon_indicies")
 	blobDbPath := path.Join(blobDir, "chaindata")
@@ -101,7 +102,11 @@ func OpenCaplinDatabase(ctx context.Context,
 			blobDB.Close() // close blob database here
 		}()
 	}
-	return db, blob_storage.NewBlobStore(blobDB, afero.NewBasePathFs(afero.NewOsFs(), blobDir), blobPruneDistance, beaconConfig, ethClock), nil
+	blobFs := afero.NewBasePathFs(afero.NewOsFs(), blobDir)
+	if blobArchive {
+		return db, blob_storage.NewArchiveBlobStore(blobDB, blobFs, beaconConfig, ethClock), nil
+	}
+	return db, blob_storage.NewBlobStore(blobDB, blobFs, blobPruneDistance, beaconConfig, ethClock), nil
 }
 
 func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngine, config *ethconfig.Config, networkConfig *clparams.NetworkConfig,
@@ -37,6 +37,7 @@ type CaplinCliCfg struct {
 	RunEngineAPI          bool          `json:"run_engine_api"`
 	EngineAPIAddr         string        `json:"engine_api_addr"`
 	EngineAPIPort         int           `json:"engine_api_port"`
+	BlobArchive           bool          `json:"blob_archive"`
 	JwtSecret             []byte
 
 	AllowedMethods   []string `json:"allowed_methods"`
@@ -109,6 +110,7 @@ func SetupCaplinCli(ctx *cli.Context) (cfg *CaplinCliCfg, err error) {
 
 	cfg.TransitionChain = ctx.Bool(caplinflags.TransitionChainFlag.Name)
 	cfg.InitialSync = ctx.Bool(caplinflags.InitSyncFlag.Name)
+	cfg.BlobArchive = ctx.Bool(utils.CaplinBlobArchiveFlag.Name)
 
 	return cfg, err
 }
@@ -781,6 +781,9 @@ func stageHeaders(db kv.RwDB, ctx context.Context, logger log.Logger) error {
 			unwindTo = uint64(cmp.Max(1, int(progress)-int(unwind)))
 		}
 
+		if err = stagedsync.RecordReorg(tx, types.ReorgTriggerManual, unwindTo, nil); err != nil {
+			return err
+		}
 		if err = stages.SaveStageProgress(tx, stages.Headers, unwindTo); err != nil {
 			return fmt.Errorf("saving Headers progress failed: %w", err)
 		}
@@ -1239,6 +1242,10 @@ func stageLogIndex(db kv.RwDB, ctx context.Context, logger log.Logger) error {
 	if historyV3 {
 		return fmt.Errorf("this stage is disable in --history.v3=true")
 	}
+	sn, borSn, agg := allSnapshots(ctx, db, logger)
+	defer sn.Close()
+	defer borSn.Close()
+	defer agg.Close()
 	_, _, sync, _, _ := newSync(ctx, db, nil /* miningConfig */, logger)
 	must(sync.SetCurrentStage(stages.LogIndex))
 	if warmup {
@@ -1268,7 +1275,7 @@ func stageLogIndex(db kv.RwDB, ctx context.Context, logger log.Logger) error {
 	logger.Info("Stage exec", "progress", execAt)
 	logger.Info("Stage", "name", s.ID, "progress", s.BlockNumber)
 
-	cfg := stagedsync.StageLogIndexCfg(db, pm, dirs.Tmp, chainConfig.NoPruneContracts)
+	cfg := stagedsync.StageLogIndexCfg(db, pm, dirs.Tmp, chainConfig.NoPruneContracts, agg)
 	if unwind > 0 {
 		u := sync.NewUnwindState(stages.LogIndex, s.BlockNumber-unwind, s.BlockNumber)
 		err = stagedsync.UnwindLogIndex(u, s, tx, cfg, ctx)
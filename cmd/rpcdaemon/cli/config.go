@@ -44,6 +44,7 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/health"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/replica"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcservices"
 	"github.com/ledgerwatch/erigon/cmd/utils"
 	"github.com/ledgerwatch/erigon/cmd/utils/flags"
@@ -88,6 +89,11 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 	cfg := &httpcfg.HttpCfg{Enabled: true, StateCache: kvcache.DefaultCoherentConfig}
 	rootCmd.PersistentFlags().StringVar(&cfg.PrivateApiAddr, "private.api.addr", "127.0.0.1:9090", "Erigon's components (txpool, rpcdaemon, sentry, downloader, ...) can be deployed as independent Processes on same/another server. Then components will connect to erigon by this internal grpc API. Example: 127.0.0.1:9090")
 	rootCmd.PersistentFlags().StringVar(&cfg.DataDir, "datadir", "", "path to Erigon working directory")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Replica, "replica", false, "Read replica mode: download the snapshots of the chain into --datadir, copy the rest of the database of the node at --private.api.addr, follow its state changes and serve RPC from the local copy")
+	rootCmd.PersistentFlags().Uint64Var(&cfg.ReplicaHistory, "replica.history", replica.DefaultConfig.History, "Amount of recent blocks whose receipts, changesets and traces are kept by the replica")
+	rootCmd.PersistentFlags().StringVar(&cfg.ReplicaDownloaderAddr, "replica.downloader.addr", "", "Downloader the replica gets the snapshots from, an embedded one is started when empty")
+	rootCmd.PersistentFlags().IntVar(&cfg.ReplicaTorrentPort, "replica.torrent.port", replica.DefaultConfig.TorrentPort, "Port of the embedded downloader of the replica")
+	rootCmd.PersistentFlags().StringSliceVar(&cfg.ReplicaTorrentStaticPeers, "replica.torrent.staticpeers", nil, "Hosts the embedded downloader of the replica bootstraps its DHT from, e.g. the node")
 	rootCmd.PersistentFlags().BoolVar(&cfg.GraphQLEnabled, "graphql", false, "enables graphql endpoint (disabled by default)")
 	rootCmd.PersistentFlags().Uint64Var(&cfg.Gascap, "rpc.gascap", 50_000_000, "Sets a cap on gas that can be used in eth_call/estimateGas")
 	rootCmd.PersistentFlags().Uint64Var(&cfg.MaxTraces, "trace.maxtraces", 200, "Sets a limit on traces that can be returned in trace_filter")
@@ -178,6 +184,9 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 		}
 
 		cfg.WithDatadir = cfg.DataDir != ""
+		if cfg.Replica && !cfg.WithDatadir {
+			return fmt.Errorf("--replica requires --datadir")
+		}
 		if cfg.WithDatadir {
 			if cfg.DataDir == "" {
 				cfg.DataDir = paths.DefaultDataDir()
@@ -281,7 +290,7 @@ func EmbeddedServices(ctx context.Context,
 	erigonDB kv.RoDB, stateCacheCfg kvcache.CoherentConfig,
 	rpcFiltersConfig rpchelper.FiltersConfig,
 	blockReader services.FullBlockReader, ethBackendServer remote.ETHBACKENDServer, txPoolServer txpool.TxpoolServer,
-	miningServer txpool.MiningServer, stateDiffClient StateChangesClient,
+	miningServer txpool.MiningServer, stateDiffClient StateChangesClient, changeFeedClient remote.CHANGEFEEDClient,
 	logger log.Logger,
 ) (eth rpchelper.ApiBackend, txPool txpool.TxpoolClient, mining txpool.MiningClient, stateCache kvcache.Cache, ff *rpchelper.Filters, err error) {
 	if stateCacheCfg.CacheSize > 0 {
@@ -298,7 +307,7 @@ func EmbeddedServices(ctx context.Context,
 
 	directClient := direct.NewEthBackendClientDirect(ethBackendServer)
 
-	eth = rpcservices.NewRemoteBackend(directClient, erigonDB, blockReader)
+	eth = rpcservices.NewRemoteBackend(directClient, changeFeedClient, erigonDB, blockReader)
 
 	txPool = direct.NewTxPoolClient(txPoolServer)
 	mining = direct.NewMiningClient(miningServer)
@@ -338,6 +347,7 @@ func RemoteServices(ctx context.Context, cfg *httpcfg.HttpCfg, logger log.Logger
 	onNewSnapshot := func() {}
 
 	var cc *chain.Config
+	var rep *replica.Replica
 
 	if cfg.WithDatadir {
 		// Opening all databases in Accede and non-Readonly modes. Here is the motivation:
@@ -353,7 +363,21 @@ func RemoteServices(ctx context.Context, cfg *httpcfg.HttpCfg, logger log.Logger
 		dir.MustExist(cfg.Dirs.SnapHistory)
 		logger.Warn("Opening chain db", "path", cfg.Dirs.Chaindata)
 		limiter := semaphore.NewWeighted(int64(cfg.DBReadConcurrency))
-		rwKv, err = kv2.NewMDBX(logger).RoTxsLimiter(limiter).Path(cfg.Dirs.Chaindata).Accede().Open(ctx)
+		if cfg.Replica {
+			// Replica owns its db: it's created on first start and filled from the primary
+			rwKv, err = kv2.NewMDBX(logger).RoTxsLimiter(limiter).Path(cfg.Dirs.Chaindata).Label(kv.ChainDB).Open(ctx)
+			if err == nil {
+				rep = replica.New(rwKv, remoteKv, remoteKvClient, remoteBackendClient, cfg.Dirs, replica.Config{
+					History:            cfg.ReplicaHistory,
+					DownloaderAddr:     cfg.ReplicaDownloaderAddr,
+					TorrentPort:        cfg.ReplicaTorrentPort,
+					TorrentStaticPeers: cfg.ReplicaTorrentStaticPeers,
+				}, logger)
+				err = rep.Init(ctx)
+			}
+		} else {
+			rwKv, err = kv2.NewMDBX(logger).RoTxsLimiter(limiter).Path(cfg.Dirs.Chaindata).Accede().Open(ctx)
+		}
 		if err != nil {
 			return nil, nil, nil, nil, nil, nil, nil, ff, nil, err
 		}
@@ -442,8 +466,19 @@ func RemoteServices(ctx context.Context, cfg *httpcfg.HttpCfg, logger log.Logger
 				}
 			}()
 		}
+		if rep != nil { // files of the replica are its own, they are not reopened with the ones of the node
+			onNewSnapshot = func() {}
+		}
 		onNewSnapshot()
 		blockReader = freezeblocks.NewBlockReader(allSnapshots, allBorSnapshots)
+		if rep != nil {
+			if err := rep.Bootstrap(ctx, blockReader, agg); err != nil {
+				return nil, nil, nil, nil, nil, nil, nil, ff, nil, fmt.Errorf("bootstrap replica: %w", err)
+			}
+			if err := rep.Start(ctx, blockReader, agg); err != nil {
+				return nil, nil, nil, nil, nil, nil, nil, ff, nil, fmt.Errorf("start replica: %w", err)
+			}
+		}
 
 		var histV3Enabled bool
 		_ = db.View(ctx, func(tx kv.Tx) error {
@@ -492,7 +527,7 @@ func RemoteServices(ctx context.Context, cfg *httpcfg.HttpCfg, logger log.Logger
 		blockReader = freezeblocks.NewRemoteBlockReader(remoteBackendClient)
 	}
 
-	remoteEth := rpcservices.NewRemoteBackend(remoteBackendClient, db, blockReader)
+	remoteEth := rpcservices.NewRemoteBackend(remoteBackendClient, remote.NewCHANGEFEEDClient(conn), db, blockReader)
 	blockReader = remoteEth
 	eth = remoteEth
 
@@ -990,12 +1025,12 @@ func (e *remoteConsensusEngine) Close() error {
 	return e.engine.Close()
 }
 
-func (e *remoteConsensusEngine) Initialize(config *chain.Config, chain consensus.ChainHeaderReader, header *types.Header, state *state.IntraBlockState, syscall consensus.SysCallCustom, logger log.Logger) {
+func (e *remoteConsensusEngine) Initialize(config *chain.Config, chain consensus.ChainHeaderReader, header *types.Header, state *state.IntraBlockState, syscall consensus.SysCallCustom, logger log.Logger) error {
 	if err := e.validateEngineReady(); err != nil {
 		panic(err)
 	}
 
-	e.engine.Initialize(config, chain, header, state, syscall, logger)
+	return e.engine.Initialize(config, chain, header, state, syscall, logger)
 }
 
 func (e *remoteConsensusEngine) VerifyHeader(_ consensus.ChainHeaderReader, _ *types.Header, _ bool) error {
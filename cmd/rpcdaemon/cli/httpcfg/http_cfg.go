@@ -16,12 +16,18 @@ type HttpCfg struct {
 	GraphQLEnabled           bool
 	WithDatadir              bool // Erigon's database can be read by separated processes on same machine - in read-only mode - with full support of transactions. It will share same "OS PageCache" with Erigon process.
 	DataDir                  string
+	Replica                  bool // Keep an own copy of the primary's database in DataDir, fed by its state-change stream
 	Dirs                     datadir.Dirs
 	AuthRpcHTTPListenAddress string
 	TLSCertfile              string
 	TLSCACert                string
 	TLSKeyFile               string
 
+	ReplicaHistory            uint64
+	ReplicaDownloaderAddr     string
+	ReplicaTorrentPort        int
+	ReplicaTorrentStaticPeers []string
+
 	HttpServerEnabled  bool
 	HttpURL            string
 	HttpListenAddress  string
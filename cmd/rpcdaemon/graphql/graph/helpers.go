@@ -4,23 +4,36 @@ import (
 	"encoding/hex"
 	"fmt"
 	hexutil2 "github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"math"
+	"math/big"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/holiman/uint256"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/common/length"
 
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+	"github.com/ledgerwatch/erigon/turbo/jsonrpc"
 )
 
 func convertDataToStringP(abstractMap map[string]interface{}, field string) *string {
 	var result string
 
 	switch v := abstractMap[field].(type) {
+	case nil:
+		return nil
 	case int64:
 		result = strconv.FormatInt(v, 10)
+	case hexutil2.Big:
+		result = v.String()
 	case *hexutil2.Big:
 		if reflect.ValueOf(abstractMap[field]).IsZero() {
 			return nil
@@ -66,6 +79,8 @@ func convertDataToIntP(abstractMap map[string]interface{}, field string) *int {
 	var result int
 
 	switch v := abstractMap[field].(type) {
+	case nil:
+		return nil
 	case hexutil2.Uint64:
 		resultUint, err := hexutil2.DecodeUint64(v.String())
 		if err != nil {
@@ -94,6 +109,8 @@ func convertDataToUint64P(abstractMap map[string]interface{}, field string) *uin
 	var result uint64
 
 	switch v := abstractMap[field].(type) {
+	case nil:
+		return nil
 	case hexutil2.Uint64:
 		resultUint, err := hexutil2.DecodeUint64(v.String())
 		if err != nil {
@@ -111,7 +128,7 @@ func convertDataToUint64P(abstractMap map[string]interface{}, field string) *uin
 	case *hexutil2.Big:
 		result = v.ToInt().Uint64()
 	case int:
-		result = abstractMap[field].(uint64)
+		result = uint64(v)
 	case uint64:
 		result = abstractMap[field].(uint64)
 	default:
@@ -134,3 +151,277 @@ func convertStrHexToDec(hexString *string) *string {
 
 	return &result
 }
+
+// convertDataToString is convertDataToStringP for fields which may be missing, it returns "" for them.
+func convertDataToString(abstractMap map[string]interface{}, field string) string {
+	if result := convertDataToStringP(abstractMap, field); result != nil {
+		return *result
+	}
+	return ""
+}
+
+// blockRef returns the block whose state the accounts of a block are read at, pending blocks have no hash.
+func blockRef(block *model.Block) rpc.BlockNumberOrHash {
+	if block == nil || block.Hash == "" {
+		return rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	}
+	return rpc.BlockNumberOrHashWithHash(libcommon.HexToHash(block.Hash), false)
+}
+
+// accountAt returns the account at the given block, or at the block number requested by the query.
+func accountAt(account *model.Account, block *uint64) *model.Account {
+	if account == nil || block == nil {
+		return account
+	}
+	return &model.Account{Address: account.Address, Block: rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(*block))}
+}
+
+// newBlock converts the header fields of a block, as marshalled for eth_getBlockByNumber.
+func newBlock(blk map[string]interface{}) *model.Block {
+	block := &model.Block{
+		Number:            *convertDataToUint64P(blk, "number"),
+		Hash:              convertDataToString(blk, "hash"),
+		Nonce:             convertDataToString(blk, "nonce"),
+		TransactionsRoot:  convertDataToString(blk, "transactionsRoot"),
+		TransactionCount:  convertDataToIntP(blk, "transactionCount"),
+		StateRoot:         convertDataToString(blk, "stateRoot"),
+		ReceiptsRoot:      convertDataToString(blk, "receiptsRoot"),
+		ExtraData:         convertDataToString(blk, "extraData"),
+		GasLimit:          *convertDataToUint64P(blk, "gasLimit"),
+		GasUsed:           *convertDataToUint64P(blk, "gasUsed"),
+		BaseFeePerGas:     convertDataToStringP(blk, "baseFeePerGas"),
+		NextBaseFeePerGas: convertDataToStringP(blk, "nextBaseFeePerGas"),
+		Timestamp:         convertDataToString(blk, "timestamp"),
+		LogsBloom:         "0x" + convertDataToString(blk, "logsBloom"),
+		MixHash:           convertDataToString(blk, "mixHash"),
+		Difficulty:        convertDataToString(blk, "difficulty"),
+		TotalDifficulty:   convertDataToString(blk, "totalDifficulty"),
+		OmmerHash:         convertDataToString(blk, "sha3Uncles"),
+		RawHeader:         convertDataToString(blk, "rawHeader"),
+		Raw:               convertDataToString(blk, "raw"),
+		Transactions:      []*model.Transaction{},
+	}
+	block.Parent = &model.Block{Hash: convertDataToString(blk, "parentHash")}
+	block.Miner = &model.Account{Address: strings.ToLower(convertDataToString(blk, "miner")), Block: blockRef(block)}
+	if uncles, ok := blk["uncles"].([]libcommon.Hash); ok {
+		ommerCount := len(uncles)
+		block.OmmerCount = &ommerCount
+	}
+	return block
+}
+
+// newTransaction converts the fields of a transaction which do not depend on its receipt.
+func newTransaction(rpcTx *jsonrpc.RPCTransaction, block *model.Block) *model.Transaction {
+	trans := &model.Transaction{
+		Hash:       rpcTx.Hash.String(),
+		Nonce:      rpcTx.Nonce.String(),
+		From:       &model.Account{Address: strings.ToLower(rpcTx.From.String()), Block: blockRef(block)},
+		Value:      bigToString(rpcTx.Value),
+		GasPrice:   bigToString(rpcTx.GasPrice),
+		Gas:        uint64(rpcTx.Gas),
+		InputData:  rpcTx.Input.String(),
+		Block:      block,
+		R:          bigToString(rpcTx.R),
+		S:          bigToString(rpcTx.S),
+		V:          bigToString(rpcTx.V),
+		Raw:        "0x",
+		RawReceipt: "0x",
+	}
+	txType := int(rpcTx.Type)
+	trans.Type = &txType
+	if rpcTx.TransactionIndex != nil {
+		index := int(*rpcTx.TransactionIndex)
+		trans.Index = &index
+	}
+	if rpcTx.To != nil {
+		trans.To = &model.Account{Address: strings.ToLower(rpcTx.To.String()), Block: blockRef(block)}
+	}
+	if rpcTx.FeeCap != nil {
+		maxFeePerGas := rpcTx.FeeCap.String()
+		trans.MaxFeePerGas = &maxFeePerGas
+	}
+	if rpcTx.Tip != nil {
+		maxPriorityFeePerGas := rpcTx.Tip.String()
+		trans.MaxPriorityFeePerGas = &maxPriorityFeePerGas
+	}
+	if rpcTx.Accesses != nil {
+		trans.AccessList = make([]*model.AccessTuple, 0, len(*rpcTx.Accesses))
+		for _, tuple := range *rpcTx.Accesses {
+			accessTuple := &model.AccessTuple{Address: strings.ToLower(tuple.Address.String()), StorageKeys: make([]string, 0, len(tuple.StorageKeys))}
+			for _, key := range tuple.StorageKeys {
+				accessTuple.StorageKeys = append(accessTuple.StorageKeys, key.String())
+			}
+			trans.AccessList = append(trans.AccessList, accessTuple)
+		}
+	}
+	if rpcTx.SourceHash != nil {
+		sourceHash := rpcTx.SourceHash.String()
+		trans.SourceHash = &sourceHash
+		mint := bigToString(rpcTx.Mint)
+		trans.Mint = &mint
+		isSystemTx := rpcTx.IsSystemTx != nil && *rpcTx.IsSystemTx
+		trans.IsSystemTx = &isSystemTx
+	}
+	return trans
+}
+
+// applyReceipt sets the fields of a mined transaction which come from its receipt, as marshalled for
+// eth_getTransactionReceipt.
+func applyReceipt(trans *model.Transaction, transReceipt map[string]interface{}) {
+	trans.Status = convertDataToUint64P(transReceipt, "status")
+	trans.GasUsed = convertDataToUint64P(transReceipt, "gasUsed")
+	trans.CumulativeGasUsed = convertDataToUint64P(transReceipt, "cumulativeGasUsed")
+	trans.EffectiveGasPrice = convertDataToStringP(transReceipt, "effectiveGasPrice")
+	if trans.EffectiveGasPrice != nil {
+		trans.GasPrice = *trans.EffectiveGasPrice
+		if trans.Block != nil && trans.Block.BaseFeePerGas != nil {
+			price, _ := hexutil2.DecodeBig(*trans.EffectiveGasPrice)
+			baseFee, _ := hexutil2.DecodeBig(*trans.Block.BaseFeePerGas)
+			if price != nil && baseFee != nil {
+				effectiveTip := hexutil2.EncodeBig(new(big.Int).Sub(price, baseFee))
+				trans.EffectiveTip = &effectiveTip
+			}
+		}
+	}
+	if address := convertDataToStringP(transReceipt, "contractAddress"); address != nil {
+		trans.CreatedContract = &model.Account{Address: strings.ToLower(*address), Block: blockRef(trans.Block)}
+	}
+	trans.Raw = convertDataToString(transReceipt, "raw")
+	trans.RawReceipt = convertDataToString(transReceipt, "rawReceipt")
+	trans.L1Fee = convertDataToStringP(transReceipt, "l1Fee")
+	trans.L1GasUsed = convertDataToStringP(transReceipt, "l1GasUsed")
+	trans.L1BaseFeeScalar = convertDataToUint64P(transReceipt, "l1BaseFeeScalar")
+
+	trans.Logs = make([]*model.Log, 0)
+	logs, _ := transReceipt["logs"].(types.Logs)
+	for _, rlog := range logs {
+		tlog := &model.Log{
+			Index:           int(rlog.Index),
+			Account:         &model.Account{Address: strings.ToLower(rlog.Address.String()), Block: blockRef(trans.Block)},
+			Data:            "0x" + hex.EncodeToString(rlog.Data),
+			Topics:          make([]string, 0, len(rlog.Topics)),
+			Transaction:     trans,
+			TransactionHash: trans.Hash,
+		}
+		for _, rtopic := range rlog.Topics {
+			tlog.Topics = append(tlog.Topics, rtopic.String())
+		}
+		trans.Logs = append(trans.Logs, tlog)
+	}
+}
+
+// matchLog reports whether the log matches the addresses and topics of a filter, following eth_getLogs.
+func matchLog(log *model.Log, addresses []string, topics [][]string) bool {
+	if len(addresses) > 0 && !slices.ContainsFunc(addresses, func(address string) bool { return strings.EqualFold(address, log.Account.Address) }) {
+		return false
+	}
+	if len(topics) > len(log.Topics) {
+		return false
+	}
+	for i, alternatives := range topics {
+		if len(alternatives) > 0 && !slices.ContainsFunc(alternatives, func(topic string) bool { return strings.EqualFold(topic, log.Topics[i]) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseAddress(address string) (libcommon.Address, error) {
+	if !libcommon.IsHexAddress(address) {
+		return libcommon.Address{}, fmt.Errorf("invalid address %q", address)
+	}
+	return libcommon.HexToAddress(address), nil
+}
+
+func parseHash(hash string) (libcommon.Hash, error) {
+	b, err := hexutil2.Decode(hash)
+	if err != nil || len(b) != length.Hash {
+		return libcommon.Hash{}, fmt.Errorf("invalid hash %q", hash)
+	}
+	return libcommon.BytesToHash(b), nil
+}
+
+// parseBlockNumber accepts a positive long integer, then a 0x prefixed hexadecimal.
+func parseBlockNumber(s string) (rpc.BlockNumber, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		if n, err = hexutil2.DecodeUint64(s); err != nil {
+			return 0, fmt.Errorf("invalid block number %q", s)
+		}
+	}
+	if n > math.MaxInt64 {
+		return 0, fmt.Errorf("block number %q out of range", s)
+	}
+	return rpc.BlockNumber(n), nil
+}
+
+// parseBigInt accepts decimal and 0x-prefixed hexadecimal strings, as the BigInt scalar does.
+func parseBigInt(s string) (*hexutil2.Big, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		v, err := hexutil2.DecodeBig(s)
+		return (*hexutil2.Big)(v), err
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid big integer %q", s)
+	}
+	return (*hexutil2.Big)(v), nil
+}
+
+func parseBigIntP(s *string) (*hexutil2.Big, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return parseBigInt(*s)
+}
+
+func bigToString(v *hexutil2.Big) string {
+	if v == nil {
+		return "0x0"
+	}
+	return v.String()
+}
+
+func newCallArgs(data model.CallData) (ethapi.CallArgs, error) {
+	var (
+		args ethapi.CallArgs
+		err  error
+	)
+	if data.From != nil {
+		from, err := parseAddress(*data.From)
+		if err != nil {
+			return args, err
+		}
+		args.From = &from
+	}
+	if data.To != nil {
+		to, err := parseAddress(*data.To)
+		if err != nil {
+			return args, err
+		}
+		args.To = &to
+	}
+	if data.Gas != nil {
+		args.Gas = (*hexutil2.Uint64)(data.Gas)
+	}
+	if args.GasPrice, err = parseBigIntP(data.GasPrice); err != nil {
+		return args, err
+	}
+	if args.MaxFeePerGas, err = parseBigIntP(data.MaxFeePerGas); err != nil {
+		return args, err
+	}
+	if args.MaxPriorityFeePerGas, err = parseBigIntP(data.MaxPriorityFeePerGas); err != nil {
+		return args, err
+	}
+	if args.Value, err = parseBigIntP(data.Value); err != nil {
+		return args, err
+	}
+	if data.Data != nil {
+		input, err := hexutil2.Decode(*data.Data)
+		if err != nil {
+			return args, fmt.Errorf("invalid call data: %w", err)
+		}
+		args.Data = (*hexutility.Bytes)(&input)
+	}
+	return args, nil
+}
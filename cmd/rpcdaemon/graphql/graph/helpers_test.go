@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/jsonrpc"
+)
+
+func TestDepositTransaction(t *testing.T) {
+	sourceHash := libcommon.HexToHash("0x01")
+	isSystemTx := false
+	index := hexutil.Uint64(0)
+	rpcTx := &jsonrpc.RPCTransaction{
+		Hash:             libcommon.HexToHash("0x02"),
+		From:             libcommon.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001"),
+		To:               &libcommon.Address{0x42},
+		Gas:              1_000_000,
+		GasPrice:         (*hexutil.Big)(big.NewInt(0)),
+		Value:            (*hexutil.Big)(big.NewInt(0)),
+		Type:             hexutil.Uint64(types.DepositTxType),
+		TransactionIndex: &index,
+		SourceHash:       &sourceHash,
+		Mint:             (*hexutil.Big)(big.NewInt(1000)),
+		IsSystemTx:       &isSystemTx,
+	}
+	block := &model.Block{Hash: libcommon.HexToHash("0x03").String(), BaseFeePerGas: strP("0x7")}
+	trans := newTransaction(rpcTx, block)
+
+	require.Equal(t, sourceHash.String(), *trans.SourceHash)
+	require.Equal(t, "0x3e8", *trans.Mint)
+	require.False(t, *trans.IsSystemTx)
+	require.Equal(t, "0xdeaddeaddeaddeaddeaddeaddeaddeaddead0001", trans.From.Address)
+	require.Equal(t, "0x0", trans.R)
+	require.Equal(t, 0, *trans.Index)
+	require.Equal(t, blockRef(block), trans.To.Block)
+
+	applyReceipt(trans, map[string]interface{}{
+		"status":            hexutil.Uint64(1),
+		"gasUsed":           hexutil.Uint64(21000),
+		"cumulativeGasUsed": hexutil.Uint64(21000),
+		"effectiveGasPrice": (*hexutil.Big)(big.NewInt(10)),
+		"logs": types.Logs{{
+			Address: libcommon.Address{0x42},
+			Topics:  []libcommon.Hash{libcommon.HexToHash("0xaa")},
+			Index:   3,
+		}},
+	})
+	require.Equal(t, uint64(1), *trans.Status)
+	require.Equal(t, "0xa", trans.GasPrice)
+	require.Equal(t, "0x3", *trans.EffectiveTip)
+	require.Nil(t, trans.L1Fee)
+	require.Nil(t, trans.CreatedContract)
+	require.Len(t, trans.Logs, 1)
+	require.Same(t, trans, trans.Logs[0].Transaction)
+}
+
+func TestL1FeeFields(t *testing.T) {
+	trans := newTransaction(&jsonrpc.RPCTransaction{Type: hexutil.Uint64(types.DynamicFeeTxType), Tip: (*hexutil.Big)(big.NewInt(2))}, nil)
+	require.Nil(t, trans.SourceHash)
+	require.Equal(t, "0x2", *trans.MaxPriorityFeePerGas)
+
+	applyReceipt(trans, map[string]interface{}{
+		"l1Fee":           hexutil.Big(*big.NewInt(5000)),
+		"l1GasUsed":       hexutil.Big(*big.NewInt(1600)),
+		"l1BaseFeeScalar": hexutil.Uint64(1368),
+		"contractAddress": libcommon.Address{0x11},
+	})
+	require.Equal(t, "0x1388", *trans.L1Fee)
+	require.Equal(t, "0x640", *trans.L1GasUsed)
+	require.Equal(t, uint64(1368), *trans.L1BaseFeeScalar)
+	require.Equal(t, "0x1100000000000000000000000000000000000000", trans.CreatedContract.Address)
+}
+
+func TestMatchLog(t *testing.T) {
+	a, b := libcommon.HexToHash("0xaa").String(), libcommon.HexToHash("0xbb").String()
+	log := &model.Log{Account: &model.Account{Address: "0x1100000000000000000000000000000000000000"}, Topics: []string{a, b}}
+
+	require.True(t, matchLog(log, nil, nil))
+	require.True(t, matchLog(log, []string{"0x1100000000000000000000000000000000000000"}, [][]string{{}, {b}}))
+	require.True(t, matchLog(log, nil, [][]string{{b, a}}))
+	require.False(t, matchLog(log, []string{"0x2200000000000000000000000000000000000000"}, nil))
+	require.False(t, matchLog(log, nil, [][]string{{b}}))
+	require.False(t, matchLog(log, nil, [][]string{{a}, {b}, {a}}))
+}
+
+func TestNewCallArgs(t *testing.T) {
+	to, value, data := "0x1100000000000000000000000000000000000000", "1000", "0x12a7b914"
+	args, err := newCallArgs(model.CallData{To: &to, Value: &value, Data: &data})
+	require.NoError(t, err)
+	require.Equal(t, libcommon.Address{0x11}, *args.To)
+	require.Equal(t, big.NewInt(1000), args.Value.ToInt())
+	require.Equal(t, "0x12a7b914", args.Data.String())
+	require.Nil(t, args.From)
+
+	bad := "0xzz"
+	_, err = newCallArgs(model.CallData{GasPrice: &bad})
+	require.Error(t, err)
+}
+
+func TestParseBlockNumber(t *testing.T) {
+	n, err := parseBlockNumber("100")
+	require.NoError(t, err)
+	require.Equal(t, rpc.BlockNumber(100), n)
+	n, err = parseBlockNumber("0x64")
+	require.NoError(t, err)
+	require.Equal(t, rpc.BlockNumber(100), n)
+
+	_, err = parseBlockNumber("latest")
+	require.Error(t, err)
+	_, err = parseBlockNumber("0xffffffffffffffff")
+	require.Error(t, err)
+}
+
+func strP(s string) *string { return &s }
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// Account is an account at a particular block, its state is resolved on demand.
+type Account struct {
+	Address string `json:"address"`
+	// Block is the block whose state the account is read at.
+	Block rpc.BlockNumberOrHash `json:"-"`
+}
+
+type Log struct {
+	Index       int          `json:"index"`
+	Account     *Account     `json:"account"`
+	Topics      []string     `json:"topics"`
+	Data        string       `json:"data"`
+	Transaction *Transaction `json:"transaction"`
+	// TransactionHash is used to load the transaction of a log which was not loaded along with it.
+	TransactionHash string `json:"-"`
+}
@@ -7,14 +7,6 @@ type AccessTuple struct {
 	StorageKeys []string `json:"storageKeys"`
 }
 
-type Account struct {
-	Address          string `json:"address"`
-	Balance          string `json:"balance"`
-	TransactionCount uint64 `json:"transactionCount"`
-	Code             string `json:"code"`
-	Storage          string `json:"storage"`
-}
-
 type Block struct {
 	Number            uint64         `json:"number"`
 	Hash              string         `json:"hash"`
@@ -78,14 +70,6 @@ type FilterCriteria struct {
 	Topics    [][]string `json:"topics,omitempty"`
 }
 
-type Log struct {
-	Index       int          `json:"index"`
-	Account     *Account     `json:"account"`
-	Topics      []string     `json:"topics"`
-	Data        string       `json:"data"`
-	Transaction *Transaction `json:"transaction"`
-}
-
 type Pending struct {
 	TransactionCount int            `json:"transactionCount"`
 	Transactions     []*Transaction `json:"transactions,omitempty"`
@@ -127,4 +111,10 @@ type Transaction struct {
 	AccessList           []*AccessTuple `json:"accessList,omitempty"`
 	Raw                  string         `json:"raw"`
 	RawReceipt           string         `json:"rawReceipt"`
+	SourceHash           *string        `json:"sourceHash,omitempty"`
+	Mint                 *string        `json:"mint,omitempty"`
+	IsSystemTx           *bool          `json:"isSystemTx,omitempty"`
+	L1Fee                *string        `json:"l1Fee,omitempty"`
+	L1GasUsed            *string        `json:"l1GasUsed,omitempty"`
+	L1BaseFeeScalar      *uint64        `json:"l1BaseFeeScalar,omitempty"`
 }
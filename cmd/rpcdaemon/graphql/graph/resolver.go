@@ -1,7 +1,13 @@
 package graph
 
 import (
+	"context"
+	"errors"
+
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
+	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/jsonrpc"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 	"github.com/ledgerwatch/erigon/turbo/services"
@@ -13,7 +19,80 @@ import (
 
 type Resolver struct {
 	GraphQLAPI  jsonrpc.GraphQLAPI
+	EthAPI      jsonrpc.EthAPI
 	db          kv.RoDB
 	filters     *rpchelper.Filters
 	blockReader services.FullBlockReader
 }
+
+var (
+	errNoGraphQLAPI = errors.New("graphql namespace is not enabled")
+	errNoEthAPI     = errors.New("eth namespace is not enabled")
+)
+
+func (r *Resolver) graphQLAPI() (jsonrpc.GraphQLAPI, error) {
+	if r.GraphQLAPI == nil {
+		return nil, errNoGraphQLAPI
+	}
+	return r.GraphQLAPI, nil
+}
+
+func (r *Resolver) ethAPI() (jsonrpc.EthAPI, error) {
+	if r.EthAPI == nil {
+		return nil, errNoEthAPI
+	}
+	return r.EthAPI, nil
+}
+
+// block loads a block along with its transactions and their receipts, it returns nil for unknown blocks.
+func (r *Resolver) block(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*model.Block, error) {
+	gql, err := r.graphQLAPI()
+	if err != nil {
+		return nil, err
+	}
+	res, err := gql.GetBlockDetails(ctx, blockNrOrHash)
+	if err != nil || res == nil {
+		return nil, err
+	}
+
+	block := newBlock(res["block"].(map[string]interface{}))
+	for _, transReceipt := range res["receipts"].([]map[string]interface{}) {
+		trans := newTransaction(transReceipt["transaction"].(*jsonrpc.RPCTransaction), block)
+		applyReceipt(trans, transReceipt)
+		block.Transactions = append(block.Transactions, trans)
+	}
+	return block, ctx.Err()
+}
+
+func (r *Resolver) call(ctx context.Context, data model.CallData, blockNrOrHash rpc.BlockNumberOrHash) (*model.CallResult, error) {
+	gql, err := r.graphQLAPI()
+	if err != nil {
+		return nil, err
+	}
+	args, err := newCallArgs(data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := gql.Call(ctx, args, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	status := uint64(1)
+	if result.Failed() {
+		status = 0
+	}
+	return &model.CallResult{Data: hexutility.Bytes(result.ReturnData).String(), GasUsed: result.UsedGas, Status: status}, nil
+}
+
+func (r *Resolver) estimateGas(ctx context.Context, data model.CallData, blockNrOrHash rpc.BlockNumberOrHash) (uint64, error) {
+	eth, err := r.ethAPI()
+	if err != nil {
+		return 0, err
+	}
+	args, err := newCallArgs(data)
+	if err != nil {
+		return 0, err
+	}
+	gas, err := eth.EstimateGas(ctx, &args, &blockNrOrHash)
+	return uint64(gas), err
+}
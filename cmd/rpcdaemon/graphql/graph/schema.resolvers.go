@@ -7,157 +7,240 @@ package graph
 import (
 	"context"
 	"encoding/hex"
-	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
-	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
-// SendRawTransaction is the resolver for the sendRawTransaction field.
-func (r *mutationResolver) SendRawTransaction(ctx context.Context, data string) (string, error) {
-	panic(fmt.Errorf("not implemented: SendRawTransaction - sendRawTransaction"))
+// Balance is the resolver for the balance field.
+func (r *accountResolver) Balance(ctx context.Context, obj *model.Account) (string, error) {
+	eth, err := r.ethAPI()
+	if err != nil {
+		return "", err
+	}
+	address, err := parseAddress(obj.Address)
+	if err != nil {
+		return "", err
+	}
+	balance, err := eth.GetBalance(ctx, address, obj.Block)
+	if err != nil {
+		return "", err
+	}
+	return balance.String(), nil
 }
 
-// Block is the resolver for the block field.
-func (r *queryResolver) Block(ctx context.Context, number *string, hash *string) (*model.Block, error) {
-	var blockNumber rpc.BlockNumber
-
-	if number != nil {
-		// Block number is not null, test for a positive long integer
-		bNum, err := strconv.ParseUint(*number, 10, 64)
-		if err == nil {
-			// Positive integer, go ahead
-			blockNumber = rpc.BlockNumber(bNum)
-		} else {
-			bNum, err := hexutil.DecodeUint64(*number)
-			if err == nil {
-				// Hexadecimal, 0x prefixed
-				blockNumber = rpc.BlockNumber(bNum)
-			} else {
-				var err error
-				return nil, err
-			}
-		}
-	} else {
-		if hash != nil {
-			blockHash, _ := hexutil.DecodeBig(*hash)
-			fmt.Println("TODO/GraphQL/Implement me, get Block by hash=", blockHash)
-			hash = nil
-		}
+// TransactionCount is the resolver for the transactionCount field.
+func (r *accountResolver) TransactionCount(ctx context.Context, obj *model.Account) (uint64, error) {
+	eth, err := r.ethAPI()
+	if err != nil {
+		return 0, err
 	}
+	address, err := parseAddress(obj.Address)
+	if err != nil {
+		return 0, err
+	}
+	nonce, err := eth.GetTransactionCount(ctx, address, obj.Block)
+	if err != nil || nonce == nil {
+		return 0, err
+	}
+	return uint64(*nonce), nil
+}
 
-	if number == nil && hash == nil {
-		// If neither number or hash is specified (nil), we should deliver "latest" block
-		/*
-			rpc.LatestExecutedBlockNumber = BlockNumber(-5)
-			rpc.FinalizedBlockNumber      = BlockNumber(-4)
-			rpc.SafeBlockNumber           = BlockNumber(-3)
-			rpc.PendingBlockNumber        = BlockNumber(-2)
-			rpc.LatestBlockNumber         = BlockNumber(-1)
-		*/
-		blockNumber = rpc.LatestBlockNumber
+// Code is the resolver for the code field.
+func (r *accountResolver) Code(ctx context.Context, obj *model.Account) (string, error) {
+	eth, err := r.ethAPI()
+	if err != nil {
+		return "", err
 	}
+	address, err := parseAddress(obj.Address)
+	if err != nil {
+		return "", err
+	}
+	code, err := eth.GetCode(ctx, address, obj.Block)
+	if err != nil {
+		return "", err
+	}
+	return code.String(), nil
+}
 
-	res, err := r.GraphQLAPI.GetBlockDetails(ctx, blockNumber)
+// Storage is the resolver for the storage field.
+func (r *accountResolver) Storage(ctx context.Context, obj *model.Account, slot string) (string, error) {
+	eth, err := r.ethAPI()
 	if err != nil {
-		fmt.Println(err)
-		return nil, err
+		return "", err
 	}
+	address, err := parseAddress(obj.Address)
+	if err != nil {
+		return "", err
+	}
+	return eth.GetStorageAt(ctx, address, slot, obj.Block)
+}
 
-	block := &model.Block{}
-	absBlk := res["block"]
+// Parent is the resolver for the parent field.
+func (r *blockResolver) Parent(ctx context.Context, obj *model.Block) (*model.Block, error) {
+	if obj.Number == 0 || obj.Parent == nil {
+		return nil, nil
+	}
+	parentHash, err := parseHash(obj.Parent.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return r.block(ctx, rpc.BlockNumberOrHashWithHash(parentHash, false))
+}
 
-	if absBlk != nil {
-		blk := absBlk.(map[string]interface{})
+// Miner is the resolver for the miner field.
+func (r *blockResolver) Miner(ctx context.Context, obj *model.Block, block *uint64) (*model.Account, error) {
+	return accountAt(obj.Miner, block), nil
+}
 
-		block.Difficulty = *convertDataToStringP(blk, "difficulty")
-		block.ExtraData = *convertDataToStringP(blk, "extraData")
-		block.GasLimit = uint64(*convertDataToUint64P(blk, "gasLimit"))
-		block.GasUsed = *convertDataToUint64P(blk, "gasUsed")
-		block.Hash = *convertDataToStringP(blk, "hash")
-		block.Miner = &model.Account{}
-		address := convertDataToStringP(blk, "miner")
-		if address != nil {
-			block.Miner.Address = strings.ToLower(*address)
-		}
-		mixHash := convertDataToStringP(blk, "mixHash")
-		if mixHash != nil {
-			block.MixHash = *mixHash
-		}
-		blockNonce := convertDataToStringP(blk, "nonce")
-		if blockNonce != nil {
-			block.Nonce = *blockNonce
+// Ommers is the resolver for the ommers field.
+func (r *blockResolver) Ommers(ctx context.Context, obj *model.Block) ([]*model.Block, error) {
+	if obj.OmmerCount == nil {
+		return nil, nil
+	}
+	ommers := make([]*model.Block, 0, *obj.OmmerCount)
+	for i := 0; i < *obj.OmmerCount; i++ {
+		ommer, err := r.OmmerAt(ctx, obj, i)
+		if err != nil {
+			return nil, err
 		}
-		block.Number = *convertDataToUint64P(blk, "number")
-		block.Ommers = []*model.Block{}
-		block.Parent = &model.Block{}
-		block.Parent.Hash = *convertDataToStringP(blk, "parentHash")
-		block.ReceiptsRoot = *convertDataToStringP(blk, "receiptsRoot")
-		block.StateRoot = *convertDataToStringP(blk, "stateRoot")
-		block.Timestamp = *convertDataToStringP(blk, "timestamp")
-		block.TransactionCount = convertDataToIntP(blk, "transactionCount")
-		block.TransactionsRoot = *convertDataToStringP(blk, "transactionsRoot")
-		block.TotalDifficulty = *convertDataToStringP(blk, "totalDifficulty")
-		block.BaseFeePerGas = convertDataToStringP(blk, "baseFeePerGas")
-		block.Transactions = []*model.Transaction{}
-
-		block.LogsBloom = "0x" + *convertDataToStringP(blk, "logsBloom")
-		block.OmmerHash = *convertDataToStringP(blk, "sha3Uncles")
-
-		absRcp := res["receipts"]
-		rcp := absRcp.([]map[string]interface{})
-		for _, transReceipt := range rcp {
-			trans := &model.Transaction{}
-			trans.CumulativeGasUsed = convertDataToUint64P(transReceipt, "cumulativeGasUsed")
-			trans.InputData = *convertDataToStringP(transReceipt, "data")
-			trans.EffectiveGasPrice = convertDataToStringP(transReceipt, "effectiveGasPrice")
-			trans.GasPrice = *convertDataToStringP(transReceipt, "effectiveGasPrice")
-			trans.GasUsed = convertDataToUint64P(transReceipt, "gasUsed")
-			trans.Hash = *convertDataToStringP(transReceipt, "transactionHash")
-			trans.Index = convertDataToIntP(transReceipt, "transactionIndex")
-			transNonce := convertDataToStringP(transReceipt, "nonce")
-			if transNonce != nil {
-				trans.Nonce = *transNonce
-			}
-			trans.Status = convertDataToUint64P(transReceipt, "status")
-			trans.Type = convertDataToIntP(transReceipt, "type")
-			trans.Value = *convertDataToStringP(transReceipt, "value")
-
-			trans.Logs = make([]*model.Log, 0)
-			for _, rlog := range transReceipt["logs"].(types.Logs) {
-				tlog := model.Log{
-					Index: int(rlog.Index),
-					Data:  "0x" + hex.EncodeToString(rlog.Data),
-				}
-				tlog.Account = &model.Account{}
-				tlog.Account.Address = strings.ToLower(rlog.Address.String())
-
-				for _, rtopic := range rlog.Topics {
-					tlog.Topics = append(tlog.Topics, rtopic.String())
-				}
-
-				trans.Logs = append(trans.Logs, &tlog)
-			}
+		ommers = append(ommers, ommer)
+	}
+	return ommers, nil
+}
 
-			trans.From = &model.Account{}
-			trans.From.Address = strings.ToLower(*convertDataToStringP(transReceipt, "from"))
+// OmmerAt is the resolver for the ommerAt field.
+func (r *blockResolver) OmmerAt(ctx context.Context, obj *model.Block, index int) (*model.Block, error) {
+	if obj.OmmerCount == nil || index < 0 || index >= *obj.OmmerCount || obj.Hash == "" {
+		return nil, nil
+	}
+	eth, err := r.ethAPI()
+	if err != nil {
+		return nil, err
+	}
+	uncle, err := eth.GetUncleByBlockHashAndIndex(ctx, libcommon.HexToHash(obj.Hash), hexutil.Uint(index))
+	if err != nil || uncle == nil {
+		return nil, err
+	}
+	return newBlock(uncle), nil
+}
 
-			trans.To = &model.Account{}
-			address := convertDataToStringP(transReceipt, "to")
-			// To address could be nil in case of contract creation
-			if address != nil {
-				trans.To.Address = strings.ToLower(*convertDataToStringP(transReceipt, "to"))
+// TransactionAt is the resolver for the transactionAt field.
+func (r *blockResolver) TransactionAt(ctx context.Context, obj *model.Block, index int) (*model.Transaction, error) {
+	if index < 0 || index >= len(obj.Transactions) {
+		return nil, nil
+	}
+	return obj.Transactions[index], nil
+}
+
+// Logs is the resolver for the logs field.
+func (r *blockResolver) Logs(ctx context.Context, obj *model.Block, filter model.BlockFilterCriteria) ([]*model.Log, error) {
+	logs := []*model.Log{}
+	for _, trans := range obj.Transactions {
+		for _, log := range trans.Logs {
+			if matchLog(log, filter.Addresses, filter.Topics) {
+				logs = append(logs, log)
 			}
+		}
+	}
+	return logs, nil
+}
+
+// Account is the resolver for the account field.
+func (r *blockResolver) Account(ctx context.Context, obj *model.Block, address string) (*model.Account, error) {
+	if _, err := parseAddress(address); err != nil {
+		return nil, err
+	}
+	return &model.Account{Address: strings.ToLower(address), Block: blockRef(obj)}, nil
+}
+
+// Call is the resolver for the call field.
+func (r *blockResolver) Call(ctx context.Context, obj *model.Block, data model.CallData) (*model.CallResult, error) {
+	return r.call(ctx, data, blockRef(obj))
+}
+
+// EstimateGas is the resolver for the estimateGas field.
+func (r *blockResolver) EstimateGas(ctx context.Context, obj *model.Block, data model.CallData) (uint64, error) {
+	return r.estimateGas(ctx, data, blockRef(obj))
+}
 
-			block.Transactions = append(block.Transactions, trans)
+// Account is the resolver for the account field.
+func (r *logResolver) Account(ctx context.Context, obj *model.Log, block *uint64) (*model.Account, error) {
+	return accountAt(obj.Account, block), nil
+}
+
+// Transaction is the resolver for the transaction field.
+func (r *logResolver) Transaction(ctx context.Context, obj *model.Log) (*model.Transaction, error) {
+	if obj.Transaction != nil {
+		return obj.Transaction, nil
+	}
+	return (&queryResolver{r.Resolver}).Transaction(ctx, obj.TransactionHash)
+}
+
+// SendRawTransaction is the resolver for the sendRawTransaction field.
+func (r *mutationResolver) SendRawTransaction(ctx context.Context, data string) (string, error) {
+	eth, err := r.ethAPI()
+	if err != nil {
+		return "", err
+	}
+	encodedTx, err := hexutil.Decode(data)
+	if err != nil {
+		return "", err
+	}
+	hash, err := eth.SendRawTransaction(ctx, encodedTx)
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// Account is the resolver for the account field.
+func (r *pendingResolver) Account(ctx context.Context, obj *model.Pending, address string) (*model.Account, error) {
+	if _, err := parseAddress(address); err != nil {
+		return nil, err
+	}
+	return &model.Account{Address: strings.ToLower(address), Block: blockRef(nil)}, nil
+}
+
+// Call is the resolver for the call field.
+func (r *pendingResolver) Call(ctx context.Context, obj *model.Pending, data model.CallData) (*model.CallResult, error) {
+	return r.call(ctx, data, blockRef(nil))
+}
+
+// EstimateGas is the resolver for the estimateGas field.
+func (r *pendingResolver) EstimateGas(ctx context.Context, obj *model.Pending, data model.CallData) (uint64, error) {
+	return r.estimateGas(ctx, data, blockRef(nil))
+}
+
+// Block is the resolver for the block field.
+func (r *queryResolver) Block(ctx context.Context, number *string, hash *string) (*model.Block, error) {
+	var blockNrOrHash rpc.BlockNumberOrHash
+
+	switch {
+	case number != nil:
+		bNum, err := parseBlockNumber(*number)
+		if err != nil {
+			return nil, err
 		}
+		blockNrOrHash = rpc.BlockNumberOrHashWithNumber(bNum)
+	case hash != nil:
+		blockHash, err := parseHash(*hash)
+		if err != nil {
+			return nil, err
+		}
+		blockNrOrHash = rpc.BlockNumberOrHashWithHash(blockHash, false)
+	default:
+		// If neither number or hash is specified (nil), we should deliver "latest" block
+		blockNrOrHash = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
 	}
 
-	return block, ctx.Err()
+	return r.block(ctx, blockNrOrHash)
 }
 
 // Blocks is the resolver for the blocks field.
@@ -166,14 +249,28 @@ func (r *queryResolver) Blocks(ctx context.Context, from *uint64, to *uint64) ([
 
 	const maxBlocks = 25
 
-	fromBlockNumber := *from
-	toBlockNumber := *to
+	var fromBlockNumber, toBlockNumber uint64
+	if from != nil {
+		fromBlockNumber = *from
+	}
+	if to != nil {
+		toBlockNumber = *to
+	} else {
+		eth, err := r.ethAPI()
+		if err != nil {
+			return nil, err
+		}
+		latest, err := eth.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		toBlockNumber = uint64(latest)
+	}
 
 	if toBlockNumber >= fromBlockNumber && (toBlockNumber-fromBlockNumber+1) < maxBlocks {
 
 		for i := fromBlockNumber; i <= toBlockNumber; i++ {
-			blockNumberStr := strconv.FormatUint(i, 10)
-			block, _ := r.Block(ctx, &blockNumberStr, nil)
+			block, _ := r.block(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(i)))
 			if block != nil {
 				blocks = append(blocks, block)
 			}
@@ -185,46 +282,215 @@ func (r *queryResolver) Blocks(ctx context.Context, from *uint64, to *uint64) ([
 
 // Pending is the resolver for the pending field.
 func (r *queryResolver) Pending(ctx context.Context) (*model.Pending, error) {
-	panic(fmt.Errorf("not implemented: Pending - pending"))
+	gql, err := r.graphQLAPI()
+	if err != nil {
+		return nil, err
+	}
+	txs, err := gql.PendingTransactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending := &model.Pending{Transactions: make([]*model.Transaction, 0, len(txs))}
+	for _, rpcTx := range txs {
+		pending.Transactions = append(pending.Transactions, newTransaction(rpcTx, nil))
+	}
+	pending.TransactionCount = len(pending.Transactions)
+	return pending, nil
 }
 
 // Transaction is the resolver for the transaction field.
 func (r *queryResolver) Transaction(ctx context.Context, hash string) (*model.Transaction, error) {
-	panic(fmt.Errorf("not implemented: Transaction - transaction"))
+	eth, err := r.ethAPI()
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := parseHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	rpcTx, err := eth.GetTransactionByHash(ctx, txHash)
+	if err != nil || rpcTx == nil {
+		return nil, err
+	}
+
+	if rpcTx.BlockHash != nil && rpcTx.TransactionIndex != nil {
+		block, err := r.block(ctx, rpc.BlockNumberOrHashWithHash(*rpcTx.BlockHash, false))
+		if err != nil || block == nil {
+			return nil, err
+		}
+		if index := int(*rpcTx.TransactionIndex); index < len(block.Transactions) {
+			return block.Transactions[index], nil
+		}
+		return nil, nil
+	}
+
+	// The transaction has not yet been mined
+	trans := newTransaction(rpcTx, nil)
+	raw, err := eth.GetRawTransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if raw != nil {
+		trans.Raw = raw.String()
+	}
+	return trans, nil
 }
 
 // Logs is the resolver for the logs field.
 func (r *queryResolver) Logs(ctx context.Context, filter model.FilterCriteria) ([]*model.Log, error) {
-	panic(fmt.Errorf("not implemented: Logs - logs"))
+	eth, err := r.ethAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	var crit filters.FilterCriteria
+	if filter.FromBlock != nil {
+		crit.FromBlock = new(big.Int).SetUint64(*filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		crit.ToBlock = new(big.Int).SetUint64(*filter.ToBlock)
+	}
+	for _, address := range filter.Addresses {
+		addr, err := parseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		crit.Addresses = append(crit.Addresses, addr)
+	}
+	for _, alternatives := range filter.Topics {
+		topics := make([]libcommon.Hash, 0, len(alternatives))
+		for _, topic := range alternatives {
+			hash, err := parseHash(topic)
+			if err != nil {
+				return nil, err
+			}
+			topics = append(topics, hash)
+		}
+		crit.Topics = append(crit.Topics, topics)
+	}
+
+	rlogs, err := eth.GetLogs(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	logs := make([]*model.Log, 0, len(rlogs))
+	for _, rlog := range rlogs {
+		tlog := &model.Log{
+			Index:           int(rlog.Index),
+			Account:         &model.Account{Address: strings.ToLower(rlog.Address.String()), Block: rpc.BlockNumberOrHashWithHash(rlog.BlockHash, false)},
+			Data:            "0x" + hex.EncodeToString(rlog.Data),
+			Topics:          make([]string, 0, len(rlog.Topics)),
+			TransactionHash: rlog.TxHash.String(),
+		}
+		for _, rtopic := range rlog.Topics {
+			tlog.Topics = append(tlog.Topics, rtopic.String())
+		}
+		logs = append(logs, tlog)
+	}
+	return logs, ctx.Err()
 }
 
 // GasPrice is the resolver for the gasPrice field.
 func (r *queryResolver) GasPrice(ctx context.Context) (string, error) {
-	panic(fmt.Errorf("not implemented: GasPrice - gasPrice"))
+	eth, err := r.ethAPI()
+	if err != nil {
+		return "", err
+	}
+	price, err := eth.GasPrice(ctx)
+	if err != nil {
+		return "", err
+	}
+	return price.String(), nil
 }
 
 // MaxPriorityFeePerGas is the resolver for the maxPriorityFeePerGas field.
 func (r *queryResolver) MaxPriorityFeePerGas(ctx context.Context) (string, error) {
-	panic(fmt.Errorf("not implemented: MaxPriorityFeePerGas - maxPriorityFeePerGas"))
+	eth, err := r.ethAPI()
+	if err != nil {
+		return "", err
+	}
+	tip, err := eth.MaxPriorityFeePerGas(ctx)
+	if err != nil {
+		return "", err
+	}
+	return tip.String(), nil
 }
 
 // Syncing is the resolver for the syncing field.
 func (r *queryResolver) Syncing(ctx context.Context) (*model.SyncState, error) {
-	panic(fmt.Errorf("not implemented: Syncing - syncing"))
+	eth, err := r.ethAPI()
+	if err != nil {
+		return nil, err
+	}
+	res, err := eth.Syncing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	progress, ok := res.(map[string]interface{})
+	if !ok {
+		// Not syncing
+		return nil, nil
+	}
+	return &model.SyncState{
+		CurrentBlock: *convertDataToUint64P(progress, "currentBlock"),
+		HighestBlock: *convertDataToUint64P(progress, "highestBlock"),
+	}, nil
 }
 
 // ChainID is the resolver for the chainID field.
 func (r *queryResolver) ChainID(ctx context.Context) (string, error) {
-	chainID, err := r.GraphQLAPI.GetChainID(ctx)
+	gql, err := r.graphQLAPI()
+	if err != nil {
+		return "", err
+	}
+	chainID, err := gql.GetChainID(ctx)
+	if err != nil {
+		return "", err
+	}
 
-	return "0x" + strconv.FormatUint(chainID.Uint64(), 16), err
+	return "0x" + strconv.FormatUint(chainID.Uint64(), 16), nil
 }
 
+// From is the resolver for the from field.
+func (r *transactionResolver) From(ctx context.Context, obj *model.Transaction, block *uint64) (*model.Account, error) {
+	return accountAt(obj.From, block), nil
+}
+
+// To is the resolver for the to field.
+func (r *transactionResolver) To(ctx context.Context, obj *model.Transaction, block *uint64) (*model.Account, error) {
+	return accountAt(obj.To, block), nil
+}
+
+// CreatedContract is the resolver for the createdContract field.
+func (r *transactionResolver) CreatedContract(ctx context.Context, obj *model.Transaction, block *uint64) (*model.Account, error) {
+	return accountAt(obj.CreatedContract, block), nil
+}
+
+// Account returns AccountResolver implementation.
+func (r *Resolver) Account() AccountResolver { return &accountResolver{r} }
+
+// Block returns BlockResolver implementation.
+func (r *Resolver) Block() BlockResolver { return &blockResolver{r} }
+
+// Log returns LogResolver implementation.
+func (r *Resolver) Log() LogResolver { return &logResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
+// Pending returns PendingResolver implementation.
+func (r *Resolver) Pending() PendingResolver { return &pendingResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Transaction returns TransactionResolver implementation.
+func (r *Resolver) Transaction() TransactionResolver { return &transactionResolver{r} }
+
+type accountResolver struct{ *Resolver }
+type blockResolver struct{ *Resolver }
+type logResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type pendingResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type transactionResolver struct{ *Resolver }
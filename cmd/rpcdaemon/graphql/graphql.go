@@ -19,6 +19,7 @@ const (
 func CreateHandler(api []rpc.API) *handler.Server {
 
 	var graphqlAPI jsonrpc.GraphQLAPI
+	var ethAPI jsonrpc.EthAPI
 
 	for _, rpc := range api {
 		if rpc.Service == nil {
@@ -28,10 +29,14 @@ func CreateHandler(api []rpc.API) *handler.Server {
 		if graphqlCandidate, ok := rpc.Service.(jsonrpc.GraphQLAPI); ok {
 			graphqlAPI = graphqlCandidate
 		}
+		if ethCandidate, ok := rpc.Service.(jsonrpc.EthAPI); ok {
+			ethAPI = ethCandidate
+		}
 	}
 
 	resolver := graph.Resolver{}
 	resolver.GraphQLAPI = graphqlAPI
+	resolver.EthAPI = ethAPI
 
 	return handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &resolver})) // TODO : init resolver.DB here !!!
 }
@@ -0,0 +1,24 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ledgerwatch/log/v3"
+)
+
+var (
+	errUpgradeRequired = errors.New("required protocol version upgrade pending")
+)
+
+func checkProtocolVersion(opAPI OptimismAPI, r *http.Request) error {
+	versions, err := opAPI.ProtocolVersions(r.Context())
+	if err != nil {
+		log.Root().Warn("unable to process protocol version request", "err", err.Error())
+		return err
+	}
+	if versions.UpgradeRequired {
+		return errUpgradeRequired
+	}
+	return nil
+}
@@ -27,6 +27,7 @@ const (
 	minPeerCount     = "min_peer_count"
 	checkBlock       = "check_block"
 	maxSecondsBehind = "max_seconds_behind"
+	protocolVersion  = "protocol_version"
 )
 
 var (
@@ -43,19 +44,19 @@ func ProcessHealthcheckIfNeeded(
 		return false
 	}
 
-	netAPI, ethAPI := parseAPI(rpcAPI)
+	netAPI, ethAPI, opAPI := parseAPI(rpcAPI)
 
 	headers := r.Header.Values(healthHeader)
 	if len(headers) != 0 {
-		processFromHeaders(headers, ethAPI, netAPI, w, r)
+		processFromHeaders(headers, ethAPI, netAPI, opAPI, w, r)
 	} else {
-		processFromBody(w, r, netAPI, ethAPI)
+		processFromBody(w, r, netAPI, ethAPI, opAPI)
 	}
 
 	return true
 }
 
-func processFromHeaders(headers []string, ethAPI EthAPI, netAPI NetAPI, w http.ResponseWriter, r *http.Request) {
+func processFromHeaders(headers []string, ethAPI EthAPI, netAPI NetAPI, opAPI OptimismAPI, w http.ResponseWriter, r *http.Request) {
 	var (
 		errCheckSynced  = errCheckDisabled
 		errCheckPeer    = errCheckDisabled
@@ -99,10 +100,10 @@ func processFromHeaders(headers []string, ethAPI EthAPI, netAPI NetAPI, w http.R
 		}
 	}
 
-	reportHealthFromHeaders(errCheckSynced, errCheckPeer, errCheckBlock, errCheckSeconds, w)
+	reportHealthFromHeaders(errCheckSynced, errCheckPeer, errCheckBlock, errCheckSeconds, checkProtocolVersionIfEnabled(opAPI, r), w)
 }
 
-func processFromBody(w http.ResponseWriter, r *http.Request, netAPI NetAPI, ethAPI EthAPI) {
+func processFromBody(w http.ResponseWriter, r *http.Request, netAPI NetAPI, ethAPI EthAPI, opAPI OptimismAPI) {
 	body, errParse := parseHealthCheckBody(r.Body)
 	defer r.Body.Close()
 
@@ -123,7 +124,7 @@ func processFromBody(w http.ResponseWriter, r *http.Request, netAPI NetAPI, ethA
 		// TODO add time from the last sync cycle
 	}
 
-	err := reportHealthFromBody(errParse, errMinPeerCount, errCheckBlock, w)
+	err := reportHealthFromBody(errParse, errMinPeerCount, errCheckBlock, checkProtocolVersionIfEnabled(opAPI, r), w)
 	if err != nil {
 		log.Root().Warn("unable to process healthcheck request", "err", err)
 	}
@@ -145,29 +146,45 @@ func parseHealthCheckBody(reader io.Reader) (requestBody, error) {
 	return body, nil
 }
 
-func reportHealthFromBody(errParse, errMinPeerCount, errCheckBlock error, w http.ResponseWriter) error {
+// checkProtocolVersionIfEnabled fails the health check while a required protocol version upgrade is
+// pending. It only applies to OP stack nodes serving the optimism namespace.
+func checkProtocolVersionIfEnabled(opAPI OptimismAPI, r *http.Request) error {
+	if opAPI == nil {
+		return errCheckDisabled
+	}
+	return checkProtocolVersion(opAPI, r)
+}
+
+func reportHealthFromBody(errParse, errMinPeerCount, errCheckBlock, errProtocolVersion error, w http.ResponseWriter) error {
 	statusCode := http.StatusOK
-	errors := make(map[string]string)
+	errs := make(map[string]string)
 
 	if shouldChangeStatusCode(errParse) {
 		statusCode = http.StatusInternalServerError
 	}
-	errors["healthcheck_query"] = errorStringOrOK(errParse)
+	errs["healthcheck_query"] = errorStringOrOK(errParse)
 
 	if shouldChangeStatusCode(errMinPeerCount) {
 		statusCode = http.StatusInternalServerError
 	}
-	errors["min_peer_count"] = errorStringOrOK(errMinPeerCount)
+	errs["min_peer_count"] = errorStringOrOK(errMinPeerCount)
 
 	if shouldChangeStatusCode(errCheckBlock) {
 		statusCode = http.StatusInternalServerError
 	}
-	errors["check_block"] = errorStringOrOK(errCheckBlock)
+	errs["check_block"] = errorStringOrOK(errCheckBlock)
 
-	return writeResponse(w, errors, statusCode)
+	if !errors.Is(errProtocolVersion, errCheckDisabled) {
+		if shouldChangeStatusCode(errProtocolVersion) {
+			statusCode = http.StatusInternalServerError
+		}
+		errs[protocolVersion] = errorStringOrOK(errProtocolVersion)
+	}
+
+	return writeResponse(w, errs, statusCode)
 }
 
-func reportHealthFromHeaders(errCheckSynced, errCheckPeer, errCheckBlock, errCheckSeconds error, w http.ResponseWriter) error {
+func reportHealthFromHeaders(errCheckSynced, errCheckPeer, errCheckBlock, errCheckSeconds, errProtocolVersion error, w http.ResponseWriter) error {
 	statusCode := http.StatusOK
 	errs := make(map[string]string)
 
@@ -191,6 +208,13 @@ func reportHealthFromHeaders(errCheckSynced, errCheckPeer, errCheckBlock, errChe
 	}
 	errs[maxSecondsBehind] = errorStringOrOK(errCheckSeconds)
 
+	if !errors.Is(errProtocolVersion, errCheckDisabled) {
+		if shouldChangeStatusCode(errProtocolVersion) {
+			statusCode = http.StatusInternalServerError
+		}
+		errs[protocolVersion] = errorStringOrOK(errProtocolVersion)
+	}
+
 	return writeResponse(w, errs, statusCode)
 }
 
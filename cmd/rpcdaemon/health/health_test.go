@@ -14,6 +14,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 
 	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/jsonrpc"
 )
 
 type netApiStub struct {
@@ -561,3 +562,49 @@ func TestProcessHealthcheckIfNeeded_RequestBody(t *testing.T) {
 		}
 	}
 }
+
+type optimismApiStub struct {
+	upgradeRequired bool
+}
+
+func (o *optimismApiStub) ProtocolVersions(_ context.Context) (*jsonrpc.ProtocolVersions, error) {
+	return &jsonrpc.ProtocolVersions{UpgradeRequired: o.upgradeRequired}, nil
+}
+
+func TestProcessHealthcheckIfNeeded_ProtocolVersion(t *testing.T) {
+	for _, upgradeRequired := range []bool{false, true} {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, "http://localhost:9090/health", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Add("X-ERIGON-HEALTHCHECK", "synced")
+
+		apis := []rpc.API{
+			{Service: &ethApiStub{syncingResult: false}},
+			{Service: &optimismApiStub{upgradeRequired: upgradeRequired}},
+		}
+		ProcessHealthcheckIfNeeded(w, r, apis)
+
+		result := w.Result()
+		var body map[string]string
+		if err := json.NewDecoder(result.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if upgradeRequired {
+			if result.StatusCode != http.StatusInternalServerError {
+				t.Errorf("expected status code %d, got %d", http.StatusInternalServerError, result.StatusCode)
+			}
+			if body[protocolVersion] != "ERROR: "+errUpgradeRequired.Error() {
+				t.Errorf("unexpected protocol version check result %q", body[protocolVersion])
+			}
+		} else {
+			if result.StatusCode != http.StatusOK {
+				t.Errorf("expected status code %d, got %d", http.StatusOK, result.StatusCode)
+			}
+			if body[protocolVersion] != "HEALTHY" {
+				t.Errorf("unexpected protocol version check result %q", body[protocolVersion])
+			}
+		}
+	}
+}
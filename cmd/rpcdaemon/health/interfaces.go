@@ -5,6 +5,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 
 	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/jsonrpc"
 )
 
 type NetAPI interface {
@@ -15,3 +16,7 @@ type EthAPI interface {
 	GetBlockByNumber(_ context.Context, number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error)
 	Syncing(ctx context.Context) (interface{}, error)
 }
+
+type OptimismAPI interface {
+	ProtocolVersions(ctx context.Context) (*jsonrpc.ProtocolVersions, error)
+}
@@ -4,7 +4,7 @@ import (
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
-func parseAPI(api []rpc.API) (netAPI NetAPI, ethAPI EthAPI) {
+func parseAPI(api []rpc.API) (netAPI NetAPI, ethAPI EthAPI, opAPI OptimismAPI) {
 	for _, rpc := range api {
 		if rpc.Service == nil {
 			continue
@@ -17,6 +17,10 @@ func parseAPI(api []rpc.API) (netAPI NetAPI, ethAPI EthAPI) {
 		if ethCandidate, ok := rpc.Service.(EthAPI); ok {
 			ethAPI = ethCandidate
 		}
+
+		if opCandidate, ok := rpc.Service.(OptimismAPI); ok {
+			opAPI = opCandidate
+		}
 	}
-	return netAPI, ethAPI
+	return netAPI, ethAPI, opAPI
 }
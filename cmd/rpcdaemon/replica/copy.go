@@ -0,0 +1,505 @@
+package replica
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/dbutils"
+	"github.com/ledgerwatch/erigon-lib/kv/temporal/historyv2"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+)
+
+// pageSize bounds the amount of pairs read from the primary by one Range call: without a limit the kv server
+// returns a whole range in a single message.
+var pageSize = 4096
+
+// bootstrapBatch is the amount of pairs written to the replica by one transaction during bootstrap
+const bootstrapBatch = 1_000_000
+
+// bootstrapBlocksBatch is the amount of blocks written to the replica by one transaction during bootstrap
+const bootstrapBlocksBatch = 10_000
+
+// blockTables are keyed by block number, they hold the block data not reachable through the canonical hash
+var blockTables = []string{kv.Receipts, kv.Log, kv.AccountChangeSet, kv.StorageChangeSet, kv.CallTraceSet, kv.BlockExecutionProfiles}
+
+// blockStages are the stages whose work is done by the primary, their progress is the head of the replica
+var blockStages = []stages.SyncStage{stages.Headers, stages.BlockHashes, stages.Bodies, stages.Senders}
+
+// stateTables hold the current state, they are copied from the primary at bootstrap
+var stateTables = []string{kv.PlainState, kv.PlainContractCode, kv.Code, kv.IncarnationMap, kv.HashedAccounts, kv.HashedStorage, kv.ContractCode, kv.TrieOfAccounts, kv.TrieOfStorage}
+
+// forRange walks the [from, to) range of a table of the primary page by page. Pages of DupSort tables end on a key
+// boundary, so that the values of a key are never split between two pages.
+func forRange(tx kv.Tx, table string, from, to []byte, walker func(k, v []byte) error) error {
+	cfg := kv.ChaindataTablesCfg[table]
+	dupSort := cfg.Flags&kv.DupSort != 0 && !cfg.AutoDupSortKeysConversion
+	for {
+		it, err := tx.RangeAscend(table, from, to, pageSize)
+		if err != nil {
+			return err
+		}
+		var keys, values [][]byte
+		for it.HasNext() {
+			k, v, err := it.Next()
+			if err != nil {
+				return err
+			}
+			keys, values = append(keys, k), append(values, v)
+		}
+		if casted, ok := it.(kv.Closer); ok {
+			casted.Close()
+		}
+
+		n := len(keys)
+		if n < pageSize {
+			for i := range keys {
+				if err := walker(keys[i], values[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		last := libcommon.Copy(keys[n-1])
+		if dupSort {
+			for n > 0 && bytes.Equal(keys[n-1], last) {
+				n--
+			}
+		}
+		for i := 0; i < n; i++ {
+			if err := walker(keys[i], values[i]); err != nil {
+				return err
+			}
+		}
+		switch {
+		case n == 0: // the values of a single key don't fit a page
+			if err := forDups(tx, table, last, walker); err != nil {
+				return err
+			}
+			from = append(last, 0)
+		case dupSort:
+			from = last
+		default:
+			from = append(last, 0)
+		}
+	}
+}
+
+func forDups(tx kv.Tx, table string, key []byte, walker func(k, v []byte) error) error {
+	c, err := tx.CursorDupSort(table)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, v, err := c.SeekExact(key); k != nil; k, v, err = c.NextDup() {
+		if err != nil {
+			return err
+		}
+		if err := walker(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyTable replaces a table of the replica by the one of the primary
+func (r *Replica) copyTable(ctx context.Context, ptx kv.Tx, table string) error {
+	tx, err := r.db.BeginRw(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { tx.Rollback() }()
+	if err := tx.ClearBucket(table); err != nil {
+		return err
+	}
+
+	var count int
+	if err := forRange(ptx, table, nil, nil, func(k, v []byte) error {
+		if err := tx.Append(table, k, v); err != nil {
+			return err
+		}
+		if count++; count%bootstrapBatch != 0 {
+			return nil
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		r.logger.Info("[replica] bootstrap", "table", table, "copied", count)
+		tx, err = r.db.BeginRw(ctx)
+		return err
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// copyChain copies the canonical blocks [from, to] of the primary in batches, dropping the blocks copied by an
+// interrupted bootstrap first
+func (r *Replica) copyChain(ctx context.Context, ptx kv.Tx, from, to uint64) error {
+	if err := r.db.Update(ctx, func(tx kv.RwTx) error { return r.truncate(ctx, tx, from) }); err != nil {
+		return err
+	}
+	for batchFrom := from; batchFrom <= to; batchFrom += bootstrapBlocksBatch {
+		batchTo := min(batchFrom+bootstrapBlocksBatch-1, to)
+		if err := r.db.Update(ctx, func(tx kv.RwTx) error {
+			return r.copyBlocks(ctx, tx, ptx, batchFrom, batchTo)
+		}); err != nil {
+			return err
+		}
+		r.logger.Info("[replica] bootstrap", "blocks", batchTo, "of", to)
+	}
+	return nil
+}
+
+// copyBlocks copies the canonical blocks [from, to] of the primary. Blocks the primary already moved to its files
+// are fetched through its backend, transactions get ids of the replica.
+func (r *Replica) copyBlocks(ctx context.Context, tx kv.RwTx, ptx kv.Tx, from, to uint64) error {
+	for number := from; number <= to; number++ {
+		hash, err := rawdb.ReadCanonicalHash(ptx, number)
+		if err != nil {
+			return err
+		}
+		if hash == (libcommon.Hash{}) {
+			return fmt.Errorf("canonical hash of block %d not found on the primary", number)
+		}
+		key := dbutils.HeaderKey(number, hash)
+		stored, err := rawdb.ReadBodyForStorageByKey(ptx, key)
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			for _, table := range []string{kv.Headers, kv.Senders} {
+				if err := copyValue(tx, ptx, table, key); err != nil {
+					return err
+				}
+			}
+			// the first and the last transactions of a stored body are system ones
+			body := &types.RawBody{Uncles: stored.Uncles, Withdrawals: stored.Withdrawals}
+			if stored.TxAmount > 2 {
+				if body.Transactions, err = rawTransactions(ptx, stored.BaseTxId+1, stored.BaseTxId+uint64(stored.TxAmount)-1); err != nil {
+					return err
+				}
+			}
+			if _, err := rawdb.WriteRawBody(tx, hash, number, body); err != nil {
+				return err
+			}
+		} else {
+			block, senders, err := r.blocks.BlockWithSenders(ctx, nil, hash, number)
+			if err != nil {
+				return fmt.Errorf("block %d of the primary: %w", number, err)
+			}
+			if err := rawdb.WriteHeader(tx, block.Header()); err != nil {
+				return err
+			}
+			if _, err := rawdb.WriteRawBody(tx, hash, number, block.RawBody()); err != nil {
+				return err
+			}
+			if err := rawdb.WriteSenders(tx, hash, number, senders); err != nil {
+				return err
+			}
+		}
+		if err := copyValue(tx, ptx, kv.HeaderTD, key); err != nil {
+			return err
+		}
+		if err := rawdb.WriteCanonicalHash(tx, hash, number); err != nil {
+			return err
+		}
+		if err := tx.Put(kv.HeaderNumber, hash[:], hexutility.EncodeTs(number)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawTransactions reads the transactions [from, to) of the primary
+func rawTransactions(ptx kv.Tx, from, to uint64) ([][]byte, error) {
+	var txs [][]byte
+	if err := forRange(ptx, kv.EthTx, hexutility.EncodeTs(from), hexutility.EncodeTs(to), func(_, v []byte) error {
+		txs = append(txs, libcommon.Copy(v))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if uint64(len(txs)) != to-from {
+		return nil, fmt.Errorf("transactions [%d, %d) not found on the primary", from, to)
+	}
+	return txs, nil
+}
+
+// copyHistory copies the receipts, changesets and traces of the blocks [from, to] of the primary
+func copyHistory(tx kv.RwTx, ptx kv.Tx, from, to uint64) error {
+	for _, table := range blockTables {
+		if err := forRange(ptx, table, hexutility.EncodeTs(from), hexutility.EncodeTs(to+1), func(k, v []byte) error {
+			return tx.Put(table, k, v)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyValue copies the value of a key from the primary, deleting the key when the primary doesn't have it
+func copyValue(tx kv.RwTx, ptx kv.Tx, table string, key []byte) error {
+	v, err := ptx.GetOne(table, key)
+	if err != nil {
+		return err
+	}
+	if len(v) == 0 {
+		return tx.Delete(table, key)
+	}
+	return tx.Put(table, key, v)
+}
+
+// copyMetadata mirrors the forkchoice pointers, reorg log and superchain signal of the primary
+func copyMetadata(tx kv.RwTx, ptx kv.Tx) error {
+	// safe and finalized blocks may not be applied by the replica yet
+	if hash := rawdb.ReadForkchoiceSafe(ptx); rawdb.ReadHeaderNumber(tx, hash) != nil {
+		rawdb.WriteForkchoiceSafe(tx, hash)
+	}
+	if hash := rawdb.ReadForkchoiceFinalized(ptx); rawdb.ReadHeaderNumber(tx, hash) != nil {
+		rawdb.WriteForkchoiceFinalized(tx, hash)
+	}
+	for _, table := range []string{kv.Reorgs, kv.SuperchainSignal} {
+		c, err := tx.Cursor(table)
+		if err != nil {
+			return err
+		}
+		last, _, err := c.Last()
+		last = libcommon.Copy(last)
+		c.Close()
+		if err != nil {
+			return err
+		}
+		if err := forRange(ptx, table, last, nil, func(k, v []byte) error { return tx.Put(table, k, v) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// catchUp moves the replica from block `from` to block `to` of the primary: the keys changed in between are
+// taken from the changesets of the primary and their values from its current state.
+func (r *Replica) catchUp(ctx context.Context, tx kv.RwTx, ptx kv.Tx, from, to uint64) error {
+	pm, err := prune.Get(ptx)
+	if err != nil {
+		return err
+	}
+	if pm.History.Enabled() && from+1 < pm.History.PruneTo(to) {
+		return fmt.Errorf("primary pruned the changesets after block %d, remove the chaindata of the replica to bootstrap it again", from)
+	}
+
+	seen := map[string]struct{}{}
+	copyOnce := func(table string, key []byte) error {
+		if _, ok := seen[string(key)]; ok {
+			return nil
+		}
+		seen[string(key)] = struct{}{}
+		if table == kv.PlainState && len(key) == length.Addr {
+			return copyAccount(tx, ptx, key)
+		}
+		return copyValue(tx, ptx, table, key)
+	}
+	fromKey, toKey := hexutility.EncodeTs(from+1), hexutility.EncodeTs(to+1)
+	if err := forRange(ptx, kv.AccountChangeSet, fromKey, toKey, func(_, v []byte) error {
+		return copyOnce(kv.PlainState, v[:length.Addr])
+	}); err != nil {
+		return err
+	}
+	if err := forRange(ptx, kv.StorageChangeSet, fromKey, toKey, func(k, v []byte) error {
+		key := make([]byte, 0, length.Addr+length.Incarnation+length.Hash)
+		key = append(append(key, k[length.BlockNum:]...), v[:length.Hash]...)
+		return copyOnce(kv.PlainState, key)
+	}); err != nil {
+		return err
+	}
+	if err := r.copyBlocks(ctx, tx, ptx, from+1, to); err != nil {
+		return err
+	}
+	if err := copyHistory(tx, ptx, from+1, to); err != nil {
+		return err
+	}
+	return r.setHead(tx, to)
+}
+
+// copyAccount copies an account from the primary along with its code
+func copyAccount(tx kv.RwTx, ptx kv.Tx, address []byte) error {
+	if err := copyValue(tx, ptx, kv.IncarnationMap, address); err != nil {
+		return err
+	}
+	v, err := ptx.GetOne(kv.PlainState, address)
+	if err != nil {
+		return err
+	}
+	if len(v) == 0 {
+		return tx.Delete(kv.PlainState, address)
+	}
+	if err := tx.Put(kv.PlainState, address, v); err != nil {
+		return err
+	}
+	var acc accounts.Account
+	if err := acc.DecodeForStorage(v); err != nil {
+		return err
+	}
+	if acc.Incarnation == 0 {
+		return nil
+	}
+	prefix := dbutils.PlainGenerateStoragePrefix(address, acc.Incarnation)
+	codeHash, err := ptx.GetOne(kv.PlainContractCode, prefix)
+	if err != nil || len(codeHash) == 0 {
+		return err
+	}
+	if err := tx.Put(kv.PlainContractCode, prefix, codeHash); err != nil {
+		return err
+	}
+	return copyValue(tx, ptx, kv.Code, codeHash)
+}
+
+// applyChanges writes the state diffs of a block the way state.PlainStateWriter wrote them on the primary
+func applyChanges(tx kv.RwTx, changes []*remote.AccountChange) error {
+	for _, change := range changes {
+		address := libcommon.Address(gointerfaces.ConvertH160toAddress(change.Address))
+		switch change.Action {
+		case remote.Action_UPSERT, remote.Action_UPSERT_CODE:
+			if err := updateAccount(tx, address, change.Data); err != nil {
+				return err
+			}
+		case remote.Action_REMOVE:
+			if err := deleteAccount(tx, address); err != nil {
+				return err
+			}
+		}
+		if change.Action == remote.Action_CODE || change.Action == remote.Action_UPSERT_CODE {
+			codeHash := crypto.Keccak256Hash(change.Code)
+			if err := tx.Put(kv.Code, codeHash[:], change.Code); err != nil {
+				return err
+			}
+			if err := tx.Put(kv.PlainContractCode, dbutils.PlainGenerateStoragePrefix(address[:], change.Incarnation), codeHash[:]); err != nil {
+				return err
+			}
+		}
+		for _, storage := range change.StorageChanges {
+			location := gointerfaces.ConvertH256ToHash(storage.Location)
+			key := dbutils.PlainGenerateCompositeStorageKey(address[:], change.Incarnation, location[:])
+			var err error
+			if len(storage.Data) == 0 {
+				err = tx.Delete(kv.PlainState, key)
+			} else {
+				err = tx.Put(kv.PlainState, key, storage.Data)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func updateAccount(tx kv.RwTx, address libcommon.Address, data []byte) error {
+	var account accounts.Account
+	if err := account.DecodeForStorage(data); err != nil {
+		return err
+	}
+	if account.Incarnation == 0 {
+		if err := rememberIncarnation(tx, address); err != nil {
+			return err
+		}
+	}
+	return tx.Put(kv.PlainState, address[:], data)
+}
+
+func deleteAccount(tx kv.RwTx, address libcommon.Address) error {
+	if err := rememberIncarnation(tx, address); err != nil {
+		return err
+	}
+	return tx.Delete(kv.PlainState, address[:])
+}
+
+// rememberIncarnation keeps the incarnation of an account about to be replaced in kv.IncarnationMap
+func rememberIncarnation(tx kv.RwTx, address libcommon.Address) error {
+	v, err := tx.GetOne(kv.PlainState, address[:])
+	if err != nil || len(v) == 0 {
+		return err
+	}
+	var original accounts.Account
+	if err := original.DecodeForStorage(v); err != nil {
+		return err
+	}
+	if original.Incarnation == 0 {
+		return nil
+	}
+	return tx.Put(kv.IncarnationMap, address[:], hexutility.EncodeTs(original.Incarnation))
+}
+
+// truncate deletes the blocks from the given one on, together with their receipts, changesets and traces
+func (r *Replica) truncate(ctx context.Context, tx kv.RwTx, from uint64) error {
+	if err := tx.ForEach(kv.Headers, hexutility.EncodeTs(from), func(k, _ []byte) error {
+		return tx.Delete(kv.HeaderNumber, k[length.BlockNum:])
+	}); err != nil {
+		return err
+	}
+	if err := rawdb.TruncateBlocks(ctx, tx, from); err != nil {
+		return err
+	}
+	if err := rawdb.TruncateCanonicalHash(tx, from, false); err != nil {
+		return err
+	}
+	if err := rawdb.TruncateTd(tx, from); err != nil {
+		return err
+	}
+	if err := rawdb.TruncateReceipts(tx, from); err != nil {
+		return err
+	}
+	if err := historyv2.Truncate(tx, from); err != nil {
+		return err
+	}
+	for _, table := range []string{kv.CallTraceSet, kv.BlockExecutionProfiles} {
+		var keys [][]byte
+		if err := tx.ForEach(table, hexutility.EncodeTs(from), func(k, _ []byte) error {
+			keys = append(keys, libcommon.Copy(k))
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := tx.Delete(table, k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setHead moves the head of the replica and the progress of the stages done by the primary to the given block.
+// The state diffs of the block are applied too, so Execution moves with them.
+func (r *Replica) setHead(tx kv.RwTx, number uint64) error {
+	hash, err := rawdb.ReadCanonicalHash(tx, number)
+	if err != nil {
+		return err
+	}
+	for _, stage := range blockStages {
+		if err := stages.SaveStageProgress(tx, stage, number); err != nil {
+			return err
+		}
+	}
+	if err := stages.SaveStageProgress(tx, stages.Execution, number); err != nil {
+		return err
+	}
+	if err := rawdb.WriteHeadHeaderHash(tx, hash); err != nil {
+		return err
+	}
+	rawdb.WriteHeadBlockHash(tx, hash)
+	rawdb.WriteForkchoiceHead(tx, hash)
+	return nil
+}
@@ -0,0 +1,52 @@
+package replica
+
+import (
+	"context"
+	"fmt"
+
+	lg "github.com/anacrolix/log"
+	"github.com/c2h5oh/datasize"
+	"github.com/ledgerwatch/erigon-lib/chain/snapcfg"
+	"github.com/ledgerwatch/erigon-lib/direct"
+	"github.com/ledgerwatch/erigon-lib/downloader"
+	"github.com/ledgerwatch/erigon-lib/downloader/downloadercfg"
+	"github.com/ledgerwatch/erigon-lib/downloader/downloadergrpc"
+	proto_downloader "github.com/ledgerwatch/erigon-lib/gointerfaces/downloader"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/params"
+)
+
+// same defaults as the torrent flags of erigon
+const (
+	downloadRate  = 16 * datasize.MB
+	uploadRate    = 4 * datasize.MB
+	connsPerFile  = 10
+	downloadSlots = 3
+)
+
+// newDownloader connects to the downloader of Config.DownloaderAddr, or starts an embedded one for the time of the
+// bootstrap.
+func (r *Replica) newDownloader(ctx context.Context, chainName string) (proto_downloader.DownloaderClient, func(), error) {
+	if r.cfg.DownloaderAddr != "" {
+		client, err := downloadergrpc.NewClient(ctx, r.cfg.DownloaderAddr)
+		return client, func() {}, err
+	}
+
+	version := "erigon: " + params.VersionWithCommit(params.GitCommit)
+	cfg, err := downloadercfg.New(r.dirs, version, lg.Info, downloadRate, uploadRate, r.cfg.TorrentPort, connsPerFile, downloadSlots, r.cfg.TorrentStaticPeers, snapcfg.KnownWebseeds[chainName], chainName, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	d, err := downloader.New(ctx, cfg, r.logger, log.LvlInfo, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.MainLoopInBackground(false)
+	server, err := downloader.NewGrpcServer(d)
+	if err != nil {
+		d.Close()
+		return nil, nil, fmt.Errorf("new server: %w", err)
+	}
+	return direct.NewDownloaderClient(server), d.Close, nil
+}
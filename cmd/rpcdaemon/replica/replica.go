@@ -0,0 +1,511 @@
+// Package replica keeps a local copy of the chain db of a primary node, so that rpcdaemon can serve reads from its
+// own mdbx instead of doing a network round-trip per cursor operation through remotedb.
+//
+// A replica is bootstrapped once the way a node syncs from scratch: block files are downloaded by the downloader.
+// Only the blocks after the files, the current state and the recent history are copied from the primary, history
+// older than Config.History blocks is pruned on the replica. It then follows the StateChanges stream of the primary:
+// the state diffs of a block are applied directly, while its block data (headers, bodies, receipts, changesets...)
+// is copied from the primary. Unwinds of the primary are followed through the unwind flag of the stream.
+// Primaries running history v3 aren't supported, they are refused with ErrHistoryV3.
+// When the replica misses a part of the stream (reconnects, primary catching up far from the tip, deep reorgs) it
+// resyncs from the changesets of the primary. The commitment and the history indices are built locally by the
+// stages of stagedsync.ReplicaStages, which also checks the state root of every block the replica applies.
+package replica
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	proto_downloader "github.com/ledgerwatch/erigon-lib/gointerfaces/downloader"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcfg"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/erigon-lib/wrap"
+	"github.com/ledgerwatch/log/v3"
+	"google.golang.org/grpc"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+var (
+	replicaHeadBlock    = metrics.GetOrCreateGauge("replica_head_block")
+	replicaPrimaryBlock = metrics.GetOrCreateGauge("replica_primary_block")
+)
+
+// bootstrapKey marks a finished bootstrap in kv.DatabaseInfo, it stores the block the replica was bootstrapped at
+var bootstrapKey = []byte("replicaBootstrap")
+
+// ErrHistoryV3 is returned for a primary running history v3, whose state history isn't kept in changesets
+var ErrHistoryV3 = errors.New("the replica doesn't support nodes running history v3 yet")
+
+// errNotContiguous is returned when a block of the stream doesn't extend the chain of the replica
+var errNotContiguous = errors.New("block is not contiguous with the replica")
+
+// Client is the part of the KV service of the primary used by a replica
+type Client interface {
+	StateChanges(ctx context.Context, in *remote.StateChangeRequest, opts ...grpc.CallOption) (remote.KV_StateChangesClient, error)
+}
+
+// Config of a replica, set by the --replica.* flags of rpcdaemon
+type Config struct {
+	History            uint64   // blocks of history kept by the replica, older receipts, changesets and traces are pruned
+	DownloaderAddr     string   // external downloader, a downloader is embedded when empty
+	TorrentPort        int      // port of the embedded downloader
+	TorrentStaticPeers []string // hosts the embedded downloader bootstraps its DHT from, e.g. the primary
+}
+
+var DefaultConfig = Config{
+	History:     params.FullImmutabilityThreshold,
+	TorrentPort: 42069,
+}
+
+type Replica struct {
+	db          kv.RwDB
+	primary     kv.RoDB
+	client      Client
+	blocks      *freezeblocks.RemoteBlockReader // blocks the primary only has in its files
+	dirs        datadir.Dirs
+	cfg         Config
+	blockReader services.FullBlockReader
+	sync        *stagedsync.Sync
+	logger      log.Logger
+}
+
+func New(db kv.RwDB, primary kv.RoDB, client Client, backend remote.ETHBACKENDClient, dirs datadir.Dirs, cfg Config, logger log.Logger) *Replica {
+	return &Replica{db: db, primary: primary, client: client, blocks: freezeblocks.NewRemoteBlockReader(backend), dirs: dirs, cfg: cfg, logger: logger}
+}
+
+// Init copies the chain config, the db settings and the genesis block of the primary into an empty replica, so that
+// its db can be opened like the one of a node. It does nothing for an initialized replica.
+func (r *Replica) Init(ctx context.Context) error {
+	return r.db.Update(ctx, func(tx kv.RwTx) error {
+		genesisHash, err := rawdb.ReadCanonicalHash(tx, 0)
+		if err != nil || genesisHash != (libcommon.Hash{}) {
+			return err
+		}
+		ptx, err := r.primary.BeginRo(ctx)
+		if err != nil {
+			return err
+		}
+		defer ptx.Rollback()
+		historyV3, err := kvcfg.HistoryV3.Enabled(ptx)
+		if err != nil {
+			return err
+		}
+		if historyV3 {
+			return ErrHistoryV3
+		}
+
+		for _, table := range []string{kv.ConfigTable, kv.DatabaseInfo} {
+			if err := forRange(ptx, table, nil, nil, func(k, v []byte) error { return tx.Put(table, k, v) }); err != nil {
+				return err
+			}
+		}
+		// the replica lists the files it downloaded itself
+		for _, key := range [][]byte{rawdb.SnapshotsKey, rawdb.SnapshotsHistoryKey} {
+			if err := tx.Delete(kv.DatabaseInfo, key); err != nil {
+				return err
+			}
+		}
+		return r.copyBlocks(ctx, tx, ptx, 0, 0)
+	})
+}
+
+// Bootstrap fills an initialized replica, it does nothing for a bootstrapped one. Block files are downloaded, the
+// blocks after them and the current state are copied from the primary.
+func (r *Replica) Bootstrap(ctx context.Context, blockReader services.FullBlockReader, agg *libstate.Aggregator) error {
+	var done bool
+	if err := r.db.View(ctx, func(tx kv.Tx) (err error) {
+		done, err = tx.Has(kv.DatabaseInfo, bootstrapKey)
+		return err
+	}); err != nil || done {
+		return err
+	}
+
+	ptx, err := r.primary.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer ptx.Rollback()
+	head, err := stages.GetStageProgress(ptx, stages.Execution)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Info("[replica] bootstrap from primary", "block", head)
+	if err := r.downloadSnapshots(ctx, blockReader, agg); err != nil {
+		return err
+	}
+	frozen := blockReader.FrozenBlocks()
+	if frozen > head {
+		return fmt.Errorf("primary executed blocks up to %d, behind the downloaded files (%d): bootstrap once it catches up", head, frozen)
+	}
+	if err := r.copyChain(ctx, ptx, frozen+1, head); err != nil {
+		return err
+	}
+	for _, table := range stateTables {
+		if err := r.copyTable(ctx, ptx, table); err != nil {
+			return fmt.Errorf("copy %s: %w", table, err)
+		}
+	}
+
+	return r.db.Update(ctx, func(tx kv.RwTx) error {
+		pm, err := prune.Get(tx)
+		if err != nil {
+			return err
+		}
+		window := prune.Distance(r.cfg.History)
+		for _, amount := range []*prune.BlockAmount{&pm.History, &pm.Receipts, &pm.CallTraces} {
+			if !(*amount).Enabled() || (*amount).PruneTo(head) < window.PruneTo(head) {
+				*amount = window
+			}
+		}
+		if err := prune.Override(tx, pm); err != nil {
+			return err
+		}
+		historyFrom := window.PruneTo(head)
+		if err := copyHistory(tx, ptx, historyFrom, head); err != nil {
+			return err
+		}
+
+		if err := r.setHead(tx, head); err != nil {
+			return err
+		}
+		for _, stage := range []stages.SyncStage{stages.Execution, stages.HashState, stages.IntermediateHashes} {
+			if err := stages.SaveStageProgress(tx, stage, head); err != nil {
+				return err
+			}
+		}
+		// the indices of the history window are built by the first run of the stages
+		for _, stage := range []stages.SyncStage{stages.CallTraces, stages.AccountHistoryIndex, stages.StorageHistoryIndex, stages.LogIndex} {
+			if err := stages.SaveStageProgress(tx, stage, max(historyFrom, 1)-1); err != nil {
+				return err
+			}
+		}
+		if err := copyMetadata(tx, ptx); err != nil {
+			return err
+		}
+		return tx.Put(kv.DatabaseInfo, bootstrapKey, hexutility.EncodeTs(head))
+	})
+}
+
+// downloadSnapshots downloads the files of the chain known to the downloader, like the Snapshots stage of a node
+// syncing from scratch does
+func (r *Replica) downloadSnapshots(ctx context.Context, blockReader services.FullBlockReader, agg *libstate.Aggregator) error {
+	var cc *chain.Config
+	if err := r.db.View(ctx, func(tx kv.Tx) (err error) {
+		cc, err = readChainConfig(tx)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	var downloader proto_downloader.DownloaderClient
+	if !blockReader.FreezingCfg().NoDownloader {
+		var closeDownloader func()
+		var err error
+		if downloader, closeDownloader, err = r.newDownloader(ctx, cc.ChainName); err != nil {
+			return fmt.Errorf("start downloader: %w", err)
+		}
+		defer closeDownloader()
+	}
+	return r.db.Update(ctx, func(tx kv.RwTx) error {
+		if err := snapshotsync.WaitForDownloader(ctx, "replica", false, false, snapshotsync.NoCaplin, agg, tx, blockReader, cc, downloader, nil); err != nil {
+			return err
+		}
+		return stagedsync.FillDBFromSnapshots("replica", ctx, tx, r.dirs, blockReader, agg, r.logger)
+	})
+}
+
+func readChainConfig(tx kv.Tx) (*chain.Config, error) {
+	genesisHash, err := rawdb.ReadCanonicalHash(tx, 0)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := rawdb.ReadChainConfig(tx, genesisHash)
+	if err != nil {
+		return nil, err
+	}
+	if cc == nil {
+		return nil, errors.New("chain config not found in replica db")
+	}
+	return cc, nil
+}
+
+// Start builds the stages maintaining the commitment and the indices of the replica and starts following the
+// primary in background.
+func (r *Replica) Start(ctx context.Context, blockReader services.FullBlockReader, agg *libstate.Aggregator) error {
+	if err := r.setup(ctx, blockReader, agg); err != nil {
+		return err
+	}
+	go r.Run(ctx)
+	return nil
+}
+
+func (r *Replica) setup(ctx context.Context, blockReader services.FullBlockReader, agg *libstate.Aggregator) error {
+	var cc *chain.Config
+	var pm prune.Mode
+	var historyV3 bool
+	if err := r.db.View(ctx, func(tx kv.Tx) (err error) {
+		if cc, err = readChainConfig(tx); err != nil {
+			return err
+		}
+		if historyV3, err = kvcfg.HistoryV3.Enabled(tx); err != nil {
+			return err
+		}
+		pm, err = prune.Get(tx)
+		return err
+	}); err != nil {
+		return err
+	}
+	if historyV3 { // a replica initialized before history v3 was refused
+		return ErrHistoryV3
+	}
+
+	r.blockReader = blockReader
+	r.sync = stagedsync.New(
+		ethconfig.Defaults.Sync,
+		stagedsync.ReplicaStages(ctx,
+			stagedsync.StageExecuteBlocksCfg(r.db, pm, 0, nil, cc, nil, &vm.Config{}, nil, false, true, false, r.dirs, blockReader, nil, nil, ethconfig.Defaults.Sync, agg, nil),
+			stagedsync.StageHashStateCfg(r.db, r.dirs, false),
+			stagedsync.StageTrieCfg(r.db, true, true, true, r.dirs.Tmp, blockReader, nil, false, agg),
+			stagedsync.StageHistoryCfg(r.db, pm, r.dirs.Tmp),
+			stagedsync.StageLogIndexCfg(r.db, pm, r.dirs.Tmp, nil, agg),
+			stagedsync.StageCallTracesCfg(r.db, pm, 0, r.dirs.Tmp),
+			stagedsync.StageTxLookupCfg(r.db, pm, r.dirs.Tmp, cc.Bor, blockReader),
+			stagedsync.StageFinishCfg(r.db, r.dirs.Tmp, nil),
+		),
+		stagedsync.ReplicaUnwindOrder,
+		stagedsync.ReplicaPruneOrder,
+		r.logger,
+	)
+	return nil
+}
+
+// Run follows the primary until the context is cancelled
+func (r *Replica) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if err := r.follow(ctx); err != nil {
+			if !grpcutil.IsRetryLater(err) && !grpcutil.IsEndOfStream(err) && !errors.Is(err, context.Canceled) {
+				r.logger.Warn("[replica] follow primary", "err", err)
+			}
+			time.Sleep(3 * time.Second)
+		}
+	}
+}
+
+func (r *Replica) follow(ctx context.Context) error {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := r.client.StateChanges(streamCtx, &remote.StateChangeRequest{WithStorage: true}, grpc.WaitForReady(true))
+	if err != nil {
+		return err
+	}
+	// changes committed before the subscription are not streamed
+	if err := r.resync(ctx); err != nil {
+		return err
+	}
+	for batch, err := stream.Recv(); ; batch, err = stream.Recv() {
+		if err != nil {
+			return err
+		}
+		if batch == nil {
+			return nil
+		}
+		if err := r.onBatch(ctx, batch); err != nil {
+			return err
+		}
+	}
+}
+
+// onBatch applies the blocks of a batch, falling back to a resync when the batch doesn't extend the replica
+func (r *Replica) onBatch(ctx context.Context, batch *remote.StateChangeBatch) error {
+	var needResync bool
+	if err := r.db.Update(ctx, func(tx kv.RwTx) error {
+		ptx, err := r.primary.BeginRo(ctx)
+		if err != nil {
+			return err
+		}
+		defer ptx.Rollback()
+
+		for _, change := range batch.ChangeBatch {
+			head, err := stages.GetStageProgress(tx, stages.Senders)
+			if err != nil {
+				return err
+			}
+			replicaPrimaryBlock.SetUint64(change.BlockHeight)
+			switch {
+			case change.Direction == remote.Direction_UNWIND:
+				if change.BlockHeight < head {
+					if err := r.unwind(ctx, tx, change.BlockHeight); err != nil {
+						return err
+					}
+				}
+			case change.BlockHeight <= head:
+				hash, err := rawdb.ReadCanonicalHash(tx, change.BlockHeight)
+				if err != nil {
+					return err
+				}
+				needResync = hash != gointerfaces.ConvertH256ToHash(change.BlockHash)
+			case change.BlockHeight == head+1:
+				err = r.forward(ctx, tx, ptx, change)
+				if errors.Is(err, errNotContiguous) {
+					needResync = true
+				} else if err != nil {
+					return err
+				}
+			default:
+				needResync = true
+			}
+			if needResync {
+				break
+			}
+		}
+		return r.finish(tx, ptx)
+	}); err != nil {
+		return err
+	}
+	if needResync {
+		return r.resync(ctx)
+	}
+	return nil
+}
+
+// forward applies the state diffs of the block following the head of the replica and copies its block data
+func (r *Replica) forward(ctx context.Context, tx kv.RwTx, ptx kv.Tx, change *remote.StateChange) error {
+	number, hash := change.BlockHeight, libcommon.Hash(gointerfaces.ConvertH256ToHash(change.BlockHash))
+	primaryHash, err := rawdb.ReadCanonicalHash(ptx, number)
+	if err != nil {
+		return err
+	}
+	if primaryHash != hash { // the primary moved to another fork since
+		return errNotContiguous
+	}
+	headHash, err := rawdb.ReadCanonicalHash(tx, number-1)
+	if err != nil {
+		return err
+	}
+	if header := rawdb.ReadHeader(ptx, hash, number); header == nil || header.ParentHash != headHash {
+		return errNotContiguous
+	}
+
+	if err := applyChanges(tx, change.Changes); err != nil {
+		return fmt.Errorf("apply state changes of block %d: %w", number, err)
+	}
+	if err := r.copyBlocks(ctx, tx, ptx, number, number); err != nil {
+		return err
+	}
+	if err := copyHistory(tx, ptx, number, number); err != nil {
+		return err
+	}
+	return r.setHead(tx, number)
+}
+
+// unwind reverts the replica to the given block, the state is restored from its changesets
+func (r *Replica) unwind(ctx context.Context, tx kv.RwTx, to uint64) error {
+	r.sync.UnwindTo(to, stagedsync.StagedUnwind)
+	if err := r.sync.RunUnwind(r.db, wrap.TxContainer{Tx: tx}); err != nil {
+		return err
+	}
+	if err := r.truncate(ctx, tx, to+1); err != nil {
+		return err
+	}
+	return r.setHead(tx, to)
+}
+
+// resync catches up with the primary without the stream: it unwinds the replica to the last block it shares with
+// the primary and copies the state changed since from the primary.
+func (r *Replica) resync(ctx context.Context) error {
+	return r.db.Update(ctx, func(tx kv.RwTx) error {
+		ptx, err := r.primary.BeginRo(ctx)
+		if err != nil {
+			return err
+		}
+		defer ptx.Rollback()
+
+		head, err := stages.GetStageProgress(tx, stages.Senders)
+		if err != nil {
+			return err
+		}
+		target, err := stages.GetStageProgress(ptx, stages.Execution)
+		if err != nil {
+			return err
+		}
+		replicaPrimaryBlock.SetUint64(target)
+
+		ancestor := min(head, target)
+		for ; ancestor > 0; ancestor-- {
+			hash, err := r.blockReader.CanonicalHash(ctx, tx, ancestor)
+			if err != nil {
+				return err
+			}
+			primaryHash, err := r.blockReader.CanonicalHash(ctx, ptx, ancestor)
+			if err != nil {
+				return err
+			}
+			if hash == primaryHash {
+				break
+			}
+		}
+		if ancestor == head && ancestor == target {
+			return r.finish(tx, ptx)
+		}
+
+		r.logger.Info("[replica] resync with primary", "head", head, "ancestor", ancestor, "primary", target)
+		if ancestor < head {
+			if err := r.unwind(ctx, tx, ancestor); err != nil {
+				return err
+			}
+		}
+		if ancestor < target {
+			if err := r.catchUp(ctx, tx, ptx, ancestor, target); err != nil {
+				return err
+			}
+		}
+		return r.finish(tx, ptx)
+	})
+}
+
+// finish builds the commitment and the indices of the applied blocks, prunes the history older than the window
+// of the replica and mirrors the chain metadata of the primary
+func (r *Replica) finish(tx kv.RwTx, ptx kv.Tx) error {
+	if err := r.sync.RunNoInterrupt(r.db, wrap.TxContainer{Tx: tx}, false); err != nil {
+		return err
+	}
+	if err := r.sync.RunPrune(r.db, tx, false); err != nil {
+		return err
+	}
+	if err := copyMetadata(tx, ptx); err != nil {
+		return err
+	}
+	head, err := stages.GetStageProgress(tx, stages.Execution)
+	if err != nil {
+		return err
+	}
+	replicaHeadBlock.SetUint64(head)
+	return nil
+}
@@ -0,0 +1,298 @@
+package replica
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcfg"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/kv/temporal/historyv2"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/ledgerwatch/erigon/common/u256"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/shards"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+type testClient struct{}
+
+func (c *testClient) StateChanges(ctx context.Context, in *remote.StateChangeRequest, opts ...grpc.CallOption) (remote.KV_StateChangesClient, error) {
+	panic("not used")
+}
+
+// testBackend serves the blocks of a chain the way the backend of the primary does
+type testBackend struct {
+	remote.ETHBACKENDClient
+	blocks []*types.Block
+	sender libcommon.Address
+}
+
+func (b *testBackend) Block(ctx context.Context, in *remote.BlockRequest, opts ...grpc.CallOption) (*remote.BlockReply, error) {
+	for _, block := range b.blocks {
+		if block.Hash() != gointerfaces.ConvertH256ToHash(in.BlockHash) {
+			continue
+		}
+		blockRlp, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			return nil, err
+		}
+		senders := make([]byte, 0, length.Addr*block.Transactions().Len())
+		for range block.Transactions() {
+			senders = append(senders, b.sender[:]...)
+		}
+		return &remote.BlockReply{BlockRlp: blockRlp, Senders: senders}, nil
+	}
+	return nil, fmt.Errorf("block %d not found", in.BlockHeight)
+}
+
+// newReplica returns an initialized replica of the primary, with a block reader over its empty snapshots dir
+func newReplica(t *testing.T, m *mock.MockSentry, backend *testBackend, cfg Config) (*Replica, services.FullBlockReader) {
+	t.Helper()
+	dirs := datadir.New(t.TempDir())
+	r := New(memdb.NewTestDB(t), m.DB, &testClient{}, backend, dirs, cfg, log.New())
+	require.NoError(t, r.Init(context.Background()))
+	freezing := ethconfig.BlocksFreezing{Enabled: true, NoDownloader: true}
+	return r, freezeblocks.NewBlockReader(freezeblocks.NewRoSnapshots(freezing, dirs.Snap, 0, log.New()), freezeblocks.NewBorRoSnapshots(freezing, dirs.Snap, 0, log.New()))
+}
+
+// initCode stores 42 in slot 0 and deploys a contract made of a single STOP
+var initCode = libcommon.FromHex("0x602a60005560016000f3")
+
+func dump(t *testing.T, db kv.RoDB, table string) map[string]string {
+	t.Helper()
+	res := map[string]string{}
+	require.NoError(t, db.View(context.Background(), func(tx kv.Tx) error {
+		return tx.ForEach(table, nil, func(k, v []byte) error {
+			res[string(k)] += string(v)
+			return nil
+		})
+	}))
+	return res
+}
+
+func head(t *testing.T, db kv.RoDB) (number uint64, hash libcommon.Hash) {
+	t.Helper()
+	require.NoError(t, db.View(context.Background(), func(tx kv.Tx) (err error) {
+		if number, err = stages.GetStageProgress(tx, stages.Execution); err != nil {
+			return err
+		}
+		hash = rawdb.ReadHeadBlockHash(tx)
+		return nil
+	}))
+	return number, hash
+}
+
+// stateChange builds the change the primary streams for its head block from its changesets
+func stateChange(t *testing.T, m *mock.MockSentry, block *types.Block) *remote.StateChange {
+	t.Helper()
+	acc := shards.NewAccumulator()
+	acc.StartChange(block.NumberU64(), block.Hash(), nil, false)
+	require.NoError(t, m.DB.View(context.Background(), func(tx kv.Tx) error {
+		if err := historyv2.ForPrefix(tx, kv.AccountChangeSet, hexutility.EncodeTs(block.NumberU64()), func(_ uint64, k, original []byte) error {
+			address := libcommon.BytesToAddress(k)
+			v, err := tx.GetOne(kv.PlainState, k)
+			if err != nil {
+				return err
+			}
+			if len(v) == 0 {
+				acc.DeleteAccount(address)
+				return nil
+			}
+			var account accounts.Account
+			if err := account.DecodeForStorage(v); err != nil {
+				return err
+			}
+			acc.ChangeAccount(address, account.Incarnation, v)
+			if len(original) == 0 && account.Incarnation > 0 { // created by the block
+				code, err := tx.GetOne(kv.Code, account.CodeHash[:])
+				if err != nil {
+					return err
+				}
+				acc.ChangeCode(address, account.Incarnation, code)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return historyv2.ForPrefix(tx, kv.StorageChangeSet, hexutility.EncodeTs(block.NumberU64()), func(_ uint64, k, _ []byte) error {
+			v, err := tx.GetOne(kv.PlainState, k)
+			if err != nil {
+				return err
+			}
+			location := libcommon.BytesToHash(k[length.Addr+length.Incarnation:])
+			acc.ChangeStorage(libcommon.BytesToAddress(k[:length.Addr]), 1, location, v)
+			return nil
+		})
+	}))
+	batch := &remote.StateChangeBatch{}
+	acc.SendAndReset(context.Background(), consumerFunc(func(sc *remote.StateChangeBatch) { batch = sc }), 0, 0, 0, 0)
+	return batch.ChangeBatch[0]
+}
+
+type consumerFunc func(sc *remote.StateChangeBatch)
+
+func (f consumerFunc) SendStateChanges(_ context.Context, sc *remote.StateChangeBatch) { f(sc) }
+
+func TestReplicaFollowsPrimary(t *testing.T) {
+	defer func(size int) { pageSize = size }(pageSize)
+	pageSize = 2 // split tables and values of DupSort keys between pages
+
+	ctx := context.Background()
+	m := mock.Mock(t)
+	signer := types.LatestSignerForChainID(m.ChainConfig.ChainID)
+	generate := func(n int, forkAt int) *core.ChainPack {
+		chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, n, func(i int, gen *core.BlockGen) {
+			if i >= forkAt {
+				gen.SetCoinbase(libcommon.Address{0xfe})
+				return
+			}
+			for j := 0; j < 3; j++ {
+				tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(m.Address), libcommon.Address{byte(j + 1)}, uint256.NewInt(10_000), params.TxGas, u256.Num1, nil), *signer, m.Key)
+				require.NoError(t, err)
+				gen.AddTx(tx)
+			}
+			if i == 3 {
+				tx, err := types.SignTx(types.NewContractCreation(gen.TxNonce(m.Address), u256.Num0, 100_000, u256.Num1, initCode), *signer, m.Key)
+				require.NoError(t, err)
+				gen.AddTx(tx)
+			}
+		})
+		require.NoError(t, err)
+		return chain
+	}
+	chain, fork := generate(6, 6), generate(7, 4)
+	require.Equal(t, chain.Blocks[3].Hash(), fork.Blocks[3].Hash())
+
+	require.NoError(t, m.InsertChain(chain.Slice(0, 3)))
+	r, blockReader := newReplica(t, m, &testBackend{}, DefaultConfig)
+	require.NoError(t, r.Bootstrap(ctx, blockReader, nil))
+	require.NoError(t, r.setup(ctx, blockReader, nil))
+	number, hash := head(t, r.db)
+	require.Equal(t, uint64(3), number)
+	require.Equal(t, chain.Blocks[2].Hash(), hash)
+	require.Equal(t, dump(t, m.DB, kv.PlainState), dump(t, r.db, kv.PlainState))
+	require.Equal(t, dump(t, m.DB, kv.AccountChangeSet), dump(t, r.db, kv.AccountChangeSet))
+
+	// block 4 is applied from its state diffs, the stages check its state root
+	require.NoError(t, m.InsertChain(chain.Slice(3, 4)))
+	change := stateChange(t, m, chain.Blocks[3])
+	require.NoError(t, r.onBatch(ctx, &remote.StateChangeBatch{ChangeBatch: []*remote.StateChange{change}}))
+	number, hash = head(t, r.db)
+	require.Equal(t, uint64(4), number)
+	require.Equal(t, chain.Blocks[3].Hash(), hash)
+	for _, table := range []string{kv.PlainState, kv.PlainContractCode, kv.Code, kv.Receipts, kv.StorageChangeSet} {
+		require.Equal(t, dump(t, m.DB, table), dump(t, r.db, table), table)
+	}
+	require.NoError(t, r.db.View(ctx, func(tx kv.Tx) error {
+		entry, err := rawdb.ReadTxLookupEntry(tx, chain.Blocks[3].Transactions()[0].Hash())
+		require.NotNil(t, entry)
+		require.Equal(t, uint64(4), *entry)
+		return err
+	}))
+	stateAt4 := dump(t, r.db, kv.PlainState)
+
+	// a gap in the stream is filled from the changesets of the primary
+	require.NoError(t, m.InsertChain(chain.Slice(4, 6)))
+	last := &remote.StateChange{BlockHeight: 6, BlockHash: gointerfaces.ConvertHashToH256(chain.Blocks[5].Hash())}
+	require.NoError(t, r.onBatch(ctx, &remote.StateChangeBatch{ChangeBatch: []*remote.StateChange{last}}))
+	number, hash = head(t, r.db)
+	require.Equal(t, uint64(6), number)
+	require.Equal(t, chain.Blocks[5].Hash(), hash)
+	require.Equal(t, dump(t, m.DB, kv.PlainState), dump(t, r.db, kv.PlainState))
+
+	// the primary reorgs to a longer fork: the replica unwinds, then resyncs on the next block
+	require.NoError(t, m.InsertChain(fork.Slice(4, 7)))
+	unwind := &remote.StateChange{Direction: remote.Direction_UNWIND, BlockHeight: 4, BlockHash: gointerfaces.ConvertHashToH256(chain.Blocks[3].Hash())}
+	require.NoError(t, r.onBatch(ctx, &remote.StateChangeBatch{ChangeBatch: []*remote.StateChange{unwind}}))
+	number, hash = head(t, r.db)
+	require.Equal(t, uint64(4), number)
+	require.Equal(t, chain.Blocks[3].Hash(), hash)
+	require.Equal(t, stateAt4, dump(t, r.db, kv.PlainState))
+
+	last = &remote.StateChange{BlockHeight: 7, BlockHash: gointerfaces.ConvertHashToH256(fork.TopBlock.Hash())}
+	require.NoError(t, r.onBatch(ctx, &remote.StateChangeBatch{ChangeBatch: []*remote.StateChange{last}}))
+	number, hash = head(t, r.db)
+	require.Equal(t, uint64(7), number)
+	require.Equal(t, fork.TopBlock.Hash(), hash)
+	for _, table := range []string{kv.PlainState, kv.HeaderCanonical, kv.AccountChangeSet} {
+		require.Equal(t, dump(t, m.DB, table), dump(t, r.db, table), table)
+	}
+}
+
+func TestBootstrapPrunedPrimary(t *testing.T) {
+	ctx := context.Background()
+	m := mock.Mock(t)
+	signer := types.LatestSignerForChainID(m.ChainConfig.ChainID)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 6, func(i int, gen *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(m.Address), libcommon.Address{1}, uint256.NewInt(10_000), params.TxGas, u256.Num1, nil), *signer, m.Key)
+		require.NoError(t, err)
+		gen.AddTx(tx)
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.InsertChain(chain))
+	// blocks 1-3 are only in the files of the primary
+	require.NoError(t, m.DB.Update(ctx, func(tx kv.RwTx) error { return rawdb.PruneBlocks(tx, 4, 100) }))
+
+	r, blockReader := newReplica(t, m, &testBackend{blocks: chain.Blocks, sender: m.Address}, Config{History: 2})
+	require.NoError(t, r.Bootstrap(ctx, blockReader, nil))
+	number, hash := head(t, r.db)
+	require.Equal(t, uint64(6), number)
+	require.Equal(t, chain.TopBlock.Hash(), hash)
+	require.Equal(t, dump(t, m.DB, kv.PlainState), dump(t, r.db, kv.PlainState))
+	require.NoError(t, r.db.View(ctx, func(tx kv.Tx) error {
+		for _, expected := range chain.Blocks {
+			block, senders, err := rawdb.ReadBlockWithSenders(tx, expected.Hash(), expected.NumberU64())
+			require.NoError(t, err)
+			require.NotNil(t, block, expected.NumberU64())
+			require.Equal(t, expected.Transactions()[0].Hash(), block.Transactions()[0].Hash())
+			require.Equal(t, []libcommon.Address{m.Address}, senders)
+		}
+
+		// only the history of the last blocks is copied, older one is pruned by the replica
+		pm, err := prune.Get(tx)
+		require.NoError(t, err)
+		require.Equal(t, prune.Distance(2), pm.History)
+		require.Equal(t, prune.Distance(2), pm.Receipts)
+		return tx.ForEach(kv.AccountChangeSet, nil, func(k, _ []byte) error {
+			require.GreaterOrEqual(t, binary.BigEndian.Uint64(k), uint64(4))
+			return nil
+		})
+	}))
+}
+
+func TestReplicaRefusesHistoryV3(t *testing.T) {
+	ctx := context.Background()
+	primary := memdb.NewTestDB(t)
+	require.NoError(t, primary.Update(ctx, func(tx kv.RwTx) error { return kvcfg.HistoryV3.ForceWrite(tx, true) }))
+
+	r := New(memdb.NewTestDB(t), primary, &testClient{}, &testBackend{}, datadir.New(t.TempDir()), DefaultConfig, log.New())
+	require.ErrorIs(t, r.Init(ctx), ErrHistoryV3)
+	require.NoError(t, r.db.View(ctx, func(tx kv.Tx) error {
+		enabled, err := kvcfg.HistoryV3.Enabled(tx)
+		require.False(t, enabled)
+		return err
+	}))
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/ledgerwatch/log/v3"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
@@ -32,15 +33,17 @@ var _ services.FullBlockReader = &RemoteBackend{}
 
 type RemoteBackend struct {
 	remoteEthBackend remote.ETHBACKENDClient
+	changeFeed       remote.CHANGEFEEDClient // nil when the node doesn't keep the change feed
 	log              log.Logger
 	version          gointerfaces.Version
 	db               kv.RoDB
 	blockReader      services.FullBlockReader
 }
 
-func NewRemoteBackend(client remote.ETHBACKENDClient, db kv.RoDB, blockReader services.FullBlockReader) *RemoteBackend {
+func NewRemoteBackend(client remote.ETHBACKENDClient, changeFeed remote.CHANGEFEEDClient, db kv.RoDB, blockReader services.FullBlockReader) *RemoteBackend {
 	return &RemoteBackend{
 		remoteEthBackend: client,
+		changeFeed:       changeFeed,
 		version:          gointerfaces.VersionFromProto(privateapi.EthBackendAPIVersion),
 		log:              log.New("remote_service", "eth_backend"),
 		db:               db,
@@ -243,6 +246,55 @@ func (back *RemoteBackend) SubscribeLogs(ctx context.Context, onNewLogs func(rep
 	return nil
 }
 
+var errChangeFeedDisabled = errors.New("change feed is not enabled on the node, restart it with --changefeed")
+
+func changeFeedError(err error) error {
+	if s, ok := status.FromError(err); ok {
+		if s.Code() == codes.Unimplemented {
+			return errChangeFeedDisabled
+		}
+		return errors.New(s.Message())
+	}
+	return err
+}
+
+func (back *RemoteBackend) ChangeFeedInfo(ctx context.Context, req *remote.ChangeFeedInfoRequest) (*remote.ChangeFeedInfoReply, error) {
+	if back.changeFeed == nil {
+		return nil, errChangeFeedDisabled
+	}
+	info, err := back.changeFeed.Info(ctx, req)
+	if err != nil {
+		return nil, changeFeedError(err)
+	}
+	return info, nil
+}
+
+// ChangeFeed calls onDiff for the diffs of the change feed from the requested position, until the context is
+// canceled or onDiff fails
+func (back *RemoteBackend) ChangeFeed(ctx context.Context, req *remote.ChangeFeedRequest, onDiff func(*remote.BlockDiff) error) error {
+	if back.changeFeed == nil {
+		return errChangeFeedDisabled
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := back.changeFeed.Diffs(ctx, req)
+	if err != nil {
+		return changeFeedError(err)
+	}
+	for {
+		diff, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return changeFeedError(err)
+		}
+		if err := onDiff(diff); err != nil {
+			return err
+		}
+	}
+}
+
 func (back *RemoteBackend) TxnLookup(ctx context.Context, tx kv.Getter, txnHash common.Hash) (uint64, bool, error) {
 	return back.blockReader.TxnLookup(ctx, tx, txnHash)
 }
@@ -44,6 +44,7 @@ type Worker struct {
 	chain    ChainReader
 
 	callTracer  *CallTracer
+	tracer      vm.EVMLogger // runs the transactions instead of callTracer while set
 	taskGasPool *core.GasPool
 
 	evm *vm.EVM
@@ -86,6 +87,13 @@ func NewWorker(lock sync.Locker, ctx context.Context, background bool, chainDb k
 
 func (rw *Worker) Tx() kv.Tx        { return rw.chainTx }
 func (rw *Worker) DiscardReadList() { rw.stateReader.DiscardReadList() }
+
+func (rw *Worker) CallTracer() *CallTracer           { return rw.callTracer }
+func (rw *Worker) StateReader() *state.StateReaderV3 { return rw.stateReader }
+
+// SetTracer runs the transactions under tracer, which must pass the events on to CallTracer. nil restores the
+// call tracer.
+func (rw *Worker) SetTracer(tracer vm.EVMLogger) { rw.tracer = tracer }
 func (rw *Worker) ResetTx(chainTx kv.Tx) {
 	if rw.background && rw.chainTx != nil {
 		rw.chainTx.Rollback()
@@ -156,7 +164,10 @@ func (rw *Worker) RunTxTaskNoLock(txTask *exec22.TxTask) {
 		syscall := func(contract libcommon.Address, data []byte, ibs *state.IntraBlockState, header *types.Header, constCall bool) ([]byte, error) {
 			return core.SysCallContract(contract, data, rw.chainConfig, ibs, header, rw.engine, constCall /* constCall */)
 		}
-		rw.engine.Initialize(rw.chainConfig, rw.chain, header, ibs, syscall, logger)
+		if err := rw.engine.Initialize(rw.chainConfig, rw.chain, header, ibs, syscall, logger); err != nil {
+			txTask.Error = err
+			break
+		}
 		txTask.Error = ibs.FinalizeTx(rules, noop)
 	case txTask.Final:
 		if txTask.BlockNum == 0 {
@@ -188,6 +199,9 @@ func (rw *Worker) RunTxTaskNoLock(txTask *exec22.TxTask) {
 		rw.callTracer.Reset()
 
 		vmConfig := vm.Config{Debug: true, Tracer: rw.callTracer, SkipAnalysis: txTask.SkipAnalysis}
+		if rw.tracer != nil {
+			vmConfig.Tracer = rw.tracer
+		}
 		ibs.SetTxContext(txHash, txTask.BlockHash, txTask.TxIndex)
 		msg := txTask.TxAsMessage
 
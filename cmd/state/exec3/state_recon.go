@@ -321,7 +321,11 @@ func (rw *ReconWorker) runTxTask(txTask *exec22.TxTask) error {
 			return core.SysCallContract(contract, data, rw.chainConfig, ibState, header, rw.engine, constCall /* constCall */)
 		}
 
-		rw.engine.Initialize(rw.chainConfig, rw.chain, txTask.Header, ibs, syscall, logger)
+		if err = rw.engine.Initialize(rw.chainConfig, rw.chain, txTask.Header, ibs, syscall, logger); err != nil {
+			if _, readError := rw.stateReader.ReadError(); !readError {
+				return fmt.Errorf("initialize of block %d failed: %w", txTask.BlockNum, err)
+			}
+		}
 		if err = ibs.FinalizeTx(rules, noop); err != nil {
 			if _, readError := rw.stateReader.ReadError(); !readError {
 				return err
@@ -64,6 +64,7 @@ var (
 	noTxGossipLegacy bool
 
 	commitEvery time.Duration
+	policyFile  string
 )
 
 func init() {
@@ -89,6 +90,7 @@ func init() {
 	rootCmd.PersistentFlags().Uint64Var(&priceBump, "txpool.pricebump", txpoolcfg.DefaultConfig.PriceBump, "Price bump percentage to replace an already existing transaction")
 	rootCmd.PersistentFlags().Uint64Var(&blobPriceBump, "txpool.blobpricebump", txpoolcfg.DefaultConfig.BlobPriceBump, "Price bump percentage to replace an existing blob (type-3) transaction")
 	rootCmd.PersistentFlags().DurationVar(&commitEvery, utils.TxPoolCommitEveryFlag.Name, utils.TxPoolCommitEveryFlag.Value, utils.TxPoolCommitEveryFlag.Usage)
+	rootCmd.PersistentFlags().StringVar(&policyFile, utils.TxPoolPolicyFileFlag.Name, utils.TxPoolPolicyFileFlag.Value, utils.TxPoolPolicyFileFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&optimism, "txpool.optimism", txpoolcfg.DefaultConfig.Optimism, "Enable Optimism Bedrock to make txpool account for L1 cost of transactions")
 	rootCmd.PersistentFlags().BoolVar(&noTxGossipLegacy, "txpool.disabletxpoolgossip", utils.TxPoolGossipDisableFlag.Value, "[Deprecated] Disable transaction pool gossip")
 	rootCmd.PersistentFlags().BoolVar(&noTxGossip, utils.TxPoolGossipDisableFlag.Name, utils.TxPoolGossipDisableFlag.Value, utils.TxPoolGossipDisableFlag.Usage)
@@ -160,6 +162,7 @@ func doTxpool(ctx context.Context, logger log.Logger) error {
 	cfg.PriceBump = priceBump
 	cfg.BlobPriceBump = blobPriceBump
 	cfg.NoGossip = noTxGossip
+	cfg.PolicyFile = policyFile
 
 	if noTxGossipLegacy && !noTxGossip {
 		logger.Warn("--txpool.disabletxpoolgossip flag is deprecated. use --txpool.gossip.disable")
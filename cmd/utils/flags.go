@@ -19,6 +19,8 @@ package utils
 
 import (
 	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -147,6 +149,18 @@ var (
 		Name:  "override.granite",
 		Usage: "Manually specify the Optimism Granite fork time, overriding the bundled setting",
 	}
+	OverrideRetroactiveForksFlag = cli.BoolFlag{
+		Name:  "superchain.override",
+		Usage: "Accept chain config updates which change forks that already activated on the local chain",
+	}
+	SuperchainRegistryFlag = cli.StringFlag{
+		Name:  "superchain-registry",
+		Usage: "Load OP stack chain configs from a superchain registry bundle (configs directory, .tar.gz archive or http(s) URL of one) instead of the bundled registry",
+	}
+	SuperchainRegistrySHA256Flag = cli.StringFlag{
+		Name:  "superchain-registry.sha256",
+		Usage: "Hex sha256 checksum the --superchain-registry archive must match. Required for a bundle fetched over plain http",
+	}
 	// Ethash settings
 	EthashCachesInMemoryFlag = cli.IntFlag{
 		Name:  "ethash.cachesinmem",
@@ -259,6 +273,11 @@ var (
 		Usage: "How often transactions should be committed to the storage",
 		Value: txpoolcfg.DefaultConfig.CommitEvery,
 	}
+	TxPoolPolicyFileFlag = cli.StringFlag{
+		Name:  "txpool.policies",
+		Usage: "TOML file with admission policies for the transaction pool (deny lists, gas caps per contract, minimum tips per type, max data availability size). It's reloaded when modified",
+		Value: "",
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -704,6 +723,14 @@ var (
 		Name:  "rollup.halt",
 		Usage: "Opt-in option to halt on incompatible protocol version requirements of the given level (major/minor/patch/none), as signaled through the Engine API by the rollup node",
 	}
+	RollupHaltGraceBlocksFlag = cli.Uint64Flag{
+		Name:  "rollup.halt.graceblocks",
+		Usage: "Number of blocks to keep running after the rollup node first signaled an unsupported required protocol version, before halting with --rollup.halt",
+	}
+	RollupHaltGracePeriodFlag = cli.DurationFlag{
+		Name:  "rollup.halt.graceperiod",
+		Usage: "Time to keep running after the rollup node first signaled an unsupported required protocol version, before halting with --rollup.halt",
+	}
 
 	// Metrics flags
 	MetricsEnabledFlag = cli.BoolFlag{
@@ -1047,6 +1074,11 @@ var (
 		Usage: "disable blob pruning in caplin",
 		Value: false,
 	}
+	CaplinBlobArchiveFlag = cli.BoolFlag{
+		Name:  "caplin.blobs.archive",
+		Usage: "keep all blob sidecars forever, moving old ones into snapshots, and index them by versioned hash",
+		Value: false,
+	}
 	CaplinArchiveFlag = cli.BoolFlag{
 		Name:  "caplin.archive",
 		Usage: "enables archival node in caplin",
@@ -1578,6 +1610,9 @@ func setTxPool(ctx *cli.Context, fullCfg *ethconfig.Config) {
 		fullCfg.TxPool.BlobPriceBump = ctx.Uint64(TxPoolBlobPriceBumpFlag.Name)
 	}
 	cfg.CommitEvery = common2.RandomizeDuration(ctx.Duration(TxPoolCommitEveryFlag.Name))
+	if ctx.IsSet(TxPoolPolicyFileFlag.Name) {
+		fullCfg.TxPool.PolicyFile = ctx.String(TxPoolPolicyFileFlag.Name)
+	}
 }
 
 func setEthash(ctx *cli.Context, datadir string, cfg *ethconfig.Config) {
@@ -1740,10 +1775,11 @@ func setBeaconAPI(ctx *cli.Context, cfg *ethconfig.Config) error {
 
 func setCaplin(ctx *cli.Context, cfg *ethconfig.Config) {
 	// Caplin's block's backfilling is enabled if any of the following flags are set
-	cfg.CaplinConfig.Backfilling = ctx.Bool(CaplinBackfillingFlag.Name) || ctx.Bool(CaplinArchiveFlag.Name) || ctx.Bool(CaplinBlobBackfillingFlag.Name)
+	cfg.CaplinConfig.Backfilling = ctx.Bool(CaplinBackfillingFlag.Name) || ctx.Bool(CaplinArchiveFlag.Name) || ctx.Bool(CaplinBlobBackfillingFlag.Name) || ctx.Bool(CaplinBlobArchiveFlag.Name)
 	// More granularity here.
-	cfg.CaplinConfig.BlobBackfilling = ctx.Bool(CaplinBlobBackfillingFlag.Name)
+	cfg.CaplinConfig.BlobBackfilling = ctx.Bool(CaplinBlobBackfillingFlag.Name) || ctx.Bool(CaplinBlobArchiveFlag.Name)
 	cfg.CaplinConfig.BlobPruningDisabled = ctx.Bool(CaplinDisableBlobPruningFlag.Name)
+	cfg.CaplinConfig.BlobArchive = ctx.Bool(CaplinBlobArchiveFlag.Name)
 	cfg.CaplinConfig.Archive = ctx.Bool(CaplinArchiveFlag.Name)
 }
 
@@ -1828,6 +1864,32 @@ func readGenesis(genesisPath string) *types.Genesis {
 	return genesis
 }
 
+// LoadSuperchainRegistry loads the superchain registry bundle given by --superchain-registry, if any.
+// It has to run before chain configs are looked up by name.
+func LoadSuperchainRegistry(ctx *cli.Context, logger log.Logger) {
+	location := ctx.String(SuperchainRegistryFlag.Name)
+	if location == "" {
+		return
+	}
+	var checksum []byte
+	if ctx.IsSet(SuperchainRegistrySHA256Flag.Name) {
+		var err error
+		checksum, err = hex.DecodeString(strings.TrimPrefix(ctx.String(SuperchainRegistrySHA256Flag.Name), "0x"))
+		if err != nil || len(checksum) != sha256.Size {
+			Fatalf("Invalid --%s: expected 32 hex encoded bytes", SuperchainRegistrySHA256Flag.Name)
+		}
+	}
+	chains, err := params.LoadSuperchainBundle(ctx.Context, location, checksum)
+	if err != nil {
+		Fatalf("Failed to load superchain registry: %v", err)
+	}
+	names := make([]string, 0, len(chains))
+	for _, c := range chains {
+		names = append(names, c.Chain+"-"+c.Superchain)
+	}
+	logger.Info("Loaded superchain registry bundle", "location", location, "chains", strings.Join(names, ","))
+}
+
 // SetEthConfig applies eth-related command line flags to the config.
 func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.Config, logger log.Logger) {
 	cfg.LightClientDiscoveryAddr = ctx.String(LightClientDiscoveryAddrFlag.Name)
@@ -1837,6 +1899,8 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	cfg.SentinelPort = ctx.Uint64(SentinelPortFlag.Name)
 	cfg.ForcePartialCommit = ctx.Bool(ForcePartialCommitFlag.Name)
 
+	LoadSuperchainRegistry(ctx, logger)
+
 	chain := GetChainNameFromFlag(ctx) // mainnet by default
 	if ctx.IsSet(NetworkIdFlag.Name) {
 		cfg.NetworkID = ctx.Uint64(NetworkIdFlag.Name)
@@ -2027,6 +2091,7 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	if ctx.IsSet(OverrideOptimismGraniteFlag.Name) {
 		cfg.OverrideOptimismGraniteTime = flags.GlobalBig(ctx, OverrideOptimismGraniteFlag.Name)
 	}
+	cfg.OverrideRetroactiveForks = ctx.Bool(OverrideRetroactiveForksFlag.Name)
 	if ctx.IsSet(InternalConsensusFlag.Name) && clparams.EmbeddedSupported(cfg.NetworkID) {
 		cfg.InternalCL = ctx.Bool(InternalConsensusFlag.Name)
 	}
@@ -2054,6 +2119,8 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 			logger.Warn("Ignoring incorrect value for --rollup.halt. Please specify a level from major/minor/patch/none.")
 		}
 	}
+	cfg.RollupHaltGraceBlocks = ctx.Uint64(RollupHaltGraceBlocksFlag.Name)
+	cfg.RollupHaltGracePeriod = ctx.Duration(RollupHaltGracePeriodFlag.Name)
 }
 
 // SetDNSDiscoveryDefaults configures DNS discovery with the given URL if
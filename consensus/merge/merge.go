@@ -273,16 +273,26 @@ func (s *Merge) IsServiceTransaction(sender libcommon.Address, syscall consensus
 
 func (s *Merge) Initialize(config *chain.Config, chain consensus.ChainHeaderReader, header *types.Header,
 	state *state.IntraBlockState, syscall consensus.SysCallCustom, logger log.Logger,
-) {
+) error {
 	if !misc.IsPoSHeader(header) {
-		s.eth1Engine.Initialize(config, chain, header, state, syscall, logger)
+		if err := s.eth1Engine.Initialize(config, chain, header, state, syscall, logger); err != nil {
+			return err
+		}
 	}
 	if chain.Config().IsCancun(header.Time) {
 		misc.ApplyBeaconRootEip4788(header.ParentBeaconBlockRoot, func(addr libcommon.Address, data []byte) ([]byte, error) {
 			return syscall(addr, data, state, header, false /* constCall */)
 		})
 	}
+	if config.IsPrague(header.Time) || (config.IsOptimismIsthmus(header.Time) && !misc.IsIsthmusActivationBlock(config, header.Time)) {
+		if err := misc.StoreBlockHashesEip2935(header.ParentHash, func(addr libcommon.Address, data []byte) ([]byte, error) {
+			return syscall(addr, data, state, header, false /* constCall */)
+		}); err != nil {
+			return err
+		}
+	}
 	misc.EnsureCreate2Deployer(config, header.Time, state)
+	return nil
 }
 
 func (s *Merge) APIs(chain consensus.ChainHeaderReader) []rpc.API {
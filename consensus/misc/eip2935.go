@@ -0,0 +1,26 @@
+package misc
+
+import (
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+// StoreBlockHashesEip2935 writes the parent block hash into the ring buffer of the history storage contract.
+// The call is a no-op until the contract is deployed: on L1 it is created by a regular transaction,
+// on OP chains by the network upgrade deposit transactions of the Isthmus activation block.
+func StoreBlockHashesEip2935(parentHash libcommon.Hash, syscall consensus.SystemCall) error {
+	if _, err := syscall(params.HistoryStorageAddress, parentHash.Bytes()); err != nil {
+		return fmt.Errorf("history storage contract call: %w", err)
+	}
+	return nil
+}
+
+// IsIsthmusActivationBlock reports whether the block is the first one of Isthmus, the history storage contract only
+// exists after its upgrade transactions.
+func IsIsthmusActivationBlock(c *chain.Config, timestamp uint64) bool {
+	return c.IsOptimism() && c.IsthmusTime != nil && c.IsthmusTime.Uint64() == timestamp
+}
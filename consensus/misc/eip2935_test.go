@@ -0,0 +1,65 @@
+package misc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsIsthmusActivationBlock(t *testing.T) {
+	isthmusTime := uint64(1000)
+	var tests = []struct {
+		name      string
+		override  func(cfg *chain.Config)
+		timestamp uint64
+		activates bool
+	}{
+		{
+			name:      "at hardfork",
+			timestamp: isthmusTime,
+			activates: true,
+		},
+		{
+			name:      "pre isthmus",
+			timestamp: isthmusTime - 1,
+			activates: false,
+		},
+		{
+			name:      "post hardfork",
+			timestamp: isthmusTime + 1,
+			activates: false,
+		},
+		{
+			name: "isthmus not configured",
+			override: func(cfg *chain.Config) {
+				cfg.IsthmusTime = nil
+			},
+			timestamp: isthmusTime,
+			activates: false,
+		},
+		{
+			name: "not optimism",
+			override: func(cfg *chain.Config) {
+				cfg.Optimism = nil
+			},
+			timestamp: isthmusTime,
+			activates: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := chain.Config{
+				ChainID:     big.NewInt(params.OPMainnetChainID),
+				Optimism:    &chain.OptimismConfig{},
+				IsthmusTime: big.NewInt(int64(isthmusTime)),
+			}
+			if tt.override != nil {
+				tt.override(&cfg)
+			}
+			assert.Equal(t, tt.activates, IsIsthmusActivationBlock(&cfg, tt.timestamp))
+		})
+	}
+}
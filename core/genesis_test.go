@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
 	"github.com/ledgerwatch/erigon-lib/chain/networkname"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
@@ -19,9 +20,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/trie"
 	"github.com/ledgerwatch/log/v3"
 )
 
@@ -36,7 +39,7 @@ func TestGenesisBlockHashes(t *testing.T) {
 			t.Fatal(err)
 		}
 		defer tx.Rollback()
-		_, block, err := core.WriteGenesisBlock(tx, genesis, nil, nil, nil, nil, nil, nil, nil, "", logger)
+		_, block, err := core.WriteGenesisBlock(tx, genesis, nil, nil, nil, nil, nil, nil, nil, false, "", logger)
 		require.NoError(t, err)
 		expect := params.GenesisHashByChainName(network)
 		require.NotNil(t, expect, network)
@@ -85,19 +88,57 @@ func TestCommitGenesisIdempotency(t *testing.T) {
 	defer tx.Rollback()
 
 	genesis := core.GenesisBlockByChainName(networkname.MainnetChainName)
-	_, _, err = core.WriteGenesisBlock(tx, genesis, nil, nil, nil, nil, nil, nil, nil, "", logger)
+	_, _, err = core.WriteGenesisBlock(tx, genesis, nil, nil, nil, nil, nil, nil, nil, false, "", logger)
 	require.NoError(t, err)
 	seq, err := tx.ReadSequence(kv.EthTx)
 	require.NoError(t, err)
 	require.Equal(t, uint64(2), seq)
 
-	_, _, err = core.WriteGenesisBlock(tx, genesis, nil, nil, nil, nil, nil, nil, nil, "", logger)
+	_, _, err = core.WriteGenesisBlock(tx, genesis, nil, nil, nil, nil, nil, nil, nil, false, "", logger)
 	require.NoError(t, err)
 	seq, err = tx.ReadSequence(kv.EthTx)
 	require.NoError(t, err)
 	require.Equal(t, uint64(2), seq)
 }
 
+func TestOptimismRetroactiveForkChange(t *testing.T) {
+	t.Parallel()
+	logger := log.New()
+	_, db, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	tx, err := db.BeginRw(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	opGenesis := func(ecotone, fjord int64) *types.Genesis {
+		config := *params.TestChainConfig
+		config.BedrockBlock = big.NewInt(0)
+		config.RegolithTime = big.NewInt(0)
+		config.EcotoneTime = big.NewInt(ecotone)
+		config.FjordTime = big.NewInt(fjord)
+		config.Optimism = &chain.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: 250}
+		return &types.Genesis{Config: &config, GasLimit: 30_000_000, Difficulty: big.NewInt(0), Alloc: types.GenesisAlloc{}}
+	}
+	_, genesisBlock, err := core.WriteGenesisBlock(tx, opGenesis(100, 200), nil, nil, nil, nil, nil, nil, nil, false, "", logger)
+	require.NoError(t, err)
+
+	// the local chain is at time 150: ecotone activated, fjord did not
+	head := &types.Header{ParentHash: genesisBlock.Hash(), Number: big.NewInt(1), Time: 150, Difficulty: big.NewInt(0)}
+	require.NoError(t, rawdb.WriteHeader(tx, head))
+	require.NoError(t, rawdb.WriteCanonicalHash(tx, head.Hash(), 1))
+	rawdb.WriteHeadHeaderHash(tx, head.Hash())
+
+	cfg, _, err := core.WriteGenesisBlock(tx, opGenesis(100, 300), nil, nil, nil, nil, nil, nil, nil, false, "", logger)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(300), cfg.FjordTime)
+
+	_, _, err = core.WriteGenesisBlock(tx, opGenesis(120, 300), nil, nil, nil, nil, nil, nil, nil, false, "", logger)
+	require.ErrorIs(t, err, core.ErrRetroactiveForkChange)
+
+	cfg, _, err = core.WriteGenesisBlock(tx, opGenesis(120, 300), nil, nil, nil, nil, nil, nil, nil, true, "", logger)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(120), cfg.EcotoneTime)
+}
+
 func TestAllocConstructor(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -154,3 +195,15 @@ func TestDecodeBalance0(t *testing.T) {
 	require.NoError(t, err)
 	_ = genesisData
 }
+
+func TestIsthmusGenesisHistoryStorage(t *testing.T) {
+	t.Parallel()
+	config := *params.AllProtocolChanges
+	config.Optimism = &chain.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+	config.IsthmusTime = big.NewInt(0)
+
+	// the history storage contract comes from the genesis alloc or the upgrade transactions, never from the node
+	block, _, err := core.GenesisToBlock(&types.Genesis{Config: &config}, "", log.Root())
+	require.NoError(t, err)
+	require.Equal(t, trie.EmptyRoot, block.Root())
+}
@@ -26,6 +26,7 @@ import (
 	"math/big"
 	"reflect"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/c2h5oh/datasize"
@@ -68,16 +69,16 @@ import (
 //
 // The returned chain configuration is never nil.
 func CommitGenesisBlock(db kv.RwDB, genesis *types.Genesis, tmpDir string, logger log.Logger) (*chain.Config, *types.Block, error) {
-	return CommitGenesisBlockWithOverride(db, genesis, nil, nil, nil, nil, nil, nil, nil, tmpDir, logger)
+	return CommitGenesisBlockWithOverride(db, genesis, nil, nil, nil, nil, nil, nil, nil, false, tmpDir, logger)
 }
 
-func CommitGenesisBlockWithOverride(db kv.RwDB, genesis *types.Genesis, overrideCancunTime, overrideShanghaiTime, overrideOptimismCanyonTime, overrideOptimismEcotoneTime, overrideOptimismFjordTime, overrideOptimismGraniteTime, overridePragueTime *big.Int, tmpDir string, logger log.Logger) (*chain.Config, *types.Block, error) {
+func CommitGenesisBlockWithOverride(db kv.RwDB, genesis *types.Genesis, overrideCancunTime, overrideShanghaiTime, overrideOptimismCanyonTime, overrideOptimismEcotoneTime, overrideOptimismFjordTime, overrideOptimismGraniteTime, overridePragueTime *big.Int, allowRetroactiveForks bool, tmpDir string, logger log.Logger) (*chain.Config, *types.Block, error) {
 	tx, err := db.BeginRw(context.Background())
 	if err != nil {
 		return nil, nil, err
 	}
 	defer tx.Rollback()
-	c, b, err := WriteGenesisBlock(tx, genesis, overrideCancunTime, overrideShanghaiTime, overrideOptimismCanyonTime, overrideOptimismEcotoneTime, overrideOptimismFjordTime, overrideOptimismGraniteTime, overridePragueTime, tmpDir, logger)
+	c, b, err := WriteGenesisBlock(tx, genesis, overrideCancunTime, overrideShanghaiTime, overrideOptimismCanyonTime, overrideOptimismEcotoneTime, overrideOptimismFjordTime, overrideOptimismGraniteTime, overridePragueTime, allowRetroactiveForks, tmpDir, logger)
 	if err != nil {
 		return c, b, err
 	}
@@ -88,7 +89,7 @@ func CommitGenesisBlockWithOverride(db kv.RwDB, genesis *types.Genesis, override
 	return c, b, nil
 }
 
-func WriteGenesisBlock(tx kv.RwTx, genesis *types.Genesis, overrideCancunTime, overrideShanghaiTime, overrideOptimismCanyonTime, overrideOptimismEcotoneTime, overrideOptimismFjordTime, overrideOptimismGraniteTime, overridePragueTime *big.Int, tmpDir string, logger log.Logger) (*chain.Config, *types.Block, error) {
+func WriteGenesisBlock(tx kv.RwTx, genesis *types.Genesis, overrideCancunTime, overrideShanghaiTime, overrideOptimismCanyonTime, overrideOptimismEcotoneTime, overrideOptimismFjordTime, overrideOptimismGraniteTime, overridePragueTime *big.Int, allowRetroactiveForks bool, tmpDir string, logger log.Logger) (*chain.Config, *types.Block, error) {
 	var storedBlock *types.Block
 	if genesis != nil && genesis.Config == nil {
 		return params.AllProtocolChanges, nil, types.ErrGenesisNoConfig
@@ -209,6 +210,9 @@ func WriteGenesisBlock(tx kv.RwTx, genesis *types.Genesis, overrideCancunTime, o
 	if newCfg.IsOptimism() {
 		if !reflect.DeepEqual(newCfg, storedCfg) {
 			log.Info("Update latest chain config from superchain registry")
+			if err := checkChainConfigUpdate(tx, storedCfg, newCfg, allowRetroactiveForks, logger); err != nil {
+				return newCfg, storedBlock, err
+			}
 		}
 		// rewrite using superchain config just in case
 		if err := rawdb.WriteChainConfig(tx, storedHash, newCfg); err != nil {
@@ -232,6 +236,35 @@ func WriteGenesisBlock(tx kv.RwTx, genesis *types.Genesis, overrideCancunTime, o
 	return newCfg, storedBlock, nil
 }
 
+// checkChainConfigUpdate reports how the new chain config differs from the stored one, and refuses
+// changes to forks which already activated on the local chain unless they are explicitly allowed.
+func checkChainConfigUpdate(tx kv.Tx, storedCfg, newCfg *chain.Config, allowRetroactiveForks bool, logger log.Logger) error {
+	var headNumber, headTime uint64
+	if head := rawdb.ReadCurrentHeader(tx); head != nil {
+		headNumber, headTime = head.Number.Uint64(), head.Time
+	}
+	changes, err := params.DiffChainConfigs(storedCfg, newCfg, headNumber, headTime)
+	if err != nil {
+		return err
+	}
+	var retroactive []string
+	for _, change := range changes {
+		switch {
+		case change.Retroactive && headNumber > 0:
+			logger.Warn("Chain config change of an activated fork", "change", change)
+			retroactive = append(retroactive, change.String())
+		case change.Fork:
+			logger.Info("Chain config fork change", "change", change)
+		default:
+			logger.Info("Chain config change", "change", change)
+		}
+	}
+	if len(retroactive) > 0 && !allowRetroactiveForks {
+		return fmt.Errorf("%w: %s", ErrRetroactiveForkChange, strings.Join(retroactive, ", "))
+	}
+	return nil
+}
+
 func WriteGenesisState(g *types.Genesis, tx kv.RwTx, tmpDir string, logger log.Logger) (*types.Block, *state.IntraBlockState, error) {
 	block, statedb, err := GenesisToBlock(g, tmpDir, logger)
 	if err != nil {
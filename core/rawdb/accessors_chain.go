@@ -1347,6 +1347,11 @@ func WriteSnapshots(tx kv.RwTx, list, histList []string) error {
 
 // PruneTable has `limit` parameter to avoid too large data deletes per one sync cycle - better delete by small portions to reduce db.FreeList size
 func PruneTable(tx kv.RwTx, table string, pruneTo uint64, ctx context.Context, limit int) error {
+	return PruneTableFrom(tx, table, 0, pruneTo, ctx, limit)
+}
+
+// PruneTableFrom is PruneTable which keeps the entries of blocks below pruneFrom
+func PruneTableFrom(tx kv.RwTx, table string, pruneFrom, pruneTo uint64, ctx context.Context, limit int) error {
 	c, err := tx.RwCursor(table)
 
 	if err != nil {
@@ -1355,7 +1360,7 @@ func PruneTable(tx kv.RwTx, table string, pruneTo uint64, ctx context.Context, l
 	defer c.Close()
 
 	i := 0
-	for k, _, err := c.First(); k != nil; k, _, err = c.Next() {
+	for k, _, err := c.Seek(hexutility.EncodeTs(pruneFrom)); k != nil; k, _, err = c.Next() {
 		if err != nil {
 			return err
 		}
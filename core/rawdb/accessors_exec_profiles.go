@@ -0,0 +1,75 @@
+package rawdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// WriteBlockExecutionProfile stores the profile of an executed block, dropping the profiles of blocks
+// more than `keep` blocks older so the table stays a bounded ring.
+func WriteBlockExecutionProfile(tx kv.RwTx, profile *diagnostics.BlockExecutionProfile, keep uint64) error {
+	v, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("failed to encode execution profile: %w", err)
+	}
+	if err := tx.Put(kv.BlockExecutionProfiles, hexutility.EncodeTs(profile.BlockNumber), v); err != nil {
+		return fmt.Errorf("failed to store execution profile: %w", err)
+	}
+	if profile.BlockNumber < keep {
+		return nil
+	}
+	c, err := tx.RwCursor(kv.BlockExecutionProfiles)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.First(); k != nil; k, _, err = c.First() {
+		if err != nil {
+			return err
+		}
+		if binary.BigEndian.Uint64(k) > profile.BlockNumber-keep {
+			break
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBlockExecutionProfiles returns the stored profiles of blocks in [from, to]. Blocks which were pruned
+// from the ring, or not executed by this node, are skipped.
+func ReadBlockExecutionProfiles(tx kv.Tx, from, to uint64) ([]*diagnostics.BlockExecutionProfile, error) {
+	var profiles []*diagnostics.BlockExecutionProfile
+	c, err := tx.Cursor(kv.BlockExecutionProfiles)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	for k, v, err := c.Seek(hexutility.EncodeTs(from)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return nil, err
+		}
+		if binary.BigEndian.Uint64(k) > to {
+			break
+		}
+		profile := &diagnostics.BlockExecutionProfile{}
+		if err := json.Unmarshal(v, profile); err != nil {
+			return nil, fmt.Errorf("invalid execution profile %x: %w", k, err)
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// TruncateBlockExecutionProfiles removes the profiles of the given block number or newer - used for Unwind
+func TruncateBlockExecutionProfiles(tx kv.RwTx, number uint64) error {
+	return tx.ForEach(kv.BlockExecutionProfiles, hexutility.EncodeTs(number), func(k, _ []byte) error {
+		return tx.Delete(kv.BlockExecutionProfiles, k)
+	})
+}
@@ -0,0 +1,31 @@
+package rawdb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+)
+
+func TestBlockExecutionProfilesRing(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	for i := uint64(1); i <= 10; i++ {
+		require.NoError(t, rawdb.WriteBlockExecutionProfile(tx, &diagnostics.BlockExecutionProfile{BlockNumber: i, GasUsed: i * 100}, 4))
+	}
+
+	profiles, err := rawdb.ReadBlockExecutionProfiles(tx, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, profiles, 4)
+	require.Equal(t, uint64(7), profiles[0].BlockNumber)
+	require.Equal(t, uint64(1000), profiles[3].GasUsed)
+
+	require.NoError(t, rawdb.TruncateBlockExecutionProfiles(tx, 9))
+	profiles, err = rawdb.ReadBlockExecutionProfiles(tx, 8, 100)
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	require.Equal(t, uint64(8), profiles[0].BlockNumber)
+}
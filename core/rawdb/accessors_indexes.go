@@ -17,9 +17,11 @@
 package rawdb
 
 import (
+	"encoding/binary"
 	"math/big"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/log/v3"
 
@@ -63,3 +65,51 @@ func WriteTxLookupEntries(db kv.Putter, block *types.Block) {
 func DeleteTxLookupEntry(db kv.Deleter, hash libcommon.Hash) error {
 	return db.Delete(kv.TxLookup, hash.Bytes())
 }
+
+// ReadLogAddressTopicIndexFrom returns the first block covered by the address+topic0 log index,
+// ok is false if the index was never built.
+func ReadLogAddressTopicIndexFrom(db kv.Getter) (from uint64, ok bool, err error) {
+	v, err := db.GetOne(kv.DatabaseInfo, kv.LogAddressTopicIndexFromKey)
+	if err != nil || len(v) != 8 {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(v), true, nil
+}
+
+// WriteLogAddressTopicIndexFrom stores the first block covered by the address+topic0 log index
+func WriteLogAddressTopicIndexFrom(db kv.Putter, from uint64) error {
+	return db.Put(kv.DatabaseInfo, kv.LogAddressTopicIndexFromKey, hexutility.EncodeTs(from))
+}
+
+// ReadLegacyStateBlocks returns the number of pre-Bedrock blocks in the legacy state DB, the state
+// after block n is available for n < blocks.
+func ReadLegacyStateBlocks(db kv.Getter) (uint64, error) {
+	v, err := db.GetOne(kv.DatabaseInfo, kv.LegacyStateBlocksKey)
+	if err != nil || len(v) != 8 {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// WriteLegacyStateBlocks stores the number of pre-Bedrock blocks in the legacy state DB
+func WriteLegacyStateBlocks(db kv.Putter, blocks uint64) error {
+	return db.Put(kv.DatabaseInfo, kv.LegacyStateBlocksKey, hexutility.EncodeTs(blocks))
+}
+
+// ReadLegacyReceiptsRange returns the range [from, to] of pre-Bedrock blocks whose receipts are imported,
+// ok is false if there are none. Receipts of every block with transactions in the range are in the DB.
+func ReadLegacyReceiptsRange(db kv.Getter) (from, to uint64, ok bool, err error) {
+	v, err := db.GetOne(kv.DatabaseInfo, kv.LegacyReceiptsRangeKey)
+	if err != nil || len(v) != 16 {
+		return 0, 0, false, err
+	}
+	return binary.BigEndian.Uint64(v), binary.BigEndian.Uint64(v[8:]), true, nil
+}
+
+// WriteLegacyReceiptsRange stores the range [from, to] of pre-Bedrock blocks whose receipts are imported
+func WriteLegacyReceiptsRange(db kv.Putter, from, to uint64) error {
+	v := make([]byte, 16)
+	binary.BigEndian.PutUint64(v, from)
+	binary.BigEndian.PutUint64(v[8:], to)
+	return db.Put(kv.DatabaseInfo, kv.LegacyReceiptsRangeKey, v)
+}
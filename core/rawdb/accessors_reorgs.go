@@ -0,0 +1,79 @@
+package rawdb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// WriteReorg appends the reorg to the reorg log and returns its id. Ids are increasing, so
+// the log is ordered by the time of the reorg. Only the latest `keep` reorgs are kept, older ones are dropped.
+func WriteReorg(tx kv.RwTx, reorg *types.Reorg, keep uint64) (uint64, error) {
+	v, err := rlp.EncodeToBytes(reorg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to RLP encode reorg: %w", err)
+	}
+	id, err := tx.IncrementSequence(kv.Reorgs, 1)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Put(kv.Reorgs, hexutility.EncodeTs(id), v); err != nil {
+		return 0, fmt.Errorf("failed to store reorg: %w", err)
+	}
+	if id < keep {
+		return id, nil
+	}
+	c, err := tx.RwCursor(kv.Reorgs)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	for k, _, err := c.First(); k != nil; k, _, err = c.First() {
+		if err != nil {
+			return 0, err
+		}
+		if binary.BigEndian.Uint64(k) > id-keep {
+			break
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// NextReorgID - id which will be given to the next recorded reorg
+func NextReorgID(tx kv.Tx) (uint64, error) {
+	return tx.ReadSequence(kv.Reorgs)
+}
+
+// ForEachReorg walks over the reorg log starting from the given id, or the oldest kept one, until the walker returns false
+func ForEachReorg(tx kv.Tx, fromID uint64, walker func(id uint64, reorg *types.Reorg) (bool, error)) error {
+	c, err := tx.Cursor(kv.Reorgs)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, v, err := c.Seek(hexutility.EncodeTs(fromID)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		reorg := &types.Reorg{}
+		if err := rlp.DecodeBytes(v, reorg); err != nil {
+			return fmt.Errorf("invalid reorg RLP %x: %w", k, err)
+		}
+		next, err := walker(binary.BigEndian.Uint64(k), reorg)
+		if err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package rawdb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+func TestReorgLogBounded(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	for i := uint64(0); i < 10; i++ {
+		id, err := rawdb.WriteReorg(tx, &types.Reorg{OldNumber: i + 1, AncestorNumber: i}, 4)
+		require.NoError(t, err)
+		require.Equal(t, i, id)
+	}
+
+	var ids []uint64
+	require.NoError(t, rawdb.ForEachReorg(tx, 0, func(id uint64, reorg *types.Reorg) (bool, error) {
+		require.Equal(t, id+1, reorg.OldNumber)
+		ids = append(ids, id)
+		return true, nil
+	}))
+	require.Equal(t, []uint64{6, 7, 8, 9}, ids)
+	next, err := rawdb.NextReorgID(tx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), next)
+}
@@ -0,0 +1,52 @@
+package rawdb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/params"
+)
+
+var superchainSignalKey = []byte("latest")
+
+// SuperchainSignal is the latest superchain protocol version signal received from the rollup node
+// through engine_signalSuperchainV1.
+type SuperchainSignal struct {
+	Recommended params.ProtocolVersion `json:"recommended"`
+	Required    params.ProtocolVersion `json:"required"`
+	Local       params.ProtocolVersion `json:"local"`
+	ReceivedAt  uint64                 `json:"receivedAt"` // unix time
+
+	// UpgradeRequired is set while the required version is ahead of the local one. The time and the
+	// head block of the first signal which required the upgrade are kept until it is done.
+	UpgradeRequired      bool   `json:"upgradeRequired"`
+	UpgradeRequiredSince uint64 `json:"upgradeRequiredSince,omitempty"` // unix time
+	UpgradeRequiredBlock uint64 `json:"upgradeRequiredBlock,omitempty"`
+}
+
+// ReadSuperchainSignal returns the latest stored signal, or nil if none was received yet.
+func ReadSuperchainSignal(db kv.Getter) (*SuperchainSignal, error) {
+	data, err := db.GetOne(kv.SuperchainSignal, superchainSignalKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	signal := &SuperchainSignal{}
+	if err := json.Unmarshal(data, signal); err != nil {
+		return nil, fmt.Errorf("invalid superchain signal: %w", err)
+	}
+	return signal, nil
+}
+
+// WriteSuperchainSignal replaces the stored signal.
+func WriteSuperchainSignal(db kv.Putter, signal *SuperchainSignal) error {
+	data, err := json.Marshal(signal)
+	if err != nil {
+		return fmt.Errorf("failed to encode superchain signal: %w", err)
+	}
+	return db.Put(kv.SuperchainSignal, superchainSignalKey, data)
+}
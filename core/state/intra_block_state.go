@@ -249,6 +249,14 @@ func (sdb *IntraBlockState) GetCodeSize(addr libcommon.Address) int {
 	return l
 }
 
+// GetDelegatedDesignation returns the delegate of the account if its code is an EIP-7702 delegation designator
+func (sdb *IntraBlockState) GetDelegatedDesignation(addr libcommon.Address) (libcommon.Address, bool) {
+	if sdb.GetCodeSize(addr) != types.DelegationDesignatorSize {
+		return libcommon.Address{}, false
+	}
+	return types.ParseDelegation(sdb.GetCode(addr))
+}
+
 // DESCRIBED: docs/programmers_guide/guide.md#address---identifier-of-an-account
 func (sdb *IntraBlockState) GetCodeHash(addr libcommon.Address) libcommon.Hash {
 	stateObject := sdb.getStateObject(addr)
@@ -688,6 +696,44 @@ func (sdb *IntraBlockState) FinalizeTx(chainRules *chain.Rules, stateWriter Stat
 	return nil
 }
 
+// SoftFinalizeTx finalizes a transaction like FinalizeTx, but without writing it nor clearing the journal: the state
+// can still be reverted to a snapshot taken before the transaction. FinalizeTx has to be called once it is final.
+func (sdb *IntraBlockState) SoftFinalizeTx(chainRules *chain.Rules) error {
+	ch := softFinalizeChange{
+		prevRefund:           sdb.refund,
+		prevAccessList:       sdb.accessList,
+		prevTransientStorage: sdb.transientStorage,
+		objects:              map[libcommon.Address]finalizedObject{},
+	}
+	for addr, bi := range sdb.balanceInc {
+		if !bi.transferred {
+			ch.loaded = append(ch.loaded, addr)
+			sdb.getStateObject(addr)
+		}
+	}
+	noop := NewNoopWriter()
+	for addr := range sdb.journal.dirties {
+		so, exist := sdb.stateObjects[addr]
+		if !exist {
+			continue
+		}
+		_, dirty := sdb.stateObjectsDirty[addr]
+		ch.objects[addr] = finalizedObject{originStorage: so.originStorage.Copy(), deleted: so.deleted, newlyCreated: so.newlyCreated, dirty: dirty}
+
+		if err := updateAccount(chainRules.IsSpuriousDragon, chainRules.IsAura, noop, addr, so, true); err != nil {
+			return err
+		}
+		so.newlyCreated = false
+		sdb.stateObjectsDirty[addr] = struct{}{}
+	}
+	// The refund, the access list and the transient storage only live for the time of a transaction
+	sdb.journal.append(ch)
+	sdb.refund = 0
+	sdb.accessList = newAccessList()
+	sdb.transientStorage = newTransientStorage()
+	return nil
+}
+
 // CommitBlock finalizes the state by removing the self destructed objects
 // and clears the journal as well as the refunds.
 func (sdb *IntraBlockState) CommitBlock(chainRules *chain.Rules, stateWriter StateWriter) error {
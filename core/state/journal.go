@@ -137,6 +137,14 @@ type (
 	addLogChange struct {
 		txhash libcommon.Hash
 	}
+	// softFinalizeChange undoes IntraBlockState.SoftFinalizeTx
+	softFinalizeChange struct {
+		prevRefund           uint64
+		prevAccessList       *accessList
+		prevTransientStorage transientStorage
+		loaded               []libcommon.Address // objects loaded to transfer their balance increase
+		objects              map[libcommon.Address]finalizedObject
+	}
 	touchChange struct {
 		account *libcommon.Address
 	}
@@ -272,6 +280,39 @@ func (ch refundChange) dirtied() *libcommon.Address {
 	return nil
 }
 
+// finalizedObject is the state of an object before its soft finalization
+type finalizedObject struct {
+	originStorage Storage
+	deleted       bool
+	newlyCreated  bool
+	dirty         bool
+}
+
+func (ch softFinalizeChange) revert(s *IntraBlockState) {
+	s.refund = ch.prevRefund
+	s.accessList = ch.prevAccessList
+	s.transientStorage = ch.prevTransientStorage
+	for addr, prev := range ch.objects {
+		so, exist := s.stateObjects[addr]
+		if !exist {
+			continue
+		}
+		so.originStorage = prev.originStorage
+		so.deleted = prev.deleted
+		so.newlyCreated = prev.newlyCreated
+		if !prev.dirty {
+			delete(s.stateObjectsDirty, addr)
+		}
+	}
+	for _, addr := range ch.loaded {
+		delete(s.stateObjects, addr)
+	}
+}
+
+func (ch softFinalizeChange) dirtied() *libcommon.Address {
+	return nil
+}
+
 func (ch addLogChange) revert(s *IntraBlockState) {
 	logs := s.logs[ch.txhash]
 	if len(logs) == 1 {
@@ -507,6 +507,11 @@ func (rs *StateV3) ApplyHistory(txTask *exec22.TxTask, agg *libstate.Aggregator)
 				return err
 			}
 		}
+		if len(log.Topics) > 0 {
+			if err := agg.PutIdx(kv.TblLogAddrTopicsIdx, append(log.Address.Bytes(), log.Topics[0][:]...)); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -841,6 +846,8 @@ type StateReaderV3 struct {
 
 	discardReadList bool
 	readLists       map[string]*libstate.KvList
+
+	hits, misses uint64 // reads served from the state buffered in rs / from the db
 }
 
 func NewStateReaderV3(rs *StateV3) *StateReaderV3 {
@@ -857,15 +864,21 @@ func (r *StateReaderV3) ReadSet() map[string]*libstate.KvList { return r.readLis
 func (r *StateReaderV3) SetTrace(trace bool)                  { r.trace = trace }
 func (r *StateReaderV3) ResetReadSet()                        { r.readLists = newReadList() }
 
+// ReadStats returns the number of reads served from the buffered state and from the db since the reader was created
+func (r *StateReaderV3) ReadStats() (hits, misses uint64) { return r.hits, r.misses }
+
 func (r *StateReaderV3) ReadAccountData(address common.Address) (*accounts.Account, error) {
 	addr := address.Bytes()
 	enc, ok := r.rs.Get(kv.PlainState, addr)
 	if !ok {
+		r.misses++
 		var err error
 		enc, err = r.tx.GetOne(kv.PlainState, addr)
 		if err != nil {
 			return nil, err
 		}
+	} else {
+		r.hits++
 	}
 	if !r.discardReadList {
 		// lifecycle of `r.readList` is less than lifecycle of `r.rs` and `r.tx`, also `r.rs` and `r.tx` do store data immutable way
@@ -888,11 +901,14 @@ func (r *StateReaderV3) ReadAccountStorage(address common.Address, incarnation u
 	composite := dbutils.PlainGenerateCompositeStorageKey(address.Bytes(), incarnation, key.Bytes())
 	enc, ok := r.rs.Get(StorageTable, composite)
 	if !ok || enc == nil {
+		r.misses++
 		var err error
 		enc, err = r.tx.GetOne(kv.PlainState, composite)
 		if err != nil {
 			return nil, err
 		}
+	} else {
+		r.hits++
 	}
 	if !r.discardReadList {
 		r.readLists[StorageTable].Push(string(composite), enc)
@@ -914,11 +930,14 @@ func (r *StateReaderV3) ReadAccountCode(address common.Address, incarnation uint
 	addr, codeHashBytes := address.Bytes(), codeHash.Bytes()
 	enc, ok := r.rs.Get(kv.Code, codeHashBytes)
 	if !ok || enc == nil {
+		r.misses++
 		var err error
 		enc, err = r.tx.GetOne(kv.Code, codeHashBytes)
 		if err != nil {
 			return nil, err
 		}
+	} else {
+		r.hits++
 	}
 	if !r.discardReadList {
 		r.readLists[kv.Code].Push(string(addr), enc)
@@ -933,11 +952,14 @@ func (r *StateReaderV3) ReadAccountCodeSize(address common.Address, incarnation
 	codeHashBytes := codeHash.Bytes()
 	enc, ok := r.rs.Get(kv.Code, codeHashBytes)
 	if !ok || enc == nil {
+		r.misses++
 		var err error
 		enc, err = r.tx.GetOne(kv.Code, codeHashBytes)
 		if err != nil {
 			return 0, err
 		}
+	} else {
+		r.hits++
 	}
 	var sizebuf [8]byte
 	binary.BigEndian.PutUint64(sizebuf[:], uint64(len(enc)))
@@ -955,11 +977,14 @@ func (r *StateReaderV3) ReadAccountIncarnation(address common.Address) (uint64,
 	addrBytes := address[:]
 	enc, ok := r.rs.Get(kv.IncarnationMap, addrBytes)
 	if !ok || enc == nil {
+		r.misses++
 		var err error
 		enc, err = r.tx.GetOne(kv.IncarnationMap, addrBytes)
 		if err != nil {
 			return 0, err
 		}
+	} else {
+		r.hits++
 	}
 	if !r.discardReadList {
 		r.readLists[kv.IncarnationMap].Push(string(addrBytes), enc)
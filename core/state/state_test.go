@@ -276,6 +276,63 @@ func TestSnapshot2(t *testing.T) {
 	}
 }
 
+func TestSoftFinalizeTx(t *testing.T) {
+	t.Parallel()
+	_, tx := memdb.NewTestTx(t)
+	state := New(NewPlainStateReader(tx))
+
+	addr := toAddr([]byte("so0"))
+	coinbase := toAddr([]byte("coinbase"))
+	var key common.Hash
+	state.SetState(addr, &key, *uint256.NewInt(1))
+	state.AddBalance(coinbase, uint256.NewInt(1))
+	if err := state.FinalizeTx(&chain.Rules{}, NewPlainStateWriter(tx, tx, 1)); err != nil {
+		t.Fatal("error while finalizing transaction", err)
+	}
+	// the coinbase isn't loaded until the end of the transaction
+	state = New(NewPlainStateReader(tx))
+
+	snapshot := state.Snapshot()
+	state.SetState(addr, &key, *uint256.NewInt(2))
+	state.AddBalance(coinbase, uint256.NewInt(3))
+	state.AddRefund(4)
+	state.AddSlotToAccessList(addr, key)
+	if err := state.SoftFinalizeTx(&chain.Rules{}); err != nil {
+		t.Fatal("error while soft finalizing transaction", err)
+	}
+
+	// the next transaction sees the previous one as committed
+	var value uint256.Int
+	state.GetCommittedState(addr, &key, &value)
+	if value.Uint64() != 2 {
+		t.Fatalf("committed state mismatch: have %d, want 2", value.Uint64())
+	}
+	if state.GetRefund() != 0 {
+		t.Fatalf("refund not reset: %d", state.GetRefund())
+	}
+	if state.AddressInAccessList(addr) {
+		t.Fatalf("access list not reset")
+	}
+	state.AddSlotToAccessList(addr, key)
+	state.AddBalance(coinbase, uint256.NewInt(5))
+
+	state.RevertToSnapshot(snapshot)
+	state.GetState(addr, &key, &value)
+	if value.Uint64() != 1 {
+		t.Fatalf("state mismatch after revert: have %d, want 1", value.Uint64())
+	}
+	state.GetCommittedState(addr, &key, &value)
+	if value.Uint64() != 1 {
+		t.Fatalf("committed state mismatch after revert: have %d, want 1", value.Uint64())
+	}
+	if balance := state.GetBalance(coinbase); balance.Uint64() != 1 {
+		t.Fatalf("balance mismatch after revert: have %d, want 1", balance.Uint64())
+	}
+	if state.AddressInAccessList(addr) {
+		t.Fatalf("access list not reverted")
+	}
+}
+
 func compareStateObjects(so0, so1 *stateObject, t *testing.T) {
 	if so0.Address() != so1.Address() {
 		t.Fatalf("Address mismatch: have %v, want %v", so0.address, so1.address)
@@ -35,7 +35,7 @@ import (
 // indicating the block was invalid.
 func applyTransaction(config *chain.Config, engine consensus.EngineReader, gp *GasPool, ibs *state.IntraBlockState,
 	stateWriter state.StateWriter, header *types.Header, tx types.Transaction, usedGas, usedBlobGas *uint64,
-	evm *vm.EVM, cfg vm.Config) (*types.Receipt, []byte, error) {
+	evm *vm.EVM, cfg vm.Config, revertible bool) (*types.Receipt, []byte, error) {
 	rules := evm.ChainRules()
 	msg, err := tx.AsMessage(*types.MakeSigner(config, header.Number.Uint64(), header.Time), header.BaseFee, rules)
 	if err != nil {
@@ -69,7 +69,12 @@ func applyTransaction(config *chain.Config, engine consensus.EngineReader, gp *G
 		return nil, nil, err
 	}
 	// Update the state with pending changes
-	if err = ibs.FinalizeTx(rules, stateWriter); err != nil {
+	if revertible {
+		err = ibs.SoftFinalizeTx(rules)
+	} else {
+		err = ibs.FinalizeTx(rules, stateWriter)
+	}
+	if err != nil {
 		return nil, nil, err
 	}
 	*usedGas += result.UsedGas
@@ -134,5 +139,20 @@ func ApplyTransaction(config *chain.Config, blockHashFunc func(n uint64) libcomm
 	blockContext.L1CostFunc = opstack.NewL1CostFunc(config, ibs)
 	vmenv := vm.NewEVM(blockContext, evmtypes.TxContext{}, ibs, config, cfg)
 
-	return applyTransaction(config, engine, gp, ibs, stateWriter, header, tx, usedGas, usedBlobGas, vmenv, cfg)
+	return applyTransaction(config, engine, gp, ibs, stateWriter, header, tx, usedGas, usedBlobGas, vmenv, cfg, false)
+}
+
+// ApplyBundleTransaction applies a transaction of a bundle like ApplyTransaction, but only soft finalizes it: the
+// state can be reverted to a snapshot taken before the bundle until IntraBlockState.FinalizeTx is called.
+func ApplyBundleTransaction(config *chain.Config, blockHashFunc func(n uint64) libcommon.Hash, engine consensus.EngineReader,
+	author *libcommon.Address, gp *GasPool, ibs *state.IntraBlockState, header *types.Header, tx types.Transaction,
+	usedGas, usedBlobGas *uint64, cfg vm.Config,
+) (*types.Receipt, []byte, error) {
+	cfg.SkipAnalysis = SkipAnalysis(config, header.Number.Uint64())
+
+	blockContext := NewEVMBlockContext(header, blockHashFunc, engine, author)
+	blockContext.L1CostFunc = opstack.NewL1CostFunc(config, ibs)
+	vmenv := vm.NewEVM(blockContext, evmtypes.TxContext{}, ibs, config, cfg)
+
+	return applyTransaction(config, engine, gp, ibs, nil, header, tx, usedGas, usedBlobGas, vmenv, cfg, true)
 }
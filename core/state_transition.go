@@ -21,12 +21,14 @@ import (
 
 	"github.com/holiman/uint256"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/fixedgas"
 	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
 	types2 "github.com/ledgerwatch/erigon-lib/types"
 
 	cmath "github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/common/u256"
 	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/vm"
 	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
 	"github.com/ledgerwatch/erigon/crypto"
@@ -98,6 +100,7 @@ type Message interface {
 	Data() []byte
 	AccessList() types2.AccessList
 	BlobHashes() []libcommon.Hash
+	Authorizations() []types.Authorization
 
 	IsFree() bool
 	IsFake() bool
@@ -139,7 +142,7 @@ func (result *ExecutionResult) Revert() []byte {
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, accessList types2.AccessList, isContractCreation bool, isHomestead, isEIP2028, isEIP3860 bool) (uint64, error) {
+func IntrinsicGas(data []byte, accessList types2.AccessList, authorizationsLen uint64, isContractCreation bool, isHomestead, isEIP2028, isEIP3860 bool) (uint64, error) {
 	// Zero and non-zero bytes are priced differently
 	dataLen := uint64(len(data))
 	dataNonZeroLen := uint64(0)
@@ -149,7 +152,23 @@ func IntrinsicGas(data []byte, accessList types2.AccessList, isContractCreation
 		}
 	}
 
-	gas, status := txpoolcfg.CalcIntrinsicGas(dataLen, dataNonZeroLen, accessList, isContractCreation, isHomestead, isEIP2028, isEIP3860)
+	gas, status := txpoolcfg.CalcIntrinsicGas(dataLen, dataNonZeroLen, authorizationsLen, accessList, isContractCreation, isHomestead, isEIP2028, isEIP3860)
+	if status != txpoolcfg.Success {
+		return 0, ErrGasUintOverflow
+	}
+	return gas, nil
+}
+
+// FloorDataGas computes the minimum gas required for a transaction with the given data (EIP-7623).
+func FloorDataGas(data []byte) (uint64, error) {
+	dataNonZeroLen := uint64(0)
+	for _, byt := range data {
+		if byt != 0 {
+			dataNonZeroLen++
+		}
+	}
+
+	gas, status := txpoolcfg.CalcFloorDataGas(uint64(len(data)), dataNonZeroLen)
 	if status != txpoolcfg.Success {
 		return 0, ErrGasUintOverflow
 	}
@@ -339,8 +358,21 @@ func (st *StateTransition) preCheck(gasBailout bool) error {
 			// libcommon.Hash{} means that the sender is not in the state.
 			// Historically there were transactions with 0 gas price and non-existing sender,
 			// so we have to allow that.
-			return fmt.Errorf("%w: address %v, codehash: %s", ErrSenderNoEOA,
-				st.msg.From().Hex(), codeHash)
+			// EIP-7702: an account with a delegation designator is still an EOA.
+			if _, delegated := st.state.GetDelegatedDesignation(st.msg.From()); !delegated || !st.evm.ChainRules().IsPrague {
+				return fmt.Errorf("%w: address %v, codehash: %s", ErrSenderNoEOA,
+					st.msg.From().Hex(), codeHash)
+			}
+		}
+	}
+
+	// Check that EIP-7702 authorization list signatures are well formed.
+	if auths := st.msg.Authorizations(); auths != nil {
+		if st.msg.To() == nil {
+			return fmt.Errorf("%w: address %v", ErrSetCodeTxCreate, st.msg.From().Hex())
+		}
+		if len(auths) == 0 {
+			return fmt.Errorf("%w: address %v", ErrEmptyAuthList, st.msg.From().Hex())
 		}
 	}
 
@@ -373,6 +405,40 @@ func (st *StateTransition) preCheck(gasBailout bool) error {
 	return st.buyGas(gasBailout)
 }
 
+// applyAuthorization sets the code of the authority to the delegation designator (EIP-7702).
+// Authorizations which don't pass the validation are skipped without affecting the transaction.
+func (st *StateTransition) applyAuthorization(auth *types.Authorization) {
+	if !auth.ChainID.IsZero() && auth.ChainID.ToBig().Cmp(st.evm.ChainConfig().ChainID) != 0 {
+		return
+	}
+	if auth.Nonce+1 < auth.Nonce {
+		return
+	}
+	authority, err := auth.RecoverSigner()
+	if err != nil {
+		return
+	}
+	st.state.AddAddressToAccessList(authority)
+	if code := st.state.GetCode(authority); len(code) != 0 {
+		if _, ok := types.ParseDelegation(code); !ok {
+			return
+		}
+	}
+	if st.state.GetNonce(authority) != auth.Nonce {
+		return
+	}
+	if st.state.Exist(authority) {
+		st.state.AddRefund(fixedgas.PerEmptyAccountCost - fixedgas.PerAuthBaseCost)
+	}
+	st.state.SetNonce(authority, auth.Nonce+1)
+	if auth.Address == (libcommon.Address{}) {
+		// delegation to the zero address clears the code of the authority
+		st.state.SetCode(authority, nil)
+		return
+	}
+	st.state.SetCode(authority, types.AddressToDelegation(auth.Address))
+}
+
 // TransitionDb will transition the state by applying the current message and
 // returning the evm execution result with following fields.
 //
@@ -455,13 +521,25 @@ func (st *StateTransition) innerTransitionDb(refunds bool, gasBailout bool) (*Ex
 	isEIP3860 := vmConfig.HasEip3860(rules)
 
 	// Check clauses 4-5, subtract intrinsic gas if everything is correct
-	gas, err := IntrinsicGas(st.data, st.msg.AccessList(), contractCreation, rules.IsHomestead, rules.IsIstanbul, isEIP3860)
+	gas, err := IntrinsicGas(st.data, st.msg.AccessList(), uint64(len(st.msg.Authorizations())), contractCreation, rules.IsHomestead, rules.IsIstanbul, isEIP3860)
 	if err != nil {
 		return nil, err
 	}
 	if st.gasRemaining < gas {
 		return nil, fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, st.gasRemaining, gas)
 	}
+	// EIP-7623: data heavy transactions pay at least the calldata floor.
+	// Deposits are exempt, their gas is bought on L1 and they can't be rejected.
+	var floorDataGas uint64
+	if rules.IsPrague && !msg.IsDepositTx() {
+		floorDataGas, err = FloorDataGas(st.data)
+		if err != nil {
+			return nil, err
+		}
+		if st.initialGas < floorDataGas {
+			return nil, fmt.Errorf("%w: have %d, want %d", ErrFloorDataGas, st.initialGas, floorDataGas)
+		}
+	}
 	st.gasRemaining -= gas
 
 	var bailout bool
@@ -495,6 +573,15 @@ func (st *StateTransition) innerTransitionDb(refunds bool, gasBailout bool) (*Ex
 	} else {
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+		// Apply EIP-7702 authorizations, invalid ones are skipped
+		auths := msg.Authorizations()
+		for i := range auths {
+			st.applyAuthorization(&auths[i])
+		}
+		// Delegate of the destination is accessed by the call, so it is warmed up the same way as the destination
+		if delegate, ok := st.state.GetDelegatedDesignation(st.to()); ok && rules.IsPrague {
+			st.state.AddAddressToAccessList(delegate)
+		}
 		ret, st.gasRemaining, vmerr = st.evm.Call(sender, st.to(), st.data, st.gasRemaining, st.value, bailout)
 	}
 
@@ -525,6 +612,13 @@ func (st *StateTransition) innerTransitionDb(refunds bool, gasBailout bool) (*Ex
 			st.refundGas(params.RefundQuotient)
 		}
 	}
+	// EIP-7623: the gas used after refunds can't go below the calldata floor
+	if st.gasUsed() < floorDataGas {
+		st.gasRemaining = st.initialGas - floorDataGas
+	}
+	if refunds && !gasBailout {
+		st.returnGas()
+	}
 	if st.msg.IsDepositTx() && rules.IsOptimismRegolith {
 		// Skip coinbase payments for deposit tx in Regolith
 		return &ExecutionResult{
@@ -593,7 +687,10 @@ func (st *StateTransition) refundGas(refundQuotient uint64) {
 		refund = st.state.GetRefund()
 	}
 	st.gasRemaining += refund
+}
 
+// returnGas returns ETH for the remaining gas to the sender and the gas to the block gas pool.
+func (st *StateTransition) returnGas() {
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := new(uint256.Int).Mul(new(uint256.Int).SetUint64(st.gasRemaining), st.gasPrice)
 	st.state.AddBalance(st.msg.From(), remaining)
@@ -0,0 +1,102 @@
+package core_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+func TestFloorDataGasWithL1Cost(t *testing.T) {
+	t.Parallel()
+	config := *params.AllProtocolChanges
+	config.Optimism = &chain.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+	config.IsthmusTime = big.NewInt(0)
+
+	from := libcommon.HexToAddress("0xaa")
+	to := libcommon.HexToAddress("0xbb")
+	initialBalance := uint256.NewInt(1e18)
+	gasPrice := uint256.NewInt(2)
+	l1Fee := uint256.NewInt(1_000_000)
+	// 1000 non-zero bytes: intrinsic gas is 37000, the calldata floor is 61000
+	data := bytes.Repeat([]byte{0xff}, 1000)
+	floorDataGas, err := core.FloorDataGas(data)
+	require.NoError(t, err)
+	require.Equal(t, uint64(61000), floorDataGas)
+
+	apply := func(gas uint64, isFake, refunds bool) (*core.ExecutionResult, *state.IntraBlockState, error) {
+		_, tx := memdb.NewTestTx(t)
+		ibs := state.New(state.NewDbStateReader(tx))
+		ibs.AddBalance(from, initialBalance)
+		blockCtx := evmtypes.BlockContext{
+			CanTransfer:   core.CanTransfer,
+			Transfer:      core.Transfer,
+			GetHash:       func(uint64) libcommon.Hash { return libcommon.Hash{} },
+			BlockNumber:   1,
+			Time:          1,
+			GasLimit:      30_000_000,
+			BaseFee:       uint256.NewInt(1),
+			ExcessBlobGas: new(uint64),
+			L1CostFunc: func(types2.RollupCostData, uint64) *uint256.Int {
+				return l1Fee.Clone()
+			},
+		}
+		msg := types.NewMessage(from, &to, 0, uint256.NewInt(0), gas, gasPrice, gasPrice, uint256.NewInt(1), data, nil,
+			false /* checkNonce */, false /* isFree */, isFake, nil /* maxFeePerBlobGas */)
+		evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), ibs, &config, vm.Config{})
+		res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(gas), refunds, false /* gasBailout */)
+		return res, ibs, err
+	}
+
+	// Transactions must cover the floor
+	_, _, err = apply(floorDataGas-1, false, true)
+	require.ErrorIs(t, err, core.ErrFloorDataGas)
+
+	// Unused gas above the floor is refunded, the L1 data fee is charged on top in ETH
+	res, ibs, err := apply(100_000, false, true)
+	require.NoError(t, err)
+	require.Equal(t, floorDataGas, res.UsedGas)
+	spent := new(uint256.Int).Sub(initialBalance, ibs.GetBalance(from))
+	want := new(uint256.Int).Add(new(uint256.Int).Mul(uint256.NewInt(floorDataGas), gasPrice), l1Fee)
+	require.Equal(t, want, spent)
+	require.Equal(t, l1Fee, ibs.GetBalance(params.OptimismL1FeeRecipient))
+
+	// Estimation runs fake calls: the lowest working gas limit is the floor itself,
+	// the L1 data fee doesn't add gas on top of it...
+	_, _, err = apply(floorDataGas-1, true, true)
+	require.ErrorIs(t, err, core.ErrFloorDataGas)
+	res, _, err = apply(floorDataGas, true, true)
+	require.NoError(t, err)
+	require.Equal(t, floorDataGas, res.UsedGas)
+
+	// ...and that estimate is enough for the real transaction paying the L1 fee
+	res, _, err = apply(floorDataGas, false, true)
+	require.NoError(t, err)
+	require.False(t, res.Failed())
+	require.Equal(t, floorDataGas, res.UsedGas)
+
+	// The floor doesn't depend on refunds being applied
+	res, _, err = apply(100_000, false, false)
+	require.NoError(t, err)
+	require.Equal(t, floorDataGas, res.UsedGas)
+
+	// Before Isthmus only the intrinsic gas is charged
+	config.IsthmusTime = nil
+	res, _, err = apply(100_000, false, true)
+	require.NoError(t, err)
+	require.Equal(t, uint64(37000), res.UsedGas)
+}
@@ -0,0 +1,240 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/secp256k1"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	rlp2 "github.com/ledgerwatch/erigon-lib/rlp"
+
+	"github.com/ledgerwatch/erigon/common/u256"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// AuthorizationMagic is the EIP-7702 prefix of the authorization signing payload
+const AuthorizationMagic = 0x05
+
+// DelegationPrefix is the EIP-7702 code prefix of the delegation designator, it is followed by the delegate address
+var DelegationPrefix = []byte{0xef, 0x01, 0x00}
+
+// DelegationDesignatorSize - size of the code installed by an authorization
+const DelegationDesignatorSize = 3 + length.Addr
+
+// Authorization is an EIP-7702 tuple, signed by the authority to delegate its code to Address
+type Authorization struct {
+	ChainID uint256.Int
+	Address libcommon.Address
+	Nonce   uint64
+	YParity uint8
+	R       uint256.Int
+	S       uint256.Int
+}
+
+// AddressToDelegation returns the delegation designator pointing to addr
+func AddressToDelegation(addr libcommon.Address) []byte {
+	return append(libcommon.CopyBytes(DelegationPrefix), addr.Bytes()...)
+}
+
+// ParseDelegation returns the delegate address if code is a delegation designator
+func ParseDelegation(code []byte) (libcommon.Address, bool) {
+	if len(code) != DelegationDesignatorSize || !bytes.HasPrefix(code, DelegationPrefix) {
+		return libcommon.Address{}, false
+	}
+	return libcommon.BytesToAddress(code[len(DelegationPrefix):]), true
+}
+
+func (a *Authorization) SigningHash() libcommon.Hash {
+	return prefixedRlpHash(AuthorizationMagic, []interface{}{&a.ChainID, a.Address, a.Nonce})
+}
+
+// RecoverSigner returns the authority of the authorization. The signature must be in the lower half of the curve order.
+func (a *Authorization) RecoverSigner() (libcommon.Address, error) {
+	if a.YParity > 1 {
+		return libcommon.Address{}, ErrInvalidSig
+	}
+	var v uint256.Int
+	v.SetUint64(uint64(a.YParity))
+	v.Add(&v, u256.Num27)
+	return recoverPlain(secp256k1.DefaultContext, a.SigningHash(), &a.R, &a.S, &v, true)
+}
+
+// SignAuthorization returns a copy of the authorization signed with the given private key
+func SignAuthorization(a Authorization, prv *ecdsa.PrivateKey) (Authorization, error) {
+	h := a.SigningHash()
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return a, err
+	}
+	r, s, v := decodeSignature(sig)
+	a.R.Set(r)
+	a.S.Set(s)
+	a.YParity = uint8(v.Uint64())
+	return a, nil
+}
+
+func authorizationSize(a *Authorization) int {
+	// size of ChainID
+	size := 1 + rlp.Uint256LenExcludingHead(&a.ChainID)
+	// size of Address
+	size += 1 + length.Addr
+	// size of Nonce
+	size += 1 + rlp.IntLenExcludingHead(a.Nonce)
+	// size of YParity
+	size += 1 + rlp.IntLenExcludingHead(uint64(a.YParity))
+	// size of R and S
+	size += 1 + rlp.Uint256LenExcludingHead(&a.R)
+	size += 1 + rlp.Uint256LenExcludingHead(&a.S)
+	return size
+}
+
+func authorizationsSize(auths []Authorization) int {
+	var size int
+	for i := range auths {
+		authLen := authorizationSize(&auths[i])
+		size += rlp2.ListPrefixLen(authLen) + authLen
+	}
+	return size
+}
+
+func encodeAuthorizations(auths []Authorization, w io.Writer, b []byte) error {
+	for i := range auths {
+		a := &auths[i]
+		if err := EncodeStructSizePrefix(authorizationSize(a), w, b); err != nil {
+			return err
+		}
+		if err := a.ChainID.EncodeRLP(w); err != nil {
+			return err
+		}
+		b[0] = 128 + length.Addr
+		if _, err := w.Write(b[:1]); err != nil {
+			return err
+		}
+		if _, err := w.Write(a.Address.Bytes()); err != nil {
+			return err
+		}
+		if err := rlp.EncodeInt(a.Nonce, w, b); err != nil {
+			return err
+		}
+		if err := rlp.EncodeInt(uint64(a.YParity), w, b); err != nil {
+			return err
+		}
+		if err := a.R.EncodeRLP(w); err != nil {
+			return err
+		}
+		if err := a.S.EncodeRLP(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeAuthorizations(auths *[]Authorization, s *rlp.Stream) error {
+	_, err := s.List()
+	if err != nil {
+		return fmt.Errorf("open authorizations: %w", err)
+	}
+	var b []byte
+	for _, err = s.List(); err == nil; _, err = s.List() {
+		var a Authorization
+		if b, err = s.Uint256Bytes(); err != nil {
+			return fmt.Errorf("read ChainID: %w", err)
+		}
+		a.ChainID.SetBytes(b)
+		if b, err = s.Bytes(); err != nil {
+			return fmt.Errorf("read Address: %w", err)
+		}
+		if len(b) != length.Addr {
+			return fmt.Errorf("wrong size for Authorization address: %d", len(b))
+		}
+		copy(a.Address[:], b)
+		if a.Nonce, err = s.Uint(); err != nil {
+			return fmt.Errorf("read Nonce: %w", err)
+		}
+		var yParity uint64
+		if yParity, err = s.Uint(); err != nil {
+			return fmt.Errorf("read YParity: %w", err)
+		}
+		if yParity > 0xff {
+			return fmt.Errorf("wrong YParity: %d", yParity)
+		}
+		a.YParity = uint8(yParity)
+		if b, err = s.Uint256Bytes(); err != nil {
+			return fmt.Errorf("read R: %w", err)
+		}
+		a.R.SetBytes(b)
+		if b, err = s.Uint256Bytes(); err != nil {
+			return fmt.Errorf("read S: %w", err)
+		}
+		a.S.SetBytes(b)
+		if err = s.ListEnd(); err != nil {
+			return fmt.Errorf("close Authorization: %w", err)
+		}
+		*auths = append(*auths, a)
+	}
+	if !errors.Is(err, rlp.EOL) {
+		return fmt.Errorf("open Authorization: %w", err)
+	}
+	if err = s.ListEnd(); err != nil {
+		return fmt.Errorf("close authorizations: %w", err)
+	}
+	return nil
+}
+
+type authorizationJSON struct {
+	ChainID *hexutil.Big      `json:"chainId"`
+	Address libcommon.Address `json:"address"`
+	Nonce   hexutil.Uint64    `json:"nonce"`
+	YParity hexutil.Uint64    `json:"yParity"`
+	R       *hexutil.Big      `json:"r"`
+	S       *hexutil.Big      `json:"s"`
+}
+
+func (a Authorization) MarshalJSON() ([]byte, error) {
+	return json.Marshal(authorizationJSON{
+		ChainID: (*hexutil.Big)(a.ChainID.ToBig()),
+		Address: a.Address,
+		Nonce:   hexutil.Uint64(a.Nonce),
+		YParity: hexutil.Uint64(a.YParity),
+		R:       (*hexutil.Big)(a.R.ToBig()),
+		S:       (*hexutil.Big)(a.S.ToBig()),
+	})
+}
+
+func (a *Authorization) UnmarshalJSON(input []byte) error {
+	var dec authorizationJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.ChainID == nil {
+		return errors.New("missing required field 'chainId' in authorization")
+	}
+	if dec.R == nil || dec.S == nil {
+		return errors.New("missing required signature field in authorization")
+	}
+	if dec.YParity > 0xff {
+		return fmt.Errorf("wrong 'yParity' in authorization: %d", dec.YParity)
+	}
+	if a.ChainID.SetFromBig(dec.ChainID.ToInt()) {
+		return errors.New("'chainId' in authorization does not fit in 256 bits")
+	}
+	a.Address = dec.Address
+	a.Nonce = uint64(dec.Nonce)
+	a.YParity = uint8(dec.YParity)
+	if a.R.SetFromBig(dec.R.ToInt()) {
+		return errors.New("'r' in authorization does not fit in 256 bits")
+	}
+	if a.S.SetFromBig(dec.S.ToInt()) {
+		return errors.New("'s' in authorization does not fit in 256 bits")
+	}
+	return nil
+}
@@ -104,6 +104,7 @@ type Header struct {
 	ExcessBlobGas *uint64 `json:"excessBlobGas"`
 
 	ParentBeaconBlockRoot *libcommon.Hash `json:"parentBeaconBlockRoot"` // EIP-4788
+	RequestsHash          *libcommon.Hash `json:"requestsHash"`          // EIP-7685
 
 	// The verkle proof is ignored in legacy headers
 	Verkle        bool
@@ -161,6 +162,10 @@ func (h *Header) EncodingSize() int {
 		encodingSize += 33
 	}
 
+	if h.RequestsHash != nil {
+		encodingSize += 33
+	}
+
 	if h.Verkle {
 		// Encoding of Verkle Proof
 		encodingSize += rlp2.StringLen(h.VerkleProof)
@@ -310,6 +315,16 @@ func (h *Header) EncodeRLP(w io.Writer) error {
 		}
 	}
 
+	if h.RequestsHash != nil {
+		b[0] = 128 + 32
+		if _, err := w.Write(b[:1]); err != nil {
+			return err
+		}
+		if _, err := w.Write(h.RequestsHash.Bytes()); err != nil {
+			return err
+		}
+	}
+
 	if h.Verkle {
 		if err := rlp.EncodeString(h.VerkleProof, w, b[:]); err != nil {
 			return err
@@ -498,6 +513,23 @@ func (h *Header) DecodeRLP(s *rlp.Stream) error {
 	h.ParentBeaconBlockRoot = new(libcommon.Hash)
 	h.ParentBeaconBlockRoot.SetBytes(b)
 
+	// RequestsHash
+	if b, err = s.Bytes(); err != nil {
+		if errors.Is(err, rlp.EOL) {
+			h.RequestsHash = nil
+			if err := s.ListEnd(); err != nil {
+				return fmt.Errorf("close header struct (no RequestsHash): %w", err)
+			}
+			return nil
+		}
+		return fmt.Errorf("read RequestsHash: %w", err)
+	}
+	if len(b) != 32 {
+		return fmt.Errorf("wrong size for RequestsHash: %d", len(b))
+	}
+	h.RequestsHash = new(libcommon.Hash)
+	h.RequestsHash.SetBytes(b)
+
 	if h.Verkle {
 		if h.VerkleProof, err = s.Bytes(); err != nil {
 			return fmt.Errorf("read VerkleProof: %w", err)
@@ -557,6 +589,9 @@ func (h *Header) Size() common.StorageSize {
 	if h.ParentBeaconBlockRoot != nil {
 		s += common.StorageSize(32)
 	}
+	if h.RequestsHash != nil {
+		s += common.StorageSize(32)
+	}
 	return s
 }
 
@@ -956,6 +991,7 @@ func NewBlock(header *Header, txs []Transaction, uncles []*Header, receipts []*R
 	}
 
 	b.header.ParentBeaconBlockRoot = header.ParentBeaconBlockRoot
+	b.header.RequestsHash = header.RequestsHash
 
 	return b
 }
@@ -1024,6 +1060,10 @@ func CopyHeader(h *Header) *Header {
 		cpy.ParentBeaconBlockRoot = new(libcommon.Hash)
 		cpy.ParentBeaconBlockRoot.SetBytes(h.ParentBeaconBlockRoot.Bytes())
 	}
+	if h.RequestsHash != nil {
+		cpy.RequestsHash = new(libcommon.Hash)
+		cpy.RequestsHash.SetBytes(h.RequestsHash.Bytes())
+	}
 	return &cpy
 }
 
@@ -1154,6 +1194,7 @@ func (b *Block) BaseFee() *big.Int {
 func (b *Block) WithdrawalsHash() *libcommon.Hash       { return b.header.WithdrawalsHash }
 func (b *Block) Withdrawals() Withdrawals               { return b.withdrawals }
 func (b *Block) ParentBeaconBlockRoot() *libcommon.Hash { return b.header.ParentBeaconBlockRoot }
+func (b *Block) RequestsHash() *libcommon.Hash          { return b.header.RequestsHash }
 
 // Header returns a deep-copy of the entire block header using CopyHeader()
 func (b *Block) Header() *Header       { return CopyHeader(b.header) }
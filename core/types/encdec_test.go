@@ -72,6 +72,7 @@ func (tr *TRand) RandWithdrawal() *Withdrawal {
 func (tr *TRand) RandHeader() *Header {
 	wHash := tr.RandHash()
 	pHash := tr.RandHash()
+	rHash := tr.RandHash()
 	return &Header{
 		ParentHash:            tr.RandHash(),                              // libcommon.Hash
 		UncleHash:             tr.RandHash(),                              // libcommon.Hash
@@ -93,6 +94,7 @@ func (tr *TRand) RandHeader() *Header {
 		BlobGasUsed:           tr.RandUint64(),                            // *uint64
 		ExcessBlobGas:         tr.RandUint64(),                            // *uint64
 		ParentBeaconBlockRoot: &pHash,                                     //*libcommon.Hash
+		RequestsHash:          &rHash,                                     //*libcommon.Hash
 	}
 }
 
@@ -281,6 +283,7 @@ func compareHeaders(t *testing.T, a, b *Header) {
 	check(t, "Header.BlobGasUsed", a.BlobGasUsed, b.BlobGasUsed)
 	check(t, "Header.ExcessBlobGas", a.ExcessBlobGas, b.ExcessBlobGas)
 	check(t, "Header.ParentBeaconBlockRoot", a.ParentBeaconBlockRoot, b.ParentBeaconBlockRoot)
+	check(t, "Header.RequestsHash", a.RequestsHash, b.RequestsHash)
 }
 
 func compareWithdrawals(t *testing.T, a, b *Withdrawal) {
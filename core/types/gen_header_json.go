@@ -40,6 +40,7 @@ func (h Header) MarshalJSON() ([]byte, error) {
 		BlobGasUsed           *hexutil.Uint64 `json:"blobGasUsed"`
 		ExcessBlobGas         *hexutil.Uint64 `json:"excessBlobGas"`
 		ParentBeaconBlockRoot *common.Hash    `json:"parentBeaconBlockRoot"`
+		RequestsHash          *common.Hash    `json:"requestsHash"`
 		Verkle                bool
 		VerkleProof           []byte
 		VerkleKeyVals         []verkle.KeyValuePair
@@ -68,6 +69,7 @@ func (h Header) MarshalJSON() ([]byte, error) {
 	enc.BlobGasUsed = (*hexutil.Uint64)(h.BlobGasUsed)
 	enc.ExcessBlobGas = (*hexutil.Uint64)(h.ExcessBlobGas)
 	enc.ParentBeaconBlockRoot = h.ParentBeaconBlockRoot
+	enc.RequestsHash = h.RequestsHash
 	enc.Verkle = h.Verkle
 	enc.VerkleProof = h.VerkleProof
 	enc.VerkleKeyVals = h.VerkleKeyVals
@@ -100,6 +102,7 @@ func (h *Header) UnmarshalJSON(input []byte) error {
 		BlobGasUsed           *hexutil.Uint64 `json:"blobGasUsed"`
 		ExcessBlobGas         *hexutil.Uint64 `json:"excessBlobGas"`
 		ParentBeaconBlockRoot *common.Hash    `json:"parentBeaconBlockRoot"`
+		RequestsHash          *common.Hash    `json:"requestsHash"`
 		Verkle                *bool
 		VerkleProof           []byte
 		VerkleKeyVals         []verkle.KeyValuePair
@@ -186,6 +189,9 @@ func (h *Header) UnmarshalJSON(input []byte) error {
 	if dec.ParentBeaconBlockRoot != nil {
 		h.ParentBeaconBlockRoot = dec.ParentBeaconBlockRoot
 	}
+	if dec.RequestsHash != nil {
+		h.RequestsHash = dec.RequestsHash
+	}
 	if dec.Verkle != nil {
 		h.Verkle = *dec.Verkle
 	}
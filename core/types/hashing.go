@@ -18,6 +18,7 @@ package types
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"io"
 
@@ -201,3 +202,17 @@ func prefixedSSZHash(prefix byte, obj codec.Serializable) (h libcommon.Hash) {
 	sha.Read(h[:])
 	return h
 }
+
+// CalcRequestsHash is the EIP-7685 commitment of the header to the requests of the block: the sha256 of the
+// sha256 of each request, a type byte followed by its data. Requests without data are left out
+func CalcRequestsHash(requests [][]byte) (h libcommon.Hash) {
+	hasher := sha256.New()
+	for _, request := range requests {
+		if len(request) > 1 {
+			reqHash := sha256.Sum256(request)
+			hasher.Write(reqHash[:])
+		}
+	}
+	hasher.Sum(h[:0])
+	return h
+}
@@ -118,3 +118,15 @@ func BenchmarkCurrentLargeList(b *testing.B) {
 		DeriveSha(largeTxList)
 	}
 }
+
+func TestCalcRequestsHash(t *testing.T) {
+	t.Parallel()
+	// sha256 of nothing, the hash of a block without requests
+	if h := CalcRequestsHash(nil); h != libcommon.HexToHash("0xe3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855") {
+		t.Errorf("empty requests hash: %x", h)
+	}
+	requests := [][]byte{{0x00, 1, 2, 3, 4, 5, 6, 7, 8}, {0x01}, {0x02, 0xaa}}
+	if h := CalcRequestsHash(requests); h != libcommon.HexToHash("0x7daa780dc229b9eacfbd6519190bdc689f10c3e5fd784723cc4a8d10e0ec204d") {
+		t.Errorf("requests hash: %x", h)
+	}
+}
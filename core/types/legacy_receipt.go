@@ -0,0 +1,90 @@
+package types
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// l2gethStoredReceiptRLP is the storage encoding of a receipt used by the pre-Bedrock
+// l2geth (OVM) client. It extends the upstream storage encoding with the L1 fee fields.
+type l2gethStoredReceiptRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []*LogForStorage
+	L1GasUsed         *big.Int
+	L1GasPrice        *big.Int
+	L1Fee             *big.Int
+	FeeScalar         string
+}
+
+// LegacyReceiptForStorage is a wrapper around a Receipt that decodes the l2geth
+// storage encoding of pre-Bedrock receipts, including their L1 fee fields.
+type LegacyReceiptForStorage Receipt
+
+// EncodeRLP implements rlp.Encoder using the l2geth storage encoding.
+func (r *LegacyReceiptForStorage) EncodeRLP(w io.Writer) error {
+	enc := &l2gethStoredReceiptRLP{
+		PostStateOrStatus: (*Receipt)(r).statusEncoding(),
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Logs:              make([]*LogForStorage, len(r.Logs)),
+		L1GasUsed:         r.L1GasUsed,
+		L1GasPrice:        r.L1GasPrice,
+		L1Fee:             r.L1Fee,
+	}
+	if r.FeeScalar != nil {
+		enc.FeeScalar = r.FeeScalar.String()
+	}
+	for i, log := range r.Logs {
+		enc.Logs[i] = (*LogForStorage)(log)
+	}
+	return rlp.Encode(w, enc)
+}
+
+// DecodeRLP implements rlp.Decoder, and loads the consensus fields and the L1 fee
+// fields of an l2geth receipt from an RLP stream.
+func (r *LegacyReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
+	var stored l2gethStoredReceiptRLP
+	if err := s.Decode(&stored); err != nil {
+		return err
+	}
+	if err := (*Receipt)(r).setStatus(stored.PostStateOrStatus); err != nil {
+		return err
+	}
+	r.Type = LegacyTxType
+	r.CumulativeGasUsed = stored.CumulativeGasUsed
+	r.Logs = make([]*Log, len(stored.Logs))
+	for i, log := range stored.Logs {
+		r.Logs[i] = (*Log)(log)
+	}
+	r.Bloom = CreateBloom(Receipts{(*Receipt)(r)})
+	r.L1GasUsed = stored.L1GasUsed
+	r.L1GasPrice = stored.L1GasPrice
+	r.L1Fee = stored.L1Fee
+	if stored.FeeScalar != "" {
+		scalar, ok := new(big.Float).SetString(stored.FeeScalar)
+		if !ok {
+			return fmt.Errorf("invalid l1 fee scalar: %q", stored.FeeScalar)
+		}
+		r.FeeScalar = scalar
+	}
+	return nil
+}
+
+// LegacyBlockReceipts is one entry of a pre-Bedrock receipts export: the receipts
+// of a single block, in l2geth storage encoding, keyed by block number.
+type LegacyBlockReceipts struct {
+	Number   uint64
+	Receipts []*LegacyReceiptForStorage
+}
+
+// ToReceipts converts the decoded legacy receipts into regular receipts.
+func (b *LegacyBlockReceipts) ToReceipts() Receipts {
+	receipts := make(Receipts, len(b.Receipts))
+	for i, r := range b.Receipts {
+		receipts[i] = (*Receipt)(r)
+	}
+	return receipts
+}
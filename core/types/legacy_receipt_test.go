@@ -0,0 +1,51 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+func TestLegacyReceiptForStorageRoundTrip(t *testing.T) {
+	rcpt := &Receipt{
+		Status:            ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		Logs: []*Log{
+			{
+				Address: libcommon.BytesToAddress([]byte{0x11}),
+				Topics:  []libcommon.Hash{libcommon.HexToHash("dead")},
+				Data:    []byte{0x01, 0x00, 0xff},
+			},
+		},
+		L1GasUsed:  big.NewInt(2724),
+		L1GasPrice: big.NewInt(30_000_000_000),
+		L1Fee:      big.NewInt(122_580_000_000_000),
+		FeeScalar:  big.NewFloat(1.5),
+	}
+	data, err := rlp.EncodeToBytes((*LegacyReceiptForStorage)(rcpt))
+	require.NoError(t, err)
+
+	d := &LegacyReceiptForStorage{}
+	require.NoError(t, rlp.DecodeBytes(data, d))
+	require.Equal(t, rcpt.Status, d.Status)
+	require.Equal(t, rcpt.CumulativeGasUsed, d.CumulativeGasUsed)
+	require.Equal(t, rcpt.Logs, d.Logs)
+	require.Equal(t, rcpt.L1GasUsed, d.L1GasUsed)
+	require.Equal(t, rcpt.L1GasPrice, d.L1GasPrice)
+	require.Equal(t, rcpt.L1Fee, d.L1Fee)
+	require.Equal(t, rcpt.FeeScalar.String(), d.FeeScalar.String())
+	require.Equal(t, CreateBloom(Receipts{rcpt}), d.Bloom)
+
+	entry := LegacyBlockReceipts{Number: 7, Receipts: []*LegacyReceiptForStorage{(*LegacyReceiptForStorage)(rcpt)}}
+	data, err = rlp.EncodeToBytes(&entry)
+	require.NoError(t, err)
+	var decoded LegacyBlockReceipts
+	require.NoError(t, rlp.DecodeBytes(data, &decoded))
+	require.Equal(t, uint64(7), decoded.Number)
+	require.Len(t, decoded.ToReceipts(), 1)
+	require.Equal(t, rcpt.L1Fee, decoded.ToReceipts()[0].L1Fee)
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// LegacyStateDiff is an entry of a pre-Bedrock state export: the accounts changed by block
+// Number, with their values after the block. The entry of block 0 holds the genesis alloc.
+type LegacyStateDiff struct {
+	Number   uint64
+	Accounts []LegacyAccountDiff
+}
+
+// LegacyAccountDiff is the state of an account after a block. Code is only set by the block
+// which deployed it, Storage only holds the changed slots.
+type LegacyAccountDiff struct {
+	Address libcommon.Address
+	Deleted bool
+	Nonce   uint64
+	Balance *uint256.Int
+	Code    []byte
+	Storage []LegacyStorageDiff
+}
+
+// LegacyStorageDiff is the value of a storage slot after a block, zero if it was cleared.
+type LegacyStorageDiff struct {
+	Key   libcommon.Hash
+	Value libcommon.Hash
+}
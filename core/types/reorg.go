@@ -0,0 +1,40 @@
+package types
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// ReorgTrigger - what caused the canonical chain to be rewound
+type ReorgTrigger string
+
+const (
+	ReorgTriggerEngineAPI ReorgTrigger = "engine_api" // forkchoice update moved the head to another branch
+	ReorgTriggerBadBlock  ReorgTrigger = "bad_block"  // unwind after a block failed validation
+	ReorgTriggerManual    ReorgTrigger = "manual"     // operator unwind, e.g. `integration stage_headers --unwind`
+)
+
+// Reorg is a record of the canonical chain reorganisation: the blocks above Ancestor were
+// replaced by Added (possibly none). Hashes are kept in ascending block number order.
+type Reorg struct {
+	OldHead        libcommon.Hash
+	OldNumber      uint64
+	NewHead        libcommon.Hash
+	NewNumber      uint64
+	Ancestor       libcommon.Hash
+	AncestorNumber uint64
+	Dropped        []libcommon.Hash
+	Added          []libcommon.Hash
+	Time           uint64 // unix seconds
+	Trigger        ReorgTrigger
+}
+
+// ReorgEvent - reorg log entry with its id, as sent to the subscribers
+type ReorgEvent struct {
+	ID    uint64
+	Reorg *Reorg
+}
+
+// Depth - number of canonical blocks which were dropped
+func (r *Reorg) Depth() uint64 {
+	return r.OldNumber - r.AncestorNumber
+}
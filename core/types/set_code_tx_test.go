@@ -0,0 +1,163 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
+
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+func TestAuthorizationSigning(t *testing.T) {
+	t.Parallel()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	authority := crypto.PubkeyToAddress(key.PublicKey)
+
+	auth, err := SignAuthorization(Authorization{
+		ChainID: *uint256.NewInt(1),
+		Address: testAddr,
+		Nonce:   7,
+	}, key)
+	require.NoError(t, err)
+	signer, err := auth.RecoverSigner()
+	require.NoError(t, err)
+	require.Equal(t, authority, signer)
+
+	// the signature doesn't match other content
+	auth.Nonce++
+	signer, err = auth.RecoverSigner()
+	if err == nil {
+		require.NotEqual(t, authority, signer)
+	}
+
+	auth.YParity = 2
+	_, err = auth.RecoverSigner()
+	require.Error(t, err)
+}
+
+func TestParseDelegation(t *testing.T) {
+	t.Parallel()
+	code := AddressToDelegation(testAddr)
+	require.Len(t, code, DelegationDesignatorSize)
+	addr, ok := ParseDelegation(code)
+	require.True(t, ok)
+	require.Equal(t, testAddr, addr)
+
+	_, ok = ParseDelegation(code[:len(code)-1])
+	require.False(t, ok)
+	_, ok = ParseDelegation(append(code, 0))
+	require.False(t, ok)
+	code[0] = 0xfe
+	_, ok = ParseDelegation(code)
+	require.False(t, ok)
+}
+
+func TestSetCodeTxCoding(t *testing.T) {
+	t.Parallel()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	authKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	signer := LatestSignerForChainID(libcommon.Big1)
+	auth, err := SignAuthorization(Authorization{ChainID: *uint256.NewInt(1), Address: testAddr, Nonce: 1}, authKey)
+	require.NoError(t, err)
+	recipient := libcommon.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87")
+	txdata := &SetCodeTransaction{
+		DynamicFeeTransaction: DynamicFeeTransaction{
+			CommonTx: CommonTx{
+				Nonce: 3,
+				To:    &recipient,
+				Gas:   100_000,
+				Value: uint256.NewInt(5),
+				Data:  []byte("abcdef"),
+			},
+			ChainID:    uint256.NewInt(1),
+			Tip:        uint256.NewInt(1),
+			FeeCap:     uint256.NewInt(10),
+			AccessList: types2.AccessList{{Address: testAddr, StorageKeys: []libcommon.Hash{{0x01}}}},
+		},
+		Authorizations: []Authorization{auth, {Address: recipient, Nonce: 2, R: *uint256.NewInt(1), S: *uint256.NewInt(1)}},
+	}
+	tx, err := SignNewTx(key, *signer, txdata)
+	require.NoError(t, err)
+
+	from, err := tx.Sender(*signer)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), from)
+
+	var buf bytes.Buffer
+	require.NoError(t, tx.MarshalBinary(&buf))
+	require.Equal(t, byte(SetCodeTxType), buf.Bytes()[0])
+	require.Equal(t, crypto.Keccak256Hash(buf.Bytes()), tx.Hash())
+
+	// RLP
+	parsedTx, err := encodeDecodeBinary(tx)
+	require.NoError(t, err)
+	require.NoError(t, assertEqual(parsedTx, tx))
+	require.Equal(t, txdata.Authorizations, parsedTx.(*SetCodeTransaction).Authorizations)
+
+	// JSON
+	parsedTx, err = encodeDecodeJSON(tx)
+	require.NoError(t, err)
+	require.NoError(t, assertEqual(parsedTx, tx))
+	require.Equal(t, txdata.Authorizations, parsedTx.(*SetCodeTransaction).Authorizations)
+
+	// Sender is recovered from the decoded transaction
+	parsedFrom, err := parsedTx.Sender(*signer)
+	require.NoError(t, err)
+	require.Equal(t, from, parsedFrom)
+}
+
+// TestSetCodeTxVectors checks fixed EIP-7702 vectors, computed apart from this package from the encoding of
+// the EIP: tx = 0x04 || rlp([chain_id, nonce, max_priority_fee_per_gas, max_fee_per_gas, gas_limit, destination,
+// value, data, access_list, authorization_list, y_parity, r, s]), authorization hash = keccak(0x05 || rlp([chain_id,
+// address, nonce])). The signatures are deterministic (RFC 6979).
+func TestSetCodeTxVectors(t *testing.T) {
+	t.Parallel()
+	authKey, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	require.NoError(t, err)
+	authority := libcommon.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+	sender := libcommon.HexToAddress("0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b")
+	delegate := libcommon.HexToAddress("0x000000000000000000000000000000000000aaaa")
+
+	auth := Authorization{ChainID: *uint256.NewInt(1), Address: delegate, Nonce: 1}
+	authHash := libcommon.HexToHash("0xec4af342bb9197e727111d6a9e0a68881bd368a85a04053fe2606f4c6610dfc2")
+	require.Equal(t, authHash, crypto.Keccak256Hash(libcommon.FromHex("0x05d70194000000000000000000000000000000000000aaaa01")))
+	require.Equal(t, authHash, auth.SigningHash())
+	signed, err := SignAuthorization(auth, authKey)
+	require.NoError(t, err)
+	require.Equal(t, uint8(1), signed.YParity)
+	require.Equal(t, uint256.MustFromHex("0xf7e3e597fc097e71ed6c26b14b25e5395bc8510d58b9136af439e12715f2d721"), &signed.R)
+	require.Equal(t, uint256.MustFromHex("0x6cf7c3d7939bfdb784373effc0ebb0bd7549691a513f395e3cdabf8602724987"), &signed.S)
+	recovered, err := signed.RecoverSigner()
+	require.NoError(t, err)
+	require.Equal(t, authority, recovered)
+
+	raw := libcommon.FromHex("0x04f8c101800214830186a094095e7baea6a6c7c4c2dfeb977efac326af552d878080c0f85cf85a0194000000000000000000000000000000000000aaaa0101a0f7e3e597fc097e71ed6c26b14b25e5395bc8510d58b9136af439e12715f2d721a06cf7c3d7939bfdb784373effc0ebb0bd7549691a513f395e3cdabf860272498701a0b13a831c8a3b7d87b2f6e4da3e6f91e3996d53bcc748ffd7c1cacf78fe395f30a07e32768c89869d2eec0079617d15a2e83ba32dfd021a80455c8f79f652a14184")
+	tx, err := DecodeTransaction(raw)
+	require.NoError(t, err)
+	setCodeTx, ok := tx.(*SetCodeTransaction)
+	require.True(t, ok)
+	require.Equal(t, uint64(0), setCodeTx.Nonce)
+	require.Equal(t, uint256.NewInt(2), setCodeTx.Tip)
+	require.Equal(t, uint256.NewInt(20), setCodeTx.FeeCap)
+	require.Equal(t, uint64(100_000), setCodeTx.Gas)
+	require.Equal(t, libcommon.HexToAddress("0x095e7baea6a6c7c4c2dfeb977efac326af552d87"), *setCodeTx.To)
+	require.Equal(t, []Authorization{signed}, setCodeTx.Authorizations)
+
+	require.Equal(t, libcommon.HexToHash("0xf2521577072f8fcd6dc387b1c41ec8b978bf1ebca1df3e459b5d060a9de197d2"), setCodeTx.SigningHash(libcommon.Big1))
+	require.Equal(t, libcommon.HexToHash("0xbb34403e12386e1bdbd18ae168fb71884018e8c839bb9049577df1d7292292d5"), tx.Hash())
+	from, err := tx.Sender(*LatestSignerForChainID(libcommon.Big1))
+	require.NoError(t, err)
+	require.Equal(t, sender, from)
+	var buf bytes.Buffer
+	require.NoError(t, tx.MarshalBinary(&buf))
+	require.Equal(t, raw, buf.Bytes())
+}
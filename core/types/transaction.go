@@ -52,6 +52,7 @@ const (
 	AccessListTxType
 	DynamicFeeTxType
 	BlobTxType
+	SetCodeTxType
 	DepositTxType = 0x7E
 )
 
@@ -256,6 +257,12 @@ func UnmarshalTransactionFromBinary(data []byte, blobTxnsAreWrappedWithBlobs boo
 			}
 			return t, nil
 		}
+	case SetCodeTxType:
+		t := &SetCodeTransaction{}
+		if err := t.DecodeRLP(s); err != nil {
+			return nil, err
+		}
+		return t, nil
 	default:
 		if data[0] >= 0x80 {
 			// Tx is type legacy which is RLP encoded
@@ -463,6 +470,7 @@ type Message struct {
 	checkNonce       bool
 	isFree           bool
 	blobHashes       []libcommon.Hash
+	authorizations   []Authorization
 	isFake           bool
 
 	isSystemTx  bool
@@ -551,6 +559,11 @@ func (m Message) MaxFeePerBlobGas() *uint256.Int {
 
 func (m Message) BlobHashes() []libcommon.Hash { return m.blobHashes }
 
+func (m Message) Authorizations() []Authorization { return m.authorizations }
+func (m *Message) SetAuthorizations(authorizations []Authorization) {
+	m.authorizations = authorizations
+}
+
 func DecodeSSZ(data []byte, dest codec.Deserializable) error {
 	err := dest.Deserialize(codec.NewDecodingReader(bytes.NewReader(data), uint64(len(data))))
 	return err
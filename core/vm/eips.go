@@ -29,6 +29,7 @@ import (
 )
 
 var activators = map[int]func(*JumpTable){
+	7702: enable7702,
 	7516: enable7516,
 	6780: enable6780,
 	5656: enable5656,
@@ -327,3 +328,12 @@ func enable7516(jt *JumpTable) {
 		numPush:     1,
 	}
 }
+
+// enable7702 applies EIP-7702 (Set EOA account code): calls to an account with a delegation designator
+// also pay for the access of the delegate. EXTCODE* opcodes are not affected, they operate on the designator itself.
+func enable7702(jt *JumpTable) {
+	jt[CALL].dynamicGas = gasCallEIP7702
+	jt[CALLCODE].dynamicGas = gasCallCodeEIP7702
+	jt[STATICCALL].dynamicGas = gasStaticCallEIP7702
+	jt[DELEGATECALL].dynamicGas = gasDelegateCallEIP7702
+}
@@ -196,8 +196,15 @@ func (evm *EVM) call(typ OpCode, caller ContractRef, addr libcommon.Address, inp
 	}
 	p, isPrecompile := evm.precompile(addr)
 	var code []byte
+	var codeAddr libcommon.Address // account the code is loaded from, differs from addr for EIP-7702 delegations
 	if !isPrecompile {
-		code = evm.intraBlockState.GetCode(addr)
+		codeAddr = addr
+		if evm.chainRules.IsPrague {
+			if delegate, ok := evm.intraBlockState.GetDelegatedDesignation(addr); ok {
+				codeAddr = delegate
+			}
+		}
+		code = evm.intraBlockState.GetCode(codeAddr)
 	}
 
 	snapshot := evm.intraBlockState.Snapshot()
@@ -269,7 +276,7 @@ func (evm *EVM) call(typ OpCode, caller ContractRef, addr libcommon.Address, inp
 		addrCopy := addr
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
-		codeHash := evm.intraBlockState.GetCodeHash(addrCopy)
+		codeHash := evm.intraBlockState.GetCodeHash(codeAddr)
 		var contract *Contract
 		if typ == CALLCODE {
 			contract = NewContract(caller, caller.Address(), value, gas, evm.config.SkipAnalysis)
@@ -75,6 +75,8 @@ type IntraBlockState interface {
 	GetCode(common.Address) []byte
 	SetCode(common.Address, []byte)
 	GetCodeSize(common.Address) int
+	// GetDelegatedDesignation returns the EIP-7702 delegate of the account, if its code is a delegation designator
+	GetDelegatedDesignation(common.Address) (common.Address, bool)
 
 	AddRefund(uint64)
 	SubRefund(uint64)
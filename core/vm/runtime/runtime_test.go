@@ -17,13 +17,17 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/holiman/uint256"
+
 	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
@@ -36,6 +40,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/vm"
 	"github.com/ledgerwatch/erigon/eth/tracers/logger"
+	"github.com/ledgerwatch/erigon/params"
 )
 
 func TestDefaults(t *testing.T) {
@@ -133,6 +138,78 @@ func TestCall(t *testing.T) {
 	}
 }
 
+// TestCallDelegated checks that a call to an account with EIP-7702 delegation designator
+// runs the code of the delegate in the context of the account
+func TestCallDelegated(t *testing.T) {
+	t.Parallel()
+	_, tx := memdb.NewTestTx(t)
+	state := state.New(state.NewDbStateReader(tx))
+	authority := libcommon.HexToAddress("0xaa")
+	delegate := libcommon.HexToAddress("0xbb")
+	state.SetCode(delegate, []byte{
+		byte(vm.PUSH1), 42,
+		byte(vm.PUSH1), 0,
+		byte(vm.SSTORE),
+		byte(vm.ADDRESS),
+		byte(vm.EXTCODESIZE),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	})
+	state.SetCode(authority, types.AddressToDelegation(delegate))
+
+	ret, _, err := Call(authority, nil, &Config{State: state})
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	// EXTCODESIZE is not following the delegation
+	if size := new(big.Int).SetBytes(ret); size.Cmp(big.NewInt(types.DelegationDesignatorSize)) != 0 {
+		t.Error("Expected code size of the designator, got", size)
+	}
+	var key libcommon.Hash
+	var value uint256.Int
+	state.GetState(authority, &key, &value)
+	if value.Uint64() != 42 {
+		t.Error("Expected 42 in the storage of the authority, got", value.Uint64())
+	}
+	state.GetState(delegate, &key, &value)
+	if !value.IsZero() {
+		t.Error("Expected empty storage of the delegate, got", value.Uint64())
+	}
+}
+
+func TestHistoryStorage(t *testing.T) {
+	t.Parallel()
+	_, tx := memdb.NewTestTx(t)
+	statedb := state.New(state.NewDbStateReader(tx))
+	statedb.SetCode(params.HistoryStorageAddress, params.HistoryStorageCode)
+	parentHash := libcommon.HexToHash("0x1234")
+	getHash := func(n uint64) libcommon.Hash { return libcommon.BigToHash(new(big.Int).SetUint64(n)) }
+
+	// the system call stores the parent hash
+	_, _, err := Call(params.HistoryStorageAddress, parentHash.Bytes(), &Config{
+		State: statedb, Origin: state.SystemAddress, BlockNumber: big.NewInt(1000), GetHashFn: getHash,
+	})
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+
+	cfg := &Config{State: statedb, BlockNumber: big.NewInt(1000), GetHashFn: getHash}
+	ret, _, err := Call(params.HistoryStorageAddress, libcommon.BigToHash(big.NewInt(999)).Bytes(), cfg)
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	if !bytes.Equal(parentHash.Bytes(), ret) {
+		t.Errorf("Expected %x, got %x", parentHash.Bytes(), ret)
+	}
+	_, _, err = Call(params.HistoryStorageAddress, libcommon.BigToHash(big.NewInt(1000)).Bytes(), cfg)
+	if !errors.Is(err, vm.ErrExecutionReverted) {
+		t.Error("Expected revert for the current block, got", err)
+	}
+}
+
 func BenchmarkCall(b *testing.B) {
 	var definition = `[{"constant":true,"inputs":[],"name":"seller","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"abort","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"value","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":false,"inputs":[],"name":"refund","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"buyer","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmReceived","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"state","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmPurchase","outputs":[],"type":"function"},{"inputs":[],"type":"constructor"},{"anonymous":false,"inputs":[],"name":"Aborted","type":"event"},{"anonymous":false,"inputs":[],"name":"PurchaseConfirmed","type":"event"},{"anonymous":false,"inputs":[],"name":"ItemReceived","type":"event"},{"anonymous":false,"inputs":[],"name":"Refunded","type":"event"}]`
 
@@ -76,6 +76,7 @@ type Config struct {
 	EcotoneTime *big.Int `json:"ecotoneTime,omitempty"` // Ecotone switch time (nil = no fork, 0 = already on optimism ecotone)
 	FjordTime   *big.Int `json:"fjordTime,omitempty"`   // Fjord switch time (nil = no fork, 0 = already on optimism fjord)
 	GraniteTime *big.Int `json:"graniteTime,omitempty"` // Granite switch time (nil = no fork, 0 = already on optimism granite)
+	IsthmusTime *big.Int `json:"isthmusTime,omitempty"` // Isthmus switch time (nil = no fork, 0 = already on optimism isthmus)
 
 	// Optional EIP-4844 parameters
 	MinBlobGasPrice            *uint64 `json:"minBlobGasPrice,omitempty"`
@@ -151,13 +152,14 @@ func (c *Config) String() string {
 		c.NoPruneContracts,
 	)
 	if c.IsOptimism() {
-		configString += fmt.Sprintf("{Bedrock: %v, Regolith: %v, Canyon: %v, Ecotone: %v, Fjord: %v, Granite: %v}",
+		configString += fmt.Sprintf("{Bedrock: %v, Regolith: %v, Canyon: %v, Ecotone: %v, Fjord: %v, Granite: %v, Isthmus: %v}",
 			c.BedrockBlock,
 			c.RegolithTime,
 			c.CanyonTime,
 			c.EcotoneTime,
 			c.FjordTime,
 			c.GraniteTime,
+			c.IsthmusTime,
 		)
 	}
 	return configString
@@ -343,6 +345,10 @@ func (c *Config) IsGranite(time uint64) bool {
 	return isForked(c.GraniteTime, time)
 }
 
+func (c *Config) IsIsthmus(time uint64) bool {
+	return isForked(c.IsthmusTime, time)
+}
+
 // IsOptimism returns whether the node is an optimism node or not.
 func (c *Config) IsOptimism() bool {
 	return c.Optimism != nil
@@ -373,6 +379,12 @@ func (c *Config) IsOptimismGranite(time uint64) bool {
 	return c.IsOptimism() && c.IsGranite(time)
 }
 
+// IsOptimismIsthmus returns true iff this is an optimism node & isthmus is active.
+// Isthmus brings the execution-layer changes of Prague to OP chains.
+func (c *Config) IsOptimismIsthmus(time uint64) bool {
+	return c.IsOptimism() && c.IsIsthmus(time)
+}
+
 // IsOptimismPreBedrock returns true iff this is an optimism node & bedrock is not yet active
 func (c *Config) IsOptimismPreBedrock(num uint64) bool {
 	return c.IsOptimism() && !c.IsBedrock(num)
@@ -626,7 +638,7 @@ type Rules struct {
 	IsAura                                               bool
 	IsOptimismBedrock, IsOptimismRegolith                bool
 	IsOptimismCanyon, IsOptimismEcotone, IsOptimismFjord bool
-	IsOptimismGranite                                    bool
+	IsOptimismGranite, IsOptimismIsthmus                 bool
 }
 
 // Rules ensures c's ChainID is not nil and returns a new Rules instance
@@ -650,7 +662,7 @@ func (c *Config) Rules(num uint64, time uint64) *Rules {
 		IsShanghai:         c.IsShanghai(time) || c.IsAgra(num),
 		IsCancun:           c.IsCancun(time),
 		IsNapoli:           c.IsNapoli(num),
-		IsPrague:           c.IsPrague(time),
+		IsPrague:           c.IsPrague(time) || c.IsOptimismIsthmus(time),
 		IsOsaka:            c.IsOsaka(time),
 		IsAura:             c.Aura != nil,
 		IsOptimismBedrock:  c.IsOptimismBedrock(num),
@@ -659,6 +671,7 @@ func (c *Config) Rules(num uint64, time uint64) *Rules {
 		IsOptimismEcotone:  c.IsOptimismEcotone(time),
 		IsOptimismFjord:    c.IsOptimismFjord(time),
 		IsOptimismGranite:  c.IsOptimismGranite(time),
+		IsOptimismIsthmus:  c.IsOptimismIsthmus(time),
 	}
 }
 
@@ -46,6 +46,8 @@ type Dirs struct {
 	Nodes           string
 	CaplinBlobs     string
 	CaplinIndexing  string
+	ChangeFeed      string
+	LegacyState     string
 }
 
 func New(datadir string) Dirs {
@@ -74,6 +76,8 @@ func New(datadir string) Dirs {
 		Nodes:           filepath.Join(datadir, "nodes"),
 		CaplinBlobs:     filepath.Join(datadir, "caplin", "blobs"),
 		CaplinIndexing:  filepath.Join(datadir, "caplin", "indexing"),
+		ChangeFeed:      filepath.Join(datadir, "changefeed"),
+		LegacyState:     filepath.Join(datadir, "legacystate"),
 	}
 
 	dir.MustExist(dirs.Chaindata, dirs.Tmp,
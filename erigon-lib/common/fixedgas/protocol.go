@@ -36,4 +36,12 @@ const (
 	BlobSize                       = FieldElementsPerBlob * 32
 	BlobGasPerBlob          uint64 = 0x20000
 	DefaultMaxBlobsPerBlock uint64 = 6 // lower for Gnosis
+
+	// EIP-7702: Set EOA account code
+	PerEmptyAccountCost uint64 = 25000 // Per authorization in the authorization list of a set code transaction
+	PerAuthBaseCost     uint64 = 12500 // Paid per authorization if the authority already exists (the rest is refunded)
+
+	// EIP-7623: Increase calldata cost
+	TxTokenPerNonZeroByte    uint64 = 4  // Calldata tokens per non-zero byte, zero bytes count as one token
+	TxTotalCostFloorPerToken uint64 = 10 // Minimum gas charged per calldata token
 )
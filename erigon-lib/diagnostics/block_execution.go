@@ -23,6 +23,8 @@ import (
 	"sync"
 
 	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/common"
 )
 
 type BlockEexcStatsData struct {
@@ -44,6 +46,41 @@ type BlockExecutionStatistics struct {
 	TimeElapsed float64 `json:"timeElapsed"`
 }
 
+// BlockExecutionProfile is the record the execution stage keeps for every executed block. Blocks built by the
+// mining stage are recorded once the execution stage imports them, and the parallel executor of history v3
+// records none.
+type BlockExecutionProfile struct {
+	BlockNumber   uint64              `json:"blockNumber"`
+	BlockHash     common.Hash         `json:"blockHash"`
+	GasUsed       uint64              `json:"gasUsed"`
+	GasLimit      uint64              `json:"gasLimit"`
+	WallTimeUs    uint64              `json:"wallTimeUs"`
+	TxCount       int                 `json:"txCount"`
+	DepositCount  int                 `json:"depositCount"`
+	StateReads    uint64              `json:"stateReads"`
+	StateWrites   uint64              `json:"stateWrites"`
+	BatchHits     uint64              `json:"batchHits"`     // state reads served by the in-memory state of the execution stage, not by the kvcache of the RPC
+	BatchMisses   uint64              `json:"batchMisses"`   // state reads which fell through to the db
+	BatchHitRatio float64             `json:"batchHitRatio"` // BatchHits / (BatchHits + BatchMisses)
+	SlowestTx     *TxExecutionProfile `json:"slowestTx,omitempty"`
+	TopContracts  []ContractGasUsage  `json:"topContracts"`
+}
+
+type TxExecutionProfile struct {
+	Hash       common.Hash `json:"hash"`
+	Index      int         `json:"index"`
+	GasUsed    uint64      `json:"gasUsed"`
+	WallTimeUs uint64      `json:"wallTimeUs"`
+}
+
+// ContractGasUsage is the gas used running the code of a contract, excluding the calls it makes to other
+// contracts, and the number of transactions in which its code ran.
+type ContractGasUsage struct {
+	Address common.Address `json:"address"`
+	GasUsed uint64         `json:"gasUsed"`
+	TxCount int            `json:"txCount"`
+}
+
 func (b *BlockEexcStatsData) SetData(d BlockExecutionStatistics) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
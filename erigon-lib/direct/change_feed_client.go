@@ -0,0 +1,113 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package direct
+
+import (
+	"context"
+	"io"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/types"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var _ remote.CHANGEFEEDClient = (*ChangeFeedClientDirect)(nil) // compile-time interface check
+
+// ChangeFeedClientDirect implements CHANGEFEEDClient interface by connecting the instance of the client directly
+// with the corresponding instance of CHANGEFEEDServer
+type ChangeFeedClientDirect struct {
+	server remote.CHANGEFEEDServer
+}
+
+func NewChangeFeedClientDirect(server remote.CHANGEFEEDServer) *ChangeFeedClientDirect {
+	return &ChangeFeedClientDirect{server: server}
+}
+
+func (c *ChangeFeedClientDirect) Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*types.VersionReply, error) {
+	return c.server.Version(ctx, in)
+}
+
+func (c *ChangeFeedClientDirect) Info(ctx context.Context, in *remote.ChangeFeedInfoRequest, opts ...grpc.CallOption) (*remote.ChangeFeedInfoReply, error) {
+	return c.server.Info(ctx, in)
+}
+
+// -- start Diffs
+
+func (c *ChangeFeedClientDirect) Diffs(ctx context.Context, in *remote.ChangeFeedRequest, opts ...grpc.CallOption) (remote.CHANGEFEED_DiffsClient, error) {
+	ch := make(chan *blockDiffReply, 256)
+	ctx, cancel := context.WithCancel(ctx)
+	streamServer := &ChangeFeedDiffsS{ch: ch, ctx: ctx}
+	go func() {
+		defer close(ch)
+		streamServer.Err(c.server.Diffs(in, streamServer))
+	}()
+	return &ChangeFeedDiffsC{ch: ch, ctx: ctx, cancel: cancel}, nil
+}
+
+type blockDiffReply struct {
+	r   *remote.BlockDiff
+	err error
+}
+
+type ChangeFeedDiffsC struct {
+	ch     chan *blockDiffReply
+	ctx    context.Context
+	cancel context.CancelFunc
+	grpc.ClientStream
+}
+
+func (c *ChangeFeedDiffsC) Recv() (*remote.BlockDiff, error) {
+	m, ok := <-c.ch
+	if !ok || m == nil {
+		c.cancel()
+		return nil, io.EOF
+	}
+	if m.err != nil {
+		c.cancel()
+	}
+	return m.r, m.err
+}
+func (c *ChangeFeedDiffsC) Context() context.Context { return c.ctx }
+
+// ChangeFeedDiffsS implements remote.CHANGEFEED_DiffsServer
+type ChangeFeedDiffsS struct {
+	ch  chan *blockDiffReply
+	ctx context.Context
+	grpc.ServerStream
+}
+
+func (s *ChangeFeedDiffsS) Send(m *remote.BlockDiff) error {
+	select {
+	case s.ch <- &blockDiffReply{r: m}:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+func (s *ChangeFeedDiffsS) Context() context.Context { return s.ctx }
+func (s *ChangeFeedDiffsS) Err(err error) {
+	if err == nil {
+		return
+	}
+	select {
+	case s.ch <- &blockDiffReply{err: err}:
+	case <-s.ctx.Done():
+	}
+}
+
+// -- end Diffs
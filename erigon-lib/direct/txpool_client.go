@@ -119,3 +119,11 @@ func (s *TxPoolClient) Status(ctx context.Context, in *txpool_proto.StatusReques
 func (s *TxPoolClient) Nonce(ctx context.Context, in *txpool_proto.NonceRequest, opts ...grpc.CallOption) (*txpool_proto.NonceReply, error) {
 	return s.server.Nonce(ctx, in)
 }
+
+func (s *TxPoolClient) TxStatus(ctx context.Context, in *txpool_proto.TxStatusRequest, opts ...grpc.CallOption) (*txpool_proto.TxStatusReply, error) {
+	return s.server.TxStatus(ctx, in)
+}
+
+func (s *TxPoolClient) SendBundle(ctx context.Context, in *txpool_proto.SendBundleRequest, opts ...grpc.CallOption) (*txpool_proto.SendBundleReply, error) {
+	return s.server.SendBundle(ctx, in)
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/c2h5oh/datasize"
 	btree2 "github.com/tidwall/btree"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/crypto/sha3"
 
 	"github.com/ledgerwatch/erigon-lib/common"
@@ -36,6 +37,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/erigon-lib/tracing"
 )
 
 type CacheValidationResult struct {
@@ -136,14 +138,31 @@ type CoherentRoot struct {
 // CoherentView - dumb object, which proxy all requests to Coherent object.
 // It's thread-safe, because immutable
 type CoherentView struct {
+	ctx            context.Context // parent of the tracing spans
 	tx             kv.Tx
 	cache          *Coherent
 	stateVersionID uint64
 }
 
-func (c *CoherentView) Get(k []byte) ([]byte, error) { return c.cache.Get(k, c.tx, c.stateVersionID) }
+func (c *CoherentView) Get(k []byte) ([]byte, error) {
+	if !tracing.Recording(c.ctx) {
+		return c.cache.Get(k, c.tx, c.stateVersionID)
+	}
+	_, span := tracing.StartSpan(c.ctx, "kvcache.Get")
+	v, hit, err := c.cache.get(k, c.tx, c.stateVersionID)
+	span.SetAttributes(attribute.Bool("cache.hit", hit))
+	tracing.EndSpan(span, err)
+	return v, err
+}
 func (c *CoherentView) GetCode(k []byte) ([]byte, error) {
-	return c.cache.GetCode(k, c.tx, c.stateVersionID)
+	if !tracing.Recording(c.ctx) {
+		return c.cache.GetCode(k, c.tx, c.stateVersionID)
+	}
+	_, span := tracing.StartSpan(c.ctx, "kvcache.GetCode")
+	v, hit, err := c.cache.getCode(k, c.tx, c.stateVersionID)
+	span.SetAttributes(attribute.Bool("cache.hit", hit))
+	tracing.EndSpan(span, err)
+	return v, err
 }
 
 var _ Cache = (*Coherent)(nil)         // compile-time interface check
@@ -340,13 +359,13 @@ func (c *Coherent) View(ctx context.Context, tx kv.Tx) (CacheView, error) {
 	r := c.selectOrCreateRoot(id)
 
 	if !c.cfg.WaitForNewBlock || c.waitExceededCount.Load() >= MAX_WAITS {
-		return &CoherentView{stateVersionID: id, tx: tx, cache: c}, nil
+		return &CoherentView{ctx: ctx, stateVersionID: id, tx: tx, cache: c}, nil
 	}
 
 	select { // fast non-blocking path
 	case <-r.ready:
 		//fmt.Printf("recv broadcast: %d\n", id)
-		return &CoherentView{stateVersionID: id, tx: tx, cache: c}, nil
+		return &CoherentView{ctx: ctx, stateVersionID: id, tx: tx, cache: c}, nil
 	default:
 	}
 
@@ -360,7 +379,7 @@ func (c *Coherent) View(ctx context.Context, tx kv.Tx) (CacheView, error) {
 		c.waitExceededCount.Add(1)
 		//log.Info("timeout", "db_id", id, "has_btree", r.cache != nil)
 	}
-	return &CoherentView{stateVersionID: id, tx: tx, cache: c}, nil
+	return &CoherentView{ctx: ctx, stateVersionID: id, tx: tx, cache: c}, nil
 }
 
 func (c *Coherent) getFromCache(k []byte, id uint64, code bool) (*Element, *CoherentRoot, error) {
@@ -387,53 +406,63 @@ func (c *Coherent) getFromCache(k []byte, id uint64, code bool) (*Element, *Cohe
 	return it, r, nil
 }
 func (c *Coherent) Get(k []byte, tx kv.Tx, id uint64) ([]byte, error) {
+	v, _, err := c.get(k, tx, id)
+	return v, err
+}
+
+func (c *Coherent) get(k []byte, tx kv.Tx, id uint64) ([]byte, bool, error) {
 	it, r, err := c.getFromCache(k, id, false)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if it != nil {
 		//fmt.Printf("from cache:  %#x,%x\n", k, it.(*Element).V)
 		c.hits.Inc()
-		return it.V, nil
+		return it.V, true, nil
 	}
 	c.miss.Inc()
 
 	v, err := tx.GetOne(kv.PlainState, k)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	//fmt.Printf("from db: %#x,%x\n", k, v)
 
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	v = c.add(common.Copy(k), common.Copy(v), r, id).V
-	return v, nil
+	return v, false, nil
 }
 
 func (c *Coherent) GetCode(k []byte, tx kv.Tx, id uint64) ([]byte, error) {
+	v, _, err := c.getCode(k, tx, id)
+	return v, err
+}
+
+func (c *Coherent) getCode(k []byte, tx kv.Tx, id uint64) ([]byte, bool, error) {
 	it, r, err := c.getFromCache(k, id, true)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if it != nil {
 		//fmt.Printf("from cache:  %#x,%x\n", k, it.(*Element).V)
 		c.codeHits.Inc()
-		return it.V, nil
+		return it.V, true, nil
 	}
 	c.codeMiss.Inc()
 
 	v, err := tx.GetOne(kv.Code, k)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	//fmt.Printf("from db: %#x,%x\n", k, v)
 
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	v = c.addCode(common.Copy(k), common.Copy(v), r, id).V
-	return v, nil
+	return v, false, nil
 }
 func (c *Coherent) removeOldest(r *CoherentRoot) {
 	e := c.stateEvict.Oldest()
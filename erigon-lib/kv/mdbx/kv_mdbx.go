@@ -36,6 +36,7 @@ import (
 	"github.com/erigontech/mdbx-go/mdbx"
 	stack2 "github.com/go-stack/stack"
 	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/maps"
 	"golang.org/x/sync/semaphore"
 
@@ -45,6 +46,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/iter"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/mmap"
+	"github.com/ledgerwatch/erigon-lib/tracing"
 )
 
 const NonExistingDBI kv.DBI = 999_999_999
@@ -1192,6 +1194,17 @@ func (tx *MdbxTx) Delete(table string, k []byte) error {
 }
 
 func (tx *MdbxTx) GetOne(bucket string, k []byte) ([]byte, error) {
+	if tracing.Recording(tx.ctx) {
+		_, span := tracing.StartSpan(tx.ctx, "mdbx.GetOne", attribute.String("db.table", bucket))
+		v, err := tx.getOne(bucket, k)
+		span.SetAttributes(attribute.Bool("db.found", v != nil))
+		tracing.EndSpan(span, err)
+		return v, err
+	}
+	return tx.getOne(bucket, k)
+}
+
+func (tx *MdbxTx) getOne(bucket string, k []byte) ([]byte, error) {
 	c, err := tx.statelessCursor(bucket)
 	if err != nil {
 		return nil, err
@@ -2204,6 +2217,9 @@ func (tx *MdbxTx) ForAmount(bucket string, fromPrefix []byte, amount uint32, wal
 	return nil
 }
 
+// Ctx returns the context the transaction was opened with.
+func (tx *MdbxTx) Ctx() context.Context { return tx.ctx }
+
 func (tx *MdbxTx) CHandle() unsafe.Pointer {
 	return tx.tx.CHandle()
 }
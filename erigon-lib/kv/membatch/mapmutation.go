@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -24,6 +25,8 @@ type Mapmutation struct {
 	count  uint64
 	tmpdir string
 	logger log.Logger
+
+	hits, misses atomic.Uint64 // reads served from the batch / from the db
 }
 
 // NewBatch - starts in-mem batch
@@ -106,8 +109,10 @@ func (m *Mapmutation) ReadSequence(bucket string) (res uint64, err error) {
 // Can only be called from the worker thread
 func (m *Mapmutation) GetOne(table string, key []byte) ([]byte, error) {
 	if value, ok := m.getMem(table, key); ok {
+		m.hits.Add(1)
 		return value, nil
 	}
+	m.misses.Add(1)
 	if m.db != nil {
 		// TODO: simplify when tx can no longer be parent of mutation
 		value, err := m.db.GetOne(table, key)
@@ -119,6 +124,11 @@ func (m *Mapmutation) GetOne(table string, key []byte) ([]byte, error) {
 	return nil, nil
 }
 
+// ReadStats returns how many reads were served by the batch and how many fell through to the db.
+func (m *Mapmutation) ReadStats() (hits, misses uint64) {
+	return m.hits.Load(), m.misses.Load()
+}
+
 func (m *Mapmutation) Last(table string) ([]byte, []byte, error) {
 	c, err := m.db.Cursor(table)
 	if err != nil {
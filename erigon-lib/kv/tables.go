@@ -287,6 +287,12 @@ const (
 	// shard number - it's biggest value in bitmap
 	LogTopicIndex   = "LogTopicIndex"
 	LogAddressIndex = "LogAddressIndex"
+	// LogAddressTopicIndex - composite index of logs by emitter and first topic (event signature), same format:
+	// [addr + topic0] + [4 bytes shard number] -> bitmap(blockN)
+	// it's complete only above the block stored under LogAddressTopicIndexFromKey, see stage_log_index.go.
+	// Only the recent blocks are here: the LogIndex stage freezes older steps into logaddrtopics files of the
+	// aggregator, see freezeLogAddrTopics.
+	LogAddressTopicIndex = "LogAddressTopicIndex"
 
 	// CallTraceSet is the name of the table that contain the mapping of block number to the set (sorted) of all accounts
 	// touched by call traces. It is DupSort-ed table
@@ -335,6 +341,15 @@ const (
 	// headBlockHash, safeBlockHash, finalizedBlockHash of the latest Engine API forkchoice
 	LastForkchoice = "LastForkchoice"
 
+	// Reorgs - log of canonical chain reorganisations, see core/rawdb/accessors_reorgs.go
+	Reorgs = "Reorg" // seq_u64 -> rlp(reorg)
+
+	// BlockExecutionProfiles - bounded ring of per-block execution records, see core/rawdb/accessors_exec_profiles.go
+	BlockExecutionProfiles = "BlockExecutionProfile" // block_num_u64 -> json(profile)
+
+	// SuperchainSignal - latest superchain protocol version signal of the rollup node, see core/rawdb/accessors_superchain.go
+	SuperchainSignal = "SuperchainSignal" // "latest" -> json(signal)
+
 	// TransitionBlockKey tracks the last proof-of-work block
 	TransitionBlockKey = "TransitionBlock"
 
@@ -403,6 +418,10 @@ const (
 	TblLogAddressIdx  = "LogAddressIdx"
 	TblLogTopicsKeys  = "LogTopicsKeys"
 	TblLogTopicsIdx   = "LogTopicsIdx"
+	// TblLogAddrTopicsKeys, TblLogAddrTopicsIdx - recent part of the address+topic0 log index, the rest is frozen into
+	// logaddrtopics files of the aggregator. Keys are [addr + topic0], like in LogAddressTopicIndex
+	TblLogAddrTopicsKeys = "LogAddrTopicsKeys"
+	TblLogAddrTopicsIdx  = "LogAddrTopicsIdx"
 
 	TblTracesFromKeys = "TracesFromKeys"
 	TblTracesFromIdx  = "TracesFromIdx"
@@ -454,6 +473,8 @@ const (
 
 	BlockRootToKzgCommitments = "BlockRootToKzgCommitments"
 	KzgCommitmentToBlob       = "KzgCommitmentToBlob"
+	// [Versioned Hash] => [Slot + Block Root + Blob Index], only maintained by the blob archive
+	VersionedHashToBlobSidecar = "VersionedHashToBlobSidecar"
 
 	// [Block Root] => [Parent Root]
 	BlockRootToParentRoot = "BlockRootToParentRoot"
@@ -523,6 +544,17 @@ var (
 
 	DBSchemaVersionKey = []byte("dbVersion")
 
+	// LogAddressTopicIndexFromKey - first block covered by LogAddressTopicIndex (DatabaseInfo table)
+	LogAddressTopicIndexFromKey = []byte("logAddressTopicIndexFrom")
+
+	// LegacyStateBlocksKey - number of pre-Bedrock blocks whose state is imported (DatabaseInfo table of the legacy state DB)
+	LegacyStateBlocksKey = []byte("legacyStateBlocks")
+	// LegacyReceiptsRangeKey - first and last pre-Bedrock block of the contiguous range of imported receipts
+	LegacyReceiptsRangeKey = []byte("legacyReceiptsRange")
+
+	// FrozenBlobsIndexedKey - first frozen slot whose blob sidecars are not yet in VersionedHashToBlobSidecar (DatabaseInfo table)
+	FrozenBlobsIndexedKey = []byte("frozenBlobsIndexed")
+
 	BittorrentPeerID            = "peerID"
 	CurrentHeadersSnapshotHash  = []byte("CurrentHeadersSnapshotHash")
 	CurrentHeadersSnapshotBlock = []byte("CurrentHeadersSnapshotBlock")
@@ -568,9 +600,13 @@ var ChaindataTables = []string{
 	HeadBlockKey,
 	HeadHeaderKey,
 	LastForkchoice,
+	Reorgs,
+	BlockExecutionProfiles,
+	SuperchainSignal,
 	Migrations,
 	LogTopicIndex,
 	LogAddressIndex,
+	LogAddressTopicIndex,
 	CallTraceSet,
 	CallFromIndex,
 	CallToIndex,
@@ -633,6 +669,8 @@ var ChaindataTables = []string{
 	TblLogAddressIdx,
 	TblLogTopicsKeys,
 	TblLogTopicsIdx,
+	TblLogAddrTopicsKeys,
+	TblLogAddrTopicsIdx,
 
 	TblTracesFromKeys,
 	TblTracesFromIdx,
@@ -669,6 +707,7 @@ var ChaindataTables = []string{
 	// Blob Storage
 	BlockRootToKzgCommitments,
 	KzgCommitmentToBlob,
+	VersionedHashToBlobSidecar,
 	// State Reconstitution
 	ValidatorPublicKeys,
 	InvertedValidatorPublicKeys,
@@ -804,6 +843,8 @@ var ChaindataTablesCfg = TableCfg{
 	TblLogAddressIdx:         {Flags: DupSort},
 	TblLogTopicsKeys:         {Flags: DupSort},
 	TblLogTopicsIdx:          {Flags: DupSort},
+	TblLogAddrTopicsKeys:     {Flags: DupSort},
+	TblLogAddrTopicsIdx:      {Flags: DupSort},
 	TblTracesFromKeys:        {Flags: DupSort},
 	TblTracesFromIdx:         {Flags: DupSort},
 	TblTracesToKeys:          {Flags: DupSort},
@@ -940,8 +981,9 @@ const (
 	StorageHistoryIdx  InvertedIdx = "StorageHistoryIdx"
 	CodeHistoryIdx     InvertedIdx = "CodeHistoryIdx"
 
-	LogTopicIdx   InvertedIdx = "LogTopicIdx"
-	LogAddrIdx    InvertedIdx = "LogAddrIdx"
-	TracesFromIdx InvertedIdx = "TracesFromIdx"
-	TracesToIdx   InvertedIdx = "TracesToIdx"
+	LogTopicIdx     InvertedIdx = "LogTopicIdx"
+	LogAddrIdx      InvertedIdx = "LogAddrIdx"
+	LogAddrTopicIdx InvertedIdx = "LogAddrTopicIdx"
+	TracesFromIdx   InvertedIdx = "TracesFromIdx"
+	TracesToIdx     InvertedIdx = "TracesToIdx"
 )
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/config3"
 	"github.com/ledgerwatch/erigon-lib/kv"
@@ -12,6 +14,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/erigon-lib/tracing"
 )
 
 //Variables Naming:
@@ -308,6 +311,17 @@ func (tx *Tx) DomainGetAsOf(name kv.Domain, key, key2 []byte, ts uint64) (v []by
 }
 
 func (tx *Tx) HistoryGet(name kv.History, key []byte, ts uint64) (v []byte, ok bool, err error) {
+	if tracing.Recording(tx.Ctx()) {
+		_, span := tracing.StartSpan(tx.Ctx(), "history.Get", attribute.String("history", string(name)), attribute.Int64("txnum", int64(ts)))
+		v, ok, err = tx.historyGet(name, key, ts)
+		span.SetAttributes(attribute.Bool("history.found", ok))
+		tracing.EndSpan(span, err)
+		return v, ok, err
+	}
+	return tx.historyGet(name, key, ts)
+}
+
+func (tx *Tx) historyGet(name kv.History, key []byte, ts uint64) (v []byte, ok bool, err error) {
 	switch name {
 	case kv.AccountsHistory:
 		v, ok, err = tx.aggCtx.ReadAccountDataNoStateWithRecent(key, ts, tx.MdbxTx)
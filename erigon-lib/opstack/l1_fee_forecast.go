@@ -0,0 +1,137 @@
+package opstack
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// L1Fees are the L1 fee values an L1 info deposit sets on the L1Block contract
+type L1Fees struct {
+	BaseFee     *uint256.Int
+	BlobBaseFee *uint256.Int // zero before Ecotone
+}
+
+// ExtractL1Fees decodes the L1 fees from the calldata of an L1 info deposit transaction.
+// Both the Bedrock and the Ecotone encodings are understood.
+func ExtractL1Fees(data []byte) (L1Fees, error) {
+	if len(data) < 4 {
+		return L1Fees{}, fmt.Errorf("expected at least 4 L1 info bytes, got %d", len(data))
+	}
+	if bytes.Equal(data[:4], BedrockL1AttributesSelector) {
+		baseFee, _, _, _, err := extractL1InfoPreEcotone(data)
+		if err != nil {
+			return L1Fees{}, err
+		}
+		return L1Fees{BaseFee: baseFee, BlobBaseFee: new(uint256.Int)}, nil
+	}
+	baseFee, blobBaseFee, _, _, err := extractL1InfoPostEcotone(data)
+	if err != nil {
+		return L1Fees{}, err
+	}
+	return L1Fees{BaseFee: baseFee, BlobBaseFee: blobBaseFee}, nil
+}
+
+// withL1Fees returns a copy of the L1 info deposit calldata with the L1 fees replaced
+func withL1Fees(data []byte, fees L1Fees) []byte {
+	data = bytes.Clone(data)
+	if bytes.Equal(data[:4], BedrockL1AttributesSelector) {
+		// uint256 _basefee is the arg index 2
+		fees.BaseFee.WriteToSlice(data[4+32*2 : 4+32*3])
+		return data
+	}
+	fees.BaseFee.WriteToSlice(data[36:68])
+	fees.BlobBaseFee.WriteToSlice(data[68:100])
+	return data
+}
+
+// ProjectL1Fees extends the linear trend of the L1 fees, given oldest first, by the number of blocks.
+// The trend is the slope between the oldest and the newest values, the projection doesn't go below zero.
+func ProjectL1Fees(history []L1Fees, blocks uint64) L1Fees {
+	last := history[len(history)-1]
+	if len(history) < 2 {
+		return last
+	}
+	first := history[0]
+	project := func(first, last *uint256.Int) *uint256.Int {
+		delta := new(big.Int).Sub(last.ToBig(), first.ToBig())
+		delta.Mul(delta, new(big.Int).SetUint64(blocks))
+		delta.Quo(delta, big.NewInt(int64(len(history)-1)))
+		projected := delta.Add(delta, last.ToBig())
+		if projected.Sign() < 0 {
+			return new(uint256.Int)
+		}
+		if projected.BitLen() > 256 {
+			return new(uint256.Int).SetAllOne()
+		}
+		return uint256.MustFromBig(projected)
+	}
+	return L1Fees{
+		BaseFee:     project(first.BaseFee, last.BaseFee),
+		BlobBaseFee: project(first.BlobBaseFee, last.BlobBaseFee),
+	}
+}
+
+// L1CostForecast is the L1 data fee of a transaction: with the latest L1 fees, with the L1 fees projected
+// from their trend, and the band of the fees over the history and the projection.
+type L1CostForecast struct {
+	Current   *uint256.Int
+	Projected *uint256.Int
+	Low       *uint256.Int
+	High      *uint256.Int
+}
+
+// ForecastL1Cost computes the L1 data fee of a transaction against the L1 fees of the L1 info deposits,
+// given oldest first, and against their trend projected by the number of blocks. Only the L1 fees vary:
+// the cost function of the block at time and the scalars of the newest deposit are used throughout.
+func ForecastL1Cost(config *chain.Config, time uint64, l1Infos [][]byte, rcd types.RollupCostData, blocks uint64) (*L1CostForecast, error) {
+	if len(l1Infos) == 0 {
+		return nil, fmt.Errorf("no L1 info to forecast the L1 cost from")
+	}
+	history := make([]L1Fees, len(l1Infos))
+	for i, data := range l1Infos {
+		fees, err := ExtractL1Fees(data)
+		if err != nil {
+			return nil, err
+		}
+		history[i] = fees
+	}
+	latest := l1Infos[len(l1Infos)-1]
+	cost := func(fees L1Fees) (*uint256.Int, error) {
+		params, err := ExtractL1GasParams(config, time, withL1Fees(latest, fees))
+		if err != nil {
+			return nil, err
+		}
+		fee, _ := params.CostFunc(rcd)
+		return fee, nil
+	}
+
+	projected, err := cost(ProjectL1Fees(history, blocks))
+	if err != nil {
+		return nil, err
+	}
+	forecast := &L1CostForecast{
+		Projected: projected,
+		Low:       projected.Clone(),
+		High:      projected.Clone(),
+	}
+	for _, fees := range history {
+		fee, err := cost(fees)
+		if err != nil {
+			return nil, err
+		}
+		if fee.Lt(forecast.Low) {
+			forecast.Low.Set(fee)
+		}
+		if fee.Gt(forecast.High) {
+			forecast.High.Set(fee)
+		}
+		forecast.Current = fee
+	}
+	return forecast, nil
+}
@@ -0,0 +1,72 @@
+package opstack
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+)
+
+func TestExtractL1Fees(t *testing.T) {
+	fees, err := ExtractL1Fees(getBedrockL1Attributes(basefee, overhead, scalar))
+	require.NoError(t, err)
+	require.Equal(t, L1Fees{BaseFee: basefee, BlobBaseFee: uint256.NewInt(0)}, fees)
+
+	ecotone := getEcotoneL1Attributes(basefee, blobBasefee, basefeeScalar, blobBasefeeScalar)
+	fees, err = ExtractL1Fees(ecotone)
+	require.NoError(t, err)
+	require.Equal(t, L1Fees{BaseFee: basefee, BlobBaseFee: blobBasefee}, fees)
+
+	_, err = ExtractL1Fees(ecotone[:len(ecotone)-1])
+	require.Error(t, err)
+}
+
+func TestProjectL1Fees(t *testing.T) {
+	fees := func(baseFee, blobBaseFee uint64) L1Fees {
+		return L1Fees{BaseFee: uint256.NewInt(baseFee), BlobBaseFee: uint256.NewInt(blobBaseFee)}
+	}
+	// a single sample has no trend
+	require.Equal(t, fees(10, 1), ProjectL1Fees([]L1Fees{fees(10, 1)}, 5))
+	// the base fee rises by 10 per block, the blob base fee falls by 2 per block
+	history := []L1Fees{fees(100, 10), fees(105, 9), fees(120, 6)}
+	require.Equal(t, fees(150, 0), ProjectL1Fees(history, 3))
+	require.Equal(t, fees(120, 6), ProjectL1Fees(history, 0))
+}
+
+func TestForecastL1Cost(t *testing.T) {
+	zeroTime := big.NewInt(0)
+	config := &chain.Config{
+		Optimism:     OptimismTestConfig,
+		RegolithTime: zeroTime,
+		EcotoneTime:  zeroTime,
+	}
+	baseFees := []uint64{1100 * 1e6, 1000 * 1e6, 1200 * 1e6}
+	l1Infos := make([][]byte, len(baseFees))
+	for i, baseFee := range baseFees {
+		l1Infos[i] = getEcotoneL1Attributes(uint256.NewInt(baseFee), blobBasefee, basefeeScalar, blobBasefeeScalar)
+	}
+	cost := func(baseFee uint64) *uint256.Int {
+		fee, _ := newL1CostFuncEcotone(uint256.NewInt(baseFee), blobBasefee, basefeeScalar, blobBasefeeScalar)(emptyTxRollupCostData)
+		return fee
+	}
+
+	forecast, err := ForecastL1Cost(config, 0, l1Infos, emptyTxRollupCostData, 4)
+	require.NoError(t, err)
+	require.Equal(t, cost(1200*1e6), forecast.Current)
+	// (1200 - 1100) / 2 per block
+	require.Equal(t, cost(1400*1e6), forecast.Projected)
+	require.Equal(t, cost(1000*1e6), forecast.Low)
+	require.Equal(t, cost(1400*1e6), forecast.High)
+
+	// the Bedrock cost function of the newest deposit is used
+	forecast, err = ForecastL1Cost(config, 0, [][]byte{getBedrockL1Attributes(basefee, overhead, scalar)}, emptyTxRollupCostData, 4)
+	require.NoError(t, err)
+	require.Equal(t, regolithFee, forecast.Current)
+	require.Equal(t, regolithFee, forecast.High)
+
+	_, err = ForecastL1Cost(config, 0, nil, emptyTxRollupCostData, 4)
+	require.Error(t, err)
+}
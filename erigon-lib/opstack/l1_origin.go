@@ -0,0 +1,86 @@
+package opstack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// L1InfoDepositSourceDomain is the domain of the source hashes of the L1 info deposits
+const L1InfoDepositSourceDomain = 1
+
+// L1Origin is the L1 block an L2 block was derived from, as recorded by the L1 info deposit
+// at the start of the L2 block.
+type L1Origin struct {
+	Number uint64
+	Hash   libcommon.Hash
+	Time   uint64
+}
+
+// ExtractL1Origin decodes the L1 origin from the calldata of an L1 info deposit transaction.
+// Both the Bedrock and the Ecotone encodings are understood.
+func ExtractL1Origin(data []byte) (*L1Origin, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("expected at least 4 L1 info bytes, got %d", len(data))
+	}
+	if bytes.Equal(data[:4], BedrockL1AttributesSelector) {
+		if len(data) < PreEcotoneL1InfoBytes {
+			return nil, fmt.Errorf("expected at least %d L1 info bytes, got %d", PreEcotoneL1InfoBytes, len(data))
+		}
+		// setL1BlockValues(uint64 _number, uint64 _timestamp, uint256 _basefee, bytes32 _hash, ...)
+		args := data[4:]
+		return &L1Origin{
+			Number: binary.BigEndian.Uint64(args[24:32]),
+			Time:   binary.BigEndian.Uint64(args[32+24 : 32*2]),
+			Hash:   libcommon.BytesToHash(args[32*3 : 32*4]),
+		}, nil
+	}
+	// the packed Ecotone layout is described in extractL1InfoPostEcotone
+	if len(data) < PostEcotoneL1InfoBytes {
+		return nil, fmt.Errorf("expected at least %d L1 info bytes, got %d", PostEcotoneL1InfoBytes, len(data))
+	}
+	return &L1Origin{
+		Time:   binary.BigEndian.Uint64(data[20:28]),
+		Number: binary.BigEndian.Uint64(data[28:36]),
+		Hash:   libcommon.BytesToHash(data[100:132]),
+	}, nil
+}
+
+// NextL1InfoData returns the calldata of the L1 info deposit of the next L2 block, assuming it keeps the
+// L1 origin of the block of data: the sequence number is incremented, the L1 attributes are unchanged.
+func NextL1InfoData(data []byte) (next []byte, seqNumber uint64, err error) {
+	if _, err := ExtractL1Origin(data); err != nil {
+		return nil, 0, err
+	}
+	next = bytes.Clone(data)
+	seq := next[12:20]
+	if bytes.Equal(data[:4], BedrockL1AttributesSelector) {
+		// uint64 _sequenceNumber is the arg index 4
+		seq = next[4+32*4+24 : 4+32*5]
+	}
+	seqNumber = binary.BigEndian.Uint64(seq) + 1
+	binary.BigEndian.PutUint64(seq, seqNumber)
+	return next, seqNumber, nil
+}
+
+// L1InfoDepositSource is the source hash of the L1 info deposit of the L2 block with the L1 origin and
+// sequence number
+func L1InfoDepositSource(l1BlockHash libcommon.Hash, seqNumber uint64) (source libcommon.Hash) {
+	var input [64]byte
+	copy(input[:32], l1BlockHash[:])
+	binary.BigEndian.PutUint64(input[56:], seqNumber)
+	keccak := sha3.NewLegacyKeccak256()
+	keccak.Write(input[:])
+	keccak.Sum(input[32:32])
+
+	clear(input[:32])
+	binary.BigEndian.PutUint64(input[24:32], L1InfoDepositSourceDomain)
+	keccak.Reset()
+	keccak.Write(input[:])
+	keccak.Sum(source[:0])
+	return source
+}
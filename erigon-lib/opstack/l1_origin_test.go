@@ -0,0 +1,73 @@
+package opstack
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestExtractL1Origin(t *testing.T) {
+	// the test attribute encoders fill the origin fields with 1234
+	expected := &L1Origin{Number: 1234, Time: 1234, Hash: common.BigToHash(big.NewInt(1234))}
+
+	bedrock := getBedrockL1Attributes(basefee, overhead, scalar)
+	origin, err := ExtractL1Origin(bedrock)
+	require.NoError(t, err)
+	require.Equal(t, expected, origin)
+
+	ecotone := getEcotoneL1Attributes(basefee, blobBasefee, basefeeScalar, blobBasefeeScalar)
+	origin, err = ExtractL1Origin(ecotone)
+	require.NoError(t, err)
+	require.Equal(t, expected, origin)
+
+	_, err = ExtractL1Origin(bedrock[:len(bedrock)-32])
+	require.Error(t, err)
+	_, err = ExtractL1Origin(ecotone[:len(ecotone)-1])
+	require.Error(t, err)
+}
+
+func TestNextL1InfoData(t *testing.T) {
+	for _, data := range [][]byte{
+		getBedrockL1Attributes(basefee, overhead, scalar),
+		getEcotoneL1Attributes(basefee, blobBasefee, basefeeScalar, blobBasefeeScalar),
+	} {
+		next, seqNumber, err := NextL1InfoData(data)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1235), seqNumber)
+		require.Len(t, next, len(data))
+
+		origin, err := ExtractL1Origin(data)
+		require.NoError(t, err)
+		nextOrigin, err := ExtractL1Origin(next)
+		require.NoError(t, err)
+		require.Equal(t, origin, nextOrigin)
+		// the sequence number is the only change
+		diff := 0
+		for i := range data {
+			if data[i] != next[i] {
+				diff++
+			}
+		}
+		require.Equal(t, 1, diff)
+	}
+	_, _, err := NextL1InfoData([]byte{1, 2, 3})
+	require.Error(t, err)
+}
+
+func TestL1InfoDepositSource(t *testing.T) {
+	l1BlockHash := common.HexToHash("0xc00e5d67c2755389aded7d8b151cbd5bcdf7ed275ad5e028b664880fc7581c77")
+	keccak := func(data ...[]byte) []byte {
+		h := sha3.NewLegacyKeccak256()
+		h.Write(bytes.Join(data, nil))
+		return h.Sum(nil)
+	}
+	// keccak256(bytes32(uint256(1)), keccak256(l1BlockHash, bytes32(uint256(seqNumber))))
+	inner := keccak(l1BlockHash[:], common.BigToHash(big.NewInt(4)).Bytes())
+	expected := keccak(common.BigToHash(big.NewInt(L1InfoDepositSourceDomain)).Bytes(), inner)
+	require.Equal(t, common.BytesToHash(expected), L1InfoDepositSource(l1BlockHash, 4))
+}
@@ -50,6 +50,7 @@ type Aggregator struct {
 	code             *History
 	logAddrs         *InvertedIndex
 	logTopics        *InvertedIndex
+	logAddrTopics    *InvertedIndex
 	tracesFrom       *InvertedIndex
 	accounts         *History
 	logPrefix        string
@@ -119,6 +120,9 @@ func NewAggregator(ctx context.Context, dir, tmpdir string, aggregationStep uint
 	if a.logTopics, err = NewInvertedIndex(dir, a.tmpdir, aggregationStep, "logtopics", kv.TblLogTopicsKeys, kv.TblLogTopicsIdx, false, nil, logger); err != nil {
 		return nil, err
 	}
+	if a.logAddrTopics, err = NewInvertedIndex(dir, a.tmpdir, aggregationStep, "logaddrtopics", kv.TblLogAddrTopicsKeys, kv.TblLogAddrTopicsIdx, false, nil, logger); err != nil {
+		return nil, err
+	}
 	if a.tracesFrom, err = NewInvertedIndex(dir, a.tmpdir, aggregationStep, "tracesfrom", kv.TblTracesFromKeys, kv.TblTracesFromIdx, false, nil, logger); err != nil {
 		return nil, err
 	}
@@ -150,6 +154,9 @@ func (a *Aggregator) OpenFolder() error {
 	if err = a.logTopics.OpenFolder(); err != nil {
 		return fmt.Errorf("OpenFolder: %w", err)
 	}
+	if err = a.logAddrTopics.OpenFolder(); err != nil {
+		return fmt.Errorf("OpenFolder: %w", err)
+	}
 	if err = a.tracesFrom.OpenFolder(); err != nil {
 		return fmt.Errorf("OpenFolder: %w", err)
 	}
@@ -179,6 +186,9 @@ func (a *Aggregator) OpenList(fNames []string) error {
 	if err = a.logTopics.OpenList(fNames); err != nil {
 		return err
 	}
+	if err = a.logAddrTopics.OpenList(fNames); err != nil {
+		return err
+	}
 	if err = a.tracesFrom.OpenList(fNames); err != nil {
 		return err
 	}
@@ -201,6 +211,7 @@ func (a *Aggregator) Close() {
 	a.code.Close()
 	a.logAddrs.Close()
 	a.logTopics.Close()
+	a.logAddrTopics.Close()
 	a.tracesFrom.Close()
 	a.tracesTo.Close()
 }
@@ -214,6 +225,7 @@ func (a *Aggregator) CleanDir() {
 	a.code.deleteGarbageFiles()
 	a.logAddrs.deleteGarbageFiles()
 	a.logTopics.deleteGarbageFiles()
+	a.logAddrTopics.deleteGarbageFiles()
 	a.tracesFrom.deleteGarbageFiles()
 	a.tracesTo.deleteGarbageFiles()
 
@@ -224,6 +236,7 @@ func (a *Aggregator) CleanDir() {
 	ac.a.code.cleanAfterFreeze(ac.code.frozenTo())
 	ac.a.logAddrs.cleanAfterFreeze(ac.logAddrs.frozenTo())
 	ac.a.logTopics.cleanAfterFreeze(ac.logTopics.frozenTo())
+	ac.a.logAddrTopics.cleanAfterFreeze(ac.logAddrTopics.frozenTo())
 	ac.a.tracesFrom.cleanAfterFreeze(ac.tracesFrom.frozenTo())
 	ac.a.tracesTo.cleanAfterFreeze(ac.tracesTo.frozenTo())
 }
@@ -234,6 +247,7 @@ func (a *Aggregator) SetWorkers(i int) {
 	a.code.compressWorkers = i
 	a.logAddrs.compressWorkers = i
 	a.logTopics.compressWorkers = i
+	a.logAddrTopics.compressWorkers = i
 	a.tracesFrom.compressWorkers = i
 	a.tracesTo.compressWorkers = i
 }
@@ -253,6 +267,7 @@ func (a *Aggregator) Files() (res []string) {
 	res = append(res, a.code.Files()...)
 	res = append(res, a.logAddrs.Files()...)
 	res = append(res, a.logTopics.Files()...)
+	res = append(res, a.logAddrTopics.Files()...)
 	res = append(res, a.tracesFrom.Files()...)
 	res = append(res, a.tracesTo.Files()...)
 	return res
@@ -318,6 +333,7 @@ func (a *Aggregator) BuildMissedIndices(ctx context.Context, workers int) error
 		a.code.BuildMissedIndices(ctx, g, ps)
 		a.logAddrs.BuildMissedIndices(ctx, g, ps)
 		a.logTopics.BuildMissedIndices(ctx, g, ps)
+		a.logAddrTopics.BuildMissedIndices(ctx, g, ps)
 		a.tracesFrom.BuildMissedIndices(ctx, g, ps)
 		a.tracesTo.BuildMissedIndices(ctx, g, ps)
 
@@ -343,6 +359,7 @@ func (a *Aggregator) SetTx(tx kv.RwTx) {
 	a.code.SetTx(tx)
 	a.logAddrs.SetTx(tx)
 	a.logTopics.SetTx(tx)
+	a.logAddrTopics.SetTx(tx)
 	a.tracesFrom.SetTx(tx)
 	a.tracesTo.SetTx(tx)
 }
@@ -353,18 +370,20 @@ func (a *Aggregator) SetTxNum(txNum uint64) {
 	a.code.SetTxNum(txNum)
 	a.logAddrs.SetTxNum(txNum)
 	a.logTopics.SetTxNum(txNum)
+	a.logAddrTopics.SetTxNum(txNum)
 	a.tracesFrom.SetTxNum(txNum)
 	a.tracesTo.SetTxNum(txNum)
 }
 
 type AggV3Collation struct {
-	logAddrs   map[string]*roaring64.Bitmap
-	logTopics  map[string]*roaring64.Bitmap
-	tracesFrom map[string]*roaring64.Bitmap
-	tracesTo   map[string]*roaring64.Bitmap
-	accounts   HistoryCollation
-	storage    HistoryCollation
-	code       HistoryCollation
+	logAddrs      map[string]*roaring64.Bitmap
+	logTopics     map[string]*roaring64.Bitmap
+	logAddrTopics map[string]*roaring64.Bitmap
+	tracesFrom    map[string]*roaring64.Bitmap
+	tracesTo      map[string]*roaring64.Bitmap
+	accounts      HistoryCollation
+	storage       HistoryCollation
+	code          HistoryCollation
 }
 
 func (c AggV3Collation) Close() {
@@ -378,6 +397,9 @@ func (c AggV3Collation) Close() {
 	for _, b := range c.logTopics {
 		bitmapdb.ReturnToPool64(b)
 	}
+	for _, b := range c.logAddrTopics {
+		bitmapdb.ReturnToPool64(b)
+	}
 	for _, b := range c.tracesFrom {
 		bitmapdb.ReturnToPool64(b)
 	}
@@ -487,6 +509,22 @@ func (a *Aggregator) buildFiles(ctx context.Context, step, txFrom, txTo uint64)
 	//go func() {
 	//	defer wg.Done()
 	//	var err error
+	if err = a.db.View(ctx, func(tx kv.Tx) error {
+		ac.logAddrTopics, err = a.logAddrTopics.collate(ctx, txFrom, txTo, tx)
+		return err
+	}); err != nil {
+		return sf, err
+		//errCh <- err
+	}
+
+	if sf.logAddrTopics, err = a.logAddrTopics.buildFiles(ctx, step, ac.logAddrTopics, a.ps); err != nil {
+		return sf, err
+		//errCh <- err
+	}
+	//}()
+	//go func() {
+	//	defer wg.Done()
+	//	var err error
 	if err = a.db.View(ctx, func(tx kv.Tx) error {
 		ac.tracesFrom, err = a.tracesFrom.collate(ctx, txFrom, txTo, tx)
 		return err
@@ -533,13 +571,14 @@ func (a *Aggregator) buildFiles(ctx context.Context, step, txFrom, txTo uint64)
 }
 
 type AggV3StaticFiles struct {
-	accounts   HistoryFiles
-	storage    HistoryFiles
-	code       HistoryFiles
-	logAddrs   InvertedFiles
-	logTopics  InvertedFiles
-	tracesFrom InvertedFiles
-	tracesTo   InvertedFiles
+	accounts      HistoryFiles
+	storage       HistoryFiles
+	code          HistoryFiles
+	logAddrs      InvertedFiles
+	logTopics     InvertedFiles
+	logAddrTopics InvertedFiles
+	tracesFrom    InvertedFiles
+	tracesTo      InvertedFiles
 }
 
 func (sf AggV3StaticFiles) Close() {
@@ -548,6 +587,7 @@ func (sf AggV3StaticFiles) Close() {
 	sf.code.Close()
 	sf.logAddrs.Close()
 	sf.logTopics.Close()
+	sf.logAddrTopics.Close()
 	sf.tracesFrom.Close()
 	sf.tracesTo.Close()
 }
@@ -654,6 +694,7 @@ func (a *Aggregator) integrateFiles(sf AggV3StaticFiles, txNumFrom, txNumTo uint
 	a.code.integrateFiles(sf.code, txNumFrom, txNumTo)
 	a.logAddrs.integrateFiles(sf.logAddrs, txNumFrom, txNumTo)
 	a.logTopics.integrateFiles(sf.logTopics, txNumFrom, txNumTo)
+	a.logAddrTopics.integrateFiles(sf.logAddrTopics, txNumFrom, txNumTo)
 	a.tracesFrom.integrateFiles(sf.tracesFrom, txNumFrom, txNumTo)
 	a.tracesTo.integrateFiles(sf.tracesTo, txNumFrom, txNumTo)
 }
@@ -683,6 +724,9 @@ func (a *Aggregator) Unwind(ctx context.Context, txUnwindTo uint64) error {
 	if err := a.logTopics.prune(ctx, txUnwindTo, math2.MaxUint64, math2.MaxUint64, logEvery); err != nil {
 		return err
 	}
+	if err := a.logAddrTopics.prune(ctx, txUnwindTo, math2.MaxUint64, math2.MaxUint64, logEvery); err != nil {
+		return err
+	}
 	if err := a.tracesFrom.prune(ctx, txUnwindTo, math2.MaxUint64, math2.MaxUint64, logEvery); err != nil {
 		return err
 	}
@@ -712,6 +756,9 @@ func (a *Aggregator) Warmup(ctx context.Context, txFrom, limit uint64) error {
 	e.Go(func() error {
 		return a.db.View(ctx, func(tx kv.Tx) error { return a.logTopics.warmup(ctx, txFrom, limit, tx) })
 	})
+	e.Go(func() error {
+		return a.db.View(ctx, func(tx kv.Tx) error { return a.logAddrTopics.warmup(ctx, txFrom, limit, tx) })
+	})
 	e.Go(func() error {
 		return a.db.View(ctx, func(tx kv.Tx) error { return a.tracesFrom.warmup(ctx, txFrom, limit, tx) })
 	})
@@ -728,6 +775,7 @@ func (a *Aggregator) DiscardHistory() *Aggregator {
 	a.code.DiscardHistory()
 	a.logAddrs.DiscardHistory(a.tmpdir)
 	a.logTopics.DiscardHistory(a.tmpdir)
+	a.logAddrTopics.DiscardHistory(a.tmpdir)
 	a.tracesFrom.DiscardHistory(a.tmpdir)
 	a.tracesTo.DiscardHistory(a.tmpdir)
 	return a
@@ -742,6 +790,7 @@ func (a *Aggregator) StartWrites() *Aggregator {
 	a.code.StartWrites()
 	a.logAddrs.StartWrites()
 	a.logTopics.StartWrites()
+	a.logAddrTopics.StartWrites()
 	a.tracesFrom.StartWrites()
 	a.tracesTo.StartWrites()
 	return a
@@ -754,6 +803,7 @@ func (a *Aggregator) StartUnbufferedWrites() *Aggregator {
 	a.code.StartWrites()
 	a.logAddrs.StartWrites()
 	a.logTopics.StartWrites()
+	a.logAddrTopics.StartWrites()
 	a.tracesFrom.StartWrites()
 	a.tracesTo.StartWrites()
 	return a
@@ -766,6 +816,7 @@ func (a *Aggregator) FinishWrites() {
 	a.code.FinishWrites()
 	a.logAddrs.FinishWrites()
 	a.logTopics.FinishWrites()
+	a.logAddrTopics.FinishWrites()
 	a.tracesFrom.FinishWrites()
 	a.tracesTo.FinishWrites()
 }
@@ -783,6 +834,7 @@ func (a *Aggregator) rotate() []flusher {
 		a.code.Rotate(),
 		a.logAddrs.Rotate(),
 		a.logTopics.Rotate(),
+		a.logAddrTopics.Rotate(),
 		a.tracesFrom.Rotate(),
 		a.tracesTo.Rotate(),
 	}
@@ -829,6 +881,7 @@ func (a *Aggregator) StepsRangeInDBAsStr(tx kv.Tx) string {
 		a.code.stepsRangeInDBAsStr(tx),
 		a.logAddrs.stepsRangeInDBAsStr(tx),
 		a.logTopics.stepsRangeInDBAsStr(tx),
+		a.logAddrTopics.stepsRangeInDBAsStr(tx),
 		a.tracesFrom.stepsRangeInDBAsStr(tx),
 		a.tracesTo.stepsRangeInDBAsStr(tx),
 	}, ", ")
@@ -866,6 +919,9 @@ func (a *Aggregator) prune(ctx context.Context, txFrom, txTo, limit uint64) erro
 	if err := a.logTopics.prune(ctx, txFrom, txTo, limit, logEvery); err != nil {
 		return err
 	}
+	if err := a.logAddrTopics.prune(ctx, txFrom, txTo, limit, logEvery); err != nil {
+		return err
+	}
 	if err := a.tracesFrom.prune(ctx, txFrom, txTo, limit, logEvery); err != nil {
 		return err
 	}
@@ -915,6 +971,7 @@ func (a *Aggregator) LogStats(tx kv.Tx, tx2block func(endTxNumMinimax uint64) ui
 }
 
 func (a *Aggregator) EndTxNumMinimax() uint64 { return a.minimaxTxNumInFiles.Load() }
+func (a *Aggregator) StepSize() uint64        { return a.aggregationStep }
 func (a *Aggregator) EndTxNumFrozenAndIndexed() uint64 {
 	return cmp.Min(
 		cmp.Min(
@@ -938,6 +995,8 @@ func (a *Aggregator) recalcMaxTxNum() {
 	if txNum := a.logTopics.endTxNumMinimax(); txNum < min {
 		min = txNum
 	}
+	// logAddrTopics isn't here: it has no files for the steps built before it was added and history v2 builds
+	// its files alone, see FreezeLogAddrTopics
 	if txNum := a.tracesFrom.endTxNumMinimax(); txNum < min {
 		min = txNum
 	}
@@ -948,25 +1007,28 @@ func (a *Aggregator) recalcMaxTxNum() {
 }
 
 type RangesV3 struct {
-	accounts             HistoryRanges
-	storage              HistoryRanges
-	code                 HistoryRanges
-	logTopicsStartTxNum  uint64
-	logAddrsEndTxNum     uint64
-	logAddrsStartTxNum   uint64
-	logTopicsEndTxNum    uint64
-	tracesFromStartTxNum uint64
-	tracesFromEndTxNum   uint64
-	tracesToStartTxNum   uint64
-	tracesToEndTxNum     uint64
-	logAddrs             bool
-	logTopics            bool
-	tracesFrom           bool
-	tracesTo             bool
+	accounts                HistoryRanges
+	storage                 HistoryRanges
+	code                    HistoryRanges
+	logTopicsStartTxNum     uint64
+	logAddrTopicsStartTxNum uint64
+	logAddrsEndTxNum        uint64
+	logAddrsStartTxNum      uint64
+	logTopicsEndTxNum       uint64
+	logAddrTopicsEndTxNum   uint64
+	tracesFromStartTxNum    uint64
+	tracesFromEndTxNum      uint64
+	tracesToStartTxNum      uint64
+	tracesToEndTxNum        uint64
+	logAddrs                bool
+	logTopics               bool
+	logAddrTopics           bool
+	tracesFrom              bool
+	tracesTo                bool
 }
 
 func (r RangesV3) any() bool {
-	return r.accounts.any() || r.storage.any() || r.code.any() || r.logAddrs || r.logTopics || r.tracesFrom || r.tracesTo
+	return r.accounts.any() || r.storage.any() || r.code.any() || r.logAddrs || r.logTopics || r.logAddrTopics || r.tracesFrom || r.tracesTo
 }
 
 func (ac *AggregatorRoTx) findMergeRange(maxEndTxNum, maxSpan uint64) RangesV3 {
@@ -976,6 +1038,8 @@ func (ac *AggregatorRoTx) findMergeRange(maxEndTxNum, maxSpan uint64) RangesV3 {
 	r.code = ac.a.code.findMergeRange(maxEndTxNum, maxSpan)
 	r.logAddrs, r.logAddrsStartTxNum, r.logAddrsEndTxNum = ac.a.logAddrs.findMergeRange(maxEndTxNum, maxSpan)
 	r.logTopics, r.logTopicsStartTxNum, r.logTopicsEndTxNum = ac.a.logTopics.findMergeRange(maxEndTxNum, maxSpan)
+	// logAddrTopics files may be built alone, see FreezeLogAddrTopics
+	r.logAddrTopics, r.logAddrTopicsStartTxNum, r.logAddrTopicsEndTxNum = ac.a.logAddrTopics.findMergeRange(ac.a.logAddrTopics.endTxNumMinimax(), maxSpan)
 	r.tracesFrom, r.tracesFromStartTxNum, r.tracesFromEndTxNum = ac.a.tracesFrom.findMergeRange(maxEndTxNum, maxSpan)
 	r.tracesTo, r.tracesToStartTxNum, r.tracesToEndTxNum = ac.a.tracesTo.findMergeRange(maxEndTxNum, maxSpan)
 	//log.Info(fmt.Sprintf("findMergeRange(%d, %d)=%+v\n", maxEndTxNum, maxSpan, r))
@@ -983,28 +1047,30 @@ func (ac *AggregatorRoTx) findMergeRange(maxEndTxNum, maxSpan uint64) RangesV3 {
 }
 
 type SelectedStaticFilesV3 struct {
-	logTopics    []*filesItem
-	accountsHist []*filesItem
-	tracesTo     []*filesItem
-	storageIdx   []*filesItem
-	storageHist  []*filesItem
-	tracesFrom   []*filesItem
-	codeIdx      []*filesItem
-	codeHist     []*filesItem
-	accountsIdx  []*filesItem
-	logAddrs     []*filesItem
-	codeI        int
-	logAddrsI    int
-	logTopicsI   int
-	storageI     int
-	tracesFromI  int
-	accountsI    int
-	tracesToI    int
+	logTopics      []*filesItem
+	logAddrTopics  []*filesItem
+	accountsHist   []*filesItem
+	tracesTo       []*filesItem
+	storageIdx     []*filesItem
+	storageHist    []*filesItem
+	tracesFrom     []*filesItem
+	codeIdx        []*filesItem
+	codeHist       []*filesItem
+	accountsIdx    []*filesItem
+	logAddrs       []*filesItem
+	codeI          int
+	logAddrsI      int
+	logTopicsI     int
+	logAddrTopicsI int
+	storageI       int
+	tracesFromI    int
+	accountsI      int
+	tracesToI      int
 }
 
 func (sf SelectedStaticFilesV3) Close() {
 	for _, group := range [][]*filesItem{sf.accountsIdx, sf.accountsHist, sf.storageIdx, sf.accountsHist, sf.codeIdx, sf.codeHist,
-		sf.logAddrs, sf.logTopics, sf.tracesFrom, sf.tracesTo} {
+		sf.logAddrs, sf.logTopics, sf.logAddrTopics, sf.tracesFrom, sf.tracesTo} {
 		for _, item := range group {
 			if item != nil {
 				if item.decompressor != nil {
@@ -1043,6 +1109,9 @@ func (ac *AggregatorRoTx) staticFilesInRange(r RangesV3) (sf SelectedStaticFiles
 	if r.logTopics {
 		sf.logTopics, sf.logTopicsI = ac.logTopics.staticFilesInRange(r.logTopicsStartTxNum, r.logTopicsEndTxNum)
 	}
+	if r.logAddrTopics {
+		sf.logAddrTopics, sf.logAddrTopicsI = ac.logAddrTopics.staticFilesInRange(r.logAddrTopicsStartTxNum, r.logAddrTopicsEndTxNum)
+	}
 	if r.tracesFrom {
 		sf.tracesFrom, sf.tracesFromI = ac.tracesFrom.staticFilesInRange(r.tracesFromStartTxNum, r.tracesFromEndTxNum)
 	}
@@ -1058,6 +1127,7 @@ type MergedFilesV3 struct {
 	codeIdx, codeHist         *filesItem
 	logAddrs                  *filesItem
 	logTopics                 *filesItem
+	logAddrTopics             *filesItem
 	tracesFrom                *filesItem
 	tracesTo                  *filesItem
 }
@@ -1090,6 +1160,9 @@ func (mf MergedFilesV3) FrozenList() (frozen []string) {
 	if mf.logTopics != nil && mf.logTopics.frozen {
 		frozen = append(frozen, mf.logTopics.decompressor.FileName())
 	}
+	if mf.logAddrTopics != nil && mf.logAddrTopics.frozen {
+		frozen = append(frozen, mf.logAddrTopics.decompressor.FileName())
+	}
 	if mf.tracesFrom != nil && mf.tracesFrom.frozen {
 		frozen = append(frozen, mf.tracesFrom.decompressor.FileName())
 	}
@@ -1100,7 +1173,7 @@ func (mf MergedFilesV3) FrozenList() (frozen []string) {
 }
 func (mf MergedFilesV3) Close() {
 	for _, item := range []*filesItem{mf.accountsIdx, mf.accountsHist, mf.storageIdx, mf.storageHist, mf.codeIdx, mf.codeHist,
-		mf.logAddrs, mf.logTopics, mf.tracesFrom, mf.tracesTo} {
+		mf.logAddrs, mf.logTopics, mf.logAddrTopics, mf.tracesFrom, mf.tracesTo} {
 		if item != nil {
 			if item.decompressor != nil {
 				item.decompressor.Close()
@@ -1158,6 +1231,13 @@ func (ac *AggregatorRoTx) mergeFiles(ctx context.Context, files SelectedStaticFi
 			return err
 		})
 	}
+	if r.logAddrTopics {
+		g.Go(func() error {
+			var err error
+			mf.logAddrTopics, err = ac.a.logAddrTopics.mergeFiles(ctx, files.logAddrTopics, r.logAddrTopicsStartTxNum, r.logAddrTopicsEndTxNum, workers, ac.a.ps)
+			return err
+		})
+	}
 	if r.tracesFrom {
 		g.Go(func() error {
 			var err error
@@ -1189,6 +1269,7 @@ func (a *Aggregator) integrateMergedFiles(outs SelectedStaticFilesV3, in MergedF
 	a.code.integrateMergedFiles(outs.codeIdx, outs.codeHist, in.codeIdx, in.codeHist)
 	a.logAddrs.integrateMergedFiles(outs.logAddrs, in.logAddrs)
 	a.logTopics.integrateMergedFiles(outs.logTopics, in.logTopics)
+	a.logAddrTopics.integrateMergedFiles(outs.logAddrTopics, in.logAddrTopics)
 	a.tracesFrom.integrateMergedFiles(outs.tracesFrom, in.tracesFrom)
 	a.tracesTo.integrateMergedFiles(outs.tracesTo, in.tracesTo)
 	a.cleanAfterNewFreeze(in)
@@ -1210,6 +1291,9 @@ func (a *Aggregator) cleanAfterNewFreeze(in MergedFilesV3) {
 	if in.logTopics != nil && in.logTopics.frozen {
 		a.logTopics.cleanAfterFreeze(in.logTopics.endTxNum)
 	}
+	if in.logAddrTopics != nil && in.logAddrTopics.frozen {
+		a.logAddrTopics.cleanAfterFreeze(in.logAddrTopics.endTxNum)
+	}
 	if in.tracesFrom != nil && in.tracesFrom.frozen {
 		a.tracesFrom.cleanAfterFreeze(in.tracesFrom.endTxNum)
 	}
@@ -1280,6 +1364,34 @@ func (a *Aggregator) BuildFilesInBackground(txNum uint64) {
 	}()
 }
 
+// LogAddrTopicsFrozenTo - end of the address+topic0 log index files
+func (a *Aggregator) LogAddrTopicsFrozenTo() uint64 {
+	a.filesMutationLock.Lock()
+	defer a.filesMutationLock.Unlock()
+	return a.logAddrTopics.endTxNumMinimax()
+}
+
+// FreezeLogAddrTopics - builds the address+topic0 log index file of step from bitmaps and merges it with older files.
+// History v3 builds this index with the other ones, history v2 keeps it in the LogAddressTopicIndex table and freezes
+// its old blocks here: block numbers take the place of txNums.
+func (a *Aggregator) FreezeLogAddrTopics(ctx context.Context, step uint64, bitmaps map[string]*roaring64.Bitmap) error {
+	sf, err := a.logAddrTopics.buildFiles(ctx, step, bitmaps, a.ps)
+	if err != nil {
+		return err
+	}
+	a.filesMutationLock.Lock()
+	a.logAddrTopics.integrateFiles(sf, step*a.aggregationStep, (step+1)*a.aggregationStep)
+	a.filesMutationLock.Unlock()
+	a.needSaveFilesListInDB.Store(true)
+	a.onFreeze(nil) // readers must reopen files before the step leaves the DB
+
+	if ok := a.mergeingFiles.CompareAndSwap(false, true); !ok {
+		return nil
+	}
+	defer a.mergeingFiles.Store(false)
+	return a.MergeLoop(ctx, 1)
+}
+
 func (a *Aggregator) BatchHistoryWriteStart() *Aggregator {
 	a.walLock.RLock()
 	return a
@@ -1312,6 +1424,8 @@ func (a *Aggregator) PutIdx(idx kv.InvertedIdx, key []byte) error {
 		return a.logAddrs.Add(key)
 	case kv.LogTopicIndex:
 		return a.logTopics.Add(key)
+	case kv.TblLogAddrTopicsIdx:
+		return a.logAddrTopics.Add(key)
 	default:
 		panic(idx)
 	}
@@ -1324,6 +1438,7 @@ func (a *Aggregator) DisableReadAhead() {
 	a.code.DisableReadAhead()
 	a.logAddrs.DisableReadAhead()
 	a.logTopics.DisableReadAhead()
+	a.logAddrTopics.DisableReadAhead()
 	a.tracesFrom.DisableReadAhead()
 	a.tracesTo.DisableReadAhead()
 }
@@ -1333,6 +1448,7 @@ func (a *Aggregator) EnableReadAhead() *Aggregator {
 	a.code.EnableReadAhead()
 	a.logAddrs.EnableReadAhead()
 	a.logTopics.EnableReadAhead()
+	a.logAddrTopics.EnableReadAhead()
 	a.tracesFrom.EnableReadAhead()
 	a.tracesTo.EnableReadAhead()
 	return a
@@ -1343,6 +1459,7 @@ func (a *Aggregator) EnableMadvWillNeed() *Aggregator {
 	a.code.EnableMadvWillNeed()
 	a.logAddrs.EnableMadvWillNeed()
 	a.logTopics.EnableMadvWillNeed()
+	a.logAddrTopics.EnableMadvWillNeed()
 	a.tracesFrom.EnableMadvWillNeed()
 	a.tracesTo.EnableMadvWillNeed()
 	return a
@@ -1353,6 +1470,7 @@ func (a *Aggregator) EnableMadvNormal() *Aggregator {
 	a.code.EnableMadvNormalReadAhead()
 	a.logAddrs.EnableMadvNormalReadAhead()
 	a.logTopics.EnableMadvNormalReadAhead()
+	a.logAddrTopics.EnableMadvNormalReadAhead()
 	a.tracesFrom.EnableMadvNormalReadAhead()
 	a.tracesTo.EnableMadvNormalReadAhead()
 	return a
@@ -1368,6 +1486,8 @@ func (ac *AggregatorRoTx) IndexRange(name kv.InvertedIdx, k []byte, fromTs, toTs
 		return ac.code.IdxRange(k, fromTs, toTs, asc, limit, tx)
 	case kv.LogTopicIdx:
 		return ac.logTopics.IdxRange(k, fromTs, toTs, asc, limit, tx)
+	case kv.LogAddrTopicIdx:
+		return ac.logAddrTopics.IdxRange(k, fromTs, toTs, asc, limit, tx)
 	case kv.LogAddrIdx:
 		return ac.logAddrs.IdxRange(k, fromTs, toTs, asc, limit, tx)
 	case kv.TracesFromIdx:
@@ -1469,29 +1589,31 @@ func (a *Aggregator) Stats() FilesStats22 {
 }
 
 type AggregatorRoTx struct {
-	a          *Aggregator
-	accounts   *HistoryRoTx
-	storage    *HistoryRoTx
-	code       *HistoryRoTx
-	logAddrs   *InvertedIndexRoTx
-	logTopics  *InvertedIndexRoTx
-	tracesFrom *InvertedIndexRoTx
-	tracesTo   *InvertedIndexRoTx
-	keyBuf     []byte
+	a             *Aggregator
+	accounts      *HistoryRoTx
+	storage       *HistoryRoTx
+	code          *HistoryRoTx
+	logAddrs      *InvertedIndexRoTx
+	logTopics     *InvertedIndexRoTx
+	logAddrTopics *InvertedIndexRoTx
+	tracesFrom    *InvertedIndexRoTx
+	tracesTo      *InvertedIndexRoTx
+	keyBuf        []byte
 
 	id uint64 // set only if TRACE_AGG=true
 }
 
 func (a *Aggregator) BeginFilesRo() *AggregatorRoTx {
 	ac := &AggregatorRoTx{
-		a:          a,
-		accounts:   a.accounts.BeginFilesRo(),
-		storage:    a.storage.BeginFilesRo(),
-		code:       a.code.BeginFilesRo(),
-		logAddrs:   a.logAddrs.BeginFilesRo(),
-		logTopics:  a.logTopics.BeginFilesRo(),
-		tracesFrom: a.tracesFrom.BeginFilesRo(),
-		tracesTo:   a.tracesTo.BeginFilesRo(),
+		a:             a,
+		accounts:      a.accounts.BeginFilesRo(),
+		storage:       a.storage.BeginFilesRo(),
+		code:          a.code.BeginFilesRo(),
+		logAddrs:      a.logAddrs.BeginFilesRo(),
+		logTopics:     a.logTopics.BeginFilesRo(),
+		logAddrTopics: a.logAddrTopics.BeginFilesRo(),
+		tracesFrom:    a.tracesFrom.BeginFilesRo(),
+		tracesTo:      a.tracesTo.BeginFilesRo(),
 
 		id: a.leakDetector.Add(),
 	}
@@ -1505,6 +1627,7 @@ func (ac *AggregatorRoTx) Close() {
 	ac.code.Close()
 	ac.logAddrs.Close()
 	ac.logTopics.Close()
+	ac.logAddrTopics.Close()
 	ac.tracesFrom.Close()
 	ac.tracesTo.Close()
 }
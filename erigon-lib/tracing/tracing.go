@@ -0,0 +1,83 @@
+// Package tracing exports OpenTelemetry spans over OTLP/HTTP.
+//
+// Spans are no-ops until Setup is called, so hot paths can be instrumented unconditionally. Callers that need
+// to build expensive attributes should check Enabled first.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/ledgerwatch/erigon"
+
+var enabled atomic.Bool
+
+// Enabled reports whether spans are being recorded.
+func Enabled() bool { return enabled.Load() }
+
+// Recording reports whether the span in ctx is being recorded. Hot paths, like database reads, check it to
+// only add child spans to sampled requests instead of starting a root span per call.
+func Recording(ctx context.Context) bool {
+	return enabled.Load() && trace.SpanFromContext(ctx).IsRecording()
+}
+
+// StartSpan starts a child span of the span in ctx. When tracing is disabled ctx is returned unchanged together
+// with a span which does nothing.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !enabled.Load() {
+		return ctx, noop.Span{}
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records err on span, if any, and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type Config struct {
+	Endpoint    string  // OTLP/HTTP collector URL, e.g. http://localhost:4318
+	ServiceName string  // reported as service.name
+	SampleRatio float64 // fraction of root spans sampled, children follow their parent
+}
+
+// Setup installs a global tracer provider exporting to the configured collector and enables spans.
+// The returned function flushes pending spans and must be called on shutdown.
+func Setup(ctx context.Context, cfg Config, logger log.Logger) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	enabled.Store(true)
+
+	logger.Info("Enabling OpenTelemetry trace export", "endpoint", cfg.Endpoint, "sampleRatio", cfg.SampleRatio)
+	return func(ctx context.Context) error {
+		enabled.Store(false)
+		return provider.Shutdown(ctx)
+	}, nil
+}
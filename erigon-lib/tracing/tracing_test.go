@@ -0,0 +1,49 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/ledgerwatch/erigon-lib/tracing"
+	"github.com/ledgerwatch/erigon-lib/tracing/tracingtest"
+)
+
+func TestDisabledSpansAreNoop(t *testing.T) {
+	ctx := context.Background()
+	spanCtx, span := tracing.StartSpan(ctx, "noop")
+	require.Equal(t, ctx, spanCtx)
+	require.False(t, span.SpanContext().IsValid())
+	tracing.EndSpan(span, errors.New("ignored"))
+}
+
+func TestExportToCollector(t *testing.T) {
+	c := tracingtest.NewCollector(t)
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{Endpoint: c.URL, ServiceName: "erigon-test", SampleRatio: 1}, log.New())
+	require.NoError(t, err)
+	require.True(t, tracing.Enabled())
+
+	require.False(t, tracing.Recording(context.Background()))
+	ctx, parent := tracing.StartSpan(context.Background(), "rpc.eth_call", attribute.String("rpc.method", "eth_call"))
+	require.True(t, tracing.Recording(ctx))
+	_, child := tracing.StartSpan(ctx, "kvcache.Get", attribute.Bool("cache.hit", false))
+	tracing.EndSpan(child, errors.New("boom"))
+	tracing.EndSpan(parent, nil)
+
+	require.NoError(t, shutdown(context.Background()))
+	require.False(t, tracing.Enabled())
+
+	require.Len(t, c.Spans(), 2)
+	rpcSpan, cacheSpan := c.Span("rpc.eth_call"), c.Span("kvcache.Get")
+	require.NotNil(t, rpcSpan)
+	require.NotNil(t, cacheSpan)
+	require.Equal(t, rpcSpan.SpanId, cacheSpan.ParentSpanId)
+	require.Equal(t, tracepb.Status_STATUS_CODE_ERROR, cacheSpan.Status.Code)
+	require.Equal(t, "eth_call", tracingtest.Attribute(rpcSpan, "rpc.method"))
+	require.Equal(t, false, tracingtest.Attribute(cacheSpan, "cache.hit"))
+}
@@ -0,0 +1,92 @@
+// Package tracingtest provides an in-process stand-in for an OTLP/HTTP collector.
+package tracingtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Collector keeps every span exported to it.
+type Collector struct {
+	URL string
+
+	mu    sync.Mutex
+	spans []*tracepb.Span
+}
+
+// NewCollector starts a collector which is closed when the test ends.
+func NewCollector(t testing.TB) *Collector {
+	c := &Collector{}
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	c.URL = srv.URL
+	return c
+}
+
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || r.URL.Path != "/v1/traces" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+	c.mu.Unlock()
+	resp, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(resp)
+}
+
+// Spans returns the spans received so far.
+func (c *Collector) Spans() []*tracepb.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*tracepb.Span(nil), c.spans...)
+}
+
+// Span returns the first received span with the given name, or nil.
+func (c *Collector) Span(name string) *tracepb.Span {
+	for _, s := range c.Spans() {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Attribute returns the value of the span attribute with the given key, or nil.
+func Attribute(span *tracepb.Span, key string) interface{} {
+	for _, kv := range span.Attributes {
+		if kv.Key != key {
+			continue
+		}
+		switch v := kv.Value.Value.(type) {
+		case *commonpb.AnyValue_StringValue:
+			return v.StringValue
+		case *commonpb.AnyValue_BoolValue:
+			return v.BoolValue
+		case *commonpb.AnyValue_IntValue:
+			return v.IntValue
+		case *commonpb.AnyValue_DoubleValue:
+			return v.DoubleValue
+		}
+	}
+	return nil
+}
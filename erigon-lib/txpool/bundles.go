@@ -0,0 +1,177 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// MaxBundleBlocksAhead limits how far in the future a bundle may target, bundles are kept in memory until their
+// last block is mined, or until the block including one of their transactions is finalized
+const MaxBundleBlocksAhead = 1_000
+
+var (
+	ErrEmptyBundleRange = errors.New("bundle block or timestamp range is empty")
+	ErrBundleExpired    = errors.New("bundle max block number is already mined")
+	ErrBundlePoolFull   = errors.New("bundle pool is full")
+	ErrBundlePolicy     = errors.New("bundle rejected by a txpool policy")
+
+	bundlesGauge = metrics.GetOrCreateGauge(`txpool_bundles`)
+)
+
+// AddBundle keeps the bundle for the block builder until it's mined or its last block is. A bundle mined in a block
+// which is unwound later is offered to the block builder again
+func (p *TxPool) AddBundle(bundle *types.Bundle) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if bundle.MinBlock > bundle.MaxBlock || (bundle.MaxTimestamp != 0 && bundle.MinTimestamp > bundle.MaxTimestamp) {
+		return ErrEmptyBundleRange
+	}
+	lastSeenBlock := p.lastSeenBlock.Load()
+	if bundle.MaxBlock <= lastSeenBlock {
+		return ErrBundleExpired
+	}
+	if bundle.MinBlock > lastSeenBlock+MaxBundleBlocksAhead {
+		return fmt.Errorf("bundle min block number %d is more than %d blocks ahead of %d", bundle.MinBlock, MaxBundleBlocksAhead, lastSeenBlock)
+	}
+	if _, ok := p.bundles[bundle.Hash]; !ok && len(p.bundles) >= p.cfg.BundleSlots {
+		return ErrBundlePoolFull
+	}
+	if i, reason, name := p.checkBundleLocked(bundle, policyStageAdmission); reason != txpoolcfg.Success {
+		return fmt.Errorf("%w: transaction %d: %s (policy %s)", ErrBundlePolicy, i, reason, name)
+	}
+	p.bundles[bundle.Hash] = bundle
+	bundlesGauge.SetInt(len(p.bundles))
+	return nil
+}
+
+// Bundles returns the bundles which may be included in the block, in no particular order. The policies may have
+// changed since a bundle was added, the bundles they reject now are dropped
+func (p *TxPool) Bundles(blockNum, timestamp uint64) []*types.Bundle {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var res []*types.Bundle
+	for hash, bundle := range p.bundles {
+		if !bundle.Targets(blockNum, timestamp) {
+			continue
+		}
+		if i, reason, name := p.checkBundleLocked(bundle, policyStageYield); reason != txpoolcfg.Success {
+			p.logger.Debug("[txpool] Dropping bundle rejected by policy", "hash", fmt.Sprintf("%x", hash), "txn", i, "policy", name, "reason", reason)
+			delete(p.bundles, hash)
+			continue
+		}
+		res = append(res, bundle)
+	}
+	bundlesGauge.SetInt(len(p.bundles))
+	return res
+}
+
+// checkBundleLocked runs every transaction of the bundle through the policies, a single rejection rejects the bundle
+func (p *TxPool) checkBundleLocked(bundle *types.Bundle, stage policyStage) (int, txpoolcfg.DiscardReason, string) {
+	for i, slot := range bundle.Slots {
+		if reason, name := p.policies.check(slot, bundle.Senders.AddressAt(i), stage); reason != txpoolcfg.Success {
+			return i, reason, name
+		}
+	}
+	return 0, txpoolcfg.Success, ""
+}
+
+// pruneBundlesLocked drops the bundles which can't be included after the block anymore: their last block is mined,
+// or one of their transactions is. The latter are kept aside until the block is finalized or their last block is
+// mined, so that restoreUnwoundBundlesLocked can bring them back if the block is unwound
+func (p *TxPool) pruneBundlesLocked(blockNum, finalizedBlock uint64, minedTxs types.TxSlots) {
+	for minedBlock, bundles := range p.minedBundlesByBlock {
+		if minedBlock <= finalizedBlock {
+			delete(p.minedBundlesByBlock, minedBlock)
+			continue
+		}
+		kept := bundles[:0]
+		for _, bundle := range bundles {
+			if bundle.MaxBlock > blockNum {
+				kept = append(kept, bundle)
+			}
+		}
+		if len(kept) == 0 {
+			delete(p.minedBundlesByBlock, minedBlock)
+		} else {
+			p.minedBundlesByBlock[minedBlock] = kept
+		}
+	}
+	if len(p.bundles) == 0 {
+		return
+	}
+	mined := make(map[[32]byte]struct{}, len(minedTxs.Txs))
+	for _, txn := range minedTxs.Txs {
+		mined[txn.IDHash] = struct{}{}
+	}
+PRUNE:
+	for hash, bundle := range p.bundles {
+		if bundle.MaxBlock <= blockNum {
+			delete(p.bundles, hash)
+			continue
+		}
+		for _, txHash := range bundle.TxHashes {
+			if _, ok := mined[txHash]; ok {
+				delete(p.bundles, hash)
+				p.minedBundlesByBlock[blockNum] = append(p.minedBundlesByBlock[blockNum], bundle)
+				continue PRUNE
+			}
+		}
+	}
+	bundlesGauge.SetInt(len(p.bundles))
+}
+
+// restoreUnwoundBundlesLocked puts the mined bundles back in the pool when one of their transactions is unwound,
+// as long as their last block isn't mined yet. Restored bundles may exceed BundleSlots, they held a slot before
+func (p *TxPool) restoreUnwoundBundlesLocked(blockNum uint64, unwindTxs, unwindBlobTxs types.TxSlots) {
+	if len(p.minedBundlesByBlock) == 0 || len(unwindTxs.Txs)+len(unwindBlobTxs.Txs) == 0 {
+		return
+	}
+	unwound := make(map[[32]byte]struct{}, len(unwindTxs.Txs)+len(unwindBlobTxs.Txs))
+	for _, txn := range unwindTxs.Txs {
+		unwound[txn.IDHash] = struct{}{}
+	}
+	for _, txn := range unwindBlobTxs.Txs {
+		unwound[txn.IDHash] = struct{}{}
+	}
+	for minedBlock, bundles := range p.minedBundlesByBlock {
+		kept := bundles[:0]
+	BUNDLES:
+		for _, bundle := range bundles {
+			for _, txHash := range bundle.TxHashes {
+				if _, ok := unwound[txHash]; ok {
+					if bundle.MaxBlock > blockNum {
+						p.bundles[bundle.Hash] = bundle
+					}
+					continue BUNDLES
+				}
+			}
+			kept = append(kept, bundle)
+		}
+		if len(kept) == 0 {
+			delete(p.minedBundlesByBlock, minedBlock)
+		} else {
+			p.minedBundlesByBlock[minedBlock] = kept
+		}
+	}
+	bundlesGauge.SetInt(len(p.bundles))
+}
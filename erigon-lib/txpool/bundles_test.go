@@ -0,0 +1,170 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/fixedgas"
+	"github.com/ledgerwatch/erigon-lib/common/u256"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+func TestBundles(t *testing.T) {
+	require := require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	cfg := txpoolcfg.DefaultConfig
+	cfg.BundleSlots = 3
+	pool, err := New(ch, coreDB, cfg, kvcache.New(kvcache.DefaultCoherentConfig), *u256.N1, nil, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	ctx := context.Background()
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+
+	var finalized uint64
+	newBlock := func(height uint64, unwind, mined types.TxSlots) {
+		change := &remote.StateChangeBatch{
+			PendingBlockBaseFee: 200_000,
+			BlockGasLimit:       1_000_000,
+			FinalizedBlock:      finalized,
+			ChangeBatch:         []*remote.StateChange{{BlockHeight: height, BlockHash: gointerfaces.ConvertHashToH256([32]byte{byte(height)})}},
+		}
+		require.NoError(pool.OnNewBlock(ctx, change, unwind, types.TxSlots{}, mined, tx))
+	}
+	newBlock(10, types.TxSlots{}, types.TxSlots{})
+
+	newBundle := func(id byte, minBlock, maxBlock uint64) *types.Bundle {
+		txHashes := [][32]byte{{id, 1}, {id, 2}}
+		return &types.Bundle{
+			Hash:     types.BundleHash(txHashes),
+			Txs:      [][]byte{{id, 1}, {id, 2}},
+			TxHashes: txHashes,
+			MinBlock: minBlock,
+			MaxBlock: maxBlock,
+		}
+	}
+
+	require.ErrorIs(pool.AddBundle(newBundle(1, 12, 11)), ErrEmptyBundleRange)
+	require.ErrorIs(pool.AddBundle(newBundle(1, 9, 10)), ErrBundleExpired)
+	require.ErrorContains(pool.AddBundle(newBundle(1, 11+MaxBundleBlocksAhead, 12+MaxBundleBlocksAhead)), "blocks ahead")
+	withTime := newBundle(1, 11, 11)
+	withTime.MinTimestamp, withTime.MaxTimestamp = 100, 99
+	require.ErrorIs(pool.AddBundle(withTime), ErrEmptyBundleRange)
+
+	next, later, minedLater := newBundle(1, 11, 11), newBundle(2, 12, 13), newBundle(3, 11, 20)
+	next.MinTimestamp, next.MaxTimestamp = 100, 200
+	for _, bundle := range []*types.Bundle{next, later, minedLater} {
+		require.NoError(pool.AddBundle(bundle))
+	}
+	require.ErrorIs(pool.AddBundle(newBundle(4, 11, 11)), ErrBundlePoolFull)
+	// replacing a bundle doesn't take a slot
+	require.NoError(pool.AddBundle(next))
+
+	require.ElementsMatch([]*types.Bundle{next, minedLater}, pool.Bundles(11, 150))
+	require.ElementsMatch([]*types.Bundle{minedLater}, pool.Bundles(11, 201))
+	require.ElementsMatch([]*types.Bundle{later, minedLater}, pool.Bundles(12, 0))
+
+	// the last block of next is mined, and so is a transaction of minedLater
+	var mined types.TxSlots
+	mined.Append(&types.TxSlot{IDHash: minedLater.TxHashes[1]}, make([]byte, 20), false)
+	newBlock(11, types.TxSlots{}, mined)
+	require.ElementsMatch([]*types.Bundle{later}, pool.Bundles(12, 0))
+	require.Len(pool.bundles, 1)
+
+	// block 11 is replaced by a block without the transaction of minedLater, which is offered again
+	newBlock(11, mined, types.TxSlots{})
+	require.ElementsMatch([]*types.Bundle{later, minedLater}, pool.Bundles(12, 0))
+
+	// once the block including it is finalized, a mined bundle is gone for good
+	newBlock(12, types.TxSlots{}, mined)
+	require.ElementsMatch([]*types.Bundle{later}, pool.Bundles(13, 0))
+	finalized = 12
+	newBlock(13, types.TxSlots{}, types.TxSlots{})
+	require.Empty(pool.bundles)
+	require.Empty(pool.minedBundlesByBlock)
+	newBlock(13, mined, types.TxSlots{})
+	require.Empty(pool.bundles)
+}
+
+func TestBundlePolicies(t *testing.T) {
+	require := require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	denied, allowed := common.Address{1}, common.Address{2}
+	file := filepath.Join(t.TempDir(), "policies.toml")
+	modTime := time.Now().Add(-time.Hour)
+	writePolicies(t, file, "[[deny]]\nsenders = [\""+denied.Hex()+"\"]\n", modTime)
+
+	cfg := txpoolcfg.DefaultConfig
+	cfg.PolicyFile = file
+	pool, err := New(ch, coreDB, cfg, kvcache.New(kvcache.DefaultCoherentConfig), *u256.N1, nil, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	ctx := context.Background()
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 200_000,
+		BlockGasLimit:       1_000_000,
+		ChangeBatch:         []*remote.StateChange{{BlockHeight: 10, BlockHash: gointerfaces.ConvertHashToH256([32]byte{10})}},
+	}
+	require.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx))
+
+	newBundle := func(id byte, senders ...common.Address) *types.Bundle {
+		bundle := &types.Bundle{MinBlock: 11, MaxBlock: 11}
+		for i, sender := range senders {
+			txHash := [32]byte{id, byte(i)}
+			bundle.Txs = append(bundle.Txs, []byte{id, byte(i)})
+			bundle.Slots = append(bundle.Slots, &types.TxSlot{IDHash: txHash})
+			bundle.TxHashes = append(bundle.TxHashes, txHash)
+			bundle.Senders = append(bundle.Senders, sender[:]...)
+		}
+		bundle.Hash = types.BundleHash(bundle.TxHashes)
+		return bundle
+	}
+
+	// a single transaction from a denied sender rejects the whole bundle
+	require.ErrorIs(pool.AddBundle(newBundle(1, allowed, denied)), ErrBundlePolicy)
+	require.Empty(pool.bundles)
+	bundle := newBundle(2, allowed, allowed)
+	require.NoError(pool.AddBundle(bundle))
+	require.Equal([]*types.Bundle{bundle}, pool.Bundles(11, 0))
+
+	// the rules changed after the bundle was added, it's dropped instead of being offered to the block builder
+	writePolicies(t, file, "[[deny]]\nsenders = [\""+allowed.Hex()+"\"]\n", modTime.Add(time.Minute))
+	reloaded, err := pool.policies.reload(pool.logger)
+	require.NoError(err)
+	require.True(reloaded)
+	require.Empty(pool.Bundles(11, 0))
+	require.Empty(pool.bundles)
+}
@@ -0,0 +1,280 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// Policy is an operator-defined admission rule. It's checked when a transaction is added to the pool, and again
+// before the transaction is yielded for a block, as the rules may have changed since it was admitted.
+type Policy interface {
+	// Name labels the rejections of the policy in logs and metrics
+	Name() string
+	// Check returns txpoolcfg.Success to admit the transaction, or the reason to reject it
+	Check(txn *types.TxSlot, sender common.Address) txpoolcfg.DiscardReason
+}
+
+// policyStage is where a policy rejected a transaction, it's a label of the rejection metrics
+type policyStage string
+
+const (
+	policyStageAdmission policyStage = "admission"
+	policyStageYield     policyStage = "yield"
+)
+
+// PolicyFile is the format of the rule file given by --txpool.policies, for example:
+//
+//	[[deny]]
+//	name = "sanctions"
+//	senders = ["0x..."]
+//	recipients = ["0x..."]
+//
+//	[[gas_cap]]
+//	contracts = ["0x..."]
+//	max_gas = 500000
+//
+//	[[min_tip]]
+//	tx_types = [2]         # all types when empty
+//	min_tip = 1000000000   # wei
+//
+//	[[max_da_size]]
+//	max_size = 65536       # bytes, after FastLZ compression as estimated for the L1 data fee
+//
+// Rules without a name are named after their kind and position, e.g. "gas_cap_1".
+type PolicyFile struct {
+	Deny      []DenyRule      `toml:"deny"`
+	GasCap    []GasCapRule    `toml:"gas_cap"`
+	MinTip    []MinTipRule    `toml:"min_tip"`
+	MaxDASize []MaxDASizeRule `toml:"max_da_size"`
+}
+
+type DenyRule struct {
+	Name       string           `toml:"name"`
+	Senders    []common.Address `toml:"senders"`
+	Recipients []common.Address `toml:"recipients"`
+}
+
+type GasCapRule struct {
+	Name      string           `toml:"name"`
+	Contracts []common.Address `toml:"contracts"`
+	MaxGas    uint64           `toml:"max_gas"`
+}
+
+type MinTipRule struct {
+	Name    string  `toml:"name"`
+	TxTypes []uint8 `toml:"tx_types"`
+	MinTip  uint64  `toml:"min_tip"`
+}
+
+type MaxDASizeRule struct {
+	Name    string `toml:"name"`
+	MaxSize uint64 `toml:"max_size"`
+}
+
+var policyNameRe = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// LoadPolicies parses the rule file into policies
+func LoadPolicies(file string) ([]Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var f PolicyFile
+	if err := toml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing txpool policies %s: %w", file, err)
+	}
+	var policies []Policy
+	for i, r := range f.Deny {
+		policies = append(policies, newDenyPolicy(policyName(r.Name, "deny", i), r))
+	}
+	for i, r := range f.GasCap {
+		policies = append(policies, newGasCapPolicy(policyName(r.Name, "gas_cap", i), r))
+	}
+	for i, r := range f.MinTip {
+		policies = append(policies, newMinTipPolicy(policyName(r.Name, "min_tip", i), r))
+	}
+	for i, r := range f.MaxDASize {
+		policies = append(policies, &maxDASizePolicy{name: policyName(r.Name, "max_da_size", i), maxSize: r.MaxSize})
+	}
+	names := map[string]struct{}{}
+	for _, p := range policies {
+		if !policyNameRe.MatchString(p.Name()) {
+			return nil, fmt.Errorf("txpool policy name %q: only letters, digits, '_', '.' and '-' are allowed", p.Name())
+		}
+		if _, ok := names[p.Name()]; ok {
+			return nil, fmt.Errorf("duplicate txpool policy name %q", p.Name())
+		}
+		names[p.Name()] = struct{}{}
+	}
+	return policies, nil
+}
+
+func policyName(name, kind string, i int) string {
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s_%d", kind, i)
+}
+
+type denyPolicy struct {
+	name       string
+	senders    map[common.Address]struct{}
+	recipients map[common.Address]struct{}
+}
+
+func newDenyPolicy(name string, r DenyRule) *denyPolicy {
+	p := &denyPolicy{name: name, senders: map[common.Address]struct{}{}, recipients: map[common.Address]struct{}{}}
+	for _, a := range r.Senders {
+		p.senders[a] = struct{}{}
+	}
+	for _, a := range r.Recipients {
+		p.recipients[a] = struct{}{}
+	}
+	return p
+}
+
+func (p *denyPolicy) Name() string { return p.name }
+
+func (p *denyPolicy) Check(txn *types.TxSlot, sender common.Address) txpoolcfg.DiscardReason {
+	if _, ok := p.senders[sender]; ok {
+		return txpoolcfg.SenderDenied
+	}
+	if _, ok := p.recipients[txn.To]; ok && !txn.Creation {
+		return txpoolcfg.RecipientDenied
+	}
+	return txpoolcfg.Success
+}
+
+type gasCapPolicy struct {
+	name      string
+	contracts map[common.Address]struct{}
+	maxGas    uint64
+}
+
+func newGasCapPolicy(name string, r GasCapRule) *gasCapPolicy {
+	p := &gasCapPolicy{name: name, contracts: map[common.Address]struct{}{}, maxGas: r.MaxGas}
+	for _, a := range r.Contracts {
+		p.contracts[a] = struct{}{}
+	}
+	return p
+}
+
+func (p *gasCapPolicy) Name() string { return p.name }
+
+func (p *gasCapPolicy) Check(txn *types.TxSlot, _ common.Address) txpoolcfg.DiscardReason {
+	if _, ok := p.contracts[txn.To]; ok && !txn.Creation && txn.Gas > p.maxGas {
+		return txpoolcfg.ContractGasCap
+	}
+	return txpoolcfg.Success
+}
+
+type minTipPolicy struct {
+	name    string
+	txTypes map[byte]struct{} // all types when empty
+	minTip  uint256.Int
+}
+
+func newMinTipPolicy(name string, r MinTipRule) *minTipPolicy {
+	p := &minTipPolicy{name: name, txTypes: map[byte]struct{}{}}
+	for _, t := range r.TxTypes {
+		p.txTypes[t] = struct{}{}
+	}
+	p.minTip.SetUint64(r.MinTip)
+	return p
+}
+
+func (p *minTipPolicy) Name() string { return p.name }
+
+func (p *minTipPolicy) Check(txn *types.TxSlot, _ common.Address) txpoolcfg.DiscardReason {
+	if _, ok := p.txTypes[txn.Type]; len(p.txTypes) > 0 && !ok {
+		return txpoolcfg.Success
+	}
+	if txn.Tip.Lt(&p.minTip) {
+		return txpoolcfg.PolicyTipTooLow
+	}
+	return txpoolcfg.Success
+}
+
+type maxDASizePolicy struct {
+	name    string
+	maxSize uint64
+}
+
+func (p *maxDASizePolicy) Name() string { return p.name }
+
+func (p *maxDASizePolicy) Check(txn *types.TxSlot, _ common.Address) txpoolcfg.DiscardReason {
+	if txn.RollupCostData.FastLzSize > p.maxSize {
+		return txpoolcfg.DASizeTooLarge
+	}
+	return txpoolcfg.Success
+}
+
+// policies holds the policies loaded from the rule file and the ones added in code
+type policies struct {
+	file     string
+	modTime  time.Time
+	fromFile []Policy
+	custom   []Policy
+}
+
+// check runs the policies in order and returns the first rejection, counting it in the metrics of the policy
+func (ps *policies) check(txn *types.TxSlot, sender common.Address, stage policyStage) (txpoolcfg.DiscardReason, string) {
+	for _, list := range [][]Policy{ps.custom, ps.fromFile} {
+		for _, p := range list {
+			if reason := p.Check(txn, sender); reason != txpoolcfg.Success {
+				metrics.GetOrCreateCounter(fmt.Sprintf(`txpool_policy_rejected_total{policy="%s",stage="%s"}`, p.Name(), stage)).Inc()
+				return reason, p.Name()
+			}
+		}
+	}
+	return txpoolcfg.Success, ""
+}
+
+// reload reads the rule file again if it was modified since the last load. A file which fails to load leaves the
+// previous policies in place.
+func (ps *policies) reload(logger log.Logger) (bool, error) {
+	if ps.file == "" {
+		return false, nil
+	}
+	stat, err := os.Stat(ps.file)
+	if err != nil {
+		return false, err
+	}
+	if stat.ModTime().Equal(ps.modTime) {
+		return false, nil
+	}
+	loaded, err := LoadPolicies(ps.file)
+	if err != nil {
+		return false, err
+	}
+	ps.fromFile, ps.modTime = loaded, stat.ModTime()
+	logger.Info("[txpool] Loaded admission policies", "file", ps.file, "policies", len(loaded))
+	return true, nil
+}
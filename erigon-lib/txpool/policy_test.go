@@ -0,0 +1,192 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/fixedgas"
+	"github.com/ledgerwatch/erigon-lib/common/u256"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+func writePolicies(t *testing.T, file, content string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	require.NoError(t, os.Chtimes(file, modTime, modTime))
+}
+
+func TestLoadPolicies(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policies.toml")
+	writePolicies(t, file, `
+[[deny]]
+name = "sanctions"
+senders = ["0x0000000000000000000000000000000000000001"]
+
+[[gas_cap]]
+contracts = ["0x0000000000000000000000000000000000000002"]
+max_gas = 50000
+
+[[gas_cap]]
+contracts = ["0x0000000000000000000000000000000000000003"]
+max_gas = 60000
+
+[[min_tip]]
+tx_types = [2]
+min_tip = 100
+
+[[max_da_size]]
+max_size = 200
+`, time.Now())
+	loaded, err := LoadPolicies(file)
+	require.NoError(t, err)
+	var names []string
+	for _, p := range loaded {
+		names = append(names, p.Name())
+	}
+	require.Equal(t, []string{"sanctions", "gas_cap_0", "gas_cap_1", "min_tip_0", "max_da_size_0"}, names)
+
+	to2 := &types.TxSlot{To: common.HexToAddress("0x02"), Gas: 50_001}
+	require.Equal(t, txpoolcfg.SenderDenied, loaded[0].Check(&types.TxSlot{}, common.HexToAddress("0x01")))
+	require.Equal(t, txpoolcfg.ContractGasCap, loaded[1].Check(to2, common.Address{}))
+	require.Equal(t, txpoolcfg.Success, loaded[2].Check(to2, common.Address{}))
+	to2.Creation = true
+	require.Equal(t, txpoolcfg.Success, loaded[1].Check(to2, common.Address{}))
+
+	lowTip := &types.TxSlot{Type: types.DynamicFeeTxType, Tip: *uint256.NewInt(99)}
+	require.Equal(t, txpoolcfg.PolicyTipTooLow, loaded[3].Check(lowTip, common.Address{}))
+	lowTip.Type = types.LegacyTxType
+	require.Equal(t, txpoolcfg.Success, loaded[3].Check(lowTip, common.Address{}))
+
+	large := &types.TxSlot{RollupCostData: types.RollupCostData{FastLzSize: 201}}
+	require.Equal(t, txpoolcfg.DASizeTooLarge, loaded[4].Check(large, common.Address{}))
+
+	writePolicies(t, file, "[[deny]]\nname = \"a\"\n[[min_tip]]\nname = \"a\"\n", time.Now())
+	_, err = LoadPolicies(file)
+	require.ErrorContains(t, err, "duplicate")
+	writePolicies(t, file, "[[deny]]\nname = \"a b\"\n", time.Now())
+	_, err = LoadPolicies(file)
+	require.ErrorContains(t, err, "only letters")
+}
+
+func TestPolicies(t *testing.T) {
+	require := require.New(t)
+	ch := make(chan types.Announcements, 100)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+
+	denied, allowed, contract := common.Address{1}, common.Address{2}, common.Address{0xc}
+	file := filepath.Join(t.TempDir(), "policies.toml")
+	modTime := time.Now().Add(-time.Hour)
+	writePolicies(t, file, "[[deny]]\nsenders = [\""+denied.Hex()+"\"]\n", modTime)
+
+	cfg := txpoolcfg.DefaultConfig
+	cfg.PolicyFile = file
+	pool, err := New(ch, coreDB, cfg, kvcache.New(kvcache.DefaultCoherentConfig), *u256.N1, nil, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	ctx := context.Background()
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 200_000,
+		BlockGasLimit:       1_000_000,
+		ChangeBatch:         []*remote.StateChange{{BlockHeight: 0, BlockHash: gointerfaces.ConvertHashToH256([32]byte{})}},
+	}
+	for _, addr := range []common.Address{denied, allowed} {
+		v := make([]byte, types.EncodeSenderLengthForStorage(2, *uint256.NewInt(1 * common.Ether)))
+		types.EncodeSender(2, *uint256.NewInt(1 * common.Ether), v)
+		change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+			Action:  remote.Action_UPSERT,
+			Address: gointerfaces.ConvertAddressToH160(addr),
+			Data:    v,
+		})
+	}
+	require.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx))
+
+	newTx := func(id byte, sender common.Address) types.TxSlots {
+		var txs types.TxSlots
+		txn := &types.TxSlot{
+			Tip:    *uint256.NewInt(300_000),
+			FeeCap: *uint256.NewInt(300_000),
+			Gas:    100_000,
+			Nonce:  2,
+			To:     contract,
+			Rlp:    []byte{id},
+		}
+		txn.IDHash[0] = id
+		txs.Append(txn, sender[:], true)
+		return txs
+	}
+
+	reasons, err := pool.AddLocalTxs(ctx, newTx(1, denied), tx)
+	require.NoError(err)
+	require.Equal([]txpoolcfg.DiscardReason{txpoolcfg.SenderDenied}, reasons)
+	reasons, err = pool.AddLocalTxs(ctx, newTx(2, allowed), tx)
+	require.NoError(err)
+	require.Equal([]txpoolcfg.DiscardReason{txpoolcfg.Success}, reasons)
+	require.Equal(1, pool.pending.Len())
+
+	// the rules changed after the transaction was admitted, it's dropped instead of being yielded
+	writePolicies(t, file, "[[gas_cap]]\ncontracts = [\""+contract.Hex()+"\"]\nmax_gas = 21000\n", modTime.Add(time.Minute))
+	reloaded, err := pool.policies.reload(pool.logger)
+	require.NoError(err)
+	require.True(reloaded)
+
+	// peeking skips it but leaves the pool untouched
+	var txs types.TxsRlp
+	_, err = pool.PeekBest(10, &txs, tx, 0, 1_000_000, 0)
+	require.NoError(err)
+	require.Zero(len(txs.Txs))
+	require.Equal(1, pool.pending.Len())
+
+	_, count, err := pool.YieldBest(10, &txs, tx, 0, 1_000_000, 0, mapset.NewThreadUnsafeSet[[32]byte]())
+	require.NoError(err)
+	require.Zero(count)
+	require.Zero(pool.pending.Len())
+	status := pool.TxStatus(append([]byte{2}, make([]byte, 31)...))
+	require.Equal(txpoolcfg.ContractGasCap, status.Discarded)
+
+	// a broken file keeps the previous rules
+	writePolicies(t, file, "[[gas_cap", modTime.Add(2*time.Minute))
+	_, err = pool.policies.reload(pool.logger)
+	require.Error(err)
+	require.Len(pool.policies.fromFile, 1)
+
+	pool.AddPolicy(&maxDASizePolicy{name: "custom", maxSize: 0})
+	txs3 := newTx(3, allowed)
+	txs3.Txs[0].To = common.Address{}
+	txs3.Txs[0].RollupCostData.FastLzSize = 1
+	reasons, err = pool.AddLocalTxs(ctx, txs3, tx)
+	require.NoError(err)
+	require.Equal([]txpoolcfg.DiscardReason{txpoolcfg.DASizeTooLarge}, reasons)
+}
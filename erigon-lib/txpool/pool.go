@@ -212,6 +212,7 @@ type TxPool struct {
 	queued                  *SubPool
 	minedBlobTxsByBlock     map[uint64][]*metaTx             // (blockNum => slice): cache of recently mined blobs
 	minedBlobTxsByHash      map[string]*metaTx               // (hash => mt): map of recently mined blobs
+	byAuthority             map[types.Authority]*metaTx      // (authority, nonce) => mt: EIP-7702 authorizations of the pooled txs
 	isLocalLRU              *simplelru.LRU[string, struct{}] // tx_hash => is_local : to restore isLocal flag of unwinded transactions
 	newPendingTxs           chan types.Announcements         // notifications about new txs in Pending sub-pool
 	all                     *BySenderAndNonce                // senderID => (sorted map of tx nonce => *metaTx)
@@ -233,6 +234,8 @@ type TxPool struct {
 	isPostAgra              atomic.Bool
 	cancunTime              *uint64
 	isPostCancun            atomic.Bool
+	pragueTime              *uint64
+	isPostPrague            atomic.Bool
 	maxBlobsPerBlock        uint64
 	feeCalculator           FeeCalculator
 	logger                  log.Logger
@@ -246,6 +249,11 @@ type TxPool struct {
 	isPostEcotone  atomic.Bool
 	fjordTime      *uint64
 	isPostFjord    atomic.Bool
+
+	policies policies                   // operator-defined admission rules, see Policy
+	bundles  map[[32]byte]*types.Bundle // bundle hash => bundle : non-persisted, see AddBundle
+	// block number => bundles with a transaction mined in the block, kept until it's finalized in case it's unwound
+	minedBundlesByBlock map[uint64][]*types.Bundle
 }
 
 type FeeCalculator interface {
@@ -253,7 +261,7 @@ type FeeCalculator interface {
 }
 
 func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config, cache kvcache.Cache,
-	chainID uint256.Int, shanghaiTime, agraBlock, cancunTime *big.Int,
+	chainID uint256.Int, shanghaiTime, agraBlock, cancunTime, pragueTime *big.Int,
 	regolithTime, canyonTime, ecotoneTime, fjordTime *big.Int,
 	maxBlobsPerBlock uint64, feeCalculator FeeCalculator, logger log.Logger,
 ) (*TxPool, error) {
@@ -300,6 +308,9 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		unprocessedRemoteByHash: map[string]int{},
 		minedBlobTxsByBlock:     map[uint64][]*metaTx{},
 		minedBlobTxsByHash:      map[string]*metaTx{},
+		byAuthority:             map[types.Authority]*metaTx{},
+		bundles:                 map[[32]byte]*types.Bundle{},
+		minedBundlesByBlock:     map[uint64][]*types.Bundle{},
 		maxBlobsPerBlock:        maxBlobsPerBlock,
 		feeCalculator:           feeCalculator,
 		logger:                  logger,
@@ -326,6 +337,13 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		cancunTimeU64 := cancunTime.Uint64()
 		res.cancunTime = &cancunTimeU64
 	}
+	if pragueTime != nil {
+		if !pragueTime.IsUint64() {
+			return nil, errors.New("pragueTime overflow")
+		}
+		pragueTimeU64 := pragueTime.Uint64()
+		res.pragueTime = &pragueTimeU64
+	}
 
 	if regolithTime != nil {
 		if !regolithTime.IsUint64() {
@@ -349,9 +367,21 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		res.fjordTime = &fjordTimeU64
 	}
 
+	res.policies.file = cfg.PolicyFile
+	if _, err := res.policies.reload(logger); err != nil {
+		return nil, err
+	}
+
 	return res, nil
 }
 
+// AddPolicy adds an admission policy, it's checked before the ones of the rule file
+func (p *TxPool) AddPolicy(policy Policy) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.policies.custom = append(p.policies.custom, policy)
+}
+
 func RawRLPTxToOptimismL1CostFn(payload []byte, isRegolith, isEcotone, isFjord bool) (types.L1CostFn, error) {
 	// skip prefix byte
 	if len(payload) == 0 {
@@ -502,6 +532,8 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 
 	pendingBlobFee := stateChanges.PendingBlobFeePerGas
 	p.setBlobFee(pendingBlobFee)
+	p.restoreUnwoundBundlesLocked(block, unwindTxs, unwindBlobTxs)
+	p.pruneBundlesLocked(block, stateChanges.FinalizedBlock, minedTxs)
 
 	oldGasLimit := p.blockGasLimit.Swap(stateChanges.BlockGasLimit)
 	if oldGasLimit != stateChanges.BlockGasLimit {
@@ -580,7 +612,7 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 	var announcements types.Announcements
 
 	announcements, err = p.addTxsOnNewBlock(block, cacheView, stateChanges, p.senders, unwindTxs, /* newTxs */
-		pendingBaseFee, stateChanges.BlockGasLimit, p.logger)
+		minedTxs, pendingBaseFee, stateChanges.BlockGasLimit, p.logger)
 
 	if err != nil {
 		return err
@@ -804,7 +836,9 @@ func (p *TxPool) IsLocal(idHash []byte) bool {
 func (p *TxPool) AddNewGoodPeer(peerID types.PeerID) { p.recentlyConnectedPeers.AddPeer(peerID) }
 func (p *TxPool) Started() bool                      { return p.started.Load() }
 
-func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableGas, availableBlobGas uint64, yielded mapset.Set[[32]byte]) (bool, int, error) {
+// best collects the best pending transactions. Transactions rejected by the current policies are skipped, and
+// discarded only when yielding: peeking doesn't change the pool.
+func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableGas, availableBlobGas uint64, yielded mapset.Set[[32]byte], yield bool) (bool, int, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -816,9 +850,12 @@ func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableG
 	best := p.pending.best
 
 	isShanghai := p.isShanghai() || p.isAgra() || p.isCanyon()
+	isPrague := p.isPrague()
 
 	txs.Resize(uint(cmp.Min(int(n), len(best.ms))))
 	var toRemove []*metaTx
+	var toDiscard []*metaTx
+	var discardReasons []txpoolcfg.DiscardReason
 	count := 0
 	i := 0
 
@@ -843,6 +880,18 @@ func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableG
 			continue
 		}
 
+		// the policies may have changed since the transaction was admitted
+		if reason, name := p.policies.check(mt.Tx, p.senders.senderID2Addr[mt.Tx.SenderID], policyStageYield); reason != txpoolcfg.Success {
+			if mt.Tx.Traced {
+				p.logger.Info(fmt.Sprintf("TX TRACING: best rejected by policy idHash=%x policy=%s reason=%s", mt.Tx.IDHash, name, reason))
+			}
+			if yield {
+				toDiscard = append(toDiscard, mt)
+				discardReasons = append(discardReasons, reason)
+			}
+			continue
+		}
+
 		rlpTx, sender, isLocal, err := p.getRlpLocked(tx, mt.Tx.IDHash[:])
 		if err != nil {
 			return false, count, err
@@ -862,7 +911,12 @@ func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableG
 		// make sure we have enough gas in the caller to add this transaction.
 		// not an exact science using intrinsic gas but as close as we could hope for at
 		// this stage
-		intrinsicGas, _ := txpoolcfg.CalcIntrinsicGas(uint64(mt.Tx.DataLen), uint64(mt.Tx.DataNonZeroLen), nil, mt.Tx.Creation, true, true, isShanghai)
+		intrinsicGas, _ := txpoolcfg.CalcIntrinsicGas(uint64(mt.Tx.DataLen), uint64(mt.Tx.DataNonZeroLen), uint64(mt.Tx.AuthCount), nil, mt.Tx.Creation, true, true, isShanghai)
+		if isPrague {
+			if floorDataGas, _ := txpoolcfg.CalcFloorDataGas(uint64(mt.Tx.DataLen), uint64(mt.Tx.DataNonZeroLen)); floorDataGas > intrinsicGas {
+				intrinsicGas = floorDataGas
+			}
+		}
 		if intrinsicGas > availableGas {
 			// we might find another TX with a low enough intrinsic gas to include so carry on
 			continue
@@ -882,16 +936,20 @@ func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableG
 			p.pending.Remove(mt, "best", p.logger)
 		}
 	}
+	for j, mt := range toDiscard {
+		p.pending.Remove(mt, "policy", p.logger)
+		p.discardLocked(mt, discardReasons[j])
+	}
 	return true, count, nil
 }
 
 func (p *TxPool) YieldBest(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableGas, availableBlobGas uint64, toSkip mapset.Set[[32]byte]) (bool, int, error) {
-	return p.best(n, txs, tx, onTopOf, availableGas, availableBlobGas, toSkip)
+	return p.best(n, txs, tx, onTopOf, availableGas, availableBlobGas, toSkip, true)
 }
 
 func (p *TxPool) PeekBest(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableGas, availableBlobGas uint64) (bool, error) {
 	set := mapset.NewThreadUnsafeSet[[32]byte]()
-	onTime, _, err := p.YieldBest(n, txs, tx, onTopOf, availableGas, availableBlobGas, set)
+	onTime, _, err := p.best(n, txs, tx, onTopOf, availableGas, availableBlobGas, set, false)
 	return onTime, err
 }
 
@@ -996,6 +1054,25 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 		}
 	}
 
+	if txn.Type == types.SetCodeTxType {
+		if !p.isPrague() {
+			return txpoolcfg.TypeNotActivated
+		}
+		if txn.Creation {
+			return txpoolcfg.CreateSetCodeTxn
+		}
+		if txn.AuthCount == 0 {
+			return txpoolcfg.NoAuthorizations
+		}
+	}
+
+	if reason, name := p.policies.check(txn, p.senders.senderID2Addr[txn.SenderID], policyStageAdmission); reason != txpoolcfg.Success {
+		if txn.Traced {
+			p.logger.Info(fmt.Sprintf("TX TRACING: validateTx rejected by policy idHash=%x policy=%s reason=%s", txn.IDHash, name, reason))
+		}
+		return reason
+	}
+
 	// Drop non-local transactions under our own minimal accepted gas price or tip
 	if !isLocal && uint256.NewInt(p.cfg.MinFeeCap).Cmp(&txn.FeeCap) == 1 {
 		if txn.Traced {
@@ -1003,7 +1080,7 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 		}
 		return txpoolcfg.UnderPriced
 	}
-	gas, reason := txpoolcfg.CalcIntrinsicGas(uint64(txn.DataLen), uint64(txn.DataNonZeroLen), nil, txn.Creation, true, true, isShanghai)
+	gas, reason := txpoolcfg.CalcIntrinsicGas(uint64(txn.DataLen), uint64(txn.DataNonZeroLen), uint64(txn.AuthCount), nil, txn.Creation, true, true, isShanghai)
 	if txn.Traced {
 		p.logger.Info(fmt.Sprintf("TX TRACING: validateTx intrinsic gas idHash=%x gas=%d", txn.IDHash, gas))
 	}
@@ -1019,6 +1096,18 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 		}
 		return txpoolcfg.IntrinsicGas
 	}
+	if p.isPrague() {
+		floorDataGas, reason := txpoolcfg.CalcFloorDataGas(uint64(txn.DataLen), uint64(txn.DataNonZeroLen))
+		if reason != txpoolcfg.Success {
+			return reason
+		}
+		if floorDataGas > txn.Gas {
+			if txn.Traced {
+				p.logger.Info(fmt.Sprintf("TX TRACING: validateTx floor data gas > txn.gas idHash=%x gas=%d, txn.gas=%d", txn.IDHash, floorDataGas, txn.Gas))
+			}
+			return txpoolcfg.FloorDataGas
+		}
+	}
 	if !isLocal && uint64(p.all.count(txn.SenderID)) > p.cfg.AccountSlots {
 		if txn.Traced {
 			p.logger.Info(fmt.Sprintf("TX TRACING: validateTx marked as spamming idHash=%x slots=%d, limit=%d", txn.IDHash, p.all.count(txn.SenderID), p.cfg.AccountSlots))
@@ -1164,6 +1253,31 @@ func (p *TxPool) isCancun() bool {
 	return activated
 }
 
+func (p *TxPool) isPrague() bool {
+	// once this flag has been set for the first time we no longer need to check the timestamp
+	set := p.isPostPrague.Load()
+	if set {
+		return true
+	}
+	if p.pragueTime == nil {
+		return false
+	}
+	pragueTime := *p.pragueTime
+
+	// a zero here means Prague is always active
+	if pragueTime == 0 {
+		p.isPostPrague.Swap(true)
+		return true
+	}
+
+	now := time.Now().Unix()
+	activated := uint64(now) >= pragueTime
+	if activated {
+		p.isPostPrague.Swap(true)
+	}
+	return activated
+}
+
 func (p *TxPool) isRegolith() bool {
 	// once this flag has been set for the first time we no longer need to check the timestamp
 	set := p.isPostRegolith.Load()
@@ -1480,6 +1594,7 @@ func (p *TxPool) addTxs(blockNum uint64, cacheView kvcache.CacheView, senders *s
 			logger.Info(fmt.Sprintf("TX TRACING: schedule sendersWithChangedState idHash=%x senderId=%d", txn.IDHash, mt.Tx.SenderID))
 		}
 		sendersWithChangedState[mt.Tx.SenderID] = struct{}{}
+		p.authoritiesWithTxs(mt.Tx, sendersWithChangedState)
 	}
 
 	for senderID := range sendersWithChangedState {
@@ -1498,7 +1613,7 @@ func (p *TxPool) addTxs(blockNum uint64, cacheView kvcache.CacheView, senders *s
 
 // TODO: Looks like a copy of the above
 func (p *TxPool) addTxsOnNewBlock(blockNum uint64, cacheView kvcache.CacheView, stateChanges *remote.StateChangeBatch,
-	senders *sendersBatch, newTxs types.TxSlots, pendingBaseFee uint64, blockGasLimit uint64, logger log.Logger) (types.Announcements, error) {
+	senders *sendersBatch, newTxs, minedTxs types.TxSlots, pendingBaseFee uint64, blockGasLimit uint64, logger log.Logger) (types.Announcements, error) {
 	if assert.Enable {
 		for _, txn := range newTxs.Txs {
 			if txn.SenderID == 0 {
@@ -1527,6 +1642,11 @@ func (p *TxPool) addTxsOnNewBlock(blockNum uint64, cacheView kvcache.CacheView,
 			continue
 		}
 		sendersWithChangedState[mt.Tx.SenderID] = struct{}{}
+		p.authoritiesWithTxs(mt.Tx, sendersWithChangedState)
+	}
+	// the authorizations of the mined transactions bumped the nonces of their authorities
+	for _, txn := range minedTxs.Txs {
+		p.authoritiesWithTxs(txn, sendersWithChangedState)
 	}
 	// add senders changed in state to `sendersWithChangedState` list
 	for _, changesList := range stateChanges.ChangeBatch {
@@ -1575,6 +1695,20 @@ func (p *TxPool) setBlobFee(blobFee uint64) {
 func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoolcfg.DiscardReason {
 	// Insert to pending pool, if pool doesn't have txn with same Nonce and bigger Tip
 	found := p.all.get(mt.Tx.SenderID, mt.Tx.Nonce)
+	// An authorization can be applied only once, so the same (authority, nonce) can't be used by two transactions,
+	// unless the new one replaces the transaction which holds it
+	for _, authority := range mt.Tx.Authorities {
+		if holder, ok := p.byAuthority[authority]; ok && holder != found {
+			return txpoolcfg.AuthorityReserved
+		}
+	}
+	// for the same reason the nonce of an authority can't be used by its own transaction once an authorization in
+	// the pool consumes it
+	if sender, ok := p.senders.senderID2Addr[mt.Tx.SenderID]; ok {
+		if holder, ok := p.byAuthority[types.Authority{Address: sender, Nonce: mt.Tx.Nonce}]; ok && holder != found {
+			return txpoolcfg.AuthorityReserved
+		}
+	}
 	if found != nil {
 		if found.Tx.Type == types.BlobTxType && mt.Tx.Type != types.BlobTxType {
 			return txpoolcfg.BlobTxReplace
@@ -1635,8 +1769,24 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 		return txpoolcfg.FeeTooLow
 	}
 
+	// pooled transactions of the authorities with the nonces the authorizations consume can't be included after
+	// this one
+	for _, authority := range mt.Tx.Authorities {
+		authorityID, ok := p.senders.getID(authority.Address)
+		if !ok {
+			continue
+		}
+		if used := p.all.get(authorityID, authority.Nonce); used != nil && used != found {
+			p.removeFromSubPool(used, "authority-nonce-used")
+			p.discardLocked(used, txpoolcfg.AuthorityNonceUsed)
+		}
+	}
+
 	hashStr := string(mt.Tx.IDHash[:])
 	p.byHash[hashStr] = mt
+	for _, authority := range mt.Tx.Authorities {
+		p.byAuthority[authority] = mt
+	}
 
 	if replaced := p.all.replaceOrInsert(mt, p.logger); replaced != nil {
 		if assert.Enable {
@@ -1659,11 +1809,39 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 	return txpoolcfg.NotSet
 }
 
+// removeFromSubPool takes the transaction out of the sub-pool it is currently in
+func (p *TxPool) removeFromSubPool(mt *metaTx, reason string) {
+	switch mt.currentSubPool {
+	case PendingSubPool:
+		p.pending.Remove(mt, reason, p.logger)
+	case BaseFeeSubPool:
+		p.baseFee.Remove(mt, reason, p.logger)
+	case QueuedSubPool:
+		p.queued.Remove(mt, reason, p.logger)
+	default:
+		//already removed
+	}
+}
+
+// authoritiesWithTxs adds the senders of the pool which are authorities of the transaction to the set
+func (p *TxPool) authoritiesWithTxs(txn *types.TxSlot, senderIDs map[uint64]struct{}) {
+	for _, authority := range txn.Authorities {
+		if id, ok := p.senders.getID(authority.Address); ok && p.all.count(id) > 0 {
+			senderIDs[id] = struct{}{}
+		}
+	}
+}
+
 // dropping transaction from all sub-structures and from db
 // Important: don't call it while iterating by all
 func (p *TxPool) discardLocked(mt *metaTx, reason txpoolcfg.DiscardReason) {
 	hashStr := string(mt.Tx.IDHash[:])
 	delete(p.byHash, hashStr)
+	for _, authority := range mt.Tx.Authorities {
+		if p.byAuthority[authority] == mt {
+			delete(p.byAuthority, authority)
+		}
+	}
 	p.deletedTxs = append(p.deletedTxs, mt)
 	p.all.delete(mt, reason, p.logger)
 	p.discardReasonsLRU.Add(hashStr, reason)
@@ -1726,6 +1904,58 @@ func (p *TxPool) NonceFromAddress(addr [20]byte) (nonce uint64, inPool bool) {
 	return p.all.nonce(senderID)
 }
 
+// NotPendingReason tells why a transaction of the pool can't be included into the pending block
+type NotPendingReason uint8
+
+const (
+	NonceGap                   NotPendingReason = iota
+	InsufficientBalance                         // covers this and the previous transactions of the sender, L1 cost included
+	GasLimitAboveBlockGasLimit                  //
+	FeeCapBelowBaseFee                          // this or a previous transaction of the sender
+	BlobFeeCapBelowBlobBaseFee                  //
+)
+
+// TxStatus is where a transaction is in the pool. SubPool is 0 when it's not in the pool, then Discarded is the
+// reason it was evicted if it's still remembered.
+type TxStatus struct {
+	SubPool    SubPoolType
+	NotPending []NotPendingReason
+	Discarded  txpoolcfg.DiscardReason
+	Sender     common.Address
+	Nonce      uint64
+}
+
+func (p *TxPool) TxStatus(idHash []byte) TxStatus {
+	hashS := string(idHash)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	mt, ok := p.byHash[hashS]
+	if !ok {
+		reason, _ := p.discardReasonsLRU.Get(hashS)
+		return TxStatus{Discarded: reason}
+	}
+	status := TxStatus{SubPool: mt.currentSubPool, Sender: p.senders.senderID2Addr[mt.Tx.SenderID], Nonce: mt.Tx.Nonce}
+	if mt.currentSubPool == PendingSubPool {
+		return status
+	}
+	if mt.subPool&NoNonceGaps == 0 {
+		status.NotPending = append(status.NotPending, NonceGap)
+	}
+	if mt.subPool&EnoughBalance == 0 {
+		status.NotPending = append(status.NotPending, InsufficientBalance)
+	}
+	if mt.subPool&NotTooMuchGas == 0 {
+		status.NotPending = append(status.NotPending, GasLimitAboveBlockGasLimit)
+	}
+	if mt.minFeeCap.LtUint64(p.pendingBaseFee.Load()) {
+		status.NotPending = append(status.NotPending, FeeCapBelowBaseFee)
+	}
+	if mt.Tx.Type == types.BlobTxType && mt.Tx.BlobFeeCap.LtUint64(p.pendingBlobFee.Load()) {
+		status.NotPending = append(status.NotPending, BlobFeeCapBelowBlobBaseFee)
+	}
+	return status
+}
+
 // removeMined - apply new highest block (or batch of blocks)
 //
 // 1. New best block arrives, which potentially changes the balance and the nonce of some senders.
@@ -1989,6 +2219,12 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 	defer commitEvery.Stop()
 	logEvery := time.NewTicker(p.cfg.LogEvery)
 	defer logEvery.Stop()
+	var reloadPolicies <-chan time.Time // nil blocks forever, when there's no rule file to watch
+	if p.cfg.PolicyFile != "" && p.cfg.PolicyReloadEvery > 0 {
+		reloadPoliciesEvery := time.NewTicker(p.cfg.PolicyReloadEvery)
+		defer reloadPoliciesEvery.Stop()
+		reloadPolicies = reloadPoliciesEvery.C
+	}
 
 	err := p.Start(ctx, db)
 
@@ -2004,6 +2240,13 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 			return
 		case <-logEvery.C:
 			p.logStats()
+		case <-reloadPolicies:
+			p.lock.Lock()
+			_, err := p.policies.reload(p.logger)
+			p.lock.Unlock()
+			if err != nil {
+				p.logger.Error("[txpool] reload policies, keeping the previous ones", "file", p.cfg.PolicyFile, "err", err)
+			}
 		case <-processRemoteTxsEvery.C:
 			if !p.Started() {
 				continue
@@ -53,7 +53,7 @@ func TestNonceFromAddress(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -173,7 +173,7 @@ func TestReplaceWithHigherFee(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
 	assert.NoError(err)
 	require.NotEqual(nil, pool)
 	ctx := context.Background()
@@ -290,7 +290,7 @@ func TestReverseNonces(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -417,7 +417,7 @@ func TestTxPoke(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -586,6 +586,7 @@ func TestShanghaiIntrinsicGas(t *testing.T) {
 		expected       uint64
 		dataLen        uint64
 		dataNonZeroLen uint64
+		authorizations uint64
 		creation       bool
 		isShanghai     bool
 	}{
@@ -624,11 +625,16 @@ func TestShanghaiIntrinsicGas(t *testing.T) {
 			creation:       true,
 			isShanghai:     true,
 		},
+		"two authorizations": {
+			expected:       71000,
+			authorizations: 2,
+			isShanghai:     true,
+		},
 	}
 
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
-			gas, reason := txpoolcfg.CalcIntrinsicGas(c.dataLen, c.dataNonZeroLen, nil, c.creation, true, true, c.isShanghai)
+			gas, reason := txpoolcfg.CalcIntrinsicGas(c.dataLen, c.dataNonZeroLen, c.authorizations, nil, c.creation, true, true, c.isShanghai)
 			if reason != txpoolcfg.Success {
 				t.Errorf("expected success but got reason %v", reason)
 			}
@@ -699,7 +705,7 @@ func TestShanghaiValidateTx(t *testing.T) {
 			}
 
 			cache := &kvcache.DummyCache{}
-			pool, err := New(ch, coreDB, cfg, cache, *u256.N1, shanghaiTime, nil /* agraBlock */, nil /* cancunTime */, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, logger)
+			pool, err := New(ch, coreDB, cfg, cache, *u256.N1, shanghaiTime, nil /* agraBlock */, nil /* cancunTime */, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, logger)
 			asrt.NoError(err)
 			ctx := context.Background()
 			tx, err := coreDB.BeginRw(ctx)
@@ -738,6 +744,84 @@ func TestShanghaiValidateTx(t *testing.T) {
 	}
 }
 
+func TestPragueValidateTx(t *testing.T) {
+	asrt := assert.New(t)
+	// 1000 non-zero bytes: intrinsic gas is 37000, the calldata floor is 61000
+	tests := map[string]struct {
+		expected txpoolcfg.DiscardReason
+		gas      uint64
+		isPrague bool
+	}{
+		"no prague": {
+			expected: txpoolcfg.Success,
+			gas:      37000,
+			isPrague: false,
+		},
+		"prague below floor": {
+			expected: txpoolcfg.FloorDataGas,
+			gas:      60999,
+			isPrague: true,
+		},
+		"prague exactly on floor": {
+			expected: txpoolcfg.Success,
+			gas:      61000,
+			isPrague: true,
+		},
+	}
+
+	logger := log.New()
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ch := make(chan types.Announcements, 100)
+			_, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+			cfg := txpoolcfg.DefaultConfig
+
+			var pragueTime *big.Int
+			if test.isPrague {
+				pragueTime = big.NewInt(0)
+			}
+
+			cache := &kvcache.DummyCache{}
+			pool, err := New(ch, coreDB, cfg, cache, *u256.N1, big.NewInt(0), nil /* agraBlock */, nil /* cancunTime */, pragueTime, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, logger)
+			asrt.NoError(err)
+			ctx := context.Background()
+			tx, err := coreDB.BeginRw(ctx)
+			defer tx.Rollback()
+			asrt.NoError(err)
+
+			sndr := sender{nonce: 0, balance: *uint256.NewInt(math.MaxUint64)}
+			sndrBytes := make([]byte, types.EncodeSenderLengthForStorage(sndr.nonce, sndr.balance))
+			types.EncodeSender(sndr.nonce, sndr.balance, sndrBytes)
+			err = tx.Put(kv.PlainState, []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, sndrBytes)
+			asrt.NoError(err)
+
+			txn := &types.TxSlot{
+				DataLen:        1000,
+				DataNonZeroLen: 1000,
+				FeeCap:         *uint256.NewInt(21000),
+				Gas:            test.gas,
+				SenderID:       0,
+			}
+
+			txns := types.TxSlots{
+				Txs:     append([]*types.TxSlot{}, txn),
+				Senders: types.Addresses{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			}
+			err = pool.senders.registerNewSenders(&txns, logger)
+			asrt.NoError(err)
+			view, err := cache.View(ctx, tx)
+			asrt.NoError(err)
+
+			reason := pool.validateTx(txn, false, view)
+
+			if reason != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, reason)
+			}
+		})
+	}
+}
+
 // Blob gas price bump + other requirements to replace existing txns in the pool
 func TestBlobTxReplacement(t *testing.T) {
 	assert, require := assert.New(t), require.New(t)
@@ -745,7 +829,7 @@ func TestBlobTxReplacement(t *testing.T) {
 	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -951,6 +1035,153 @@ func makeBlobTx() types.TxSlot {
 	return blobTx
 }
 
+// The same authorization (authority and nonce) can be used only by one transaction in the pool
+func TestSetCodeTxAuthorities(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 5)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, common.Big0, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+
+	h1 := gointerfaces.ConvertHashToH256([32]byte{})
+	change := &remote.StateChangeBatch{
+		StateVersionId:      0,
+		PendingBlockBaseFee: 200_000,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: h1},
+		},
+	}
+	var addr1, addr2 [20]byte
+	addr1[0], addr2[0] = 1, 2
+	for _, addr := range [][20]byte{addr1, addr2} {
+		v := make([]byte, types.EncodeSenderLengthForStorage(0, *uint256.NewInt(1 * common.Ether)))
+		types.EncodeSender(0, *uint256.NewInt(1 * common.Ether), v)
+		change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+			Action:  remote.Action_UPSERT,
+			Address: gointerfaces.ConvertAddressToH160(addr),
+			Data:    v,
+		})
+	}
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	authority := types.Authority{Address: common.Address{0x03}, Nonce: 5}
+	makeSetCodeTx := func(id byte, tip uint64) *types.TxSlot {
+		return &types.TxSlot{
+			Tip:         *uint256.NewInt(tip),
+			FeeCap:      *uint256.NewInt(2 * tip),
+			Gas:         100_000,
+			IDHash:      [32]byte{id},
+			Type:        types.SetCodeTxType,
+			AuthCount:   1,
+			Authorities: []types.Authority{authority},
+		}
+	}
+	add := func(txn *types.TxSlot, sender [20]byte) txpoolcfg.DiscardReason {
+		txSlots := types.TxSlots{}
+		txSlots.Append(txn, sender[:], true)
+		reasons, err := pool.AddLocalTxs(ctx, txSlots, tx)
+		require.NoError(err)
+		return reasons[0]
+	}
+
+	assert.Equal(txpoolcfg.Success, add(makeSetCodeTx(0x01, 150_000), addr1))
+	// another sender can't use the same authorization
+	assert.Equal(txpoolcfg.AuthorityReserved, add(makeSetCodeTx(0x02, 150_000), addr2))
+	// but it can be used by the replacement transaction
+	assert.Equal(txpoolcfg.Success, add(makeSetCodeTx(0x03, 200_000), addr1))
+	// and is released when the transaction leaves the pool
+	replacement := [32]byte{0x03}
+	pool.lock.Lock()
+	pool.discardLocked(pool.byHash[string(replacement[:])], txpoolcfg.Mined)
+	pool.lock.Unlock()
+	assert.Equal(txpoolcfg.Success, add(makeSetCodeTx(0x04, 150_000), addr2))
+
+	noAuths := makeSetCodeTx(0x05, 150_000)
+	noAuths.AuthCount, noAuths.Authorities = 0, nil
+	assert.Equal(txpoolcfg.NoAuthorizations, add(noAuths, addr2))
+}
+
+func TestSetCodeTxAuthorityNonce(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 5)
+	db, coreDB := memdb.NewTestPoolDB(t), memdb.NewTestDB(t)
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, common.Big0, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	assert.NoError(err)
+	require.True(pool != nil)
+	ctx := context.Background()
+
+	h1 := gointerfaces.ConvertHashToH256([32]byte{})
+	change := &remote.StateChangeBatch{
+		StateVersionId:      0,
+		PendingBlockBaseFee: 200_000,
+		BlockGasLimit:       1000000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: h1},
+		},
+	}
+	var addr1, addr2 [20]byte
+	addr1[0], addr2[0] = 1, 2
+	for _, addr := range [][20]byte{addr1, addr2} {
+		v := make([]byte, types.EncodeSenderLengthForStorage(0, *uint256.NewInt(1 * common.Ether)))
+		types.EncodeSender(0, *uint256.NewInt(1 * common.Ether), v)
+		change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+			Action:  remote.Action_UPSERT,
+			Address: gointerfaces.ConvertAddressToH160(addr),
+			Data:    v,
+		})
+	}
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	err = pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx)
+	assert.NoError(err)
+
+	add := func(txn *types.TxSlot, sender [20]byte) txpoolcfg.DiscardReason {
+		txSlots := types.TxSlots{}
+		txSlots.Append(txn, sender[:], true)
+		reasons, err := pool.AddLocalTxs(ctx, txSlots, tx)
+		require.NoError(err)
+		return reasons[0]
+	}
+	makeTx := func(id byte, nonce uint64) *types.TxSlot {
+		return &types.TxSlot{
+			Tip:    *uint256.NewInt(150_000),
+			FeeCap: *uint256.NewInt(300_000),
+			Gas:    100_000,
+			IDHash: [32]byte{id},
+			Nonce:  nonce,
+		}
+	}
+
+	assert.Equal(txpoolcfg.Success, add(makeTx(0x01, 0), addr2))
+	assert.Equal(txpoolcfg.Success, add(makeTx(0x02, 1), addr2))
+
+	// the authorization consumes the nonce of the pooled transaction of the authority
+	setCode := makeTx(0x03, 0)
+	setCode.Type, setCode.AuthCount = types.SetCodeTxType, 1
+	setCode.Authorities = []types.Authority{{Address: addr2, Nonce: 1}}
+	assert.Equal(txpoolcfg.Success, add(setCode, addr1))
+	kept, dropped := [32]byte{0x01}, [32]byte{0x02}
+	pool.lock.Lock()
+	assert.Contains(pool.byHash, string(kept[:]))
+	assert.NotContains(pool.byHash, string(dropped[:]))
+	pool.lock.Unlock()
+
+	// and the authority can't take it back
+	assert.Equal(txpoolcfg.AuthorityReserved, add(makeTx(0x04, 1), addr2))
+}
+
 func TestDepositTxValidateTx(t *testing.T) {
 	asrt := assert.New(t)
 	logger := log.New()
@@ -961,7 +1192,7 @@ func TestDepositTxValidateTx(t *testing.T) {
 
 	shanghaiTime := big.NewInt(0)
 	cache := &kvcache.DummyCache{}
-	pool, err := New(ch, coreDB, cfg, cache, *u256.N1, shanghaiTime, big.NewInt(0), nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, logger)
+	pool, err := New(ch, coreDB, cfg, cache, *u256.N1, shanghaiTime, big.NewInt(0), nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, logger)
 	asrt.NoError(err)
 	ctx := context.Background()
 	tx, err := coreDB.BeginRw(ctx)
@@ -1011,7 +1242,7 @@ func TestDropRemoteAtNoGossip(t *testing.T) {
 	logger := log.New()
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
 
-	txPool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, logger)
+	txPool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, logger)
 	assert.NoError(err)
 	require.True(txPool != nil)
 
@@ -1117,7 +1348,7 @@ func TestBlobSlots(t *testing.T) {
 	cfg.TotalBlobPoolLimit = 20
 
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -1191,7 +1422,7 @@ func TestGasLimitChanged(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, nil, nil, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -39,6 +39,7 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	txpool_proto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
 	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/types"
@@ -59,6 +60,8 @@ type txPool interface {
 	CountContent() (int, int, int)
 	IdHashKnown(tx kv.Tx, hash []byte) (bool, error)
 	NonceFromAddress(addr [20]byte) (nonce uint64, inPool bool)
+	TxStatus(idHash []byte) TxStatus
+	AddBundle(bundle *types.Bundle) error
 }
 
 var _ txpool_proto.TxpoolServer = (*GrpcServer)(nil)   // compile-time interface check
@@ -97,6 +100,12 @@ func (*GrpcDisabled) Status(ctx context.Context, request *txpool_proto.StatusReq
 func (*GrpcDisabled) Nonce(ctx context.Context, request *txpool_proto.NonceRequest) (*txpool_proto.NonceReply, error) {
 	return nil, ErrPoolDisabled
 }
+func (*GrpcDisabled) TxStatus(ctx context.Context, request *txpool_proto.TxStatusRequest) (*txpool_proto.TxStatusReply, error) {
+	return nil, ErrPoolDisabled
+}
+func (*GrpcDisabled) SendBundle(ctx context.Context, request *txpool_proto.SendBundleRequest) (*txpool_proto.SendBundleReply, error) {
+	return nil, ErrPoolDisabled
+}
 
 type GrpcServer struct {
 	txpool_proto.UnimplementedTxpoolServer
@@ -237,9 +246,11 @@ func mapDiscardReasonToProto(reason txpoolcfg.DiscardReason) txpool_proto.Import
 		return txpool_proto.ImportResult_SUCCESS
 	case txpoolcfg.AlreadyKnown:
 		return txpool_proto.ImportResult_ALREADY_EXISTS
-	case txpoolcfg.UnderPriced, txpoolcfg.ReplaceUnderpriced, txpoolcfg.FeeTooLow:
+	case txpoolcfg.UnderPriced, txpoolcfg.ReplaceUnderpriced, txpoolcfg.FeeTooLow, txpoolcfg.PolicyTipTooLow:
 		return txpool_proto.ImportResult_FEE_TOO_LOW
-	case txpoolcfg.InvalidSender, txpoolcfg.NegativeValue, txpoolcfg.OversizedData, txpoolcfg.InitCodeTooLarge, txpoolcfg.RLPTooLong, txpoolcfg.TxTypeNotSupported, txpoolcfg.CreateBlobTxn, txpoolcfg.NoBlobs, txpoolcfg.TooManyBlobs, txpoolcfg.TypeNotActivated, txpoolcfg.UnequalBlobTxExt, txpoolcfg.BlobHashCheckFail, txpoolcfg.UnmatchedBlobTxExt:
+	case txpoolcfg.InvalidSender, txpoolcfg.NegativeValue, txpoolcfg.OversizedData, txpoolcfg.InitCodeTooLarge, txpoolcfg.RLPTooLong, txpoolcfg.TxTypeNotSupported, txpoolcfg.CreateBlobTxn, txpoolcfg.NoBlobs, txpoolcfg.TooManyBlobs, txpoolcfg.TypeNotActivated, txpoolcfg.UnequalBlobTxExt, txpoolcfg.BlobHashCheckFail, txpoolcfg.UnmatchedBlobTxExt,
+		txpoolcfg.CreateSetCodeTxn, txpoolcfg.NoAuthorizations, txpoolcfg.AuthorityReserved, txpoolcfg.FloorDataGas,
+		txpoolcfg.SenderDenied, txpoolcfg.RecipientDenied, txpoolcfg.ContractGasCap, txpoolcfg.DASizeTooLarge:
 		// TODO(eip-4844) TypeNotActivated may be transient (e.g. a blob transaction is submitted 1 sec prior to Cancun activation)
 		return txpool_proto.ImportResult_INVALID
 	default:
@@ -303,6 +314,88 @@ func (s *GrpcServer) Nonce(ctx context.Context, in *txpool_proto.NonceRequest) (
 	}, nil
 }
 
+func (s *GrpcServer) TxStatus(_ context.Context, in *txpool_proto.TxStatusRequest) (*txpool_proto.TxStatusReply, error) {
+	h := gointerfaces.ConvertH256ToHash(in.Hash)
+	status := s.txPool.TxStatus(h[:])
+	if status.SubPool == 0 {
+		if status.Discarded == txpoolcfg.NotSet {
+			return &txpool_proto.TxStatusReply{Status: txpool_proto.TxStatusReply_UNKNOWN}, nil
+		}
+		return &txpool_proto.TxStatusReply{Status: txpool_proto.TxStatusReply_DISCARDED, DiscardReason: status.Discarded.String()}, nil
+	}
+	reply := &txpool_proto.TxStatusReply{
+		Sender: gointerfaces.ConvertAddressToH160(status.Sender),
+		Nonce:  status.Nonce,
+	}
+	switch status.SubPool {
+	case PendingSubPool:
+		reply.Status = txpool_proto.TxStatusReply_PENDING
+	case BaseFeeSubPool:
+		reply.Status = txpool_proto.TxStatusReply_BASE_FEE
+	case QueuedSubPool:
+		reply.Status = txpool_proto.TxStatusReply_QUEUED
+	}
+	for _, reason := range status.NotPending {
+		reply.Reasons = append(reply.Reasons, convertNotPendingReason(reason))
+	}
+	return reply, nil
+}
+
+func (s *GrpcServer) SendBundle(_ context.Context, in *txpool_proto.SendBundleRequest) (*txpool_proto.SendBundleReply, error) {
+	if len(in.RlpTxs) == 0 {
+		return nil, errors.New("bundle has no transactions")
+	}
+	parseCtx := types.NewTxParseContext(s.chainID).ChainIDRequired()
+	parseCtx.ValidateRLP(s.txPool.ValidateSerializedTxn)
+
+	bundle := &types.Bundle{
+		Txs:          in.RlpTxs,
+		Slots:        make([]*types.TxSlot, len(in.RlpTxs)),
+		TxHashes:     make([][32]byte, len(in.RlpTxs)),
+		Senders:      make(types.Addresses, len(in.RlpTxs)*length.Addr),
+		RevertingTxs: make(map[[32]byte]struct{}, len(in.RevertingTxHashes)),
+		MinBlock:     in.MinBlockNumber,
+		MaxBlock:     in.MaxBlockNumber,
+		MinTimestamp: in.MinTimestamp,
+		MaxTimestamp: in.MaxTimestamp,
+	}
+	for i, rlpTx := range in.RlpTxs {
+		slot := &types.TxSlot{}
+		if _, err := parseCtx.ParseTransaction(rlpTx, 0, slot, bundle.Senders.At(i), false /* hasEnvelope */, true /* wrappedWithBlobs */, nil); err != nil {
+			return nil, fmt.Errorf("bundle transaction %d: %w", i, err)
+		}
+		if slot.Type == types.BlobTxType || slot.Type == types.DepositTxType {
+			return nil, fmt.Errorf("bundle transaction %d: %s", i, txpoolcfg.TxTypeNotSupported)
+		}
+		bundle.Slots[i], bundle.TxHashes[i] = slot, slot.IDHash
+	}
+	for _, h := range in.RevertingTxHashes {
+		bundle.RevertingTxs[gointerfaces.ConvertH256ToHash(h)] = struct{}{}
+	}
+	bundle.Hash = types.BundleHash(bundle.TxHashes)
+	if err := s.txPool.AddBundle(bundle); err != nil {
+		return nil, err
+	}
+	return &txpool_proto.SendBundleReply{BundleHash: gointerfaces.ConvertHashToH256(bundle.Hash)}, nil
+}
+
+func convertNotPendingReason(r NotPendingReason) txpool_proto.TxStatusReply_NotPendingReason {
+	switch r {
+	case NonceGap:
+		return txpool_proto.TxStatusReply_NONCE_GAP
+	case InsufficientBalance:
+		return txpool_proto.TxStatusReply_INSUFFICIENT_BALANCE
+	case GasLimitAboveBlockGasLimit:
+		return txpool_proto.TxStatusReply_GAS_LIMIT_ABOVE_BLOCK_GAS_LIMIT
+	case FeeCapBelowBaseFee:
+		return txpool_proto.TxStatusReply_FEE_CAP_BELOW_BASE_FEE
+	case BlobFeeCapBelowBlobBaseFee:
+		return txpool_proto.TxStatusReply_BLOB_FEE_CAP_BELOW_BLOB_BASE_FEE
+	default:
+		return txpool_proto.TxStatusReply_UNKNOWN_REASON
+	}
+}
+
 // NewSlotsStreams - it's safe to use this class as non-pointer
 type NewSlotsStreams struct {
 	chans map[uint]txpool_proto.Txpool_OnAddServer
@@ -64,6 +64,11 @@ type Config struct {
 	OverrideOptimismCanyonTime *big.Int
 
 	NoGossip bool // this mode doesn't broadcast any txs, and if receive remote-txn - skip it
+
+	PolicyFile        string        // TOML file with the admission policies, see txpool.LoadPolicies
+	PolicyReloadEvery time.Duration // how often the policy file is checked for changes
+
+	BundleSlots int // Max number of bundles waiting for inclusion, see eth_sendBundle
 }
 
 var DefaultConfig = Config{
@@ -71,10 +76,12 @@ var DefaultConfig = Config{
 	ProcessRemoteTxsEvery: 100 * time.Millisecond,
 	CommitEvery:           15 * time.Second,
 	LogEvery:              30 * time.Second,
+	PolicyReloadEvery:     10 * time.Second,
 
 	PendingSubPoolLimit: 10_000,
 	BaseFeeSubPoolLimit: 10_000,
 	QueuedSubPoolLimit:  10_000,
+	BundleSlots:         1_000,
 
 	MinFeeCap:          1,
 	AccountSlots:       16,  //TODO: to choose right value (16 to be compatible with Geth)
@@ -124,6 +131,16 @@ const (
 	BlobTxReplace       DiscardReason = 30 // Cannot replace type-3 blob txn with another type of txn
 	BlobPoolOverflow    DiscardReason = 31 // The total number of blobs (through blob txs) in the pool has reached its limit
 	TxTypeNotSupported  DiscardReason = 32
+	CreateSetCodeTxn    DiscardReason = 33 // EIP-7702 transactions cannot have the form of a create transaction
+	NoAuthorizations    DiscardReason = 34 // EIP-7702 transactions must have at least one authorization
+	AuthorityReserved   DiscardReason = 35 // Authorization with the same authority and nonce is used by another transaction in the pool
+	AuthorityNonceUsed  DiscardReason = 36 // The nonce is consumed by an EIP-7702 authorization of another transaction in the pool
+	FloorDataGas        DiscardReason = 37 // EIP-7623 - gas limit doesn't cover the calldata floor
+	SenderDenied        DiscardReason = 38 // The sender is on a deny list of the admission policies
+	RecipientDenied     DiscardReason = 39 // The recipient is on a deny list of the admission policies
+	ContractGasCap      DiscardReason = 40 // The gas limit is above the cap the admission policies set for the recipient contract
+	PolicyTipTooLow     DiscardReason = 41 // The tip is below the minimum the admission policies set for the transaction type
+	DASizeTooLarge      DiscardReason = 42 // The estimated data availability size is above the limit of the admission policies
 )
 
 func (r DiscardReason) String() string {
@@ -188,13 +205,33 @@ func (r DiscardReason) String() string {
 		return "can't replace blob-txn with a non-blob-txn"
 	case BlobPoolOverflow:
 		return "blobs limit in txpool is full"
+	case CreateSetCodeTxn:
+		return "set code transactions cannot have the form of a create transaction"
+	case NoAuthorizations:
+		return "set code transactions must have at least one authorization"
+	case AuthorityReserved:
+		return "authorization is already used by another transaction in the pool"
+	case AuthorityNonceUsed:
+		return "nonce is consumed by an authorization of another transaction in the pool"
+	case FloorDataGas:
+		return "gas limit is below the calldata floor"
+	case SenderDenied:
+		return "sender is denied by the txpool policy"
+	case RecipientDenied:
+		return "recipient is denied by the txpool policy"
+	case ContractGasCap:
+		return "gas limit is above the txpool policy cap of the contract"
+	case PolicyTipTooLow:
+		return "tip is below the txpool policy minimum"
+	case DASizeTooLarge:
+		return "data availability size is above the txpool policy limit"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
 }
 
 // CalcIntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func CalcIntrinsicGas(dataLen, dataNonZeroLen uint64, accessList types.AccessList, isContractCreation, isHomestead, isEIP2028, isShanghai bool) (uint64, DiscardReason) {
+func CalcIntrinsicGas(dataLen, dataNonZeroLen, authorizationsLen uint64, accessList types.AccessList, isContractCreation, isHomestead, isEIP2028, isShanghai bool) (uint64, DiscardReason) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if isContractCreation && isHomestead {
@@ -263,6 +300,38 @@ func CalcIntrinsicGas(dataLen, dataNonZeroLen uint64, accessList types.AccessLis
 			return 0, GasUintOverflow
 		}
 	}
+	// EIP-7702: every authorization is charged as if it created a new account
+	if authorizationsLen > 0 {
+		product, overflow := emath.SafeMul(authorizationsLen, fixedgas.PerEmptyAccountCost)
+		if overflow {
+			return 0, GasUintOverflow
+		}
+		gas, overflow = emath.SafeAdd(gas, product)
+		if overflow {
+			return 0, GasUintOverflow
+		}
+	}
+	return gas, Success
+}
+
+// CalcFloorDataGas computes the EIP-7623 minimum gas a transaction with the given data has to pay.
+func CalcFloorDataGas(dataLen, dataNonZeroLen uint64) (uint64, DiscardReason) {
+	product, overflow := emath.SafeMul(dataNonZeroLen, fixedgas.TxTokenPerNonZeroByte-1)
+	if overflow {
+		return 0, GasUintOverflow
+	}
+	tokens, overflow := emath.SafeAdd(dataLen, product)
+	if overflow {
+		return 0, GasUintOverflow
+	}
+	product, overflow = emath.SafeMul(tokens, fixedgas.TxTotalCostFloorPerToken)
+	if overflow {
+		return 0, GasUintOverflow
+	}
+	gas, overflow := emath.SafeAdd(fixedgas.TxGas, product)
+	if overflow {
+		return 0, GasUintOverflow
+	}
 	return gas, Success
 }
 
@@ -140,6 +140,11 @@ func AllComponents(ctx context.Context, cfg txpoolcfg.Config, cache kvcache.Cach
 		agraBlock = chainConfig.Bor.GetAgraBlock()
 	}
 	cancunTime := chainConfig.CancunTime
+	pragueTime := chainConfig.PragueTime
+	if chainConfig.IsOptimism() && chainConfig.IsthmusTime != nil {
+		// set code transactions are enabled by Isthmus on OP chains
+		pragueTime = chainConfig.IsthmusTime
+	}
 
 	regolithTime := chainConfig.RegolithTime
 	canyonTime := chainConfig.CanyonTime
@@ -147,7 +152,7 @@ func AllComponents(ctx context.Context, cfg txpoolcfg.Config, cache kvcache.Cach
 	fjordTime := chainConfig.FjordTime
 
 	var pool txpool.Pool
-	txPool, err := txpool.New(newTxs, chainDB, cfg, cache, *chainID, shanghaiTime, agraBlock, cancunTime,
+	txPool, err := txpool.New(newTxs, chainDB, cfg, cache, *chainID, shanghaiTime, agraBlock, cancunTime, pragueTime,
 		regolithTime, canyonTime, ecotoneTime, fjordTime,
 		maxBlobsPerBlock, feeCalculator, logger)
 	if err != nil {
@@ -0,0 +1,56 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"golang.org/x/crypto/sha3"
+)
+
+// Bundle is a list of transactions to be included in a block atomically: all of them, in order, or none
+type Bundle struct {
+	Hash         [32]byte  // keccak256 of the concatenated hashes of the transactions
+	Txs          [][]byte  // RLP of the signed transactions
+	Slots        []*TxSlot // parsed transactions, checked against the txpool policies
+	TxHashes     [][32]byte
+	Senders      Addresses
+	RevertingTxs map[[32]byte]struct{} // transactions which may fail without dropping the bundle
+	MinBlock     uint64
+	MaxBlock     uint64
+	MinTimestamp uint64 // 0 for no bound
+	MaxTimestamp uint64 // 0 for no bound
+}
+
+// BundleHash identifies the bundle made of the transactions
+func BundleHash(txHashes [][32]byte) (h [32]byte) {
+	keccak := sha3.NewLegacyKeccak256()
+	for _, txHash := range txHashes {
+		keccak.Write(txHash[:])
+	}
+	keccak.Sum(h[:0])
+	return h
+}
+
+// Targets reports whether the bundle may be included in the block
+func (b *Bundle) Targets(blockNum, timestamp uint64) bool {
+	if blockNum < b.MinBlock || blockNum > b.MaxBlock {
+		return false
+	}
+	if b.MinTimestamp != 0 && timestamp < b.MinTimestamp {
+		return false
+	}
+	return b.MaxTimestamp == 0 || timestamp <= b.MaxTimestamp
+}
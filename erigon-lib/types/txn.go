@@ -94,14 +94,15 @@ type TxSlot struct {
 	Nonce          uint64      // Nonce of the transaction
 	DataLen        int         // Length of transaction's data (for calculation of intrinsic gas)
 	DataNonZeroLen int
-	AlAddrCount    int      // Number of addresses in the access list
-	AlStorCount    int      // Number of storage keys in the access list
-	Gas            uint64   // Gas limit of the transaction
-	IDHash         [32]byte // Transaction hash for the purposes of using it as a transaction Id
-	Traced         bool     // Whether transaction needs to be traced throughout transaction pool code and generate debug printing
-	Creation       bool     // Set to true if "To" field of the transaction is not set
-	Type           byte     // Transaction type
-	Size           uint32   // Size of the payload (without the RLP string envelope for typed transactions)
+	AlAddrCount    int            // Number of addresses in the access list
+	AlStorCount    int            // Number of storage keys in the access list
+	Gas            uint64         // Gas limit of the transaction
+	IDHash         [32]byte       // Transaction hash for the purposes of using it as a transaction Id
+	Traced         bool           // Whether transaction needs to be traced throughout transaction pool code and generate debug printing
+	Creation       bool           // Set to true if "To" field of the transaction is not set
+	To             common.Address // Recipient of the transaction, zero when Creation is set
+	Type           byte           // Transaction type
+	Size           uint32         // Size of the payload (without the RLP string envelope for typed transactions)
 
 	// EIP-4844: Shard Blob Transactions
 	BlobFeeCap  uint256.Int // max_fee_per_blob_gas
@@ -110,14 +111,38 @@ type TxSlot struct {
 	Commitments []gokzg4844.KZGCommitment
 	Proofs      []gokzg4844.KZGProof
 
+	// EIP-7702: Set EOA account code
+	AuthCount   int         // Number of authorizations (for calculation of intrinsic gas)
+	Authorities []Authority // Authorities recovered from the authorizations with valid signatures
+
 	RollupCostData RollupCostData
 }
 
+// Authority is the signer of an EIP-7702 authorization together with the nonce the authorization consumes
+type Authority struct {
+	Address common.Address
+	Nonce   uint64
+}
+
+// authorizationMagic is the EIP-7702 prefix of the authorization signing payload
+const authorizationMagic byte = 0x05
+
+// authorization - positions and values of an EIP-7702 authorization needed to recover its authority
+type authorization struct {
+	pos, sigHashEnd int // [chain_id, address, nonce] part of the tuple
+	chainID         uint256.Int
+	address         common.Address
+	nonce           uint64
+	yParity         byte
+	r, s            uint256.Int
+}
+
 const (
 	LegacyTxType     byte = 0
 	AccessListTxType byte = 1 // EIP-2930
 	DynamicFeeTxType byte = 2 // EIP-1559
 	BlobTxType       byte = 3 // EIP-4844
+	SetCodeTxType    byte = 4 // EIP-7702
 	DepositTxType    byte = 126
 )
 
@@ -189,7 +214,7 @@ func (ctx *TxParseContext) ParseTransaction(payload []byte, pos int, slot *TxSlo
 	// If it is non-legacy transaction, the transaction type follows, and then the list
 	if !legacy {
 		slot.Type = payload[p]
-		if slot.Type > BlobTxType && slot.Type != DepositTxType {
+		if slot.Type > SetCodeTxType && slot.Type != DepositTxType {
 			return 0, fmt.Errorf("%w: unknown transaction type: %d", ErrParseTxn, slot.Type)
 		}
 		p++
@@ -299,6 +324,7 @@ func (ctx *TxParseContext) ParseTransaction(payload []byte, pos int, slot *TxSlo
 func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slot *TxSlot, sender []byte, validateHash func([]byte) error) (p int, err error) {
 	p = p0
 	legacy := slot.Type == LegacyTxType
+	var auths []authorization
 
 	// Compute transaction hash
 	ctx.Keccak1.Reset()
@@ -454,8 +480,8 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 		return 0, fmt.Errorf("%w: unexpected length of to field: %d", ErrParseTxn, dataLen)
 	}
 
-	// Only note if To field is empty or not
 	slot.Creation = dataLen == 0
+	slot.To = common.BytesToAddress(payload[dataPos : dataPos+dataLen])
 	p = dataPos + dataLen
 	// Next follows value
 	p, err = rlp.U256(payload, p, &slot.Value)
@@ -535,6 +561,63 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 		}
 		p = dataPos + dataLen
 	}
+	if slot.Type == SetCodeTxType {
+		dataPos, dataLen, err = rlp.List(payload, p)
+		if err != nil {
+			return 0, fmt.Errorf("%w: authorizations len: %s", ErrParseTxn, err) //nolint
+		}
+		authPos := dataPos
+		for authPos < dataPos+dataLen {
+			var authLen int
+			authPos, authLen, err = rlp.List(payload, authPos)
+			if err != nil {
+				return 0, fmt.Errorf("%w: authorization len: %s", ErrParseTxn, err) //nolint
+			}
+			a := authorization{pos: authPos}
+			var fieldPos int
+			fieldPos, err = rlp.U256(payload, authPos, &a.chainID)
+			if err != nil {
+				return 0, fmt.Errorf("%w: authorization chainId: %s", ErrParseTxn, err) //nolint
+			}
+			fieldPos, err = rlp.StringOfLen(payload, fieldPos, 20)
+			if err != nil {
+				return 0, fmt.Errorf("%w: authorization address len: %s", ErrParseTxn, err) //nolint
+			}
+			copy(a.address[:], payload[fieldPos:fieldPos+20])
+			fieldPos, a.nonce, err = rlp.U64(payload, fieldPos+20)
+			if err != nil {
+				return 0, fmt.Errorf("%w: authorization nonce: %s", ErrParseTxn, err) //nolint
+			}
+			a.sigHashEnd = fieldPos
+			var yParity uint64
+			fieldPos, yParity, err = rlp.U64(payload, fieldPos)
+			if err != nil {
+				return 0, fmt.Errorf("%w: authorization yParity: %s", ErrParseTxn, err) //nolint
+			}
+			if yParity > 255 {
+				return 0, fmt.Errorf("%w: authorization yParity is too large: %d", ErrParseTxn, yParity)
+			}
+			a.yParity = byte(yParity)
+			fieldPos, err = rlp.U256(payload, fieldPos, &a.r)
+			if err != nil {
+				return 0, fmt.Errorf("%w: authorization R: %s", ErrParseTxn, err) //nolint
+			}
+			fieldPos, err = rlp.U256(payload, fieldPos, &a.s)
+			if err != nil {
+				return 0, fmt.Errorf("%w: authorization S: %s", ErrParseTxn, err) //nolint
+			}
+			if fieldPos != authPos+authLen {
+				return 0, fmt.Errorf("%w: extraneous space in the authorization", ErrParseTxn)
+			}
+			slot.AuthCount++
+			auths = append(auths, a)
+			authPos += authLen
+		}
+		if authPos != dataPos+dataLen {
+			return 0, fmt.Errorf("%w: extraneous space in the authorizations", ErrParseTxn)
+		}
+		p = dataPos + dataLen
+	}
 	if slot.Type == BlobTxType {
 		p, err = rlp.U256(payload, p, &slot.BlobFeeCap)
 		if err != nil {
@@ -711,9 +794,55 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 	//take last 20 bytes as address
 	copy(sender, ctx.buf[12:32])
 
+	ctx.recoverAuthorities(payload, auths, slot)
+
 	return p, nil
 }
 
+// recoverAuthorities fills slot.Authorities. Authorizations which can't be valid for this chain are skipped,
+// same as during execution, they are only counted for the intrinsic gas.
+func (ctx *TxParseContext) recoverAuthorities(payload []byte, auths []authorization, slot *TxSlot) {
+	for i := range auths {
+		a := &auths[i]
+		if !a.chainID.IsZero() && !a.chainID.Eq(&ctx.cfg.ChainID) {
+			continue
+		}
+		if a.nonce+1 < a.nonce {
+			continue
+		}
+		if !crypto.TransactionSignatureIsValid(a.yParity, &a.r, &a.s, false) {
+			continue
+		}
+		// signing hash is keccak256(MAGIC || rlp([chain_id, address, nonce]))
+		ctx.Keccak2.Reset()
+		ctx.buf[0] = authorizationMagic
+		prefixLen := rlp.EncodeListPrefix(a.sigHashEnd-a.pos, ctx.buf[1:])
+		if _, err := ctx.Keccak2.Write(ctx.buf[:1+prefixLen]); err != nil {
+			continue
+		}
+		if _, err := ctx.Keccak2.Write(payload[a.pos:a.sigHashEnd]); err != nil {
+			continue
+		}
+		var sighash [32]byte
+		_, _ = ctx.Keccak2.(io.Reader).Read(sighash[:])
+		var sig [65]byte
+		a.r.WriteToSlice(sig[0:32])
+		a.s.WriteToSlice(sig[32:64])
+		sig[64] = a.yParity
+		if _, err := secp256k1.RecoverPubkeyWithContext(secp256k1.DefaultContext, sighash[:], sig[:], ctx.buf[:0]); err != nil {
+			continue
+		}
+		ctx.Keccak2.Reset()
+		if _, err := ctx.Keccak2.Write(ctx.buf[1:65]); err != nil {
+			continue
+		}
+		_, _ = ctx.Keccak2.(io.Reader).Read(ctx.buf[:32])
+		authority := Authority{Nonce: a.nonce}
+		copy(authority.Address[:], ctx.buf[12:32])
+		slot.Authorities = append(slot.Authorities, authority)
+	}
+}
+
 type PeerID *types.H512
 
 type Hashes []byte // flatten list of 32-byte hashes
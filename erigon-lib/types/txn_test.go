@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/fixedgas"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 )
@@ -292,3 +293,27 @@ func TestBlobTxParsing(t *testing.T) {
 	assert.Equal(t, proof0, fatTx.Proofs[0])
 	assert.Equal(t, proof1, fatTx.Proofs[1])
 }
+
+func TestSetCodeTxParsing(t *testing.T) {
+	// signed by b71c71a6..., the second authorization is for another chain
+	txRlp := hexutility.MustDecodeHex("04f9011d0503010a830186a094095e7baea6a6c7c4c2dfeb977efac326af552d878080c0f8b8" +
+		"f85a0594095e7baea6a6c7c4c2dfeb977efac326af552d870180a03b715d1c18b290c3d8771e2ea1a4cf548d19101ce1dd57cc18" +
+		"701f63292fa8faa004140c90e549a34ce483ec3df898a232cacc48cfcb7406eecc7fa194949f50a4f85a0794095e7baea6a6c7c4" +
+		"c2dfeb977efac326af552d870201a01c1adf813d0dff78de71966305f6ceb22976de58edc968c4c37b1c52ab122436a00209ff3f" +
+		"23c057a0074fb810213689a45010020c581da76a1a8c2450670b4b8b01a06476980f2356acd3c035e668a32a8a6577fead70c1c6" +
+		"7345e93f22fbc62b5fe5a0318e0b0febf0ce052ed39782a6c476c17b3a0c37f125c7f82465a897c8bdc611")
+
+	ctx := NewTxParseContext(*uint256.NewInt(5))
+	var tx TxSlot
+	sender := make([]byte, 20)
+	p, err := ctx.ParseTransaction(txRlp, 0, &tx, sender, false /* hasEnvelope */, false /* wrappedWithBlobs */, nil)
+	require.NoError(t, err)
+	assert.Equal(t, len(txRlp), p)
+	assert.Equal(t, SetCodeTxType, tx.Type)
+	assert.Equal(t, hexutility.MustDecodeHex("cceb197f7ad3302ab9e4b558d4d1391e3dd89dff17bf32d42437f7112d2d4ada"), tx.IDHash[:])
+	assert.Equal(t, hexutility.MustDecodeHex("71562b71999873db5b286df957af199ec94617f7"), sender)
+	assert.False(t, tx.Creation)
+	assert.Equal(t, 2, tx.AuthCount)
+	require.Len(t, tx.Authorities, 1)
+	assert.Equal(t, Authority{Address: common.HexToAddress("0x703c4b2bd70c169f5717101caee543299fc946c7"), Nonce: 1}, tx.Authorities[0])
+}
@@ -118,6 +118,7 @@ import (
 	polygonsync "github.com/ledgerwatch/erigon/polygon/sync"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/builder"
+	"github.com/ledgerwatch/erigon/turbo/changefeed"
 	"github.com/ledgerwatch/erigon/turbo/engineapi"
 	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_block_downloader"
 	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_helpers"
@@ -205,6 +206,8 @@ type Ethereum struct {
 	blockReader    services.FullBlockReader
 	blockWriter    *blockio.BlockWriter
 	kvRPC          *remotedbserver.KvServer
+	changeFeed     *changefeed.Feed
+	changeFeedRPC  *changefeed.Server
 	logger         log.Logger
 
 	sentinel rpcsentinel.SentinelClient
@@ -304,7 +307,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 			genesisSpec = nil
 		}
 		var genesisErr error
-		chainConfig, genesis, genesisErr = core.WriteGenesisBlock(tx, genesisSpec, config.OverrideCancunTime, config.OverrideShanghaiTime, config.OverrideOptimismCanyonTime, config.OverrideOptimismEcotoneTime, config.OverrideOptimismFjordTime, config.OverrideOptimismGraniteTime, config.OverridePragueTime, tmpdir, logger)
+		chainConfig, genesis, genesisErr = core.WriteGenesisBlock(tx, genesisSpec, config.OverrideCancunTime, config.OverrideShanghaiTime, config.OverrideOptimismCanyonTime, config.OverrideOptimismEcotoneTime, config.OverrideOptimismFjordTime, config.OverrideOptimismGraniteTime, config.OverridePragueTime, config.OverrideRetroactiveForks, tmpdir, logger)
 		if _, ok := genesisErr.(*chain.ConfigCompatError); genesisErr != nil && !ok {
 			return genesisErr
 		}
@@ -379,6 +382,19 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 	backend.notifications.StateChangesConsumer = kvRPC
 	backend.kvRPC = kvRPC
 
+	if config.ChangeFeed {
+		if config.HistoryV3 {
+			return nil, errors.New("--changefeed is not supported with history v3")
+		}
+		backend.changeFeed, err = changefeed.Open(config.Dirs.ChangeFeed, config.ChangeFeedRetention, config.ChangeFeedSegmentSize, blockReader, logger)
+		if err != nil {
+			return nil, fmt.Errorf("change feed: %w", err)
+		}
+		go backend.changeFeed.Run(ctx, backend.chainDB)
+		backend.changeFeedRPC = changefeed.NewServer(backend.changeFeed)
+		backend.notifications.ChangeFeed = backend.changeFeed
+	}
+
 	backend.gasPrice, _ = uint256.FromBig(config.Miner.GasPrice)
 
 	if config.SilkwormExecution || config.SilkwormRpcDaemon || config.SilkwormSentry {
@@ -753,11 +769,16 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 				return nil, err
 			}
 		}
+		var changeFeedRPC remote.CHANGEFEEDServer
+		if backend.changeFeedRPC != nil {
+			changeFeedRPC = backend.changeFeedRPC
+		}
 		backend.privateAPI, err = privateapi.StartGrpc(
 			kvRPC,
 			ethBackendRPC,
 			backend.txPoolGrpcServer,
 			miningRPC,
+			changeFeedRPC,
 			stack.Config().PrivateApiAddr,
 			stack.Config().PrivateApiRateLimit,
 			creds,
@@ -877,6 +898,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 		false,
 		config.Miner.EnabledPOS,
 		config,
+		chainKv,
 		stack.Close)
 	backend.engineBackendRPC = engineBackendRPC
 
@@ -917,7 +939,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 			pruneBlobDistance = math.MaxUint64
 		}
 
-		indiciesDB, blobStorage, err := caplin1.OpenCaplinDatabase(ctx, db_config.DefaultDatabaseConfiguration, beaconCfg, ethClock, dirs.CaplinIndexing, dirs.CaplinBlobs, executionEngine, false, pruneBlobDistance)
+		indiciesDB, blobStorage, err := caplin1.OpenCaplinDatabase(ctx, db_config.DefaultDatabaseConfiguration, beaconCfg, ethClock, dirs.CaplinIndexing, dirs.CaplinBlobs, executionEngine, false, pruneBlobDistance, config.CaplinConfig.BlobArchive)
 		if err != nil {
 			return nil, err
 		}
@@ -996,8 +1018,12 @@ func (s *Ethereum) Init(stack *node.Node, config *ethconfig.Config, chainConfig
 	}
 	// start HTTP API
 	httpRpcCfg := stack.Config().Http
+	var changeFeedClient remote.CHANGEFEEDClient
+	if s.changeFeedRPC != nil {
+		changeFeedClient = direct.NewChangeFeedClientDirect(s.changeFeedRPC)
+	}
 	ethRpcClient, txPoolRpcClient, miningRpcClient, stateCache, ff, err := cli.EmbeddedServices(ctx, chainKv, httpRpcCfg.StateCache, httpRpcCfg.RpcFiltersConfig, blockReader, ethBackendRPC,
-		s.txPoolGrpcServer, miningRPC, stateDiffClient, s.logger)
+		s.txPoolGrpcServer, miningRPC, stateDiffClient, changeFeedClient, s.logger)
 	if err != nil {
 		return err
 	}
@@ -1006,7 +1032,7 @@ func (s *Ethereum) Init(stack *node.Node, config *ethconfig.Config, chainConfig
 	if config.Ethstats != "" {
 		var headCh chan [][]byte
 		headCh, s.unsubscribeEthstat = s.notifications.Events.AddHeaderSubscription()
-		if err := ethstats.New(stack, s.sentryServers, chainKv, s.blockReader, s.engine, config.Ethstats, s.networkID, ctx.Done(), headCh, txPoolRpcClient); err != nil {
+		if err := ethstats.New(stack, s.sentryServers, chainKv, s.blockReader, s.engine, s.chainConfig, config.Ethstats, s.networkID, ctx.Done(), headCh, txPoolRpcClient); err != nil {
 			return err
 		}
 	}
@@ -1387,7 +1413,7 @@ func (s *Ethereum) setUpSnapDownloader(ctx context.Context, downloaderCfg *downl
 	s.agg.OnFreeze(func(frozenFileNames []string) {
 		events := s.notifications.Events
 		events.OnNewSnapshot()
-		if s.downloaderClient != nil {
+		if s.downloaderClient != nil && len(frozenFileNames) > 0 {
 			req := &protodownloader.AddRequest{Items: make([]*protodownloader.AddItem, 0, len(frozenFileNames))}
 			for _, fName := range frozenFileNames {
 				req.Items = append(req.Items, &protodownloader.AddItem{
@@ -1566,6 +1592,9 @@ func (s *Ethereum) Stop() error {
 	if s.waitForStageLoopStop != nil {
 		<-s.waitForStageLoopStop
 	}
+	if s.changeFeed != nil {
+		s.changeFeed.Close()
+	}
 	if s.config.Miner.Enabled {
 		<-s.waitForMiningStop
 	}
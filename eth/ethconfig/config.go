@@ -100,6 +100,9 @@ var Defaults = Config{
 	GPO:              FullNodeGPO,
 	RPCTxFeeCap:      1, // 1 ether
 
+	ChangeFeedRetention:   90_000,
+	ChangeFeedSegmentSize: 256 * datasize.MB,
+
 	ImportMode: false,
 	Snapshot: BlocksFreezing{
 		Enabled:    true,
@@ -227,6 +230,10 @@ type Config struct {
 
 	StateStream bool
 
+	ChangeFeed            bool              // keep the durable feed of per-block state diffs
+	ChangeFeedRetention   uint64            // blocks to keep the diffs for
+	ChangeFeedSegmentSize datasize.ByteSize // size after which the feed starts a new segment file
+
 	//  New DB and Snapshots format of history allows: parallel blocks execution, get state as of given transaction without executing whole block.",
 	HistoryV3 bool
 
@@ -261,6 +268,9 @@ type Config struct {
 
 	OverridePragueTime *big.Int `toml:",omitempty"`
 
+	// OverrideRetroactiveForks accepts chain config updates which change already activated forks
+	OverrideRetroactiveForks bool
+
 	RollupSequencerHTTP        string
 	RollupHistoricalRPC        string
 	RollupHistoricalRPCTimeout time.Duration
@@ -284,6 +294,8 @@ type Config struct {
 	DisableTxPoolGossip bool
 
 	RollupHaltOnIncompatibleProtocolVersion string
+	RollupHaltGraceBlocks                   uint64        // blocks to wait before halting on a required protocol version
+	RollupHaltGracePeriod                   time.Duration // time to wait before halting on a required protocol version
 }
 
 type Sync struct {
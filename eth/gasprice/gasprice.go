@@ -38,6 +38,8 @@ const sampleNumber = 3 // Number of transactions sampled in a block
 type OracleBackend interface {
 	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
 	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error)
+	// TxnByIdxInBlock returns the i-th transaction of a canonical block, nil if there is none
+	TxnByIdxInBlock(ctx context.Context, number uint64, i int) (types.Transaction, error)
 	ChainConfig() *chain.Config
 
 	GetReceipts(ctx context.Context, block *types.Block) (types.Receipts, error)
@@ -89,6 +89,14 @@ func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber)
 	return b.blockReader.BlockByNumber(ctx, tx, uint64(number))
 }
 
+func (b *testBackend) TxnByIdxInBlock(ctx context.Context, number uint64, i int) (types.Transaction, error) {
+	block, err := b.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil || block == nil || i >= len(block.Transactions()) {
+		return nil, err
+	}
+	return block.Transactions()[i], nil
+}
+
 func (b *testBackend) ChainConfig() *chain.Config {
 	return b.cfg
 }
@@ -2,11 +2,14 @@ package gasprice
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"sort"
 
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/opstack"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/log/v3"
@@ -110,3 +113,122 @@ func (oracle *Oracle) SuggestOptimismPriorityFee(ctx context.Context, h *types.H
 
 	return new(big.Int).Set(suggestion)
 }
+
+// l1FeeForecastBlocks is how many blocks ahead the L1 fees are projected for a fee estimate, the
+// inclusion horizon of a transaction priced with it
+const l1FeeForecastBlocks = 5
+
+// optimismL1Infos returns the calldata of the L1 info deposits of the blocks in [oldest, last], oldest
+// first. The entry of a block without an L1 info deposit, i.e. before Bedrock, is nil.
+func (oracle *Oracle) optimismL1Infos(ctx context.Context, oldest, last uint64) ([][]byte, error) {
+	l1Infos := make([][]byte, 0, last+1-oldest)
+	for number := oldest; number <= last; number++ {
+		if err := common.Stopped(ctx.Done()); err != nil {
+			return nil, err
+		}
+		// only the first transaction is read, the L1 info deposit leads the block
+		txn, err := oracle.backend.TxnByIdxInBlock(ctx, number, 0)
+		if err != nil {
+			return nil, err
+		}
+		var l1Info []byte
+		if deposit, ok := txn.(*types.DepositTx); ok && deposit.To != nil && *deposit.To == opstack.L1BlockAddr {
+			l1Info = deposit.Data
+		}
+		l1Infos = append(l1Infos, l1Info)
+	}
+	return l1Infos, nil
+}
+
+// OptimismL1FeeHistory returns the L1 base fee and blob base fee the L1 info deposits of the blocks in
+// [oldest, last] set on the L1Block contract, oldest first, followed by the fees projected from their
+// trend for the block after last. Nil is returned if one of the blocks has no L1 info deposit or one
+// that can't be decoded, e.g. of a later encoding: the L1 fees are an optional extension of the fee history.
+func (oracle *Oracle) OptimismL1FeeHistory(ctx context.Context, oldest, last uint64) ([]opstack.L1Fees, error) {
+	l1Infos, err := oracle.optimismL1Infos(ctx, oldest, last)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]opstack.L1Fees, 0, len(l1Infos)+1)
+	for _, l1Info := range l1Infos {
+		if l1Info == nil {
+			return nil, nil
+		}
+		fees, err := opstack.ExtractL1Fees(l1Info)
+		if err != nil {
+			log.Debug("Failed to decode the L1 info deposit, leaving out the L1 fee history", "err", err)
+			return nil, nil
+		}
+		history = append(history, fees)
+	}
+	return append(history, opstack.ProjectL1Fees(history, 1)), nil
+}
+
+// OptimismFeeEstimate is the estimate of the L2 and L1 fees of a transaction on an OP stack chain
+type OptimismFeeEstimate struct {
+	OldestBlock *big.Int
+	BaseFee     *big.Int // of the next block
+	PriorityFee *big.Int // as suggested by SuggestTipCap
+	// PriorityFeePercentiles holds for each of the requested reward percentiles its median over the blocks
+	PriorityFeePercentiles []*big.Int
+	L1Fee                  *opstack.L1CostForecast
+}
+
+// EstimateOptimismFees estimates the fees of a transaction with the rollup cost data from the given number
+// of latest blocks: the L2 base fee of the next block, the priority fee percentiles of the blocks, and
+// the L1 data fee the transaction pays with the L1 fees of the blocks and with their trend projected
+// l1FeeForecastBlocks ahead.
+func (oracle *Oracle) EstimateOptimismFees(ctx context.Context, rcd types2.RollupCostData, blocks int, rewardPercentiles []float64) (*OptimismFeeEstimate, error) {
+	oldest, reward, baseFee, _, err := oracle.FeeHistory(ctx, blocks, rpc.LatestBlockNumber, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(baseFee) == 0 {
+		return nil, errors.New("no blocks to estimate the fees from")
+	}
+	last := oldest.Uint64() + uint64(len(baseFee)) - 2
+	head, err := oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(last))
+	if err != nil {
+		return nil, err
+	}
+	tip, err := oracle.SuggestTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	estimate := &OptimismFeeEstimate{
+		OldestBlock:            oldest,
+		BaseFee:                baseFee[len(baseFee)-1],
+		PriorityFee:            tip,
+		PriorityFeePercentiles: make([]*big.Int, len(rewardPercentiles)),
+	}
+	for i := range rewardPercentiles {
+		rewards := make(bigIntArray, 0, len(reward))
+		for _, blockReward := range reward {
+			if blockReward != nil {
+				rewards = append(rewards, blockReward[i])
+			}
+		}
+		if len(rewards) == 0 {
+			estimate.PriorityFeePercentiles[i] = new(big.Int)
+			continue
+		}
+		sort.Sort(rewards)
+		estimate.PriorityFeePercentiles[i] = rewards[len(rewards)/2]
+	}
+
+	l1Infos, err := oracle.optimismL1Infos(ctx, oldest.Uint64(), last)
+	if err != nil {
+		return nil, err
+	}
+	// blocks before Bedrock are left out
+	for len(l1Infos) > 0 && l1Infos[0] == nil {
+		l1Infos = l1Infos[1:]
+	}
+	if len(l1Infos) == 0 {
+		return nil, errors.New("no L1 info deposit in the blocks to estimate the L1 fee from")
+	}
+	if estimate.L1Fee, err = opstack.ForecastL1Cost(oracle.backend.ChainConfig(), head.Time+1, l1Infos, rcd, l1FeeForecastBlocks); err != nil {
+		return nil, err
+	}
+	return estimate, nil
+}
@@ -17,13 +17,19 @@
 package gasprice
 
 import (
+	"bytes"
 	"context"
 	"math/big"
 	"testing"
 
 	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
 	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/opstack"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
+	"github.com/ledgerwatch/erigon/consensus/misc"
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/crypto"
@@ -79,6 +85,14 @@ func (b *opTestBackend) PendingBlockAndReceipts() (*types.Block, types.Receipts)
 	panic("not implemented")
 }
 
+func (b *opTestBackend) TxnByIdxInBlock(ctx context.Context, number uint64, i int) (types.Transaction, error) {
+	block, err := b.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil || block == nil || i >= len(block.Transactions()) {
+		return nil, err
+	}
+	return block.Transactions()[i], nil
+}
+
 func (b *opTestBackend) ChainConfig() *chain.Config {
 	return params.OptimismTestConfig
 }
@@ -155,3 +169,144 @@ func TestSuggestOptimismPriorityFee(t *testing.T) {
 		}
 	}
 }
+
+type opChainTestBackend struct {
+	config   *chain.Config
+	blocks   []*types.Block
+	receipts []types.Receipts
+}
+
+func (b *opChainTestBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	block, err := b.BlockByNumber(ctx, number)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+func (b *opChainTestBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(len(b.blocks) - 1)
+	}
+	if int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *opChainTestBackend) GetReceipts(ctx context.Context, block *types.Block) (types.Receipts, error) {
+	return b.receipts[block.NumberU64()], nil
+}
+
+func (b *opChainTestBackend) PendingBlockAndReceipts() (*types.Block, types.Receipts) {
+	return nil, nil
+}
+
+func (b *opChainTestBackend) TxnByIdxInBlock(ctx context.Context, number uint64, i int) (types.Transaction, error) {
+	block, err := b.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil || block == nil || i >= len(block.Transactions()) {
+		return nil, err
+	}
+	return block.Transactions()[i], nil
+}
+
+func (b *opChainTestBackend) ChainConfig() *chain.Config {
+	return b.config
+}
+
+func (b *opChainTestBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return nil
+}
+
+// newOpChainTestBackend returns a chain where block i holds an L1 info deposit setting the L1 base fee
+// l1BaseFees[i], or none if it's zero, followed by a transaction with a priority fee of i gwei
+func newOpChainTestBackend(t *testing.T, l1BaseFees []uint64) *opChainTestBackend {
+	config := *params.OptimismTestConfig
+	config.RegolithTime, config.EcotoneTime = big.NewInt(0), big.NewInt(0)
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	signer := types.LatestSigner(&config)
+	backend := &opChainTestBackend{config: &config}
+	for i, l1BaseFee := range l1BaseFees {
+		header := &types.Header{Number: big.NewInt(int64(i)), Time: 100 + 2*uint64(i), GasLimit: 30_000_000, BaseFee: big.NewInt(params.GWei)}
+		var txs types.Transactions
+		var receipts types.Receipts
+		if l1BaseFee != 0 {
+			// Ecotone L1 attributes with the base fee scalar 2 and the blob base fee scalar 3
+			data := make([]byte, opstack.PostEcotoneL1InfoBytes)
+			copy(data, opstack.EcotoneL1AttributesSelector)
+			data[7], data[11] = 2, 3
+			uint256.NewInt(l1BaseFee).WriteToSlice(data[36:68])
+			uint256.NewInt(10 * 1e6).WriteToSlice(data[68:100])
+			txs = append(txs, &types.DepositTx{To: &opstack.L1BlockAddr, Mint: uint256.NewInt(0), Value: uint256.NewInt(0), Gas: 1_000_000, Data: data})
+			receipts = append(receipts, &types.Receipt{GasUsed: 50_000})
+		}
+		txs = append(txs, types.MustSignNewTx(key, *signer, &types.DynamicFeeTransaction{
+			CommonTx: types.CommonTx{Nonce: uint64(i), Gas: params.TxGas, Value: uint256.NewInt(0)},
+			ChainID:  uint256.MustFromBig(config.ChainID),
+			Tip:      uint256.NewInt(uint64(i) * params.GWei),
+			FeeCap:   uint256.NewInt(100 * params.GWei),
+		}))
+		receipts = append(receipts, &types.Receipt{GasUsed: params.TxGas})
+		for _, r := range receipts {
+			header.GasUsed += r.GasUsed
+		}
+		backend.blocks = append(backend.blocks, types.NewBlock(header, txs, nil, nil, nil))
+		backend.receipts = append(backend.receipts, receipts)
+	}
+	return backend
+}
+
+func TestOptimismL1FeeHistory(t *testing.T) {
+	backend := newOpChainTestBackend(t, []uint64{0, 1000 * 1e6, 1100 * 1e6, 1200 * 1e6})
+	oracle := NewOracle(backend, gaspricecfg.Config{}, &testCache{})
+
+	fees, err := oracle.OptimismL1FeeHistory(context.Background(), 1, 3)
+	require.NoError(t, err)
+	require.Len(t, fees, 4)
+	for i, baseFee := range []uint64{1000 * 1e6, 1100 * 1e6, 1200 * 1e6, 1300 * 1e6} {
+		require.Equal(t, uint256.NewInt(baseFee), fees[i].BaseFee)
+		require.Equal(t, uint256.NewInt(10*1e6), fees[i].BlobBaseFee)
+	}
+
+	// the first block has no L1 info deposit
+	fees, err = oracle.OptimismL1FeeHistory(context.Background(), 0, 3)
+	require.NoError(t, err)
+	require.Nil(t, fees)
+
+	// an L1 info deposit of an unknown encoding leaves the L1 fees out instead of failing
+	backend.blocks[2].Transactions()[0].(*types.DepositTx).Data = []byte{0xde, 0xad, 0xbe, 0xef}
+	fees, err = oracle.OptimismL1FeeHistory(context.Background(), 1, 3)
+	require.NoError(t, err)
+	require.Nil(t, fees)
+}
+
+func TestEstimateOptimismFees(t *testing.T) {
+	backend := newOpChainTestBackend(t, []uint64{0, 1000 * 1e6, 1200 * 1e6, 1100 * 1e6, 1300 * 1e6})
+	minSuggestion := big.NewInt(1e8)
+	oracle := NewOracle(backend, gaspricecfg.Config{MinSuggestedPriorityFee: minSuggestion}, &testCache{})
+	rcd := types2.RollupCostData{Ones: 100}
+
+	// the 90th percentile is the priority fee of the transaction after the deposit
+	estimate, err := oracle.EstimateOptimismFees(context.Background(), rcd, 4, []float64{0, 90})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), estimate.OldestBlock.Uint64())
+	require.Equal(t, minSuggestion, estimate.PriorityFee)
+	require.Len(t, estimate.PriorityFeePercentiles, 2)
+	require.Zero(t, estimate.PriorityFeePercentiles[0].Sign())
+	require.Equal(t, uint64(3*params.GWei), estimate.PriorityFeePercentiles[1].Uint64())
+	require.Equal(t, misc.CalcBaseFee(backend.config, backend.blocks[4].Header(), backend.blocks[4].Time()+1), estimate.BaseFee)
+
+	cost := func(l1BaseFee uint64) *uint256.Int {
+		data := bytes.Clone(backend.blocks[4].Transactions()[0].GetData())
+		uint256.NewInt(l1BaseFee).WriteToSlice(data[36:68])
+		gasParams, err := opstack.ExtractL1GasParams(backend.config, backend.blocks[4].Time()+1, data)
+		require.NoError(t, err)
+		fee, _ := gasParams.CostFunc(rcd)
+		return fee
+	}
+	// the L1 base fee rises by 100 per block over the 4 blocks, it's projected 5 blocks ahead
+	require.Equal(t, cost(1300*1e6), estimate.L1Fee.Current)
+	require.Equal(t, cost(1800*1e6), estimate.L1Fee.Projected)
+	require.Equal(t, cost(1000*1e6), estimate.L1Fee.Low)
+	require.Equal(t, cost(1800*1e6), estimate.L1Fee.High)
+}
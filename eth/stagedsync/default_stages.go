@@ -718,6 +718,131 @@ func StateStages(ctx context.Context, headers HeadersCfg, bodies BodiesCfg, bloc
 	}
 }
 
+// ReplicaStages are the stages of a read replica: blocks and state are copied from the primary, so Execution
+// only unwinds, while the commitment and the indices are built locally from the copied changesets.
+func ReplicaStages(ctx context.Context, exec ExecuteBlockCfg, hashState HashStateCfg, trieCfg TrieCfg, history HistoryCfg, logIndex LogIndexCfg, callTraces CallTracesCfg, txLookup TxLookupCfg, finish FinishCfg) []*Stage {
+	return []*Stage{
+		{
+			ID:          stages.Execution,
+			Description: "Apply state changes of the primary",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return nil
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindExecutionStage(u, s, txc, ctx, exec, firstCycle, logger)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneExecutionStage(p, tx, exec, ctx, firstCycle)
+			},
+		},
+		{
+			ID:          stages.HashState,
+			Description: "Hash the key in the state",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return SpawnHashStateStage(s, txc.Tx, hashState, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindHashStateStage(u, s, txc.Tx, hashState, ctx, logger)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneHashStateStage(p, tx, hashState, ctx)
+			},
+		},
+		{
+			ID:          stages.IntermediateHashes,
+			Description: "Generate intermediate hashes and computing state root",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				_, err := SpawnIntermediateHashesStage(s, u, txc.Tx, trieCfg, ctx, logger)
+				return err
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindIntermediateHashesStage(u, s, txc.Tx, trieCfg, ctx, logger)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneIntermediateHashesStage(p, tx, trieCfg, ctx)
+			},
+		},
+		{
+			ID:          stages.CallTraces,
+			Description: "Generate call traces index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return SpawnCallTraces(s, txc.Tx, callTraces, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindCallTraces(u, s, txc.Tx, callTraces, ctx, logger)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneCallTraces(p, tx, callTraces, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.AccountHistoryIndex,
+			Description: "Generate account history index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return SpawnAccountHistoryIndex(s, txc.Tx, history, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindAccountHistoryIndex(u, s, txc.Tx, history, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneAccountHistoryIndex(p, tx, history, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.StorageHistoryIndex,
+			Description: "Generate storage history index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return SpawnStorageHistoryIndex(s, txc.Tx, history, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindStorageHistoryIndex(u, s, txc.Tx, history, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneStorageHistoryIndex(p, tx, history, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.LogIndex,
+			Description: "Generate receipt logs index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return SpawnLogIndex(s, txc.Tx, logIndex, ctx, 0, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindLogIndex(u, s, txc.Tx, logIndex, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneLogIndex(p, tx, logIndex, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.TxLookup,
+			Description: "Generate tx lookup index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return SpawnTxLookup(s, txc.Tx, 0 /* toBlock */, txLookup, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindTxLookup(u, s, txc.Tx, txLookup, ctx, logger)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneTxLookup(p, tx, txLookup, ctx, firstCycle, logger)
+			},
+		},
+		{
+			ID:          stages.Finish,
+			Description: "Final: update current block for the RPC API",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, _ Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return FinishForward(s, txc.Tx, finish, firstCycle)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return UnwindFinish(u, txc.Tx, finish, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneFinish(p, tx, finish, ctx)
+			},
+		},
+	}
+}
+
 var DefaultForwardOrder = UnwindOrder{
 	stages.Snapshots,
 	stages.Headers,
@@ -795,6 +920,36 @@ var StateUnwindOrder = UnwindOrder{
 	stages.Headers,
 }
 
+var ReplicaUnwindOrder = UnwindOrder{
+	stages.Finish,
+	stages.TxLookup,
+	stages.LogIndex,
+	stages.StorageHistoryIndex,
+	stages.AccountHistoryIndex,
+	stages.CallTraces,
+
+	// Unwinding of IHashes needs to happen after unwinding HashState
+	stages.HashState,
+	stages.IntermediateHashes,
+
+	stages.Execution,
+}
+
+var ReplicaPruneOrder = PruneOrder{
+	stages.Finish,
+	stages.TxLookup,
+	stages.LogIndex,
+	stages.StorageHistoryIndex,
+	stages.AccountHistoryIndex,
+	stages.CallTraces,
+
+	// Pruning of IHashes needs to happen after pruning HashState
+	stages.HashState,
+	stages.IntermediateHashes,
+
+	stages.Execution,
+}
+
 var DefaultPruneOrder = PruneOrder{
 	stages.Finish,
 	stages.TxLookup,
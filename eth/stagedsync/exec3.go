@@ -36,6 +36,7 @@ import (
 	"github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/rawdb/rawdbhelpers"
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/types"
@@ -227,6 +228,14 @@ func ExecV3(ctx context.Context,
 		}
 	}
 	agg.SetTxNum(inputTxNum)
+	// with history v3 the address+topic0 log index is filled by execution, see StateV3.ApplyHistory
+	if applyTx != nil {
+		if err := markLogAddressTopicIndexFrom(applyTx, block); err != nil {
+			return err
+		}
+	} else if err := chainDb.Update(ctx, func(tx kv.RwTx) error { return markLogAddressTopicIndexFrom(tx, block) }); err != nil {
+		return err
+	}
 
 	var outputBlockNum = stages.SyncMetrics[stages.Execution]
 	inputBlockNum := &atomic.Uint64{}
@@ -574,6 +583,12 @@ Loop:
 		}
 
 		rules := chainConfig.Rules(blockNum, b.Time())
+		// the parallel executor spreads the transactions of a block over its workers, only the serial one is profiled
+		var profiler *execProfiler
+		if !parallel && writeExecProfile(blockNum, maxBlockNum) {
+			profiler = newExecProfilerV3(b, applyWorker.CallTracer(), applyWorker.StateReader())
+			applyWorker.SetTracer(profiler)
+		}
 		var gasUsed uint64
 		for txIndex := -1; txIndex <= len(txs); txIndex++ {
 
@@ -624,6 +639,9 @@ Loop:
 			} else {
 				count++
 				applyWorker.RunTxTask(txTask)
+				if profiler != nil && txTask.Error == nil {
+					profiler.countWrites(txTask.WriteLists)
+				}
 				if err := func() error {
 					if txTask.Final {
 						gasUsed += txTask.UsedGas
@@ -651,6 +669,7 @@ Loop:
 							return err
 						}
 					}
+					applyWorker.SetTracer(nil)
 					u.UnwindTo(blockNum-1, BadBlock(header.Hash(), err))
 					break Loop
 				}
@@ -668,6 +687,12 @@ Loop:
 			stageProgress = blockNum
 			inputTxNum++
 		}
+		if profiler != nil {
+			applyWorker.SetTracer(nil)
+			if err := rawdb.WriteBlockExecutionProfile(applyTx, profiler.profile(), execProfilesKept); err != nil {
+				return err
+			}
+		}
 
 		if !parallel {
 			outputBlockNum.SetUint64(blockNum)
@@ -0,0 +1,318 @@
+package stagedsync
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
+
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+const (
+	// execProfilesKept - number of recent blocks for which execution profiles are kept
+	execProfilesKept = 10_000
+	// execProfileTopContracts - number of most gas consuming contracts recorded per block
+	execProfileTopContracts = 5
+)
+
+// writeExecProfile reports whether the execution profile of the block is recorded when executing up to `to`.
+// Only the blocks which stay in the ring are profiled, so catching up from far behind the tip doesn't pay for it.
+func writeExecProfile(blockNum, to uint64) bool {
+	return blockNum+execProfilesKept > to
+}
+
+var (
+	blockExecSeconds         = metrics.GetOrCreateHistogram("block_execution_seconds")
+	blockExecSlowestTxSecs   = metrics.GetOrCreateHistogram("block_execution_slowest_tx_seconds")
+	blockExecGasUsedRatio    = metrics.GetOrCreateHistogram("block_execution_gas_used_ratio")
+	blockExecBatchHitRatio   = metrics.GetOrCreateHistogram("block_execution_batch_hit_ratio")
+	blockExecStateReadsPerTx = metrics.GetOrCreateHistogram("block_execution_state_reads_per_tx")
+)
+
+// readStats is implemented by the in-memory state of the execution stage, which serves the reads of state
+// written since its last flush: the batch in history v2, the reader of the buffered StateV3 in history v3.
+type readStats interface {
+	ReadStats() (hits, misses uint64)
+}
+
+// execProfiler collects the execution profile of a block. It wraps the tracer of the execution stage to
+// time transactions and attribute the gas of every call frame to the contract whose code ran in it, and
+// counts state reads and writes.
+type execProfiler struct {
+	vm.EVMLogger
+
+	block      *types.Block
+	batchStats readStats
+	hits       uint64
+	misses     uint64
+	start      time.Time
+
+	reads          uint64
+	writes         uint64
+	readsFromStats bool // the reader isn't wrapped, all its reads are counted by batchStats
+
+	txIndex     int
+	txStart     time.Time
+	txGasLimit  uint64
+	txContracts map[libcommon.Address]struct{} // contracts whose code ran in the current transaction
+	frames      []execFrame
+	slowest     *diagnostics.TxExecutionProfile
+	contracts   map[libcommon.Address]*diagnostics.ContractGasUsage
+}
+
+type execFrame struct {
+	addr     libcommon.Address
+	hasCode  bool   // EOAs and precompiles don't run contract code
+	childGas uint64 // gas used by the frames called from this one
+}
+
+func newExecProfiler(block *types.Block, tracer vm.EVMLogger, batch interface{}) *execProfiler {
+	p := &execProfiler{
+		EVMLogger: tracer,
+		block:     block,
+		txIndex:   -1,
+		contracts: map[libcommon.Address]*diagnostics.ContractGasUsage{},
+		start:     time.Now(),
+	}
+	if rs, ok := batch.(readStats); ok {
+		p.batchStats = rs
+		p.hits, p.misses = rs.ReadStats()
+	}
+	return p
+}
+
+// newExecProfilerV3 profiles a block run by the serial executor of history v3. Its transactions run on the apply
+// worker, so the state reads are counted by the worker's reader and the writes are added with countWrites.
+func newExecProfilerV3(block *types.Block, tracer vm.EVMLogger, reader readStats) *execProfiler {
+	p := newExecProfiler(block, tracer, reader)
+	p.readsFromStats = true
+	return p
+}
+
+// countWrites adds the writes of a transaction run by the history v3 executor.
+func (p *execProfiler) countWrites(writeLists map[string]*libstate.KvList) {
+	for _, list := range writeLists {
+		p.writes += uint64(len(list.Keys))
+	}
+}
+
+func (p *execProfiler) CaptureTxStart(gasLimit uint64) {
+	p.txIndex++
+	p.txStart = time.Now()
+	p.txGasLimit = gasLimit
+	p.txContracts = map[libcommon.Address]struct{}{}
+	p.frames = p.frames[:0]
+	p.EVMLogger.CaptureTxStart(gasLimit)
+}
+
+func (p *execProfiler) CaptureStart(env *vm.EVM, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	p.enterFrame(to, precompile, create, code)
+	p.EVMLogger.CaptureStart(env, from, to, precompile, create, input, gas, value, code)
+}
+
+func (p *execProfiler) CaptureEnd(output []byte, usedGas uint64, err error) {
+	p.exitFrame(usedGas)
+	p.EVMLogger.CaptureEnd(output, usedGas, err)
+}
+
+func (p *execProfiler) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	p.enterFrame(to, precompile, create, code)
+	p.EVMLogger.CaptureEnter(typ, from, to, precompile, create, input, gas, value, code)
+}
+
+func (p *execProfiler) CaptureExit(output []byte, usedGas uint64, err error) {
+	p.exitFrame(usedGas)
+	p.EVMLogger.CaptureExit(output, usedGas, err)
+}
+
+func (p *execProfiler) enterFrame(addr libcommon.Address, precompile, create bool, code []byte) {
+	// the init code of a contract creation is passed as input
+	p.frames = append(p.frames, execFrame{addr: addr, hasCode: create || (!precompile && len(code) > 0)})
+}
+
+// exitFrame credits the contract of the frame with the gas used by its own code.
+func (p *execProfiler) exitFrame(usedGas uint64) {
+	if len(p.frames) == 0 {
+		return
+	}
+	frame := p.frames[len(p.frames)-1]
+	p.frames = p.frames[:len(p.frames)-1]
+	if len(p.frames) > 0 {
+		p.frames[len(p.frames)-1].childGas += usedGas
+	}
+	if !frame.hasCode {
+		return
+	}
+	usage, ok := p.contracts[frame.addr]
+	if !ok {
+		usage = &diagnostics.ContractGasUsage{Address: frame.addr}
+		p.contracts[frame.addr] = usage
+	}
+	if usedGas > frame.childGas {
+		usage.GasUsed += usedGas - frame.childGas
+	}
+	if _, ok := p.txContracts[frame.addr]; !ok {
+		p.txContracts[frame.addr] = struct{}{}
+		usage.TxCount++
+	}
+}
+
+func (p *execProfiler) CaptureTxEnd(restGas uint64) {
+	p.EVMLogger.CaptureTxEnd(restGas)
+	var gasUsed uint64
+	if p.txGasLimit > restGas {
+		gasUsed = p.txGasLimit - restGas
+	}
+	elapsed := time.Since(p.txStart)
+	if p.slowest == nil || uint64(elapsed.Microseconds()) > p.slowest.WallTimeUs {
+		txs := p.block.Transactions()
+		if p.txIndex < len(txs) {
+			p.slowest = &diagnostics.TxExecutionProfile{
+				Hash:       txs[p.txIndex].Hash(),
+				Index:      p.txIndex,
+				GasUsed:    gasUsed,
+				WallTimeUs: uint64(elapsed.Microseconds()),
+			}
+		}
+	}
+}
+
+func (p *execProfiler) wrapReader(r state.StateReader) state.StateReader {
+	return &countingStateReader{StateReader: r, count: &p.reads}
+}
+
+func (p *execProfiler) wrapWriter(w state.WriterWithChangeSets) state.WriterWithChangeSets {
+	return &countingStateWriter{WriterWithChangeSets: w, count: &p.writes}
+}
+
+// profile returns the collected profile and reports it to the execution metrics.
+func (p *execProfiler) profile() *diagnostics.BlockExecutionProfile {
+	elapsed := time.Since(p.start)
+	header := p.block.Header()
+	profile := &diagnostics.BlockExecutionProfile{
+		BlockNumber:  header.Number.Uint64(),
+		BlockHash:    p.block.Hash(),
+		GasUsed:      header.GasUsed,
+		GasLimit:     header.GasLimit,
+		WallTimeUs:   uint64(elapsed.Microseconds()),
+		TxCount:      p.block.Transactions().Len(),
+		StateReads:   p.reads,
+		StateWrites:  p.writes,
+		SlowestTx:    p.slowest,
+		TopContracts: make([]diagnostics.ContractGasUsage, 0, execProfileTopContracts),
+	}
+	for _, txn := range p.block.Transactions() {
+		if txn.Type() == types.DepositTxType {
+			profile.DepositCount++
+		}
+	}
+	if p.batchStats != nil {
+		hits, misses := p.batchStats.ReadStats()
+		profile.BatchHits, profile.BatchMisses = hits-p.hits, misses-p.misses
+		if total := profile.BatchHits + profile.BatchMisses; total > 0 {
+			profile.BatchHitRatio = float64(profile.BatchHits) / float64(total)
+		}
+		if p.readsFromStats {
+			p.reads = profile.BatchHits + profile.BatchMisses
+			profile.StateReads = p.reads
+		}
+	}
+	contracts := make([]*diagnostics.ContractGasUsage, 0, len(p.contracts))
+	for _, usage := range p.contracts {
+		contracts = append(contracts, usage)
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		if contracts[i].GasUsed != contracts[j].GasUsed {
+			return contracts[i].GasUsed > contracts[j].GasUsed
+		}
+		return bytes.Compare(contracts[i].Address[:], contracts[j].Address[:]) < 0
+	})
+	for i := 0; i < len(contracts) && i < execProfileTopContracts; i++ {
+		profile.TopContracts = append(profile.TopContracts, *contracts[i])
+	}
+
+	blockExecSeconds.Observe(elapsed.Seconds())
+	if p.slowest != nil {
+		blockExecSlowestTxSecs.Observe(float64(p.slowest.WallTimeUs) / 1e6)
+	}
+	if header.GasLimit > 0 {
+		blockExecGasUsedRatio.Observe(float64(header.GasUsed) / float64(header.GasLimit))
+	}
+	if p.batchStats != nil {
+		blockExecBatchHitRatio.Observe(profile.BatchHitRatio)
+	}
+	if profile.TxCount > 0 {
+		blockExecStateReadsPerTx.Observe(float64(p.reads) / float64(profile.TxCount))
+	}
+	return profile
+}
+
+type countingStateReader struct {
+	state.StateReader
+	count *uint64
+}
+
+func (r *countingStateReader) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	*r.count++
+	return r.StateReader.ReadAccountData(address)
+}
+
+func (r *countingStateReader) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	*r.count++
+	return r.StateReader.ReadAccountStorage(address, incarnation, key)
+}
+
+func (r *countingStateReader) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	*r.count++
+	return r.StateReader.ReadAccountCode(address, incarnation, codeHash)
+}
+
+func (r *countingStateReader) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	*r.count++
+	return r.StateReader.ReadAccountCodeSize(address, incarnation, codeHash)
+}
+
+func (r *countingStateReader) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	*r.count++
+	return r.StateReader.ReadAccountIncarnation(address)
+}
+
+type countingStateWriter struct {
+	state.WriterWithChangeSets
+	count *uint64
+}
+
+func (w *countingStateWriter) UpdateAccountData(address libcommon.Address, original, account *accounts.Account) error {
+	*w.count++
+	return w.WriterWithChangeSets.UpdateAccountData(address, original, account)
+}
+
+func (w *countingStateWriter) UpdateAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash, code []byte) error {
+	*w.count++
+	return w.WriterWithChangeSets.UpdateAccountCode(address, incarnation, codeHash, code)
+}
+
+func (w *countingStateWriter) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	*w.count++
+	return w.WriterWithChangeSets.DeleteAccount(address, original)
+}
+
+func (w *countingStateWriter) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	*w.count++
+	return w.WriterWithChangeSets.WriteAccountStorage(address, incarnation, key, original, value)
+}
+
+func (w *countingStateWriter) CreateContract(address libcommon.Address) error {
+	*w.count++
+	return w.WriterWithChangeSets.CreateContract(address)
+}
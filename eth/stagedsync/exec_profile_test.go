@@ -0,0 +1,101 @@
+package stagedsync
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/calltracer"
+)
+
+func TestWriteExecProfile(t *testing.T) {
+	require.True(t, writeExecProfile(100, 100))
+	require.True(t, writeExecProfile(100, 100+execProfilesKept-1))
+	require.False(t, writeExecProfile(100, 100+execProfilesKept))
+	require.False(t, writeExecProfile(1, 20_000_000))
+}
+
+type fakeReadStats struct{ hits, misses uint64 }
+
+func (s *fakeReadStats) ReadStats() (uint64, uint64) { return s.hits, s.misses }
+
+func TestExecProfilerContracts(t *testing.T) {
+	eoa, a, b, c := libcommon.Address{0xee}, libcommon.Address{0xa}, libcommon.Address{0xb}, libcommon.Address{0xc}
+	code := []byte{byte(vm.STOP)}
+	txs := []types.Transaction{
+		types.NewTransaction(0, a, uint256.NewInt(0), 100_000, uint256.NewInt(1), nil),
+		types.NewTransaction(1, eoa, uint256.NewInt(1), 21_000, uint256.NewInt(1), nil),
+		types.NewTransaction(2, a, uint256.NewInt(0), 100_000, uint256.NewInt(1), nil),
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1), GasLimit: 1_000_000, GasUsed: 123_000}, txs, nil, nil, nil)
+	stats := &fakeReadStats{hits: 10, misses: 5}
+	p := newExecProfiler(block, calltracer.NewCallTracer(), stats)
+
+	// a runs 13000 gas, 3000 of them in b and 3000 in a precompile, and transfers to an EOA
+	p.CaptureTxStart(100_000)
+	p.CaptureStart(nil, eoa, a, false, false, nil, 78_000, nil, code)
+	p.CaptureEnter(vm.CALL, a, b, false, false, nil, 50_000, nil, code)
+	p.CaptureExit(nil, 3_000, nil)
+	p.CaptureEnter(vm.CALL, a, eoa, false, false, nil, 50_000, nil, nil)
+	p.CaptureExit(nil, 0, nil)
+	p.CaptureEnter(vm.STATICCALL, a, libcommon.BytesToAddress([]byte{1}), true, false, nil, 50_000, nil, nil)
+	p.CaptureExit(nil, 3_000, nil)
+	p.CaptureEnd(nil, 13_000, nil)
+	p.CaptureTxEnd(100_000 - 34_000)
+
+	// plain transfer
+	p.CaptureTxStart(21_000)
+	p.CaptureStart(nil, eoa, eoa, false, false, nil, 0, uint256.NewInt(1), nil)
+	p.CaptureEnd(nil, 0, nil)
+	p.CaptureTxEnd(0)
+
+	// a runs 7000 gas and creates c, which runs 5000 gas of init code
+	p.CaptureTxStart(100_000)
+	p.CaptureStart(nil, eoa, a, false, false, nil, 78_000, nil, code)
+	p.CaptureEnter(vm.CREATE, a, c, false, true, code, 50_000, nil, nil)
+	p.CaptureExit(nil, 5_000, nil)
+	p.CaptureEnd(nil, 7_000, nil)
+	p.CaptureTxEnd(100_000 - 28_000)
+
+	stats.hits, stats.misses = 40, 15
+	profile := p.profile()
+	require.Equal(t, []diagnostics.ContractGasUsage{
+		{Address: a, GasUsed: 9_000, TxCount: 2},
+		{Address: c, GasUsed: 5_000, TxCount: 1},
+		{Address: b, GasUsed: 3_000, TxCount: 1},
+	}, profile.TopContracts)
+	require.Equal(t, 3, profile.TxCount)
+	require.Equal(t, uint64(30), profile.BatchHits)
+	require.Equal(t, uint64(10), profile.BatchMisses)
+	require.Equal(t, 0.75, profile.BatchHitRatio)
+	require.NotNil(t, profile.SlowestTx)
+}
+
+func TestExecProfilerV3Counts(t *testing.T) {
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1), GasLimit: 1_000_000}, nil, nil, nil, nil)
+	stats := &fakeReadStats{hits: 10, misses: 5}
+	p := newExecProfilerV3(block, calltracer.NewCallTracer(), stats)
+
+	p.countWrites(map[string]*libstate.KvList{
+		kv.PlainState:     {Keys: []string{"a", "b"}},
+		kv.IncarnationMap: {},
+	})
+	p.countWrites(map[string]*libstate.KvList{kv.Code: {Keys: []string{"c"}}})
+	stats.hits, stats.misses = 16, 7
+
+	// the reads of the worker's reader are the hits and misses of the block
+	profile := p.profile()
+	require.Equal(t, uint64(8), profile.StateReads)
+	require.Equal(t, uint64(3), profile.StateWrites)
+	require.Equal(t, uint64(6), profile.BatchHits)
+	require.Equal(t, uint64(2), profile.BatchMisses)
+}
@@ -0,0 +1,68 @@
+package stagedsync
+
+import (
+	"fmt"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+)
+
+var reorgDepth = metrics.GetOrCreateHistogram("reorg_depth")
+
+// reorgsKept - number of latest reorgs kept in the reorg log
+const reorgsKept = 10_000
+
+// RecordReorg writes a reorg log entry for the canonical blocks above `ancestor` which are about to be
+// replaced by `added` (ascending, may be empty). It must be called before the canonical markers
+// of the dropped blocks are removed or overwritten. Nothing is recorded if no canonical block is dropped.
+func RecordReorg(tx kv.RwTx, trigger types.ReorgTrigger, ancestor uint64, added []libcommon.Hash) error {
+	oldHeadNumber, err := stages.GetStageProgress(tx, stages.Headers)
+	if err != nil {
+		return err
+	}
+	var dropped []libcommon.Hash
+	for n := ancestor + 1; n <= oldHeadNumber; n++ {
+		hash, err := rawdb.ReadCanonicalHash(tx, n)
+		if err != nil {
+			return err
+		}
+		if hash == (libcommon.Hash{}) {
+			break
+		}
+		dropped = append(dropped, hash)
+	}
+	if len(dropped) == 0 {
+		return nil
+	}
+	ancestorHash, err := rawdb.ReadCanonicalHash(tx, ancestor)
+	if err != nil {
+		return err
+	}
+	reorg := &types.Reorg{
+		OldHead:        dropped[len(dropped)-1],
+		OldNumber:      ancestor + uint64(len(dropped)),
+		NewHead:        ancestorHash,
+		NewNumber:      ancestor,
+		Ancestor:       ancestorHash,
+		AncestorNumber: ancestor,
+		Dropped:        dropped,
+		Added:          added,
+		Time:           uint64(time.Now().Unix()),
+		Trigger:        trigger,
+	}
+	if len(added) > 0 {
+		reorg.NewHead = added[len(added)-1]
+		reorg.NewNumber = ancestor + uint64(len(added))
+	}
+	if _, err := rawdb.WriteReorg(tx, reorg, reorgsKept); err != nil {
+		return fmt.Errorf("RecordReorg: %w", err)
+	}
+	reorgDepth.Observe(float64(reorg.Depth()))
+	return nil
+}
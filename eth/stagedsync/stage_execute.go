@@ -145,6 +145,7 @@ func executeBlock(
 	writeChangesets bool,
 	writeReceipts bool,
 	writeCallTraces bool,
+	writeProfile bool,
 	stateStream bool,
 	logger log.Logger,
 ) error {
@@ -167,6 +168,12 @@ func executeBlock(
 	callTracer := calltracer.NewCallTracer()
 	vmConfig.Debug = true
 	vmConfig.Tracer = callTracer
+	var profiler *execProfiler
+	if writeProfile {
+		profiler = newExecProfiler(block, callTracer, batch)
+		vmConfig.Tracer = profiler
+		stateReader, stateWriter = profiler.wrapReader(stateReader), profiler.wrapWriter(stateWriter)
+	}
 
 	var receipts types.Receipts
 	var stateSyncReceipt *types.Receipt
@@ -180,6 +187,12 @@ func executeBlock(
 	receipts = execRs.Receipts
 	stateSyncReceipt = execRs.StateSyncReceipt
 
+	if profiler != nil {
+		if err = rawdb.WriteBlockExecutionProfile(tx, profiler.profile(), execProfilesKept); err != nil {
+			return err
+		}
+	}
+
 	// If writeReceipts is false here, append the not to be pruned receipts anyways
 	if writeReceipts || gatherNoPruneReceipts(&receipts, cfg.chainConfig) {
 		if err = rawdb.AppendReceipts(tx, blockNum, receipts); err != nil {
@@ -495,6 +508,7 @@ Loop:
 		writeChangeSets := nextStagesExpectData || blockNum > cfg.prune.History.PruneTo(to)
 		writeReceipts := nextStagesExpectData || blockNum > cfg.prune.Receipts.PruneTo(to)
 		writeCallTraces := nextStagesExpectData || blockNum > cfg.prune.CallTraces.PruneTo(to)
+		writeProfile := writeExecProfile(blockNum, to)
 
 		metrics.UpdateBlockConsumerPreExecutionDelay(block.Time(), blockNum, logger)
 
@@ -526,7 +540,7 @@ Loop:
 				blockNum++
 			}
 		} else {
-			err = executeBlock(block, txc.Tx, batch, cfg, *cfg.vmConfig, writeChangeSets, writeReceipts, writeCallTraces, stateStream, logger)
+			err = executeBlock(block, txc.Tx, batch, cfg, *cfg.vmConfig, writeChangeSets, writeReceipts, writeCallTraces, writeProfile, stateStream, logger)
 		}
 
 		if err != nil {
@@ -804,6 +818,10 @@ func unwindExecutionStage(u *UnwindState, s *StageState, txc wrap.TxContainer, c
 		accumulator.StartChange(u.UnwindPoint, hash, txs, true)
 	}
 
+	if err := rawdb.TruncateBlockExecutionProfiles(txc.Tx, u.UnwindPoint+1); err != nil {
+		return fmt.Errorf("truncate execution profiles: %w", err)
+	}
+
 	if cfg.historyV3 {
 		return unwindExec3(u, s, txc, ctx, cfg, accumulator, logger)
 	}
@@ -967,7 +985,11 @@ func PruneExecutionStage(s *PruneState, tx kv.RwTx, cfg ExecuteBlockCfg, ctx con
 		}
 
 		if cfg.prune.Receipts.Enabled() {
-			if err = rawdb.PruneTable(tx, kv.Receipts, cfg.prune.Receipts.PruneTo(s.ForwardProgress), ctx, math.MaxInt32); err != nil {
+			pruneFrom, err := legacyReceiptsPruneFrom(tx)
+			if err != nil {
+				return err
+			}
+			if err = rawdb.PruneTableFrom(tx, kv.Receipts, pruneFrom, cfg.prune.Receipts.PruneTo(s.ForwardProgress), ctx, math.MaxInt32); err != nil {
 				return err
 			}
 			if err = rawdb.PruneTable(tx, kv.BorReceipts, cfg.prune.Receipts.PruneTo(s.ForwardProgress), ctx, math.MaxUint32); err != nil {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"runtime"
 	"slices"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/dbutils"
 
 	"github.com/RoaringBitmap/roaring"
+	"github.com/RoaringBitmap/roaring/roaring64"
 	"github.com/c2h5oh/datasize"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/dbg"
@@ -19,8 +21,10 @@ import (
 	"github.com/ledgerwatch/erigon-lib/etl"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/bitmapdb"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
 	"github.com/ledgerwatch/log/v3"
 
+	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/ethdb/cbor"
 	"github.com/ledgerwatch/erigon/ethdb/prune"
@@ -38,9 +42,10 @@ type LogIndexCfg struct {
 	bufLimit         datasize.ByteSize
 	flushEvery       time.Duration
 	noPruneContracts map[libcommon.Address]bool
+	agg              *libstate.Aggregator
 }
 
-func StageLogIndexCfg(db kv.RwDB, prune prune.Mode, tmpDir string, noPruneContracts map[libcommon.Address]bool) LogIndexCfg {
+func StageLogIndexCfg(db kv.RwDB, prune prune.Mode, tmpDir string, noPruneContracts map[libcommon.Address]bool, agg *libstate.Aggregator) LogIndexCfg {
 	return LogIndexCfg{
 		db:               db,
 		prune:            prune,
@@ -48,6 +53,7 @@ func StageLogIndexCfg(db kv.RwDB, prune prune.Mode, tmpDir string, noPruneContra
 		flushEvery:       bitmapsFlushEvery,
 		tmpdir:           tmpDir,
 		noPruneContracts: noPruneContracts,
+		agg:              agg,
 	}
 }
 
@@ -90,9 +96,17 @@ func SpawnLogIndex(s *StageState, tx kv.RwTx, cfg LogIndexCfg, ctx context.Conte
 	if startBlock > 0 {
 		startBlock++
 	}
+	if err = markLogAddressTopicIndexFrom(tx, startBlock); err != nil {
+		return err
+	}
 	if err = promoteLogIndex(logPrefix, tx, startBlock, endBlock, pruneTo, cfg, ctx, logger); err != nil {
 		return err
 	}
+	if cfg.agg != nil {
+		if err = freezeLogAddrTopics(logPrefix, tx, endBlock, cfg.agg, ctx, logger); err != nil {
+			return err
+		}
+	}
 	if err = s.Update(tx, endBlock); err != nil {
 		return err
 	}
@@ -106,7 +120,109 @@ func SpawnLogIndex(s *StageState, tx kv.RwTx, cfg LogIndexCfg, ctx context.Conte
 	return nil
 }
 
-// Add the topics and address index for logs, if not in prune range or addr in noPruneContracts
+// markLogAddressTopicIndexFrom records the first block of the address+topic0 index: it's built from the first run
+// with it (or from genesis on a full re-index), older blocks keep the basic index
+func markLogAddressTopicIndexFrom(tx kv.RwTx, startBlock uint64) error {
+	if _, ok, err := rawdb.ReadLogAddressTopicIndexFrom(tx); err != nil {
+		return err
+	} else if ok && startBlock > 0 {
+		return nil
+	}
+	return rawdb.WriteLogAddressTopicIndexFrom(tx, startBlock)
+}
+
+// freezeLogAddrTopics moves old steps of the address+topic0 index from the DB into files of the aggregator, the last
+// complete step is kept in the DB for unwinds. Files are built by steps of blocks, like history v3 builds them by
+// steps of txNums.
+func freezeLogAddrTopics(logPrefix string, tx kv.RwTx, endBlock uint64, agg *libstate.Aggregator, ctx context.Context, logger log.Logger) error {
+	stepSize := agg.StepSize()
+	for step := agg.LogAddrTopicsFrozenTo() / stepSize; (step+2)*stepSize <= endBlock+1; step++ {
+		from, to := step*stepSize, (step+1)*stepSize
+		bitmaps, err := collateLogAddrTopics(tx, from, to, ctx)
+		if err != nil {
+			return err
+		}
+		err = agg.FreezeLogAddrTopics(ctx, step, bitmaps)
+		for _, bm := range bitmaps {
+			bitmapdb.ReturnToPool64(bm)
+		}
+		if err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("[%s] Frozen address+topic0 log index", logPrefix), "blocks", fmt.Sprintf("%d-%d", from, to), "keys", len(bitmaps))
+	}
+	return nil
+}
+
+// collateLogAddrTopics removes blocks [from, to) from LogAddressTopicIndex and returns them by key
+func collateLogAddrTopics(tx kv.RwTx, from, to uint64, ctx context.Context) (map[string]*roaring64.Bitmap, error) {
+	bitmaps := map[string]*roaring64.Bitmap{}
+	// chunks are rewritten after the walk: their key is the last block of the chunk
+	var deleted, changed [][]byte
+	var changedVals [][]byte
+	if err := tx.ForEach(kv.LogAddressTopicIndex, nil, func(k, v []byte) error {
+		if err := libcommon.Stopped(ctx.Done()); err != nil {
+			return err
+		}
+		key, chunkTo := k[:len(k)-4], binary.BigEndian.Uint32(k[len(k)-4:])
+		if uint64(chunkTo) < from {
+			return nil
+		}
+		chunk := roaring.New()
+		if _, err := chunk.ReadFrom(bytes.NewReader(v)); err != nil {
+			return err
+		}
+		if chunk.Minimum() >= uint32(to) || uint64(chunk.Maximum()) < from {
+			return nil
+		}
+		bm, ok := bitmaps[string(key)]
+		if !ok {
+			bm = bitmapdb.NewBitmap64()
+			bitmaps[string(key)] = bm
+		}
+		for it := chunk.Iterator(); it.HasNext(); {
+			if n := uint64(it.Next()); n >= from && n < to {
+				bm.Add(n)
+			}
+		}
+		chunk.RemoveRange(from, to)
+		deleted = append(deleted, libcommon.Copy(k))
+		if chunk.IsEmpty() {
+			return nil
+		}
+		newK := libcommon.Copy(k)
+		if chunkTo != math.MaxUint32 {
+			binary.BigEndian.PutUint32(newK[len(key):], chunk.Maximum())
+		}
+		newV, err := chunk.ToBytes()
+		if err != nil {
+			return err
+		}
+		changed, changedVals = append(changed, newK), append(changedVals, newV)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, k := range deleted {
+		if err := tx.Delete(kv.LogAddressTopicIndex, k); err != nil {
+			return nil, err
+		}
+	}
+	for i, k := range changed {
+		if err := tx.Put(kv.LogAddressTopicIndex, k, changedVals[i]); err != nil {
+			return nil, err
+		}
+	}
+	return bitmaps, nil
+}
+
+// IndexLogsRange builds the topics, address and address+topic0 index for logs of blocks [from, to] outside of
+// the stage loop. It's used for history written directly to the DB, like imported pre-Bedrock blocks.
+func IndexLogsRange(logPrefix string, tx kv.RwTx, from, to uint64, cfg LogIndexCfg, ctx context.Context, logger log.Logger) error {
+	return promoteLogIndex(logPrefix, tx, from, to, 0, cfg, ctx, logger)
+}
+
+// Add the topics, address and address+topic0 index for logs, if not in prune range or addr in noPruneContracts
 func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, endBlock uint64, pruneBlock uint64, cfg LogIndexCfg, ctx context.Context, logger log.Logger) error {
 	quit := ctx.Done()
 	logEvery := time.NewTicker(30 * time.Second)
@@ -114,6 +230,7 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, endBlock uint64
 
 	topics := map[string]*roaring.Bitmap{}
 	addresses := map[string]*roaring.Bitmap{}
+	addrTopics := map[string]*roaring.Bitmap{}
 	logs, err := tx.Cursor(kv.Log)
 	if err != nil {
 		return err
@@ -126,6 +243,8 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, endBlock uint64
 	defer collectorTopics.Close()
 	collectorAddrs := etl.NewCollector(logPrefix, cfg.tmpdir, etl.NewSortableBuffer(etl.BufferOptimalSize), logger)
 	defer collectorAddrs.Close()
+	collectorAddrTopics := etl.NewCollector(logPrefix, cfg.tmpdir, etl.NewSortableBuffer(etl.BufferOptimalSize), logger)
+	defer collectorAddrTopics.Close()
 
 	reader := bytes.NewReader(nil)
 
@@ -170,6 +289,13 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, endBlock uint64
 				}
 				addresses = map[string]*roaring.Bitmap{}
 			}
+
+			if needFlush(addrTopics, cfg.bufLimit) {
+				if err := flushBitmaps(collectorAddrTopics, addrTopics); err != nil {
+					return err
+				}
+				addrTopics = map[string]*roaring.Bitmap{}
+			}
 		}
 
 		var ll types.Logs
@@ -215,6 +341,16 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, endBlock uint64
 				addresses[accStr] = m
 			}
 			m.Add(uint32(blockNum))
+
+			if len(l.Topics) > 0 {
+				accTopicStr := accStr + string(l.Topics[0].Bytes())
+				m, ok := addrTopics[accTopicStr]
+				if !ok {
+					m = roaring.New()
+					addrTopics[accTopicStr] = m
+				}
+				m.Add(uint32(blockNum))
+			}
 		}
 	}
 
@@ -224,6 +360,9 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, endBlock uint64
 	if err := flushBitmaps(collectorAddrs, addresses); err != nil {
 		return err
 	}
+	if err := flushBitmaps(collectorAddrTopics, addrTopics); err != nil {
+		return err
+	}
 
 	var currentBitmap = roaring.New()
 	var buf = bytes.NewBuffer(nil)
@@ -267,6 +406,10 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, endBlock uint64
 		return err
 	}
 
+	if err := collectorAddrTopics.Load(tx, kv.LogAddressTopicIndex, loaderFunc, etl.TransformArgs{Quit: quit}); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -300,6 +443,7 @@ func UnwindLogIndex(u *UnwindState, s *StageState, tx kv.RwTx, cfg LogIndexCfg,
 func unwindLogIndex(logPrefix string, db kv.RwTx, to uint64, cfg LogIndexCfg, quitCh <-chan struct{}) error {
 	topics := map[string]struct{}{}
 	addrs := map[string]struct{}{}
+	addrTopics := map[string]struct{}{}
 
 	reader := bytes.NewReader(nil)
 	c, err := db.Cursor(kv.Log)
@@ -326,6 +470,9 @@ func unwindLogIndex(logPrefix string, db kv.RwTx, to uint64, cfg LogIndexCfg, qu
 				topics[string(topic.Bytes())] = struct{}{}
 			}
 			addrs[string(l.Address.Bytes())] = struct{}{}
+			if len(l.Topics) > 0 {
+				addrTopics[string(l.Address.Bytes())+string(l.Topics[0].Bytes())] = struct{}{}
+			}
 		}
 	}
 
@@ -335,6 +482,9 @@ func unwindLogIndex(logPrefix string, db kv.RwTx, to uint64, cfg LogIndexCfg, qu
 	if err := truncateBitmaps(db, kv.LogAddressIndex, addrs, to); err != nil {
 		return err
 	}
+	if err := truncateBitmaps(db, kv.LogAddressTopicIndex, addrTopics, to); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -379,7 +529,9 @@ func truncateBitmaps(tx kv.RwTx, bucket string, inMem map[string]struct{}, to ui
 	return nil
 }
 
-func pruneOldLogChunks(tx kv.RwTx, bucket string, inMem *etl.Collector, pruneTo uint64, ctx context.Context) error {
+// pruneOldLogChunks deletes the chunks of the collected keys which end before pruneTo. Blocks below keepBelow are
+// kept: chunks which end before it are left as is, chunks which start before it are truncated to these blocks.
+func pruneOldLogChunks(tx kv.RwTx, bucket string, inMem *etl.Collector, keepBelow, pruneTo uint64, ctx context.Context) error {
 	logEvery := time.NewTicker(logInterval)
 	defer logEvery.Stop()
 
@@ -390,7 +542,7 @@ func pruneOldLogChunks(tx kv.RwTx, bucket string, inMem *etl.Collector, pruneTo
 	defer c.Close()
 
 	if err := inMem.Load(tx, bucket, func(key, v []byte, table etl.CurrentTableReader, next etl.LoadNextFunc) error {
-		for k, _, err := c.Seek(key); k != nil; k, _, err = c.Next() {
+		for k, v, err := c.Seek(key); k != nil; k, v, err = c.Next() {
 			if err != nil {
 				return err
 			}
@@ -400,6 +552,32 @@ func pruneOldLogChunks(tx kv.RwTx, bucket string, inMem *etl.Collector, pruneTo
 			if !bytes.HasPrefix(k, key) || blockNum >= pruneTo {
 				break
 			}
+			if blockNum < keepBelow {
+				continue
+			}
+			if keepBelow > 0 {
+				chunk := roaring.New()
+				if _, err := chunk.ReadFrom(bytes.NewReader(v)); err != nil {
+					return err
+				}
+				if uint64(chunk.Minimum()) < keepBelow {
+					chunk.RemoveRange(keepBelow, blockNum+1)
+					newK := make([]byte, len(key)+4)
+					copy(newK, key)
+					binary.BigEndian.PutUint32(newK[len(key):], chunk.Maximum())
+					newV, err := chunk.ToBytes()
+					if err != nil {
+						return err
+					}
+					if err = c.DeleteCurrent(); err != nil {
+						return fmt.Errorf("failed delete log/index, bucket=%v block=%d: %w", bucket, blockNum, err)
+					}
+					if err = c.Put(newK, newV); err != nil {
+						return err
+					}
+					continue
+				}
+			}
 
 			if err = c.DeleteCurrent(); err != nil {
 				return fmt.Errorf("failed delete log/index, bucket=%v block=%d: %w", bucket, blockNum, err)
@@ -414,6 +592,16 @@ func pruneOldLogChunks(tx kv.RwTx, bucket string, inMem *etl.Collector, pruneTo
 	return nil
 }
 
+// legacyReceiptsPruneFrom returns the first block whose receipts may be pruned. Pre-Bedrock receipts imported
+// by `erigon import-legacy` can't be re-generated by execution, they are never pruned.
+func legacyReceiptsPruneFrom(tx kv.Getter) (uint64, error) {
+	_, to, ok, err := rawdb.ReadLegacyReceiptsRange(tx)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return to + 1, nil
+}
+
 // Call pruneLogIndex with the current sync progresses and commit the data to db
 func PruneLogIndex(s *PruneState, tx kv.RwTx, cfg LogIndexCfg, ctx context.Context, logger log.Logger) (err error) {
 	if !cfg.prune.Receipts.Enabled() {
@@ -456,6 +644,15 @@ func pruneLogIndex(logPrefix string, tx kv.RwTx, tmpDir string, pruneFrom, prune
 	defer topics.Close()
 	addrs := etl.NewCollector(logPrefix, tmpDir, etl.NewOldestEntryBuffer(bufferSize), logger)
 	defer addrs.Close()
+	addrTopics := etl.NewCollector(logPrefix, tmpDir, etl.NewOldestEntryBuffer(bufferSize), logger)
+	defer addrTopics.Close()
+
+	// logs of receipts imported by `erigon import-legacy` can't be re-generated, they are kept
+	keepBelow, err := legacyReceiptsPruneFrom(tx)
+	if err != nil {
+		return err
+	}
+	pruneFrom = max(pruneFrom, keepBelow)
 
 	reader := bytes.NewReader(nil)
 	{
@@ -507,6 +704,11 @@ func pruneLogIndex(logPrefix string, tx kv.RwTx, tmpDir string, pruneFrom, prune
 					if err := addrs.Collect(l.Address.Bytes(), nil); err != nil {
 						return err
 					}
+					if len(l.Topics) > 0 {
+						if err := addrTopics.Collect(append(l.Address.Bytes(), l.Topics[0].Bytes()...), nil); err != nil {
+							return err
+						}
+					}
 				}
 				if err := tx.Delete(kv.Log, k); err != nil {
 					return err
@@ -515,10 +717,13 @@ func pruneLogIndex(logPrefix string, tx kv.RwTx, tmpDir string, pruneFrom, prune
 		}
 	}
 
-	if err := pruneOldLogChunks(tx, kv.LogTopicIndex, topics, pruneTo, ctx); err != nil {
+	if err := pruneOldLogChunks(tx, kv.LogTopicIndex, topics, keepBelow, pruneTo, ctx); err != nil {
+		return err
+	}
+	if err := pruneOldLogChunks(tx, kv.LogAddressIndex, addrs, keepBelow, pruneTo, ctx); err != nil {
 		return err
 	}
-	if err := pruneOldLogChunks(tx, kv.LogAddressIndex, addrs, pruneTo, ctx); err != nil {
+	if err := pruneOldLogChunks(tx, kv.LogAddressTopicIndex, addrTopics, keepBelow, pruneTo, ctx); err != nil {
 		return err
 	}
 	return nil
@@ -6,11 +6,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/RoaringBitmap/roaring"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/etl"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/bitmapdb"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon/core/rawdb"
@@ -99,7 +103,7 @@ func TestPromoteLogIndex(t *testing.T) {
 
 	expectAddrs, expectTopics := genReceipts(t, tx, 100)
 
-	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "", nil)
+	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "", nil, nil)
 	cfgCopy := cfg
 	cfgCopy.bufLimit = 10
 	cfgCopy.flushEvery = time.Nanosecond
@@ -118,6 +122,13 @@ func TestPromoteLogIndex(t *testing.T) {
 		require.NoError(err)
 		require.Equal(expect, m.GetCardinality())
 	}
+	// address+topic0 pairs of genReceipts: blocks i%3==0 have {1}+{1} and {1}+{2}, blocks i%3==1 have {2}+{2} and {3}+{2}
+	for pair, expect := range map[[2]byte]uint64{{1, 1}: 34, {1, 2}: 34, {2, 2}: 33, {3, 2}: 33, {1, 3}: 0, {2, 1}: 0} {
+		addr, topic := libcommon.Address{pair[0]}, libcommon.Hash{pair[1]}
+		m, err := bitmapdb.Get(tx, kv.LogAddressTopicIndex, append(addr[:], topic[:]...), 0, 10_000_000)
+		require.NoError(err)
+		require.Equal(expect, m.GetCardinality())
+	}
 }
 
 func TestPruneLogIndex(t *testing.T) {
@@ -127,7 +138,7 @@ func TestPruneLogIndex(t *testing.T) {
 
 	_, _ = genReceipts(t, tx, 90)
 
-	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "", nil)
+	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "", nil, nil)
 	cfgCopy := cfg
 	cfgCopy.bufLimit = 10
 	cfgCopy.flushEvery = time.Nanosecond
@@ -169,6 +180,65 @@ func TestPruneLogIndex(t *testing.T) {
 	}
 }
 
+func TestPruneLogIndexKeepsLegacyReceipts(t *testing.T) {
+	logger := log.New()
+	require, tmpDir, ctx := require.New(t), t.TempDir(), context.Background()
+	_, tx := memdb.NewTestTx(t)
+
+	_, _ = genReceipts(t, tx, 90)
+	// receipts of blocks 1-29 are imported pre-Bedrock receipts
+	require.NoError(rawdb.WriteLegacyReceiptsRange(tx, 1, 29))
+
+	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "", nil, nil)
+	cfgCopy := cfg
+	cfgCopy.bufLimit = 10
+	cfgCopy.flushEvery = time.Nanosecond
+	require.NoError(promoteLogIndex("logPrefix", tx, 0, 0, 0, cfgCopy, ctx, logger))
+
+	countLogs := func(from, to uint64) int {
+		total := 0
+		require.NoError(tx.ForEach(kv.Log, nil, func(k, v []byte) error {
+			if n := binary.BigEndian.Uint64(k); n >= from && n < to {
+				total++
+			}
+			return nil
+		}))
+		return total
+	}
+	legacy := countLogs(0, 30)
+	require.NotZero(legacy)
+
+	require.NoError(pruneLogIndex("", tx, tmpDir, 0, 45, ctx, logger, nil))
+	require.Equal(legacy, countLogs(0, 30))
+	require.Zero(countLogs(30, 45))
+	require.NotZero(countLogs(45, 90))
+
+	// a chunk ending in the pruned range keeps its legacy blocks only
+	topic := libcommon.Hash{0x42}
+	chunkKey := func(n uint32) []byte {
+		k := append(libcommon.Copy(topic[:]), 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(k[length.Hash:], n)
+		return k
+	}
+	chunk, err := roaring.BitmapOf(10, 35, 40).ToBytes()
+	require.NoError(err)
+	require.NoError(tx.Put(kv.LogTopicIndex, chunkKey(40), chunk))
+	collector := etl.NewCollector("", tmpDir, etl.NewOldestEntryBuffer(etl.BufferOptimalSize), logger)
+	defer collector.Close()
+	require.NoError(collector.Collect(topic[:], nil))
+	require.NoError(pruneOldLogChunks(tx, kv.LogTopicIndex, collector, 30, 45, ctx))
+
+	v, err := tx.GetOne(kv.LogTopicIndex, chunkKey(40))
+	require.NoError(err)
+	require.Nil(v)
+	v, err = tx.GetOne(kv.LogTopicIndex, chunkKey(10))
+	require.NoError(err)
+	m := roaring.New()
+	_, err = m.FromBuffer(v)
+	require.NoError(err)
+	require.Equal([]uint32{10}, m.ToArray())
+}
+
 func TestUnwindLogIndex(t *testing.T) {
 	logger := log.New()
 	require, tmpDir, ctx := require.New(t), t.TempDir(), context.Background()
@@ -176,7 +246,7 @@ func TestUnwindLogIndex(t *testing.T) {
 
 	expectAddrs, expectTopics := genReceipts(t, tx, 100)
 
-	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "", nil)
+	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "", nil, nil)
 	cfgCopy := cfg
 	cfgCopy.bufLimit = 10
 	cfgCopy.flushEvery = time.Nanosecond
@@ -201,4 +271,51 @@ func TestUnwindLogIndex(t *testing.T) {
 		require.NoError(err)
 		require.True(m.Maximum() <= 700)
 	}
+	for addr := range expectAddrs {
+		for topic := range expectTopics {
+			m, err := bitmapdb.Get(tx, kv.LogAddressTopicIndex, append(addr[:], topic[:]...), 0, 10_000_000)
+			require.NoError(err)
+			require.True(m.IsEmpty() || m.Maximum() <= 70)
+		}
+	}
+}
+
+func TestFreezeLogAddrTopics(t *testing.T) {
+	logger := log.New()
+	require, ctx := require.New(t), context.Background()
+	db, tx := memdb.NewTestTx(t)
+
+	genReceipts(t, tx, 100)
+	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "", nil, nil)
+	err := promoteLogIndex("logPrefix", tx, 0, 0, 0, cfg, ctx, logger)
+	require.NoError(err)
+
+	agg, err := libstate.NewAggregator(ctx, t.TempDir(), t.TempDir(), 16, db, logger)
+	require.NoError(err)
+	defer agg.Close()
+
+	// steps of 16 blocks, the last complete step [80, 96) stays in the DB
+	err = freezeLogAddrTopics("logPrefix", tx, 99, agg, ctx, logger)
+	require.NoError(err)
+	require.Equal(uint64(80), agg.LogAddrTopicsFrozenTo())
+
+	ac := agg.BeginFilesRo()
+	defer ac.Close()
+	for pair, expect := range map[[2]byte]uint64{{1, 1}: 34, {1, 2}: 34, {2, 2}: 33, {3, 2}: 33, {1, 3}: 0, {2, 1}: 0} {
+		addr, topic := libcommon.Address{pair[0]}, libcommon.Hash{pair[1]}
+		key := append(addr[:], topic[:]...)
+		m, err := bitmapdb.Get(tx, kv.LogAddressTopicIndex, key, 0, 10_000_000)
+		require.NoError(err)
+		require.True(m.IsEmpty() || m.Minimum() >= 80)
+		it, err := ac.IndexRange(kv.LogAddrTopicIdx, key, 0, 100, order.Asc, kv.Unlim, tx)
+		require.NoError(err)
+		for it.HasNext() {
+			blockNum, err := it.Next()
+			require.NoError(err)
+			require.Less(blockNum, uint64(80))
+			require.False(m.Contains(uint32(blockNum)))
+			m.Add(uint32(blockNum))
+		}
+		require.Equal(expect, m.GetCardinality())
+	}
 }
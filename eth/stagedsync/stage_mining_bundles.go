@@ -0,0 +1,97 @@
+package stagedsync
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
+
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// miningBundle is a bundle of the txpool decoded for the block being built
+type miningBundle struct {
+	hash      libcommon.Hash
+	txs       []types.Transaction
+	reverting map[[32]byte]struct{}
+	tip       *uint256.Int // effective tip of the transactions, weighted by their gas limits
+}
+
+func (b *miningBundle) canRevert(txn types.Transaction) bool {
+	_, ok := b.reverting[[32]byte(txn.Hash())]
+	return ok
+}
+
+// miningBundles are the bundles which may be included in the block being built, best tip first
+type miningBundles struct {
+	queue []*miningBundle
+	// drain is set once the txpool ran dry, the remaining bundles don't compete with single transactions anymore
+	drain bool
+}
+
+func newMiningBundles(bundles []*types2.Bundle, chainID *uint256.Int, baseFee *big.Int, logger log.Logger) *miningBundles {
+	var baseFee256 *uint256.Int
+	if baseFee != nil {
+		baseFee256, _ = uint256.FromBig(baseFee)
+	}
+	res := &miningBundles{}
+BUNDLES:
+	for _, bundle := range bundles {
+		b := &miningBundle{hash: bundle.Hash, reverting: bundle.RevertingTxs, tip: new(uint256.Int)}
+		gas := new(uint256.Int)
+		for i, rlpTx := range bundle.Txs {
+			txn, err := types.DecodeWrappedTransaction(rlpTx)
+			if err != nil {
+				logger.Debug("Skipping bundle with a bad transaction", "hash", libcommon.Hash(bundle.Hash), "err", err)
+				continue BUNDLES
+			}
+			if !txn.GetChainID().IsZero() && txn.GetChainID().Cmp(chainID) != 0 {
+				continue BUNDLES
+			}
+			txn.SetSender(bundle.Senders.AddressAt(i))
+			b.txs = append(b.txs, txn)
+			txGas := uint256.NewInt(txn.GetGas())
+			gas.Add(gas, txGas)
+			b.tip.Add(b.tip, txGas.Mul(txGas, txn.GetEffectiveGasTip(baseFee256)))
+		}
+		if !gas.IsZero() {
+			b.tip.Div(b.tip, gas)
+		}
+		res.queue = append(res.queue, b)
+	}
+	sort.Slice(res.queue, func(i, j int) bool {
+		if c := res.queue[i].tip.Cmp(res.queue[j].tip); c != 0 {
+			return c > 0
+		}
+		return bytes.Compare(res.queue[i].hash[:], res.queue[j].hash[:]) < 0
+	})
+	return res
+}
+
+func (mb *miningBundles) Len() int {
+	if mb == nil {
+		return 0
+	}
+	return len(mb.queue)
+}
+
+// next pops the best bundle when its tip isn't below the tip of the next single transaction
+func (mb *miningBundles) next(txn types.Transaction, baseFee *uint256.Int) *miningBundle {
+	if mb.Len() == 0 {
+		return nil
+	}
+	if txn == nil && !mb.drain {
+		return nil
+	}
+	if txn != nil && mb.queue[0].tip.Lt(txn.GetEffectiveGasTip(baseFee)) {
+		return nil
+	}
+	bundle := mb.queue[0]
+	mb.queue = mb.queue[1:]
+	return bundle
+}
@@ -0,0 +1,120 @@
+package stagedsync
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
+
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+func TestMiningBundles(t *testing.T) {
+	require := require.New(t)
+	logger := log.New()
+	chainConfig := *params.TestChainConfig
+	chainConfig.LondonBlock = big.NewInt(0)
+	chainID, _ := uint256.FromBig(chainConfig.ChainID)
+	signer := types.LatestSignerForChainID(chainConfig.ChainID)
+
+	tx := memdb.BeginRw(t, memdb.NewTestDB(t))
+	ibs := state.New(state.NewPlainStateReader(tx))
+	var keys []*ecdsaKey
+	for i := 0; i < 5; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, &ecdsaKey{key: key, addr: crypto.PubkeyToAddress(key.PublicKey)})
+		ibs.AddBalance(keys[i].addr, uint256.NewInt(params.Ether))
+	}
+	// PUSH1 0 PUSH1 0 REVERT
+	reverter := libcommon.HexToAddress("0xdead")
+	ibs.SetCode(reverter, []byte{0x60, 0x00, 0x60, 0x00, 0xfd})
+	require.NoError(ibs.FinalizeTx(chainConfig.Rules(1, 0), state.NewNoopWriter()))
+
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		GasLimit:   10_000_000,
+		BaseFee:    big.NewInt(params.GWei),
+		Difficulty: big.NewInt(1),
+	}
+	newTx := func(k *ecdsaKey, nonce uint64, to libcommon.Address, tip uint64) types.Transaction {
+		txn := types.NewEIP1559Transaction(*chainID, nonce, to, uint256.NewInt(1), 100_000, nil, uint256.NewInt(tip), uint256.NewInt(10*params.GWei), nil)
+		signed, err := types.SignTx(txn, *signer, k.key)
+		require.NoError(err)
+		return signed
+	}
+	newBundle := func(txs []types.Transaction, reverting ...types.Transaction) *types2.Bundle {
+		bundle := &types2.Bundle{RevertingTxs: map[[32]byte]struct{}{}}
+		for _, txn := range txs {
+			rlp, err := types.MarshalTransactionsBinary(types.Transactions{txn})
+			require.NoError(err)
+			bundle.Txs = append(bundle.Txs, rlp[0])
+			bundle.TxHashes = append(bundle.TxHashes, txn.Hash())
+			sender, _ := txn.Sender(*signer)
+			bundle.Senders = append(bundle.Senders, sender[:]...)
+		}
+		for _, txn := range reverting {
+			bundle.RevertingTxs[txn.Hash()] = struct{}{}
+		}
+		bundle.Hash = types2.BundleHash(bundle.TxHashes)
+		return bundle
+	}
+
+	single := newTx(keys[0], 0, keys[4].addr, params.GWei)
+	better := newTx(keys[1], 0, keys[4].addr, 2*params.GWei)
+	// pays the best tip but reverts, and none of its transactions may
+	reverted := []types.Transaction{newTx(keys[2], 0, keys[4].addr, 3*params.GWei), newTx(keys[2], 1, reverter, 3*params.GWei)}
+	revertible := newTx(keys[3], 0, reverter, params.GWei/2)
+	bundles := newMiningBundles([]*types2.Bundle{
+		newBundle(types.Transactions{better}),
+		newBundle(reverted),
+		newBundle(types.Transactions{revertible}, revertible),
+	}, chainID, header.BaseFee, logger)
+	require.Equal(3, bundles.Len())
+
+	current := &MiningBlock{Header: header}
+	add := func(txs types.Transactions) {
+		_, _, err := addTransactionsToMiningBlock("mining", current, chainConfig, &vm.Config{}, func(libcommon.Hash, uint64) *types.Header { return nil },
+			ethash.NewFaker(), types.NewTransactionsFixedOrder(txs), bundles, libcommon.Address{}, ibs, nil, nil, 0, false, logger)
+		require.NoError(err)
+	}
+
+	// the bundle paying less than the single transaction waits for the txpool to run dry
+	add(types.Transactions{single})
+	require.Equal([]libcommon.Hash{better.Hash(), single.Hash()}, txHashes(current.Txs))
+	require.Zero(ibs.GetNonce(keys[2].addr))
+	require.Equal(uint64(params.Ether+2), ibs.GetBalance(keys[4].addr).Uint64())
+	require.Equal(current.Receipts[1].CumulativeGasUsed, header.GasUsed)
+	require.Equal(1, bundles.Len())
+
+	bundles.drain = true
+	add(nil)
+	require.Equal([]libcommon.Hash{better.Hash(), single.Hash(), revertible.Hash()}, txHashes(current.Txs))
+	require.Equal(types.ReceiptStatusFailed, current.Receipts[2].Status)
+	require.Equal(uint64(1), ibs.GetNonce(keys[3].addr))
+	require.Zero(bundles.Len())
+}
+
+type ecdsaKey struct {
+	key  *ecdsa.PrivateKey
+	addr libcommon.Address
+}
+
+func txHashes(txs types.Transactions) []libcommon.Hash {
+	res := make([]libcommon.Hash, len(txs))
+	for i, txn := range txs {
+		res[i] = txn.Hash()
+	}
+	return res
+}
@@ -55,6 +55,7 @@ type MiningExecCfg struct {
 
 type TxPoolForMining interface {
 	YieldBest(n uint16, txs *types2.TxsRlp, tx kv.Tx, onTopOf, availableGas, availableBlobGas uint64, toSkip mapset.Set[[32]byte]) (bool, int, error)
+	Bundles(blockNum, timestamp uint64) []*types2.Bundle
 }
 
 func StageMiningExecCfg(
@@ -119,14 +120,14 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 			// forceTxs is sent by Optimism consensus client, and all force txs must be included in the payload.
 			// Therefore, interrupts to block building must not be handled while force txs are being processed.
 			// So do not pass cfg.interrupt
-			logs, _, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, forceTxs, cfg.miningState.MiningConfig.Etherbase, ibs, quit, nil, cfg.payloadId, true, logger)
+			logs, _, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, forceTxs, nil, cfg.miningState.MiningConfig.Etherbase, ibs, quit, nil, cfg.payloadId, true, logger)
 			if err != nil {
 				return err
 			}
 			NotifyPendingLogs(logPrefix, cfg.notifier, logs, logger)
 		}
 		if txs != nil && !txs.Empty() {
-			logs, _, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, txs, cfg.miningState.MiningConfig.Etherbase, ibs, quit, cfg.interrupt, cfg.payloadId, false, logger)
+			logs, _, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, txs, nil, cfg.miningState.MiningConfig.Etherbase, ibs, quit, cfg.interrupt, cfg.payloadId, false, logger)
 			if err != nil {
 				return err
 			}
@@ -144,14 +145,18 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 				return err
 			}
 
+			bundles := newMiningBundles(cfg.txPool.Bundles(current.Header.Number.Uint64(), current.Header.Time), chainID, current.Header.BaseFee, logger)
 			for {
 				txs, y, err := getNextTransactions(cfg, chainID, current.Header, 50, executionAt, simulationTx, yielded, logger)
 				if err != nil {
 					return err
 				}
 
-				if !txs.Empty() {
-					logs, stop, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, txs, cfg.miningState.MiningConfig.Etherbase, ibs, quit, cfg.interrupt, cfg.payloadId, false, logger)
+				// if we yielded less than the count we wanted, assume the txpool has run dry now and stop to save another loop
+				last := txs.Empty() || y < 50
+				bundles.drain = last
+				if !txs.Empty() || bundles.Len() > 0 {
+					logs, stop, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, txs, bundles, cfg.miningState.MiningConfig.Etherbase, ibs, quit, cfg.interrupt, cfg.payloadId, false, logger)
 					if err != nil {
 						return err
 					}
@@ -159,12 +164,8 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 					if stop {
 						break
 					}
-				} else {
-					break
 				}
-
-				// if we yielded less than the count we wanted, assume the txpool has run dry now and stop to save another loop
-				if y < 50 {
+				if last {
 					break
 				}
 			}
@@ -378,7 +379,7 @@ func filterBadTransactions(transactions []types.Transaction, config chain.Config
 }
 
 func addTransactionsToMiningBlock(logPrefix string, current *MiningBlock, chainConfig chain.Config, vmConfig *vm.Config, getHeader func(hash libcommon.Hash, number uint64) *types.Header,
-	engine consensus.Engine, txs types.TransactionsStream, coinbase libcommon.Address, ibs *state.IntraBlockState, quit <-chan struct{},
+	engine consensus.Engine, txs types.TransactionsStream, bundles *miningBundles, coinbase libcommon.Address, ibs *state.IntraBlockState, quit <-chan struct{},
 	interrupt *int32, payloadId uint64, allowDeposits bool, logger log.Logger) (types.Logs, bool, error) {
 	header := current.Header
 	tcount := 0
@@ -387,6 +388,10 @@ func addTransactionsToMiningBlock(logPrefix string, current *MiningBlock, chainC
 		gasPool.AddBlobGas(chainConfig.GetMaxBlobGasPerBlock() - *header.BlobGasUsed)
 	}
 	signer := types.MakeSigner(&chainConfig, header.Number.Uint64(), header.Time)
+	var baseFee *uint256.Int
+	if header.BaseFee != nil {
+		baseFee, _ = uint256.FromBig(header.BaseFee)
+	}
 
 	var coalescedLogs types.Logs
 	noop := state.NewNoopWriter()
@@ -408,6 +413,52 @@ func addTransactionsToMiningBlock(logPrefix string, current *MiningBlock, chainC
 		return receipt.Logs, nil
 	}
 
+	// miningCommitBundle applies either all the transactions of the bundle or none of them, they are final once
+	// FinalizeTx is called
+	var miningCommitBundle = func(bundle *miningBundle, coinbase libcommon.Address, vmConfig *vm.Config, chainConfig chain.Config, ibs *state.IntraBlockState, current *MiningBlock) ([]*types.Log, error) {
+		gasSnap := gasPool.Gas()
+		blobGasSnap := gasPool.BlobGas()
+		gasUsedSnap := header.GasUsed
+		var blobGasUsedSnap uint64
+		if header.BlobGasUsed != nil {
+			blobGasUsedSnap = *header.BlobGasUsed
+		}
+		txsSnap := len(current.Txs)
+		snap := ibs.Snapshot()
+		revert := func() {
+			ibs.RevertToSnapshot(snap)
+			gasPool = new(core.GasPool).AddGas(gasSnap).AddBlobGas(blobGasSnap) // restore gasPool as well as ibs
+			header.GasUsed = gasUsedSnap
+			if header.BlobGasUsed != nil {
+				*header.BlobGasUsed = blobGasUsedSnap
+			}
+			current.Txs = current.Txs[:txsSnap]
+			current.Receipts = current.Receipts[:txsSnap]
+		}
+
+		var logs []*types.Log
+		for i, txn := range bundle.txs {
+			if txn.Type() == types.DepositTxType {
+				revert()
+				return nil, fmt.Errorf("deposit transaction %x", txn.Hash())
+			}
+			ibs.SetTxContext(txn.Hash(), libcommon.Hash{}, tcount+i)
+			receipt, _, err := core.ApplyBundleTransaction(&chainConfig, core.GetHashFn(header, getHeader), engine, &coinbase, gasPool, ibs, header, txn, &header.GasUsed, header.BlobGasUsed, *vmConfig)
+			if err != nil {
+				revert()
+				return nil, fmt.Errorf("transaction %x: %w", txn.Hash(), err)
+			}
+			current.Txs = append(current.Txs, txn)
+			current.Receipts = append(current.Receipts, receipt)
+			if receipt.Status == types.ReceiptStatusFailed && !bundle.canRevert(txn) {
+				revert()
+				return nil, fmt.Errorf("transaction %x reverted", txn.Hash())
+			}
+			logs = append(logs, receipt.Logs...)
+		}
+		return logs, nil
+	}
+
 	var stopped *time.Ticker
 	defer func() {
 		if stopped != nil {
@@ -444,8 +495,22 @@ LOOP:
 			done = true
 			break
 		}
-		// Retrieve the next transaction and abort if all done
+		// Retrieve the next transaction, or the next bundle when it pays a better tip, and abort if all done
 		txn := txs.Peek()
+		if bundle := bundles.next(txn, baseFee); bundle != nil {
+			logs, err := miningCommitBundle(bundle, coinbase, vmConfig, chainConfig, ibs, current)
+			if err != nil {
+				logger.Debug(fmt.Sprintf("[%s] Skipping bundle", logPrefix), "hash", bundle.hash, "err", err)
+				continue
+			}
+			if err := ibs.FinalizeTx(chainConfig.Rules(header.Number.Uint64(), header.Time), noop); err != nil {
+				return nil, true, err
+			}
+			coalescedLogs = append(coalescedLogs, logs...)
+			tcount += len(bundle.txs)
+			logger.Trace(fmt.Sprintf("[%s] Added bundle", logPrefix), "hash", bundle.hash, "txs", len(bundle.txs), "payload", payloadId)
+			continue
+		}
 		if txn == nil {
 			break
 		}
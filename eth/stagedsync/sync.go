@@ -13,6 +13,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/wrap"
 
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 )
@@ -239,6 +240,9 @@ func (s *Sync) RunUnwind(db kv.RwDB, txc wrap.TxContainer) error {
 	if s.unwindPoint == nil {
 		return nil
 	}
+	if err := s.recordBadBlockUnwind(db, txc); err != nil {
+		return err
+	}
 	for j := 0; j < len(s.unwindOrder); j++ {
 		if s.unwindOrder[j] == nil || s.unwindOrder[j].Disabled || s.unwindOrder[j].Unwind == nil {
 			continue
@@ -256,6 +260,20 @@ func (s *Sync) RunUnwind(db kv.RwDB, txc wrap.TxContainer) error {
 	return nil
 }
 
+// recordBadBlockUnwind adds the unwind caused by an invalid block to the reorg log
+func (s *Sync) recordBadBlockUnwind(db kv.RwDB, txc wrap.TxContainer) error {
+	if !s.unwindReason.IsBadBlock() {
+		return nil
+	}
+	record := func(tx kv.RwTx) error {
+		return RecordReorg(tx, types.ReorgTriggerBadBlock, *s.unwindPoint, nil)
+	}
+	if txc.Tx != nil {
+		return record(txc.Tx)
+	}
+	return db.Update(context.Background(), record)
+}
+
 func (s *Sync) RunNoInterrupt(db kv.RwDB, txc wrap.TxContainer, firstCycle bool) error {
 	s.prevUnwindPoint = nil
 	s.timings = s.timings[:0]
@@ -263,6 +281,9 @@ func (s *Sync) RunNoInterrupt(db kv.RwDB, txc wrap.TxContainer, firstCycle bool)
 	for !s.IsDone() {
 		var badBlockUnwind bool
 		if s.unwindPoint != nil {
+			if err := s.recordBadBlockUnwind(db, txc); err != nil {
+				return err
+			}
 			for j := 0; j < len(s.unwindOrder); j++ {
 				if s.unwindOrder[j] == nil || s.unwindOrder[j].Disabled || s.unwindOrder[j].Unwind == nil {
 					continue
@@ -333,6 +354,9 @@ func (s *Sync) Run(db kv.RwDB, txc wrap.TxContainer, firstCycle bool) (bool, err
 	for !s.IsDone() {
 		var badBlockUnwind bool
 		if s.unwindPoint != nil {
+			if err := s.recordBadBlockUnwind(db, txc); err != nil {
+				return false, err
+			}
 			for j := 0; j < len(s.unwindOrder); j++ {
 				if s.unwindOrder[j] == nil || s.unwindOrder[j].Disabled || s.unwindOrder[j].Unwind == nil {
 					continue
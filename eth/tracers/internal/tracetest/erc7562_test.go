@@ -0,0 +1,171 @@
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/tests"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+type erc7562Result struct {
+	CallsFromEntryPoint []struct {
+		TopLevelMethodSig     hexutility.Bytes  `json:"topLevelMethodSig"`
+		TopLevelTargetAddress libcommon.Address `json:"topLevelTargetAddress"`
+		Opcodes               map[string]uint64 `json:"opcodes"`
+		Access                map[libcommon.Address]struct {
+			Reads  map[libcommon.Hash]libcommon.Hash `json:"reads"`
+			Writes map[libcommon.Hash]uint64         `json:"writes"`
+		} `json:"access"`
+		ContractSize map[libcommon.Address]struct {
+			ContractSize int    `json:"contractSize"`
+			Opcode       string `json:"opcode"`
+		} `json:"contractSize"`
+	} `json:"callsFromEntryPoint"`
+	Keccak     []hexutility.Bytes `json:"keccak"`
+	Calls      []map[string]any   `json:"calls"`
+	Violations []struct {
+		Rule    string            `json:"rule"`
+		Entity  string            `json:"entity"`
+		Address libcommon.Address `json:"address"`
+	} `json:"violations"`
+}
+
+// TestErc7562Tracer runs an EntryPoint validating a user operation: it calls validateUserOp of the account,
+// then validatePaymasterUserOp of the paymaster.
+func TestErc7562Tracer(t *testing.T) {
+	var (
+		entryPoint = libcommon.HexToAddress("0x00000000000000000000000000000000000000e0")
+		account    = libcommon.HexToAddress("0x00000000000000000000000000000000000000a0")
+		paymaster  = libcommon.HexToAddress("0x00000000000000000000000000000000000000b0")
+		other      = libcommon.HexToAddress("0x00000000000000000000000000000000000000c0")
+	)
+	call := func(target libcommon.Address, selector ...byte) []byte {
+		code := []byte{byte(vm.PUSH4)}
+		code = append(code, selector...)
+		code = append(code, byte(vm.PUSH1), 0xe0, byte(vm.SHL), byte(vm.PUSH1), 0, byte(vm.MSTORE), // selector at memory 0
+			byte(vm.PUSH1), 0, byte(vm.DUP1), byte(vm.PUSH1), 4, byte(vm.PUSH1), 0, byte(vm.DUP1), byte(vm.PUSH20))
+		code = append(code, target[:]...)
+		return append(code, byte(vm.GAS), byte(vm.CALL), byte(vm.POP))
+	}
+	entryPointCode := append(call(account, 0x19, 0x82, 0x2f, 0x7c), call(paymaster, 0x52, 0xb7, 0x51, 0x2c)...)
+	// the account reads the timestamp, its own storage, and the storage of another contract
+	accountCode := []byte{byte(vm.TIMESTAMP), byte(vm.POP), byte(vm.PUSH1), 1, byte(vm.SLOAD), byte(vm.POP),
+		byte(vm.PUSH1), 0, byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1), byte(vm.PUSH20)}
+	accountCode = append(accountCode, other[:]...)
+	accountCode = append(accountCode, byte(vm.GAS), byte(vm.CALL), byte(vm.POP), byte(vm.STOP))
+	otherCode := []byte{byte(vm.PUSH1), 3, byte(vm.SLOAD), byte(vm.POP), byte(vm.STOP)}
+	// the paymaster reads the slot of a mapping keyed by the account, then its own storage
+	paymasterCode := []byte{byte(vm.PUSH20)}
+	paymasterCode = append(paymasterCode, account[:]...)
+	paymasterCode = append(paymasterCode, byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0x20, byte(vm.MSTORE),
+		byte(vm.PUSH1), 0x40, byte(vm.PUSH1), 0, byte(vm.KECCAK256), byte(vm.SLOAD), byte(vm.POP),
+		byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.POP), byte(vm.STOP))
+
+	run := func(entryPointCode []byte, config string) *erc7562Result {
+		privkey, err := crypto.HexToECDSA("0000000000000000deadbeef00000000000000000000000000000000deadbeef")
+		require.NoError(t, err)
+		signer := types.LatestSigner(params.MainnetChainConfig)
+		tx, err := types.SignNewTx(privkey, *signer, &types.LegacyTx{
+			GasPrice: uint256.NewInt(0),
+			CommonTx: types.CommonTx{Gas: 1_000_000, To: &entryPoint},
+		})
+		require.NoError(t, err)
+		origin, _ := signer.Sender(tx)
+		context := evmtypes.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			BlockNumber: 8000000,
+			Time:        5,
+			Difficulty:  big.NewInt(0x30000),
+			GasLimit:    uint64(6000000),
+		}
+		alloc := types.GenesisAlloc{
+			entryPoint: {Nonce: 1, Code: entryPointCode},
+			account:    {Nonce: 1, Code: accountCode, Storage: map[libcommon.Hash]libcommon.Hash{{31: 1}: {31: 0x2a}}},
+			paymaster:  {Nonce: 1, Code: paymasterCode},
+			other:      {Nonce: 1, Code: otherCode},
+			origin:     {Balance: big.NewInt(params.Ether)},
+		}
+		rules := params.MainnetChainConfig.Rules(context.BlockNumber, context.Time)
+		m := mock.Mock(t)
+		dbTx, err := m.DB.BeginRw(m.Ctx)
+		require.NoError(t, err)
+		defer dbTx.Rollback()
+		statedb, err := tests.MakePreState(rules, dbTx, alloc, context.BlockNumber)
+		require.NoError(t, err)
+
+		tracer, err := tracers.New("bundlerCollectorTracer", nil, json.RawMessage(config))
+		require.NoError(t, err)
+		evm := vm.NewEVM(context, evmtypes.TxContext{Origin: origin, GasPrice: uint256.NewInt(1)}, statedb, params.MainnetChainConfig, vm.Config{Debug: true, Tracer: tracer})
+		msg, err := tx.AsMessage(*signer, nil, rules)
+		require.NoError(t, err)
+		_, err = core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(tx.GetGas()), true /* refunds */, false /* gasBailout */)
+		require.NoError(t, err)
+		res, err := tracer.GetResult()
+		require.NoError(t, err)
+		result := new(erc7562Result)
+		require.NoError(t, json.Unmarshal(res, result))
+		return result
+	}
+
+	result := run(entryPointCode, `{}`)
+	require.Len(t, result.CallsFromEntryPoint, 2)
+	validateUserOp := result.CallsFromEntryPoint[0]
+	require.Equal(t, hexutility.Bytes{0x19, 0x82, 0x2f, 0x7c}, validateUserOp.TopLevelMethodSig)
+	require.Equal(t, account, validateUserOp.TopLevelTargetAddress)
+	// GAS followed by CALL is not counted
+	require.Equal(t, map[string]uint64{"TIMESTAMP": 1, "SLOAD": 2, "CALL": 1, "STOP": 2}, validateUserOp.Opcodes)
+	require.Equal(t, map[libcommon.Hash]libcommon.Hash{{31: 1}: {31: 0x2a}}, validateUserOp.Access[account].Reads)
+	require.Contains(t, validateUserOp.Access[other].Reads, libcommon.Hash{31: 3})
+	require.Equal(t, len(otherCode), validateUserOp.ContractSize[other].ContractSize)
+	require.Equal(t, "CALL", validateUserOp.ContractSize[other].Opcode)
+	require.Len(t, result.CallsFromEntryPoint[1].Access[paymaster].Reads, 2)
+	require.Equal(t, []hexutility.Bytes{append(libcommon.BytesToHash(account[:]).Bytes(), make([]byte, 32)...)}, result.Keccak)
+	// entering and exiting the account, the other contract and the paymaster
+	require.Len(t, result.Calls, 6)
+	require.Equal(t, "CALL", result.Calls[0]["type"])
+	require.Equal(t, "0x19822f7c", result.Calls[0]["method"])
+	require.Equal(t, "RETURN", result.Calls[5]["type"])
+	require.Empty(t, result.Violations)
+
+	// the rules are checked given the entities
+	result = run(entryPointCode, `{"sender": "0x00000000000000000000000000000000000000a0", "paymaster": "0x00000000000000000000000000000000000000b0"}`)
+	require.Len(t, result.Violations, 3)
+	require.Equal(t, "OP-011", result.Violations[0].Rule)
+	require.Equal(t, "account", result.Violations[0].Entity)
+	require.Equal(t, "STO-021", result.Violations[1].Rule)
+	require.Equal(t, account, result.Violations[1].Address)
+	// the slot associated with the account is allowed, not the paymaster's own storage
+	require.Equal(t, "STO-021", result.Violations[2].Rule)
+	require.Equal(t, "paymaster", result.Violations[2].Entity)
+
+	// a staked paymaster may access its own storage
+	result = run(entryPointCode, `{"sender": "0x00000000000000000000000000000000000000a0", "paymaster": "0x00000000000000000000000000000000000000b0", "staked": ["0x00000000000000000000000000000000000000b0"]}`)
+	require.Len(t, result.Violations, 2)
+	require.Equal(t, "account", result.Violations[1].Entity)
+
+	// without call data, the arguments offset of the call isn't bounded by the memory: reading the method
+	// selector from it must not overflow, even once the memory is large enough to hold the wrapped around end
+	hugeOffsetCall := []byte{byte(vm.PUSH1), 0, byte(vm.DUP1), byte(vm.MSTORE), byte(vm.PUSH1), 0, byte(vm.DUP1), byte(vm.PUSH1), 0,
+		byte(vm.PUSH8), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, byte(vm.PUSH1), 0, byte(vm.PUSH20)}
+	hugeOffsetCall = append(hugeOffsetCall, account[:]...)
+	hugeOffsetCall = append(hugeOffsetCall, byte(vm.GAS), byte(vm.CALL), byte(vm.POP))
+	result = run(hugeOffsetCall, `{}`)
+	require.Len(t, result.CallsFromEntryPoint, 1)
+	require.Empty(t, result.CallsFromEntryPoint[0].TopLevelMethodSig)
+	require.Equal(t, account, result.CallsFromEntryPoint[0].TopLevelTargetAddress)
+}
@@ -0,0 +1,521 @@
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+)
+
+func init() {
+	register("bundlerCollectorTracer", newErc7562Tracer)
+}
+
+// maxCallDataHex is the length the hex encoded output of a call is cut to, as the reference tracer does
+const maxCallDataHex = 4000
+
+var (
+	// beforeExecutionTopic is the topic of BeforeExecution(), the EntryPoint emits it once the validation
+	// of the user operations is over
+	beforeExecutionTopic = libcommon.HexToHash("0xbb47ee3e183a558b1a2ff0874b079f3fc5478b7454eacf2bfc5af2ff5878f972")
+
+	// the selectors of the validation calls the EntryPoint makes to each entity, v0.6 and v0.7
+	createSenderSelector             = [4]byte{0x57, 0x0e, 0x1a, 0x36} // createSender(bytes)
+	validateUserOpSelectors          = [][4]byte{{0x3a, 0x87, 0x1c, 0xdd}, {0x19, 0x82, 0x2f, 0x7c}}
+	validatePaymasterUserOpSelectors = [][4]byte{{0xf4, 0x65, 0xc7, 0x7e}, {0x52, 0xb7, 0x51, 0x2c}}
+
+	// bannedOpcodes may not be used during validation [OP-011], GAS only when not followed by a call [OP-012]
+	bannedOpcodes = map[vm.OpCode]bool{
+		vm.GASPRICE: true, vm.GASLIMIT: true, vm.DIFFICULTY: true, vm.TIMESTAMP: true, vm.BASEFEE: true,
+		vm.BLOCKHASH: true, vm.NUMBER: true, vm.ORIGIN: true, vm.COINBASE: true, vm.SELFDESTRUCT: true,
+		vm.BLOBHASH: true, vm.BLOBBASEFEE: true, vm.INVALID: true, vm.GAS: true,
+	}
+	// stakedOpcodes are only allowed for staked entities [OP-080]
+	stakedOpcodes = map[vm.OpCode]bool{vm.BALANCE: true, vm.SELFBALANCE: true}
+)
+
+// storageAccess is the storage of a contract accessed by an entity: the value of the slots read before
+// being written, and the number of accesses of the others.
+type storageAccess struct {
+	Reads           map[libcommon.Hash]libcommon.Hash `json:"reads"`
+	Writes          map[libcommon.Hash]uint64         `json:"writes"`
+	TransientReads  map[libcommon.Hash]uint64         `json:"transientReads"`
+	TransientWrites map[libcommon.Hash]uint64         `json:"transientWrites"`
+}
+
+type contractSizeInfo struct {
+	ContractSize int    `json:"contractSize"`
+	Opcode       string `json:"opcode"`
+}
+
+// topLevelCallInfo is what an entity did during one of the calls the EntryPoint made to validate
+type topLevelCallInfo struct {
+	TopLevelMethodSig     hexutility.Bytes                        `json:"topLevelMethodSig"`
+	TopLevelTargetAddress libcommon.Address                       `json:"topLevelTargetAddress"`
+	Opcodes               map[string]uint64                       `json:"opcodes"`
+	Access                map[libcommon.Address]*storageAccess    `json:"access"`
+	ContractSize          map[libcommon.Address]*contractSizeInfo `json:"contractSize"`
+	ExtCodeAccessInfo     map[libcommon.Address]string            `json:"extCodeAccessInfo"`
+	OOG                   bool                                    `json:"oog,omitempty"`
+	opcodes               map[vm.OpCode]uint64                    // the counted opcodes, for the rule checks
+}
+
+type enterCallInfo struct {
+	Type   string            `json:"type"`
+	From   libcommon.Address `json:"from"`
+	To     libcommon.Address `json:"to"`
+	Method hexutility.Bytes  `json:"method"`
+	Gas    uint64            `json:"gas"`
+	Value  *hexutil.Big      `json:"value"`
+}
+
+type exitCallInfo struct {
+	Type    string `json:"type"`
+	GasUsed uint64 `json:"gasUsed"`
+	Data    string `json:"data"`
+}
+
+type bundlerLog struct {
+	Topics []libcommon.Hash `json:"topics"`
+	Data   hexutility.Bytes `json:"data"`
+}
+
+// ruleViolation is an ERC-7562 rule broken by an entity during validation
+type ruleViolation struct {
+	Rule    string            `json:"rule"`
+	Entity  string            `json:"entity"`
+	Address libcommon.Address `json:"address"`
+	Message string            `json:"message"`
+}
+
+type erc7562TracerConfig struct {
+	// Sender, Factory and Paymaster are the entities of the validated user operation. The rules the entities
+	// break are reported when the sender is set.
+	Sender    *libcommon.Address  `json:"sender"`
+	Factory   *libcommon.Address  `json:"factory"`
+	Paymaster *libcommon.Address  `json:"paymaster"`
+	Staked    []libcommon.Address `json:"staked"`
+}
+
+type opcodeInfo struct {
+	op       vm.OpCode
+	stackTop uint256.Int
+}
+
+// erc7562Tracer collects what the entities of user operations do while the EntryPoint validates them, for
+// bundlers to enforce the ERC-7562 validation rules. It is a drop-in replacement of the JavaScript
+// bundlerCollectorTracer of the reference bundler, with the same output, and optionally checks the rules
+// itself given the entities.
+type erc7562Tracer struct {
+	noopTracer
+	env         *vm.EVM
+	config      erc7562TracerConfig
+	precompiles map[libcommon.Address]bool
+
+	callsFromEntryPoint []*topLevelCallInfo
+	currentLevel        *topLevelCallInfo
+	keccak              []hexutility.Bytes
+	calls               []interface{}
+	logs                []bundlerLog
+	debug               []string
+
+	lastOp         vm.OpCode
+	prevOp         opcodeInfo // the previous opcode, unless hasPrevOp is unset after a RETURN or a REVERT
+	hasPrevOp      bool
+	stopCollecting bool
+	senderDeployed bool // whether the sender had code before the validation
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+func newErc7562Tracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+	var config erc7562TracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	return &erc7562Tracer{
+		config:              config,
+		callsFromEntryPoint: []*topLevelCallInfo{},
+		keccak:              []hexutility.Bytes{},
+		calls:               []interface{}{},
+		logs:                []bundlerLog{},
+		debug:               []string{},
+	}, nil
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *erc7562Tracer) CaptureStart(env *vm.EVM, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.env = env
+	t.precompiles = make(map[libcommon.Address]bool)
+	for _, addr := range vm.ActivePrecompiles(env.ChainRules()) {
+		t.precompiles[addr] = true
+	}
+	if t.config.Sender != nil {
+		t.senderDeployed = env.IntraBlockState().GetCodeSize(*t.config.Sender) > 0
+	}
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *erc7562Tracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	if t.stopCollecting || atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	call := &enterCallInfo{Type: typ.String(), From: from, To: to, Method: libcommon.CopyBytes(input[:min(len(input), 4)]), Gas: gas}
+	if value != nil {
+		call.Value = (*hexutil.Big)(value.ToBig())
+	}
+	t.calls = append(t.calls, call)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *erc7562Tracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.stopCollecting || atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	typ := "RETURN"
+	if err != nil {
+		typ = "REVERT"
+	}
+	t.calls = append(t.calls, &exitCallInfo{Type: typ, GasUsed: gasUsed, Data: callDataHex(output)})
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *erc7562Tracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, _ *vm.ScopeContext, depth int, err error) {
+	t.debug = append(t.debug, fmt.Sprintf("fault depth=%d gas=%d cost=%d err=%v", depth, gas, cost, err))
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *erc7562Tracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.stopCollecting || atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	stack := scope.Stack
+	prev, hasPrev := t.prevOp, t.hasPrevOp
+	t.prevOp, t.hasPrevOp = opcodeInfo{op: op}, true
+	if stack.Len() > 0 {
+		t.prevOp.stackTop = *stack.Back(0)
+	}
+
+	if t.currentLevel != nil && (gas < cost || (op == vm.SSTORE && gas < 2300)) {
+		t.currentLevel.OOG = true
+	}
+	if op == vm.RETURN || op == vm.REVERT {
+		if depth == 1 {
+			// CaptureExit isn't called for the top-level call
+			t.calls = append(t.calls, &exitCallInfo{Type: op.String(), Data: callDataHex(memorySlice(scope, stack.Back(0), stack.Back(1)))})
+		}
+		hasPrev, t.hasPrevOp = false, false
+	}
+
+	if depth == 1 {
+		switch {
+		case (op == vm.CALL || op == vm.STATICCALL) && stack.Len() >= 5:
+			argsOffset := stack.Back(3)
+			if op == vm.STATICCALL {
+				// STATICCALL has no value argument
+				argsOffset = stack.Back(2)
+			}
+			t.currentLevel = &topLevelCallInfo{
+				TopLevelMethodSig:     memorySlice(scope, argsOffset, uint256.NewInt(4)),
+				TopLevelTargetAddress: stack.Back(1).Bytes20(),
+				Opcodes:               map[string]uint64{},
+				Access:                map[libcommon.Address]*storageAccess{},
+				ContractSize:          map[libcommon.Address]*contractSizeInfo{},
+				ExtCodeAccessInfo:     map[libcommon.Address]string{},
+				opcodes:               map[vm.OpCode]uint64{},
+			}
+			t.callsFromEntryPoint = append(t.callsFromEntryPoint, t.currentLevel)
+		case op == vm.LOG1 && stack.Len() >= 3:
+			if libcommon.Hash(stack.Back(2).Bytes32()) == beforeExecutionTopic {
+				t.stopCollecting = true
+			}
+		}
+		t.lastOp = 0
+		return
+	}
+	level := t.currentLevel
+	if level == nil {
+		// not called by an EntryPoint
+		return
+	}
+
+	// the opcode following EXTCODE* tells whether the code was only checked to exist [OP-051]
+	if hasPrev && isExtCodeOpcode(prev.op) {
+		if addr := libcommon.Address(prev.stackTop.Bytes20()); level.ExtCodeAccessInfo[addr] == "" {
+			level.ExtCodeAccessInfo[addr] = op.String()
+		}
+	}
+	// [OP-041]
+	if isExtCodeOpcode(op) || op == vm.CALL || op == vm.CALLCODE || op == vm.DELEGATECALL || op == vm.STATICCALL {
+		idx := 1
+		if isExtCodeOpcode(op) {
+			idx = 0
+		}
+		if stack.Len() > idx {
+			addr := libcommon.Address(stack.Back(idx).Bytes20())
+			if _, ok := level.ContractSize[addr]; !ok && !t.precompiles[addr] {
+				level.ContractSize[addr] = &contractSizeInfo{ContractSize: t.env.IntraBlockState().GetCodeSize(addr), Opcode: op.String()}
+			}
+		}
+	}
+
+	// GAS is only counted when not followed by a call [OP-012]
+	if t.lastOp == vm.GAS && op != vm.CALL && op != vm.CALLCODE && op != vm.DELEGATECALL && op != vm.STATICCALL {
+		level.count(vm.GAS)
+	}
+	if op != vm.GAS && !isUntrackedOpcode(op) {
+		level.count(op)
+	}
+	t.lastOp = op
+
+	switch {
+	case (op == vm.SLOAD || op == vm.SSTORE || op == vm.TLOAD || op == vm.TSTORE) && stack.Len() > 0:
+		slot := libcommon.Hash(stack.Back(0).Bytes32())
+		addr := scope.Contract.Address()
+		access := level.Access[addr]
+		if access == nil {
+			access = &storageAccess{
+				Reads:           map[libcommon.Hash]libcommon.Hash{},
+				Writes:          map[libcommon.Hash]uint64{},
+				TransientReads:  map[libcommon.Hash]uint64{},
+				TransientWrites: map[libcommon.Hash]uint64{},
+			}
+			level.Access[addr] = access
+		}
+		switch op {
+		case vm.SLOAD:
+			// the value before the validation wrote it
+			_, read := access.Reads[slot]
+			_, written := access.Writes[slot]
+			if !read && !written {
+				var value uint256.Int
+				t.env.IntraBlockState().GetState(addr, &slot, &value)
+				access.Reads[slot] = value.Bytes32()
+			}
+		case vm.SSTORE:
+			access.Writes[slot]++
+		case vm.TLOAD:
+			access.TransientReads[slot]++
+		case vm.TSTORE:
+			access.TransientWrites[slot]++
+		}
+	case op == vm.KECCAK256 && stack.Len() >= 2:
+		// mapping keys, to tell the storage associated with an entity
+		if size := stack.Back(1); size.GtUint64(20) && size.LtUint64(512) {
+			t.keccak = append(t.keccak, memorySlice(scope, stack.Back(0), size))
+		}
+	case op >= vm.LOG0 && op <= vm.LOG4 && stack.Len() >= 2+int(op-vm.LOG0):
+		log := bundlerLog{Topics: make([]libcommon.Hash, op-vm.LOG0), Data: memorySlice(scope, stack.Back(0), stack.Back(1))}
+		for i := range log.Topics {
+			log.Topics[i] = stack.Back(2 + i).Bytes32()
+		}
+		t.logs = append(t.logs, log)
+	}
+}
+
+// GetResult returns the json-encoded collected data, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *erc7562Tracer) GetResult() (json.RawMessage, error) {
+	result := struct {
+		CallsFromEntryPoint []*topLevelCallInfo `json:"callsFromEntryPoint"`
+		Keccak              []hexutility.Bytes  `json:"keccak"`
+		Logs                []bundlerLog        `json:"logs"`
+		Calls               []interface{}       `json:"calls"`
+		Debug               []string            `json:"debug"`
+		Violations          []ruleViolation     `json:"violations,omitempty"`
+	}{t.callsFromEntryPoint, t.keccak, t.logs, t.calls, t.debug, nil}
+	if t.config.Sender != nil {
+		result.Violations = t.violations()
+	}
+	res, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *erc7562Tracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// violations checks the ERC-7562 opcode and storage rules against the calls to the configured entities
+func (t *erc7562Tracer) violations() []ruleViolation {
+	staked := make(map[libcommon.Address]bool, len(t.config.Staked))
+	for _, addr := range t.config.Staked {
+		staked[addr] = true
+	}
+	sender := *t.config.Sender
+	// the storage associated with an address: its slot, and the slots of the mappings keyed by it [STO-021]
+	associated := func(addr libcommon.Address, slot libcommon.Hash) bool {
+		if slot == libcommon.BytesToHash(addr[:]) {
+			return true
+		}
+		key := libcommon.BytesToHash(addr[:])
+		slotInt := new(uint256.Int).SetBytes(slot[:])
+		for _, preimage := range t.keccak {
+			if len(preimage) < 32 || !bytes.Equal(preimage[:32], key[:]) {
+				continue
+			}
+			base := new(uint256.Int).SetBytes(crypto.Keccak256(preimage))
+			if offset := new(uint256.Int).Sub(slotInt, base); !slotInt.Lt(base) && offset.LtUint64(129) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var violations []ruleViolation
+	for _, level := range t.callsFromEntryPoint {
+		var entityName string
+		var entity *libcommon.Address
+		var sig [4]byte
+		copy(sig[:], level.TopLevelMethodSig)
+		switch {
+		case sig == createSenderSelector:
+			entityName, entity = "factory", t.config.Factory
+		case sig == validateUserOpSelectors[0] || sig == validateUserOpSelectors[1]:
+			entityName, entity = "account", &sender
+		case sig == validatePaymasterUserOpSelectors[0] || sig == validatePaymasterUserOpSelectors[1]:
+			entityName, entity = "paymaster", t.config.Paymaster
+		}
+		if entity == nil {
+			continue
+		}
+		violate := func(rule, format string, args ...interface{}) {
+			violations = append(violations, ruleViolation{Rule: rule, Entity: entityName, Address: *entity, Message: fmt.Sprintf(format, args...)})
+		}
+
+		ops := make([]vm.OpCode, 0, len(level.opcodes))
+		for op := range level.opcodes {
+			ops = append(ops, op)
+		}
+		slices.Sort(ops)
+		for _, op := range ops {
+			switch {
+			case bannedOpcodes[op]:
+				violate("OP-011", "uses banned opcode %s", op)
+			case stakedOpcodes[op] && !staked[*entity]:
+				violate("OP-080", "uses %s, which requires stake", op)
+			case op == vm.CREATE && (entityName != "account" || t.config.Factory == nil):
+				violate("OP-032", "uses CREATE, only allowed to the account deployed by a factory")
+			case op == vm.CREATE2 && (entityName != "factory" || level.opcodes[op] > 1):
+				violate("OP-031", "uses CREATE2, only allowed once to the factory")
+			}
+		}
+		if level.OOG {
+			violate("OP-020", "runs out of gas")
+		}
+		for _, addr := range sortedKeys(level.ContractSize) {
+			if level.ContractSize[addr].ContractSize == 0 && addr != sender {
+				violate("OP-041", "accesses %s without deployed code with %s", addr, level.ContractSize[addr].Opcode)
+			}
+		}
+
+		for _, addr := range sortedKeys(level.Access) {
+			access := level.Access[addr]
+			for _, slot := range access.slots() {
+				written := access.Writes[slot] > 0 || access.TransientWrites[slot] > 0
+				switch {
+				case addr == sender:
+					// the account's own storage [STO-010]
+				case associated(sender, slot):
+					// the storage associated with the account, before it is deployed only with a staked factory [STO-022]
+					if t.config.Factory != nil && !staked[*t.config.Factory] && !t.senderDeployed {
+						violate("STO-022", "accesses slot %s of %s associated with the undeployed account, which requires a staked factory", slot, addr)
+					}
+				case !staked[*entity]:
+					violate("STO-021", "accesses slot %s of %s not associated with the account, which requires stake", slot, addr)
+				case addr == *entity || associated(*entity, slot):
+					// the staked entity's own and associated storage [STO-031, STO-032]
+				case written:
+					violate("STO-033", "writes slot %s of %s, only reads are allowed to staked entities", slot, addr)
+				}
+			}
+		}
+	}
+	return violations
+}
+
+func (l *topLevelCallInfo) count(op vm.OpCode) {
+	l.Opcodes[op.String()]++
+	l.opcodes[op]++
+}
+
+// slots returns the accessed slots, sorted
+func (a *storageAccess) slots() []libcommon.Hash {
+	set := make(map[libcommon.Hash]struct{})
+	for slot := range a.Reads {
+		set[slot] = struct{}{}
+	}
+	for _, slots := range []map[libcommon.Hash]uint64{a.Writes, a.TransientReads, a.TransientWrites} {
+		for slot := range slots {
+			set[slot] = struct{}{}
+		}
+	}
+	return sortedKeys(set)
+}
+
+// sortedKeys returns the addresses or hashes keying the map, sorted
+func sortedKeys[K interface {
+	comparable
+	Bytes() []byte
+}, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b K) int {
+		return bytes.Compare(a.Bytes(), b.Bytes())
+	})
+	return keys
+}
+
+func isExtCodeOpcode(op vm.OpCode) bool {
+	return op == vm.EXTCODESIZE || op == vm.EXTCODECOPY || op == vm.EXTCODEHASH
+}
+
+// isUntrackedOpcode tells the opcodes not counted since no rule concerns them
+func isUntrackedOpcode(op vm.OpCode) bool {
+	if op >= vm.PUSH0 && op <= vm.SWAP16 {
+		return true
+	}
+	switch op {
+	case vm.POP, vm.ADD, vm.SUB, vm.MUL, vm.DIV, vm.EQ, vm.LT, vm.GT, vm.SLT, vm.SGT, vm.SHL, vm.SHR, vm.AND, vm.OR, vm.NOT, vm.ISZERO:
+		return true
+	}
+	return false
+}
+
+// memorySlice returns a copy of the memory, or nil if out of its bounds
+func memorySlice(scope *vm.ScopeContext, offset, size *uint256.Int) []byte {
+	memLen := uint64(scope.Memory.Len())
+	// compared without adding offset and size, which can overflow
+	if !offset.IsUint64() || !size.IsUint64() || offset.Uint64() > memLen || size.Uint64() > memLen-offset.Uint64() {
+		return nil
+	}
+	return scope.Memory.GetCopy(int64(offset.Uint64()), int64(size.Uint64()))
+}
+
+func callDataHex(data []byte) string {
+	encoded := hexutility.Encode(data)
+	if len(encoded) > maxCallDataHex {
+		return encoded[:maxCallDataHex]
+	}
+	return encoded
+}
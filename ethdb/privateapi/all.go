@@ -17,7 +17,7 @@ import (
 )
 
 func StartGrpc(kv *remotedbserver.KvServer, ethBackendSrv *EthBackendServer, txPoolServer txpool_proto.TxpoolServer,
-	miningServer txpool_proto.MiningServer, addr string, rateLimit uint32, creds credentials.TransportCredentials,
+	miningServer txpool_proto.MiningServer, changeFeedServer remote.CHANGEFEEDServer, addr string, rateLimit uint32, creds credentials.TransportCredentials,
 	healthCheck bool, logger log.Logger) (*grpc.Server, error) {
 	logger.Info("Starting private RPC server", "on", addr)
 	lis, err := net.Listen("tcp", addr)
@@ -35,6 +35,9 @@ func StartGrpc(kv *remotedbserver.KvServer, ethBackendSrv *EthBackendServer, txP
 	}
 
 	remote.RegisterKVServer(grpcServer, kv)
+	if changeFeedServer != nil {
+		remote.RegisterCHANGEFEEDServer(grpcServer, changeFeedServer)
+	}
 	var healthServer *health.Server
 	if healthCheck {
 		healthServer = health.NewServer()
@@ -153,6 +153,12 @@ func (s *EthBackendServer) Subscribe(r *remote.SubscribeRequest, subscribeServer
 	defer clean()
 	newSnCh, newSnClean := s.events.AddNewSnapshotSubscription()
 	defer newSnClean()
+	safeCh, safeClean := s.events.AddSafeHeaderSubscription()
+	defer safeClean()
+	finalizedCh, finalizedClean := s.events.AddFinalizedHeaderSubscription()
+	defer finalizedClean()
+	reorgCh, reorgClean := s.events.AddReorgSubscription()
+	defer reorgClean()
 	s.logger.Info("new subscription to newHeaders established")
 	defer func() {
 		if err != nil {
@@ -183,6 +189,18 @@ func (s *EthBackendServer) Subscribe(r *remote.SubscribeRequest, subscribeServer
 			if err = subscribeServer.Send(&remote.SubscribeReply{Type: remote.Event_NEW_SNAPSHOT}); err != nil {
 				return err
 			}
+		case headerRlp := <-safeCh:
+			if err = subscribeServer.Send(&remote.SubscribeReply{Type: remote.Event_SAFE_HEADER, Data: headerRlp}); err != nil {
+				return err
+			}
+		case headerRlp := <-finalizedCh:
+			if err = subscribeServer.Send(&remote.SubscribeReply{Type: remote.Event_FINALIZED_HEADER, Data: headerRlp}); err != nil {
+				return err
+			}
+		case reorgRlp := <-reorgCh:
+			if err = subscribeServer.Send(&remote.SubscribeReply{Type: remote.Event_REORG, Data: reorgRlp}); err != nil {
+				return err
+			}
 		}
 	}
 }
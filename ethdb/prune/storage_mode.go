@@ -180,6 +180,34 @@ func (b Before) PruneTo(uint64) uint64 {
 	return uint64(b) - 1
 }
 
+// Availability - the earliest block for which each kind of data is still servable,
+// 0 means the data was never pruned.
+type Availability struct {
+	State    uint64 `json:"state"`
+	Receipts uint64 `json:"receipts"`
+	Logs     uint64 `json:"logs"`
+	Traces   uint64 `json:"traces"`
+	TxLookup uint64 `json:"txLookup"`
+}
+
+// Availability returns the boundaries of the pruned data relative to the given head.
+func (m Mode) Availability(head uint64) Availability {
+	earliest := func(amount BlockAmount) uint64 {
+		if amount == nil || !amount.Enabled() {
+			return 0
+		}
+		return amount.PruneTo(head)
+	}
+	state, receipts := earliest(m.History), earliest(m.Receipts)
+	return Availability{
+		State:    state,
+		Receipts: min(receipts, state), // pruned receipts are re-generated from state history
+		Logs:     receipts,             // log index is pruned together with receipts
+		Traces:   earliest(m.CallTraces),
+		TxLookup: earliest(m.TxIndex),
+	}
+}
+
 func (m Mode) String() string {
 	if !m.Initialised {
 		return "default"
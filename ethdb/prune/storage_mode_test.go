@@ -73,3 +73,24 @@ func TestBeforePruneTo(t *testing.T) {
 		})
 	}
 }
+
+func TestModeAvailability(t *testing.T) {
+	m := DefaultMode
+	assert.Equal(t, Availability{}, m.Availability(3_000_000))
+
+	m.History = Distance(1_000_000)
+	m.Receipts = Before(500_000)
+	m.TxIndex = Distance(90_000)
+	assert.Equal(t, Availability{
+		State:    2_000_000,
+		Receipts: 499_999,
+		Logs:     499_999,
+		Traces:   0,
+		TxLookup: 2_910_000,
+	}, m.Availability(3_000_000))
+
+	m.Receipts = Distance(90_000)
+	av := m.Availability(3_000_000)
+	assert.Equal(t, uint64(2_000_000), av.Receipts)
+	assert.Equal(t, uint64(2_910_000), av.Logs)
+}
@@ -33,8 +33,10 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/opstack"
 	"github.com/ledgerwatch/erigon/turbo/services"
 	"github.com/ledgerwatch/log/v3"
 
@@ -59,6 +61,7 @@ type Service struct {
 	chaindb   kv.RoDB
 	networkid uint64
 	engine    consensus.Engine // Consensus engine to retrieve variadic block fields
+	config    *chain.Config    // Chain config to decide whether rollup fields are reported
 
 	node string // Name of the node to display on the monitoring page
 	pass string // Password to authorize access to the monitoring page
@@ -125,7 +128,7 @@ func (w *connWrapper) Close() error {
 
 // New returns a monitoring service ready for stats reporting.
 func New(node *node.Node, servers []*sentry.GrpcServer, chainDB kv.RoDB, blockReader services.FullBlockReader,
-	engine consensus.Engine, url string, networkid uint64, quitCh <-chan struct{}, headCh chan [][]byte, txPoolRpcClient txpool.TxpoolClient) error {
+	engine consensus.Engine, config *chain.Config, url string, networkid uint64, quitCh <-chan struct{}, headCh chan [][]byte, txPoolRpcClient txpool.TxpoolClient) error {
 	// Parse the netstats connection url
 	re := regexp.MustCompile("([^:@]*)(:([^@]*))?@(.+)")
 	parts := re.FindStringSubmatch(url)
@@ -135,6 +138,7 @@ func New(node *node.Node, servers []*sentry.GrpcServer, chainDB kv.RoDB, blockRe
 	ethstats := &Service{
 		blockReader: blockReader,
 		engine:      engine,
+		config:      config,
 		servers:     servers,
 		node:        parts[1],
 		pass:        parts[3],
@@ -483,12 +487,24 @@ type blockStats struct {
 	Miner      libcommon.Address `json:"miner"`
 	GasUsed    uint64            `json:"gasUsed"`
 	GasLimit   uint64            `json:"gasLimit"`
-	Diff       string            `json:"difficulty"`
+	Diff       string            `json:"difficulty,omitempty"`
 	TotalDiff  string            `json:"totalDifficulty"`
 	Txs        []txStats         `json:"transactions"`
 	TxHash     libcommon.Hash    `json:"transactionsRoot"`
 	Root       libcommon.Hash    `json:"stateRoot"`
-	Uncles     uncleStats        `json:"uncles"`
+	Uncles     *uncleStats       `json:"uncles,omitempty"`
+
+	*rollupBlockStats // only set on OP stack chains, which have no uncles or difficulty
+}
+
+// rollupBlockStats is the information to report about individual blocks of an
+// OP stack chain, on top of the L1 block stats.
+type rollupBlockStats struct {
+	L1OriginNumber uint64         `json:"l1OriginNumber"`
+	L1OriginHash   libcommon.Hash `json:"l1OriginHash"`
+	SequencerDrift int64          `json:"sequencerDrift"` // block time minus L1 origin time, in seconds
+	Deposits       int            `json:"deposits"`
+	L1Fees         *big.Int       `json:"l1Fees"`
 }
 
 // txStats is the information to report about individual transactions.
@@ -556,7 +572,7 @@ func (s *Service) assembleBlockStats(block *types.Block, td *big.Int) *blockStat
 		txs = append(txs, txStats{tx.Hash()})
 	}
 
-	return &blockStats{
+	stats := &blockStats{
 		Number:     block.Header().Number,
 		Hash:       block.Hash(),
 		ParentHash: block.Header().ParentHash,
@@ -564,13 +580,56 @@ func (s *Service) assembleBlockStats(block *types.Block, td *big.Int) *blockStat
 		Miner:      block.Header().Coinbase,
 		GasUsed:    block.Header().GasUsed,
 		GasLimit:   block.Header().GasLimit,
-		Diff:       block.Header().Difficulty.String(),
 		TotalDiff:  td.String(),
 		Txs:        txs,
 		TxHash:     block.Header().TxHash,
 		Root:       block.Header().Root,
-		Uncles:     block.Uncles(),
 	}
+	if s.config.IsOptimism() {
+		stats.rollupBlockStats = s.assembleRollupBlockStats(block)
+		return stats
+	}
+	uncles := uncleStats(block.Uncles())
+	stats.Diff = block.Header().Difficulty.String()
+	stats.Uncles = &uncles
+	return stats
+}
+
+// assembleRollupBlockStats decodes the L1 origin from the L1 info deposit of the
+// block and sums up the L1 data fees paid by its transactions.
+func (s *Service) assembleRollupBlockStats(block *types.Block) *rollupBlockStats {
+	stats := &rollupBlockStats{L1Fees: new(big.Int)}
+	txs := block.Transactions()
+	for _, tx := range txs {
+		if tx.Type() == types.DepositTxType {
+			stats.Deposits++
+		}
+	}
+	if !s.config.IsOptimismBedrock(block.NumberU64()) || len(txs) == 0 || txs[0].Type() != types.DepositTxType {
+		return stats
+	}
+	origin, err := opstack.ExtractL1Origin(txs[0].GetData())
+	if err != nil {
+		log.Debug("Failed to decode L1 origin for ethstats", "number", block.NumberU64(), "err", err)
+		return stats
+	}
+	stats.L1OriginNumber = origin.Number
+	stats.L1OriginHash = origin.Hash
+	stats.SequencerDrift = int64(block.Time()) - int64(origin.Time)
+
+	gasParams, err := opstack.ExtractL1GasParams(s.config, block.Time(), txs[0].GetData())
+	if err != nil {
+		log.Debug("Failed to decode L1 gas params for ethstats", "number", block.NumberU64(), "err", err)
+		return stats
+	}
+	for _, tx := range txs {
+		if tx.Type() == types.DepositTxType {
+			continue
+		}
+		fee, _ := gasParams.CostFunc(tx.RollupCostData())
+		stats.L1Fees.Add(stats.L1Fees, fee.ToBig())
+	}
+	return stats
 }
 
 // reportHistory retrieves the most recent batch of blocks and reports it to the
@@ -675,6 +734,17 @@ type nodeStats struct {
 	GoodPeers int  `json:"peers"`
 	GasPrice  int  `json:"gasPrice"`
 	Uptime    int  `json:"uptime"`
+
+	*rollupNodeStats // only set on OP stack chains
+}
+
+// rollupNodeStats is the information to report about the L2 heads of an OP
+// stack node, on top of the L1 node stats.
+type rollupNodeStats struct {
+	SafeNumber      uint64         `json:"safeNumber"`
+	SafeHash        libcommon.Hash `json:"safeHash"`
+	FinalizedNumber uint64         `json:"finalizedNumber"`
+	FinalizedHash   libcommon.Hash `json:"finalizedHash"`
 }
 
 // reportStats retrieves various stats about the node at the networking and
@@ -701,17 +771,32 @@ func (s *Service) reportStats(conn *connWrapper) error {
 			peerCount += count
 		}
 	}
+	details := &nodeStats{
+		Active:    true,
+		Mining:    false,
+		Hashrate:  0,
+		GoodPeers: peerCount,
+		GasPrice:  0,
+		Syncing:   sync != finishSync,
+		Uptime:    100,
+	}
+	if s.config.IsOptimism() {
+		// the rollup node drives the heads through the engine API, see forkchoiceUpdated
+		rollup := &rollupNodeStats{
+			SafeHash:      rawdb.ReadForkchoiceSafe(roTx),
+			FinalizedHash: rawdb.ReadForkchoiceFinalized(roTx),
+		}
+		if number := rawdb.ReadHeaderNumber(roTx, rollup.SafeHash); number != nil {
+			rollup.SafeNumber = *number
+		}
+		if number := rawdb.ReadHeaderNumber(roTx, rollup.FinalizedHash); number != nil {
+			rollup.FinalizedNumber = *number
+		}
+		details.rollupNodeStats = rollup
+	}
 	stats := map[string]interface{}{
-		"id": s.node,
-		"stats": &nodeStats{
-			Active:    true,
-			Mining:    false,
-			Hashrate:  0,
-			GoodPeers: peerCount,
-			GasPrice:  0,
-			Syncing:   sync != finishSync,
-			Uptime:    100,
-		},
+		"id":    s.node,
+		"stats": details,
 	}
 	report := map[string][]interface{}{
 		"emit": {"stats", stats},
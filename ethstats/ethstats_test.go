@@ -0,0 +1,38 @@
+package ethstats
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+func TestAssembleBlockStatsReport(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(10), Difficulty: big.NewInt(2), GasLimit: 30_000_000}
+	block := types.NewBlock(header, nil, []*types.Header{{Number: big.NewInt(9), Difficulty: big.NewInt(1)}}, nil, nil)
+
+	report := func(config *chain.Config) map[string]json.RawMessage {
+		s := &Service{config: config}
+		blob, err := json.Marshal(s.assembleBlockStats(block, big.NewInt(100)))
+		require.NoError(t, err)
+		var fields map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(blob, &fields))
+		return fields
+	}
+
+	l1 := report(&chain.Config{ChainID: big.NewInt(1)})
+	require.JSONEq(t, `"2"`, string(l1["difficulty"]))
+	require.Contains(t, l1, "uncles")
+	require.NotContains(t, l1, "l1OriginNumber")
+
+	op := report(&chain.Config{ChainID: big.NewInt(10), Optimism: &chain.OptimismConfig{}})
+	require.NotContains(t, op, "difficulty")
+	require.NotContains(t, op, "uncles")
+	require.Contains(t, op, "l1OriginNumber")
+	require.JSONEq(t, `"100"`, string(op["totalDifficulty"]))
+}
@@ -0,0 +1,118 @@
+package params
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+)
+
+// ConfigChange is a chain config field whose value differs between a stored and a new chain config.
+type ConfigChange struct {
+	Field  string // json name of the field, nested fields are joined with a dot, e.g. "optimism.eip1559Elasticity"
+	Stored string // empty if unset in the stored config
+	New    string // empty if unset in the new config
+
+	Fork        bool // the field is a fork activation block or time
+	Retroactive bool // the fork already activated on the local chain under the stored or the new value
+}
+
+func (c ConfigChange) String() string {
+	stored, updated := c.Stored, c.New
+	if stored == "" {
+		stored = "unset"
+	}
+	if updated == "" {
+		updated = "unset"
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Field, stored, updated)
+}
+
+// DiffChainConfigs lists the fields which differ between the stored and the new chain config, ordered
+// by field name. Fork changes are checked against the local head, given by its number and time: a
+// change is retroactive if either the stored or the new activation is not later than the head.
+func DiffChainConfigs(stored, updated *chain.Config, headNumber, headTime uint64) ([]ConfigChange, error) {
+	storedFields, err := flattenConfig(stored)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenConfig(updated)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range storedFields {
+		names = append(names, name)
+	}
+	for name := range newFields {
+		if _, ok := storedFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []ConfigChange
+	for _, name := range names {
+		change := ConfigChange{Field: name, Stored: storedFields[name], New: newFields[name]}
+		if change.Stored == change.New {
+			continue
+		}
+		var head uint64
+		switch {
+		case strings.Contains(name, "."):
+		case strings.HasSuffix(name, "Block"):
+			change.Fork, head = true, headNumber
+		case strings.HasSuffix(name, "Time"):
+			change.Fork, head = true, headTime
+		}
+		if change.Fork {
+			change.Retroactive = activatedBy(change.Stored, head) || activatedBy(change.New, head)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func activatedBy(activation string, head uint64) bool {
+	n, ok := new(big.Int).SetString(activation, 10)
+	return ok && n.Cmp(new(big.Int).SetUint64(head)) <= 0
+}
+
+// flattenConfig returns the json encoded fields of the config as strings, nested objects are flattened.
+func flattenConfig(config *chain.Config) (map[string]string, error) {
+	enc, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(enc))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	var flatten func(prefix string, fields map[string]interface{})
+	flatten = func(prefix string, fields map[string]interface{}) {
+		for name, value := range fields {
+			if nested, ok := value.(map[string]interface{}); ok {
+				flatten(prefix+name+".", nested)
+				continue
+			}
+			if value == nil {
+				continue
+			}
+			if _, ok := value.([]interface{}); ok {
+				b, _ := json.Marshal(value)
+				out[prefix+name] = string(b)
+				continue
+			}
+			out[prefix+name] = fmt.Sprint(value)
+		}
+	}
+	flatten("", fields)
+	return out, nil
+}
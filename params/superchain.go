@@ -283,6 +283,16 @@ func (p ProtocolVersion) Compare(other ProtocolVersion) (cmp ProtocolVersionComp
 	return fn(aPreRelease, bPreRelease, AheadPrerelease, OutdatedPrerelease)
 }
 
+// UpgradeRequired reports whether the other version is ahead of p by a major, minor or patch version,
+// i.e. whether a node at version p has to upgrade to satisfy it.
+func (p ProtocolVersion) UpgradeRequired(other ProtocolVersion) bool {
+	switch p.Compare(other) {
+	case OutdatedMajor, OutdatedMinor, OutdatedPatch:
+		return true
+	}
+	return false
+}
+
 type ProtocolVersionV0 struct {
 	Build                           [8]byte
 	Major, Minor, Patch, PreRelease uint32
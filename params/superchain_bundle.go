@@ -0,0 +1,289 @@
+package params
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+const (
+	// maxSuperchainBundleSize caps the size of a downloaded superchain registry bundle
+	maxSuperchainBundleSize = 64 << 20
+	// superchainBundleFetchTimeout bounds the download of a bundle, so an unresponsive server can't hang startup
+	superchainBundleFetchTimeout = time.Minute
+)
+
+// LoadSuperchainBundle loads a superchain registry bundle and registers its chains, replacing the
+// compiled-in configs of chains with the same chain ID. After that every OP stack lookup in this
+// package - by name or by genesis hash - sees the bundle's configs.
+//
+// The bundle has the layout of the registry's `superchain/configs` directory: one directory per
+// superchain, holding its superchain.toml and one <chain>.toml per chain. It is read from a local
+// directory, a local .tar.gz archive or an http(s) URL of such an archive.
+//
+// The chain configs are consensus critical, so a bundle fetched over plain http is only applied when
+// checksum, the sha256 of the archive, is given. If checksum is set, any archive must match it.
+//
+// Only chain configs are read from the bundle. The genesis of a chain which is not compiled in has to
+// be supplied with `erigon init`.
+func LoadSuperchainBundle(ctx context.Context, location string, checksum []byte) ([]*superchain.ChainConfig, error) {
+	files, err := readSuperchainBundle(ctx, location, checksum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read superchain registry bundle %s: %w", location, err)
+	}
+	superchains, chains, err := parseSuperchainBundle(files)
+	if err != nil {
+		return nil, fmt.Errorf("invalid superchain registry bundle %s: %w", location, err)
+	}
+	for _, s := range superchains {
+		if known, ok := superchain.Superchains[s.Superchain]; ok {
+			s.ChainIDs = mergeChainIDs(known.ChainIDs, s.ChainIDs)
+		}
+		superchain.Superchains[s.Superchain] = s
+	}
+	for _, c := range chains {
+		if known, ok := superchain.OPChains[c.ChainID]; ok && known.Superchain != c.Superchain {
+			if s, ok := superchain.Superchains[known.Superchain]; ok {
+				s.ChainIDs = removeChainID(s.ChainIDs, c.ChainID)
+			}
+		}
+		superchain.OPChains[c.ChainID] = c
+		superchain.Addresses[c.ChainID] = &c.Addresses
+		superchain.GenesisSystemConfigs[c.ChainID] = &c.Genesis.SystemConfig
+	}
+	return chains, nil
+}
+
+// readSuperchainBundle returns the toml files of the bundle, keyed by their slash separated path.
+func readSuperchainBundle(ctx context.Context, location string, checksum []byte) (map[string][]byte, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		if checksum == nil && !strings.HasPrefix(location, "https://") {
+			return nil, errors.New("a bundle fetched over plain http needs a sha256 checksum")
+		}
+		data, err := fetchSuperchainArchive(ctx, location)
+		if err != nil {
+			return nil, err
+		}
+		return readSuperchainArchive(data, checksum)
+	}
+
+	info, err := os.Stat(location)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return nil, err
+		}
+		return readSuperchainArchive(data, checksum)
+	}
+	if checksum != nil {
+		return nil, errors.New("a sha256 checksum only applies to a .tar.gz archive, not to a directory")
+	}
+	files := map[string][]byte{}
+	err = filepath.WalkDir(location, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(p) != ".toml" {
+			return err
+		}
+		rel, err := filepath.Rel(location, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	return files, err
+}
+
+func fetchSuperchainArchive(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: superchainBundleFetchTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSuperchainBundleSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSuperchainBundleSize {
+		return nil, fmt.Errorf("bundle is larger than %d bytes", maxSuperchainBundleSize)
+	}
+	return data, nil
+}
+
+func readSuperchainArchive(data []byte, checksum []byte) (map[string][]byte, error) {
+	if checksum != nil {
+		if sum := sha256.Sum256(data); !bytes.Equal(sum[:], checksum) {
+			return nil, fmt.Errorf("sha256 checksum mismatch: have %x, want %x", sum, checksum)
+		}
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Ext(hdr.Name) != ".toml" {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(hdr.Name)] = data
+	}
+}
+
+// parseSuperchainBundle decodes the bundle files the same way the registry decodes its compiled-in
+// configs. Directories without a superchain.toml are ignored, so an archive of the whole registry works too.
+func parseSuperchainBundle(files map[string][]byte) ([]*superchain.Superchain, []*superchain.ChainConfig, error) {
+	var dirs []string
+	for name := range files {
+		if path.Base(name) == "superchain.toml" && path.Dir(name) != "." {
+			dirs = append(dirs, path.Dir(name))
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, nil, errors.New("no superchain.toml found")
+	}
+	sort.Strings(dirs)
+
+	var (
+		superchains []*superchain.Superchain
+		chains      []*superchain.ChainConfig
+		seen        = map[uint64]string{}
+	)
+	for _, dir := range dirs {
+		entry := &superchain.Superchain{Superchain: path.Base(dir)}
+		var defaults superchain.HardForkConfiguration
+		if err := toml.Unmarshal(files[path.Join(dir, "superchain.toml")], &entry.Config); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode superchain config %s: %w", dir, err)
+		}
+		if err := toml.Unmarshal(files[path.Join(dir, "superchain.toml")], &defaults); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode superchain config %s: %w", dir, err)
+		}
+
+		var names []string
+		for name := range files {
+			if path.Dir(name) == dir && path.Base(name) != "superchain.toml" {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			c := &superchain.ChainConfig{}
+			if err := toml.Unmarshal(files[name], c); err != nil {
+				return nil, nil, fmt.Errorf("failed to decode chain config %s: %w", name, err)
+			}
+			if c.ChainID == 0 {
+				return nil, nil, fmt.Errorf("chain config %s has no chain_id", name)
+			}
+			if other, ok := seen[c.ChainID]; ok {
+				return nil, nil, fmt.Errorf("chain config %s has the same chain ID %d as %s", name, c.ChainID, other)
+			}
+			if c.SuperchainLevel != superchain.Frontier && c.SuperchainLevel != superchain.Standard {
+				return nil, nil, fmt.Errorf("chain config %s has invalid superchain level %d", name, c.SuperchainLevel)
+			}
+			seen[c.ChainID] = name
+			c.Chain = strings.TrimSuffix(path.Base(name), ".toml")
+			c.Superchain = entry.Superchain
+			inheritHardForkTimes(c, &defaults)
+			entry.ChainIDs = append(entry.ChainIDs, c.ChainID)
+			chains = append(chains, c)
+		}
+		superchains = append(superchains, entry)
+	}
+	return superchains, chains, nil
+}
+
+// inheritHardForkTimes fills in the fork times a chain does not set from its superchain defaults, as the
+// registry does for its compiled-in configs: a default applies if it is scheduled after the chain joined
+// the superchain, and becomes zero if it activated before the chain's genesis.
+func inheritHardForkTimes(c *superchain.ChainConfig, defaults *superchain.HardForkConfiguration) {
+	if c.SuperchainTime == nil {
+		return
+	}
+	chainForks := reflect.ValueOf(&c.HardForkConfiguration).Elem()
+	defaultForks := reflect.ValueOf(defaults).Elem()
+	for i := 0; i < chainForks.NumField(); i++ {
+		if !chainForks.Field(i).IsNil() || defaultForks.Field(i).IsNil() {
+			continue
+		}
+		activation := defaultForks.Field(i).Elem().Uint()
+		if activation < *c.SuperchainTime {
+			continue
+		}
+		if activation <= c.Genesis.L2Time {
+			activation = 0
+		}
+		chainForks.Field(i).Set(reflect.ValueOf(&activation))
+	}
+}
+
+func mergeChainIDs(a, b []uint64) []uint64 {
+	out := append([]uint64(nil), a...)
+	for _, id := range b {
+		if !containsChainID(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func removeChainID(ids []uint64, id uint64) []uint64 {
+	out := ids[:0]
+	for _, other := range ids {
+		if other != id {
+			out = append(out, other)
+		}
+	}
+	return out
+}
+
+func containsChainID(ids []uint64, id uint64) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
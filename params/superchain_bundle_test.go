@@ -0,0 +1,149 @@
+package params
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+)
+
+const testSuperchainToml = `
+name = "Testnet"
+canyon_time = 100
+ecotone_time = 200
+fjord_time = 300
+
+[l1]
+  chain_id = 11155111
+`
+
+const testChainToml = `
+name = "Bundle Test Chain"
+chain_id = 919191
+superchain_level = 0
+superchain_time = 0
+batch_inbox_addr = "0xff00000000000000000000000000000000919191"
+canyon_time = 50
+block_time = 2
+seq_window_size = 3600
+max_sequencer_drift = 600
+
+[genesis]
+  l2_time = 250
+  [genesis.l2]
+    hash = "0x1111111111111111111111111111111111111111111111111111111111111111"
+    number = 0
+`
+
+func TestLoadSuperchainBundle(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bundle-test"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bundle-test", "superchain.toml"), []byte(testSuperchainToml), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bundle-test", "chain.toml"), []byte(testChainToml), 0o644))
+	t.Cleanup(func() {
+		delete(superchain.OPChains, 919191)
+		delete(superchain.Addresses, 919191)
+		delete(superchain.GenesisSystemConfigs, 919191)
+		delete(superchain.Superchains, "bundle-test")
+	})
+
+	chains, err := LoadSuperchainBundle(context.Background(), dir, nil)
+	require.NoError(t, err)
+	require.Len(t, chains, 1)
+
+	cfg := ChainConfigByOpStackChainName("chain-bundle-test")
+	require.NotNil(t, cfg)
+	require.Equal(t, "Bundle Test Chain", cfg.ChainName)
+	require.Equal(t, big.NewInt(919191), cfg.ChainID)
+	// set by the chain itself
+	require.Equal(t, big.NewInt(50), cfg.CanyonTime)
+	// inherited from the superchain, ecotone activated before the chain genesis
+	require.Equal(t, big.NewInt(0), cfg.EcotoneTime)
+	require.Equal(t, big.NewInt(300), cfg.FjordTime)
+	require.Nil(t, cfg.GraniteTime)
+	require.Equal(t, []uint64{919191}, superchain.Superchains["bundle-test"].ChainIDs)
+
+	_, err = LoadSuperchainBundle(context.Background(), filepath.Join(dir, "bundle-test"), nil)
+	require.Error(t, err)
+}
+
+func TestLoadSuperchainBundleChecksum(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{"bundle-test/superchain.toml": testSuperchainToml, "bundle-test/chain.toml": testChainToml} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	archive := buf.Bytes()
+	sum := sha256.Sum256(archive)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer srv.Close()
+	t.Cleanup(func() {
+		delete(superchain.OPChains, 919191)
+		delete(superchain.Addresses, 919191)
+		delete(superchain.GenesisSystemConfigs, 919191)
+		delete(superchain.Superchains, "bundle-test")
+	})
+
+	// plain http without a checksum is refused
+	_, err := LoadSuperchainBundle(context.Background(), srv.URL, nil)
+	require.ErrorContains(t, err, "checksum")
+	_, err = LoadSuperchainBundle(context.Background(), srv.URL, make([]byte, sha256.Size))
+	require.ErrorContains(t, err, "checksum mismatch")
+	require.Nil(t, superchain.OPChains[919191])
+
+	chains, err := LoadSuperchainBundle(context.Background(), srv.URL, sum[:])
+	require.NoError(t, err)
+	require.Len(t, chains, 1)
+	require.Equal(t, uint64(919191), chains[0].ChainID)
+
+	// a checksum can't pin a directory
+	_, err = LoadSuperchainBundle(context.Background(), t.TempDir(), sum[:])
+	require.Error(t, err)
+}
+
+func TestDiffChainConfigs(t *testing.T) {
+	stored := &chain.Config{
+		ChainID:      big.NewInt(10),
+		BedrockBlock: big.NewInt(0),
+		EcotoneTime:  big.NewInt(1000),
+		FjordTime:    big.NewInt(2000),
+		Optimism:     &chain.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50},
+	}
+	updated := &chain.Config{
+		ChainID:      big.NewInt(10),
+		BedrockBlock: big.NewInt(0),
+		EcotoneTime:  big.NewInt(900),
+		FjordTime:    big.NewInt(2100),
+		GraniteTime:  big.NewInt(3000),
+		Optimism:     &chain.OptimismConfig{EIP1559Elasticity: 10, EIP1559Denominator: 50},
+	}
+	changes, err := DiffChainConfigs(stored, updated, 100, 1500)
+	require.NoError(t, err)
+	require.Equal(t, []ConfigChange{
+		{Field: "ecotoneTime", Stored: "1000", New: "900", Fork: true, Retroactive: true},
+		{Field: "fjordTime", Stored: "2000", New: "2100", Fork: true},
+		{Field: "graniteTime", New: "3000", Fork: true},
+		{Field: "optimism.eip1559Elasticity", Stored: "6", New: "10"},
+	}, changes)
+	require.Equal(t, "graniteTime: unset -> 3000", changes[2].String())
+}
@@ -40,6 +40,29 @@ func (e NoHistoricalFallbackError) Error() string {
 	return "no historical RPC is available for this historical (pre-bedrock) execution request"
 }
 
+// PrunedError is returned when the requested data of a block was pruned away on this node.
+// The error data carries the earliest block the data is still available for.
+type PrunedError struct {
+	Data     string // kind of pruned data: state, receipts, logs, traces, txLookup
+	Block    uint64
+	Earliest uint64
+}
+
+var _ DataError = PrunedError{}
+
+func (e PrunedError) ErrorCode() int { return -32802 }
+
+func (e PrunedError) Error() string {
+	return fmt.Sprintf("%s has been pruned for block %d, earliest available block is %d", e.Data, e.Block, e.Earliest)
+}
+
+func (e PrunedError) ErrorData() interface{} {
+	return map[string]interface{}{
+		"data":     e.Data,
+		"earliest": e.Earliest,
+	}
+}
+
 type methodNotFoundError struct{ method string }
 
 func (e *methodNotFoundError) ErrorCode() int { return -32601 }
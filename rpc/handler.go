@@ -28,6 +28,9 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ledgerwatch/erigon-lib/tracing"
 
 	"github.com/ledgerwatch/erigon/rpc/rpccfg"
 )
@@ -479,7 +482,13 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream *jsoniter
 		return msg.errorResponse(&InvalidParamsError{err.Error()})
 	}
 	start := time.Now()
-	answer := h.runMethod(cp.ctx, msg, callb, args, stream)
+	ctx, span := tracing.StartSpan(cp.ctx, "rpc."+msg.Method, attribute.String("rpc.method", msg.Method))
+	answer := h.runMethod(ctx, msg, callb, args, stream)
+	if answer != nil && answer.Error != nil {
+		tracing.EndSpan(span, answer.Error)
+	} else {
+		span.End()
+	}
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
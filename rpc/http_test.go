@@ -17,12 +17,17 @@
 package rpc
 
 import (
+	"context"
+	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/tracing"
+	"github.com/ledgerwatch/erigon-lib/tracing/tracingtest"
 )
 
 func confirmStatusCode(t *testing.T, got, want int) {
@@ -129,3 +134,47 @@ func TestHTTPRespBodyUnlimited(t *testing.T) {
 		t.Fatalf("response has wrong length %d, want %d", len(r), respLength)
 	}
 }
+
+func TestHTTPTraceSpans(t *testing.T) {
+	collector := tracingtest.NewCollector(t)
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{Endpoint: collector.URL, ServiceName: "rpc-test", SampleRatio: 1}, log.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestServer(log.New())
+	defer server.Stop()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]}`
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	confirmStatusCode(t, resp.StatusCode, http.StatusOK)
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	span := collector.Span("rpc.test_echo")
+	if span == nil {
+		t.Fatal("no span exported for test_echo")
+	}
+	if traceID := hex.EncodeToString(span.TraceId); traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("span is not part of the caller's trace: %s", traceID)
+	}
+	if parentID := hex.EncodeToString(span.ParentSpanId); parentID != "00f067aa0ba902b7" {
+		t.Errorf("span is not a child of the caller's span: %s", parentID)
+	}
+	if method := tracingtest.Attribute(span, "rpc.method"); method != "test_echo" {
+		t.Errorf("wrong rpc.method attribute: %v", method)
+	}
+}
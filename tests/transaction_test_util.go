@@ -71,13 +71,22 @@ func (tt *TransactionTest) Run(chainID *big.Int) error {
 		sender := msg.From()
 
 		// Intrinsic gas
-		requiredGas, err := core.IntrinsicGas(msg.Data(), msg.AccessList(), msg.To() == nil, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
+		requiredGas, err := core.IntrinsicGas(msg.Data(), msg.AccessList(), uint64(len(msg.Authorizations())), msg.To() == nil, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
 		if err != nil {
 			return nil, nil, 0, err
 		}
 		if requiredGas > msg.Gas() {
 			return nil, nil, requiredGas, fmt.Errorf("insufficient gas ( %d < %d )", msg.Gas(), requiredGas)
 		}
+		if rules.IsPrague {
+			floorDataGas, err := core.FloorDataGas(msg.Data())
+			if err != nil {
+				return nil, nil, 0, err
+			}
+			if floorDataGas > msg.Gas() {
+				return nil, nil, floorDataGas, fmt.Errorf("%w: have %d, want %d", core.ErrFloorDataGas, msg.Gas(), floorDataGas)
+			}
+		}
 
 		if rules.IsLondon {
 			// EIP-1559 gas fee cap
@@ -39,19 +39,20 @@ import (
 
 // CallArgs represents the arguments for a call.
 type CallArgs struct {
-	From                 *libcommon.Address `json:"from"`
-	To                   *libcommon.Address `json:"to"`
-	Gas                  *hexutil.Uint64    `json:"gas"`
-	GasPrice             *hexutil.Big       `json:"gasPrice"`
-	MaxPriorityFeePerGas *hexutil.Big       `json:"maxPriorityFeePerGas"`
-	MaxFeePerGas         *hexutil.Big       `json:"maxFeePerGas"`
-	MaxFeePerBlobGas     *hexutil.Big       `json:"maxFeePerBlobGas"`
-	Value                *hexutil.Big       `json:"value"`
-	Nonce                *hexutil.Uint64    `json:"nonce"`
-	Data                 *hexutility.Bytes  `json:"data"`
-	Input                *hexutility.Bytes  `json:"input"`
-	AccessList           *types2.AccessList `json:"accessList"`
-	ChainID              *hexutil.Big       `json:"chainId,omitempty"`
+	From                 *libcommon.Address    `json:"from"`
+	To                   *libcommon.Address    `json:"to"`
+	Gas                  *hexutil.Uint64       `json:"gas"`
+	GasPrice             *hexutil.Big          `json:"gasPrice"`
+	MaxPriorityFeePerGas *hexutil.Big          `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         *hexutil.Big          `json:"maxFeePerGas"`
+	MaxFeePerBlobGas     *hexutil.Big          `json:"maxFeePerBlobGas"`
+	Value                *hexutil.Big          `json:"value"`
+	Nonce                *hexutil.Uint64       `json:"nonce"`
+	Data                 *hexutility.Bytes     `json:"data"`
+	Input                *hexutility.Bytes     `json:"input"`
+	AccessList           *types2.AccessList    `json:"accessList"`
+	ChainID              *hexutil.Big          `json:"chainId,omitempty"`
+	AuthorizationList    []types.Authorization `json:"authorizationList,omitempty"`
 }
 
 // from retrieves the transaction sender address.
@@ -160,6 +161,9 @@ func (args *CallArgs) ToMessage(globalGasCap uint64, baseFee *uint256.Int) (type
 	}
 
 	msg := types.NewMessage(addr, args.To, 0, value, gas, gasPrice, gasFeeCap, gasTipCap, data, accessList, false /* checkNonce */, false /* isFree */, true /* isFake */, maxFeePerBlobGas)
+	if args.AuthorizationList != nil {
+		msg.SetAuthorizations(args.AuthorizationList)
+	}
 	return msg, nil
 }
 
@@ -419,6 +423,8 @@ type RPCTransaction struct {
 
 	BlobVersionedHashes []libcommon.Hash `json:"blobVersionedHashes,omitempty"`
 
+	Authorizations *[]types.Authorization `json:"authorizationList,omitempty"`
+
 	// deposit-tx only
 	SourceHash *libcommon.Hash `json:"sourceHash,omitempty"`
 	Mint       *hexutil.Big    `json:"mint,omitempty"`
@@ -515,6 +521,19 @@ func newRPCTransaction(tx types.Transaction, blockHash libcommon.Hash, blockNumb
 		result.GasPrice = computeGasPrice(tx, blockHash, baseFee)
 		result.MaxFeePerBlobGas = (*hexutil.Big)(t.MaxFeePerBlobGas.ToBig())
 		result.BlobVersionedHashes = t.GetBlobHashes()
+	case *types.SetCodeTransaction:
+		chainId.Set(t.ChainID)
+		result.ChainID = (*hexutil.Big)(chainId.ToBig())
+		result.Tip = (*hexutil.Big)(t.Tip.ToBig())
+		result.FeeCap = (*hexutil.Big)(t.FeeCap.ToBig())
+		result.YParity = (*hexutil.Big)(t.V.ToBig())
+		result.V = (*hexutil.Big)(t.V.ToBig())
+		result.R = (*hexutil.Big)(t.R.ToBig())
+		result.S = (*hexutil.Big)(t.S.ToBig())
+		result.Accesses = &t.AccessList
+		result.Authorizations = &t.Authorizations
+		// if the transaction has been mined, compute the effective gas price
+		result.GasPrice = computeGasPrice(tx, blockHash, baseFee)
 	}
 	signer := types.LatestSignerForChainID(chainId.ToBig())
 	var err error
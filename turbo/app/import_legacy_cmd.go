@@ -0,0 +1,624 @@
+package app
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/rawdb/blockio"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/ethconfig/estimate"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+var (
+	LegacyReceiptsFlag = cli.StringFlag{
+		Name:  "legacy.receipts",
+		Usage: "Path to the pre-Bedrock receipts export (RLP stream of [number, [l2geth stored receipts]] entries)",
+	}
+	LegacyStateFlag = cli.StringFlag{
+		Name:  "legacy.state",
+		Usage: "Path to the pre-Bedrock state export (RLP stream of per block [number, [account diffs]] entries, starting at block 0)",
+	}
+)
+
+var importLegacyCommand = cli.Command{
+	Action:    MigrateFlags(importLegacy),
+	Name:      "import-legacy",
+	Usage:     "Import pre-Bedrock (l2geth) blocks, receipts and state",
+	ArgsUsage: "<blocks file>",
+	Flags: []cli.Flag{
+		&utils.DataDirFlag,
+		&LegacyReceiptsFlag,
+		&LegacyStateFlag,
+		&utils.RollupHistoricalRPCFlag,
+		&utils.RollupHistoricalRPCTimeoutFlag,
+	},
+	Description: `
+The import-legacy command imports pre-Bedrock OVM history from an l2geth RLP export
+(as produced by "geth export") into an initialized op-erigon datadir. The chain config
+must have bedrockBlock set, only blocks below it are accepted.
+
+Blocks already present in the datadir (e.g. in snapshots) are kept as is. Enqueued (L1 to L2)
+transactions carry no signature, their senders are resolved through --rollup.historicalrpc
+(the l2geth node the export comes from). Imported blocks are frozen into read-only snapshot
+segments, the last (less than 1000) blocks are left to the block retirement of the node.
+
+Receipts are read from --legacy.receipts, checked against the block receipts root and indexed
+for eth_getLogs. There are no receipt segments, receipts and the log index stay in the chain DB.
+The imported range is kept by receipt pruning (--prune.r.*), it can't be re-generated locally.
+
+State is read from --legacy.state into a separate DB (<datadir>/legacystate), with the
+history needed by eth_getBalance, eth_getTransactionCount, eth_getCode and eth_getStorageAt at
+pre-Bedrock blocks. It's written by this command only, the node opens it read-only and never
+prunes it. An interrupted state import continues where it stopped.`,
+}
+
+func importLegacy(cliCtx *cli.Context) error {
+	if cliCtx.NArg() < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	logger, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+	ctx := cliCtx.Context
+	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
+
+	db := dbCfg(kv.ChainDB, dirs.Chaindata).MustOpen()
+	defer db.Close()
+
+	chainConfig := fromdb.ChainConfig(db)
+	if chainConfig == nil || chainConfig.BedrockBlock == nil || chainConfig.BedrockBlock.Sign() == 0 {
+		return errors.New("chain config has no pre-Bedrock history, run `erigon init` for an OP chain with bedrockBlock set first")
+	}
+
+	cfg := ethconfig.NewSnapCfg(true, false, true)
+	blockSnaps := freezeblocks.NewRoSnapshots(cfg, dirs.Snap, 0, logger)
+	if err = blockSnaps.ReopenFolder(); err != nil {
+		return err
+	}
+	defer blockSnaps.Close()
+	borSnaps := freezeblocks.NewBorRoSnapshots(cfg, dirs.Snap, 0, logger)
+	if err = borSnaps.ReopenFolder(); err != nil {
+		return err
+	}
+	defer borSnaps.Close()
+	blockReader := freezeblocks.NewBlockReader(blockSnaps, borSnaps)
+
+	var resolveSender LegacySenderResolver
+	if url := cliCtx.String(utils.RollupHistoricalRPCFlag.Name); url != "" {
+		timeout, err := time.ParseDuration(cliCtx.String(utils.RollupHistoricalRPCTimeoutFlag.Name))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", utils.RollupHistoricalRPCTimeoutFlag.Name, err)
+		}
+		dialCtx, cancel := context.WithTimeout(ctx, timeout)
+		client, err := rpc.DialContext(dialCtx, url, logger)
+		cancel()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		resolveSender = HistoricalSenderResolver(client, timeout)
+	}
+
+	last, err := ImportLegacyBlocks(ctx, db, chainConfig, blockReader, resolveSender, cliCtx.Args().First(), logger)
+	if err != nil {
+		return err
+	}
+	if fn := cliCtx.String(LegacyReceiptsFlag.Name); fn != "" {
+		if err := ImportLegacyReceipts(ctx, db, chainConfig, blockReader, dirs, fn, logger); err != nil {
+			return err
+		}
+	}
+	if fn := cliCtx.String(LegacyStateFlag.Name); fn != "" {
+		if err := ImportLegacyState(ctx, dirs, chainConfig, fn, logger); err != nil {
+			return err
+		}
+	}
+	return FreezeLegacyBlocks(ctx, db, chainConfig, blockReader, dirs, last, logger)
+}
+
+func openRLPStream(fn string) (*rlp.Stream, func(), error) {
+	fh, err := os.Open(fn)
+	if err != nil {
+		return nil, nil, err
+	}
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			fh.Close()
+			return nil, nil, err
+		}
+	}
+	return rlp.NewStream(reader, 0), func() { fh.Close() }, nil
+}
+
+// LegacySenderResolver returns the sender of a pre-Bedrock transaction whose signature can't be recovered
+type LegacySenderResolver func(ctx context.Context, txn types.Transaction) (libcommon.Address, error)
+
+// HistoricalSenderResolver resolves senders through the l2geth node of the export. Enqueued transactions
+// are sent by their L1 message sender, which l2geth keeps in the transaction meta.
+func HistoricalSenderResolver(client *rpc.Client, timeout time.Duration) LegacySenderResolver {
+	return func(ctx context.Context, txn types.Transaction) (libcommon.Address, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		var res *struct {
+			From            *libcommon.Address `json:"from"`
+			QueueOrigin     string             `json:"queueOrigin"`
+			L1MessageSender *libcommon.Address `json:"l1MessageSender"`
+		}
+		if err := client.CallContext(ctx, &res, "eth_getTransactionByHash", txn.Hash()); err != nil {
+			return libcommon.Address{}, err
+		}
+		switch {
+		case res == nil:
+			return libcommon.Address{}, fmt.Errorf("transaction %x is unknown to the historical backend", txn.Hash())
+		case res.QueueOrigin == "l1" && res.L1MessageSender != nil:
+			return *res.L1MessageSender, nil
+		case res.From != nil:
+			return *res.From, nil
+		}
+		return libcommon.Address{}, fmt.Errorf("historical backend has no sender of transaction %x", txn.Hash())
+	}
+}
+
+// ImportLegacyBlocks writes pre-Bedrock blocks of an RLP export into the chain DB: headers, bodies,
+// canonical markers, total difficulty, senders and tx lookup entries. Blocks which are already known are
+// not overwritten. Senders which can't be recovered from the signature are resolved by resolveSender,
+// the import fails on such a transaction if it is nil. Returns the number of the last block of the export.
+func ImportLegacyBlocks(ctx context.Context, db kv.RwDB, chainConfig *chain.Config, blockReader services.FullBlockReader, resolveSender LegacySenderResolver, fn string, logger log.Logger) (uint64, error) {
+	logger.Info("Importing legacy blocks", "file", fn)
+	stream, closeFn, err := openRLPStream(fn)
+	if err != nil {
+		return 0, err
+	}
+	defer closeFn()
+
+	bedrock := chainConfig.BedrockBlock.Uint64()
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	var imported, skipped, last uint64
+	for done := false; !done; {
+		if err := db.Update(ctx, func(tx kv.RwTx) error {
+			for i := 0; i < importBatchSize; i++ {
+				var b types.Block
+				if err := stream.Decode(&b); errors.Is(err, io.EOF) {
+					done = true
+					return nil
+				} else if err != nil {
+					return fmt.Errorf("at block %d: %w", imported+skipped, err)
+				}
+				num := b.NumberU64()
+				if num >= bedrock {
+					return fmt.Errorf("block %d is not a pre-Bedrock block, bedrock starts at %d", num, bedrock)
+				}
+				if num == 0 {
+					continue
+				}
+				written, err := writeLegacyBlock(ctx, tx, chainConfig, blockReader, resolveSender, &b)
+				if err != nil {
+					return fmt.Errorf("at block %d: %w", num, err)
+				}
+				if written {
+					imported++
+				} else {
+					skipped++
+				}
+				last = num
+
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-logEvery.C:
+					logger.Info("[import-legacy] blocks", "number", num, "imported", imported, "skipped", skipped)
+				default:
+				}
+			}
+			return nil
+		}); err != nil {
+			return 0, err
+		}
+	}
+	logger.Info("[import-legacy] blocks done", "imported", imported, "skipped", skipped)
+	return last, nil
+}
+
+func writeLegacyBlock(ctx context.Context, tx kv.RwTx, chainConfig *chain.Config, blockReader services.FullBlockReader, resolveSender LegacySenderResolver, b *types.Block) (bool, error) {
+	num, hash := b.NumberU64(), b.Hash()
+	existing, err := blockReader.HeaderByNumber(ctx, tx, num)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		if existing.Hash() != hash {
+			return false, fmt.Errorf("conflicting header: have %x, importing %x", existing.Hash(), hash)
+		}
+		// tx lookup entries are kept in the DB even for frozen blocks
+		rawdb.WriteTxLookupEntries(tx, b)
+		return false, nil
+	}
+
+	parentTd, err := rawdb.ReadTd(tx, b.ParentHash(), num-1)
+	if err != nil {
+		return false, err
+	}
+	if parentTd == nil {
+		return false, fmt.Errorf("parent %x is unknown, import blocks in order", b.ParentHash())
+	}
+
+	// enqueued (L1 to L2) txs carry no signature, their senders come from the legacy node
+	signer := types.MakeSigner(chainConfig, num, b.Time())
+	senders := make([]libcommon.Address, 0, len(b.Transactions()))
+	for _, txn := range b.Transactions() {
+		sender, err := signer.Sender(txn)
+		if err != nil {
+			if resolveSender == nil {
+				return false, fmt.Errorf("sender of transaction %x: %w, set --%s to resolve it", txn.Hash(), err, utils.RollupHistoricalRPCFlag.Name)
+			}
+			if sender, err = resolveSender(ctx, txn); err != nil {
+				return false, fmt.Errorf("sender of transaction %x: %w", txn.Hash(), err)
+			}
+		}
+		senders = append(senders, sender)
+	}
+
+	if err := rawdb.WriteHeader(tx, b.HeaderNoCopy()); err != nil {
+		return false, err
+	}
+	if err := rawdb.WriteCanonicalHash(tx, hash, num); err != nil {
+		return false, err
+	}
+	if err := rawdb.WriteTd(tx, hash, num, parentTd.Add(parentTd, b.Difficulty())); err != nil {
+		return false, err
+	}
+	if err := rawdb.WriteBody(tx, hash, num, b.Body()); err != nil {
+		return false, err
+	}
+	if err := rawdb.WriteSenders(tx, hash, num, senders); err != nil {
+		return false, err
+	}
+	rawdb.WriteTxLookupEntries(tx, b)
+	return true, nil
+}
+
+// FreezeLegacyBlocks moves imported pre-Bedrock blocks up to last into snapshot segments and prunes them
+// from the chain DB. Segments are built in steps of 1000 blocks, the remainder stays in the DB.
+func FreezeLegacyBlocks(ctx context.Context, db kv.RwDB, chainConfig *chain.Config, blockReader *freezeblocks.BlockReader, dirs datadir.Dirs, last uint64, logger log.Logger) error {
+	blockWriter := blockio.NewBlockWriter(fromdb.HistV3(db))
+	br := freezeblocks.NewBlockRetire(estimate.CompressSnapshot.Workers(), dirs, blockReader, blockWriter, db, chainConfig, nil, logger)
+	if err := br.BuildMissedIndicesIfNeed(ctx, "import-legacy", nil, chainConfig); err != nil {
+		return err
+	}
+	// legacy blocks are final: retire everything imported instead of keeping the immutability threshold
+	if err := br.RetireBlocks(ctx, 0, last+params.FullImmutabilityThreshold, log.LvlInfo, nil, nil); err != nil {
+		return err
+	}
+	frozen := blockReader.FrozenBlocks()
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		_, histFiles, err := rawdb.ReadSnapshots(tx)
+		if err != nil {
+			return err
+		}
+		return rawdb.WriteSnapshots(tx, blockReader.FrozenFiles(), histFiles)
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("[import-legacy] pruning frozen blocks", "to", frozen)
+	for done := false; !done; {
+		if err := db.Update(ctx, func(tx kv.RwTx) error {
+			first, ok, err := rawdb.ReadFirstNonGenesisHeaderNumber(tx)
+			if err != nil {
+				return err
+			}
+			if !ok || first > frozen {
+				done = true
+				return nil
+			}
+			return blockWriter.PruneBlocks(ctx, tx, frozen+1, importBatchSize)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportLegacyReceipts writes pre-Bedrock receipts of an export into the chain DB and builds
+// the log index over the imported range. Every entry is checked against the receipts root of
+// its (already imported) block. Entries must be in block order, blocks without transactions may
+// be left out. The imported range is recorded once its logs are indexed, see rawdb.ReadLegacyReceiptsRange.
+func ImportLegacyReceipts(ctx context.Context, db kv.RwDB, chainConfig *chain.Config, blockReader services.FullBlockReader, dirs datadir.Dirs, fn string, logger log.Logger) error {
+	logger.Info("Importing legacy receipts", "file", fn)
+	stream, closeFn, err := openRLPStream(fn)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	bedrock := chainConfig.BedrockBlock.Uint64()
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	var from, to, imported uint64
+	for done := false; !done; {
+		if err := db.Update(ctx, func(tx kv.RwTx) error {
+			for i := 0; i < importBatchSize; i++ {
+				var entry types.LegacyBlockReceipts
+				if err := stream.Decode(&entry); errors.Is(err, io.EOF) {
+					done = true
+					return nil
+				} else if err != nil {
+					return fmt.Errorf("after block %d: %w", to, err)
+				}
+				if entry.Number >= bedrock {
+					return fmt.Errorf("receipts of block %d are not pre-Bedrock, bedrock starts at %d", entry.Number, bedrock)
+				}
+				if imported > 0 && entry.Number != to+1 {
+					if entry.Number <= to {
+						return fmt.Errorf("receipts of block %d are out of order, after block %d", entry.Number, to)
+					}
+					empty, err := legacyBlocksWithoutReceipts(ctx, tx, blockReader, to, entry.Number)
+					if err != nil {
+						return err
+					}
+					if !empty {
+						return fmt.Errorf("receipts of blocks between %d and %d are missing", to, entry.Number)
+					}
+				}
+				header, err := blockReader.HeaderByNumber(ctx, tx, entry.Number)
+				if err != nil {
+					return err
+				}
+				if header == nil {
+					return fmt.Errorf("block %d is unknown, import legacy blocks first", entry.Number)
+				}
+				receipts := entry.ToReceipts()
+				if root := types.DeriveSha(receipts); root != header.ReceiptHash {
+					return fmt.Errorf("receipts root mismatch at block %d: have %x, want %x", entry.Number, root, header.ReceiptHash)
+				}
+				if err := rawdb.WriteReceipts(tx, entry.Number, receipts); err != nil {
+					return err
+				}
+				if imported == 0 {
+					from = entry.Number
+				}
+				to = entry.Number
+				imported++
+
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-logEvery.C:
+					logger.Info("[import-legacy] receipts", "number", entry.Number, "imported", imported)
+				default:
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	if imported == 0 {
+		return nil
+	}
+
+	logger.Info("[import-legacy] indexing logs", "from", from, "to", to)
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		cfg := stagedsync.StageLogIndexCfg(db, ethconfig.Defaults.Prune, dirs.Tmp, nil, nil)
+		if err := stagedsync.IndexLogsRange("import-legacy", tx, from, to, cfg, ctx, logger); err != nil {
+			return err
+		}
+		return mergeLegacyReceiptsRange(ctx, tx, blockReader, from, to)
+	}); err != nil {
+		return err
+	}
+	logger.Info("[import-legacy] receipts done", "imported", imported)
+	return nil
+}
+
+// mergeLegacyReceiptsRange adds the imported blocks [from, to] to the recorded range of imported receipts.
+// Ranges which neither overlap nor are separated by blocks without transactions only can't be merged,
+// the larger one is kept then.
+func mergeLegacyReceiptsRange(ctx context.Context, tx kv.RwTx, blockReader services.FullBlockReader, from, to uint64) error {
+	prevFrom, prevTo, ok, err := rawdb.ReadLegacyReceiptsRange(tx)
+	if err != nil {
+		return err
+	}
+	if ok {
+		lowFrom, lowTo, highFrom, highTo := prevFrom, prevTo, from, to
+		if from < prevFrom {
+			lowFrom, lowTo, highFrom, highTo = from, to, prevFrom, prevTo
+		}
+		adjacent := highFrom <= lowTo+1
+		if !adjacent {
+			if adjacent, err = legacyBlocksWithoutReceipts(ctx, tx, blockReader, lowTo, highFrom); err != nil {
+				return err
+			}
+		}
+		switch {
+		case adjacent:
+			from, to = lowFrom, max(lowTo, highTo)
+		case prevTo-prevFrom >= to-from:
+			return nil
+		}
+	}
+	return rawdb.WriteLegacyReceiptsRange(tx, from, to)
+}
+
+// legacyBlocksWithoutReceipts - whether the blocks strictly between after and before have no transactions
+func legacyBlocksWithoutReceipts(ctx context.Context, tx kv.Tx, blockReader services.FullBlockReader, after, before uint64) (bool, error) {
+	for n := after + 1; n < before; n++ {
+		header, err := blockReader.HeaderByNumber(ctx, tx, n)
+		if err != nil || header == nil {
+			return false, err
+		}
+		if header.ReceiptHash != types.EmptyRootHash {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ImportLegacyState writes the pre-Bedrock state of an export into the legacy state DB (dirs.LegacyState),
+// with the changesets and history indices needed to read the state after any imported block. The export
+// holds one diff per block starting at block 0, diffs of blocks which are already imported are skipped.
+func ImportLegacyState(ctx context.Context, dirs datadir.Dirs, chainConfig *chain.Config, fn string, logger log.Logger) error {
+	logger.Info("Importing legacy state", "file", fn)
+	stream, closeFn, err := openRLPStream(fn)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	db, err := mdbx.NewMDBX(logger).Path(dirs.LegacyState).Label(kv.ChainDB).Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	bedrock := chainConfig.BedrockBlock.Uint64()
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	var imported uint64
+	for done := false; !done; {
+		if err := db.Update(ctx, func(tx kv.RwTx) error {
+			next, err := rawdb.ReadLegacyStateBlocks(tx)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < importBatchSize; i++ {
+				var diff types.LegacyStateDiff
+				if err := stream.Decode(&diff); errors.Is(err, io.EOF) {
+					done = true
+					break
+				} else if err != nil {
+					return fmt.Errorf("after block %d: %w", next, err)
+				}
+				if diff.Number >= bedrock {
+					return fmt.Errorf("state of block %d is not pre-Bedrock, bedrock starts at %d", diff.Number, bedrock)
+				}
+				if diff.Number < next {
+					continue
+				}
+				if diff.Number > next {
+					return fmt.Errorf("state of block %d is missing, got block %d", next, diff.Number)
+				}
+				if err := writeLegacyStateDiff(tx, &diff); err != nil {
+					return fmt.Errorf("at block %d: %w", diff.Number, err)
+				}
+				next++
+				imported++
+
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-logEvery.C:
+					logger.Info("[import-legacy] state", "number", diff.Number, "imported", imported)
+				default:
+				}
+			}
+			return rawdb.WriteLegacyStateBlocks(tx, next)
+		}); err != nil {
+			return err
+		}
+	}
+	logger.Info("[import-legacy] state done", "imported", imported)
+	return nil
+}
+
+func writeLegacyStateDiff(tx kv.RwTx, diff *types.LegacyStateDiff) error {
+	reader := state.NewPlainStateReader(tx)
+	w := state.NewPlainStateWriter(tx, tx, diff.Number)
+	for i := range diff.Accounts {
+		a := &diff.Accounts[i]
+		original, err := reader.ReadAccountData(a.Address)
+		if err != nil {
+			return err
+		}
+		if a.Deleted {
+			if original != nil {
+				if err := w.DeleteAccount(a.Address, original); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		account := accounts.NewAccount()
+		if original != nil {
+			account = *original
+		} else {
+			original = &accounts.Account{}
+		}
+		account.Initialised = true
+		account.Nonce = a.Nonce
+		account.Balance.Clear()
+		if a.Balance != nil {
+			account.Balance.Set(a.Balance)
+		}
+		if account.Incarnation == 0 && (len(a.Code) > 0 || len(a.Storage) > 0) {
+			// a contract (re)created at this address continues after the incarnation of the deleted one
+			prev, err := reader.ReadAccountIncarnation(a.Address)
+			if err != nil {
+				return err
+			}
+			account.Incarnation = prev + 1
+		}
+		if len(a.Code) > 0 {
+			account.CodeHash = crypto.Keccak256Hash(a.Code)
+			if err := w.UpdateAccountCode(a.Address, account.Incarnation, account.CodeHash, a.Code); err != nil {
+				return err
+			}
+		}
+		for j := range a.Storage {
+			key := a.Storage[j].Key
+			enc, err := reader.ReadAccountStorage(a.Address, account.Incarnation, &key)
+			if err != nil {
+				return err
+			}
+			prev, value := new(uint256.Int).SetBytes(enc), new(uint256.Int).SetBytes(a.Storage[j].Value[:])
+			if err := w.WriteAccountStorage(a.Address, account.Incarnation, &key, prev, value); err != nil {
+				return err
+			}
+		}
+		// storage first: the changeset of an account changed only by its storage is written on update
+		if err := w.UpdateAccountData(a.Address, original, &account); err != nil {
+			return err
+		}
+	}
+	if err := w.WriteChangeSets(); err != nil {
+		return err
+	}
+	return w.WriteHistory()
+}
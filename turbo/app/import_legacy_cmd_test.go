@@ -0,0 +1,242 @@
+package app
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+
+	"github.com/ledgerwatch/erigon/common/u256"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+// newLegacyTestMock returns a mock of an OP chain whose first 100 blocks are pre-Bedrock
+func newLegacyTestMock(t *testing.T) *mock.MockSentry {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	gspec := &types.Genesis{
+		Config: &chain.Config{
+			ChainID:               big.NewInt(1337),
+			Consensus:             chain.EtHashConsensus,
+			HomesteadBlock:        big.NewInt(0),
+			TangerineWhistleBlock: big.NewInt(0),
+			SpuriousDragonBlock:   big.NewInt(0),
+			ByzantiumBlock:        big.NewInt(0),
+			ConstantinopleBlock:   big.NewInt(0),
+			PetersburgBlock:       big.NewInt(0),
+			IstanbulBlock:         big.NewInt(0),
+			MuirGlacierBlock:      big.NewInt(0),
+			BerlinBlock:           big.NewInt(0),
+			Ethash:                new(chain.EthashConfig),
+			Optimism:              &chain.OptimismConfig{EIP1559Elasticity: 8, EIP1559Denominator: 1},
+			BedrockBlock:          big.NewInt(100),
+		},
+		Alloc:    types.GenesisAlloc{crypto.PubkeyToAddress(key.PublicKey): {Balance: big.NewInt(1e18)}},
+		GasLimit: 10_000_000,
+	}
+	return mock.MockWithGenesis(t, gspec, key, false)
+}
+
+func writeRLPFile(t *testing.T, name string, items ...interface{}) string {
+	fn := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(fn)
+	require.NoError(t, err)
+	defer f.Close()
+	for _, item := range items {
+		require.NoError(t, rlp.Encode(f, item))
+	}
+	return fn
+}
+
+// generateLegacyBlocks returns 2 blocks with signed transfers and a third one with an enqueued
+// (unsigned) transaction.
+func generateLegacyBlocks(t *testing.T, m *mock.MockSentry, to libcommon.Address) []*types.Block {
+	signer := types.LatestSignerForChainID(m.ChainConfig.ChainID)
+	pack, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 2, func(i int, b *core.BlockGen) {
+		txn, err := types.SignTx(types.NewTransaction(b.TxNonce(m.Address), to, u256.Num1, 21_000, u256.Num1, nil), *signer, m.Key)
+		require.NoError(t, err)
+		b.AddTx(txn)
+	})
+	require.NoError(t, err)
+
+	parent := pack.Blocks[1]
+	header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(3), Difficulty: big.NewInt(1), GasLimit: parent.GasLimit(), Time: parent.Time() + 1}
+	enqueued := types.NewTransaction(0, to, u256.Num2, 21_000, u256.Num0, nil)
+	return append(pack.Blocks, types.NewBlock(header, types.Transactions{enqueued}, nil, nil, nil))
+}
+
+func TestImportLegacyBlocks(t *testing.T) {
+	logger := log.New()
+	ctx := context.Background()
+	src := newLegacyTestMock(t)
+	to := libcommon.HexToAddress("0x0202")
+	blocks := generateLegacyBlocks(t, src, to)
+	fn := writeRLPFile(t, "blocks.rlp", src.Genesis, blocks[0], blocks[1], blocks[2])
+
+	m := newLegacyTestMock(t)
+
+	// the sender of the enqueued tx can't be recovered without the legacy node
+	_, err := ImportLegacyBlocks(ctx, m.DB, m.ChainConfig, m.BlockReader, nil, fn, logger)
+	require.ErrorContains(t, err, "rollup.historicalrpc")
+
+	l1Sender := libcommon.HexToAddress("0x0101")
+	resolve := func(ctx context.Context, txn types.Transaction) (libcommon.Address, error) {
+		require.Equal(t, blocks[2].Transactions()[0].Hash(), txn.Hash())
+		return l1Sender, nil
+	}
+	last, err := ImportLegacyBlocks(ctx, m.DB, m.ChainConfig, m.BlockReader, resolve, fn, logger)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), last)
+
+	tx, err := m.DB.BeginRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	for _, b := range blocks {
+		hash, err := rawdb.ReadCanonicalHash(tx, b.NumberU64())
+		require.NoError(t, err)
+		require.Equal(t, b.Hash(), hash)
+		num, err := rawdb.ReadTxLookupEntry(tx, b.Transactions()[0].Hash())
+		require.NoError(t, err)
+		require.Equal(t, b.NumberU64(), *num)
+	}
+	senders, err := rawdb.ReadSenders(tx, blocks[2].Hash(), 3)
+	require.NoError(t, err)
+	require.Equal(t, []libcommon.Address{l1Sender}, senders)
+	senders, err = rawdb.ReadSenders(tx, blocks[0].Hash(), 1)
+	require.NoError(t, err)
+	require.Equal(t, []libcommon.Address{src.Address}, senders)
+	tx.Rollback()
+
+	// a block of another chain is refused, its transactions are not indexed
+	other := generateLegacyBlocks(t, src, libcommon.HexToAddress("0x0303"))
+	_, err = ImportLegacyBlocks(ctx, m.DB, m.ChainConfig, m.BlockReader, resolve, writeRLPFile(t, "other.rlp", other[0]), logger)
+	require.ErrorContains(t, err, "conflicting header")
+	require.NoError(t, m.DB.View(ctx, func(tx kv.Tx) error {
+		num, err := rawdb.ReadTxLookupEntry(tx, other[0].Transactions()[0].Hash())
+		require.Nil(t, num)
+		return err
+	}))
+}
+
+type legacyTxService struct {
+	txs map[libcommon.Hash]map[string]interface{}
+}
+
+func (s *legacyTxService) GetTransactionByHash(hash libcommon.Hash) map[string]interface{} {
+	return s.txs[hash]
+}
+
+func TestHistoricalSenderResolver(t *testing.T) {
+	enqueued := types.NewTransaction(0, libcommon.HexToAddress("0x0202"), u256.Num2, 21_000, u256.Num0, nil)
+	sequenced := types.NewTransaction(1, libcommon.HexToAddress("0x0202"), u256.Num2, 21_000, u256.Num1, nil)
+	l1Sender, from := libcommon.HexToAddress("0x0101"), libcommon.HexToAddress("0x0404")
+
+	srv := rpc.NewServer(50, false, false, true, log.New(), 0)
+	require.NoError(t, srv.RegisterName("eth", &legacyTxService{txs: map[libcommon.Hash]map[string]interface{}{
+		enqueued.Hash():  {"from": "0x0000000000000000000000000000000000000000", "queueOrigin": "l1", "l1MessageSender": l1Sender},
+		sequenced.Hash(): {"from": from, "queueOrigin": "sequencer", "l1MessageSender": nil},
+	}}))
+	client := rpc.DialInProc(srv, log.New())
+	defer client.Close()
+	resolve := HistoricalSenderResolver(client, time.Second)
+
+	sender, err := resolve(context.Background(), enqueued)
+	require.NoError(t, err)
+	require.Equal(t, l1Sender, sender)
+	sender, err = resolve(context.Background(), sequenced)
+	require.NoError(t, err)
+	require.Equal(t, from, sender)
+	_, err = resolve(context.Background(), types.NewTransaction(2, libcommon.Address{}, u256.Num0, 21_000, u256.Num0, nil))
+	require.ErrorContains(t, err, "unknown to the historical backend")
+}
+
+func TestImportLegacyState(t *testing.T) {
+	logger := log.New()
+	ctx := context.Background()
+	dirs := datadir.New(t.TempDir())
+	chainConfig := &chain.Config{BedrockBlock: big.NewInt(100)}
+	eoa, contract := libcommon.HexToAddress("0x0101"), libcommon.HexToAddress("0x0202")
+	slot, code := libcommon.Hash{0x01}, []byte{0x60, 0x00}
+
+	diffs := []interface{}{
+		&types.LegacyStateDiff{Number: 0, Accounts: []types.LegacyAccountDiff{
+			{Address: eoa, Balance: uint256.NewInt(1)},
+		}},
+		&types.LegacyStateDiff{Number: 1, Accounts: []types.LegacyAccountDiff{
+			{Address: eoa, Nonce: 1, Balance: uint256.NewInt(2)},
+			{Address: contract, Nonce: 1, Balance: uint256.NewInt(0), Code: code, Storage: []types.LegacyStorageDiff{{Key: slot, Value: libcommon.Hash{31: 5}}}},
+		}},
+		&types.LegacyStateDiff{Number: 2, Accounts: []types.LegacyAccountDiff{
+			{Address: eoa, Deleted: true},
+			{Address: contract, Nonce: 1, Balance: uint256.NewInt(0), Storage: []types.LegacyStorageDiff{{Key: slot}}},
+		}},
+	}
+	fn := writeRLPFile(t, "state.rlp", diffs...)
+	require.NoError(t, ImportLegacyState(ctx, dirs, chainConfig, fn, logger))
+	// resuming skips the imported blocks
+	require.NoError(t, ImportLegacyState(ctx, dirs, chainConfig, fn, logger))
+	require.ErrorContains(t, ImportLegacyState(ctx, dirs, chainConfig, writeRLPFile(t, "gap.rlp", &types.LegacyStateDiff{Number: 5}), logger), "state of block 3 is missing")
+
+	db := mdbx.NewMDBX(logger).Path(dirs.LegacyState).Label(kv.ChainDB).MustOpen()
+	defer db.Close()
+	tx, err := db.BeginRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	blocks, err := rawdb.ReadLegacyStateBlocks(tx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), blocks)
+
+	type expected struct {
+		eoaBalance, eoaNonce uint64
+		eoaDeleted           bool
+		contract             bool
+		slot                 uint64
+	}
+	for n, want := range []expected{
+		{eoaBalance: 1},
+		{eoaBalance: 2, eoaNonce: 1, contract: true, slot: 5},
+		{eoaDeleted: true, contract: true},
+	} {
+		reader := state.NewPlainState(tx, uint64(n)+1, nil)
+		acc, err := reader.ReadAccountData(eoa)
+		require.NoError(t, err)
+		if want.eoaDeleted {
+			require.Nil(t, acc, n)
+		} else {
+			require.Equal(t, want.eoaBalance, acc.Balance.Uint64(), n)
+			require.Equal(t, want.eoaNonce, acc.Nonce, n)
+		}
+
+		acc, err = reader.ReadAccountData(contract)
+		require.NoError(t, err)
+		if !want.contract {
+			require.Nil(t, acc, n)
+			continue
+		}
+		require.Equal(t, uint64(1), acc.Incarnation, n)
+		c, err := reader.ReadAccountCode(contract, acc.Incarnation, acc.CodeHash)
+		require.NoError(t, err)
+		require.Equal(t, code, c, n)
+		v, err := reader.ReadAccountStorage(contract, acc.Incarnation, &slot)
+		require.NoError(t, err)
+		require.Equal(t, want.slot, new(uint256.Int).SetBytes(v).Uint64(), n)
+	}
+}
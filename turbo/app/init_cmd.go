@@ -13,6 +13,7 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/utils"
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/node"
+	"github.com/ledgerwatch/erigon/params"
 )
 
 var initCommand = cli.Command{
@@ -22,6 +23,9 @@ var initCommand = cli.Command{
 	ArgsUsage: "<genesisPath>",
 	Flags: []cli.Flag{
 		&utils.DataDirFlag,
+		&opNetworkFlag,
+		&utils.SuperchainRegistryFlag,
+		&utils.SuperchainRegistrySHA256Flag,
 	},
 	//Category: "BLOCKCHAIN COMMANDS",
 	Description: `
@@ -29,7 +33,13 @@ The init command initializes a new genesis block and definition for the network.
 This is a destructive action and changes the network in which you will be
 participating.
 
-It expects the genesis file as argument.`,
+It expects the genesis file as argument, or the name of an OP stack chain of the
+superchain registry given by --op-network.`,
+}
+
+var opNetworkFlag = cli.StringFlag{
+	Name:  "op-network",
+	Usage: "Initialize the genesis of the named OP stack chain from the superchain registry, e.g. op-mainnet",
 }
 
 // initGenesis will initialise the given JSON format genesis file and writes it as
@@ -40,21 +50,33 @@ func initGenesis(cliCtx *cli.Context) error {
 	if logger, _, _, err = debug.Setup(cliCtx, true /* rootLogger */); err != nil {
 		return err
 	}
-	// Make sure we have a valid genesis JSON
-	genesisPath := cliCtx.Args().First()
-	if len(genesisPath) == 0 {
-		utils.Fatalf("Must supply path to genesis JSON file")
-	}
+	utils.LoadSuperchainRegistry(cliCtx, logger)
 
-	file, err := os.Open(genesisPath)
-	if err != nil {
-		utils.Fatalf("Failed to read genesis file: %v", err)
-	}
-	defer file.Close()
+	var genesis *types.Genesis
+	if network := cliCtx.String(opNetworkFlag.Name); network != "" {
+		if params.OPStackChainConfigByName(network) == nil {
+			utils.Fatalf("Unknown OP stack network: %s", network)
+		}
+		if genesis = core.GenesisBlockByChainName(network); genesis == nil {
+			utils.Fatalf("No genesis for OP stack network: %s", network)
+		}
+	} else {
+		// Make sure we have a valid genesis JSON
+		genesisPath := cliCtx.Args().First()
+		if len(genesisPath) == 0 {
+			utils.Fatalf("Must supply path to genesis JSON file or --%s", opNetworkFlag.Name)
+		}
+
+		file, err := os.Open(genesisPath)
+		if err != nil {
+			utils.Fatalf("Failed to read genesis file: %v", err)
+		}
+		defer file.Close()
 
-	genesis := new(types.Genesis)
-	if err := json.NewDecoder(file).Decode(genesis); err != nil {
-		utils.Fatalf("invalid genesis file: %v", err)
+		genesis = new(types.Genesis)
+		if err := json.NewDecoder(file).Decode(genesis); err != nil {
+			utils.Fatalf("invalid genesis file: %v", err)
+		}
 	}
 
 	// Open and initialise both full and light databases
@@ -63,6 +63,7 @@ func MakeApp(name string, action cli.ActionFunc, cliFlags []cli.Flag) *cli.App {
 	app.Commands = []*cli.Command{
 		&initCommand,
 		&importCommand,
+		&importLegacyCommand,
 		&snapshotCommand,
 		&supportCommand,
 		//&backupCommand,
@@ -0,0 +1,614 @@
+// Package changefeed keeps a durable feed of per-block state diffs for external consumers.
+//
+// After every commit of the staged sync, the feed appends a FORWARD diff for each newly executed block, and an
+// UNWIND diff for each block which is no longer canonical. A diff carries both the previous and the new values of
+// every account and storage slot the block changed, so an UNWIND diff is the FORWARD diff of the same block with the
+// values swapped. Diffs get consecutive offsets and are kept in append-only segment files, whole segments are
+// deleted once all their blocks are older than the retention. Consumers persist the offset of the last diff they
+// processed and resume from the next one.
+//
+// The feed is reconciled with the database instead of with the in-memory state stream: diffs are computed from
+// the changesets of the committed blocks, so a node restart or a crash between a commit and an append doesn't lose
+// any block.
+package changefeed
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/ledgerwatch/log/v3"
+	"google.golang.org/protobuf/proto"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/temporal/historyv2"
+
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+var (
+	ErrOffsetNotRetained = errors.New("offset is no longer retained")
+	ErrOffsetAhead       = errors.New("offset is ahead of the feed")
+	ErrBlockNotRetained  = errors.New("block is not retained")
+	ErrClosed            = errors.New("change feed is closed")
+)
+
+// readBatch is the max amount of diffs read from the segments under one lock
+const readBatch = 64
+
+// position is the offset of the FORWARD diff which led the feed to the block
+type position struct {
+	block  uint64
+	hash   libcommon.Hash
+	offset uint64
+}
+
+type Feed struct {
+	dir         string
+	retention   uint64 // blocks to keep the diffs for
+	segmentSize int64
+	blockReader services.HeaderAndCanonicalReader
+	logger      log.Logger
+
+	syncLock sync.Mutex // serializes Sync
+	head     position   // block the appended diffs lead to, zero before the first Sync of an empty feed
+
+	lock      sync.RWMutex
+	segments  []*segment
+	next      uint64     // offset of the next appended diff
+	durable   uint64     // offset of the first diff which is not synced to disk yet, only earlier diffs are readable
+	durableAt position   // block the durable diffs lead to
+	live      []position // FORWARD diffs which were not reverted, in ascending order of blocks
+	newDiffs  chan struct{}
+	syncReq   chan struct{} // pending Notify, buffered by one so the requests of the sync loop never pile up
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+// Open indexes the segments in dir, the diffs of blocks older than retention blocks are deleted as the feed grows
+func Open(dir string, retention uint64, segmentSize datasize.ByteSize, blockReader services.HeaderAndCanonicalReader, logger log.Logger) (*Feed, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f := &Feed{
+		dir:         dir,
+		retention:   retention,
+		segmentSize: int64(segmentSize.Bytes()),
+		blockReader: blockReader,
+		logger:      logger,
+		newDiffs:    make(chan struct{}),
+		syncReq:     make(chan struct{}, 1),
+		quit:        make(chan struct{}),
+	}
+	firsts, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i, first := range firsts {
+		if i > 0 && first != f.next {
+			f.close()
+			return nil, fmt.Errorf("change feed segments are not contiguous: diffs %d-%d are missing", f.next, first-1)
+		}
+		f.next = first
+		s, err := openSegment(dir, first, i == len(firsts)-1, func(h frameHeader) {
+			f.index(h, f.next)
+			f.next++
+		})
+		if err != nil {
+			f.close()
+			return nil, err
+		}
+		f.segments = append(f.segments, s)
+	}
+	f.durable = f.next
+	if len(f.segments) > 0 && f.next > f.segments[0].first {
+		last, err := f.read(f.next - 1)
+		if err != nil {
+			f.close()
+			return nil, err
+		}
+		f.head = headAfter(last)
+		f.durableAt = f.head
+		logger.Info("[changefeed] opened", "diffs", f.next-f.segments[0].first, "next", f.next, "block", f.head.block)
+	}
+	return f, nil
+}
+
+// index tracks the blocks the diffs lead to, so the FORWARD diff of a block can be found when it has to be reverted
+func (f *Feed) index(h frameHeader, offset uint64) {
+	if h.direction == remote.Direction_FORWARD {
+		f.live = append(f.live, position{block: h.block, hash: h.hash, offset: offset})
+		return
+	}
+	if n := len(f.live); n > 0 && f.live[n-1].block == h.block {
+		f.live = f.live[:n-1]
+	}
+}
+
+// headAfter returns the block the feed leads to after the diff
+func headAfter(d *remote.BlockDiff) position {
+	if d.Direction == remote.Direction_UNWIND {
+		return position{block: d.BlockNumber - 1, hash: gointerfaces.ConvertH256ToHash(d.ParentHash)}
+	}
+	return position{block: d.BlockNumber, hash: gointerfaces.ConvertH256ToHash(d.BlockHash), offset: d.Offset}
+}
+
+// Sync appends the diffs which lead the feed from its head to the head of the database: UNWIND diffs for the blocks
+// which are no longer canonical, then FORWARD diffs for the executed blocks. It must be called with a transaction
+// which sees the committed sync progress.
+func (f *Feed) Sync(ctx context.Context, tx kv.Tx) error {
+	f.syncLock.Lock()
+	defer f.syncLock.Unlock()
+	select {
+	case <-f.quit:
+		return ErrClosed
+	default:
+	}
+
+	to, err := syncedTo(tx)
+	if err != nil {
+		return err
+	}
+	if f.head.hash == (libcommon.Hash{}) {
+		hash, err := f.blockReader.CanonicalHash(ctx, tx, to)
+		if err != nil {
+			return err
+		}
+		f.head = position{block: to, hash: hash}
+		f.logger.Info("[changefeed] started", "block", to)
+		return f.publish()
+	}
+
+	for {
+		canonical, err := f.canonical(ctx, tx, f.head, to)
+		if err != nil {
+			return err
+		}
+		if canonical {
+			break
+		}
+		if err := f.revert(f.head); err != nil {
+			return err
+		}
+	}
+	if f.head.block < to {
+		if err := checkChangesets(tx, f.head.block+1); err != nil {
+			return err
+		}
+	}
+	for n := f.head.block + 1; n <= to; n++ {
+		select {
+		case <-ctx.Done():
+			return errors.Join(ctx.Err(), f.publish())
+		default:
+		}
+		d, err := f.blockDiff(ctx, tx, n)
+		if err != nil {
+			return errors.Join(err, f.publish())
+		}
+		if err := f.append(d); err != nil {
+			return errors.Join(err, f.publish())
+		}
+	}
+	return f.publish()
+}
+
+// Notify asks the Run loop to sync the feed, without waiting for it. Requests made while a sync is pending are
+// merged into it, since every sync catches up to the committed head of the database.
+func (f *Feed) Notify() {
+	select {
+	case f.syncReq <- struct{}{}:
+	default:
+	}
+}
+
+// Run syncs the feed with read transactions of db on every Notify, until ctx is done or the feed is closed.
+// A failed sync is only logged: the feed catches up from the changesets on the next one.
+func (f *Feed) Run(ctx context.Context, db kv.RoDB) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.quit:
+			return
+		case <-f.syncReq:
+		}
+		if err := db.View(ctx, func(tx kv.Tx) error { return f.Sync(ctx, tx) }); err != nil {
+			if errors.Is(err, ErrClosed) || ctx.Err() != nil {
+				return
+			}
+			f.logger.Warn("[changefeed] sync failed", "err", err)
+		}
+	}
+}
+
+// syncedTo returns the last block which state and history indices are committed
+func syncedTo(tx kv.Tx) (uint64, error) {
+	to := uint64(math.MaxUint64)
+	for _, stage := range []stages.SyncStage{stages.Execution, stages.AccountHistoryIndex, stages.StorageHistoryIndex} {
+		progress, err := stages.GetStageProgress(tx, stage)
+		if err != nil {
+			return 0, err
+		}
+		to = min(to, progress)
+	}
+	return to, nil
+}
+
+// checkChangesets fails if the history was pruned past the block, as its diff can't be computed anymore
+func checkChangesets(tx kv.Tx, from uint64) error {
+	available, err := historyv2.AvailableFrom(tx)
+	if err != nil {
+		return err
+	}
+	if available != math.MaxUint64 && available > from {
+		return fmt.Errorf("changesets of block %d are pruned, the change feed can't catch up", from)
+	}
+	return nil
+}
+
+func (f *Feed) canonical(ctx context.Context, tx kv.Tx, p position, to uint64) (bool, error) {
+	if p.block > to {
+		return false, nil
+	}
+	hash, err := f.blockReader.CanonicalHash(ctx, tx, p.block)
+	if err != nil {
+		return false, err
+	}
+	return hash == p.hash, nil
+}
+
+// revert appends the UNWIND diff of the head block
+func (f *Feed) revert(head position) error {
+	f.lock.RLock()
+	n := len(f.live)
+	var forward position
+	if n > 0 {
+		forward = f.live[n-1]
+	}
+	f.lock.RUnlock()
+	if n == 0 || forward.block != head.block || forward.hash != head.hash {
+		return fmt.Errorf("%w: can't revert block %d %x", ErrBlockNotRetained, head.block, head.hash)
+	}
+	d, err := f.read(forward.offset)
+	if err != nil {
+		return err
+	}
+	return f.append(invert(d))
+}
+
+// invert turns the FORWARD diff of a block into the UNWIND diff which reverts it
+func invert(d *remote.BlockDiff) *remote.BlockDiff {
+	d.Direction = remote.Direction_UNWIND
+	for _, a := range d.Accounts {
+		a.Before, a.After = a.After, a.Before
+		a.BeforeCode, a.AfterCode = a.AfterCode, a.BeforeCode
+		for _, s := range a.Storage {
+			s.Before, s.After = s.After, s.Before
+		}
+	}
+	return d
+}
+
+// blockDiff computes the FORWARD diff of the canonical block from its changesets: the previous values are read as
+// of the beginning of the block and the new values as of the beginning of the next block
+func (f *Feed) blockDiff(ctx context.Context, tx kv.Tx, n uint64) (*remote.BlockDiff, error) {
+	header, err := f.blockReader.HeaderByNumber(ctx, tx, n)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("canonical header %d not found", n)
+	}
+	d := &remote.BlockDiff{
+		Direction:   remote.Direction_FORWARD,
+		BlockNumber: n,
+		BlockHash:   gointerfaces.ConvertHashToH256(header.Hash()),
+		ParentHash:  gointerfaces.ConvertHashToH256(header.ParentHash),
+	}
+	before, after := state.NewPlainState(tx, n, nil), state.NewPlainState(tx, n+1, nil)
+	diffs := map[libcommon.Address]*remote.AccountDiff{}
+	accountDiff := func(address libcommon.Address) (*remote.AccountDiff, error) {
+		if a, ok := diffs[address]; ok {
+			return a, nil
+		}
+		b, err := before.ReadAccountData(address)
+		if err != nil {
+			return nil, err
+		}
+		a, err := after.ReadAccountData(address)
+		if err != nil {
+			return nil, err
+		}
+		diff := &remote.AccountDiff{Address: gointerfaces.ConvertAddressToH160(address), Before: accountState(b), After: accountState(a)}
+		if b != nil && a != nil && b.CodeHash == a.CodeHash {
+			diffs[address] = diff
+			return diff, nil
+		}
+		if b != nil && !b.IsEmptyCodeHash() {
+			if diff.BeforeCode, err = before.ReadAccountCode(address, b.Incarnation, b.CodeHash); err != nil {
+				return nil, err
+			}
+		}
+		if a != nil && !a.IsEmptyCodeHash() {
+			if diff.AfterCode, err = after.ReadAccountCode(address, a.Incarnation, a.CodeHash); err != nil {
+				return nil, err
+			}
+		}
+		diffs[address] = diff
+		return diff, nil
+	}
+	prefix := hexutility.EncodeTs(n)
+	if err := historyv2.ForPrefix(tx, kv.AccountChangeSet, prefix, func(_ uint64, k, _ []byte) error {
+		_, err := accountDiff(libcommon.BytesToAddress(k))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if err := historyv2.ForPrefix(tx, kv.StorageChangeSet, prefix, func(_ uint64, k, _ []byte) error {
+		address := libcommon.BytesToAddress(k[:length.Addr])
+		incarnation := binary.BigEndian.Uint64(k[length.Addr:])
+		location := libcommon.BytesToHash(k[length.Addr+length.Incarnation:])
+		b, err := before.ReadAccountStorage(address, incarnation, &location)
+		if err != nil {
+			return err
+		}
+		a, err := after.ReadAccountStorage(address, incarnation, &location)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(b, a) {
+			return nil
+		}
+		diff, err := accountDiff(address)
+		if err != nil {
+			return err
+		}
+		diff.Storage = append(diff.Storage, &remote.StorageDiff{
+			Incarnation: incarnation,
+			Location:    gointerfaces.ConvertHashToH256(location),
+			Before:      libcommon.Copy(b),
+			After:       libcommon.Copy(a),
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, diff := range diffs {
+		if len(diff.Storage) == 0 && proto.Equal(diff.Before, diff.After) {
+			continue
+		}
+		d.Accounts = append(d.Accounts, diff)
+	}
+	sort.Slice(d.Accounts, func(i, j int) bool {
+		a, b := gointerfaces.ConvertH160toAddress(d.Accounts[i].Address), gointerfaces.ConvertH160toAddress(d.Accounts[j].Address)
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	return d, nil
+}
+
+func accountState(a *accounts.Account) *remote.AccountState {
+	if a == nil {
+		return nil
+	}
+	return &remote.AccountState{
+		Nonce:       a.Nonce,
+		Balance:     gointerfaces.ConvertUint256IntToH256(&a.Balance),
+		CodeHash:    gointerfaces.ConvertHashToH256(a.CodeHash),
+		Incarnation: a.Incarnation,
+	}
+}
+
+// append writes the diff to the last segment, a new segment is started when the last one is full
+func (f *Feed) append(d *remote.BlockDiff) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if n := len(f.segments); n == 0 || f.segments[n-1].size >= f.segmentSize {
+		if n > 0 {
+			if err := f.segments[n-1].file.Sync(); err != nil {
+				return err
+			}
+		}
+		s, err := createSegment(f.dir, f.next)
+		if err != nil {
+			return err
+		}
+		f.segments = append(f.segments, s)
+	}
+	d.Offset = f.next
+	payload, err := proto.Marshal(d)
+	if err != nil {
+		return err
+	}
+	h := frameHeader{direction: d.Direction, block: d.BlockNumber, hash: gointerfaces.ConvertH256ToHash(d.BlockHash)}
+	if err := f.segments[len(f.segments)-1].append(encodeFrame(h, payload), d.BlockNumber); err != nil {
+		return err
+	}
+	f.index(h, f.next)
+	f.next++
+	f.head = headAfter(d)
+	return nil
+}
+
+// publish syncs the appended diffs to disk, makes them readable and deletes the segments past the retention
+func (f *Feed) publish() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.durable == f.next && f.durableAt == f.head {
+		return nil
+	}
+	if n := len(f.segments); n > 0 && f.durable < f.next {
+		if err := f.segments[n-1].file.Sync(); err != nil {
+			return err
+		}
+	}
+	f.durable, f.durableAt = f.next, f.head
+	close(f.newDiffs)
+	f.newDiffs = make(chan struct{})
+
+	for len(f.segments) > 1 && f.segments[0].lastBlock+f.retention < f.head.block {
+		if err := f.segments[0].remove(f.dir); err != nil {
+			return err
+		}
+		f.segments = f.segments[1:]
+	}
+	first := f.first()
+	i := sort.Search(len(f.live), func(i int) bool { return f.live[i].offset >= first })
+	f.live = f.live[i:]
+	return nil
+}
+
+func (f *Feed) segment(offset uint64) *segment {
+	i := sort.Search(len(f.segments), func(i int) bool { return f.segments[i].next() > offset })
+	if i == len(f.segments) || f.segments[i].first > offset {
+		return nil
+	}
+	return f.segments[i]
+}
+
+func (f *Feed) read(offset uint64) (*remote.BlockDiff, error) {
+	f.lock.RLock()
+	s := f.segment(offset)
+	if s == nil {
+		f.lock.RUnlock()
+		return nil, fmt.Errorf("%w: %d", ErrOffsetNotRetained, offset)
+	}
+	payload, err := s.read(offset)
+	f.lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	d := &remote.BlockDiff{}
+	if err := proto.Unmarshal(payload, d); err != nil {
+		return nil, fmt.Errorf("decoding diff %d: %w", offset, err)
+	}
+	return d, nil
+}
+
+// Diffs calls fn for the durable diffs starting from the offset, then waits for the new ones until the context is
+// canceled, the feed is closed or fn returns an error
+func (f *Feed) Diffs(ctx context.Context, from uint64, fn func(*remote.BlockDiff) error) error {
+	for {
+		payloads, wait, err := f.readFrom(from)
+		if err != nil {
+			return err
+		}
+		for _, payload := range payloads {
+			d := &remote.BlockDiff{}
+			if err := proto.Unmarshal(payload, d); err != nil {
+				return fmt.Errorf("decoding diff %d: %w", from, err)
+			}
+			if err := fn(d); err != nil {
+				return err
+			}
+			from++
+		}
+		if len(payloads) > 0 {
+			continue
+		}
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-f.quit:
+			return ErrClosed
+		}
+	}
+}
+
+func (f *Feed) readFrom(from uint64) (payloads [][]byte, wait <-chan struct{}, err error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if first := f.first(); from < first {
+		return nil, nil, fmt.Errorf("%w: %d, the oldest retained diff is %d", ErrOffsetNotRetained, from, first)
+	}
+	if from > f.durable {
+		return nil, nil, fmt.Errorf("%w: %d, the next diff is %d", ErrOffsetAhead, from, f.durable)
+	}
+	for offset := from; offset < f.durable && len(payloads) < readBatch; offset++ {
+		payload, err := f.segment(offset).read(offset)
+		if err != nil {
+			return nil, nil, err
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads, f.newDiffs, nil
+}
+
+func (f *Feed) first() uint64 {
+	if len(f.segments) == 0 {
+		return f.durable
+	}
+	return f.segments[0].first
+}
+
+// OffsetOf returns the offset of the FORWARD diff of the canonical block, if it is retained
+func (f *Feed) OffsetOf(block uint64) (uint64, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	offset, ok := f.offsetOf(block)
+	if !ok {
+		return 0, fmt.Errorf("%w: %d", ErrBlockNotRetained, block)
+	}
+	return offset, nil
+}
+
+func (f *Feed) offsetOf(block uint64) (uint64, bool) {
+	i := sort.Search(len(f.live), func(i int) bool { return f.live[i].block >= block })
+	if i == len(f.live) || f.live[i].block != block || f.live[i].offset >= f.durable {
+		return 0, false
+	}
+	return f.live[i].offset, true
+}
+
+// Info returns the range of the retained durable diffs and the block they lead to, and looks up the offset of the
+// requested block
+func (f *Feed) Info(req *remote.ChangeFeedInfoRequest) *remote.ChangeFeedInfoReply {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	reply := &remote.ChangeFeedInfoReply{
+		FirstOffset:     f.first(),
+		NextOffset:      f.durable,
+		HeadBlockNumber: f.durableAt.block,
+		HeadBlockHash:   gointerfaces.ConvertHashToH256(f.durableAt.hash),
+	}
+	if req.BlockNumber != nil {
+		if offset, ok := f.offsetOf(*req.BlockNumber); ok {
+			reply.BlockOffset = &offset
+		}
+	}
+	return reply
+}
+
+func (f *Feed) close() {
+	for _, s := range f.segments {
+		s.file.Close()
+	}
+}
+
+// Close stops the readers and closes the segments
+func (f *Feed) Close() {
+	f.closeOnce.Do(func() {
+		close(f.quit)
+		f.syncLock.Lock()
+		defer f.syncLock.Unlock()
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		f.close()
+	})
+}
@@ -0,0 +1,237 @@
+package changefeed
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/common/u256"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+// initCode stores 42 in slot 0 and deploys a contract made of a single STOP
+var initCode = libcommon.FromHex("0x602a60005560016000f3")
+
+func generate(t *testing.T, m *mock.MockSentry, n int, forkAt int) *core.ChainPack {
+	t.Helper()
+	signer := types.LatestSignerForChainID(m.ChainConfig.ChainID)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, n, func(i int, gen *core.BlockGen) {
+		if i >= forkAt {
+			gen.SetCoinbase(libcommon.Address{0xfe})
+			return
+		}
+		tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(m.Address), libcommon.Address{byte(i + 1)}, uint256.NewInt(10_000), params.TxGas, u256.Num1, nil), *signer, m.Key)
+		require.NoError(t, err)
+		gen.AddTx(tx)
+		if i == 2 {
+			tx, err := types.SignTx(types.NewContractCreation(gen.TxNonce(m.Address), u256.Num0, 100_000, u256.Num1, initCode), *signer, m.Key)
+			require.NoError(t, err)
+			gen.AddTx(tx)
+		}
+	})
+	require.NoError(t, err)
+	return chain
+}
+
+func syncFeed(t *testing.T, m *mock.MockSentry, f *Feed) {
+	t.Helper()
+	require.NoError(t, m.DB.View(context.Background(), func(tx kv.Tx) error {
+		return f.Sync(context.Background(), tx)
+	}))
+}
+
+// collect reads n diffs starting from the offset
+func collect(t *testing.T, f *Feed, from uint64, n int) []*remote.BlockDiff {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var diffs []*remote.BlockDiff
+	err := f.Diffs(ctx, from, func(d *remote.BlockDiff) error {
+		diffs = append(diffs, d)
+		if len(diffs) == n {
+			cancel()
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	return diffs
+}
+
+func account(d *remote.BlockDiff, address libcommon.Address) *remote.AccountDiff {
+	for _, a := range d.Accounts {
+		if gointerfaces.ConvertH160toAddress(a.Address) == address {
+			return a
+		}
+	}
+	return nil
+}
+
+func TestFeedFollowsChain(t *testing.T) {
+	m := mock.Mock(t)
+	if m.HistoryV3 {
+		t.Skip("the change feed doesn't support HistoryV3")
+	}
+	chain, fork := generate(t, m, 4, 4), generate(t, m, 5, 2)
+	dir := t.TempDir()
+	f, err := Open(dir, 100, 1*datasize.KB, m.BlockReader, log.New())
+	require.NoError(t, err)
+	defer f.Close()
+
+	// an empty feed starts at the head of the database
+	syncFeed(t, m, f)
+	info := f.Info(&remote.ChangeFeedInfoRequest{})
+	require.Equal(t, uint64(0), info.NextOffset)
+	require.Equal(t, m.Genesis.Hash(), libcommon.Hash(gointerfaces.ConvertH256ToHash(info.HeadBlockHash)))
+
+	require.NoError(t, m.InsertChain(chain))
+	syncFeed(t, m, f)
+	diffs := collect(t, f, 0, 4)
+	for i, d := range diffs {
+		require.Equal(t, uint64(i), d.Offset)
+		require.Equal(t, remote.Direction_FORWARD, d.Direction)
+		require.Equal(t, chain.Blocks[i].Hash(), libcommon.Hash(gointerfaces.ConvertH256ToHash(d.BlockHash)))
+	}
+	recipient := account(diffs[0], libcommon.Address{1})
+	require.NotNil(t, recipient)
+	require.Nil(t, recipient.Before)
+	require.Equal(t, uint64(10_000), gointerfaces.ConvertH256ToUint256Int(recipient.After.Balance).Uint64())
+	sender := account(diffs[1], m.Address)
+	require.Equal(t, uint64(1), sender.Before.Nonce)
+	require.Equal(t, uint64(2), sender.After.Nonce)
+
+	contract := account(diffs[2], crypto.CreateAddress(m.Address, 3))
+	require.NotNil(t, contract)
+	require.Nil(t, contract.Before)
+	require.Equal(t, []byte{0x00}, contract.AfterCode)
+	require.Len(t, contract.Storage, 1)
+	require.Empty(t, contract.Storage[0].Before)
+	require.Equal(t, []byte{42}, contract.Storage[0].After)
+
+	// the reorg reverts blocks 4 and 3, then applies the fork
+	require.NoError(t, m.InsertChain(fork.Slice(2, 5)))
+	syncFeed(t, m, f)
+	reorg := collect(t, f, 4, 5)
+	var directions []remote.Direction
+	var blocks []uint64
+	for _, d := range reorg {
+		directions = append(directions, d.Direction)
+		blocks = append(blocks, d.BlockNumber)
+	}
+	require.Equal(t, []remote.Direction{remote.Direction_UNWIND, remote.Direction_UNWIND, remote.Direction_FORWARD, remote.Direction_FORWARD, remote.Direction_FORWARD}, directions)
+	require.Equal(t, []uint64{4, 3, 3, 4, 5}, blocks)
+	reverted := account(reorg[1], crypto.CreateAddress(m.Address, 3))
+	require.Nil(t, reverted.After)
+	require.Empty(t, reverted.AfterCode)
+	require.Equal(t, []byte{42}, reverted.Storage[0].Before)
+
+	offset, err := f.OffsetOf(3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), offset)
+	offset, err = f.OffsetOf(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), offset)
+	_, err = f.OffsetOf(6)
+	require.ErrorIs(t, err, ErrBlockNotRetained)
+	info = f.Info(&remote.ChangeFeedInfoRequest{BlockNumber: &blocks[2]})
+	require.Equal(t, uint64(6), *info.BlockOffset)
+	require.Equal(t, uint64(9), info.NextOffset)
+	require.Equal(t, fork.TopBlock.Hash(), libcommon.Hash(gointerfaces.ConvertH256ToHash(info.HeadBlockHash)))
+
+	// reopening restores the offsets and the head, a torn frame at the end is dropped
+	f.Close()
+	firsts, err := listSegments(dir)
+	require.NoError(t, err)
+	require.Greater(t, len(firsts), 1)
+	file, err := os.OpenFile(segmentPath(dir, firsts[len(firsts)-1]), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{0xff, 0, 0, 0, 1, 2, 3})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	f, err = Open(dir, 100, 1*datasize.KB, m.BlockReader, log.New())
+	require.NoError(t, err)
+	defer f.Close()
+	reopened := f.Info(&remote.ChangeFeedInfoRequest{BlockNumber: &blocks[2]})
+	require.Equal(t, info.NextOffset, reopened.NextOffset)
+	require.Equal(t, info.HeadBlockNumber, reopened.HeadBlockNumber)
+	require.Equal(t, uint64(6), *reopened.BlockOffset)
+	syncFeed(t, m, f)
+	require.Equal(t, info.NextOffset, f.Info(&remote.ChangeFeedInfoRequest{}).NextOffset)
+	require.Equal(t, reorg[4].BlockHash, collect(t, f, 8, 1)[0].BlockHash)
+}
+
+func TestFeedRetention(t *testing.T) {
+	m := mock.Mock(t)
+	if m.HistoryV3 {
+		t.Skip("the change feed doesn't support HistoryV3")
+	}
+	chain := generate(t, m, 8, 8)
+	f, err := Open(t.TempDir(), 2, 1, m.BlockReader, log.New()) // one diff per segment
+	require.NoError(t, err)
+	defer f.Close()
+	syncFeed(t, m, f)
+
+	require.NoError(t, m.InsertChain(chain))
+	syncFeed(t, m, f)
+	info := f.Info(&remote.ChangeFeedInfoRequest{})
+	require.Equal(t, uint64(5), info.FirstOffset) // blocks 6, 7 and 8
+	require.Equal(t, uint64(8), info.NextOffset)
+
+	err = f.Diffs(context.Background(), 4, func(*remote.BlockDiff) error { return nil })
+	require.ErrorIs(t, err, ErrOffsetNotRetained)
+	err = f.Diffs(context.Background(), 9, func(*remote.BlockDiff) error { return nil })
+	require.ErrorIs(t, err, ErrOffsetAhead)
+	_, err = f.OffsetOf(5)
+	require.ErrorIs(t, err, ErrBlockNotRetained)
+	require.Equal(t, uint64(6), collect(t, f, 5, 1)[0].BlockNumber)
+}
+
+func TestFeedRunsOnNotify(t *testing.T) {
+	m := mock.Mock(t)
+	if m.HistoryV3 {
+		t.Skip("the change feed doesn't support HistoryV3")
+	}
+	chain := generate(t, m, 3, 3)
+	f, err := Open(t.TempDir(), 100, 1*datasize.KB, m.BlockReader, log.New())
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.Run(context.Background(), m.DB)
+	}()
+
+	// the feed starts at genesis, so the blocks inserted afterwards are appended by the next sync
+	f.Notify()
+	require.Eventually(t, func() bool {
+		head := f.Info(&remote.ChangeFeedInfoRequest{}).HeadBlockHash
+		return libcommon.Hash(gointerfaces.ConvertH256ToHash(head)) == m.Genesis.Hash()
+	}, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, m.InsertChain(chain))
+	f.Notify()
+	f.Notify() // merged with the pending request
+	diffs := collect(t, f, 0, 3)
+	for i, d := range diffs {
+		require.Equal(t, chain.Blocks[i].Hash(), libcommon.Hash(gointerfaces.ConvertH256ToHash(d.BlockHash)))
+	}
+
+	f.Close()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Run did not return after Close")
+	}
+}
@@ -0,0 +1,196 @@
+package changefeed
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+)
+
+// Each diff is stored as a frame:
+//
+//	payload length (4) | crc32 of the rest of the frame (4) | direction (1) | block number (8) | block hash (32) | payload
+//
+// where the payload is the protobuf encoded remote.BlockDiff. The block fields are duplicated in the frame header, so
+// the segments can be indexed at startup without decoding the payloads.
+const (
+	frameHeaderLen = 4 + 4 + 1 + length.BlockNum + length.Hash
+	segmentExt     = ".diffs"
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+type frameHeader struct {
+	direction remote.Direction
+	block     uint64
+	hash      libcommon.Hash
+}
+
+// segment is an append-only file of consecutive diffs, named by the offset of its first diff
+type segment struct {
+	first     uint64
+	file      *os.File
+	positions []int64 // file position of every diff, the diff at positions[i] has offset first+i
+	size      int64
+	lastBlock uint64 // highest block number of the diffs in the segment
+}
+
+func segmentPath(dir string, first uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", first, segmentExt))
+}
+
+func (s *segment) next() uint64 { return s.first + uint64(len(s.positions)) }
+
+func encodeFrame(h frameHeader, payload []byte) []byte {
+	frame := make([]byte, frameHeaderLen+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	frame[8] = byte(h.direction)
+	binary.BigEndian.PutUint64(frame[9:], h.block)
+	copy(frame[17:], h.hash[:])
+	copy(frame[frameHeaderLen:], payload)
+	binary.BigEndian.PutUint32(frame[4:], crc32.Checksum(frame[8:], crcTable))
+	return frame
+}
+
+func decodeFrameHeader(b []byte) (payloadLen uint32, crc uint32, h frameHeader) {
+	payloadLen = binary.BigEndian.Uint32(b)
+	crc = binary.BigEndian.Uint32(b[4:])
+	h.direction = remote.Direction(b[8])
+	h.block = binary.BigEndian.Uint64(b[9:])
+	copy(h.hash[:], b[17:frameHeaderLen])
+	return payloadLen, crc, h
+}
+
+// listSegments returns the offsets of the first diffs of the segments in dir, in ascending order
+func listSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var firsts []uint64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		first, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected file %s in the change feed directory", name)
+		}
+		firsts = append(firsts, first)
+	}
+	sort.Slice(firsts, func(i, j int) bool { return firsts[i] < firsts[j] })
+	return firsts, nil
+}
+
+// openSegment indexes the segment file and calls fn for the header of every frame. Only the frames of the last
+// segment are checked against their checksums: a torn frame at its end is left by a crash during an append, and is
+// truncated away.
+func openSegment(dir string, first uint64, last bool, fn func(frameHeader)) (*segment, error) {
+	path := segmentPath(dir, first)
+	file, err := os.OpenFile(path, os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s := &segment{first: first, file: file}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	header := make([]byte, frameHeaderLen)
+	var payload []byte
+	for s.size < info.Size() {
+		torn := func() (*segment, error) {
+			if !last {
+				file.Close()
+				return nil, fmt.Errorf("segment %s is corrupted at position %d", path, s.size)
+			}
+			if err := file.Truncate(s.size); err != nil {
+				file.Close()
+				return nil, err
+			}
+			return s, nil
+		}
+		if _, err := file.ReadAt(header, s.size); err != nil {
+			if errors.Is(err, io.EOF) {
+				return torn()
+			}
+			file.Close()
+			return nil, err
+		}
+		payloadLen, crc, h := decodeFrameHeader(header)
+		end := s.size + frameHeaderLen + int64(payloadLen)
+		if end > info.Size() {
+			return torn()
+		}
+		if last {
+			if cap(payload) < int(payloadLen) {
+				payload = make([]byte, payloadLen)
+			}
+			payload = payload[:payloadLen]
+			if _, err := file.ReadAt(payload, s.size+frameHeaderLen); err != nil {
+				file.Close()
+				return nil, err
+			}
+			if crc32.Update(crc32.Checksum(header[8:], crcTable), crcTable, payload) != crc {
+				return torn()
+			}
+		}
+		s.positions = append(s.positions, s.size)
+		s.size = end
+		s.lastBlock = max(s.lastBlock, h.block)
+		fn(h)
+	}
+	return s, nil
+}
+
+func createSegment(dir string, first uint64) (*segment, error) {
+	file, err := os.OpenFile(segmentPath(dir, first), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &segment{first: first, file: file}, nil
+}
+
+func (s *segment) append(frame []byte, block uint64) error {
+	if _, err := s.file.WriteAt(frame, s.size); err != nil {
+		return err
+	}
+	s.positions = append(s.positions, s.size)
+	s.size += int64(len(frame))
+	s.lastBlock = max(s.lastBlock, block)
+	return nil
+}
+
+// read returns the payload of the diff with the given offset
+func (s *segment) read(offset uint64) ([]byte, error) {
+	i := offset - s.first
+	start := s.positions[i] + frameHeaderLen
+	end := s.size
+	if i+1 < uint64(len(s.positions)) {
+		end = s.positions[i+1]
+	}
+	payload := make([]byte, end-start)
+	if _, err := s.file.ReadAt(payload, start); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (s *segment) remove(dir string) error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(segmentPath(dir, s.first))
+}
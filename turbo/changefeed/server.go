@@ -0,0 +1,50 @@
+package changefeed
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/types"
+)
+
+// APIVersion - use it to track changes in API
+// 1.0.0 - initial version
+var APIVersion = &types.VersionReply{Major: 1, Minor: 0, Patch: 0}
+
+// Server serves the feed over gRPC
+type Server struct {
+	remote.UnimplementedCHANGEFEEDServer // must be embedded to have forward compatible implementations.
+
+	feed *Feed
+}
+
+func NewServer(feed *Feed) *Server {
+	return &Server{feed: feed}
+}
+
+func (s *Server) Version(context.Context, *emptypb.Empty) (*types.VersionReply, error) {
+	return APIVersion, nil
+}
+
+func (s *Server) Info(_ context.Context, req *remote.ChangeFeedInfoRequest) (*remote.ChangeFeedInfoReply, error) {
+	return s.feed.Info(req), nil
+}
+
+func (s *Server) Diffs(req *remote.ChangeFeedRequest, stream remote.CHANGEFEED_DiffsServer) error {
+	from := req.FromOffset
+	if req.FromBlock != nil {
+		offset, err := s.feed.OffsetOf(*req.FromBlock)
+		if err != nil {
+			return err
+		}
+		from = offset
+	}
+	err := s.feed.Diffs(stream.Context(), from, stream.Send)
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrClosed) {
+		return nil
+	}
+	return err
+}
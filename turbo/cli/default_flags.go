@@ -27,6 +27,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.TxPoolLifetimeFlag,
 	&utils.TxPoolTraceSendersFlag,
 	&utils.TxPoolCommitEveryFlag,
+	&utils.TxPoolPolicyFileFlag,
 	&PruneFlag,
 	&PruneHistoryFlag,
 	&PruneReceiptFlag,
@@ -47,6 +48,9 @@ var DefaultFlags = []cli.Flag{
 	&TLSKeyFlag,
 	&TLSCACertFlag,
 	&StateStreamDisableFlag,
+	&ChangeFeedFlag,
+	&ChangeFeedRetentionFlag,
+	&ChangeFeedSegmentSizeFlag,
 	&SyncLoopThrottleFlag,
 	&BadBlockFlag,
 
@@ -172,11 +176,16 @@ var DefaultFlags = []cli.Flag{
 	&utils.OverrideOptimismEcotoneFlag,
 	&utils.OverrideOptimismFjordFlag,
 	&utils.OverrideOptimismGraniteFlag,
+	&utils.OverrideRetroactiveForksFlag,
+	&utils.SuperchainRegistryFlag,
+	&utils.SuperchainRegistrySHA256Flag,
 	&utils.RollupSequencerHTTPFlag,
 	&utils.RollupHistoricalRPCFlag,
 	&utils.RollupHistoricalRPCTimeoutFlag,
 	&utils.RollupDisableTxPoolGossipFlag,
 	&utils.RollupHaltOnIncompatibleProtocolVersionFlag,
+	&utils.RollupHaltGraceBlocksFlag,
+	&utils.RollupHaltGracePeriodFlag,
 	&utils.OverridePragueFlag,
 
 	&utils.LightClientDiscoveryAddrFlag,
@@ -215,6 +224,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.CaplinBlobBackfillingFlag,
 	&utils.CaplinDisableBlobPruningFlag,
 	&utils.CaplinArchiveFlag,
+	&utils.CaplinBlobArchiveFlag,
 
 	&utils.TrustedSetupFile,
 	&utils.RPCSlowFlag,
@@ -143,6 +143,21 @@ var (
 		Usage: "Disable streaming of state changes from core to RPC daemon",
 	}
 
+	ChangeFeedFlag = cli.BoolFlag{
+		Name:  "changefeed",
+		Usage: "Keep the per-block state diffs in segment files under <datadir>/changefeed, and serve them with resumable offsets over the private API and WebSocket. Only supported on history v2 databases, not on history v3 (E3) ones",
+	}
+	ChangeFeedRetentionFlag = cli.Uint64Flag{
+		Name:  "changefeed.retention",
+		Usage: "Number of recent blocks to keep the state diffs of in the change feed",
+		Value: ethconfig.Defaults.ChangeFeedRetention,
+	}
+	ChangeFeedSegmentSizeFlag = cli.StringFlag{
+		Name:  "changefeed.segment.size",
+		Usage: "Size after which the change feed starts a new segment file",
+		Value: ethconfig.Defaults.ChangeFeedSegmentSize.String(),
+	}
+
 	// Throttling Flags
 	SyncLoopThrottleFlag = cli.StringFlag{
 		Name:  "sync.loop.throttle",
@@ -321,6 +336,11 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 	}
 
 	cfg.StateStream = !ctx.Bool(StateStreamDisableFlag.Name)
+	cfg.ChangeFeed = ctx.Bool(ChangeFeedFlag.Name)
+	cfg.ChangeFeedRetention = ctx.Uint64(ChangeFeedRetentionFlag.Name)
+	if err := cfg.ChangeFeedSegmentSize.UnmarshalText([]byte(ctx.String(ChangeFeedSegmentSizeFlag.Name))); err != nil {
+		utils.Fatalf("Invalid changefeed.segment.size provided: %v", err)
+	}
 	if ctx.String(BodyCacheLimitFlag.Name) != "" {
 		err := cfg.Sync.BodyCacheLimit.UnmarshalText([]byte(ctx.String(BodyCacheLimitFlag.Name)))
 		if err != nil {
@@ -17,6 +17,7 @@
 package debug
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -27,6 +28,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/disk"
 	"github.com/ledgerwatch/erigon-lib/common/mem"
 	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/erigon-lib/tracing"
 
 	"github.com/ledgerwatch/log/v3"
 	"github.com/pelletier/go-toml"
@@ -82,12 +84,37 @@ var (
 		Name:  "trace",
 		Usage: "Write execution trace to the given file",
 	}
+	otelEndpointFlag = cli.StringFlag{
+		Name:  "otel.endpoint",
+		Usage: "Export OpenTelemetry trace spans to the given OTLP/HTTP collector, e.g. http://localhost:4318",
+	}
+	otelSampleRatioFlag = cli.Float64Flag{
+		Name:  "otel.sampleratio",
+		Usage: "Fraction of requests for which OpenTelemetry spans are exported",
+		Value: 1,
+	}
 )
 
 // Flags holds all command-line flags required for debugging.
 var Flags = []cli.Flag{
 	&pprofFlag, &pprofAddrFlag, &pprofPortFlag,
 	&cpuprofileFlag, &traceFlag,
+	&otelEndpointFlag, &otelSampleRatioFlag,
+}
+
+// tracingShutdown flushes pending OpenTelemetry spans, set once export is enabled.
+var tracingShutdown func(context.Context) error
+
+func setupTracing(ctx context.Context, endpoint string, sampleRatio float64, serviceName string, logger log.Logger) error {
+	if endpoint == "" {
+		return nil
+	}
+	shutdown, err := tracing.Setup(ctx, tracing.Config{Endpoint: endpoint, ServiceName: serviceName, SampleRatio: sampleRatio}, logger)
+	if err != nil {
+		return err
+	}
+	tracingShutdown = shutdown
+	return nil
 }
 
 // SetupCobra sets up logging, profiling and tracing for cobra commands
@@ -158,6 +185,21 @@ func SetupCobra(cmd *cobra.Command, filePrefix string) log.Logger {
 		panic(err)
 	}
 
+	otelEndpoint, err := flags.GetString(otelEndpointFlag.Name)
+	if err != nil {
+		log.Error("failed setting config flags from yaml/toml file", "err", err)
+		panic(err)
+	}
+	otelSampleRatio, err := flags.GetFloat64(otelSampleRatioFlag.Name)
+	if err != nil {
+		log.Error("failed setting config flags from yaml/toml file", "err", err)
+		panic(err)
+	}
+	if err := setupTracing(context.Background(), otelEndpoint, otelSampleRatio, filePrefix, logger); err != nil {
+		log.Error("failed to setup OpenTelemetry tracing", "err", err)
+		panic(err)
+	}
+
 	// setup periodic logging and prometheus updates
 	go mem.LogMemStats(cmd.Context(), log.Root())
 	go disk.UpdateDiskStats(cmd.Context(), log.Root())
@@ -205,6 +247,10 @@ func Setup(ctx *cli.Context, rootLogger bool) (log.Logger, *http.ServeMux, *http
 			return logger, nil, nil, err
 		}
 	}
+	if err := setupTracing(ctx.Context, ctx.String(otelEndpointFlag.Name), ctx.Float64(otelSampleRatioFlag.Name), "erigon", logger); err != nil {
+		return logger, nil, nil, err
+	}
+
 	pprofEnabled := ctx.Bool(pprofFlag.Name)
 	metricsEnabled := ctx.Bool(metricsEnabledFlag.Name)
 	metricsAddr := ctx.String(metricsAddrFlag.Name)
@@ -275,6 +321,9 @@ func StartPProf(address string, metricsMux *http.ServeMux) *http.ServeMux {
 func Exit() {
 	_ = Handler.StopCPUProfile()
 	_ = Handler.StopGoTrace()
+	if tracingShutdown != nil {
+		_ = tracingShutdown(context.Background())
+	}
 }
 
 // RaiseFdLimit raises out the number of allowed file handles per process
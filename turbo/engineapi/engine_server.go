@@ -14,6 +14,7 @@ import (
 	"github.com/ledgerwatch/erigon/eth/ethutils"
 
 	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -24,6 +25,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
 	libstate "github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/erigon-lib/tracing"
 
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
@@ -31,6 +33,7 @@ import (
 	"github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/merge"
+	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/params"
@@ -59,6 +62,11 @@ type EngineServer struct {
 	lock    sync.Mutex
 	logger  log.Logger
 
+	db         kv.RwDB // stores the superchain protocol version signal
+	signalLock sync.Mutex
+	signal     *rawdb.SuperchainSignal // latest superchain protocol version signal, see superchain_signal.go
+	halting    bool
+
 	nodeCloser func() error
 }
 
@@ -66,7 +74,7 @@ const fcuTimeout = 1000 // according to mathematics: 1000 millisecods = 1 second
 
 func NewEngineServer(logger log.Logger, config *chain.Config, executionService execution.ExecutionClient,
 	hd *headerdownload.HeaderDownload,
-	blockDownloader *engine_block_downloader.EngineBlockDownloader, test bool, proposing bool, ethConfig *ethconfig.Config, nodeCloser func() error) *EngineServer {
+	blockDownloader *engine_block_downloader.EngineBlockDownloader, test bool, proposing bool, ethConfig *ethconfig.Config, db kv.RwDB, nodeCloser func() error) *EngineServer {
 	chainRW := eth1_chain_reader.NewChainReaderEth1(config, executionService, fcuTimeout)
 	return &EngineServer{
 		logger:           logger,
@@ -77,6 +85,7 @@ func NewEngineServer(logger log.Logger, config *chain.Config, executionService e
 		chainRW:          chainRW,
 		proposing:        proposing,
 		hd:               hd,
+		db:               db,
 		nodeCloser:       nodeCloser,
 	}
 }
@@ -94,6 +103,10 @@ func (e *EngineServer) Start(
 	txPool txpool.TxpoolClient,
 	mining txpool.MiningClient,
 ) {
+	if err := e.loadSuperchainSignal(ctx); err != nil {
+		e.logger.Warn("Failed to load superchain version signal", "err", err)
+	}
+
 	base := jsonrpc.NewBaseApi(filters, stateCache, blockReader, agg, httpConfig.WithDatadir, httpConfig.EvmCallTimeout, engineReader, httpConfig.Dirs, nil, nil)
 
 	ethImpl := jsonrpc.NewEthAPI(base, db, eth, txPool, mining, httpConfig.Gascap, httpConfig.Feecap, httpConfig.ReturnDataLimit, httpConfig.AllowUnprotectedTxs, httpConfig.MaxGetProofRewindBlockCount, httpConfig.WebsocketSubscribeLogsChannelSize, e.logger)
@@ -131,7 +144,15 @@ func (s *EngineServer) checkWithdrawalsPresence(time uint64, withdrawals []*type
 // EngineNewPayload validates and possibly executes payload
 func (s *EngineServer) newPayload(ctx context.Context, req *engine_types.ExecutionPayload,
 	expectedBlobHashes []libcommon.Hash, parentBeaconBlockRoot *libcommon.Hash, version clparams.StateVersion,
-) (*engine_types.PayloadStatus, error) {
+) (status *engine_types.PayloadStatus, err error) {
+	ctx, span := tracing.StartSpan(ctx, "engine.newPayload",
+		attribute.Int64("block.number", int64(req.BlockNumber)), attribute.String("block.hash", req.BlockHash.Hex()))
+	defer func() {
+		if status != nil {
+			span.SetAttributes(attribute.String("engine.status", string(status.Status)))
+		}
+		tracing.EndSpan(span, err)
+	}()
 	var bloom types.Bloom
 	copy(bloom[:], req.LogsBloom)
 
@@ -452,9 +473,19 @@ func (s *EngineServer) getPayload(ctx context.Context, payloadId uint64, version
 
 // engineForkChoiceUpdated either states new block head or request the assembling of a new block
 func (s *EngineServer) forkchoiceUpdated(ctx context.Context, forkchoiceState *engine_types.ForkChoiceState, payloadAttributes *engine_types.PayloadAttributes, version clparams.StateVersion,
-) (*engine_types.ForkChoiceUpdatedResponse, error) {
+) (fcuResp *engine_types.ForkChoiceUpdatedResponse, err error) {
+	ctx, span := tracing.StartSpan(ctx, "engine.forkchoiceUpdated",
+		attribute.String("head.hash", forkchoiceState.HeadHash.Hex()), attribute.Bool("engine.build", payloadAttributes != nil))
+	defer func() {
+		if fcuResp != nil && fcuResp.PayloadStatus != nil {
+			span.SetAttributes(attribute.String("engine.status", string(fcuResp.PayloadStatus.Status)))
+		}
+		tracing.EndSpan(span, err)
+	}()
+	if err := s.checkPendingHalt(ctx); err != nil {
+		return nil, err
+	}
 	var status *engine_types.PayloadStatus
-	var err error
 	// In the Optimism case, we allow arbitrary rewinding of the safe block
 	// hash, so we skip the path which might short-circuit that
 	if s.config.Optimism == nil {
@@ -705,7 +736,10 @@ func (e *EngineServer) SignalSuperchainV1(ctx context.Context, signal *engine_ty
 	LogProtocolVersionSupport(logger, params.OPStackSupport, signal.Recommended, "recommended")
 	LogProtocolVersionSupport(logger, params.OPStackSupport, signal.Required, "required")
 
-	if err := e.HandleRequiredProtocolVersion(signal.Required); err != nil {
+	if err := e.recordSuperchainSignal(ctx, signal); err != nil {
+		log.Warn("Failed to store superchain version signal", "err", err)
+	}
+	if err := e.HandleRequiredProtocolVersion(ctx, signal.Required); err != nil {
 		log.Error("Failed to handle required protocol version", "err", err, "required", signal.Required)
 		return params.OPStackSupport, err
 	}
@@ -715,7 +749,21 @@ func (e *EngineServer) SignalSuperchainV1(ctx context.Context, signal *engine_ty
 
 // HandleRequiredProtocolVersion handles the protocol version signal. This implements opt-in halting,
 // the protocol version data is already logged and metered when signaled through the Engine API.
-func (e *EngineServer) HandleRequiredProtocolVersion(required params.ProtocolVersion) error {
+// With a configured grace period the halt is delayed, see checkPendingHalt.
+func (e *EngineServer) HandleRequiredProtocolVersion(ctx context.Context, required params.ProtocolVersion) error {
+	if !e.haltOnProtocolVersion(required) {
+		return nil
+	}
+	if remaining := e.haltGraceRemaining(ctx); remaining != "" {
+		log.Warn("Unprepared for protocol change, halting after grace period", "required", required, "local", params.OPStackSupport, "remaining", remaining)
+		return nil
+	}
+	log.Error("Opted to halt, unprepared for protocol change", "required", required, "local", params.OPStackSupport)
+	return e.halt()
+}
+
+// haltOnProtocolVersion reports whether the node opted to halt on the required version.
+func (e *EngineServer) haltOnProtocolVersion(required params.ProtocolVersion) bool {
 	var needLevel int
 	switch e.ethConfig.RollupHaltOnIncompatibleProtocolVersion {
 	case "major":
@@ -725,7 +773,7 @@ func (e *EngineServer) HandleRequiredProtocolVersion(required params.ProtocolVer
 	case "patch":
 		needLevel = 1
 	default:
-		return nil // do not consider halting if not configured to
+		return false // do not consider halting if not configured to
 	}
 	haveLevel := 0
 	switch params.OPStackSupport.Compare(required) {
@@ -736,11 +784,7 @@ func (e *EngineServer) HandleRequiredProtocolVersion(required params.ProtocolVer
 	case params.OutdatedPatch:
 		haveLevel = 1
 	}
-	if haveLevel >= needLevel { // halt if we opted in to do so at this granularity
-		log.Error("Opted to halt, unprepared for protocol change", "required", required, "local", params.OPStackSupport)
-		return e.nodeCloser()
-	}
-	return nil
+	return haveLevel >= needLevel // halt if we opted in to do so at this granularity
 }
 
 func LogProtocolVersionSupport(logger log.Logger, local, other params.ProtocolVersion, name string) {
@@ -0,0 +1,151 @@
+package engineapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
+)
+
+var (
+	superchainSignalTimestamp = metrics.GetOrCreateGauge("superchain_signal_timestamp")
+	superchainUpgradeRequired = metrics.GetOrCreateGauge("superchain_upgrade_required")
+)
+
+// loadSuperchainSignal restores the latest signal stored before a restart, so a pending halt and its
+// grace period carry over without waiting for the next signal of the rollup node. The local version may
+// have changed since, the upgrade is only kept as required if this build still does not support it.
+func (e *EngineServer) loadSuperchainSignal(ctx context.Context) error {
+	if e.db == nil {
+		return nil
+	}
+	var signal *rawdb.SuperchainSignal
+	if err := e.db.View(ctx, func(tx kv.Tx) (err error) {
+		signal, err = rawdb.ReadSuperchainSignal(tx)
+		return err
+	}); err != nil {
+		return err
+	}
+	if signal == nil {
+		return nil
+	}
+	signal.Local = params.OPStackSupport
+	if signal.UpgradeRequired = params.OPStackSupport.UpgradeRequired(signal.Required); !signal.UpgradeRequired {
+		signal.UpgradeRequiredSince, signal.UpgradeRequiredBlock = 0, 0
+	}
+
+	e.signalLock.Lock()
+	defer e.signalLock.Unlock()
+	if e.signal == nil {
+		e.signal = signal
+		updateSuperchainSignalMetrics(signal)
+	}
+	return nil
+}
+
+func updateSuperchainSignalMetrics(signal *rawdb.SuperchainSignal) {
+	superchainSignalTimestamp.SetUint64(signal.ReceivedAt)
+	if signal.UpgradeRequired {
+		superchainUpgradeRequired.SetUint64(1)
+	} else {
+		superchainUpgradeRequired.SetUint64(0)
+	}
+}
+
+// recordSuperchainSignal keeps the latest signal in memory and in the db. While the required version is
+// ahead of the local one, the time and head block of the first signal which required it are carried
+// over: they start the grace period before halting.
+func (e *EngineServer) recordSuperchainSignal(ctx context.Context, signal *engine_types.SuperchainSignal) error {
+	e.signalLock.Lock()
+	defer e.signalLock.Unlock()
+
+	now := uint64(time.Now().Unix())
+	record := &rawdb.SuperchainSignal{
+		Recommended:     signal.Recommended,
+		Required:        signal.Required,
+		Local:           params.OPStackSupport,
+		ReceivedAt:      now,
+		UpgradeRequired: params.OPStackSupport.UpgradeRequired(signal.Required),
+	}
+	prev := e.signal
+	if record.UpgradeRequired {
+		if prev != nil && prev.UpgradeRequired {
+			record.UpgradeRequiredSince, record.UpgradeRequiredBlock = prev.UpgradeRequiredSince, prev.UpgradeRequiredBlock
+		} else {
+			record.UpgradeRequiredSince = now
+			if head := e.chainRW.CurrentHeader(ctx); head != nil {
+				record.UpgradeRequiredBlock = head.Number.Uint64()
+			}
+		}
+	}
+	e.signal = record
+	updateSuperchainSignalMetrics(record)
+
+	if e.db == nil {
+		return nil
+	}
+	return e.db.Update(ctx, func(tx kv.RwTx) error {
+		return rawdb.WriteSuperchainSignal(tx, record)
+	})
+}
+
+// haltGraceRemaining describes what is left of the grace period before halting on the latest signal,
+// or returns "" once it is over. Halting waits until all configured grace periods are over, without
+// any the node halts right away.
+func (e *EngineServer) haltGraceRemaining(ctx context.Context) string {
+	e.signalLock.Lock()
+	signal := e.signal
+	e.signalLock.Unlock()
+	if signal == nil || !signal.UpgradeRequired {
+		return ""
+	}
+
+	var remaining []string
+	if period := e.ethConfig.RollupHaltGracePeriod; period > 0 {
+		deadline := time.Unix(int64(signal.UpgradeRequiredSince), 0).Add(period)
+		if left := time.Until(deadline); left > 0 {
+			remaining = append(remaining, left.Round(time.Second).String())
+		}
+	}
+	if blocks := e.ethConfig.RollupHaltGraceBlocks; blocks > 0 {
+		var head uint64
+		if header := e.chainRW.CurrentHeader(ctx); header != nil {
+			head = header.Number.Uint64()
+		}
+		if deadline := signal.UpgradeRequiredBlock + blocks; head < deadline {
+			remaining = append(remaining, fmt.Sprintf("%d blocks", deadline-head))
+		}
+	}
+	return strings.Join(remaining, ", ")
+}
+
+// checkPendingHalt halts the node once the grace period of a pending halt is over. It runs on every
+// forkchoice update, so the halt does not wait for the next signal of the rollup node.
+func (e *EngineServer) checkPendingHalt(ctx context.Context) error {
+	e.signalLock.Lock()
+	signal, halting := e.signal, e.halting
+	e.signalLock.Unlock()
+	if halting || signal == nil || !signal.UpgradeRequired || !e.haltOnProtocolVersion(signal.Required) {
+		return nil
+	}
+	if e.haltGraceRemaining(ctx) != "" {
+		return nil
+	}
+	log.Error("Opted to halt, grace period for protocol change is over", "required", signal.Required, "local", params.OPStackSupport)
+	return e.halt()
+}
+
+func (e *EngineServer) halt() error {
+	e.signalLock.Lock()
+	e.halting = true
+	e.signalLock.Unlock()
+	return e.nodeCloser()
+}
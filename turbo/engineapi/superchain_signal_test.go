@@ -0,0 +1,57 @@
+package engineapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+func TestLoadSuperchainSignal(t *testing.T) {
+	ahead := params.ProtocolVersionV0{Major: 1000}.Encode()
+	cases := []struct {
+		name     string
+		stored   *rawdb.SuperchainSignal
+		required bool
+		since    uint64
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:     "pending upgrade",
+			stored:   &rawdb.SuperchainSignal{Required: ahead, ReceivedAt: 20, UpgradeRequired: true, UpgradeRequiredSince: 10, UpgradeRequiredBlock: 5},
+			required: true,
+			since:    10,
+		},
+		{
+			name:   "upgraded since",
+			stored: &rawdb.SuperchainSignal{Required: params.OPStackSupport, ReceivedAt: 20, UpgradeRequired: true, UpgradeRequiredSince: 10, UpgradeRequiredBlock: 5},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := memdb.NewTestDB(t)
+			if c.stored != nil {
+				require.NoError(t, db.Update(context.Background(), func(tx kv.RwTx) error {
+					return rawdb.WriteSuperchainSignal(tx, c.stored)
+				}))
+			}
+			e := &EngineServer{db: db}
+			require.NoError(t, e.loadSuperchainSignal(context.Background()))
+			if c.stored == nil {
+				require.Nil(t, e.signal)
+				return
+			}
+			require.NotNil(t, e.signal)
+			require.Equal(t, params.OPStackSupport, e.signal.Local)
+			require.Equal(t, c.required, e.signal.UpgradeRequired)
+			require.Equal(t, c.since, e.signal.UpgradeRequiredSince)
+		})
+	}
+}
@@ -87,6 +87,21 @@ func TestBlockRpcConversion(t *testing.T) {
 	require.Equal(testBlockRaw, roundTripBody) // validates txns, uncles, and withdrawals
 }
 
+func TestHeaderRequestsHashRpcConversion(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	header := makeBlock(1, 0, 1).Header()
+	blobGasUsed, excessBlobGas := uint64(0), uint64(0)
+	parentRoot, requestsHash := libcommon.Hash{1}, types.CalcRequestsHash(nil)
+	header.BlobGasUsed, header.ExcessBlobGas = &blobGasUsed, &excessBlobGas
+	header.ParentBeaconBlockRoot, header.RequestsHash = &parentRoot, &requestsHash
+
+	// the block hash is checked on the way back, it commits to the requests hash
+	roundTripHeader, err := HeaderRpcToHeader(HeaderToHeaderRPC(header))
+	require.NoError(err)
+	require.Equal(header, roundTripHeader)
+}
+
 func TestBigIntConversion(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -13,6 +13,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
 	"github.com/ledgerwatch/erigon-lib/wrap"
 	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/stagedsync"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 )
@@ -163,6 +164,8 @@ func (e *EthereumExecutionModule) updateForkChoice(ctx context.Context, blockHas
 	}
 	if canonicalHash == blockHash {
 		// if block hash is part of the canonical chain treat it as no-op.
+		safeMoved := safeHash != (libcommon.Hash{}) && safeHash != rawdb.ReadForkchoiceSafe(tx)
+		finalizedMoved := finalizedHash != (libcommon.Hash{}) && finalizedHash != rawdb.ReadForkchoiceFinalized(tx)
 		writeForkChoiceHashes(tx, blockHash, safeHash, finalizedHash)
 		valid, err := e.verifyForkchoiceHashes(ctx, tx, blockHash, finalizedHash, safeHash)
 		if err != nil {
@@ -177,6 +180,18 @@ func (e *EthereumExecutionModule) updateForkChoice(ctx context.Context, blockHas
 			return
 		}
 		if !unwindingToCanonical {
+			// head is unchanged, but safe and finalized may have moved: only then is there something to
+			// commit, and subscribers are notified once the new heads are visible to readers.
+			if e.hook != nil && (safeMoved || finalizedMoved) {
+				if err := tx.Commit(); err != nil {
+					sendForkchoiceErrorWithoutWaiting(outcomeCh, err)
+					return
+				}
+				if err := e.db.View(ctx, e.hook.NotifyForkchoiceHeads); err != nil {
+					sendForkchoiceErrorWithoutWaiting(outcomeCh, err)
+					return
+				}
+			}
 			sendForkchoiceReceiptWithoutWaiting(outcomeCh, &execution.ForkChoiceReceipt{
 				LatestValidHash: gointerfaces.ConvertHashToH256(blockHash),
 				Status:          execution.ExecutionStatus_Success,
@@ -264,6 +279,15 @@ func (e *EthereumExecutionModule) updateForkChoice(ctx context.Context, blockHas
 		}
 	}
 
+	added := make([]libcommon.Hash, 0, len(newCanonicals))
+	for i := len(newCanonicals) - 1; i >= 0; i-- {
+		added = append(added, newCanonicals[i].hash)
+	}
+	if err := stagedsync.RecordReorg(tx, types.ReorgTriggerEngineAPI, unwindToNumber, added); err != nil {
+		sendForkchoiceErrorWithoutWaiting(outcomeCh, err)
+		return
+	}
+
 	// Run the unwind
 	if err := e.executionPipeline.RunUnwind(e.db, wrap.TxContainer{Tx: tx}); err != nil {
 		err = fmt.Errorf("updateForkChoice: %w", err)
@@ -378,7 +402,10 @@ func (e *EthereumExecutionModule) updateForkChoice(ctx context.Context, blockHas
 		}
 		if e.hook != nil {
 			if err := e.db.View(ctx, func(tx kv.Tx) error {
-				return e.hook.AfterRun(tx, finishProgressBefore)
+				if err := e.hook.AfterRun(tx, finishProgressBefore); err != nil {
+					return err
+				}
+				return e.hook.NotifyForkchoiceHeads(tx)
 			}); err != nil {
 				sendForkchoiceErrorWithoutWaiting(outcomeCh, err)
 				return
@@ -51,9 +51,9 @@ var (
 func getHeaderByNumber(ctx context.Context, number rpc.BlockNumber, api *BorImpl, tx kv.Tx) (*types.Header, error) {
 	// Pending block is only known by the miner
 	if number == rpc.PendingBlockNumber {
-		block := api.pendingBlock()
-		if block == nil {
-			return nil, nil
+		block, err := api.pendingBlock(ctx, tx)
+		if err != nil || block == nil {
+			return nil, err
 		}
 		return block.Header(), nil
 	}
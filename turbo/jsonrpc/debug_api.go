@@ -9,6 +9,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
@@ -29,6 +30,9 @@ import (
 // AccountRangeMaxResults is the maximum number of results to be returned per call
 const AccountRangeMaxResults = 256
 
+// ExecutionStatsMaxBlocks is the maximum number of blocks debug_getBlockExecutionStats returns per call
+const ExecutionStatsMaxBlocks = 1024
+
 // PrivateDebugAPI Exposed RPC endpoints for debugging use
 type PrivateDebugAPI interface {
 	StorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex uint64, contractAddress common.Address, keyStart hexutility.Bytes, maxResult int) (StorageRangeResult, error)
@@ -42,6 +46,7 @@ type PrivateDebugAPI interface {
 	AccountAt(ctx context.Context, blockHash common.Hash, txIndex uint64, account common.Address) (*AccountResult, error)
 	GetRawHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error)
 	GetRawBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error)
+	GetBlockExecutionStats(ctx context.Context, from, to rpc.BlockNumber) ([]*diagnostics.BlockExecutionProfile, error)
 }
 
 // PrivateDebugAPIImpl is implementation of the PrivateDebugAPI interface based on remote Db access
@@ -407,3 +412,29 @@ func (api *PrivateDebugAPIImpl) GetRawBlock(ctx context.Context, blockNrOrHash r
 	}
 	return rlp.EncodeToBytes(block)
 }
+
+// GetBlockExecutionStats implements debug_getBlockExecutionStats. Returns the execution profiles recorded by
+// the execution stage for blocks in [from, to]. Only recently executed blocks have a profile.
+func (api *PrivateDebugAPIImpl) GetBlockExecutionStats(ctx context.Context, from, to rpc.BlockNumber) ([]*diagnostics.BlockExecutionProfile, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	fromNum, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(from), tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	toNum, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(to), tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	if fromNum > toNum {
+		return nil, fmt.Errorf("start block (%d) must be less than or equal to end block (%d)", fromNum, toNum)
+	}
+	if toNum-fromNum >= ExecutionStatsMaxBlocks {
+		return nil, fmt.Errorf("block range too large, max %d blocks", ExecutionStatsMaxBlocks)
+	}
+	return rawdb.ReadBlockExecutionProfiles(tx, fromNum, toNum)
+}
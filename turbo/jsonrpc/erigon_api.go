@@ -21,6 +21,7 @@ type ErigonAPI interface {
 	// System related (see ./erigon_system.go)
 	Forks(ctx context.Context) (Forks, error)
 	BlockNumber(ctx context.Context, rpcBlockNumPtr *rpc.BlockNumber) (hexutil.Uint64, error)
+	PruneInfo(ctx context.Context) (*PruneInfo, error)
 
 	// Blocks related (see ./erigon_blocks.go)
 	GetHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
@@ -33,9 +34,16 @@ type ErigonAPI interface {
 	//GetLogsByNumber(ctx context.Context, number rpc.BlockNumber) ([][]*types.Log, error)
 	GetLogs(ctx context.Context, crit filters.FilterCriteria) (types.ErigonLogs, error)
 	GetLatestLogs(ctx context.Context, crit filters.FilterCriteria, logOptions filters.LogFilterOptions) (types.ErigonLogs, error)
+	GetLogsPaginated(ctx context.Context, crit filters.FilterCriteria, cursor *LogsCursor, pageSize *hexutil.Uint64) (*LogsPage, error) // see ./erigon_logs_paginated.go
 	// Gets cannonical block receipt through hash. If the block is not cannonical returns error
 	GetBlockReceiptsByBlockHash(ctx context.Context, cannonicalBlockHash common.Hash) ([]map[string]interface{}, error)
 
+	// Reorgs related (see ./erigon_reorgs.go)
+	GetReorgs(ctx context.Context, crit ReorgCriteria) ([]*Reorg, error)
+
+	// Change feed related (see ./erigon_change_feed.go)
+	ChangeFeedInfo(ctx context.Context, blockNumber *hexutil.Uint64) (*ChangeFeedInfo, error)
+
 	// NodeInfo returns a collection of metadata known about the host.
 	NodeInfo(ctx context.Context) ([]p2p.NodeInfo, error)
 }
@@ -28,21 +28,21 @@ import (
 
 // GetHeaderByNumber implements erigon_getHeaderByNumber. Returns a block's header given a block number ignoring the block's transaction and uncle list (may be faster).
 func (api *ErigonImpl) GetHeaderByNumber(ctx context.Context, blockNumber rpc.BlockNumber) (*types.Header, error) {
-	// Pending block is only known by the miner
-	if blockNumber == rpc.PendingBlockNumber {
-		block := api.pendingBlock()
-		if block == nil {
-			return nil, nil
-		}
-		return block.Header(), nil
-	}
-
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
+	// Pending block is only known by the miner, or simulated on OP chains
+	if blockNumber == rpc.PendingBlockNumber {
+		block, err := api.pendingBlock(ctx, tx)
+		if err != nil || block == nil {
+			return nil, err
+		}
+		return block.Header(), nil
+	}
+
 	blockNum, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(blockNumber), tx, api.filters)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,174 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// StateDiffsCriteria selects where a stateDiffs subscription starts, fromBlock takes precedence over fromOffset
+type StateDiffsCriteria struct {
+	FromOffset *hexutil.Uint64 `json:"fromOffset"`
+	FromBlock  *hexutil.Uint64 `json:"fromBlock"` // the FORWARD diff of this canonical block
+}
+
+// ChangeFeedInfo is the RPC representation of the range of diffs the change feed retains
+type ChangeFeedInfo struct {
+	FirstOffset     hexutil.Uint64  `json:"firstOffset"`
+	NextOffset      hexutil.Uint64  `json:"nextOffset"`
+	HeadBlockNumber hexutil.Uint64  `json:"headBlockNumber"`
+	HeadBlockHash   common.Hash     `json:"headBlockHash"`
+	BlockOffset     *hexutil.Uint64 `json:"blockOffset,omitempty"`
+}
+
+// FeedStateDiff is the RPC representation of the state diff of one block
+type FeedStateDiff struct {
+	Offset      hexutil.Uint64     `json:"offset"`
+	Direction   string             `json:"direction"` // "forward" applies the block, "unwind" reverts it
+	BlockNumber hexutil.Uint64     `json:"blockNumber"`
+	BlockHash   common.Hash        `json:"blockHash"`
+	ParentHash  common.Hash        `json:"parentHash"`
+	Accounts    []*FeedAccountDiff `json:"accounts"`
+}
+
+type FeedAccountDiff struct {
+	Address    common.Address     `json:"address"`
+	Before     *FeedAccountState  `json:"before"` // null when the account didn't exist
+	After      *FeedAccountState  `json:"after"`  // null when the account was deleted
+	BeforeCode hexutility.Bytes   `json:"beforeCode,omitempty"`
+	AfterCode  hexutility.Bytes   `json:"afterCode,omitempty"`
+	Storage    []*FeedStorageDiff `json:"storage"`
+}
+
+type FeedAccountState struct {
+	Nonce       hexutil.Uint64 `json:"nonce"`
+	Balance     *hexutil.Big   `json:"balance"`
+	CodeHash    common.Hash    `json:"codeHash"`
+	Incarnation hexutil.Uint64 `json:"incarnation"`
+}
+
+type FeedStorageDiff struct {
+	Incarnation hexutil.Uint64 `json:"incarnation"`
+	Location    common.Hash    `json:"location"`
+	Before      common.Hash    `json:"before"`
+	After       common.Hash    `json:"after"`
+}
+
+func newRPCChangeFeedInfo(info *remote.ChangeFeedInfoReply) *ChangeFeedInfo {
+	res := &ChangeFeedInfo{
+		FirstOffset:     hexutil.Uint64(info.FirstOffset),
+		NextOffset:      hexutil.Uint64(info.NextOffset),
+		HeadBlockNumber: hexutil.Uint64(info.HeadBlockNumber),
+		HeadBlockHash:   gointerfaces.ConvertH256ToHash(info.HeadBlockHash),
+	}
+	if info.BlockOffset != nil {
+		offset := hexutil.Uint64(*info.BlockOffset)
+		res.BlockOffset = &offset
+	}
+	return res
+}
+
+func newRPCStateDiff(d *remote.BlockDiff) *FeedStateDiff {
+	direction := "forward"
+	if d.Direction == remote.Direction_UNWIND {
+		direction = "unwind"
+	}
+	accounts := make([]*FeedAccountDiff, 0, len(d.Accounts))
+	for _, a := range d.Accounts {
+		storage := make([]*FeedStorageDiff, 0, len(a.Storage))
+		for _, s := range a.Storage {
+			storage = append(storage, &FeedStorageDiff{
+				Incarnation: hexutil.Uint64(s.Incarnation),
+				Location:    gointerfaces.ConvertH256ToHash(s.Location),
+				Before:      common.BytesToHash(s.Before),
+				After:       common.BytesToHash(s.After),
+			})
+		}
+		accounts = append(accounts, &FeedAccountDiff{
+			Address:    gointerfaces.ConvertH160toAddress(a.Address),
+			Before:     newRPCAccountState(a.Before),
+			After:      newRPCAccountState(a.After),
+			BeforeCode: a.BeforeCode,
+			AfterCode:  a.AfterCode,
+			Storage:    storage,
+		})
+	}
+	return &FeedStateDiff{
+		Offset:      hexutil.Uint64(d.Offset),
+		Direction:   direction,
+		BlockNumber: hexutil.Uint64(d.BlockNumber),
+		BlockHash:   gointerfaces.ConvertH256ToHash(d.BlockHash),
+		ParentHash:  gointerfaces.ConvertH256ToHash(d.ParentHash),
+		Accounts:    accounts,
+	}
+}
+
+func newRPCAccountState(a *remote.AccountState) *FeedAccountState {
+	if a == nil {
+		return nil
+	}
+	return &FeedAccountState{
+		Nonce:       hexutil.Uint64(a.Nonce),
+		Balance:     (*hexutil.Big)(gointerfaces.ConvertH256ToUint256Int(a.Balance).ToBig()),
+		CodeHash:    gointerfaces.ConvertH256ToHash(a.CodeHash),
+		Incarnation: hexutil.Uint64(a.Incarnation),
+	}
+}
+
+// ChangeFeedInfo implements erigon_changeFeedInfo. Returns the range of diffs the change feed retains and, when
+// blockNumber is given and its diff is retained, the offset to subscribe from to receive it.
+func (api *ErigonImpl) ChangeFeedInfo(ctx context.Context, blockNumber *hexutil.Uint64) (*ChangeFeedInfo, error) {
+	req := &remote.ChangeFeedInfoRequest{}
+	if blockNumber != nil {
+		n := uint64(*blockNumber)
+		req.BlockNumber = &n
+	}
+	info, err := api.ethBackend.ChangeFeedInfo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return newRPCChangeFeedInfo(info), nil
+}
+
+// changeFeedRequest checks the criteria against the retained range, a subscription can't report errors once it
+// was created
+func changeFeedRequest(ctx context.Context, backend rpchelper.ApiBackend, crit *StateDiffsCriteria) (*remote.ChangeFeedRequest, error) {
+	if crit == nil {
+		crit = &StateDiffsCriteria{}
+	}
+	infoReq := &remote.ChangeFeedInfoRequest{}
+	if crit.FromBlock != nil {
+		n := uint64(*crit.FromBlock)
+		infoReq.BlockNumber = &n
+	}
+	info, err := backend.ChangeFeedInfo(ctx, infoReq)
+	if err != nil {
+		return nil, err
+	}
+	req := &remote.ChangeFeedRequest{FromOffset: info.NextOffset}
+	switch {
+	case crit.FromBlock != nil:
+		if info.BlockOffset == nil {
+			return nil, &rpc.InvalidParamsError{Message: fmt.Sprintf("the diff of block %d is not retained by the change feed", uint64(*crit.FromBlock))}
+		}
+		req.FromOffset = *info.BlockOffset
+	case crit.FromOffset != nil:
+		offset := uint64(*crit.FromOffset)
+		if offset < info.FirstOffset {
+			return nil, &rpc.InvalidParamsError{Message: fmt.Sprintf("offset %d is not retained by the change feed, the first retained one is %d", offset, info.FirstOffset)}
+		}
+		if offset > info.NextOffset {
+			return nil, &rpc.InvalidParamsError{Message: fmt.Sprintf("offset %d is ahead of the change feed, the next one is %d", offset, info.NextOffset)}
+		}
+		req.FromOffset = offset
+	}
+	return req, nil
+}
@@ -0,0 +1,290 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/bitmapdb"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/ethdb/cbor"
+	bortypes "github.com/ledgerwatch/erigon/polygon/bor/types"
+)
+
+const (
+	defaultLogsPageSize = 1_000
+	maxLogsPageSize     = 10_000
+	// maxLogsPageBlocks bounds the number of candidate blocks read for one page: a page can be shorter
+	// than requested (and still have a cursor) if the indices yield many blocks without matching logs
+	maxLogsPageBlocks = 10_000
+	// maxLogsPageBlocksV3 - same with history v3, where reading the logs of a block may need its re-execution
+	maxLogsPageBlocksV3 = 1_000
+)
+
+// LogsCursor - position in the block range to continue erigon_getLogsPaginated from
+type LogsCursor struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	LogIndex    hexutil.Uint   `json:"logIndex"`
+}
+
+// LogsPage - result of erigon_getLogsPaginated, Cursor is nil when the range is exhausted
+type LogsPage struct {
+	Logs   types.ErigonLogs `json:"logs"`
+	Cursor *LogsCursor      `json:"cursor"`
+}
+
+// GetLogsPaginated implements erigon_getLogsPaginated. Returns up to pageSize logs matching the filter criteria
+// (same as erigon_getLogs), starting from the cursor of the previous page. The work done for a single call is
+// bounded, so wide ranges are served page by page instead of timing out.
+// Filters on both an address and a first topic are served by the address+topic0 index, see applyFilters and
+// applyFiltersV3.
+func (api *ErigonImpl) GetLogsPaginated(ctx context.Context, crit filters.FilterCriteria, cursor *LogsCursor, pageSize *hexutil.Uint64) (*LogsPage, error) {
+	limit := defaultLogsPageSize
+	if pageSize != nil {
+		if *pageSize == 0 || *pageSize > maxLogsPageSize {
+			return nil, fmt.Errorf("page size must be in [1, %d]", maxLogsPageSize)
+		}
+		limit = int(*pageSize)
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	page := &LogsPage{Logs: types.ErigonLogs{}}
+	begin, end, ok, err := api.logsRange(ctx, tx, crit)
+	if !ok {
+		if err != nil {
+			return nil, err
+		}
+		return page, nil
+	}
+	var skipLogs uint
+	if cursor != nil {
+		if uint64(cursor.BlockNumber) < begin || uint64(cursor.BlockNumber) > end {
+			return nil, fmt.Errorf("cursor block %d is out of the range [%d, %d]", cursor.BlockNumber, begin, end)
+		}
+		begin, skipLogs = uint64(cursor.BlockNumber), uint(cursor.LogIndex)
+	}
+	if err := api.checkPruned(tx, prunedLogs, begin); err != nil {
+		return nil, err
+	}
+
+	addrMap := make(map[common.Address]struct{}, len(crit.Addresses))
+	for _, v := range crit.Addresses {
+		addrMap[v] = struct{}{}
+	}
+	// candidate blocks of the range and their matching logs: with history v3 the logs index isn't in the DB,
+	// candidates come from the inverted indices of the state files and the logs from the block receipts
+	var candidates blockIterator
+	readBlockLogs := func(blockNumber uint64) ([]*types.Log, error) {
+		return readFilteredBlockLogs(tx, blockNumber, addrMap, crit.Topics)
+	}
+	maxBlocks := maxLogsPageBlocks
+	if api.historyV3(tx) {
+		txNumbers, err := applyFiltersV3(tx.(kv.TemporalTx), begin, end, crit)
+		if err != nil {
+			return nil, err
+		}
+		candidates = &txNumBlockIterator{it: MapTxNum2BlockNum(tx, txNumbers)}
+		readBlockLogs = func(blockNumber uint64) ([]*types.Log, error) {
+			return api.readFilteredBlockLogsV3(ctx, tx, blockNumber, addrMap, crit.Topics)
+		}
+		// receipts of a block may have to be re-generated by executing it
+		maxBlocks = maxLogsPageBlocksV3
+	} else {
+		blockNumbers := bitmapdb.NewBitmap()
+		defer bitmapdb.ReturnToPool(blockNumbers)
+		if err := applyFilters(blockNumbers, tx, api._agg, begin, end, crit); err != nil {
+			return nil, err
+		}
+		candidates = &bitmapBlockIterator{it: blockNumbers.Iterator()}
+	}
+
+	for blocks := 0; ; blocks++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		blockNumber, ok, err := candidates.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if blocks == maxBlocks {
+			page.Cursor = &LogsCursor{BlockNumber: hexutil.Uint64(blockNumber)}
+			break
+		}
+
+		blockLogs, err := readBlockLogs(blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if blockNumber == begin && skipLogs > 0 {
+			i := 0
+			for i < len(blockLogs) && blockLogs[i].Index < skipLogs {
+				i++
+			}
+			blockLogs = blockLogs[i:]
+		}
+		if len(blockLogs) == 0 {
+			continue
+		}
+		if left := limit - len(page.Logs); len(blockLogs) > left {
+			page.Cursor = &LogsCursor{BlockNumber: hexutil.Uint64(blockNumber), LogIndex: hexutil.Uint(blockLogs[left].Index)}
+			blockLogs = blockLogs[:left]
+		}
+		if page.Logs, err = api.appendErigonLogs(ctx, tx, page.Logs, blockNumber, blockLogs); err != nil {
+			return nil, err
+		}
+		if page.Cursor == nil && len(page.Logs) == limit && blockNumber < end {
+			page.Cursor = &LogsCursor{BlockNumber: hexutil.Uint64(blockNumber + 1)}
+		}
+		if page.Cursor != nil {
+			break
+		}
+	}
+	return page, nil
+}
+
+// blockIterator - ascending block numbers which may have matching logs
+type blockIterator interface {
+	next() (blockNumber uint64, ok bool, err error)
+}
+
+type bitmapBlockIterator struct {
+	it roaring.IntPeekable
+}
+
+func (i *bitmapBlockIterator) next() (uint64, bool, error) {
+	if !i.it.HasNext() {
+		return 0, false, nil
+	}
+	return uint64(i.it.Next()), true, nil
+}
+
+// txNumBlockIterator - blocks of the transactions yielded by the inverted indices of history v3
+type txNumBlockIterator struct {
+	it      *MapTxNum2BlockNumIter
+	started bool
+	last    uint64
+}
+
+func (i *txNumBlockIterator) next() (uint64, bool, error) {
+	for i.it.HasNext() {
+		_, blockNum, _, isFinalTxn, _, err := i.it.Next()
+		if err != nil {
+			return 0, false, err
+		}
+		if isFinalTxn || (i.started && blockNum == i.last) {
+			continue
+		}
+		i.started, i.last = true, blockNum
+		return blockNum, true, nil
+	}
+	return 0, false, nil
+}
+
+// readFilteredBlockLogsV3 returns the logs of the block matching the addresses and topics, taken from the block
+// receipts
+func (api *ErigonImpl) readFilteredBlockLogsV3(ctx context.Context, tx kv.Tx, blockNumber uint64, addrMap map[common.Address]struct{}, topics [][]common.Hash) ([]*types.Log, error) {
+	block, err := api.blockByNumberWithSenders(ctx, tx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block not found %d", blockNumber)
+	}
+	receipts, err := api.getReceipts(ctx, tx, block, block.Body().SendersFromTxs())
+	if err != nil {
+		return nil, err
+	}
+	var blockLogs []*types.Log
+	for _, receipt := range receipts {
+		blockLogs = append(blockLogs, types.Logs(receipt.Logs).Filter(addrMap, topics, 0)...)
+	}
+	return blockLogs, nil
+}
+
+// readFilteredBlockLogs returns the logs of the block matching the addresses and topics, with block-wide log indices
+func readFilteredBlockLogs(tx kv.Tx, blockNumber uint64, addrMap map[common.Address]struct{}, topics [][]common.Hash) ([]*types.Log, error) {
+	it, err := tx.Prefix(kv.Log, hexutility.EncodeTs(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if casted, ok := it.(kv.Closer); ok {
+		defer casted.Close()
+	}
+	var logIndex uint
+	var blockLogs []*types.Log
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		var logs types.Logs
+		if err := cbor.Unmarshal(&logs, bytes.NewReader(v)); err != nil {
+			return nil, fmt.Errorf("receipt unmarshal failed:  %w", err)
+		}
+		for _, log := range logs {
+			log.Index = logIndex
+			logIndex++
+		}
+		filtered := logs.Filter(addrMap, topics, 0)
+		txIndex := uint(binary.BigEndian.Uint32(k[8:]))
+		for _, log := range filtered {
+			log.TxIndex = txIndex
+		}
+		blockLogs = append(blockLogs, filtered...)
+	}
+	return blockLogs, nil
+}
+
+func (api *ErigonImpl) appendErigonLogs(ctx context.Context, tx kv.Tx, erigonLogs types.ErigonLogs, blockNumber uint64, blockLogs []*types.Log) (types.ErigonLogs, error) {
+	header, err := api._blockReader.HeaderByNumber(ctx, tx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block header not found: %d", blockNumber)
+	}
+	blockHash := header.Hash()
+	body, err := api._blockReader.BodyWithTransactions(ctx, tx, blockHash, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("block not found %d", blockNumber)
+	}
+	for _, log := range blockLogs {
+		erigonLog := &types.ErigonLog{
+			Address:     log.Address,
+			Topics:      log.Topics,
+			Data:        log.Data,
+			BlockNumber: blockNumber,
+			TxIndex:     log.TxIndex,
+			BlockHash:   blockHash,
+			Index:       log.Index,
+			Removed:     log.Removed,
+			Timestamp:   header.Time,
+		}
+		if log.TxIndex == uint(len(body.Transactions)) {
+			erigonLog.TxHash = bortypes.ComputeBorTxHash(blockNumber, blockHash)
+		} else {
+			erigonLog.TxHash = body.Transactions[log.TxIndex].Hash()
+		}
+		erigonLogs = append(erigonLogs, erigonLog)
+	}
+	return erigonLogs, nil
+}
@@ -51,7 +51,6 @@ func (api *ErigonImpl) GetLogsByHash(ctx context.Context, hash common.Hash) ([][
 
 // GetLogs implements erigon_getLogs. Returns an array of logs matching a given filter object.
 func (api *ErigonImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) (types.ErigonLogs, error) {
-	var begin, end uint64
 	erigonLogs := types.ErigonLogs{}
 
 	tx, beginErr := api.db.BeginRo(ctx)
@@ -60,47 +59,13 @@ func (api *ErigonImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria)
 	}
 	defer tx.Rollback()
 
-	if crit.BlockHash != nil {
-		header, err := api._blockReader.HeaderByHash(ctx, tx, *crit.BlockHash)
-		if header == nil {
-			return nil, err
-		}
-		begin = header.Number.Uint64()
-		end = header.Number.Uint64()
-
-	} else {
-		// Convert the RPC block numbers into internal representations
-		latest, err := rpchelper.GetLatestBlockNumber(tx)
-		if err != nil {
-			return nil, err
-		}
-
-		begin = 0
-		if crit.FromBlock != nil {
-			if crit.FromBlock.Sign() >= 0 {
-				begin = crit.FromBlock.Uint64()
-			} else if !crit.FromBlock.IsInt64() || crit.FromBlock.Int64() != int64(rpc.LatestBlockNumber) {
-				return nil, fmt.Errorf("negative value for FromBlock: %v", crit.FromBlock)
-			}
-		}
-		end = latest
-		if crit.ToBlock != nil {
-			if crit.ToBlock.Sign() >= 0 {
-				end = crit.ToBlock.Uint64()
-			} else if !crit.ToBlock.IsInt64() || crit.ToBlock.Int64() != int64(rpc.LatestBlockNumber) {
-				return nil, fmt.Errorf("negative value for ToBlock: %v", crit.ToBlock)
-			}
-		}
-	}
-	if end < begin {
-		return nil, fmt.Errorf("end (%d) < begin (%d)", end, begin)
-	}
-	if end > roaring.MaxUint32 {
-		return nil, fmt.Errorf("end (%d) > MaxUint32", end)
+	begin, end, ok, err := api.logsRange(ctx, tx, crit)
+	if !ok {
+		return nil, err
 	}
 	blockNumbers := bitmapdb.NewBitmap()
 	defer bitmapdb.ReturnToPool(blockNumbers)
-	if err := applyFilters(blockNumbers, tx, begin, end, crit); err != nil {
+	if err := applyFilters(blockNumbers, tx, api._agg, begin, end, crit); err != nil {
 		return nil, err
 	}
 	if blockNumbers.IsEmpty() {
@@ -195,6 +160,49 @@ func (api *ErigonImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria)
 	return erigonLogs, nil
 }
 
+// logsRange resolves the block range of the filter criteria, ok is false if the block hash of the criteria is unknown
+func (api *ErigonImpl) logsRange(ctx context.Context, tx kv.Tx, crit filters.FilterCriteria) (begin, end uint64, ok bool, err error) {
+	if crit.BlockHash != nil {
+		header, err := api._blockReader.HeaderByHash(ctx, tx, *crit.BlockHash)
+		if header == nil {
+			return 0, 0, false, err
+		}
+		begin = header.Number.Uint64()
+		end = header.Number.Uint64()
+
+	} else {
+		// Convert the RPC block numbers into internal representations
+		latest, err := rpchelper.GetLatestBlockNumber(tx)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		begin = 0
+		if crit.FromBlock != nil {
+			if crit.FromBlock.Sign() >= 0 {
+				begin = crit.FromBlock.Uint64()
+			} else if !crit.FromBlock.IsInt64() || crit.FromBlock.Int64() != int64(rpc.LatestBlockNumber) {
+				return 0, 0, false, fmt.Errorf("negative value for FromBlock: %v", crit.FromBlock)
+			}
+		}
+		end = latest
+		if crit.ToBlock != nil {
+			if crit.ToBlock.Sign() >= 0 {
+				end = crit.ToBlock.Uint64()
+			} else if !crit.ToBlock.IsInt64() || crit.ToBlock.Int64() != int64(rpc.LatestBlockNumber) {
+				return 0, 0, false, fmt.Errorf("negative value for ToBlock: %v", crit.ToBlock)
+			}
+		}
+	}
+	if end < begin {
+		return 0, 0, false, fmt.Errorf("end (%d) < begin (%d)", end, begin)
+	}
+	if end > roaring.MaxUint32 {
+		return 0, 0, false, fmt.Errorf("end (%d) > MaxUint32", end)
+	}
+	return begin, end, true, nil
+}
+
 // GetLatestLogs implements erigon_getLatestLogs.
 // Return specific number of logs or block matching a give filter objects by descend.
 // IgnoreTopicsOrder option provide a way to match the logs with addresses and topics without caring about the topics' orders
@@ -262,7 +270,7 @@ func (api *ErigonImpl) GetLatestLogs(ctx context.Context, crit filters.FilterCri
 
 	blockNumbers := bitmapdb.NewBitmap()
 	defer bitmapdb.ReturnToPool(blockNumbers)
-	if err := applyFilters(blockNumbers, tx, begin, end, crit); err != nil {
+	if err := applyFilters(blockNumbers, tx, api._agg, begin, end, crit); err != nil {
 		return erigonLogs, err
 	}
 	if blockNumbers.IsEmpty() {
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon-lib/kv"
 
 	"github.com/ledgerwatch/log/v3"
@@ -57,6 +58,67 @@ func TestGetLogs(t *testing.T) {
 	}
 }
 
+func TestErigonGetLogsPaginated(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewErigonAPI(newBaseApiForTest(m), m.DB, nil)
+	ethApi := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 1e18, 100_000, false, 100_000, 128, log.New())
+	crit := filters.FilterCriteria{FromBlock: big.NewInt(0), ToBlock: big.NewInt(rpc.LatestBlockNumber.Int64())}
+
+	// same logs as eth_getLogs, which works with both history v2 and v3
+	checkLogs := func(crit filters.FilterCriteria, logs types.ErigonLogs) {
+		expectedLogs, err := ethApi.GetLogs(m.Ctx, crit)
+		require.NoError(t, err)
+		require.NotEmpty(t, expectedLogs)
+		require.Len(t, logs, len(expectedLogs))
+		for i, l := range expectedLogs {
+			require.Equal(t, l.TxHash, logs[i].TxHash)
+			require.Equal(t, l.BlockNumber, logs[i].BlockNumber)
+			require.Equal(t, l.Address, logs[i].Address)
+			require.Equal(t, l.Topics, logs[i].Topics)
+			require.Equal(t, l.Data, logs[i].Data)
+		}
+	}
+
+	maxPageSize := hexutil.Uint64(maxLogsPageSize)
+	page, err := api.GetLogsPaginated(m.Ctx, crit, nil, &maxPageSize)
+	require.NoError(t, err)
+	require.Nil(t, page.Cursor)
+	allLogs := page.Logs
+	checkLogs(crit, allLogs)
+	if !m.HistoryV3 {
+		expectedLogs, err := api.GetLogs(m.Ctx, crit)
+		require.NoError(t, err)
+		require.Equal(t, expectedLogs, allLogs)
+	}
+
+	var logs types.ErigonLogs
+	var cursor *LogsCursor
+	pageSize := hexutil.Uint64(1)
+	for pages := 0; ; pages++ {
+		require.LessOrEqual(t, pages, len(allLogs))
+		page, err := api.GetLogsPaginated(m.Ctx, crit, cursor, &pageSize)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Logs), int(pageSize))
+		logs = append(logs, page.Logs...)
+		if cursor = page.Cursor; cursor == nil {
+			break
+		}
+	}
+	require.Equal(t, allLogs, logs)
+
+	// address+topic0 criteria are served by the composite index (history v2)
+	crit.Addresses = []libcommon.Address{allLogs[0].Address}
+	crit.Topics = [][]libcommon.Hash{{allLogs[0].Topics[0]}}
+	page, err = api.GetLogsPaginated(m.Ctx, crit, nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, page.Cursor)
+	checkLogs(crit, page.Logs)
+
+	pageSize = 0
+	_, err = api.GetLogsPaginated(m.Ctx, crit, nil, &pageSize)
+	require.Error(t, err)
+}
+
 func TestErigonGetLatestLogs(t *testing.T) {
 	assert := assert.New(t)
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
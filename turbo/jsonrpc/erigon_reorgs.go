@@ -0,0 +1,107 @@
+package jsonrpc
+
+import (
+	"context"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// maxReorgsPerRequest - cap on the number of entries returned by erigon_getReorgs
+const maxReorgsPerRequest = 1000
+
+// ReorgCriteria filters the reorg log, all fields are optional. To page through the log, pass the id of the last
+// returned entry plus one as FromID of the next request.
+type ReorgCriteria struct {
+	FromID    *hexutil.Uint64 `json:"fromId"`    // first reorg log entry to look at
+	Limit     *hexutil.Uint64 `json:"limit"`     // max number of entries to return, capped at maxReorgsPerRequest
+	FromTime  *hexutil.Uint64 `json:"fromTime"`  // unix seconds
+	FromBlock *hexutil.Uint64 `json:"fromBlock"` // reorgs which dropped or added blocks at or above it
+}
+
+// Reorg is the RPC representation of a reorg log entry
+type Reorg struct {
+	ID             hexutil.Uint64     `json:"id"`
+	OldHead        common.Hash        `json:"oldHead"`
+	OldNumber      hexutil.Uint64     `json:"oldNumber"`
+	NewHead        common.Hash        `json:"newHead"`
+	NewNumber      hexutil.Uint64     `json:"newNumber"`
+	CommonAncestor common.Hash        `json:"commonAncestor"`
+	AncestorNumber hexutil.Uint64     `json:"ancestorNumber"`
+	Depth          hexutil.Uint64     `json:"depth"`
+	Dropped        []common.Hash      `json:"dropped"`
+	Added          []common.Hash      `json:"added"`
+	Timestamp      hexutil.Uint64     `json:"timestamp"`
+	Trigger        types.ReorgTrigger `json:"trigger"`
+}
+
+func newRPCReorg(id uint64, r *types.Reorg) *Reorg {
+	added := r.Added
+	if added == nil {
+		added = []common.Hash{}
+	}
+	return &Reorg{
+		ID:             hexutil.Uint64(id),
+		OldHead:        r.OldHead,
+		OldNumber:      hexutil.Uint64(r.OldNumber),
+		NewHead:        r.NewHead,
+		NewNumber:      hexutil.Uint64(r.NewNumber),
+		CommonAncestor: r.Ancestor,
+		AncestorNumber: hexutil.Uint64(r.AncestorNumber),
+		Depth:          hexutil.Uint64(r.Depth()),
+		Dropped:        r.Dropped,
+		Added:          added,
+		Timestamp:      hexutil.Uint64(r.Time),
+		Trigger:        r.Trigger,
+	}
+}
+
+func (crit ReorgCriteria) matches(r *types.Reorg) bool {
+	if crit.FromTime != nil && r.Time < uint64(*crit.FromTime) {
+		return false
+	}
+	if crit.FromBlock != nil && max(r.OldNumber, r.NewNumber) < uint64(*crit.FromBlock) {
+		return false
+	}
+	return true
+}
+
+// reorgsFrom returns the matching reorg log entries, at most limit of them
+func reorgsFrom(tx kv.Tx, crit ReorgCriteria, limit int) ([]*Reorg, error) {
+	var fromID uint64
+	if crit.FromID != nil {
+		fromID = uint64(*crit.FromID)
+	}
+	result := []*Reorg{}
+	if err := rawdb.ForEachReorg(tx, fromID, func(id uint64, r *types.Reorg) (bool, error) {
+		if crit.matches(r) {
+			result = append(result, newRPCReorg(id, r))
+		}
+		return len(result) < limit, nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetReorgs implements erigon_getReorgs. Returns recorded reorgs of the canonical chain, oldest first. The node
+// only keeps its latest reorgs, older ids are no longer returned.
+func (api *ErigonImpl) GetReorgs(ctx context.Context, crit ReorgCriteria) ([]*Reorg, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	limit := maxReorgsPerRequest
+	if crit.Limit != nil && uint64(*crit.Limit) < maxReorgsPerRequest {
+		limit = int(*crit.Limit)
+	}
+	if limit == 0 {
+		return []*Reorg{}, nil
+	}
+	return reorgsFrom(tx, crit, limit)
+}
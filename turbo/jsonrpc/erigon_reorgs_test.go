@@ -0,0 +1,95 @@
+package jsonrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+)
+
+func TestGetReorgs(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	ctx := context.Background()
+
+	var head uint64
+	added := []common.Hash{{0x01}, {0x02}}
+	require.NoError(t, m.DB.Update(ctx, func(tx kv.RwTx) (err error) {
+		if head, err = stages.GetStageProgress(tx, stages.Headers); err != nil {
+			return err
+		}
+		// nothing is dropped - not a reorg
+		if err = stagedsync.RecordReorg(tx, types.ReorgTriggerEngineAPI, head, added); err != nil {
+			return err
+		}
+		if err = stagedsync.RecordReorg(tx, types.ReorgTriggerEngineAPI, head-3, added); err != nil {
+			return err
+		}
+		return stagedsync.RecordReorg(tx, types.ReorgTriggerManual, head-1, nil)
+	}))
+
+	api := NewErigonAPI(newBaseApiForTest(m), m.DB, nil)
+	reorgs, err := api.GetReorgs(ctx, ReorgCriteria{})
+	require.NoError(t, err)
+	require.Len(t, reorgs, 2)
+
+	tx, err := m.DB.BeginRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	oldHead, err := rawdb.ReadCanonicalHash(tx, head)
+	require.NoError(t, err)
+	ancestor, err := rawdb.ReadCanonicalHash(tx, head-3)
+	require.NoError(t, err)
+
+	r := reorgs[0]
+	require.Equal(t, types.ReorgTriggerEngineAPI, r.Trigger)
+	require.Equal(t, uint64(3), uint64(r.Depth))
+	require.Len(t, r.Dropped, 3)
+	require.Equal(t, oldHead, r.OldHead)
+	require.Equal(t, head, uint64(r.OldNumber))
+	require.Equal(t, ancestor, r.CommonAncestor)
+	require.Equal(t, head-3, uint64(r.AncestorNumber))
+	require.Equal(t, added, r.Added)
+	require.Equal(t, common.Hash{0x02}, r.NewHead)
+	require.Equal(t, head-1, uint64(r.NewNumber))
+
+	r = reorgs[1]
+	require.Equal(t, types.ReorgTriggerManual, r.Trigger)
+	require.Equal(t, uint64(1), uint64(r.Depth))
+	require.Equal(t, head-1, uint64(r.NewNumber))
+	require.Empty(t, r.Added)
+
+	fromBlock := hexutil.Uint64(head)
+	reorgs, err = api.GetReorgs(ctx, ReorgCriteria{FromBlock: &fromBlock})
+	require.NoError(t, err)
+	require.Len(t, reorgs, 2)
+	fromBlock++
+	reorgs, err = api.GetReorgs(ctx, ReorgCriteria{FromBlock: &fromBlock})
+	require.NoError(t, err)
+	require.Empty(t, reorgs)
+
+	// paging through the log
+	limit := hexutil.Uint64(1)
+	reorgs, err = api.GetReorgs(ctx, ReorgCriteria{Limit: &limit})
+	require.NoError(t, err)
+	require.Len(t, reorgs, 1)
+	require.Equal(t, types.ReorgTriggerEngineAPI, reorgs[0].Trigger)
+	fromID := reorgs[0].ID + 1
+	reorgs, err = api.GetReorgs(ctx, ReorgCriteria{FromID: &fromID, Limit: &limit})
+	require.NoError(t, err)
+	require.Len(t, reorgs, 1)
+	require.Equal(t, types.ReorgTriggerManual, reorgs[0].Trigger)
+	fromID = reorgs[0].ID + 1
+	reorgs, err = api.GetReorgs(ctx, ReorgCriteria{FromID: &fromID})
+	require.NoError(t, err)
+	require.Empty(t, reorgs)
+}
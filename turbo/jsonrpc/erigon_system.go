@@ -39,6 +39,47 @@ func (api *ErigonImpl) Forks(ctx context.Context) (Forks, error) {
 	return Forks{genesis.Hash(), heightForks, timeForks}, nil
 }
 
+// PruneInfo is a data type to report which blocks this node can serve each kind of data for
+type PruneInfo struct {
+	Mode     string         `json:"mode"`
+	Head     hexutil.Uint64 `json:"head"`
+	State    hexutil.Uint64 `json:"state"`
+	Receipts hexutil.Uint64 `json:"receipts"`
+	Logs     hexutil.Uint64 `json:"logs"`
+	Traces   hexutil.Uint64 `json:"traces"`
+	TxLookup hexutil.Uint64 `json:"txLookup"`
+}
+
+// PruneInfo implements erigon_pruneInfo. Returns the prune mode and the earliest block available
+// for state, receipts, logs, traces and tx lookup. Requests below these blocks fail with a "pruned" error.
+// A lookup by hash can't tell a pruned transaction from an unknown one, it returns null: txLookup is only
+// reported here.
+func (api *ErigonImpl) PruneInfo(ctx context.Context) (*PruneInfo, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	mode, err := api.pruneMode(tx)
+	if err != nil {
+		return nil, err
+	}
+	available, head, err := api.pruneAvailability(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &PruneInfo{
+		Mode:     mode.String(),
+		Head:     hexutil.Uint64(head),
+		State:    hexutil.Uint64(available.State),
+		Receipts: hexutil.Uint64(available.Receipts),
+		Logs:     hexutil.Uint64(available.Logs),
+		Traces:   hexutil.Uint64(available.Traces),
+		TxLookup: hexutil.Uint64(available.TxLookup),
+	}, nil
+}
+
 // Post the merge eth_blockNumber will return latest forkChoiceHead block number
 // erigon_blockNumber will return latest executed block number or any block number requested
 func (api *ErigonImpl) BlockNumber(ctx context.Context, rpcBlockNumPtr *rpc.BlockNumber) (hexutil.Uint64, error) {
@@ -0,0 +1,94 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+func TestPruneInfo(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	ctx := context.Background()
+
+	mode := prune.DefaultMode
+	mode.History = prune.Distance(2)
+	mode.Receipts = prune.Before(3)
+	require.NoError(t, m.DB.Update(ctx, func(tx kv.RwTx) error {
+		return prune.Override(tx, mode)
+	}))
+
+	base := newBaseApiForTest(m)
+	info, err := NewErigonAPI(base, m.DB, nil).PruneInfo(ctx)
+	require.NoError(t, err)
+	require.Greater(t, uint64(info.Head), uint64(2))
+	require.Equal(t, uint64(info.Head)-2, uint64(info.State))
+	require.Equal(t, uint64(2), uint64(info.Receipts))
+	require.Equal(t, uint64(2), uint64(info.Logs))
+	require.Equal(t, uint64(0), uint64(info.Traces))
+	require.Equal(t, uint64(0), uint64(info.TxLookup))
+
+	api := NewEthAPI(base, m.DB, nil, nil, nil, 5000000, 1e18, 100_000, false, 100_000, 128, log.New())
+	_, err = api.GetBalance(ctx, common.Address{}, rpc.BlockNumberOrHashWithNumber(1))
+	var pruned rpc.PrunedError
+	require.True(t, errors.As(err, &pruned))
+	require.Equal(t, prunedState, pruned.Data)
+	require.Equal(t, uint64(1), pruned.Block)
+	require.Equal(t, uint64(info.State), pruned.Earliest)
+
+	_, err = api.GetBalance(ctx, common.Address{}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	require.NoError(t, err)
+}
+
+// pendingTxPool holds the pending transactions of the tests
+type pendingTxPool struct {
+	txpool.TxpoolClient
+	pending map[common.Hash][]byte
+}
+
+func (p pendingTxPool) Transactions(_ context.Context, in *txpool.TransactionsRequest, _ ...grpc.CallOption) (*txpool.TransactionsReply, error) {
+	reply := &txpool.TransactionsReply{RlpTxs: make([][]byte, len(in.Hashes))}
+	for i, hash := range in.Hashes {
+		reply.RlpTxs[i] = p.pending[gointerfaces.ConvertH256ToHash(hash)]
+	}
+	return reply, nil
+}
+
+func TestPrunedTxLookup(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	ctx := context.Background()
+	pool := pendingTxPool{pending: map[common.Hash][]byte{}}
+
+	mode := prune.DefaultMode
+	mode.TxIndex = prune.Before(3)
+	require.NoError(t, m.DB.Update(ctx, func(tx kv.RwTx) error {
+		return prune.Override(tx, mode)
+	}))
+	base := newBaseApiForTest(m)
+	api := NewEthAPI(base, m.DB, nil, pool, nil, 5000000, 1e18, 100_000, false, 100_000, 128, log.New())
+
+	// a hash missing from a pruned lookup index may as well be unknown or not mined yet, it's null
+	receipt, err := api.GetTransactionReceipt(ctx, common.Hash{0x01})
+	require.NoError(t, err)
+	require.Nil(t, receipt)
+	txn, err := api.GetTransactionByHash(ctx, common.Hash{0x01})
+	require.NoError(t, err)
+	require.Nil(t, txn)
+
+	// the boundary is reported by erigon_pruneInfo
+	info, err := NewErigonAPI(base, m.DB, nil).PruneInfo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), uint64(info.TxLookup))
+}
@@ -12,11 +12,11 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	"google.golang.org/grpc"
 
-	"github.com/ledgerwatch/erigon/turbo/rpchelper"
-
 	txpool_proto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
 
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
@@ -38,6 +38,18 @@ func (api *APIImpl) GetBalance(ctx context.Context, address libcommon.Address, b
 		return nil, fmt.Errorf("read chain config: %v", err)
 	}
 	if chainConfig.IsOptimismPreBedrock(blockNum) {
+		var acc *accounts.Account
+		if ok, err := api.readLegacyState(ctx, blockNum, func(reader state.StateReader) (err error) {
+			acc, err = reader.ReadAccountData(address)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("cant get a balance for account %x: %w", address.String(), err)
+		} else if ok {
+			if acc == nil {
+				return (*hexutil.Big)(big.NewInt(0)), nil
+			}
+			return (*hexutil.Big)(acc.Balance.ToBig()), nil
+		}
 		if api.historicalRPCService == nil {
 			return nil, rpc.ErrNoHistoricalFallback
 		}
@@ -47,8 +59,11 @@ func (api *APIImpl) GetBalance(ctx context.Context, address libcommon.Address, b
 		}
 		return &result, nil
 	}
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return nil, err
+	}
 
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), "")
+	reader, err := api.createStateReader(ctx, tx, blockNrOrHash, "")
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +110,16 @@ func (api *APIImpl) GetTransactionCount(ctx context.Context, address libcommon.A
 		return nil, fmt.Errorf("read chain config: %v", err)
 	}
 	if chainConfig.IsOptimismPreBedrock(blockNum) {
+		nonce := hexutil.Uint64(0)
+		if ok, err := api.readLegacyState(ctx, blockNum, func(reader state.StateReader) error {
+			acc, err := reader.ReadAccountData(address)
+			if acc != nil {
+				nonce = hexutil.Uint64(acc.Nonce)
+			}
+			return err
+		}); err != nil || ok {
+			return &nonce, err
+		}
 		if api.historicalRPCService == nil {
 			return nil, rpc.ErrNoHistoricalFallback
 		}
@@ -104,8 +129,11 @@ func (api *APIImpl) GetTransactionCount(ctx context.Context, address libcommon.A
 		}
 		return &result, nil
 	}
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return nil, err
+	}
 
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), "")
+	reader, err := api.createStateReader(ctx, tx, blockNrOrHash, "")
 	if err != nil {
 		return nil, err
 	}
@@ -135,6 +163,20 @@ func (api *APIImpl) GetCode(ctx context.Context, address libcommon.Address, bloc
 		return nil, fmt.Errorf("read chain config: %v", err)
 	}
 	if chainConfig.IsOptimismPreBedrock(blockNum) {
+		code := hexutility.Bytes("")
+		if ok, err := api.readLegacyState(ctx, blockNum, func(reader state.StateReader) error {
+			acc, err := reader.ReadAccountData(address)
+			if acc == nil || err != nil {
+				return err
+			}
+			res, err := reader.ReadAccountCode(address, acc.Incarnation, acc.CodeHash)
+			if res != nil {
+				code = res
+			}
+			return err
+		}); err != nil || ok {
+			return code, err
+		}
 		if api.historicalRPCService == nil {
 			return nil, rpc.ErrNoHistoricalFallback
 		}
@@ -144,8 +186,11 @@ func (api *APIImpl) GetCode(ctx context.Context, address libcommon.Address, bloc
 		}
 		return result, nil
 	}
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return nil, err
+	}
 
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+	reader, err := api.createStateReader(ctx, tx, blockNrOrHash, chainConfig.ChainName)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +226,18 @@ func (api *APIImpl) GetStorageAt(ctx context.Context, address libcommon.Address,
 		return hexutility.Encode(common.LeftPadBytes(empty, 32)), fmt.Errorf("read chain config: %v", err)
 	}
 	if chainConfig.IsOptimismPreBedrock(blockNum) {
+		var res []byte
+		if ok, err := api.readLegacyState(ctx, blockNum, func(reader state.StateReader) error {
+			acc, err := reader.ReadAccountData(address)
+			if acc == nil || err != nil {
+				return err
+			}
+			location := libcommon.HexToHash(index)
+			res, err = reader.ReadAccountStorage(address, acc.Incarnation, &location)
+			return err
+		}); err != nil || ok {
+			return hexutility.Encode(common.LeftPadBytes(res, 32)), err
+		}
 		if api.historicalRPCService == nil {
 			return hexutility.Encode(common.LeftPadBytes(empty, 32)), rpc.ErrNoHistoricalFallback
 		}
@@ -190,8 +247,11 @@ func (api *APIImpl) GetStorageAt(ctx context.Context, address libcommon.Address,
 		}
 		return hexutility.Encode(common.LeftPadBytes(result, 32)), nil
 	}
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return hexutility.Encode(common.LeftPadBytes(empty, 32)), err
+	}
 
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), "")
+	reader, err := api.createStateReader(ctx, tx, blockNrOrHash, "")
 	if err != nil {
 		return hexutility.Encode(common.LeftPadBytes(empty, 32)), err
 	}
@@ -216,7 +276,7 @@ func (api *APIImpl) Exist(ctx context.Context, address libcommon.Address, blockN
 	}
 	defer tx.Rollback()
 
-	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), "")
+	reader, err := api.createStateReader(ctx, tx, blockNrOrHash, "")
 	if err != nil {
 		return false, err
 	}
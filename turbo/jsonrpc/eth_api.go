@@ -87,11 +87,13 @@ type EthAPI interface {
 	ChainId(ctx context.Context) (hexutil.Uint64, error) /* called eth_protocolVersion elsewhere */
 	ProtocolVersion(_ context.Context) (hexutil.Uint, error)
 	GasPrice(_ context.Context) (*hexutil.Big, error)
+	MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error)
 
 	// Sending related (see ./eth_call.go)
 	Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *ethapi2.StateOverrides) (hexutility.Bytes, error)
 	EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error)
 	SendRawTransaction(ctx context.Context, encodedTx hexutility.Bytes) (common.Hash, error)
+	SendBundle(ctx context.Context, args SendBundleArgs) (*SendBundleResult, error)
 	SendTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
 	Sign(ctx context.Context, _ common.Address, _ hexutility.Bytes) (hexutility.Bytes, error)
 	SignTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
@@ -130,6 +132,10 @@ type BaseAPI struct {
 	// Optimism specific field
 	seqRPCService        *rpc.Client
 	historicalRPCService *rpc.Client
+	pendingSimulator     *pendingBlockSimulator
+
+	legacyStateLock sync.Mutex
+	legacyState     kv.RoDB // pre-Bedrock state, see legacyStateDB
 }
 
 func NewBaseApi(f *rpchelper.Filters, stateCache kvcache.Cache, blockReader services.FullBlockReader, agg *libstate.Aggregator, singleNodeMode bool, evmCallTimeout time.Duration, engine consensus.EngineReader, dirs datadir.Dirs, seqRPCService *rpc.Client, historicalRPCService *rpc.Client) *BaseAPI {
@@ -289,8 +295,19 @@ func (api *BaseAPI) chainConfigWithGenesis(ctx context.Context, tx kv.Tx) (*chai
 	return cc, genesisBlock, nil
 }
 
-func (api *BaseAPI) pendingBlock() *types.Block {
-	return api.filters.LastPendingBlock()
+// pendingBlock returns the pending block: simulated on OP chains, built by the mining stage otherwise
+func (api *BaseAPI) pendingBlock(ctx context.Context, tx kv.Tx) (*types.Block, error) {
+	pending, err := api.simulatedPendingBlock(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return pending.block, nil
+	}
+	if api.filters == nil {
+		return nil, nil
+	}
+	return api.filters.LastPendingBlock(), nil
 }
 
 func (api *BaseAPI) blockByRPCNumber(ctx context.Context, number rpc.BlockNumber, tx kv.Tx) (*types.Block, error) {
@@ -312,34 +329,68 @@ func (api *BaseAPI) headerByRPCNumber(ctx context.Context, number rpc.BlockNumbe
 	return api._blockReader.Header(ctx, tx, h, n)
 }
 
+// Kinds of prunable data, see prune.Availability
+const (
+	prunedState    = "state"
+	prunedReceipts = "receipts"
+	prunedLogs     = "logs"
+	prunedTraces   = "traces"
+	prunedTxLookup = "txLookup"
+)
+
 // checks the pruning state to see if we would hold information about this
 // block in state history or not.  Some strange issues arise getting account
 // history for blocks that have been pruned away giving nonce too low errors
 // etc. as red herrings
 func (api *BaseAPI) checkPruneHistory(tx kv.Tx, block uint64) error {
-	p, err := api.pruneMode(tx)
+	return api.checkPruned(tx, prunedState, block)
+}
+
+// checkPruned returns rpc.PrunedError if the given kind of data was pruned away for the block
+func (api *BaseAPI) checkPruned(tx kv.Tx, data string, block uint64) error {
+	available, _, err := api.pruneAvailability(tx)
 	if err != nil {
 		return err
 	}
+	var earliest uint64
+	switch data {
+	case prunedState:
+		earliest = available.State
+	case prunedReceipts:
+		earliest = available.Receipts
+	case prunedLogs:
+		earliest = available.Logs
+	case prunedTraces:
+		earliest = available.Traces
+	case prunedTxLookup:
+		earliest = available.TxLookup
+	default:
+		return fmt.Errorf("unknown kind of prunable data: %s", data)
+	}
+	if block < earliest {
+		return rpc.PrunedError{Data: data, Block: block, Earliest: earliest}
+	}
+	return nil
+}
+
+// pruneAvailability returns the earliest servable blocks for the prune mode of the node and the head they are relative to
+func (api *BaseAPI) pruneAvailability(tx kv.Tx) (prune.Availability, uint64, error) {
+	p, err := api.pruneMode(tx)
+	if err != nil {
+		return prune.Availability{}, 0, err
+	}
 	if p == nil {
 		// no prune info found
-		return nil
+		return prune.Availability{}, 0, nil
 	}
-	if p.History.Enabled() {
-		latest, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), tx, api.filters)
-		if err != nil {
-			return err
-		}
-		if latest <= 1 {
-			return nil
-		}
-		prunedTo := p.History.PruneTo(latest)
-		if block < prunedTo {
-			return fmt.Errorf("history has been pruned for this block")
-		}
+	latest, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), tx, api.filters)
+	if err != nil {
+		return prune.Availability{}, 0, err
 	}
-
-	return nil
+	if latest <= 1 {
+		return prune.Availability{}, latest, nil
+	}
+	return p.Availability(latest), latest, nil
 }
 
 func (api *BaseAPI) pruneMode(tx kv.Tx) (*prune.Mode, error) {
@@ -355,7 +406,7 @@ func (api *BaseAPI) pruneMode(tx kv.Tx) (*prune.Mode, error) {
 
 	api._pruneMode.Store(&mode)
 
-	return p, nil
+	return &mode, nil
 }
 
 // APIImpl is implementation of the EthAPI interface based on remote Db access
@@ -404,28 +455,29 @@ func (api *APIImpl) relayToHistoricalBackend(ctx context.Context, result interfa
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	BlockHash           *common.Hash       `json:"blockHash"`
-	BlockNumber         *hexutil.Big       `json:"blockNumber"`
-	From                common.Address     `json:"from"`
-	Gas                 hexutil.Uint64     `json:"gas"`
-	GasPrice            *hexutil.Big       `json:"gasPrice,omitempty"`
-	Tip                 *hexutil.Big       `json:"maxPriorityFeePerGas,omitempty"`
-	FeeCap              *hexutil.Big       `json:"maxFeePerGas,omitempty"`
-	Hash                common.Hash        `json:"hash"`
-	Input               hexutility.Bytes   `json:"input"`
-	Nonce               hexutil.Uint64     `json:"nonce"`
-	To                  *common.Address    `json:"to,omitempty"`
-	TransactionIndex    *hexutil.Uint64    `json:"transactionIndex"`
-	Value               *hexutil.Big       `json:"value"`
-	Type                hexutil.Uint64     `json:"type"`
-	Accesses            *types2.AccessList `json:"accessList,omitempty"`
-	ChainID             *hexutil.Big       `json:"chainId,omitempty"`
-	MaxFeePerBlobGas    *hexutil.Big       `json:"maxFeePerBlobGas,omitempty"`
-	BlobVersionedHashes []common.Hash      `json:"blobVersionedHashes,omitempty"`
-	V                   *hexutil.Big       `json:"v,omitempty"`
-	YParity             *hexutil.Big       `json:"yParity,omitempty"`
-	R                   *hexutil.Big       `json:"r,omitempty"`
-	S                   *hexutil.Big       `json:"s,omitempty"`
+	BlockHash           *common.Hash           `json:"blockHash"`
+	BlockNumber         *hexutil.Big           `json:"blockNumber"`
+	From                common.Address         `json:"from"`
+	Gas                 hexutil.Uint64         `json:"gas"`
+	GasPrice            *hexutil.Big           `json:"gasPrice,omitempty"`
+	Tip                 *hexutil.Big           `json:"maxPriorityFeePerGas,omitempty"`
+	FeeCap              *hexutil.Big           `json:"maxFeePerGas,omitempty"`
+	Hash                common.Hash            `json:"hash"`
+	Input               hexutility.Bytes       `json:"input"`
+	Nonce               hexutil.Uint64         `json:"nonce"`
+	To                  *common.Address        `json:"to,omitempty"`
+	TransactionIndex    *hexutil.Uint64        `json:"transactionIndex"`
+	Value               *hexutil.Big           `json:"value"`
+	Type                hexutil.Uint64         `json:"type"`
+	Accesses            *types2.AccessList     `json:"accessList,omitempty"`
+	ChainID             *hexutil.Big           `json:"chainId,omitempty"`
+	MaxFeePerBlobGas    *hexutil.Big           `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes []common.Hash          `json:"blobVersionedHashes,omitempty"`
+	Authorizations      *[]types.Authorization `json:"authorizationList,omitempty"`
+	V                   *hexutil.Big           `json:"v,omitempty"`
+	YParity             *hexutil.Big           `json:"yParity,omitempty"`
+	R                   *hexutil.Big           `json:"r,omitempty"`
+	S                   *hexutil.Big           `json:"s,omitempty"`
 
 	// deposit-tx only
 	SourceHash *common.Hash `json:"sourceHash,omitempty"`
@@ -524,6 +576,18 @@ func NewRPCTransaction(tx types.Transaction, blockHash common.Hash, blockNumber
 		result.GasPrice = computeGasPrice(tx, blockHash, baseFee)
 		result.MaxFeePerBlobGas = (*hexutil.Big)(t.MaxFeePerBlobGas.ToBig())
 		result.BlobVersionedHashes = t.BlobVersionedHashes
+	case *types.SetCodeTransaction:
+		chainId.Set(t.ChainID)
+		result.ChainID = (*hexutil.Big)(chainId.ToBig())
+		result.Tip = (*hexutil.Big)(t.Tip.ToBig())
+		result.FeeCap = (*hexutil.Big)(t.FeeCap.ToBig())
+		result.YParity = (*hexutil.Big)(t.V.ToBig())
+		result.V = (*hexutil.Big)(t.V.ToBig())
+		result.R = (*hexutil.Big)(t.R.ToBig())
+		result.S = (*hexutil.Big)(t.S.ToBig())
+		result.Accesses = &t.AccessList
+		result.Authorizations = &t.Authorizations
+		result.GasPrice = computeGasPrice(tx, blockHash, baseFee)
 	}
 	signer := types.LatestSignerForChainID(chainId.ToBig())
 	result.From, _ = tx.Sender(*signer)
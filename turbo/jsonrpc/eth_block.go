@@ -209,15 +209,31 @@ func (api *APIImpl) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber
 	if err != nil {
 		return nil, err
 	}
+	var pending *simulatedPendingBlock
 	if chainConfig.IsOptimism() && number == rpc.PendingBlockNumber {
-		number = rpc.LatestBlockNumber
+		if pending, err = api.simulatedPendingBlock(ctx, tx); err != nil {
+			return nil, err
+		}
+		if pending == nil {
+			number = rpc.LatestBlockNumber
+		}
 	}
 
-	b, err := api.blockByNumber(ctx, number, tx)
-	if err != nil {
+	var b *types.Block
+	if pending != nil {
+		b = pending.block
+	} else if b, err = api.blockByNumber(ctx, number, tx); err != nil {
 		return nil, err
 	}
 	if b == nil {
+		// pre-Bedrock blocks are served locally once imported, by the historical backend otherwise
+		if number >= 0 && chainConfig.IsOptimismPreBedrock(uint64(number)) && api.historicalRPCService != nil {
+			var result map[string]interface{}
+			if err := api.relayToHistoricalBackend(ctx, &result, "eth_getBlockByNumber", hexutil.EncodeUint64(uint64(number)), fullTx); err != nil {
+				return nil, fmt.Errorf("historical backend error: %w", err)
+			}
+			return result, nil
+		}
 		return nil, nil
 	}
 	additionalFields := make(map[string]interface{})
@@ -239,6 +255,9 @@ func (api *APIImpl) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber
 	}
 
 	receipts := rawdb.ReadRawReceipts(tx, b.NumberU64())
+	if pending != nil {
+		receipts = pending.receipts
+	}
 	response, err := ethapi.RPCMarshalBlockEx(b, true, fullTx, borTx, borTxHash, additionalFields, receipts)
 	if err == nil && number == rpc.PendingBlockNumber {
 		// Pending blocks need to nil out a few fields
@@ -324,7 +343,7 @@ func (api *APIImpl) GetBlockTransactionCountByNumber(ctx context.Context, blockN
 	defer tx.Rollback()
 
 	if blockNr == rpc.PendingBlockNumber {
-		b, err := api.blockByRPCNumber(ctx, blockNr, tx)
+		b, err := api.blockByNumber(ctx, blockNr, tx)
 		if err != nil {
 			return nil, err
 		}
@@ -420,8 +439,8 @@ func (api *APIImpl) blockByNumber(ctx context.Context, number rpc.BlockNumber, t
 		return api.blockByRPCNumber(ctx, number, tx)
 	}
 
-	if block := api.pendingBlock(); block != nil {
-		return block, nil
+	if block, err := api.pendingBlock(ctx, tx); err != nil || block != nil {
+		return block, err
 	}
 
 	block, err := api.ethBackend.PendingBlock(ctx)
@@ -65,6 +65,9 @@ func (api *APIImpl) Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHa
 		}
 		return result, nil
 	}
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return nil, err
+	}
 
 	engine := api.engine()
 
@@ -72,23 +75,29 @@ func (api *APIImpl) Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHa
 		args.Gas = (*hexutil.Uint64)(&api.GasCap)
 	}
 
-	blockNumber, hash, _, err := rpchelper.GetCanonicalBlockNumber(blockNrOrHash, tx, api.filters) // DoCall cannot be executed on non-canonical blocks
+	header, stateReader, err := api.pendingHeaderAndState(ctx, tx, blockNrOrHash)
 	if err != nil {
 		return nil, err
 	}
-	block, err := api.blockWithSenders(ctx, tx, hash, blockNumber)
-	if err != nil {
-		return nil, err
-	}
-	if block == nil {
-		return nil, nil
-	}
+	if header == nil {
+		blockNumber, hash, _, err := rpchelper.GetCanonicalBlockNumber(blockNrOrHash, tx, api.filters) // DoCall cannot be executed on non-canonical blocks
+		if err != nil {
+			return nil, err
+		}
+		block, err := api.blockWithSenders(ctx, tx, hash, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, nil
+		}
 
-	stateReader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
-	if err != nil {
-		return nil, err
+		stateReader, err = rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+		if err != nil {
+			return nil, err
+		}
+		header = block.HeaderNoCopy()
 	}
-	header := block.HeaderNoCopy()
 	result, err := transactions.DoCall(ctx, engine, args, tx, blockNrOrHash, header, overrides, api.GasCap, chainConfig, stateReader, api._blockReader, api.evmCallTimeout)
 	if err != nil {
 		return nil, err
@@ -179,10 +188,17 @@ func (api *APIImpl) EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
+	// the estimation runs on the simulated pending block when there is one, on the latest block otherwise
+	pendingHeader, pendingReader, err := api.pendingHeaderAndState(ctx, dbtx, bNrOrHash)
+	if err != nil {
+		return 0, err
+	}
 
 	// Determine the highest gas limit can be used during the estimation.
 	if args.Gas != nil && uint64(*args.Gas) >= params.TxGas {
 		hi = uint64(*args.Gas)
+	} else if pendingHeader != nil {
+		hi = pendingHeader.GasLimit
 	} else {
 		// Retrieve the block to act as the gas ceiling
 		h, err := headerByNumberOrHash(ctx, dbtx, bNrOrHash, api)
@@ -219,11 +235,14 @@ func (api *APIImpl) EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs
 	}
 	// Recap the highest gas limit with account's available balance.
 	if feeCap.Sign() != 0 {
-		cacheView, err := api.stateCache.View(ctx, dbtx)
-		if err != nil {
-			return 0, err
+		var stateReader state.StateReader = pendingReader
+		if stateReader == nil {
+			cacheView, err := api.stateCache.View(ctx, dbtx)
+			if err != nil {
+				return 0, err
+			}
+			stateReader = state.NewCachedReader2(cacheView, dbtx)
 		}
-		stateReader := state.NewCachedReader2(cacheView, dbtx)
 		state := state.New(stateReader)
 		if state == nil {
 			return 0, fmt.Errorf("can't get the current state")
@@ -260,23 +279,44 @@ func (api *APIImpl) EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs
 
 	engine := api.engine()
 
-	// try and get the block from the lru cache first then try DB before failing
-	block := api.tryBlockFromLru(latestCanHash)
-	if block == nil {
-		block, err = api.blockWithSenders(ctx, dbtx, latestCanHash, latestCanBlockNumber)
+	header, stateReader := pendingHeader, pendingReader
+	if header == nil {
+		// try and get the block from the lru cache first then try DB before failing
+		block := api.tryBlockFromLru(latestCanHash)
+		if block == nil {
+			block, err = api.blockWithSenders(ctx, dbtx, latestCanHash, latestCanBlockNumber)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if block == nil {
+			return 0, fmt.Errorf("could not find latest block in cache or db")
+		}
+
+		stateReader, err = rpchelper.CreateStateReaderFromBlockNumber(ctx, dbtx, latestCanBlockNumber, isLatest, 0, api.stateCache, api.historyV3(dbtx), chainConfig.ChainName)
 		if err != nil {
 			return 0, err
 		}
-	}
-	if block == nil {
-		return 0, fmt.Errorf("could not find latest block in cache or db")
+		header = block.HeaderNoCopy()
 	}
 
-	stateReader, err := rpchelper.CreateStateReaderFromBlockNumber(ctx, dbtx, latestCanBlockNumber, isLatest, 0, api.stateCache, api.historyV3(dbtx), chainConfig.ChainName)
-	if err != nil {
-		return 0, err
+	// EIP-7623: no gas limit below the calldata floor can succeed, so start the search from there.
+	// On OP chains the L1 data fee is paid from the balance, it doesn't raise the estimate on top of the floor.
+	if chainConfig.Rules(header.Number.Uint64(), header.Time).IsPrague {
+		var data []byte
+		if args.Input != nil {
+			data = *args.Input
+		} else if args.Data != nil {
+			data = *args.Data
+		}
+		floorDataGas, err := core.FloorDataGas(data)
+		if err != nil {
+			return 0, err
+		}
+		if floorDataGas-1 > lo {
+			lo = floorDataGas - 1
+		}
 	}
-	header := block.HeaderNoCopy()
 
 	caller, err := transactions.NewReusableCaller(engine, stateReader, nil, header, args, api.GasCap, latestNumOrHash, dbtx, api._blockReader, chainConfig, api.evmCallTimeout)
 	if err != nil {
@@ -287,7 +327,7 @@ func (api *APIImpl) EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs
 	executable := func(gas uint64) (bool, *core.ExecutionResult, error) {
 		result, err := caller.DoCallWithNewGas(ctx, gas)
 		if err != nil {
-			if errors.Is(err, core.ErrIntrinsicGas) {
+			if errors.Is(err, core.ErrIntrinsicGas) || errors.Is(err, core.ErrFloorDataGas) {
 				// Special case, raise gas limit
 				return true, nil, nil
 			}
@@ -376,6 +416,9 @@ func (api *APIImpl) GetProof(ctx context.Context, address libcommon.Address, sto
 		}
 		return &result, nil
 	}
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return nil, err
+	}
 
 	blockNr, _, _, err := rpchelper.GetBlockNumber(blockNrOrHash, tx, api.filters)
 	if err != nil {
@@ -505,6 +548,9 @@ func (api *APIImpl) CreateAccessList(ctx context.Context, args ethapi2.CallArgs,
 		}
 		return &result, nil
 	}
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return nil, err
+	}
 
 	engine := api.engine()
 
@@ -2,9 +2,11 @@ package jsonrpc
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon/common/debug"
@@ -162,6 +164,130 @@ func (api *APIImpl) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 	return rpcSub, nil
 }
 
+// SafeHeads send a notification each time the safe head of the forkchoice changes.
+func (api *APIImpl) SafeHeads(ctx context.Context) (*rpc.Subscription, error) {
+	if api.filters == nil {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	return api.subscribeForkchoiceHeads(ctx, api.filters.SubscribeSafeHeads, api.filters.UnsubscribeSafeHeads)
+}
+
+// FinalizedHeads send a notification each time the finalized head of the forkchoice changes.
+func (api *APIImpl) FinalizedHeads(ctx context.Context) (*rpc.Subscription, error) {
+	if api.filters == nil {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	return api.subscribeForkchoiceHeads(ctx, api.filters.SubscribeFinalizedHeads, api.filters.UnsubscribeFinalizedHeads)
+}
+
+func (api *APIImpl) subscribeForkchoiceHeads(ctx context.Context, subscribe func(size int) (<-chan *types.Header, rpchelper.HeadsSubID), unsubscribe func(id rpchelper.HeadsSubID) bool) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer debug.LogPanic()
+		headers, id := subscribe(8)
+		defer unsubscribe(id)
+		for {
+			select {
+			case h, ok := <-headers:
+				if h != nil {
+					err := notifier.Notify(rpcSub.ID, h)
+					if err != nil {
+						log.Warn("[rpc] error while notifying subscription", "err", err)
+					}
+				}
+				if !ok {
+					log.Warn("[rpc] forkchoice heads channel was closed")
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// Reorgs send a notification for each reorg of the canonical chain recorded after the subscription was created.
+func (api *APIImpl) Reorgs(ctx context.Context) (*rpc.Subscription, error) {
+	if api.filters == nil {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer debug.LogPanic()
+		reorgs, id := api.filters.SubscribeReorgs(8)
+		defer api.filters.UnsubscribeReorgs(id)
+		for {
+			select {
+			case r, ok := <-reorgs:
+				if r != nil {
+					err := notifier.Notify(rpcSub.ID, newRPCReorg(r.ID, r.Reorg))
+					if err != nil {
+						log.Warn("[rpc] error while notifying subscription", "err", err)
+					}
+				}
+				if !ok {
+					log.Warn("[rpc] reorgs channel was closed")
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// StateDiffs send a notification with the state diff of each block applied or reverted by the node, starting from
+// the requested position of its change feed. Without a position only the new diffs are sent.
+func (api *APIImpl) StateDiffs(ctx context.Context, crit *StateDiffsCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	req, err := changeFeedRequest(ctx, api.ethBackend, crit)
+	if err != nil {
+		return &rpc.Subscription{}, err
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer debug.LogPanic()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-rpcSub.Err():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		err := api.ethBackend.ChangeFeed(ctx, req, func(d *remote.BlockDiff) error {
+			return notifier.Notify(rpcSub.ID, newRPCStateDiff(d))
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Warn("[rpc] state diffs subscription stopped", "err", err)
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // NewPendingTransactions send a notification each time when a transaction had added into mempool.
 func (api *APIImpl) NewPendingTransactions(ctx context.Context, fullTx *bool) (*rpc.Subscription, error) {
 	if api.filters == nil {
@@ -0,0 +1,109 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/dir"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// errLegacyReceiptsNotImported is returned for pre-Bedrock blocks whose receipts are not in the DB.
+// Such receipts can't be re-generated locally (there is no legacy state), see `erigon import-legacy`.
+var errLegacyReceiptsNotImported = errors.New("receipts of pre-bedrock block are not imported")
+
+// hasPreBedrockHistory - whether the chain has blocks produced by the legacy (OVM) client
+func hasPreBedrockHistory(cc *chain.Config) bool {
+	return cc.IsOptimism() && cc.BedrockBlock != nil && cc.BedrockBlock.Sign() > 0
+}
+
+// legacyReceiptsImported - whether receipts of pre-Bedrock blocks [from, to] are stored locally, i.e. the
+// range is covered by the one recorded by `erigon import-legacy`. The genesis block has no receipts.
+func legacyReceiptsImported(tx kv.Tx, from, to uint64) (bool, error) {
+	importedFrom, importedTo, ok, err := rawdb.ReadLegacyReceiptsRange(tx)
+	if err != nil || !ok {
+		return false, err
+	}
+	return importedFrom <= max(from, 1) && to <= importedTo, nil
+}
+
+// preBedrockBlocksStored - whether the pre-Bedrock blocks are in the local chain, so the lookup index has their
+// transactions. A datadir starting at the Bedrock block has none of them.
+func (api *BaseAPI) preBedrockBlocksStored(ctx context.Context, tx kv.Tx) (bool, error) {
+	hash, err := api._blockReader.CanonicalHash(ctx, tx, 1)
+	if err != nil {
+		return false, err
+	}
+	return hash != (libcommon.Hash{}), nil
+}
+
+// getLegacyLogs serves eth_getLogs for pre-Bedrock blocks [from, to] from the historical backend
+func (api *APIImpl) getLegacyLogs(ctx context.Context, from, to uint64, crit filters.FilterCriteria) (types.Logs, error) {
+	if api.historicalRPCService == nil {
+		return nil, rpc.ErrNoHistoricalFallback
+	}
+	args := map[string]interface{}{
+		"fromBlock": hexutil.EncodeUint64(from),
+		"toBlock":   hexutil.EncodeUint64(to),
+	}
+	if len(crit.Addresses) > 0 {
+		args["address"] = crit.Addresses
+	}
+	if len(crit.Topics) > 0 {
+		args["topics"] = crit.Topics
+	}
+	var result types.Logs
+	if err := api.relayToHistoricalBackend(ctx, &result, "eth_getLogs", args); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// legacyStateDB returns the pre-Bedrock state imported by `erigon import-legacy --legacy.state`, nil if
+// there is none. It's opened on first use: the import may run while the node is up.
+func (api *BaseAPI) legacyStateDB(ctx context.Context) (kv.RoDB, error) {
+	api.legacyStateLock.Lock()
+	defer api.legacyStateLock.Unlock()
+	if api.legacyState != nil || api.dirs.LegacyState == "" || !dir.FileExist(filepath.Join(api.dirs.LegacyState, "mdbx.dat")) {
+		return api.legacyState, nil
+	}
+	db, err := mdbx.NewMDBX(log.Root()).Path(api.dirs.LegacyState).Label(kv.ChainDB).Readonly().Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api.legacyState = db
+	return db, nil
+}
+
+// readLegacyState calls fn with the state after pre-Bedrock block blockNum, ok is false if that state
+// isn't imported.
+func (api *BaseAPI) readLegacyState(ctx context.Context, blockNum uint64, fn func(reader state.StateReader) error) (ok bool, err error) {
+	db, err := api.legacyStateDB(ctx)
+	if err != nil || db == nil {
+		return false, err
+	}
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	blocks, err := rawdb.ReadLegacyStateBlocks(tx)
+	if err != nil || blockNum >= blocks {
+		return false, err
+	}
+	// changesets of a block hold the values before it, i.e. after the previous block
+	return true, fn(state.NewPlainState(tx, blockNum+1, nil))
+}
@@ -0,0 +1,174 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+func TestGetBalanceLegacyState(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateOptimismTestSentry(t)
+	// the datadir of the mock is shared by the tests
+	m.Dirs.LegacyState = filepath.Join(t.TempDir(), "legacystate")
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 1e18, 100_000, false, 100_000, 128, log.New())
+	addr := libcommon.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+
+	// legacy state of blocks 0 and 1, as written by `erigon import-legacy`
+	db := mdbx.NewMDBX(log.New()).Path(m.Dirs.LegacyState).Label(kv.ChainDB).MustOpen()
+	require.NoError(t, db.Update(m.Ctx, func(tx kv.RwTx) error {
+		original := &accounts.Account{}
+		for n, balance := range []uint64{7, 9} {
+			account := accounts.NewAccount()
+			account.Initialised = true
+			account.Nonce = uint64(n)
+			account.Balance.SetUint64(balance)
+			w := state.NewPlainStateWriter(tx, tx, uint64(n))
+			if err := w.UpdateAccountData(addr, original, &account); err != nil {
+				return err
+			}
+			if err := w.WriteChangeSets(); err != nil {
+				return err
+			}
+			if err := w.WriteHistory(); err != nil {
+				return err
+			}
+			original = &account
+		}
+		return rawdb.WriteLegacyStateBlocks(tx, 2)
+	}))
+	db.Close()
+
+	for n, want := range []string{"0x7", "0x9"} {
+		bal, err := api.GetBalance(m.Ctx, addr, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(n)))
+		require.NoError(t, err)
+		require.Equal(t, want, fmt.Sprintf("%v", bal))
+		nonce, err := api.GetTransactionCount(m.Ctx, addr, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(n)))
+		require.NoError(t, err)
+		require.Equal(t, uint64(n), uint64(*nonce))
+	}
+	// state of later blocks isn't imported, it's relayed
+	_, err := api.GetBalance(m.Ctx, addr, rpc.BlockNumberOrHashWithNumber(2))
+	require.ErrorIs(t, err, rpc.ErrNoHistoricalFallback)
+
+	s := MockServer{}
+	s.Start()
+	defer s.Stop()
+	historicalRPCService, err := s.GetRPC()
+	require.NoError(t, err)
+	api.historicalRPCService = historicalRPCService
+	s.UpdatePayload("{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":\"0x1\"}")
+	bal, err := api.GetBalance(m.Ctx, addr, rpc.BlockNumberOrHashWithNumber(2))
+	require.NoError(t, err)
+	require.Equal(t, "0x1", fmt.Sprintf("%v", bal))
+}
+
+func TestGetTransactionReceiptLegacyRelay(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateOptimismTestSentry(t)
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 1e18, 100_000, false, 100_000, 128, log.New())
+
+	// receipts of the pre-Bedrock block weren't imported
+	tx, err := m.DB.BeginRw(m.Ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	block, err := m.BlockReader.BlockByNumber(m.Ctx, tx, 1)
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(kv.Receipts, hexutility.EncodeTs(1)))
+	require.NoError(t, tx.Commit())
+	txnHash := block.Transactions()[0].Hash()
+
+	_, err = api.GetTransactionReceipt(m.Ctx, txnHash)
+	require.ErrorIs(t, err, rpc.ErrNoHistoricalFallback)
+
+	s := MockServer{}
+	s.Start()
+	defer s.Stop()
+	historicalRPCService, err := s.GetRPC()
+	require.NoError(t, err)
+	api.historicalRPCService = historicalRPCService
+	s.UpdatePayload(fmt.Sprintf("{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"transactionHash\":\"%s\",\"l1Fee\":\"0x1\"}}", txnHash.Hex()))
+	receipt, err := api.GetTransactionReceipt(m.Ctx, txnHash)
+	require.NoError(t, err)
+	require.Equal(t, txnHash.Hex(), receipt["transactionHash"])
+	require.Equal(t, "0x1", receipt["l1Fee"])
+
+	// an unknown hash isn't relayed, the pre-Bedrock blocks are in the lookup index
+	s.UpdatePayload(fmt.Sprintf("{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"transactionHash\":\"%s\"}}", libcommon.Hash{0x01}.Hex()))
+	receipt, err = api.GetTransactionReceipt(m.Ctx, libcommon.Hash{0x01})
+	require.NoError(t, err)
+	require.Nil(t, receipt)
+
+	// a datadir starting at Bedrock has no pre-Bedrock blocks, their transactions are only known to the backend
+	require.NoError(t, m.DB.Update(m.Ctx, func(tx kv.RwTx) error {
+		return tx.Delete(kv.HeaderCanonical, hexutility.EncodeTs(1))
+	}))
+	receipt, err = api.GetTransactionReceipt(m.Ctx, libcommon.Hash{0x01})
+	require.NoError(t, err)
+	require.Equal(t, libcommon.Hash{0x01}.Hex(), receipt["transactionHash"])
+
+	// unknown to the backend as well
+	for _, payload := range []string{
+		"{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":null}",
+		"{\"jsonrpc\":\"2.0\",\"id\":1,\"error\":{\"code\":-32000,\"message\":\"error\"}}",
+	} {
+		s.UpdatePayload(payload)
+		receipt, err = api.GetTransactionReceipt(m.Ctx, libcommon.Hash{0x02})
+		require.NoError(t, err)
+		require.Nil(t, receipt)
+	}
+
+	// nothing is relayed once the legacy receipts are imported
+	m, _, _ = rpcdaemontest.CreateOptimismTestSentry(t)
+	require.NoError(t, m.DB.Update(m.Ctx, func(tx kv.RwTx) error {
+		// the import covers all the blocks before Bedrock, the test chain only has the first ones
+		return rawdb.WriteLegacyReceiptsRange(tx, 1, m.ChainConfig.BedrockBlock.Uint64()-1)
+	}))
+	api = NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 1e18, 100_000, false, 100_000, 128, log.New())
+	api.historicalRPCService = historicalRPCService
+	s.UpdatePayload(fmt.Sprintf("{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"transactionHash\":\"%s\"}}", libcommon.Hash{0x01}.Hex()))
+	receipt, err = api.GetTransactionReceipt(m.Ctx, libcommon.Hash{0x01})
+	require.NoError(t, err)
+	require.Nil(t, receipt)
+}
+
+func TestGetLogsLegacyPartialImport(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateOptimismTestSentry(t)
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 1e18, 100_000, false, 100_000, 128, log.New())
+	s := MockServer{}
+	s.Start()
+	defer s.Stop()
+	historicalRPCService, err := s.GetRPC()
+	require.NoError(t, err)
+	api.historicalRPCService = historicalRPCService
+	relayed := libcommon.Address{0x42}
+	s.UpdatePayload(fmt.Sprintf("{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":[{\"address\":\"%s\",\"topics\":[],\"data\":\"0x\",\"transactionHash\":\"%s\"}]}", relayed.Hex(), libcommon.Hash{0x01}.Hex()))
+
+	// only the receipts of blocks 1 and 2 are imported
+	require.NoError(t, m.DB.Update(m.Ctx, func(tx kv.RwTx) error {
+		return rawdb.WriteLegacyReceiptsRange(tx, 1, 2)
+	}))
+	for _, tc := range []struct {
+		from, to int64
+		relay    bool
+	}{{0, 2, false}, {1, 3, true}, {2, 3, true}} {
+		logs, err := api.GetLogs(m.Ctx, filters.FilterCriteria{FromBlock: big.NewInt(tc.from), ToBlock: big.NewInt(tc.to)})
+		require.NoError(t, err)
+		isRelayed := len(logs) == 1 && logs[0].Address == relayed
+		require.Equal(t, tc.relay, isRelayed, "blocks %d-%d", tc.from, tc.to)
+	}
+}
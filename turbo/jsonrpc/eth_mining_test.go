@@ -37,7 +37,8 @@ func TestPendingBlock(t *testing.T) {
 	defer ff.UnsubscribePendingBlock(id)
 
 	ff.HandlePendingBlock(&txpool.OnPendingBlockReply{RplBlock: b})
-	block := api.pendingBlock()
+	block, err := api.pendingBlock(ctx, nil)
+	require.NoError(t, err)
 
 	require.Equal(t, block.NumberU64(), expect)
 	select {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 
 	"github.com/RoaringBitmap/roaring"
@@ -11,13 +12,16 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/chain"
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/common/length"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/bitmapdb"
 	"github.com/ledgerwatch/erigon-lib/kv/iter"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
 	"github.com/ledgerwatch/erigon-lib/opstack"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
 	"github.com/ledgerwatch/erigon/eth/ethutils"
 	bortypes "github.com/ledgerwatch/erigon/polygon/bor/types"
 
@@ -51,6 +55,9 @@ func (api *BaseAPI) getReceipts(ctx context.Context, tx kv.Tx, block *types.Bloc
 		api.receiptsCache.Add(block.Hash(), receipts)
 		return receipts, nil
 	}
+	if len(block.Transactions()) > 0 && chainConfig.IsOptimismPreBedrock(block.NumberU64()) {
+		return nil, errLegacyReceiptsNotImported
+	}
 
 	engine := api.engine()
 
@@ -183,12 +190,42 @@ func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) (t
 		end = latest
 	}
 
+	if err := api.checkPruned(tx, prunedLogs, begin); err != nil {
+		return nil, err
+	}
+
+	// pre-Bedrock logs are served locally if legacy receipts were imported, by the historical backend otherwise
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if chainConfig.IsOptimismPreBedrock(begin) {
+		legacyEnd := min(end, chainConfig.BedrockBlock.Uint64()-1)
+		imported, err := legacyReceiptsImported(tx, begin, legacyEnd)
+		if err != nil {
+			return nil, err
+		}
+		if !imported {
+			if logs, err = api.getLegacyLogs(ctx, begin, legacyEnd, crit); err != nil {
+				return nil, err
+			}
+			if end == legacyEnd {
+				return logs, nil
+			}
+			begin = legacyEnd + 1
+		}
+	}
+
 	if api.historyV3(tx) {
-		return api.getLogsV3(ctx, tx.(kv.TemporalTx), begin, end, crit)
+		logsV3, err := api.getLogsV3(ctx, tx.(kv.TemporalTx), begin, end, crit)
+		if err != nil {
+			return nil, err
+		}
+		return append(logs, logsV3...), nil
 	}
 	blockNumbers := bitmapdb.NewBitmap()
 	defer bitmapdb.ReturnToPool(blockNumbers)
-	if err := applyFilters(blockNumbers, tx, begin, end, crit); err != nil {
+	if err := applyFilters(blockNumbers, tx, api._agg, begin, end, crit); err != nil {
 		return logs, err
 	}
 	if blockNumbers.IsEmpty() {
@@ -331,8 +368,87 @@ func getAddrsBitmap(tx kv.Tx, addrs []common.Address, from, to uint64) (*roaring
 	return roaring.FastOr(rx...), nil
 }
 
-func applyFilters(out *roaring.Bitmap, tx kv.Tx, begin, end uint64, crit filters.FilterCriteria) error {
+// maxAddrTopicPairs - above this number of address+topic0 pairs the criteria are served by the separate indices
+const maxAddrTopicPairs = 256
+
+// addrTopicsIndexed - whether the address+topic0 index serves the criteria for blocks from..
+func addrTopicsIndexed(tx kv.Getter, addrs []common.Address, topics [][]common.Hash, from uint64) (bool, error) {
+	if len(addrs) == 0 || len(topics) == 0 || len(topics[0]) == 0 || len(addrs)*len(topics[0]) > maxAddrTopicPairs {
+		return false, nil
+	}
+	indexFrom, ok, err := rawdb.ReadLogAddressTopicIndexFrom(tx)
+	if err != nil || !ok {
+		return false, err
+	}
+	return from >= indexFrom, nil
+}
+
+// getAddrTopicsBitmap - blocks with logs of any of the addresses having any of the first topics. Returns nil if the criteria
+// don't restrict both or if the address+topic0 index doesn't cover the range. Old blocks of the index are frozen into
+// files of agg, recent ones are in the DB.
+func getAddrTopicsBitmap(tx kv.Tx, agg *libstate.Aggregator, addrs []common.Address, topics [][]common.Hash, from, to uint64) (*roaring.Bitmap, error) {
+	if ok, err := addrTopicsIndexed(tx, addrs, topics, from); err != nil || !ok {
+		return nil, err
+	}
+	var ac *libstate.AggregatorRoTx
+	if agg != nil {
+		ac = agg.BeginFilesRo()
+		defer ac.Close()
+	}
+	rx := make([]*roaring.Bitmap, 0, len(addrs)*len(topics[0]))
+	defer func() {
+		for _, bm := range rx {
+			bitmapdb.ReturnToPool(bm)
+		}
+	}()
+	key := make([]byte, length.Addr+length.Hash)
+	for _, addr := range addrs {
+		copy(key, addr[:])
+		for _, topic := range topics[0] {
+			copy(key[length.Addr:], topic[:])
+			m, err := bitmapdb.Get(tx, kv.LogAddressTopicIndex, key, uint32(from), uint32(to))
+			if err != nil {
+				return nil, err
+			}
+			rx = append(rx, m)
+			if ac == nil {
+				continue
+			}
+			// history v2 puts block numbers into the files
+			it, err := ac.IndexRange(kv.LogAddrTopicIdx, key, int(from), int(to)+1, order.Asc, kv.Unlim, tx)
+			if err != nil {
+				return nil, err
+			}
+			for it.HasNext() {
+				blockNum, err := it.Next()
+				if err != nil {
+					return nil, err
+				}
+				m.Add(uint32(blockNum))
+			}
+		}
+	}
+	return roaring.FastOr(rx...), nil
+}
+
+func applyFilters(out *roaring.Bitmap, tx kv.Tx, agg *libstate.Aggregator, begin, end uint64, crit filters.FilterCriteria) error {
 	out.AddRange(begin, end+1) // [from,to)
+	addrTopicsBitmap, err := getAddrTopicsBitmap(tx, agg, crit.Addresses, crit.Topics, begin, end)
+	if err != nil {
+		return err
+	}
+	if addrTopicsBitmap != nil {
+		// the composite index replaces the (much wider) address and first topic bitmaps
+		out.And(addrTopicsBitmap)
+		topicsBitmap, err := getTopicsBitmap(tx, crit.Topics[1:], begin, end)
+		if err != nil {
+			return err
+		}
+		if topicsBitmap != nil {
+			out.And(topicsBitmap)
+		}
+		return nil
+	}
 	topicsBitmap, err := getTopicsBitmap(tx, crit.Topics, begin, end)
 	if err != nil {
 		return err
@@ -402,6 +518,21 @@ func applyFiltersV3(tx kv.TemporalTx, begin, end uint64, crit filters.FilterCrit
 	}
 	toTxNum++
 
+	addrTopicsBitmap, err := getAddrTopicsBitmapV3(tx, crit.Addresses, crit.Topics, begin, fromTxNum, toTxNum)
+	if err != nil {
+		return out, err
+	}
+	if addrTopicsBitmap != nil {
+		// the composite index replaces the (much wider) address and first topic indices
+		topicsBitmap, err := getTopicsBitmapV3(tx, crit.Topics[1:], fromTxNum, toTxNum)
+		if err != nil {
+			return out, err
+		}
+		if topicsBitmap != nil {
+			return iter.Intersect[uint64](addrTopicsBitmap, topicsBitmap, -1), nil
+		}
+		return addrTopicsBitmap, nil
+	}
 	topicsBitmap, err := getTopicsBitmapV3(tx, crit.Topics, fromTxNum, toTxNum)
 	if err != nil {
 		return out, err
@@ -615,6 +746,26 @@ func getTopicsBitmapV3(tx kv.TemporalTx, topics [][]common.Hash, from, to uint64
 	return res, nil
 }
 
+// getAddrTopicsBitmapV3 - txs with logs of any of the addresses having any of the first topics, see getAddrTopicsBitmap
+func getAddrTopicsBitmapV3(tx kv.TemporalTx, addrs []common.Address, topics [][]common.Hash, begin, from, to uint64) (res iter.U64, err error) {
+	if ok, err := addrTopicsIndexed(tx, addrs, topics, begin); err != nil || !ok {
+		return nil, err
+	}
+	key := make([]byte, length.Addr+length.Hash)
+	for _, addr := range addrs {
+		copy(key, addr[:])
+		for _, topic := range topics[0] {
+			copy(key[length.Addr:], topic[:])
+			it, err := tx.IndexRange(kv.LogAddrTopicIdx, key, int(from), int(to), order.Asc, kv.Unlim)
+			if err != nil {
+				return nil, err
+			}
+			res = iter.Union[uint64](res, it, order.Asc, -1)
+		}
+	}
+	return res, nil
+}
+
 func getAddrsBitmapV3(tx kv.TemporalTx, addrs []common.Address, from, to uint64) (res iter.U64, err error) {
 	for _, addr := range addrs {
 		it, err := tx.IndexRange(kv.LogAddrIdx, addr[:], int(from), int(to), true, kv.Unlim)
@@ -655,9 +806,30 @@ func (api *APIImpl) GetTransactionReceipt(ctx context.Context, txnHash common.Ha
 	}
 
 	if !ok {
+		// the lookup only misses pre-Bedrock transactions if the datadir has no pre-Bedrock blocks,
+		// otherwise the transaction is unknown or pending and isn't relayed
+		if api.historicalRPCService != nil && hasPreBedrockHistory(cc) {
+			stored, err := api.preBedrockBlocksStored(ctx, tx)
+			if err != nil {
+				return nil, err
+			}
+			if !stored {
+				receipt, err := api.relayLegacyReceipt(ctx, txnHash)
+				if err != nil {
+					api.logger.Debug("[rpc] legacy receipt lookup failed", "hash", txnHash, "err", err)
+				}
+				if receipt != nil {
+					return receipt, nil
+				}
+			}
+		}
 		return nil, nil
 	}
 
+	if err := api.checkPruned(tx, prunedReceipts, blockNum); err != nil {
+		return nil, err
+	}
+
 	block, err := api.blockByNumberWithSenders(ctx, tx, blockNum)
 	if err != nil {
 		return nil, err
@@ -684,6 +856,9 @@ func (api *APIImpl) GetTransactionReceipt(ctx context.Context, txnHash common.Ha
 		}
 	}
 	receipts, err := api.getReceipts(ctx, tx, block, block.Body().SendersFromTxs())
+	if errors.Is(err, errLegacyReceiptsNotImported) {
+		return api.relayLegacyReceipt(ctx, txnHash)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("getReceipts error: %w", err)
 	}
@@ -706,6 +881,17 @@ func (api *APIImpl) GetTransactionReceipt(ctx context.Context, txnHash common.Ha
 	return ethutils.MarshalReceipt(receipts[txnIndex], block.Transactions()[txnIndex], cc, block.HeaderNoCopy(), txnHash, true), nil
 }
 
+func (api *APIImpl) relayLegacyReceipt(ctx context.Context, txnHash common.Hash) (map[string]interface{}, error) {
+	if api.historicalRPCService == nil {
+		return nil, rpc.ErrNoHistoricalFallback
+	}
+	var result map[string]interface{}
+	if err := api.relayToHistoricalBackend(ctx, &result, "eth_getTransactionReceipt", txnHash); err != nil {
+		return nil, fmt.Errorf("historical backend error: %w", err)
+	}
+	return result, nil
+}
+
 // GetBlockReceipts - receipts for individual block
 func (api *APIImpl) GetBlockReceipts(ctx context.Context, numberOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
 	tx, err := api.db.BeginRo(ctx)
@@ -718,6 +904,9 @@ func (api *APIImpl) GetBlockReceipts(ctx context.Context, numberOrHash rpc.Block
 	if err != nil {
 		return nil, err
 	}
+	if err := api.checkPruned(tx, prunedReceipts, blockNum); err != nil {
+		return nil, err
+	}
 	block, err := api.blockWithSenders(ctx, tx, blockHash, blockNum)
 	if err != nil {
 		return nil, err
@@ -730,6 +919,16 @@ func (api *APIImpl) GetBlockReceipts(ctx context.Context, numberOrHash rpc.Block
 		return nil, err
 	}
 	receipts, err := api.getReceipts(ctx, tx, block, block.Body().SendersFromTxs())
+	if errors.Is(err, errLegacyReceiptsNotImported) {
+		if api.historicalRPCService == nil {
+			return nil, rpc.ErrNoHistoricalFallback
+		}
+		var result []map[string]interface{}
+		if err := api.relayToHistoricalBackend(ctx, &result, "eth_getBlockReceipts", hexutil.EncodeUint64(blockNum)); err != nil {
+			return nil, fmt.Errorf("historical backend error: %w", err)
+		}
+		return result, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("getReceipts error: %w", err)
 	}
@@ -57,7 +57,7 @@ func TestGetReceipts(t *testing.T) {
 	defer tx.Rollback()
 
 	var (
-		bedrockBlock = common.Big0.Add(m.ChainConfig.BedrockBlock, big.NewInt(1))
+		bedrockBlock = new(big.Int).Add(m.ChainConfig.BedrockBlock, big.NewInt(1))
 
 		l1BaseFee = uint256.NewInt(1000).Bytes32()
 		overhead  = uint256.NewInt(100).Bytes32()
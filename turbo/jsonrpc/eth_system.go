@@ -146,6 +146,10 @@ type feeHistoryResult struct {
 	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
 	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
 	GasUsedRatio []float64        `json:"gasUsedRatio"`
+	// L1BaseFee and L1BlobBaseFee are the L1 fees the blocks of OP stack chains set on the L1Block contract,
+	// including the fees projected for the next block
+	L1BaseFee     []*hexutil.Big `json:"l1BaseFeePerGas,omitempty"`
+	L1BlobBaseFee []*hexutil.Big `json:"l1BlobBaseFeePerGas,omitempty"`
 }
 
 func (api *APIImpl) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
@@ -179,6 +183,18 @@ func (api *APIImpl) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex,
 			results.BaseFee[i] = (*hexutil.Big)(v)
 		}
 	}
+	if cc, err := api.chainConfig(ctx, tx); err != nil {
+		return nil, err
+	} else if cc.IsOptimism() && len(gasUsed) > 0 {
+		l1Fees, err := oracle.OptimismL1FeeHistory(ctx, oldest.Uint64(), oldest.Uint64()+uint64(len(gasUsed))-1)
+		if err != nil {
+			return nil, err
+		}
+		for _, fees := range l1Fees {
+			results.L1BaseFee = append(results.L1BaseFee, (*hexutil.Big)(fees.BaseFee.ToBig()))
+			results.L1BlobBaseFee = append(results.L1BlobBaseFee, (*hexutil.Big)(fees.BlobBaseFee.ToBig()))
+		}
+	}
 	return results, nil
 }
 
@@ -204,6 +220,9 @@ func (b *GasPriceOracleBackend) HeaderByNumber(ctx context.Context, number rpc.B
 func (b *GasPriceOracleBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
 	return b.baseApi.blockByRPCNumber(ctx, number, b.tx)
 }
+func (b *GasPriceOracleBackend) TxnByIdxInBlock(ctx context.Context, number uint64, i int) (types.Transaction, error) {
+	return b.baseApi._blockReader.TxnByIdxInBlock(ctx, b.tx, number, i)
+}
 func (b *GasPriceOracleBackend) ChainConfig() *chain.Config {
 	cc, _ := b.baseApi.chainConfig(context.Background(), b.tx)
 	return cc
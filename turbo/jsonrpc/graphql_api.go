@@ -1,35 +1,45 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/big"
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/ethutils"
+	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
 )
 
 type GraphQLAPI interface {
-	GetBlockDetails(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error)
+	GetBlockDetails(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (map[string]interface{}, error)
 	GetChainID(ctx context.Context) (*big.Int, error)
+	Call(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash) (*core.ExecutionResult, error)
+	PendingTransactions(ctx context.Context) ([]*RPCTransaction, error)
 }
 
 type GraphQLAPIImpl struct {
 	*BaseAPI
-	db kv.RoDB
+	db     kv.RoDB
+	GasCap uint64
 }
 
-func NewGraphQLAPI(base *BaseAPI, db kv.RoDB) *GraphQLAPIImpl {
+func NewGraphQLAPI(base *BaseAPI, db kv.RoDB, gascap uint64) *GraphQLAPIImpl {
 	return &GraphQLAPIImpl{
 		BaseAPI: base,
 		db:      db,
+		GasCap:  gascap,
 	}
 }
 
@@ -48,22 +58,28 @@ func (api *GraphQLAPIImpl) GetChainID(ctx context.Context) (*big.Int, error) {
 	return response.ChainID, nil
 }
 
-func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.BlockNumber) (map[string]interface{}, error) {
+// GetBlockDetails returns the block along with its transactions and their receipts. Every receipt carries
+// its transaction as "transaction" and the canonical encodings of both as "raw" and "rawReceipt".
+func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (map[string]interface{}, error) {
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	block, senders, err := api.getBlockWithSenders(ctx, blockNumber, tx)
+	block, senders, err := api.getBlockWithSenders(ctx, blockNrOrHash, tx)
 	if err != nil {
 		return nil, err
 	}
 	if block == nil {
 		return nil, nil
 	}
+	pending := false
+	if number, ok := blockNrOrHash.Number(); ok && number == rpc.PendingBlockNumber {
+		pending = true
+	}
 
-	getBlockRes, err := api.delegateGetBlockByNumber(tx, block, blockNumber, false)
+	getBlockRes, err := api.delegateGetBlockByNumber(tx, block, pending, false)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +88,16 @@ func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.
 	if err != nil {
 		return nil, err
 	}
+	header := block.HeaderNoCopy()
+	if chainConfig.IsLondon(block.NumberU64()) {
+		getBlockRes["nextBaseFeePerGas"] = (*hexutil.Big)(misc.CalcBaseFee(chainConfig, header, header.Time+1))
+	}
+	if getBlockRes["rawHeader"], err = rlp.EncodeToBytes(header); err != nil {
+		return nil, err
+	}
+	if getBlockRes["raw"], err = rlp.EncodeToBytes(block); err != nil {
+		return nil, err
+	}
 
 	receipts, err := api.getReceipts(ctx, tx, block, senders)
 	if err != nil {
@@ -79,14 +105,21 @@ func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.
 	}
 
 	result := make([]map[string]interface{}, 0, len(receipts))
-	for _, receipt := range receipts {
+	for i, receipt := range receipts {
 		txn := block.Transactions()[receipt.TransactionIndex]
 
-		transaction := ethutils.MarshalReceipt(receipt, txn, chainConfig, block.HeaderNoCopy(), txn.Hash(), true)
-		transaction["nonce"] = txn.GetNonce()
-		transaction["value"] = txn.GetValue()
-		transaction["data"] = txn.GetData()
+		transaction := ethutils.MarshalReceipt(receipt, txn, chainConfig, header, txn.Hash(), true)
+		transaction["transaction"] = NewRPCTransaction(txn, block.Hash(), block.NumberU64(), uint64(receipt.TransactionIndex), block.BaseFee(), receipt)
 		transaction["logs"] = receipt.Logs
+
+		var buf bytes.Buffer
+		if err := txn.MarshalBinary(&buf); err != nil {
+			return nil, err
+		}
+		transaction["raw"] = hexutility.Bytes(buf.Bytes())
+		buf.Reset()
+		types.Receipts(receipts).EncodeIndex(i, &buf)
+		transaction["rawReceipt"] = hexutility.Bytes(buf.Bytes())
 		result = append(result, transaction)
 	}
 
@@ -97,12 +130,73 @@ func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.
 	return response, nil
 }
 
-func (api *GraphQLAPIImpl) getBlockWithSenders(ctx context.Context, number rpc.BlockNumber, tx kv.Tx) (*types.Block, []common.Address, error) {
-	if number == rpc.PendingBlockNumber {
-		return api.pendingBlock(), nil, nil
+// Call executes a call on the state of the given block, unlike eth_call it returns the full execution
+// result: the gas used and the failure of a call are part of the GraphQL schema.
+func (api *GraphQLAPIImpl) Call(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash) (*core.ExecutionResult, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("read chain config: %v", err)
+	}
+	blockNumber, hash, _, err := rpchelper.GetCanonicalBlockNumber(blockNrOrHash, tx, api.filters) // DoCall cannot be executed on non-canonical blocks
+	if err != nil {
+		return nil, err
+	}
+	if chainConfig.IsOptimismPreBedrock(blockNumber) {
+		return nil, rpc.ErrNoHistoricalFallback
+	}
+	if err := api.checkPruneHistory(tx, blockNumber); err != nil {
+		return nil, err
+	}
+	block, err := api.blockWithSenders(ctx, tx, hash, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNumber)
+	}
+
+	if args.Gas == nil || uint64(*args.Gas) == 0 {
+		args.Gas = (*hexutil.Uint64)(&api.GasCap)
+	}
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+	if err != nil {
+		return nil, err
+	}
+	return transactions.DoCall(ctx, api.engine(), args, tx, blockNrOrHash, block.HeaderNoCopy(), nil, api.GasCap, chainConfig, stateReader, api._blockReader, api.evmCallTimeout)
+}
+
+// PendingTransactions returns the transactions of the pending block, if this node builds or simulates one.
+func (api *GraphQLAPIImpl) PendingTransactions(ctx context.Context) ([]*RPCTransaction, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	block, err := api.pendingBlock(ctx, tx)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	result := make([]*RPCTransaction, 0, block.Transactions().Len())
+	for i, txn := range block.Transactions() {
+		result = append(result, NewRPCTransaction(txn, common.Hash{}, block.NumberU64(), uint64(i), block.BaseFee(), nil))
+	}
+	return result, nil
+}
+
+func (api *GraphQLAPIImpl) getBlockWithSenders(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, tx kv.Tx) (*types.Block, []common.Address, error) {
+	if number, ok := blockNrOrHash.Number(); ok && number == rpc.PendingBlockNumber {
+		block, err := api.pendingBlock(ctx, tx)
+		return block, nil, err
 	}
 
-	blockHeight, blockHash, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(number), tx, api.filters)
+	blockHeight, blockHash, _, err := rpchelper.GetBlockNumber(blockNrOrHash, tx, api.filters)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -117,13 +211,13 @@ func (api *GraphQLAPIImpl) getBlockWithSenders(ctx context.Context, number rpc.B
 	return block, block.Body().SendersFromTxs(), nil
 }
 
-func (api *GraphQLAPIImpl) delegateGetBlockByNumber(tx kv.Tx, b *types.Block, number rpc.BlockNumber, inclTx bool) (map[string]interface{}, error) {
+func (api *GraphQLAPIImpl) delegateGetBlockByNumber(tx kv.Tx, b *types.Block, pending bool, inclTx bool) (map[string]interface{}, error) {
 	td, err := rawdb.ReadTd(tx, b.Hash(), b.NumberU64())
 	if err != nil {
 		return nil, err
 	}
 	additionalFields := make(map[string]interface{})
-	receipts := rawdb.ReadRawReceipts(tx, uint64(number.Int64()))
+	receipts := rawdb.ReadRawReceipts(tx, b.NumberU64())
 	response, err := ethapi.RPCMarshalBlock(b, inclTx, inclTx, additionalFields, receipts)
 	if !inclTx {
 		delete(response, "transactions") // workaround for https://github.com/ledgerwatch/erigon/issues/4989#issuecomment-1218415666
@@ -131,7 +225,7 @@ func (api *GraphQLAPIImpl) delegateGetBlockByNumber(tx kv.Tx, b *types.Block, nu
 	response["totalDifficulty"] = (*hexutil.Big)(td)
 	response["transactionCount"] = b.Transactions().Len()
 
-	if err == nil && number == rpc.PendingBlockNumber {
+	if err == nil && pending {
 		// Pending blocks need to nil out a few fields
 		for _, field := range []string{"hash", "nonce", "miner"} {
 			response[field] = nil
@@ -0,0 +1,59 @@
+package jsonrpc
+
+import (
+	"context"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+)
+
+func TestGraphQLGetBlockDetailsByHash(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewGraphQLAPI(newBaseApiForTest(m), m.DB, 5000000)
+	ctx := context.Background()
+
+	tx, err := m.DB.BeginRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	hash, err := rawdb.ReadCanonicalHash(tx, 1)
+	require.NoError(t, err)
+	block, _, err := m.BlockReader.BlockWithSenders(ctx, tx, hash, 1)
+	require.NoError(t, err)
+
+	res, err := api.GetBlockDetails(ctx, rpc.BlockNumberOrHashWithHash(hash, false))
+	require.NoError(t, err)
+	require.Equal(t, hash, res["block"].(map[string]interface{})["hash"])
+	require.NotEmpty(t, res["block"].(map[string]interface{})["rawHeader"])
+
+	receipts := res["receipts"].([]map[string]interface{})
+	require.Len(t, receipts, block.Transactions().Len())
+	for i, receipt := range receipts {
+		txn := receipt["transaction"].(*RPCTransaction)
+		require.Equal(t, block.Transactions()[i].Hash(), txn.Hash)
+		require.Equal(t, hash, *txn.BlockHash)
+		require.NotEmpty(t, receipt["raw"])
+		require.NotEmpty(t, receipt["rawReceipt"])
+	}
+
+	res, err = api.GetBlockDetails(ctx, rpc.BlockNumberOrHashWithHash(libcommon.Hash{0x1}, false))
+	require.Error(t, err)
+	require.Nil(t, res)
+}
+
+func TestGraphQLCall(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewGraphQLAPI(newBaseApiForTest(m), m.DB, 5000000)
+	from := libcommon.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+	to := libcommon.HexToAddress("0x0d3ab14bbad3d99f4203bd7a11acb94882050e7e")
+
+	result, err := api.Call(context.Background(), ethapi.CallArgs{From: &from, To: &to}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	require.NoError(t, err)
+	require.False(t, result.Failed())
+	require.GreaterOrEqual(t, result.UsedGas, uint64(21000))
+}
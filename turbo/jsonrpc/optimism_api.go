@@ -0,0 +1,208 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/gasprice"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	ethapi2 "github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+)
+
+const (
+	// defaultFeeEstimateBlocks is the number of latest blocks optimism_estimateFees looks at by default
+	defaultFeeEstimateBlocks = 20
+	// maxFeeEstimateBlocks bounds the number of blocks, their bodies are read for the L1 info deposits
+	maxFeeEstimateBlocks = 1024
+)
+
+// defaultFeeEstimatePercentiles are the priority fee percentiles optimism_estimateFees reports by default
+var defaultFeeEstimatePercentiles = []float64{10, 50, 90}
+
+// OptimismAPI OP stack specific routines
+type OptimismAPI interface {
+	ProtocolVersions(ctx context.Context) (*ProtocolVersions, error)
+	EstimateFees(ctx context.Context, args ethapi2.CallArgs, blockCount *rpc.DecimalOrHex, rewardPercentiles []float64) (*FeeEstimate, error)
+}
+
+// OptimismAPIImpl is implementation of the OptimismAPI interface
+type OptimismAPIImpl struct {
+	*BaseAPI
+	db       kv.RoDB
+	gasCache *GasPriceCache
+}
+
+// NewOptimismAPI returns OptimismAPIImpl instance
+func NewOptimismAPI(base *BaseAPI, db kv.RoDB) *OptimismAPIImpl {
+	return &OptimismAPIImpl{
+		BaseAPI:  base,
+		db:       db,
+		gasCache: NewGasPriceCache(),
+	}
+}
+
+// ProtocolVersions is the superchain protocol version supported by this node, and the latest
+// versions signaled by the rollup node.
+type ProtocolVersions struct {
+	Local  params.ProtocolVersion  `json:"local"`
+	Signal *rawdb.SuperchainSignal `json:"signal"` // nil if the rollup node did not signal yet
+
+	// UpgradeRequired is set if the signaled required version is ahead of the local one
+	UpgradeRequired bool `json:"upgradeRequired"`
+}
+
+// ProtocolVersions implements optimism_protocolVersions. Returns the latest superchain protocol version
+// signal received through engine_signalSuperchainV1.
+func (api *OptimismAPIImpl) ProtocolVersions(ctx context.Context) (*ProtocolVersions, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	signal, err := rawdb.ReadSuperchainSignal(tx)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProtocolVersions{Local: params.OPStackSupport, Signal: signal}
+	if signal != nil {
+		// the signal may predate an upgrade of this node, so compare against the running version
+		result.UpgradeRequired = params.OPStackSupport.UpgradeRequired(signal.Required)
+	}
+	return result, nil
+}
+
+// FeeEstimate is the estimate of the fees of a transaction: the L2 fees per gas, and the L1 data fee
+// the transaction pays as a whole.
+type FeeEstimate struct {
+	OldestBlock          *hexutil.Big `json:"oldestBlock"`
+	BaseFee              *hexutil.Big `json:"baseFeePerGas"` // of the next block
+	MaxPriorityFeePerGas *hexutil.Big `json:"maxPriorityFeePerGas"`
+	// Reward holds the median over the blocks of each of the requested priority fee percentiles
+	Reward []*hexutil.Big `json:"reward"`
+	L1Fee  L1FeeEstimate  `json:"l1Fee"`
+}
+
+// L1FeeEstimate is the L1 data fee of a transaction with the L1 fees of the latest block and with their
+// trend projected a few blocks ahead, bounded by the lowest and highest fees over the blocks.
+type L1FeeEstimate struct {
+	Current   *hexutil.Big `json:"current"`
+	Projected *hexutil.Big `json:"projected"`
+	Low       *hexutil.Big `json:"low"`
+	High      *hexutil.Big `json:"high"`
+}
+
+// EstimateFees implements optimism_estimateFees. Returns the L2 priority fee percentiles of the latest blocks
+// and the band of L1 data fees the transaction is expected to pay given the trend of the L1 base fee and
+// blob base fee the blocks set on the L1Block contract.
+func (api *OptimismAPIImpl) EstimateFees(ctx context.Context, args ethapi2.CallArgs, blockCount *rpc.DecimalOrHex, rewardPercentiles []float64) (*FeeEstimate, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cc, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if !cc.IsOptimism() {
+		return nil, errors.New("fee estimates are only available on OP stack chains")
+	}
+	blocks := defaultFeeEstimateBlocks
+	if blockCount != nil {
+		blocks = min(int(*blockCount), maxFeeEstimateBlocks)
+	}
+	if rewardPercentiles == nil {
+		rewardPercentiles = defaultFeeEstimatePercentiles
+	}
+
+	rcd, err := rollupCostData(cc, &args)
+	if err != nil {
+		return nil, err
+	}
+	oracle := gasprice.NewOracle(NewGasPriceOracleBackend(tx, api.BaseAPI), ethconfig.Defaults.GPO, api.gasCache)
+	estimate, err := oracle.EstimateOptimismFees(ctx, rcd, blocks, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	result := &FeeEstimate{
+		OldestBlock:          (*hexutil.Big)(estimate.OldestBlock),
+		BaseFee:              (*hexutil.Big)(estimate.BaseFee),
+		MaxPriorityFeePerGas: (*hexutil.Big)(estimate.PriorityFee),
+		Reward:               make([]*hexutil.Big, len(estimate.PriorityFeePercentiles)),
+		L1Fee: L1FeeEstimate{
+			Current:   (*hexutil.Big)(estimate.L1Fee.Current.ToBig()),
+			Projected: (*hexutil.Big)(estimate.L1Fee.Projected.ToBig()),
+			Low:       (*hexutil.Big)(estimate.L1Fee.Low.ToBig()),
+			High:      (*hexutil.Big)(estimate.L1Fee.High.ToBig()),
+		},
+	}
+	for i, reward := range estimate.PriorityFeePercentiles {
+		result.Reward[i] = (*hexutil.Big)(reward)
+	}
+	return result, nil
+}
+
+// feeEstimateKey signs the transactions fees are estimated for: the L1 data fee depends on the size
+// and the compressibility of the signed transaction, not on who signed it
+var feeEstimateKey, _ = crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+
+// rollupCostData is the rollup cost data of the transaction of the call arguments once signed
+func rollupCostData(cc *chain.Config, args *ethapi2.CallArgs) (types2.RollupCostData, error) {
+	chainID := uint256.MustFromBig(cc.ChainID)
+	fee := func(fee *hexutil.Big) *uint256.Int {
+		if fee == nil {
+			return new(uint256.Int)
+		}
+		return uint256.MustFromBig(fee.ToInt())
+	}
+	var accessList types2.AccessList
+	if args.AccessList != nil {
+		accessList = *args.AccessList
+	}
+
+	var txn types.Transaction
+	var commonTx *types.CommonTx
+	switch {
+	case args.GasPrice != nil && args.AccessList == nil:
+		legacyTx := &types.LegacyTx{GasPrice: fee(args.GasPrice)}
+		txn, commonTx = legacyTx, &legacyTx.CommonTx
+	case args.GasPrice != nil:
+		accessListTx := &types.AccessListTx{LegacyTx: types.LegacyTx{GasPrice: fee(args.GasPrice)}, ChainID: chainID, AccessList: accessList}
+		txn, commonTx = accessListTx, &accessListTx.CommonTx
+	default:
+		dynamicFeeTx := &types.DynamicFeeTransaction{ChainID: chainID, Tip: fee(args.MaxPriorityFeePerGas), FeeCap: fee(args.MaxFeePerGas), AccessList: accessList}
+		txn, commonTx = dynamicFeeTx, &dynamicFeeTx.CommonTx
+	}
+	commonTx.To, commonTx.Value = args.To, fee(args.Value)
+	if args.Nonce != nil {
+		commonTx.Nonce = uint64(*args.Nonce)
+	}
+	if args.Gas != nil {
+		commonTx.Gas = uint64(*args.Gas)
+	}
+	if args.Input != nil {
+		commonTx.Data = *args.Input
+	} else if args.Data != nil {
+		commonTx.Data = *args.Data
+	}
+
+	signed, err := types.SignTx(txn, *types.LatestSignerForChainID(cc.ChainID), feeEstimateKey)
+	if err != nil {
+		return types2.RollupCostData{}, err
+	}
+	return signed.RollupCostData(), nil
+}
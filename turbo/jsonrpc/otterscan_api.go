@@ -48,6 +48,7 @@ type OtterscanAPI interface {
 	GetInternalOperations(ctx context.Context, hash common.Hash) ([]*InternalOperation, error)
 	SearchTransactionsBefore(ctx context.Context, addr common.Address, blockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error)
 	SearchTransactionsAfter(ctx context.Context, addr common.Address, blockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error)
+	SearchDepositBySourceHash(ctx context.Context, sourceHash common.Hash, blockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error)
 	GetBlockDetails(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error)
 	GetBlockDetailsByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error)
 	GetBlockTransactions(ctx context.Context, number rpc.BlockNumber, pageNumber uint8, pageSize uint8) (map[string]interface{}, error)
@@ -723,6 +724,7 @@ type internalIssuance struct {
 	BlockReward string `json:"blockReward,omitempty"`
 	UncleReward string `json:"uncleReward,omitempty"`
 	Issuance    string `json:"issuance,omitempty"`
+	Minted      string `json:"minted,omitempty"` // ETH minted by the deposits of an OP block, bridged from L1 so not part of Issuance
 }
 
 func delegateIssuance(tx kv.Tx, block *types.Block, chainConfig *chain.Config, engine consensus.EngineReader) (internalIssuance, error) {
@@ -750,28 +752,45 @@ func delegateIssuance(tx kv.Tx, block *types.Block, chainConfig *chain.Config, e
 	ret.UncleReward = hexutil2.EncodeBig(uncleReward.ToBig())
 
 	blockReward.Add(blockReward, uncleReward)
+	if chainConfig.IsOptimism() {
+		minted := uint256.NewInt(0)
+		for _, txn := range block.Transactions() {
+			if deposit, ok := txn.(*types.DepositTx); ok && deposit.Mint != nil {
+				minted.Add(minted, deposit.Mint)
+			}
+		}
+		ret.Minted = hexutil2.EncodeBig(minted.ToBig())
+	}
 	ret.Issuance = hexutil2.EncodeBig(blockReward.ToBig())
 	return ret, nil
 }
 
-func delegateBlockFees(ctx context.Context, tx kv.Tx, block *types.Block, senders []common.Address, chainConfig *chain.Config, receipts types.Receipts) (*big.Int, uint64, error) {
+// delegateBlockFees sums up the execution fees and, on OP chains, the L1 data fees paid by the transactions
+// of the block. Deposits pay neither, the gas they use is returned apart.
+func delegateBlockFees(ctx context.Context, tx kv.Tx, block *types.Block, senders []common.Address, chainConfig *chain.Config, receipts types.Receipts) (*big.Int, *big.Int, uint64, error) {
 	gasUsedDepositTx := uint64(0)
 	fee := big.NewInt(0)
 	gasUsed := big.NewInt(0)
 
 	totalFees := big.NewInt(0)
+	l1Fees := big.NewInt(0)
 	for _, receipt := range receipts {
 		txn := block.Transactions()[receipt.TransactionIndex]
+		if chainConfig.IsOptimism() {
+			if receipt.IsDepositTxReceipt() {
+				// if depositTx, no fee consumption
+				gasUsedDepositTx += receipt.GasUsed
+				continue
+			}
+			if receipt.L1Fee != nil {
+				l1Fees.Add(l1Fees, receipt.L1Fee)
+			}
+		}
 		effectiveGasPrice := uint64(0)
 		if !chainConfig.IsLondon(block.NumberU64()) {
 			effectiveGasPrice = txn.GetPrice().Uint64()
 		} else {
 			baseFee, _ := uint256.FromBig(block.BaseFee())
-			if chainConfig.IsOptimism() && receipt.IsDepositTxReceipt() {
-				// if depositTx, no fee consumption
-				gasUsedDepositTx += receipt.GasUsed
-				continue
-			}
 			gasPrice := new(big.Int).Add(block.BaseFee(), txn.GetEffectiveGasTip(baseFee).ToBig())
 			effectiveGasPrice = gasPrice.Uint64()
 		}
@@ -783,12 +802,13 @@ func delegateBlockFees(ctx context.Context, tx kv.Tx, block *types.Block, sender
 		totalFees.Add(totalFees, fee)
 	}
 
-	return totalFees, gasUsedDepositTx, nil
+	return totalFees, l1Fees, gasUsedDepositTx, nil
 }
 
 func (api *OtterscanAPIImpl) getBlockWithSenders(ctx context.Context, number rpc.BlockNumber, tx kv.Tx) (*types.Block, []common.Address, error) {
 	if number == rpc.PendingBlockNumber {
-		return api.pendingBlock(), nil, nil
+		block, err := api.pendingBlock(ctx, tx)
+		return block, nil, err
 	}
 
 	n, hash, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(number), tx, api.filters)
@@ -3,11 +3,15 @@ package jsonrpc
 import (
 	"context"
 	"fmt"
+	"math/big"
+
+	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/opstack"
 
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
@@ -78,7 +82,7 @@ func (api *OtterscanAPIImpl) getBlockDetailsImpl(ctx context.Context, tx kv.Tx,
 	if err != nil {
 		return nil, fmt.Errorf("getReceipts error: %v", err)
 	}
-	feesRes, gasUsedDepositTxRes, err := delegateBlockFees(ctx, tx, b, senders, chainConfig, receipts)
+	feesRes, l1FeesRes, gasUsedDepositTxRes, err := delegateBlockFees(ctx, tx, b, senders, chainConfig, receipts)
 	if err != nil {
 		return nil, err
 	}
@@ -86,9 +90,52 @@ func (api *OtterscanAPIImpl) getBlockDetailsImpl(ctx context.Context, tx kv.Tx,
 	response := map[string]interface{}{}
 	response["block"] = getBlockRes
 	response["issuance"] = getIssuanceRes
-	response["totalFees"] = (*hexutil.Big)(feesRes)
+	response["totalFees"] = (*hexutil.Big)(new(big.Int).Add(feesRes, l1FeesRes))
 	if chainConfig.IsOptimism() {
+		response["feesDetails"] = &otsFeesDetails{
+			ExecutionFees: (*hexutil.Big)(feesRes),
+			L1Fees:        (*hexutil.Big)(l1FeesRes),
+		}
 		response["gasUsedDepositTx"] = hexutil.Uint64(gasUsedDepositTxRes)
+		if l1Origin := delegateL1Origin(b); l1Origin != nil {
+			response["l1Origin"] = l1Origin
+		}
 	}
 	return response, nil
 }
+
+// otsFeesDetails splits the total fees of an OP block between the L2 execution and the L1 data
+type otsFeesDetails struct {
+	ExecutionFees *hexutil.Big `json:"executionFees"`
+	L1Fees        *hexutil.Big `json:"l1Fees"`
+}
+
+type otsL1Origin struct {
+	Number     hexutil.Uint64 `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	Timestamp  hexutil.Uint64 `json:"timestamp"`
+	SourceHash common.Hash    `json:"sourceHash"` // of the L1 info deposit
+}
+
+// delegateL1Origin decodes the L1 block an OP block was derived from out of its L1 info deposit,
+// nil if the block doesn't start with one.
+func delegateL1Origin(b *types.Block) *otsL1Origin {
+	if len(b.Transactions()) == 0 {
+		return nil
+	}
+	l1Info, ok := b.Transactions()[0].(*types.DepositTx)
+	if !ok || l1Info.To == nil || *l1Info.To != opstack.L1BlockAddr {
+		return nil
+	}
+	origin, err := opstack.ExtractL1Origin(l1Info.Data)
+	if err != nil {
+		log.Debug("Failed to decode L1 origin", "number", b.NumberU64(), "err", err)
+		return nil
+	}
+	return &otsL1Origin{
+		Number:     hexutil.Uint64(origin.Number),
+		Hash:       origin.Hash,
+		Timestamp:  hexutil.Uint64(origin.Time),
+		SourceHash: l1Info.SourceHash,
+	}
+}
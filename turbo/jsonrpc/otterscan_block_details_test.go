@@ -0,0 +1,107 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/opstack"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/common/u256"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+var (
+	otsL1OriginHash   = common.Hash{0x11}
+	otsUserDepositSrc = common.Hash{0x42}
+	otsDepositor      = common.HexToAddress("0x0000000000000000000000000000000000000d0d")
+)
+
+// writeOtsOptimismBlock writes a Bedrock block with the L1 info deposit, a user deposit minting 5 wei and a
+// legacy transaction.
+func writeOtsOptimismBlock(t *testing.T, m *mock.MockSentry) *types.Block {
+	l1BaseFee, overhead, scalar := uint256.NewInt(1000).Bytes32(), uint256.NewInt(100).Bytes32(), uint256.NewInt(100).Bytes32()
+	l1Info := buildSystemTx(l1BaseFee, overhead, scalar)
+	copy(l1Info, opstack.BedrockL1AttributesSelector)
+	binary.BigEndian.PutUint64(l1Info[4+24:4+32], 17_000_000)       // number
+	binary.BigEndian.PutUint64(l1Info[4+32+24:4+64], 1_700_000_000) // timestamp
+	copy(l1Info[4+96:4+128], otsL1OriginHash[:])
+
+	txs := types.Transactions{
+		&types.DepositTx{SourceHash: opstack.L1InfoDepositSource(otsL1OriginHash, 0), From: otsDepositor, To: &opstack.L1BlockAddr,
+			Mint: uint256.NewInt(0), Value: uint256.NewInt(0), Gas: 1_000_000, IsSystemTransaction: true, Data: l1Info},
+		&types.DepositTx{SourceHash: otsUserDepositSrc, From: otsDepositor, To: &otsDepositor, Mint: uint256.NewInt(5), Value: uint256.NewInt(5), Gas: 21_000},
+		types.NewTransaction(2, common.HexToAddress("0x2"), u256.Num2, 21_000, u256.Num2, nil),
+	}
+	header := &types.Header{Number: new(big.Int).Add(m.ChainConfig.BedrockBlock, big.NewInt(1)), Difficulty: big.NewInt(100)}
+	body := &types.Body{Transactions: txs}
+	depositNonce := uint64(3)
+	receipts := types.Receipts{
+		{Type: types.DepositTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 50_000, GasUsed: 50_000},
+		{Type: types.DepositTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 71_000, GasUsed: 21_000, DepositNonce: &depositNonce},
+		{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 92_000, GasUsed: 21_000},
+	}
+
+	tx, err := m.DB.BeginRw(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	require.NoError(t, rawdb.WriteCanonicalHash(tx, header.Hash(), header.Number.Uint64()))
+	rawdb.WriteHeader(tx, header)
+	require.NoError(t, rawdb.WriteBody(tx, header.Hash(), header.Number.Uint64(), body))
+	require.NoError(t, rawdb.WriteSenders(tx, header.Hash(), header.Number.Uint64(), body.SendersFromTxs()))
+	require.NoError(t, rawdb.WriteReceipts(tx, header.Number.Uint64(), receipts))
+	block, _, err := m.BlockReader.BlockWithSenders(context.Background(), tx, header.Hash(), header.Number.Uint64())
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	return block
+}
+
+func TestGetBlockDetailsOptimism(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateOptimismTestSentry(t)
+	agg := m.HistoryV3Components()
+	api := NewOtterscanAPI(NewBaseApi(nil, nil, m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs, nil, nil), m.DB, 25)
+	block := writeOtsOptimismBlock(t, m)
+
+	details, err := api.GetBlockDetails(m.Ctx, rpc.BlockNumber(block.NumberU64()))
+	require.NoError(t, err)
+
+	require.Equal(t, &otsL1Origin{
+		Number:     17_000_000,
+		Hash:       otsL1OriginHash,
+		Timestamp:  1_700_000_000,
+		SourceHash: opstack.L1InfoDepositSource(otsL1OriginHash, 0),
+	}, details["l1Origin"])
+	require.Equal(t, hexutil.Uint64(71_000), details["gasUsedDepositTx"])
+
+	// only the legacy transaction pays fees
+	tx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	receipts, err := api.getReceipts(m.Ctx, tx, block, block.Body().SendersFromTxs())
+	require.NoError(t, err)
+	l1Fee := receipts[2].L1Fee
+	require.Positive(t, l1Fee.Sign())
+	feesDetails := details["feesDetails"].(*otsFeesDetails)
+	require.Equal(t, big.NewInt(2*21_000), feesDetails.ExecutionFees.ToInt())
+	require.Equal(t, l1Fee, feesDetails.L1Fees.ToInt())
+	require.Equal(t, new(big.Int).Add(l1Fee, big.NewInt(2*21_000)), details["totalFees"].(*hexutil.Big).ToInt())
+
+	issuance := details["issuance"].(internalIssuance)
+	require.Equal(t, "0x5", issuance.Minted)
+	// minted ETH is bridged from L1, not issued
+	blockReward, err := hexutil.DecodeBig(issuance.BlockReward)
+	require.NoError(t, err)
+	uncleReward, err := hexutil.DecodeBig(issuance.UncleReward)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.EncodeBig(blockReward.Add(blockReward, uncleReward)), issuance.Issuance)
+}
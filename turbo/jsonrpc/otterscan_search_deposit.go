@@ -0,0 +1,103 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/ethutils"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// Search the deposit transaction of an OP chain by its source hash, which identifies the L1 event it was
+// derived from (the user deposit log or the L1 info of an L1 block).
+//
+// It searches back a certain block (excluding), 0 means the latest block (including). Deposits aren't
+// indexed by source hash: pageSize is the number of blocks searched, the next page starts pageSize
+// blocks further back. At most one deposit is returned; the last page is reached once it is found or
+// the search reached Bedrock.
+func (api *OtterscanAPIImpl) SearchDepositBySourceHash(ctx context.Context, sourceHash common.Hash, blockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error) {
+	if pageSize == 0 {
+		// nothing would be scanned and the next page would start at the same block
+		return nil, &rpc.InvalidParamsError{Message: "page size must be at least 1"}
+	}
+	if uint64(pageSize) > api.maxPageSize {
+		return nil, fmt.Errorf("max allowed page size: %v", api.maxPageSize)
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if !chainConfig.IsOptimism() {
+		return nil, fmt.Errorf("deposits only exist on OP chains")
+	}
+
+	isFirstPage := false
+	if blockNum == 0 {
+		isFirstPage = true
+		latest, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), tx, api.filters)
+		if err != nil {
+			return nil, err
+		}
+		blockNum = latest + 1
+	}
+	var bedrockBlock uint64
+	if chainConfig.BedrockBlock != nil {
+		bedrockBlock = chainConfig.BedrockBlock.Uint64()
+	}
+
+	result := &TransactionsWithReceipts{
+		Txs:       make([]*RPCTransaction, 0, 1),
+		Receipts:  make([]map[string]interface{}, 0, 1),
+		FirstPage: isFirstPage,
+	}
+	for n := blockNum; n > bedrockBlock && blockNum-n < uint64(pageSize); n-- {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		block, err := api.blockByNumberWithSenders(ctx, tx, n-1)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			continue
+		}
+		for idx, txn := range block.Transactions() {
+			// deposits come first in a block
+			deposit, ok := txn.(*types.DepositTx)
+			if !ok {
+				break
+			}
+			if deposit.SourceHash != sourceHash {
+				continue
+			}
+			receipts, err := api.getReceipts(ctx, tx, block, block.Body().SendersFromTxs())
+			if err != nil {
+				return nil, fmt.Errorf("getReceipts error: %w", err)
+			}
+			if idx >= len(receipts) {
+				return nil, fmt.Errorf("requested receipt idx %d, but have only %d", idx, len(receipts))
+			}
+			mReceipt := ethutils.MarshalReceipt(receipts[idx], txn, chainConfig, block.HeaderNoCopy(), txn.Hash(), true)
+			mReceipt["timestamp"] = block.Time()
+			result.Txs = append(result.Txs, NewRPCTransaction(txn, block.Hash(), block.NumberU64(), uint64(idx), block.BaseFee(), receipts[idx]))
+			result.Receipts = append(result.Receipts, mReceipt)
+			result.LastPage = true
+			return result, nil
+		}
+	}
+	result.LastPage = blockNum <= bedrockBlock+uint64(pageSize)
+	return result, nil
+}
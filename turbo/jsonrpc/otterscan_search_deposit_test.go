@@ -0,0 +1,46 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+)
+
+func TestSearchDepositBySourceHash(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateOptimismTestSentry(t)
+	agg := m.HistoryV3Components()
+	api := NewOtterscanAPI(NewBaseApi(nil, nil, m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs, nil, nil), m.DB, 25)
+	block := writeOtsOptimismBlock(t, m)
+
+	results, err := api.SearchDepositBySourceHash(m.Ctx, otsUserDepositSrc, block.NumberU64()+3, 5)
+	require.NoError(t, err)
+	require.Len(t, results.Txs, 1)
+	require.Len(t, results.Receipts, 1)
+	require.Equal(t, block.Transactions()[1].Hash(), results.Txs[0].Hash)
+	require.Equal(t, &otsUserDepositSrc, results.Txs[0].SourceHash)
+	require.False(t, results.FirstPage)
+	require.True(t, results.LastPage)
+
+	// the block is out of the searched range
+	results, err = api.SearchDepositBySourceHash(m.Ctx, otsUserDepositSrc, block.NumberU64()+6, 5)
+	require.NoError(t, err)
+	require.Empty(t, results.Txs)
+	require.False(t, results.LastPage)
+
+	// the search stops at Bedrock
+	results, err = api.SearchDepositBySourceHash(m.Ctx, common.Hash{0x43}, block.NumberU64()+3, 5)
+	require.NoError(t, err)
+	require.Empty(t, results.Txs)
+	require.True(t, results.LastPage)
+
+	_, err = api.SearchDepositBySourceHash(m.Ctx, otsUserDepositSrc, block.NumberU64()+1, 26)
+	require.Error(t, err)
+	_, err = api.SearchDepositBySourceHash(m.Ctx, otsUserDepositSrc, block.NumberU64()+1, 0)
+	var paramsErr *rpc.InvalidParamsError
+	require.ErrorAs(t, err, &paramsErr)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/temporal/historyv2"
 	"github.com/ledgerwatch/log/v3"
 
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 )
 
@@ -143,6 +144,21 @@ func (api *OtterscanAPIImpl) GetTransactionBySenderAndNonce(ctx context.Context,
 			return nil, nil
 		}
 		found := txn.GetNonce() == nonce
+		if txn.Type() == types.DepositTxType {
+			block, err := api.blockByNumberWithSenders(ctx, tx, bn)
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				return nil, fmt.Errorf("block not found: %d", bn)
+			}
+			receipts, err := api.getReceipts(ctx, tx, block, block.Body().SendersFromTxs())
+			if err != nil {
+				return nil, err
+			}
+			depositNonce, ok := depositNonce(receipts, txIndex)
+			found = ok && depositNonce == nonce
+		}
 		if !found {
 			return nil, nil
 		}
@@ -287,12 +303,24 @@ func (api *OtterscanAPIImpl) findNonce(ctx context.Context, tx kv.Tx, addr commo
 	senders := block.Body().SendersFromTxs()
 
 	txs := block.Transactions()
+	var receipts types.Receipts // computed once, for the first deposit of the sender
 	for i, s := range senders {
 		if s != addr {
 			continue
 		}
 
 		t := txs[i]
+		if t.Type() == types.DepositTxType {
+			if receipts == nil {
+				if receipts, err = api.getReceipts(ctx, tx, block, senders); err != nil {
+					return false, common.Hash{}, err
+				}
+			}
+			if depositNonce, ok := depositNonce(receipts, i); ok && depositNonce == nonce {
+				return true, t.Hash(), nil
+			}
+			continue
+		}
 		if t.GetNonce() == nonce {
 			return true, t.Hash(), nil
 		}
@@ -300,3 +328,12 @@ func (api *OtterscanAPIImpl) findNonce(ctx context.Context, tx kv.Tx, addr commo
 
 	return false, common.Hash{}, nil
 }
+
+// depositNonce returns the sender nonce a deposit transaction used. Deposits don't carry a nonce,
+// it is recorded in their receipt since Regolith; ok is false before.
+func depositNonce(receipts types.Receipts, txIndex int) (nonce uint64, ok bool) {
+	if txIndex >= len(receipts) || receipts[txIndex].DepositNonce == nil {
+		return 0, false
+	}
+	return *receipts[txIndex].DepositNonce, true
+}
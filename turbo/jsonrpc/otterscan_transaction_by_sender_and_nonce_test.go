@@ -54,3 +54,23 @@ func TestGetTransactionBySenderAndNonce(t *testing.T) {
 		require.Nil(results)
 	})
 }
+
+func TestDepositNonce(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateOptimismTestSentry(t)
+	agg := m.HistoryV3Components()
+	api := NewOtterscanAPI(NewBaseApi(nil, nil, m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs, nil, nil), m.DB, 25)
+	block := writeOtsOptimismBlock(t, m)
+
+	tx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	receipts, err := api.getReceipts(m.Ctx, tx, block, block.Body().SendersFromTxs())
+	require.NoError(t, err)
+	nonce, ok := depositNonce(receipts, 1)
+	require.True(t, ok)
+	require.Equal(t, uint64(3), nonce)
+
+	// the receipt of the L1 info deposit doesn't record its nonce
+	_, ok = depositNonce(receipts, 0)
+	require.False(t, ok)
+}
@@ -0,0 +1,359 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/opstack"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// pendingBlockRebuildInterval is how long a simulated pending block is served before it's built again on top of the
+// same head, to pick up the transactions which entered the txpool since
+const pendingBlockRebuildInterval = 2 * time.Second
+
+// simulatedPendingBlock is the block an OP node which doesn't sequence expects next: the L1 info deposit derived from
+// the one of the head, followed by the pending transactions of the local txpool. It's executed on top of the head and
+// never written to the database.
+type simulatedPendingBlock struct {
+	block    *types.Block
+	receipts types.Receipts
+	state    *pendingState
+	builtAt  time.Time
+}
+
+// pendingBlockSimulator keeps the last simulated pending block, shared by the APIs of the daemon
+type pendingBlockSimulator struct {
+	txPool txpool.TxpoolClient
+	lock   sync.Mutex
+	last   *simulatedPendingBlock
+}
+
+func newPendingBlockSimulator(txPool txpool.TxpoolClient) *pendingBlockSimulator {
+	return &pendingBlockSimulator{txPool: txPool}
+}
+
+// simulatedPendingBlock returns the pending block simulated on top of the head, nil when the chain isn't an OP chain
+// or when the head has no L1 info deposit to derive the next one from.
+func (api *BaseAPI) simulatedPendingBlock(ctx context.Context, tx kv.Tx) (*simulatedPendingBlock, error) {
+	if api.pendingSimulator == nil {
+		return nil, nil
+	}
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if !chainConfig.IsOptimism() {
+		return nil, nil
+	}
+	headNumber, err := rpchelper.GetLatestBlockNumber(tx)
+	if err != nil {
+		return nil, err
+	}
+	if headNumber == 0 {
+		return nil, nil
+	}
+	head, err := api.blockByNumberWithSenders(ctx, tx, headNumber)
+	if err != nil || head == nil {
+		return nil, err
+	}
+
+	sim := api.pendingSimulator
+	sim.lock.Lock()
+	defer sim.lock.Unlock()
+	if last := sim.last; last != nil && last.block.ParentHash() == head.Hash() && time.Since(last.builtAt) < pendingBlockRebuildInterval {
+		return last, nil
+	}
+
+	parent, err := api._blockReader.HeaderByNumber(ctx, tx, headNumber-1)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("block %d not found", headNumber-1)
+	}
+	// the block time of the rollup isn't part of the chain config, the head tells it
+	blockTime := uint64(1)
+	if head.Time() > parent.Time {
+		blockTime = head.Time() - parent.Time
+	}
+
+	// the txpool yields the pending transactions in the order the block builder would include them; a disabled
+	// txpool only leaves the deposit in the block
+	var txs types.Transactions
+	if reply, err := sim.txPool.Pending(ctx, &emptypb.Empty{}); err == nil {
+		for _, pending := range reply.Txs {
+			txn, err := types.DecodeWrappedTransaction(pending.RlpTx)
+			if err != nil {
+				continue
+			}
+			txn.SetSender(gointerfaces.ConvertH160toAddress(pending.Sender))
+			txs = append(txs, txn)
+		}
+	}
+
+	stateReader, err := rpchelper.CreateStateReaderFromBlockNumber(ctx, tx, headNumber, true, 0, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+	if err != nil {
+		return nil, err
+	}
+	getHeader := func(hash common.Hash, number uint64) *types.Header {
+		h, _ := api._blockReader.Header(ctx, tx, hash, number)
+		return h
+	}
+	chainReader := stagedsync.NewChainReaderImpl(chainConfig, tx, nil, nil)
+	pending, err := buildPendingBlock(ctx, chainConfig, api.engine(), chainReader, getHeader, head, blockTime, stateReader, txs)
+	if err != nil || pending == nil {
+		return nil, err
+	}
+	pending.builtAt = time.Now()
+	sim.last = pending
+	return pending, nil
+}
+
+// pendingHeaderAndState returns the header of the simulated pending block and a reader of the state after it, when
+// blockNrOrHash is the pending block and a pending block is simulated
+func (api *BaseAPI) pendingHeaderAndState(ctx context.Context, tx kv.Tx, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, state.StateReader, error) {
+	if number, ok := blockNrOrHash.Number(); !ok || number != rpc.PendingBlockNumber {
+		return nil, nil, nil
+	}
+	pending, err := api.simulatedPendingBlock(ctx, tx)
+	if err != nil || pending == nil {
+		return nil, nil, err
+	}
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	// simulatedPendingBlock made sure the block is on top of the head of tx
+	headReader, err := rpchelper.CreateStateReaderFromBlockNumber(ctx, tx, pending.block.NumberU64()-1, true, 0, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pending.block.Header(), &pendingStateReader{pending: pending.state, head: headReader}, nil
+}
+
+// createStateReader is rpchelper.CreateStateReader, which reads the state after the simulated pending block when
+// there is one
+func (api *BaseAPI) createStateReader(ctx context.Context, tx kv.Tx, blockNrOrHash rpc.BlockNumberOrHash, chainName string) (state.StateReader, error) {
+	if _, reader, err := api.pendingHeaderAndState(ctx, tx, blockNrOrHash); err != nil || reader != nil {
+		return reader, err
+	}
+	return rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainName)
+}
+
+// buildPendingBlock executes the next L1 info deposit and the transactions on top of head. Transactions which can't
+// be included are skipped. It returns nil when the head has no L1 info deposit.
+func buildPendingBlock(ctx context.Context, chainConfig *chain.Config, engine consensus.EngineReader, chainReader consensus.ChainHeaderReader,
+	getHeader func(hash common.Hash, number uint64) *types.Header, head *types.Block, blockTime uint64, stateReader state.StateReader,
+	txs types.Transactions) (*simulatedPendingBlock, error) {
+	if len(head.Transactions()) == 0 {
+		return nil, nil
+	}
+	l1Info, ok := head.Transactions()[0].(*types.DepositTx)
+	if !ok || l1Info.To == nil || *l1Info.To != opstack.L1BlockAddr {
+		return nil, nil
+	}
+	// the L1 origin of the next block is only known to the sequencer, the head's one is kept
+	data, seqNumber, err := opstack.NextL1InfoData(l1Info.Data)
+	if err != nil {
+		return nil, err
+	}
+	origin, err := opstack.ExtractL1Origin(data)
+	if err != nil {
+		return nil, err
+	}
+	nextL1Info := &types.DepositTx{
+		SourceHash:          opstack.L1InfoDepositSource(origin.Hash, seqNumber),
+		From:                l1Info.From,
+		To:                  l1Info.To,
+		Mint:                uint256.NewInt(0),
+		Value:               uint256.NewInt(0),
+		Gas:                 l1Info.Gas,
+		IsSystemTransaction: l1Info.IsSystemTransaction,
+		Data:                data,
+	}
+
+	gasLimit := head.GasLimit()
+	header := core.MakeEmptyHeader(head.HeaderNoCopy(), chainConfig, head.Time()+blockTime, &gasLimit)
+	header.Coinbase = head.Coinbase()
+	header.MixDigest = head.MixDigest()
+	header.ParentBeaconBlockRoot = head.HeaderNoCopy().ParentBeaconBlockRoot
+	header.Extra = common.CopyBytes(head.Extra())
+	var withdrawals []*types.Withdrawal
+	if chainConfig.IsShanghai(header.Time) {
+		withdrawals = []*types.Withdrawal{}
+	}
+	rules := chainConfig.Rules(header.Number.Uint64(), header.Time)
+	signer := types.MakeSigner(chainConfig, header.Number.Uint64(), header.Time)
+
+	ibs := state.New(stateReader)
+	pending := newPendingState()
+	if engine, ok := engine.(consensus.Engine); ok {
+		if err := engine.Initialize(chainConfig, chainReader, header, ibs, func(contract common.Address, data []byte, ibs *state.IntraBlockState, header *types.Header, constCall bool) ([]byte, error) {
+			return core.SysCallContract(contract, data, chainConfig, ibs, header, engine, constCall)
+		}, log.Root()); err != nil {
+			return nil, err
+		}
+		if err := ibs.FinalizeTx(rules, pending); err != nil {
+			return nil, err
+		}
+	}
+
+	gasPool := new(core.GasPool).AddGas(header.GasLimit)
+	if header.BlobGasUsed != nil {
+		gasPool.AddBlobGas(chainConfig.GetMaxBlobGasPerBlock())
+	}
+	var included types.Transactions
+	var receipts types.Receipts
+	for _, txn := range append(types.Transactions{nextL1Info}, txs...) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if len(included) > 0 && (txn.Type() == types.DepositTxType || txn.Type() == types.BlobTxType) {
+			continue
+		}
+		if gasPool.Gas() < txn.GetGas() {
+			continue
+		}
+		if _, err := txn.Sender(*signer); err != nil {
+			continue
+		}
+		ibs.SetTxContext(txn.Hash(), common.Hash{}, len(included))
+		snap := ibs.Snapshot()
+		gasSnap, blobGasSnap := gasPool.Gas(), gasPool.BlobGas()
+		receipt, _, err := core.ApplyTransaction(chainConfig, core.GetHashFn(header, getHeader), engine, &header.Coinbase, gasPool, ibs, pending, header, txn, &header.GasUsed, header.BlobGasUsed, vm.Config{})
+		if err != nil {
+			if len(included) == 0 {
+				return nil, fmt.Errorf("L1 info deposit: %w", err)
+			}
+			ibs.RevertToSnapshot(snap)
+			gasPool = new(core.GasPool).AddGas(gasSnap).AddBlobGas(blobGasSnap)
+			continue
+		}
+		included = append(included, txn)
+		receipts = append(receipts, receipt)
+	}
+
+	return &simulatedPendingBlock{
+		block:    types.NewBlock(header, included, nil, receipts, withdrawals),
+		receipts: receipts,
+		state:    pending,
+	}, nil
+}
+
+type pendingStorageKey struct {
+	address     common.Address
+	incarnation uint64
+	key         common.Hash
+}
+
+// pendingState is the StateWriter of the simulated pending block, it keeps the changes of the block in memory
+type pendingState struct {
+	accounts     map[common.Address]*accounts.Account // nil for the deleted accounts
+	incarnations map[common.Address]uint64            // incarnations of the deleted contracts
+	storage      map[pendingStorageKey][]byte
+	code         map[common.Hash][]byte
+}
+
+func newPendingState() *pendingState {
+	return &pendingState{
+		accounts:     map[common.Address]*accounts.Account{},
+		incarnations: map[common.Address]uint64{},
+		storage:      map[pendingStorageKey][]byte{},
+		code:         map[common.Hash][]byte{},
+	}
+}
+
+func (s *pendingState) UpdateAccountData(address common.Address, _, account *accounts.Account) error {
+	acc := *account
+	s.accounts[address] = &acc
+	return nil
+}
+
+func (s *pendingState) UpdateAccountCode(_ common.Address, _ uint64, codeHash common.Hash, code []byte) error {
+	s.code[codeHash] = bytes.Clone(code)
+	return nil
+}
+
+func (s *pendingState) DeleteAccount(address common.Address, original *accounts.Account) error {
+	s.accounts[address] = nil
+	if original.Incarnation > 0 {
+		s.incarnations[address] = original.Incarnation
+	}
+	return nil
+}
+
+func (s *pendingState) WriteAccountStorage(address common.Address, incarnation uint64, key *common.Hash, _, value *uint256.Int) error {
+	s.storage[pendingStorageKey{address: address, incarnation: incarnation, key: *key}] = value.Bytes()
+	return nil
+}
+
+func (s *pendingState) CreateContract(common.Address) error {
+	return nil
+}
+
+// pendingStateReader reads the state after the simulated pending block: its changes, on top of the state of the head
+type pendingStateReader struct {
+	pending *pendingState
+	head    state.StateReader
+}
+
+func (r *pendingStateReader) ReadAccountData(address common.Address) (*accounts.Account, error) {
+	if acc, ok := r.pending.accounts[address]; ok {
+		if acc == nil {
+			return nil, nil
+		}
+		res := *acc
+		return &res, nil
+	}
+	return r.head.ReadAccountData(address)
+}
+
+func (r *pendingStateReader) ReadAccountStorage(address common.Address, incarnation uint64, key *common.Hash) ([]byte, error) {
+	if value, ok := r.pending.storage[pendingStorageKey{address: address, incarnation: incarnation, key: *key}]; ok {
+		return value, nil
+	}
+	return r.head.ReadAccountStorage(address, incarnation, key)
+}
+
+func (r *pendingStateReader) ReadAccountCode(address common.Address, incarnation uint64, codeHash common.Hash) ([]byte, error) {
+	if code, ok := r.pending.code[codeHash]; ok {
+		return code, nil
+	}
+	return r.head.ReadAccountCode(address, incarnation, codeHash)
+}
+
+func (r *pendingStateReader) ReadAccountCodeSize(address common.Address, incarnation uint64, codeHash common.Hash) (int, error) {
+	if code, ok := r.pending.code[codeHash]; ok {
+		return len(code), nil
+	}
+	return r.head.ReadAccountCodeSize(address, incarnation, codeHash)
+}
+
+func (r *pendingStateReader) ReadAccountIncarnation(address common.Address) (uint64, error) {
+	if incarnation, ok := r.pending.incarnations[address]; ok {
+		return incarnation, nil
+	}
+	return r.head.ReadAccountIncarnation(address)
+}
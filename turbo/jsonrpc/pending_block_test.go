@@ -0,0 +1,136 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/opstack"
+
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+func TestBuildPendingBlock(t *testing.T) {
+	require := require.New(t)
+	chainConfig := params.OptimismTestConfig
+	chainID, _ := uint256.FromBig(chainConfig.ChainID)
+	signer := types.LatestSignerForChainID(chainConfig.ChainID)
+	key, _ := crypto.GenerateKey()
+	sender, recipient := crypto.PubkeyToAddress(key.PublicKey), common.Address{0x42}
+
+	tx := memdb.BeginRw(t, memdb.NewTestDB(t))
+	ibs := state.New(state.NewPlainStateReader(tx))
+	ibs.AddBalance(sender, uint256.NewInt(params.Ether))
+	require.NoError(ibs.CommitBlock(chainConfig.Rules(10, 100), state.NewPlainStateWriterNoHistory(tx)))
+
+	// Ecotone L1 attributes with the sequence number 7
+	l1Info := make([]byte, opstack.PostEcotoneL1InfoBytes)
+	copy(l1Info, opstack.EcotoneL1AttributesSelector)
+	binary.BigEndian.PutUint64(l1Info[12:20], 7)
+	binary.BigEndian.PutUint64(l1Info[28:36], 1000)
+	l1Hash := common.Hash{0x11}
+	copy(l1Info[100:132], l1Hash[:])
+	depositor := common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001")
+	head := types.NewBlock(&types.Header{
+		Number:   big.NewInt(10),
+		Time:     100,
+		GasLimit: 30_000_000,
+		BaseFee:  big.NewInt(params.GWei),
+		Coinbase: common.Address{0xfe},
+	}, types.Transactions{&types.DepositTx{From: depositor, To: &opstack.L1BlockAddr, Mint: uint256.NewInt(0), Value: uint256.NewInt(0), Gas: 1_000_000, Data: l1Info}}, nil, nil, nil)
+
+	newTx := func(nonce uint64) types.Transaction {
+		txn, err := types.SignTx(types.NewEIP1559Transaction(*chainID, nonce, recipient, uint256.NewInt(1234), params.TxGas, nil, uint256.NewInt(params.GWei), uint256.NewInt(10*params.GWei), nil), *signer, key)
+		require.NoError(err)
+		return txn
+	}
+	included := newTx(0)
+	// the nonce gap and the deposit from the txpool are skipped
+	txs := types.Transactions{included, newTx(2), &types.DepositTx{From: sender, To: &recipient, Mint: uint256.NewInt(0), Value: uint256.NewInt(1), Gas: 21_000}}
+
+	pending, err := buildPendingBlock(context.Background(), chainConfig, ethash.NewFaker(), nil, func(common.Hash, uint64) *types.Header { return nil },
+		head, 2, state.NewPlainStateReader(tx), txs)
+	require.NoError(err)
+	block := pending.block
+	require.Equal(uint64(11), block.NumberU64())
+	require.Equal(uint64(102), block.Time())
+	require.Equal(head.Hash(), block.ParentHash())
+	require.Equal(head.Coinbase(), block.Coinbase())
+	require.Len(block.Transactions(), 2)
+	require.Len(pending.receipts, 2)
+	require.Equal(included.Hash(), block.Transactions()[1].Hash())
+	require.Equal(block.GasUsed(), pending.receipts[1].CumulativeGasUsed)
+	for _, receipt := range pending.receipts {
+		require.Equal(types.ReceiptStatusSuccessful, receipt.Status)
+	}
+
+	deposit, ok := block.Transactions()[0].(*types.DepositTx)
+	require.True(ok)
+	require.Equal(uint64(8), binary.BigEndian.Uint64(deposit.Data[12:20]))
+	require.Equal(opstack.L1InfoDepositSource(l1Hash, 8), deposit.SourceHash)
+	require.Equal(depositor, deposit.From)
+
+	reader := &pendingStateReader{pending: pending.state, head: state.NewPlainStateReader(tx)}
+	acc, err := reader.ReadAccountData(sender)
+	require.NoError(err)
+	require.Equal(uint64(1), acc.Nonce)
+	acc, err = reader.ReadAccountData(recipient)
+	require.NoError(err)
+	require.Equal(uint64(1234), acc.Balance.Uint64())
+	// the head is untouched
+	acc, err = state.NewPlainStateReader(tx).ReadAccountData(sender)
+	require.NoError(err)
+	require.Zero(acc.Nonce)
+
+	// without an L1 info deposit in the head there is nothing to derive the pending block from
+	pending, err = buildPendingBlock(context.Background(), chainConfig, ethash.NewFaker(), nil, nil, types.NewBlockWithHeader(head.Header()), 2, state.NewPlainStateReader(tx), txs)
+	require.NoError(err)
+	require.Nil(pending)
+}
+
+func TestPendingStateReader(t *testing.T) {
+	require := require.New(t)
+	tx := memdb.BeginRw(t, memdb.NewTestDB(t))
+	contract, key := common.Address{1}, common.Hash{2}
+	w := state.NewPlainStateWriterNoHistory(tx)
+	require.NoError(w.UpdateAccountData(contract, &accounts.Account{}, &accounts.Account{Incarnation: 1, Initialised: true}))
+	require.NoError(w.WriteAccountStorage(contract, 1, &key, uint256.NewInt(0), uint256.NewInt(3)))
+
+	pending := newPendingState()
+	reader := &pendingStateReader{pending: pending, head: state.NewPlainStateReader(tx)}
+	value, err := reader.ReadAccountStorage(contract, 1, &key)
+	require.NoError(err)
+	require.Equal([]byte{3}, value)
+
+	// the contract is destructed then created again by the pending block
+	require.NoError(pending.DeleteAccount(contract, &accounts.Account{Incarnation: 1}))
+	acc, err := reader.ReadAccountData(contract)
+	require.NoError(err)
+	require.Nil(acc)
+	incarnation, err := reader.ReadAccountIncarnation(contract)
+	require.NoError(err)
+	require.Equal(uint64(1), incarnation)
+
+	require.NoError(pending.UpdateAccountData(contract, nil, &accounts.Account{Incarnation: 2, Initialised: true}))
+	require.NoError(pending.WriteAccountStorage(contract, 2, &key, uint256.NewInt(0), uint256.NewInt(4)))
+	acc, err = reader.ReadAccountData(contract)
+	require.NoError(err)
+	require.Equal(uint64(2), acc.Incarnation)
+	value, err = reader.ReadAccountStorage(contract, 2, &key)
+	require.NoError(err)
+	require.Equal([]byte{4}, value)
+	value, err = reader.ReadAccountStorage(contract, 1, &key)
+	require.NoError(err)
+	require.Equal([]byte{3}, value)
+}
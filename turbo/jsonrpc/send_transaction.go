@@ -9,11 +9,14 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	txPoolProto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
 
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 )
 
 // SendRawTransaction implements eth_sendRawTransaction. Creates new message call transaction or a contract creation for previously-signed transactions.
@@ -54,6 +57,13 @@ func (api *APIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutility
 		if err := api.seqRPCService.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(encodedTx)); err != nil {
 			return common.Hash{}, err
 		}
+		// keep the transaction in the local txpool too, for the simulated pending block and newPendingTransactions
+		res, err := api.txPool.Add(ctx, &txPoolProto.AddRequest{RlpTxs: [][]byte{encodedTx}})
+		if err != nil {
+			api.logger.Debug("Transaction sent to the sequencer, not added to the local txpool", "hash", txn.Hash(), "err", err)
+		} else if res.Imported[0] != txPoolProto.ImportResult_SUCCESS {
+			api.logger.Debug("Transaction sent to the sequencer, not added to the local txpool", "hash", txn.Hash(), "err", res.Errors[0])
+		}
 		return txn.Hash(), nil
 	}
 
@@ -87,6 +97,98 @@ func (api *APIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutility
 	return txn.Hash(), nil
 }
 
+// SendBundleArgs are the arguments of eth_sendBundle. blockNumber targets a single block, minBlockNumber and
+// maxBlockNumber a range of blocks, the bundle targets the next block when none of them is given.
+type SendBundleArgs struct {
+	Txs               []hexutility.Bytes `json:"txs"`
+	RevertingTxHashes []common.Hash      `json:"revertingTxHashes"`
+	BlockNumber       *hexutil.Uint64    `json:"blockNumber"`
+	MinBlockNumber    *hexutil.Uint64    `json:"minBlockNumber"`
+	MaxBlockNumber    *hexutil.Uint64    `json:"maxBlockNumber"`
+	MinTimestamp      *uint64            `json:"minTimestamp"`
+	MaxTimestamp      *uint64            `json:"maxTimestamp"`
+}
+
+type SendBundleResult struct {
+	BundleHash common.Hash `json:"bundleHash"`
+}
+
+// SendBundle implements eth_sendBundle. Submits transactions which the block builder of the node includes atomically
+// and in order: either all of them, or none. The transactions in revertingTxHashes may fail without dropping the bundle.
+func (api *APIImpl) SendBundle(ctx context.Context, args SendBundleArgs) (*SendBundleResult, error) {
+	if len(args.Txs) == 0 {
+		return nil, &rpc.InvalidParamsError{Message: "bundle has no transactions"}
+	}
+	if api.seqRPCService != nil {
+		var res SendBundleResult
+		if err := api.seqRPCService.CallContext(ctx, &res, "eth_sendBundle", args); err != nil {
+			return nil, err
+		}
+		return &res, nil
+	}
+
+	hashes := make(map[common.Hash]struct{}, len(args.Txs))
+	for i, encodedTx := range args.Txs {
+		txn, err := types.DecodeWrappedTransaction(encodedTx)
+		if err != nil {
+			return nil, &rpc.InvalidParamsError{Message: fmt.Sprintf("transaction %d: %s", i, err)}
+		}
+		if err := checkTxFee(txn.GetPrice().ToBig(), txn.GetGas(), api.FeeCap); err != nil {
+			return nil, err
+		}
+		if !txn.Protected() && !api.AllowUnprotectedTxs {
+			return nil, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+		}
+		hashes[txn.Hash()] = struct{}{}
+	}
+	req := &txPoolProto.SendBundleRequest{RlpTxs: make([][]byte, len(args.Txs))}
+	for i, encodedTx := range args.Txs {
+		req.RlpTxs[i] = encodedTx
+	}
+	for _, h := range args.RevertingTxHashes {
+		if _, ok := hashes[h]; !ok {
+			return nil, &rpc.InvalidParamsError{Message: fmt.Sprintf("reverting transaction %x is not in the bundle", h)}
+		}
+		req.RevertingTxHashes = append(req.RevertingTxHashes, gointerfaces.ConvertHashToH256(h))
+	}
+
+	switch {
+	case args.BlockNumber != nil:
+		req.MinBlockNumber, req.MaxBlockNumber = uint64(*args.BlockNumber), uint64(*args.BlockNumber)
+	case args.MinBlockNumber != nil || args.MaxBlockNumber != nil:
+		if args.MinBlockNumber != nil {
+			req.MinBlockNumber = uint64(*args.MinBlockNumber)
+		}
+		if args.MaxBlockNumber == nil {
+			return nil, &rpc.InvalidParamsError{Message: "maxBlockNumber is required with minBlockNumber"}
+		}
+		req.MaxBlockNumber = uint64(*args.MaxBlockNumber)
+	default:
+		tx, err := api.db.BeginRo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+		latest, err := rpchelper.GetLatestBlockNumber(tx)
+		if err != nil {
+			return nil, err
+		}
+		req.MinBlockNumber, req.MaxBlockNumber = latest+1, latest+1
+	}
+	if args.MinTimestamp != nil {
+		req.MinTimestamp = *args.MinTimestamp
+	}
+	if args.MaxTimestamp != nil {
+		req.MaxTimestamp = *args.MaxTimestamp
+	}
+
+	reply, err := api.txPool.SendBundle(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &SendBundleResult{BundleHash: gointerfaces.ConvertH256ToHash(reply.BundleHash)}, nil
+}
+
 // SendTransaction implements eth_sendTransaction. Creates new message call transaction or a contract creation if the data field contains code.
 func (api *APIImpl) SendTransaction(_ context.Context, txObject interface{}) (common.Hash, error) {
 	return common.Hash{0}, fmt.Errorf(NotImplemented, "eth_sendTransaction")
@@ -9,7 +9,10 @@ import (
 
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
 	"github.com/ledgerwatch/erigon-lib/wrap"
 
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
@@ -167,6 +170,47 @@ func TestSendRawTransactionUnprotected(t *testing.T) {
 	}
 }
 
+func TestSendBundle(t *testing.T) {
+	mockSentry, require := mock.MockWithTxPool(t), require.New(t)
+	logger := log.New()
+
+	oneBlockStep(mockSentry, require, t)
+
+	signer := types.LatestSignerForChainID(mockSentry.ChainConfig.ChainID)
+	var txs []hexutility.Bytes
+	var hashes [][32]byte
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		txn, err := types.SignTx(types.NewTransaction(nonce, common.Address{1}, uint256.NewInt(1), params.TxGas, uint256.NewInt(10*params.GWei), nil), *signer, mockSentry.Key)
+		require.NoError(err)
+		buf := bytes.NewBuffer(nil)
+		require.NoError(txn.MarshalBinary(buf))
+		txs = append(txs, buf.Bytes())
+		hashes = append(hashes, txn.Hash())
+	}
+
+	ctx, conn := rpcdaemontest.CreateTestGrpcConn(t, mockSentry)
+	txPool := txpool.NewTxpoolClient(conn)
+	api := jsonrpc.NewEthAPI(newBaseApiForTest(mockSentry), mockSentry.DB, nil, txPool, nil, 5000000, 1e18, 100_000, false, 100_000, 128, logger)
+
+	_, err := api.SendBundle(ctx, jsonrpc.SendBundleArgs{Txs: txs, RevertingTxHashes: []common.Hash{{1}}})
+	require.ErrorContains(err, "is not in the bundle")
+	genesis := hexutil.Uint64(0)
+	_, err = api.SendBundle(ctx, jsonrpc.SendBundleArgs{Txs: txs, BlockNumber: &genesis})
+	require.ErrorContains(err, "already mined")
+
+	// targets the next block by default
+	res, err := api.SendBundle(ctx, jsonrpc.SendBundleArgs{Txs: txs, RevertingTxHashes: []common.Hash{hashes[1]}})
+	require.NoError(err)
+	require.Equal(common.Hash(types2.BundleHash(hashes)), res.BundleHash)
+
+	bundles := mockSentry.TxPool.Bundles(2, 0)
+	require.Len(bundles, 1)
+	require.Equal(hashes, bundles[0].TxHashes)
+	require.Equal(mockSentry.Address, bundles[0].Senders.AddressAt(0))
+	require.Contains(bundles[0].RevertingTxs, hashes[1])
+	require.Empty(mockSentry.TxPool.Bundles(3, 0))
+}
+
 func transaction(nonce uint64, gaslimit uint64, key *ecdsa.PrivateKey) types.Transaction {
 	return pricedTransaction(nonce, gaslimit, u256.Num1, key)
 }
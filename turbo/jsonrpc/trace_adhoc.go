@@ -770,6 +770,9 @@ func (api *TraceAPIImpl) ReplayTransaction(ctx context.Context, txHash libcommon
 
 		isBorStateSyncTxn = true
 	}
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return nil, err
+	}
 
 	block, err := api.blockByNumberWithSenders(ctx, tx, blockNum)
 	if err != nil {
@@ -853,6 +856,9 @@ func (api *TraceAPIImpl) ReplayBlockTransactions(ctx context.Context, blockNrOrH
 	if err != nil {
 		return nil, err
 	}
+	if err := api.checkPruneHistory(tx, blockNumber); err != nil {
+		return nil, err
+	}
 
 	// Extract transactions from block
 	block, bErr := api.blockWithSenders(ctx, tx, blockHash, blockNumber)
@@ -71,6 +71,9 @@ func (api *TraceAPIImpl) Transaction(ctx context.Context, txHash common.Hash, ga
 
 		isBorStateSyncTxn = true
 	}
+	if err := api.checkPruneHistory(tx, blockNumber); err != nil {
+		return nil, err
+	}
 
 	block, err := api.blockByNumberWithSenders(ctx, tx, blockNumber)
 	if err != nil {
@@ -179,6 +182,9 @@ func (api *TraceAPIImpl) Block(ctx context.Context, blockNr rpc.BlockNumber, gas
 	if blockNum == 0 {
 		return []ParityTrace{}, nil
 	}
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return nil, err
+	}
 	bn := hexutil.Uint64(blockNum)
 
 	// Extract transactions from block
@@ -349,12 +355,19 @@ func (api *TraceAPIImpl) Filter(ctx context.Context, req TraceFilterRequest, gas
 	}
 	defer dbtx.Rollback()
 
+	available, _, err := api.pruneAvailability(dbtx)
+	if err != nil {
+		return err
+	}
 	var fromBlock uint64
 	var toBlock uint64
 	if req.FromBlock == nil {
-		fromBlock = 0
+		fromBlock = available.Traces
 	} else {
 		fromBlock = uint64(*req.FromBlock)
+		if fromBlock < available.Traces {
+			return rpc.PrunedError{Data: prunedTraces, Block: fromBlock, Earliest: available.Traces}
+		}
 	}
 
 	if req.ToBlock == nil {
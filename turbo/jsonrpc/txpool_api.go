@@ -19,6 +19,8 @@ import (
 type TxPoolAPI interface {
 	Content(ctx context.Context) (map[string]map[string]map[string]*RPCTransaction, error)
 	ContentFrom(ctx context.Context, addr libcommon.Address) (map[string]map[string]*RPCTransaction, error)
+	Inspect(ctx context.Context) (map[string]map[string]map[string]string, error)
+	TxStatus(ctx context.Context, hash libcommon.Hash) (*TxStatus, error)
 }
 
 // TxPoolAPIImpl data structure to store things needed for net_ commands
@@ -201,40 +203,94 @@ func (api *TxPoolAPIImpl) Status(ctx context.Context) (map[string]hexutil.Uint,
 	}, nil
 }
 
-/*
-
 // Inspect retrieves the content of the transaction pool and flattens it into an
 // easily inspectable list.
-func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
+func (api *TxPoolAPIImpl) Inspect(ctx context.Context) (map[string]map[string]map[string]string, error) {
+	reply, err := api.pool.All(ctx, &proto_txpool.AllRequest{})
+	if err != nil {
+		return nil, err
+	}
+
 	content := map[string]map[string]map[string]string{
 		"pending": make(map[string]map[string]string),
 		"queued":  make(map[string]map[string]string),
 	}
-	pending, queue := s.b.TxPoolContent()
-
 	// Define a formatter to flatten a transaction into a string
-	var format = func(tx *types.Transaction) string {
-		if to := tx.To(); to != nil {
-			return fmt.Sprintf("%s: %v wei + %v gas × %v wei", tx.To().Hex(), tx.Value(), tx.Gas(), tx.GasPrice())
+	format := func(txn types.Transaction) string {
+		if to := txn.GetTo(); to != nil {
+			return fmt.Sprintf("%s: %v wei + %v gas × %v wei", to.Hex(), txn.GetValue(), txn.GetGas(), txn.GetFeeCap())
 		}
-		return fmt.Sprintf("contract creation: %v wei + %v gas × %v wei", tx.Value(), tx.Gas(), tx.GasPrice())
+		return fmt.Sprintf("contract creation: %v wei + %v gas × %v wei", txn.GetValue(), txn.GetGas(), txn.GetFeeCap())
 	}
-	// Flatten the pending transactions
-	for account, txs := range pending {
-		dump := make(map[string]string)
-		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
+	for i := range reply.Txs {
+		txn, err := types.DecodeWrappedTransaction(reply.Txs[i].RlpTx)
+		if err != nil {
+			return nil, fmt.Errorf("decoding transaction from: %x: %w", reply.Txs[i].RlpTx, err)
 		}
-		content["pending"][account.Hex()] = dump
-	}
-	// Flatten the queued transactions
-	for account, txs := range queue {
-		dump := make(map[string]string)
-		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
+		var subPool map[string]map[string]string
+		switch reply.Txs[i].TxnType {
+		case proto_txpool.AllReply_PENDING, proto_txpool.AllReply_BASE_FEE:
+			// same shape as geth: transactions only waiting for the base fee to drop are pending
+			subPool = content["pending"]
+		case proto_txpool.AllReply_QUEUED:
+			subPool = content["queued"]
+		default:
+			continue
 		}
-		content["queued"][account.Hex()] = dump
+		addr := gointerfaces.ConvertH160toAddress(reply.Txs[i].Sender)
+		account := libcommon.Address(addr).Hex()
+		if _, ok := subPool[account]; !ok {
+			subPool[account] = make(map[string]string)
+		}
+		subPool[account][fmt.Sprintf("%d", txn.GetNonce())] = format(txn)
+	}
+	return content, nil
+}
+
+// TxStatus is where the transaction is in the pool: the sub-pool with the reasons it can't be included into the
+// pending block, or the reason it was discarded
+type TxStatus struct {
+	Status        string             `json:"status"` // pending, baseFee, queued, discarded or unknown
+	Reasons       []string           `json:"reasons,omitempty"`
+	DiscardReason string             `json:"discardReason,omitempty"`
+	Sender        *libcommon.Address `json:"sender,omitempty"`
+	Nonce         *hexutil.Uint64    `json:"nonce,omitempty"`
+}
+
+var notPendingReasons = map[proto_txpool.TxStatusReply_NotPendingReason]string{
+	proto_txpool.TxStatusReply_UNKNOWN_REASON:                   "unknown",
+	proto_txpool.TxStatusReply_NONCE_GAP:                        "nonceGap",
+	proto_txpool.TxStatusReply_INSUFFICIENT_BALANCE:             "insufficientBalance",
+	proto_txpool.TxStatusReply_GAS_LIMIT_ABOVE_BLOCK_GAS_LIMIT:  "gasLimitAboveBlockGasLimit",
+	proto_txpool.TxStatusReply_FEE_CAP_BELOW_BASE_FEE:           "feeCapBelowBaseFee",
+	proto_txpool.TxStatusReply_BLOB_FEE_CAP_BELOW_BLOB_BASE_FEE: "blobFeeCapBelowBlobBaseFee",
+}
+
+// TxStatus implements txpool_txStatus. Returns the sub-pool of the transaction and why it isn't pending, or why it
+// was discarded if the pool still remembers it.
+func (api *TxPoolAPIImpl) TxStatus(ctx context.Context, hash libcommon.Hash) (*TxStatus, error) {
+	reply, err := api.pool.TxStatus(ctx, &proto_txpool.TxStatusRequest{Hash: gointerfaces.ConvertHashToH256(hash)})
+	if err != nil {
+		return nil, err
+	}
+	switch reply.Status {
+	case proto_txpool.TxStatusReply_UNKNOWN:
+		return &TxStatus{Status: "unknown"}, nil
+	case proto_txpool.TxStatusReply_DISCARDED:
+		return &TxStatus{Status: "discarded", DiscardReason: reply.DiscardReason}, nil
+	}
+	sender, nonce := libcommon.Address(gointerfaces.ConvertH160toAddress(reply.Sender)), hexutil.Uint64(reply.Nonce)
+	status := &TxStatus{Sender: &sender, Nonce: &nonce}
+	switch reply.Status {
+	case proto_txpool.TxStatusReply_PENDING:
+		status.Status = "pending"
+	case proto_txpool.TxStatusReply_BASE_FEE:
+		status.Status = "baseFee"
+	case proto_txpool.TxStatusReply_QUEUED:
+		status.Status = "queued"
+	}
+	for _, reason := range reply.Reasons {
+		status.Reasons = append(status.Reasons, notPendingReasons[reason])
 	}
-	return content
+	return status, nil
 }
-*/
@@ -64,3 +64,61 @@ func TestTxPoolContent(t *testing.T) {
 	require.Equal(status["pending"], hexutil.Uint(1))
 	require.Equal(status["queued"], hexutil.Uint(0))
 }
+
+func TestTxPoolInspectAndTxStatus(t *testing.T) {
+	m, require := mock.MockWithTxPool(t), require.New(t)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(libcommon.Address{1})
+	})
+	require.NoError(err)
+	err = m.InsertChain(chain)
+	require.NoError(err)
+
+	ctx, conn := rpcdaemontest.CreateTestGrpcConn(t, m)
+	txPool := txpool.NewTxpoolClient(conn)
+	ff := rpchelper.New(ctx, rpchelper.DefaultFiltersConfig, nil, txPool, txpool.NewMiningClient(conn), func() {}, m.Log)
+	agg := m.HistoryV3Components()
+	api := NewTxPoolAPI(NewBaseApi(ff, kvcache.New(kvcache.DefaultCoherentConfig), m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs, nil, nil), m.DB, txPool)
+
+	signer := types.LatestSignerForChainID(m.ChainConfig.ChainID)
+	add := func(nonce uint64, gasPrice uint64) types.Transaction {
+		txn, err := types.SignTx(types.NewTransaction(nonce, libcommon.Address{2}, uint256.NewInt(1234), params.TxGas, uint256.NewInt(gasPrice), nil), *signer, m.Key)
+		require.NoError(err)
+		buf := bytes.NewBuffer(nil)
+		require.NoError(txn.MarshalBinary(buf))
+		reply, err := txPool.Add(ctx, &txpool.AddRequest{RlpTxs: [][]byte{buf.Bytes()}})
+		require.NoError(err)
+		require.Equal(txPoolProto.ImportResult_SUCCESS, reply.Imported[0], fmt.Sprintf("%s", reply.Errors))
+		return txn
+	}
+	replaced := add(0, 10*params.GWei)
+	pending := add(0, 20*params.GWei)
+	gapped := add(2, 10*params.GWei)
+
+	content, err := api.Inspect(ctx)
+	require.NoError(err)
+	sender := m.Address.String()
+	require.Equal(map[string]string{"0": "0x0200000000000000000000000000000000000000: 1234 wei + 21000 gas × 20000000000 wei"}, content["pending"][sender])
+	require.Equal(map[string]string{"2": "0x0200000000000000000000000000000000000000: 1234 wei + 21000 gas × 10000000000 wei"}, content["queued"][sender])
+	require.NotContains(content, "baseFee")
+
+	status, err := api.TxStatus(ctx, pending.Hash())
+	require.NoError(err)
+	require.Equal("pending", status.Status)
+	require.Equal(m.Address, *status.Sender)
+	require.Empty(status.Reasons)
+
+	status, err = api.TxStatus(ctx, gapped.Hash())
+	require.NoError(err)
+	require.Equal("queued", status.Status)
+	require.Equal(hexutil.Uint64(2), *status.Nonce)
+	require.Equal([]string{"nonceGap"}, status.Reasons)
+
+	status, err = api.TxStatus(ctx, replaced.Hash())
+	require.NoError(err)
+	require.Equal(&TxStatus{Status: "discarded", DiscardReason: "replaced by transaction with higher tip"}, status)
+
+	status, err = api.TxStatus(ctx, libcommon.Hash{1})
+	require.NoError(err)
+	require.Equal(&TxStatus{Status: "unknown"}, status)
+}
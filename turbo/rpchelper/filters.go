@@ -37,6 +37,9 @@ type Filters struct {
 	pendingBlock *types.Block
 
 	headsSubs        *concurrent.SyncMap[HeadsSubID, Sub[*types.Header]]
+	safeHeadsSubs    *concurrent.SyncMap[HeadsSubID, Sub[*types.Header]]
+	finalizedSubs    *concurrent.SyncMap[HeadsSubID, Sub[*types.Header]]
+	reorgsSubs       *concurrent.SyncMap[ReorgsSubID, Sub[*types.ReorgEvent]]
 	pendingLogsSubs  *concurrent.SyncMap[PendingLogsSubID, Sub[types.Logs]]
 	pendingBlockSubs *concurrent.SyncMap[PendingBlockSubID, Sub[*types.Block]]
 	pendingTxsSubs   *concurrent.SyncMap[PendingTxsSubID, Sub[[]types.Transaction]]
@@ -60,6 +63,9 @@ func New(ctx context.Context, config FiltersConfig, ethBackend ApiBackend, txPoo
 
 	ff := &Filters{
 		headsSubs:          concurrent.NewSyncMap[HeadsSubID, Sub[*types.Header]](),
+		safeHeadsSubs:      concurrent.NewSyncMap[HeadsSubID, Sub[*types.Header]](),
+		finalizedSubs:      concurrent.NewSyncMap[HeadsSubID, Sub[*types.Header]](),
+		reorgsSubs:         concurrent.NewSyncMap[ReorgsSubID, Sub[*types.ReorgEvent]](),
 		pendingTxsSubs:     concurrent.NewSyncMap[PendingTxsSubID, Sub[[]types.Transaction]](),
 		pendingLogsSubs:    concurrent.NewSyncMap[PendingLogsSubID, Sub[types.Logs]](),
 		pendingBlockSubs:   concurrent.NewSyncMap[PendingBlockSubID, Sub[*types.Block]](),
@@ -355,6 +361,63 @@ func (ff *Filters) UnsubscribeHeads(id HeadsSubID) bool {
 	return true
 }
 
+// SubscribeSafeHeads subscribes to the safe head changes of the forkchoice and returns a channel to receive
+// the headers and a subscription ID to manage the subscription.
+func (ff *Filters) SubscribeSafeHeads(size int) (<-chan *types.Header, HeadsSubID) {
+	id := HeadsSubID(generateSubscriptionID())
+	sub := newChanSub[*types.Header](size)
+	ff.safeHeadsSubs.Put(id, sub)
+	return sub.ch, id
+}
+
+// UnsubscribeSafeHeads unsubscribes from the safe head changes using the given subscription ID.
+func (ff *Filters) UnsubscribeSafeHeads(id HeadsSubID) bool {
+	ch, ok := ff.safeHeadsSubs.Delete(id)
+	if !ok {
+		return false
+	}
+	ch.Close()
+	return true
+}
+
+// SubscribeFinalizedHeads subscribes to the finalized head changes of the forkchoice and returns a channel to
+// receive the headers and a subscription ID to manage the subscription.
+func (ff *Filters) SubscribeFinalizedHeads(size int) (<-chan *types.Header, HeadsSubID) {
+	id := HeadsSubID(generateSubscriptionID())
+	sub := newChanSub[*types.Header](size)
+	ff.finalizedSubs.Put(id, sub)
+	return sub.ch, id
+}
+
+// UnsubscribeFinalizedHeads unsubscribes from the finalized head changes using the given subscription ID.
+func (ff *Filters) UnsubscribeFinalizedHeads(id HeadsSubID) bool {
+	ch, ok := ff.finalizedSubs.Delete(id)
+	if !ok {
+		return false
+	}
+	ch.Close()
+	return true
+}
+
+// SubscribeReorgs subscribes to the reorgs of the canonical chain and returns a channel to receive the reorg log
+// entries and a subscription ID to manage the subscription.
+func (ff *Filters) SubscribeReorgs(size int) (<-chan *types.ReorgEvent, ReorgsSubID) {
+	id := ReorgsSubID(generateSubscriptionID())
+	sub := newChanSub[*types.ReorgEvent](size)
+	ff.reorgsSubs.Put(id, sub)
+	return sub.ch, id
+}
+
+// UnsubscribeReorgs unsubscribes from the reorgs using the given subscription ID.
+func (ff *Filters) UnsubscribeReorgs(id ReorgsSubID) bool {
+	ch, ok := ff.reorgsSubs.Delete(id)
+	if !ok {
+		return false
+	}
+	ch.Close()
+	return true
+}
+
 // SubscribePendingLogs subscribes to pending logs and returns a channel to receive the logs
 // and a subscription ID to manage the subscription. It uses the specified filter criteria.
 func (ff *Filters) SubscribePendingLogs(size int) (<-chan types.Logs, PendingLogsSubID) {
@@ -551,6 +614,12 @@ func (ff *Filters) onNewEvent(event *remote.SubscribeReply) error {
 	case remote.Event_NEW_SNAPSHOT:
 		ff.onNewSnapshot()
 		return nil
+	case remote.Event_SAFE_HEADER:
+		return ff.onForkchoiceHeader(event, ff.safeHeadsSubs)
+	case remote.Event_FINALIZED_HEADER:
+		return ff.onForkchoiceHeader(event, ff.finalizedSubs)
+	case remote.Event_REORG:
+		return ff.onReorg(event)
 	case remote.Event_PENDING_LOGS:
 		return ff.onPendingLog(event)
 	case remote.Event_PENDING_BLOCK:
@@ -611,6 +680,33 @@ func (ff *Filters) onNewHeader(event *remote.SubscribeReply) error {
 	})
 }
 
+// onForkchoiceHeader handles a new safe or finalized header event from the remote.
+func (ff *Filters) onForkchoiceHeader(event *remote.SubscribeReply, subs *concurrent.SyncMap[HeadsSubID, Sub[*types.Header]]) error {
+	if len(event.Data) == 0 {
+		return nil
+	}
+	var header types.Header
+	if err := rlp.Decode(bytes.NewReader(event.Data), &header); err != nil {
+		return fmt.Errorf("unprocessable payload: %w", err)
+	}
+	return subs.Range(func(k HeadsSubID, v Sub[*types.Header]) error {
+		v.Send(&header)
+		return nil
+	})
+}
+
+// onReorg handles a new reorg event from the remote.
+func (ff *Filters) onReorg(event *remote.SubscribeReply) error {
+	var reorg types.ReorgEvent
+	if err := rlp.DecodeBytes(event.Data, &reorg); err != nil {
+		return fmt.Errorf("unprocessable payload: %w", err)
+	}
+	return ff.reorgsSubs.Range(func(k ReorgsSubID, v Sub[*types.ReorgEvent]) error {
+		v.Send(&reorg)
+		return nil
+	})
+}
+
 // OnNewTx handles a new transaction event from the transaction pool and processes it.
 func (ff *Filters) OnNewTx(reply *txpool.OnAddReply) {
 	txs := make([]types.Transaction, len(reply.RplTxs))
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon/core/types"
+	"math/big"
 	"testing"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -13,6 +14,7 @@ import (
 	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/types"
 
 	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/log/v3"
 )
 
@@ -489,3 +491,54 @@ func TestFilters_AddPendingTxs(t *testing.T) {
 		})
 	}
 }
+
+func TestFilters_ForkchoiceHeads(t *testing.T) {
+	f := New(context.TODO(), DefaultFiltersConfig, nil, nil, nil, func() {}, log.New())
+	safeCh, safeID := f.SubscribeSafeHeads(8)
+	finalizedCh, finalizedID := f.SubscribeFinalizedHeads(8)
+
+	safe := &types.Header{Number: big.NewInt(10)}
+	finalized := &types.Header{Number: big.NewInt(5)}
+	safeRlp, err := rlp.EncodeToBytes(safe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finalizedRlp, err := rlp.EncodeToBytes(finalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.OnNewEvent(&remote.SubscribeReply{Type: remote.Event_SAFE_HEADER, Data: safeRlp})
+	f.OnNewEvent(&remote.SubscribeReply{Type: remote.Event_FINALIZED_HEADER, Data: finalizedRlp})
+
+	if h := <-safeCh; h.Hash() != safe.Hash() {
+		t.Fatalf("unexpected safe header %x", h.Hash())
+	}
+	if h := <-finalizedCh; h.Hash() != finalized.Hash() {
+		t.Fatalf("unexpected finalized header %x", h.Hash())
+	}
+	if len(safeCh) != 0 || len(finalizedCh) != 0 {
+		t.Fatal("expected a single header per subscription")
+	}
+	if !f.UnsubscribeSafeHeads(safeID) || !f.UnsubscribeFinalizedHeads(finalizedID) {
+		t.Fatal("expected to unsubscribe")
+	}
+}
+
+func TestFilters_Reorgs(t *testing.T) {
+	f := New(context.TODO(), DefaultFiltersConfig, nil, nil, nil, func() {}, log.New())
+	ch, id := f.SubscribeReorgs(8)
+
+	reorg := &types.ReorgEvent{ID: 7, Reorg: &types.Reorg{OldNumber: 10, NewNumber: 9, AncestorNumber: 8, Dropped: []libcommon.Hash{{1}, {2}}, Trigger: types.ReorgTriggerEngineAPI}}
+	reorgRlp, err := rlp.EncodeToBytes(reorg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.OnNewEvent(&remote.SubscribeReply{Type: remote.Event_REORG, Data: reorgRlp})
+
+	if r := <-ch; r.ID != 7 || r.Reorg.Depth() != 2 || len(r.Reorg.Dropped) != 2 || r.Reorg.Trigger != types.ReorgTriggerEngineAPI {
+		t.Fatalf("unexpected reorg %+v", r.Reorg)
+	}
+	if !f.UnsubscribeReorgs(id) {
+		t.Fatal("expected to unsubscribe")
+	}
+}
@@ -29,4 +29,6 @@ type ApiBackend interface {
 	Peers(ctx context.Context) ([]*p2p.PeerInfo, error)
 	AddPeer(ctx context.Context, url *remote.AddPeerRequest) (*remote.AddPeerReply, error)
 	PendingBlock(ctx context.Context) (*types.Block, error)
+	ChangeFeedInfo(ctx context.Context, req *remote.ChangeFeedInfoRequest) (*remote.ChangeFeedInfoReply, error)
+	ChangeFeed(ctx context.Context, req *remote.ChangeFeedRequest, onDiff func(*remote.BlockDiff) error) error
 }
@@ -19,25 +19,31 @@ type LogsSubscription func([]*remote.SubscribeLogsReply) error
 
 // Events manages event subscriptions and dissimination. Thread-safe
 type Events struct {
-	id                        int
-	headerSubscriptions       map[int]chan [][]byte
-	newSnapshotSubscription   map[int]chan struct{}
-	pendingLogsSubscriptions  map[int]PendingLogsSubscription
-	pendingBlockSubscriptions map[int]PendingBlockSubscription
-	pendingTxsSubscriptions   map[int]PendingTxsSubscription
-	logsSubscriptions         map[int]chan []*remote.SubscribeLogsReply
-	hasLogSubscriptions       bool
-	lock                      sync.RWMutex
+	id                           int
+	headerSubscriptions          map[int]chan [][]byte
+	safeHeaderSubscriptions      map[int]chan []byte
+	finalizedHeaderSubscriptions map[int]chan []byte
+	reorgSubscriptions           map[int]chan []byte
+	newSnapshotSubscription      map[int]chan struct{}
+	pendingLogsSubscriptions     map[int]PendingLogsSubscription
+	pendingBlockSubscriptions    map[int]PendingBlockSubscription
+	pendingTxsSubscriptions      map[int]PendingTxsSubscription
+	logsSubscriptions            map[int]chan []*remote.SubscribeLogsReply
+	hasLogSubscriptions          bool
+	lock                         sync.RWMutex
 }
 
 func NewEvents() *Events {
 	return &Events{
-		headerSubscriptions:       map[int]chan [][]byte{},
-		pendingLogsSubscriptions:  map[int]PendingLogsSubscription{},
-		pendingBlockSubscriptions: map[int]PendingBlockSubscription{},
-		pendingTxsSubscriptions:   map[int]PendingTxsSubscription{},
-		logsSubscriptions:         map[int]chan []*remote.SubscribeLogsReply{},
-		newSnapshotSubscription:   map[int]chan struct{}{},
+		headerSubscriptions:          map[int]chan [][]byte{},
+		safeHeaderSubscriptions:      map[int]chan []byte{},
+		finalizedHeaderSubscriptions: map[int]chan []byte{},
+		reorgSubscriptions:           map[int]chan []byte{},
+		pendingLogsSubscriptions:     map[int]PendingLogsSubscription{},
+		pendingBlockSubscriptions:    map[int]PendingBlockSubscription{},
+		pendingTxsSubscriptions:      map[int]PendingTxsSubscription{},
+		logsSubscriptions:            map[int]chan []*remote.SubscribeLogsReply{},
+		newSnapshotSubscription:      map[int]chan struct{}{},
 	}
 }
 
@@ -54,6 +60,48 @@ func (e *Events) AddHeaderSubscription() (chan [][]byte, func()) {
 	}
 }
 
+// AddSafeHeaderSubscription - subscription to the safe head changes, the channel receives header RLP
+func (e *Events) AddSafeHeaderSubscription() (chan []byte, func()) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	ch := make(chan []byte, 8)
+	e.id++
+	id := e.id
+	e.safeHeaderSubscriptions[id] = ch
+	return ch, func() {
+		delete(e.safeHeaderSubscriptions, id)
+		close(ch)
+	}
+}
+
+// AddFinalizedHeaderSubscription - subscription to the finalized head changes, the channel receives header RLP
+func (e *Events) AddFinalizedHeaderSubscription() (chan []byte, func()) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	ch := make(chan []byte, 8)
+	e.id++
+	id := e.id
+	e.finalizedHeaderSubscriptions[id] = ch
+	return ch, func() {
+		delete(e.finalizedHeaderSubscriptions, id)
+		close(ch)
+	}
+}
+
+// AddReorgSubscription - subscription to the recorded reorgs, the channel receives the RLP of types.ReorgEvent
+func (e *Events) AddReorgSubscription() (chan []byte, func()) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	ch := make(chan []byte, 8)
+	e.id++
+	id := e.id
+	e.reorgSubscriptions[id] = ch
+	return ch, func() {
+		delete(e.reorgSubscriptions, id)
+		close(ch)
+	}
+}
+
 func (e *Events) AddNewSnapshotSubscription() (chan struct{}, func()) {
 	e.lock.Lock()
 	defer e.lock.Unlock()
@@ -120,6 +168,30 @@ func (e *Events) OnNewHeader(newHeadersRlp [][]byte) {
 	}
 }
 
+func (e *Events) OnNewSafeHeader(headerRlp []byte) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, ch := range e.safeHeaderSubscriptions {
+		common.PrioritizedSend(ch, headerRlp)
+	}
+}
+
+func (e *Events) OnNewFinalizedHeader(headerRlp []byte) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, ch := range e.finalizedHeaderSubscriptions {
+		common.PrioritizedSend(ch, headerRlp)
+	}
+}
+
+func (e *Events) OnNewReorg(reorgRlp []byte) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, ch := range e.reorgSubscriptions {
+		common.PrioritizedSend(ch, reorgRlp)
+	}
+}
+
 func (e *Events) OnNewPendingLogs(logs types.Logs) {
 	e.lock.Lock()
 	defer e.lock.Unlock()
@@ -138,8 +210,15 @@ func (e *Events) OnLogs(logs []*remote.SubscribeLogsReply) {
 	}
 }
 
+// ChangeFeed records the changes of the blocks committed by the staged sync. Notify must not block, the feed reads
+// the committed blocks on its own.
+type ChangeFeed interface {
+	Notify()
+}
+
 type Notifications struct {
 	Events               *Events
 	Accumulator          *Accumulator
 	StateChangesConsumer StateChangeConsumer
+	ChangeFeed           ChangeFeed
 }
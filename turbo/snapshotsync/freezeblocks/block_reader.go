@@ -12,6 +12,7 @@ import (
 
 	borsnaptype "github.com/ledgerwatch/erigon/polygon/bor/snaptype"
 	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon/polygon/bor"
@@ -24,6 +25,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/recsplit"
+	"github.com/ledgerwatch/erigon-lib/tracing"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	coresnaptype "github.com/ledgerwatch/erigon/core/snaptype"
 	"github.com/ledgerwatch/erigon/core/types"
@@ -385,6 +387,10 @@ func (r *BlockReader) HeaderByNumber(ctx context.Context, tx kv.Getter, blockHei
 		}
 	}
 
+	if tracing.Recording(ctx) {
+		_, span := tracing.StartSpan(ctx, "snapshots.HeaderByNumber", attribute.Int64("block.number", int64(blockHeight)))
+		defer func() { tracing.EndSpan(span, err) }()
+	}
 	seg, ok, release := r.sn.ViewSingleFile(coresnaptype.Headers, blockHeight)
 	if !ok {
 		if dbgLogs {
@@ -521,6 +527,10 @@ func (r *BlockReader) BodyWithTransactions(ctx context.Context, tx kv.Getter, ha
 		}
 	}
 
+	if tracing.Recording(ctx) {
+		_, span := tracing.StartSpan(ctx, "snapshots.BodyWithTransactions", attribute.Int64("block.number", int64(blockHeight)))
+		defer func() { tracing.EndSpan(span, err) }()
+	}
 	seg, ok, release := r.sn.ViewSingleFile(coresnaptype.Bodies, blockHeight)
 	if !ok {
 		if dbgLogs {
@@ -666,6 +676,10 @@ func (r *BlockReader) blockWithSenders(ctx context.Context, tx kv.Getter, hash c
 		return
 	}
 
+	if tracing.Recording(ctx) {
+		_, span := tracing.StartSpan(ctx, "snapshots.BlockWithSenders", attribute.Int64("block.number", int64(blockHeight)))
+		defer func() { tracing.EndSpan(span, err) }()
+	}
 	seg, ok, release := r.sn.ViewSingleFile(coresnaptype.Headers, blockHeight)
 	if !ok {
 		if dbgLogs {
@@ -35,6 +35,7 @@ import (
 	"github.com/ledgerwatch/erigon/p2p/sentry/sentry_multi_client"
 	"github.com/ledgerwatch/erigon/polygon/bor"
 	"github.com/ledgerwatch/erigon/polygon/bor/finality/flags"
+	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_helpers"
 	"github.com/ledgerwatch/erigon/turbo/services"
 	"github.com/ledgerwatch/erigon/turbo/shards"
@@ -233,12 +234,24 @@ type Hook struct {
 	blockReader   services.FullBlockReader
 	updateHead    func(ctx context.Context)
 	db            kv.RoDB
+
+	// safe and finalized heads which were sent to the subscribers last
+	lastSafe, lastFinalized libcommon.Hash
+	// id of the first reorg log entry which wasn't sent to the subscribers, read on the first run
+	nextReorgID *uint64
 }
 
 func NewHook(ctx context.Context, db kv.RoDB, notifications *shards.Notifications, sync *stagedsync.Sync, blockReader services.FullBlockReader, chainConfig *chain.Config, logger log.Logger, updateHead func(ctx context.Context)) *Hook {
 	return &Hook{ctx: ctx, db: db, notifications: notifications, sync: sync, blockReader: blockReader, chainConfig: chainConfig, logger: logger, updateHead: updateHead}
 }
 func (h *Hook) beforeRun(tx kv.Tx, inSync bool) error {
+	if h.nextReorgID == nil {
+		id, err := rawdb.NextReorgID(tx)
+		if err != nil {
+			return err
+		}
+		h.nextReorgID = &id
+	}
 	notifications := h.notifications
 	if notifications != nil && notifications.Accumulator != nil && inSync {
 		stateVersion, err := rawdb.GetStateVersion(tx)
@@ -267,10 +280,71 @@ func (h *Hook) afterRun(tx kv.Tx, finishProgressBefore uint64) error {
 		h.updateHead(h.ctx)
 	}
 	if h.notifications != nil {
+		if h.notifications.ChangeFeed != nil {
+			h.notifications.ChangeFeed.Notify()
+		}
+		if err := h.notifyReorgs(tx); err != nil {
+			return err
+		}
 		return h.sendNotifications(h.notifications, tx, finishProgressBefore)
 	}
 	return nil
 }
+
+// NotifyForkchoiceHeads sends the safe and finalized heads of the last forkchoice update to the
+// subscribers, if they changed since the previous notification
+func (h *Hook) NotifyForkchoiceHeads(tx kv.Tx) error {
+	if h.notifications == nil || h.notifications.Events == nil {
+		return nil
+	}
+	events := h.notifications.Events
+	if safe := rawdb.ReadForkchoiceSafe(tx); safe != h.lastSafe && safe != (libcommon.Hash{}) {
+		headerRlp, err := h.headerRLP(tx, safe)
+		if err != nil {
+			return err
+		}
+		if headerRlp != nil {
+			events.OnNewSafeHeader(headerRlp)
+		}
+		h.lastSafe = safe
+	}
+	if finalized := rawdb.ReadForkchoiceFinalized(tx); finalized != h.lastFinalized && finalized != (libcommon.Hash{}) {
+		headerRlp, err := h.headerRLP(tx, finalized)
+		if err != nil {
+			return err
+		}
+		if headerRlp != nil {
+			events.OnNewFinalizedHeader(headerRlp)
+		}
+		h.lastFinalized = finalized
+	}
+	return nil
+}
+
+// notifyReorgs sends the reorgs recorded since the previous notification to the subscribers
+func (h *Hook) notifyReorgs(tx kv.Tx) error {
+	if h.notifications.Events == nil || h.nextReorgID == nil {
+		return nil
+	}
+	return rawdb.ForEachReorg(tx, *h.nextReorgID, func(id uint64, reorg *types.Reorg) (bool, error) {
+		reorgRlp, err := rlp.EncodeToBytes(&types.ReorgEvent{ID: id, Reorg: reorg})
+		if err != nil {
+			return false, err
+		}
+		h.notifications.Events.OnNewReorg(reorgRlp)
+		*h.nextReorgID = id + 1
+		return true, nil
+	})
+}
+
+func (h *Hook) headerRLP(tx kv.Tx, hash libcommon.Hash) ([]byte, error) {
+	header, err := h.blockReader.HeaderByHash(h.ctx, tx, hash)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(header)
+}
+
 func (h *Hook) sendNotifications(notifications *shards.Notifications, tx kv.Tx, finishProgressBefore uint64) error {
 	// update the accumulator with a new plain state version so the cache can be notified that
 	// state has moved on
@@ -528,7 +602,7 @@ func NewDefaultStages(ctx context.Context,
 		stagedsync.StageHashStateCfg(db, dirs, cfg.HistoryV3),
 		stagedsync.StageTrieCfg(db, true, true, false, dirs.Tmp, blockReader, controlServer.Hd, cfg.HistoryV3, agg),
 		stagedsync.StageHistoryCfg(db, cfg.Prune, dirs.Tmp),
-		stagedsync.StageLogIndexCfg(db, cfg.Prune, dirs.Tmp, noPruneContracts),
+		stagedsync.StageLogIndexCfg(db, cfg.Prune, dirs.Tmp, noPruneContracts, agg),
 		stagedsync.StageCallTracesCfg(db, cfg.Prune, 0, dirs.Tmp),
 		stagedsync.StageTxLookupCfg(db, cfg.Prune, dirs.Tmp, controlServer.ChainConfig.Bor, blockReader),
 		stagedsync.StageFinishCfg(db, dirs.Tmp, forkValidator),
@@ -607,7 +681,7 @@ func NewPipelineStages(ctx context.Context,
 			stagedsync.StageHashStateCfg(db, dirs, cfg.HistoryV3),
 			stagedsync.StageTrieCfg(db, checkStateRoot, true, false, dirs.Tmp, blockReader, controlServer.Hd, cfg.HistoryV3, agg),
 			stagedsync.StageHistoryCfg(db, cfg.Prune, dirs.Tmp),
-			stagedsync.StageLogIndexCfg(db, cfg.Prune, dirs.Tmp, noPruneContracts),
+			stagedsync.StageLogIndexCfg(db, cfg.Prune, dirs.Tmp, noPruneContracts, agg),
 			stagedsync.StageCallTracesCfg(db, cfg.Prune, 0, dirs.Tmp),
 			stagedsync.StageTxLookupCfg(db, cfg.Prune, dirs.Tmp, controlServer.ChainConfig.Bor, blockReader),
 			stagedsync.StageFinishCfg(db, dirs.Tmp, forkValidator),
@@ -643,7 +717,7 @@ func NewPipelineStages(ctx context.Context,
 		stagedsync.StageHashStateCfg(db, dirs, cfg.HistoryV3),
 		stagedsync.StageTrieCfg(db, checkStateRoot, true, false, dirs.Tmp, blockReader, controlServer.Hd, cfg.HistoryV3, agg),
 		stagedsync.StageHistoryCfg(db, cfg.Prune, dirs.Tmp),
-		stagedsync.StageLogIndexCfg(db, cfg.Prune, dirs.Tmp, noPruneContracts),
+		stagedsync.StageLogIndexCfg(db, cfg.Prune, dirs.Tmp, noPruneContracts, agg),
 		stagedsync.StageCallTracesCfg(db, cfg.Prune, 0, dirs.Tmp),
 		stagedsync.StageTxLookupCfg(db, cfg.Prune, dirs.Tmp, controlServer.ChainConfig.Bor, blockReader),
 		stagedsync.StageFinishCfg(db, dirs.Tmp, forkValidator),
@@ -7,11 +7,13 @@ import (
 
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/opstack"
+	"github.com/ledgerwatch/erigon-lib/tracing"
 
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/core"
@@ -37,7 +39,7 @@ func DoCall(
 	stateReader state.StateReader,
 	headerReader services.HeaderReader,
 	callTimeout time.Duration,
-) (*core.ExecutionResult, error) {
+) (_ *core.ExecutionResult, err error) {
 	// todo: Pending state is only known by the miner
 	/*
 		if blockNrOrHash.BlockNumber != nil && *blockNrOrHash.BlockNumber == rpc.PendingBlockNumber {
@@ -46,6 +48,13 @@ func DoCall(
 		}
 	*/
 
+	var blockNum int64
+	if header != nil {
+		blockNum = header.Number.Int64()
+	}
+	ctx, span := tracing.StartSpan(ctx, "transactions.DoCall", attribute.Int64("block.number", blockNum))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	state := state.New(stateReader)
 
 	// Override the fields of specified contracts before execution.
@@ -99,6 +108,7 @@ func DoCall(
 	if err != nil {
 		return nil, err
 	}
+	span.SetAttributes(attribute.Int64("evm.gas_used", int64(result.UsedGas)), attribute.Bool("evm.failed", result.Failed()))
 
 	// If the timer caused an abort, return an appropriate error message
 	if evm.Cancelled() {